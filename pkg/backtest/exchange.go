@@ -266,6 +266,21 @@ func (e *Exchange) QueryKLines(
 	return nil, errors.New("endTime or startTime can not be nil")
 }
 
+// SupportedInterval returns the intervals of the source exchange, since only the klines of them are synced,
+// the other intervals are synthesized by the session.
+func (e *Exchange) SupportedInterval() map[types.Interval]int {
+	if provider, ok := e.publicExchange.(types.CustomIntervalProvider); ok {
+		return provider.SupportedInterval()
+	}
+
+	return types.SupportedIntervals
+}
+
+func (e *Exchange) IsSupportedInterval(interval types.Interval) bool {
+	_, ok := e.SupportedInterval()[interval]
+	return ok
+}
+
 func (e *Exchange) QueryTrades(
 	ctx context.Context, symbol string, options *types.TradeQueryOptions,
 ) ([]types.Trade, error) {
@@ -23,6 +23,11 @@ func CollectSubscriptionIntervals(environ *bbgo.Environment) (allKLineIntervals
 	for _, session := range environ.Sessions() {
 		for _, sub := range session.Subscriptions {
 			if sub.Channel == types.KLineChannel {
+				// the bars are built from the 1m klines
+				if sub.Options.Interval.IsBar() {
+					continue
+				}
+
 				if sub.Options.Interval.Seconds()%60 > 0 {
 					// if any subscription interval is less than 60s, then we will use 1s for back-testing
 					requiredInterval = types.Interval1s
@@ -0,0 +1,365 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultBookCheckInterval  = 5 * time.Second
+	defaultBookMaxStaleness   = time.Minute
+	defaultBookResyncCooldown = 30 * time.Second
+)
+
+// BookUnhealthyReason is the reason code of an unhealthy book, it's also used as the metrics label
+type BookUnhealthyReason string
+
+const (
+	BookUnhealthyCrossed          BookUnhealthyReason = "crossed"
+	BookUnhealthyStale            BookUnhealthyReason = "stale"
+	BookUnhealthyChecksumMismatch BookUnhealthyReason = "checksum"
+)
+
+type BookMonitorConfig struct {
+	// CheckInterval is the interval of the periodic staleness and crossed book checks
+	CheckInterval types.Duration `json:"checkInterval,omitempty"`
+
+	// MaxStaleness is the max duration since the last book update
+	MaxStaleness types.Duration `json:"maxStaleness,omitempty"`
+
+	// ResyncCooldown is the min duration between two resyncs of the same market data stream
+	ResyncCooldown types.Duration `json:"resyncCooldown,omitempty"`
+
+	// DisableResync disables the automatic resync, the unhealthy books are only reported
+	DisableResync bool `json:"disableResync,omitempty"`
+}
+
+func (c *BookMonitorConfig) setDefaults() {
+	if c.CheckInterval == 0 {
+		c.CheckInterval = types.Duration(defaultBookCheckInterval)
+	}
+
+	if c.MaxStaleness == 0 {
+		c.MaxStaleness = types.Duration(defaultBookMaxStaleness)
+	}
+
+	if c.ResyncCooldown == 0 {
+		c.ResyncCooldown = types.Duration(defaultBookResyncCooldown)
+	}
+}
+
+type BookHealthStatus struct {
+	Session string
+	Symbol  string
+	Healthy bool
+	Reason  BookUnhealthyReason
+	Message string
+
+	// Since is the time of the last health status change
+	Since time.Time
+}
+
+type monitoredBook struct {
+	session  *ExchangeSession
+	symbol   string
+	book     *types.StreamOrderBook
+	checksum types.BookChecksumCalculator
+
+	// checksumMismatch is kept until the next snapshot or the next matching checksum
+	checksumMismatch bool
+	checksumMessage  string
+
+	healthy bool
+	reason  BookUnhealthyReason
+	message string
+	since   time.Time
+}
+
+func (b *monitoredBook) status() BookHealthStatus {
+	return BookHealthStatus{
+		Session: b.session.Name,
+		Symbol:  b.symbol,
+		Healthy: b.healthy,
+		Reason:  b.reason,
+		Message: b.message,
+		Since:   b.since,
+	}
+}
+
+// BookMonitor supervises the stream order books of the sessions. A book is marked as unhealthy when it's crossed,
+// stale or mismatching the checksum sent by the exchange. The unhealthy books are resynced by reconnecting
+// the market data stream, which makes the exchange stream send a new snapshot.
+//
+//go:generate callbackgen -type BookMonitor
+type BookMonitor struct {
+	config BookMonitorConfig
+
+	mu         sync.Mutex
+	books      map[string]*monitoredBook
+	lastResync map[*ExchangeSession]time.Time
+
+	now func() time.Time
+
+	unhealthyCallbacks []func(status BookHealthStatus)
+	healthyCallbacks   []func(status BookHealthStatus)
+}
+
+func NewBookMonitor(config BookMonitorConfig) *BookMonitor {
+	config.setDefaults()
+	return &BookMonitor{
+		config:     config,
+		books:      make(map[string]*monitoredBook),
+		lastResync: make(map[*ExchangeSession]time.Time),
+		now:        time.Now,
+	}
+}
+
+func bookMonitorKey(sessionName, symbol string) string {
+	return sessionName + ":" + symbol
+}
+
+// AddSession monitors all the stream order books of the session
+func (m *BookMonitor) AddSession(session *ExchangeSession) {
+	var symbols []string
+	for symbol := range session.orderBooks {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		m.Add(session, symbol, session.orderBooks[symbol])
+	}
+}
+
+// Add monitors the stream order book of the session symbol
+func (m *BookMonitor) Add(session *ExchangeSession, symbol string, book *types.StreamOrderBook) {
+	key := bookMonitorKey(session.Name, symbol)
+
+	m.mu.Lock()
+	if _, ok := m.books[key]; ok {
+		m.mu.Unlock()
+		return
+	}
+
+	b := &monitoredBook{
+		session: session,
+		symbol:  symbol,
+		book:    book,
+		healthy: true,
+		since:   m.now(),
+	}
+
+	if calculator, ok := session.MarketDataStream.(types.BookChecksumCalculator); ok {
+		b.checksum = calculator
+	}
+
+	m.books[key] = b
+	m.mu.Unlock()
+
+	metricsBookHealthy.With(bookMetricsLabels(b)).Set(1)
+
+	book.OnSnapshot(func(_ types.SliceOrderBook) {
+		m.mu.Lock()
+		b.checksumMismatch = false
+		m.mu.Unlock()
+		m.check(b, false)
+	})
+
+	book.OnUpdate(func(update types.SliceOrderBook) {
+		if update.Checksum != 0 && b.checksum != nil {
+			m.verifyChecksum(b, update.Checksum)
+		}
+		m.check(b, false)
+	})
+}
+
+// IsHealthy returns false if the book of the session symbol is unhealthy, the strategies can pause quoting with it.
+// The books not monitored are considered healthy, and it's safe to call it on a nil monitor.
+func (m *BookMonitor) IsHealthy(sessionName, symbol string) bool {
+	if m == nil {
+		return true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.books[bookMonitorKey(sessionName, symbol)]
+	return !ok || b.healthy
+}
+
+// Statuses returns the health status of all the monitored books
+func (m *BookMonitor) Statuses() []BookHealthStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var statuses []BookHealthStatus
+	for _, b := range m.books {
+		statuses = append(statuses, b.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return bookMonitorKey(statuses[i].Session, statuses[i].Symbol) < bookMonitorKey(statuses[j].Session, statuses[j].Symbol)
+	})
+	return statuses
+}
+
+// Run checks the books periodically until the context is canceled
+func (m *BookMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.CheckInterval.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			m.CheckAll()
+		}
+	}
+}
+
+// CheckAll checks the staleness and the crossed prices of all the books
+func (m *BookMonitor) CheckAll() {
+	m.mu.Lock()
+	var books []*monitoredBook
+	for _, b := range m.books {
+		books = append(books, b)
+	}
+	m.mu.Unlock()
+
+	for _, b := range books {
+		m.check(b, true)
+	}
+}
+
+func (m *BookMonitor) verifyChecksum(b *monitoredBook, expected int64) {
+	depthBook := b.book.CopyDepth(0)
+	actual := b.checksum.CalculateBookChecksum(b.symbol, depthBook.SideBook(types.SideTypeBuy), depthBook.SideBook(types.SideTypeSell))
+
+	m.mu.Lock()
+	b.checksumMismatch = actual != expected
+	if b.checksumMismatch {
+		b.checksumMessage = fmt.Sprintf("checksum %d != expected %d", actual, expected)
+	}
+	m.mu.Unlock()
+}
+
+// check diagnoses the book and updates its health status, the staleness is only checked by the periodic checks
+func (m *BookMonitor) check(b *monitoredBook, checkStaleness bool) {
+	now := m.now()
+	lastUpdateTime := b.book.LastUpdateTime()
+
+	// the book is not initialized yet
+	if lastUpdateTime.IsZero() {
+		return
+	}
+
+	var reason BookUnhealthyReason
+	var message string
+
+	m.mu.Lock()
+	checksumMismatch, checksumMessage := b.checksumMismatch, b.checksumMessage
+	m.mu.Unlock()
+
+	if bid, ask, ok := b.book.BestBidAndAsk(); ok && bid.Price.Compare(ask.Price) >= 0 {
+		reason = BookUnhealthyCrossed
+		message = fmt.Sprintf("bid price %s >= ask price %s", bid.Price.String(), ask.Price.String())
+	} else if checksumMismatch {
+		reason = BookUnhealthyChecksumMismatch
+		message = checksumMessage
+	} else if staleness := now.Sub(lastUpdateTime); checkStaleness && staleness > m.config.MaxStaleness.Duration() {
+		reason = BookUnhealthyStale
+		message = fmt.Sprintf("no update for %s", staleness)
+	}
+
+	if reason == "" {
+		m.setHealthy(b, now)
+		return
+	}
+
+	m.setUnhealthy(b, now, reason, message)
+}
+
+func (m *BookMonitor) setHealthy(b *monitoredBook, now time.Time) {
+	m.mu.Lock()
+	if b.healthy {
+		m.mu.Unlock()
+		return
+	}
+
+	b.healthy = true
+	b.reason = ""
+	b.message = ""
+	b.since = now
+	status := b.status()
+	m.mu.Unlock()
+
+	log.Infof("[BookMonitor] %s %s order book is recovered", b.session.Name, b.symbol)
+
+	metricsBookHealthy.With(bookMetricsLabels(b)).Set(1)
+	b.book.SendSignal(&types.BookSignal{Type: types.BookSignalHealthy, Time: now})
+	m.EmitHealthy(status)
+}
+
+func (m *BookMonitor) setUnhealthy(b *monitoredBook, now time.Time, reason BookUnhealthyReason, message string) {
+	m.mu.Lock()
+	changed := b.healthy || b.reason != reason
+	b.healthy = false
+	b.reason = reason
+	b.message = message
+	if changed {
+		b.since = now
+	}
+	status := b.status()
+	m.mu.Unlock()
+
+	if changed {
+		log.Warnf("[BookMonitor] %s %s order book is unhealthy: %s, %s", b.session.Name, b.symbol, reason, message)
+
+		labels := bookMetricsLabels(b)
+		metricsBookHealthy.With(labels).Set(0)
+		labels["reason"] = string(reason)
+		metricsBookUnhealthyTotal.With(labels).Inc()
+
+		b.book.SendSignal(&types.BookSignal{Type: types.BookSignalUnhealthy, Time: now, Reason: string(reason)})
+		m.EmitUnhealthy(status)
+	}
+
+	m.resync(b, now)
+}
+
+// resync reconnects the market data stream of the session, the cooldown is applied per session
+// since the books of the session share the same market data stream.
+func (m *BookMonitor) resync(b *monitoredBook, now time.Time) {
+	if m.config.DisableResync || b.session.MarketDataStream == nil {
+		return
+	}
+
+	m.mu.Lock()
+	if last, ok := m.lastResync[b.session]; ok && now.Sub(last) < m.config.ResyncCooldown.Duration() {
+		m.mu.Unlock()
+		return
+	}
+	m.lastResync[b.session] = now
+	m.mu.Unlock()
+
+	log.Warnf("[BookMonitor] resyncing %s %s order book by reconnecting the market data stream", b.session.Name, b.symbol)
+	metricsBookResyncTotal.With(bookMetricsLabels(b)).Inc()
+	b.session.MarketDataStream.Reconnect()
+}
+
+func bookMetricsLabels(b *monitoredBook) map[string]string {
+	return map[string]string{
+		"exchange": b.session.ExchangeName.String(),
+		"session":  b.session.Name,
+		"symbol":   b.symbol,
+	}
+}
@@ -0,0 +1,183 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testBookChecksumCalculator struct {
+	checksum int64
+}
+
+func (c *testBookChecksumCalculator) CalculateBookChecksum(_ string, _, _ types.PriceVolumeSlice) int64 {
+	return c.checksum
+}
+
+func newTestBookMonitorSession() (*ExchangeSession, *types.StandardStream) {
+	stream := types.NewStandardStream()
+	session := &ExchangeSession{
+		Name:             "binance",
+		ExchangeName:     types.ExchangeBinance,
+		MarketDataStream: &stream,
+	}
+	return session, &stream
+}
+
+func TestBookMonitor_Crossed(t *testing.T) {
+	now := time.Now()
+	session, stream := newTestBookMonitorSession()
+
+	book := types.NewStreamBook("BTCUSDT")
+	book.BindStream(stream)
+
+	monitor := NewBookMonitor(BookMonitorConfig{})
+	monitor.now = func() time.Time { return now }
+	monitor.Add(session, "BTCUSDT", book)
+
+	var unhealthy, healthy []BookHealthStatus
+	monitor.OnUnhealthy(func(status BookHealthStatus) { unhealthy = append(unhealthy, status) })
+	monitor.OnHealthy(func(status BookHealthStatus) { healthy = append(healthy, status) })
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Bids:   priceVolumesFromText("30000,1"),
+		Asks:   priceVolumesFromText("30010,1"),
+	})
+	assert.True(t, monitor.IsHealthy("binance", "BTCUSDT"))
+	<-book.C
+
+	stream.EmitBookUpdate(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Bids:   priceVolumesFromText("30020,1"),
+	})
+	assert.False(t, monitor.IsHealthy("binance", "BTCUSDT"))
+	require.Len(t, unhealthy, 1)
+	assert.Equal(t, BookUnhealthyCrossed, unhealthy[0].Reason)
+
+	// the unhealthy signal is sent before the update signal
+	signal := <-book.C
+	assert.Equal(t, types.BookSignalUnhealthy, signal.Type)
+	assert.Equal(t, string(BookUnhealthyCrossed), signal.Reason)
+
+	// the market data stream is reconnected for resyncing the book
+	select {
+	case <-stream.ReconnectC:
+	default:
+		t.Fatal("market data stream is not reconnected")
+	}
+
+	// a new snapshot recovers the book
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Bids:   priceVolumesFromText("30000,1"),
+		Asks:   priceVolumesFromText("30010,1"),
+	})
+	assert.True(t, monitor.IsHealthy("binance", "BTCUSDT"))
+	require.Len(t, healthy, 1)
+}
+
+func TestBookMonitor_Stale(t *testing.T) {
+	now := time.Now()
+	session, stream := newTestBookMonitorSession()
+
+	book := types.NewStreamBook("BTCUSDT")
+	book.BindStream(stream)
+
+	monitor := NewBookMonitor(BookMonitorConfig{
+		MaxStaleness:   types.Duration(time.Minute),
+		ResyncCooldown: types.Duration(time.Hour),
+	})
+	monitor.now = func() time.Time { return now }
+	monitor.Add(session, "BTCUSDT", book)
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Bids:   priceVolumesFromText("30000,1"),
+		Asks:   priceVolumesFromText("30010,1"),
+	})
+
+	monitor.CheckAll()
+	assert.True(t, monitor.IsHealthy("binance", "BTCUSDT"))
+
+	monitor.now = func() time.Time { return now.Add(2 * time.Minute) }
+	monitor.CheckAll()
+	assert.False(t, monitor.IsHealthy("binance", "BTCUSDT"))
+
+	statuses := monitor.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, BookUnhealthyStale, statuses[0].Reason)
+
+	<-stream.ReconnectC
+
+	// the resync is throttled by the cooldown
+	monitor.CheckAll()
+	select {
+	case <-stream.ReconnectC:
+		t.Fatal("market data stream is reconnected during the cooldown")
+	default:
+	}
+
+	stream.EmitBookUpdate(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now.Add(2 * time.Minute),
+		Bids:   priceVolumesFromText("30001,1"),
+	})
+	assert.True(t, monitor.IsHealthy("binance", "BTCUSDT"))
+}
+
+func TestBookMonitor_Checksum(t *testing.T) {
+	now := time.Now()
+	session, stream := newTestBookMonitorSession()
+
+	book := types.NewStreamBook("BTCUSDT")
+	book.BindStream(stream)
+
+	monitor := NewBookMonitor(BookMonitorConfig{DisableResync: true})
+	monitor.now = func() time.Time { return now }
+	monitor.Add(session, "BTCUSDT", book)
+
+	calculator := &testBookChecksumCalculator{checksum: 100}
+	monitor.books[bookMonitorKey("binance", "BTCUSDT")].checksum = calculator
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Bids:   priceVolumesFromText("30000,1"),
+		Asks:   priceVolumesFromText("30010,1"),
+	})
+
+	stream.EmitBookUpdate(types.SliceOrderBook{
+		Symbol:   "BTCUSDT",
+		Time:     now,
+		Bids:     priceVolumesFromText("30001,1"),
+		Checksum: 100,
+	})
+	assert.True(t, monitor.IsHealthy("binance", "BTCUSDT"))
+
+	stream.EmitBookUpdate(types.SliceOrderBook{
+		Symbol:   "BTCUSDT",
+		Time:     now,
+		Bids:     priceVolumesFromText("30002,1"),
+		Checksum: 101,
+	})
+	assert.False(t, monitor.IsHealthy("binance", "BTCUSDT"))
+
+	select {
+	case <-stream.ReconnectC:
+		t.Fatal("market data stream is reconnected with the resync disabled")
+	default:
+	}
+
+	// the nil monitor considers all the books healthy
+	var nilMonitor *BookMonitor
+	assert.True(t, nilMonitor.IsHealthy("binance", "BTCUSDT"))
+}
@@ -18,6 +18,12 @@ func BootstrapEnvironmentLightweight(ctx context.Context, environ *Environment,
 		environ.SetLogging(userConfig.Logging)
 	}
 
+	// the environment options (disableStartupBalanceQuery, disableMarketDataStore, bookMonitor, etc.)
+	// are applied to the live sessions as well, not only to the replay command
+	if userConfig.Environment != nil {
+		environ.SetEnvironmentConfig(userConfig.Environment)
+	}
+
 	if userConfig.Persistence != nil {
 		if err := ConfigurePersistence(ctx, environ, userConfig.Persistence); err != nil {
 			return errors.Wrap(err, "persistence configure error")
@@ -46,6 +52,12 @@ func BootstrapEnvironment(ctx context.Context, environ *Environment, userConfig
 		environ.SetLogging(userConfig.Logging)
 	}
 
+	// the environment options (disableStartupBalanceQuery, disableMarketDataStore, bookMonitor, etc.)
+	// are applied to the live sessions as well, not only to the replay command
+	if userConfig.Environment != nil {
+		environ.SetEnvironmentConfig(userConfig.Environment)
+	}
+
 	if userConfig.Persistence != nil {
 		if err := ConfigurePersistence(ctx, environ, userConfig.Persistence); err != nil {
 			return errors.Wrap(err, "persistence configure error")
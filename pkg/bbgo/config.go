@@ -348,6 +348,18 @@ type EnvironmentConfig struct {
 	MaxSessionTradeBufferSize int `json:"maxSessionTradeBufferSize"`
 
 	SyncBufferPeriod *types.Duration `json:"syncBufferPeriod"`
+
+	// BookMonitor enables the order book health monitor of all the sessions
+	BookMonitor *BookMonitorConfig `json:"bookMonitor,omitempty"`
+
+	// MarketDataHub shares the market data connections between the sessions of the same exchange
+	MarketDataHub *MarketDataHubConfig `json:"marketDataHub,omitempty"`
+
+	// StreamWatchdog enables the watchdog of the silent market data subscriptions of all the sessions
+	StreamWatchdog *StreamWatchdogConfig `json:"streamWatchdog,omitempty"`
+
+	// IndicatorSnapshot persists the indicator klines on shutdown and restores them on boot
+	IndicatorSnapshot *IndicatorSnapshotConfig `json:"indicatorSnapshot,omitempty"`
 }
 
 type Config struct {
@@ -0,0 +1,289 @@
+package bbgo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const defaultConsolidatedBookStaleness = 30 * time.Second
+
+// ConsolidatedBookSource is an order book of a session merged into the consolidated order book
+type ConsolidatedBookSource struct {
+	// Name is the identity of the source, defaults to the session name
+	Name string
+
+	Session *ExchangeSession
+
+	// Symbol is the symbol of the source book, the sessions may use different symbols for the equivalent market,
+	// e.g., BTCUSDT and BTCTWD
+	Symbol string
+
+	Book *types.StreamOrderBook
+
+	// FeeRate is the fee rate applied to the price levels, defaults to the taker fee rate of the session.
+	// The bid prices are lowered and the ask prices are raised by the fee rate.
+	FeeRate fixedpoint.Value
+
+	// QuoteRate converts the quote currency of the source to the quote currency of the consolidated book,
+	// e.g., 0.03125 for converting TWD to USDT when 1 USDT = 32 TWD, defaults to 1
+	QuoteRate fixedpoint.Value
+
+	// QuoteRateFunc returns the latest quote rate, it overrides QuoteRate when the rate is available
+	QuoteRateFunc func() (fixedpoint.Value, bool)
+
+	// MaxStaleness is the max duration since the last book update, the stale sources are excluded
+	MaxStaleness time.Duration
+}
+
+func (s *ConsolidatedBookSource) quoteRate() fixedpoint.Value {
+	if s.QuoteRateFunc != nil {
+		if rate, ok := s.QuoteRateFunc(); ok && rate.Sign() > 0 {
+			return rate
+		}
+	}
+
+	return s.QuoteRate
+}
+
+// ConsolidatedPriceVolume is a fee-adjusted and quote-normalized price level of a source
+type ConsolidatedPriceVolume struct {
+	types.PriceVolume
+
+	Source string
+}
+
+func (pv ConsolidatedPriceVolume) String() string {
+	return fmt.Sprintf("%s@%s", pv.PriceVolume.String(), pv.Source)
+}
+
+type ConsolidatedPriceVolumeSlice []ConsolidatedPriceVolume
+
+// PriceVolumes drops the source of the levels
+func (slice ConsolidatedPriceVolumeSlice) PriceVolumes() types.PriceVolumeSlice {
+	pvs := make(types.PriceVolumeSlice, 0, len(slice))
+	for _, pv := range slice {
+		pvs = append(pvs, pv.PriceVolume)
+	}
+	return pvs
+}
+
+// ConsolidatedSourceStatus is the staleness status of a source
+type ConsolidatedSourceStatus struct {
+	Source         string
+	LastUpdateTime time.Time
+	Stale          bool
+}
+
+// ConsolidatedOrderBook merges the stream order books of the equivalent symbols from multiple sessions.
+// The price levels are fee-adjusted and converted to the quote currency of the consolidated book,
+// and the stale sources are excluded from the consolidated view.
+//
+//go:generate callbackgen -type ConsolidatedOrderBook
+type ConsolidatedOrderBook struct {
+	Symbol string
+
+	mu      sync.Mutex
+	sources []*ConsolidatedBookSource
+
+	bestBid, bestAsk       ConsolidatedPriceVolume
+	hasBestBid, hasBestAsk bool
+
+	now func() time.Time
+
+	bestPriceUpdateCallbacks []func(bid, ask ConsolidatedPriceVolume)
+}
+
+func NewConsolidatedOrderBook(symbol string) *ConsolidatedOrderBook {
+	return &ConsolidatedOrderBook{
+		Symbol: symbol,
+		now:    time.Now,
+	}
+}
+
+// AddSource adds the source book and binds the book updates for the best price callbacks
+func (b *ConsolidatedOrderBook) AddSource(source ConsolidatedBookSource) error {
+	if source.Book == nil {
+		if source.Session == nil {
+			return fmt.Errorf("consolidated book source requires either book or session")
+		}
+
+		symbol := source.Symbol
+		if symbol == "" {
+			symbol = b.Symbol
+		}
+
+		book, ok := source.Session.OrderBook(symbol)
+		if !ok {
+			return fmt.Errorf("session %s has no order book of %s, please subscribe the book channel", source.Session.Name, symbol)
+		}
+
+		source.Book = book
+	}
+
+	if source.Symbol == "" {
+		source.Symbol = source.Book.Symbol
+	}
+
+	if source.Name == "" {
+		if source.Session != nil {
+			source.Name = source.Session.Name
+		} else {
+			source.Name = source.Symbol
+		}
+	}
+
+	if source.FeeRate.IsZero() && source.Session != nil {
+		source.FeeRate = source.Session.TakerFeeRate
+	}
+
+	if source.QuoteRate.IsZero() {
+		source.QuoteRate = fixedpoint.One
+	}
+
+	if source.MaxStaleness == 0 {
+		source.MaxStaleness = defaultConsolidatedBookStaleness
+	}
+
+	b.mu.Lock()
+	for _, s := range b.sources {
+		if s.Name == source.Name {
+			b.mu.Unlock()
+			return fmt.Errorf("consolidated book source %s already exists", source.Name)
+		}
+	}
+	b.sources = append(b.sources, &source)
+	b.mu.Unlock()
+
+	source.Book.OnSnapshot(func(_ types.SliceOrderBook) { b.Refresh() })
+	source.Book.OnUpdate(func(_ types.SliceOrderBook) { b.Refresh() })
+	return nil
+}
+
+// Sources returns the status of all the sources
+func (b *ConsolidatedOrderBook) Sources() []ConsolidatedSourceStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	var statuses []ConsolidatedSourceStatus
+	for _, s := range b.sources {
+		lastUpdateTime := s.Book.LastUpdateTime()
+		statuses = append(statuses, ConsolidatedSourceStatus{
+			Source:         s.Name,
+			LastUpdateTime: lastUpdateTime,
+			Stale:          isStale(now, lastUpdateTime, s.MaxStaleness),
+		})
+	}
+
+	return statuses
+}
+
+// SideBook returns the merged price levels of the fresh sources,
+// the bids are sorted in descending order and the asks are sorted in ascending order
+func (b *ConsolidatedOrderBook) SideBook(side types.SideType) ConsolidatedPriceVolumeSlice {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sideBook(side)
+}
+
+func (b *ConsolidatedOrderBook) sideBook(side types.SideType) ConsolidatedPriceVolumeSlice {
+	now := b.now()
+
+	var merged ConsolidatedPriceVolumeSlice
+	for _, s := range b.sources {
+		if isStale(now, s.Book.LastUpdateTime(), s.MaxStaleness) {
+			continue
+		}
+
+		feeFactor := fixedpoint.One.Add(s.FeeRate)
+		if side == types.SideTypeBuy {
+			feeFactor = fixedpoint.One.Sub(s.FeeRate)
+		}
+
+		quoteRate := s.quoteRate()
+		for _, pv := range s.Book.SideBook(side) {
+			merged = append(merged, ConsolidatedPriceVolume{
+				PriceVolume: types.PriceVolume{Price: pv.Price.Mul(quoteRate).Mul(feeFactor), Volume: pv.Volume},
+				Source:      s.Name,
+			})
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if side == types.SideTypeBuy {
+			return merged[i].Price.Compare(merged[j].Price) > 0
+		}
+		return merged[i].Price.Compare(merged[j].Price) < 0
+	})
+
+	return merged
+}
+
+func (b *ConsolidatedOrderBook) BestBid() (ConsolidatedPriceVolume, bool) {
+	bids := b.SideBook(types.SideTypeBuy)
+	if len(bids) == 0 {
+		return ConsolidatedPriceVolume{}, false
+	}
+	return bids[0], true
+}
+
+func (b *ConsolidatedOrderBook) BestAsk() (ConsolidatedPriceVolume, bool) {
+	asks := b.SideBook(types.SideTypeSell)
+	if len(asks) == 0 {
+		return ConsolidatedPriceVolume{}, false
+	}
+	return asks[0], true
+}
+
+func (b *ConsolidatedOrderBook) BestBidAndAsk() (bid, ask ConsolidatedPriceVolume, ok bool) {
+	var ok1, ok2 bool
+	bid, ok1 = b.BestBid()
+	ask, ok2 = b.BestAsk()
+	return bid, ask, ok1 && ok2
+}
+
+// AverageDepthPrice returns the depth-weighted price of the required quantity across all the fresh sources
+func (b *ConsolidatedOrderBook) AverageDepthPrice(side types.SideType, requiredQuantity fixedpoint.Value) fixedpoint.Value {
+	return b.SideBook(side).PriceVolumes().AverageDepthPrice(requiredQuantity)
+}
+
+// Refresh recalculates the best prices and emits the best price update when they are changed,
+// it's called on every source book update
+func (b *ConsolidatedOrderBook) Refresh() {
+	b.mu.Lock()
+	bids := b.sideBook(types.SideTypeBuy)
+	asks := b.sideBook(types.SideTypeSell)
+
+	var bid, ask ConsolidatedPriceVolume
+	hasBid, hasAsk := len(bids) > 0, len(asks) > 0
+	if hasBid {
+		bid = bids[0]
+	}
+	if hasAsk {
+		ask = asks[0]
+	}
+
+	changed := hasBid != b.hasBestBid || hasAsk != b.hasBestAsk ||
+		!samePriceVolume(bid, b.bestBid) || !samePriceVolume(ask, b.bestAsk)
+
+	b.bestBid, b.bestAsk = bid, ask
+	b.hasBestBid, b.hasBestAsk = hasBid, hasAsk
+	b.mu.Unlock()
+
+	if changed && hasBid && hasAsk {
+		b.EmitBestPriceUpdate(bid, ask)
+	}
+}
+
+func samePriceVolume(a, b ConsolidatedPriceVolume) bool {
+	return a.Source == b.Source && a.Price.Eq(b.Price) && a.Volume.Eq(b.Volume)
+}
+
+func isStale(now, lastUpdateTime time.Time, maxStaleness time.Duration) bool {
+	return lastUpdateTime.IsZero() || (maxStaleness > 0 && now.Sub(lastUpdateTime) > maxStaleness)
+}
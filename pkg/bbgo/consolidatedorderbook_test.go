@@ -0,0 +1,152 @@
+package bbgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/c9s/bbgo/pkg/testing/testhelper"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestStreamBook(symbol string, t time.Time, bids, asks types.PriceVolumeSlice) *types.StreamOrderBook {
+	book := types.NewStreamBook(symbol)
+	book.Load(types.SliceOrderBook{Symbol: symbol, Time: t, Bids: bids, Asks: asks})
+	return book
+}
+
+func TestConsolidatedOrderBook(t *testing.T) {
+	now := time.Now()
+
+	bookA := newTestStreamBook("BTCUSDT", now,
+		priceVolumesFromText("30000,1;29990,2"),
+		priceVolumesFromText("30010,1;30020,2"))
+
+	// BTCTWD with 1 USDT = 32 TWD
+	bookB := newTestStreamBook("BTCTWD", now,
+		priceVolumesFromText("959680,1;959360,1"),
+		priceVolumesFromText("961280,1"))
+
+	book := NewConsolidatedOrderBook("BTCUSDT")
+	book.now = func() time.Time { return now }
+
+	require.NoError(t, book.AddSource(ConsolidatedBookSource{Name: "a", Book: bookA}))
+	require.NoError(t, book.AddSource(ConsolidatedBookSource{
+		Name:      "b",
+		Book:      bookB,
+		QuoteRate: Number("0.03125"),
+		FeeRate:   Number("0.001"),
+	}))
+	assert.Error(t, book.AddSource(ConsolidatedBookSource{Name: "a", Book: bookA}))
+
+	bid, ask, ok := book.BestBidAndAsk()
+	require.True(t, ok)
+
+	// 959680 / 32 * (1 - 0.001) = 29960.01
+	assert.Equal(t, "a", bid.Source)
+	assert.Equal(t, "30000", bid.Price.String())
+
+	// 961280 / 32 * (1 + 0.001) = 30070.04
+	assert.Equal(t, "a", ask.Source)
+	assert.Equal(t, "30010", ask.Price.String())
+
+	bids := book.SideBook(types.SideTypeBuy)
+	require.Len(t, bids, 4)
+	assert.Equal(t, "b", bids[2].Source)
+	assert.InDelta(t, 29960.01, bids[2].Price.Float64(), 1e-6)
+
+	asks := book.SideBook(types.SideTypeSell)
+	require.Len(t, asks, 3)
+	assert.InDelta(t, 30070.04, asks[2].Price.Float64(), 1e-6)
+
+	// (30000 * 1 + 29990 * 1) / 2
+	assert.Equal(t, "29995", book.AverageDepthPrice(types.SideTypeBuy, Number(2)).String())
+
+	// source a becomes stale
+	book.now = func() time.Time { return now.Add(time.Minute) }
+	bookB.Load(types.SliceOrderBook{
+		Symbol: "BTCTWD",
+		Time:   now.Add(time.Minute),
+		Bids:   priceVolumesFromText("959680,1"),
+		Asks:   priceVolumesFromText("961280,1"),
+	})
+
+	statuses := book.Sources()
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Stale)
+	assert.False(t, statuses[1].Stale)
+
+	bid, ok = book.BestBid()
+	require.True(t, ok)
+	assert.Equal(t, "b", bid.Source)
+}
+
+func TestConsolidatedOrderBook_BestPriceUpdate(t *testing.T) {
+	now := time.Now()
+	bookA := newTestStreamBook("BTCUSDT", now,
+		priceVolumesFromText("30000,1"),
+		priceVolumesFromText("30010,1"))
+
+	book := NewConsolidatedOrderBook("BTCUSDT")
+	require.NoError(t, book.AddSource(ConsolidatedBookSource{Name: "a", Book: bookA}))
+
+	var updates []ConsolidatedPriceVolume
+	book.OnBestPriceUpdate(func(bid, ask ConsolidatedPriceVolume) {
+		updates = append(updates, bid)
+	})
+
+	book.Refresh()
+	require.Len(t, updates, 1)
+
+	// unchanged best prices do not trigger the callbacks
+	book.Refresh()
+	require.Len(t, updates, 1)
+
+	bookA.Update(types.SliceOrderBook{Symbol: "BTCUSDT", Time: now, Bids: priceVolumesFromText("30005,1")})
+	bookA.EmitUpdate(types.SliceOrderBook{Symbol: "BTCUSDT"})
+	require.Len(t, updates, 2)
+	assert.Equal(t, "30005", updates[1].Price.String())
+}
+
+// priceVolumesFromText parses the price levels in the "price,volume;price,volume" format
+func priceVolumesFromText(text string) (pvs types.PriceVolumeSlice) {
+	for _, level := range strings.Split(text, ";") {
+		fields := strings.Split(level, ",")
+		pvs = append(pvs, types.PriceVolume{Price: Number(fields[0]), Volume: Number(fields[1])})
+	}
+	return pvs
+}
+
+func TestEnvironment_ConsolidatedOrderBook(t *testing.T) {
+	now := time.Now()
+	environ := NewEnvironment()
+	for _, name := range []string{"a", "b"} {
+		environ.sessions[name] = &ExchangeSession{
+			Name: name,
+			orderBooks: map[string]*types.StreamOrderBook{
+				"BTCUSDT": newTestStreamBook("BTCUSDT", now,
+					priceVolumesFromText("30000,1"),
+					priceVolumesFromText("30010,1")),
+			},
+		}
+	}
+
+	all, err := environ.ConsolidatedOrderBook("BTCUSDT")
+	require.NoError(t, err)
+	assert.Len(t, all.Sources(), 2)
+
+	same, err := environ.ConsolidatedOrderBook("BTCUSDT", "b", "a")
+	require.NoError(t, err)
+	assert.Same(t, all, same)
+
+	onlyA, err := environ.ConsolidatedOrderBook("BTCUSDT", "a")
+	require.NoError(t, err)
+	assert.NotSame(t, all, onlyA)
+	assert.Len(t, onlyA.Sources(), 1)
+
+	_, err = environ.ConsolidatedOrderBook("BTCUSDT", "c")
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	stdlog "log"
 	"math/rand"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -114,6 +115,15 @@ type Environment struct {
 	environmentConfig *EnvironmentConfig
 
 	sessions map[string]*ExchangeSession
+
+	bookMonitor *BookMonitor
+
+	streamWatchdog *StreamWatchdog
+
+	marketDataHub *MarketDataHub
+
+	consolidatedOrderBooksMutex sync.Mutex
+	consolidatedOrderBooks      map[string]*ConsolidatedOrderBook
 }
 
 func NewEnvironment() *Environment {
@@ -124,6 +134,8 @@ func NewEnvironment() *Environment {
 		sessions:      make(map[string]*ExchangeSession),
 		startTime:     now,
 
+		consolidatedOrderBooks: make(map[string]*ConsolidatedOrderBook),
+
 		syncStatus: SyncNotStarted,
 	}
 }
@@ -145,6 +157,76 @@ func (environ *Environment) Sessions() map[string]*ExchangeSession {
 	return environ.sessions
 }
 
+// ConsolidatedOrderBook returns the consolidated order book of the symbol across the sessions.
+// All the sessions subscribing the book of the symbol are merged if no session name is given.
+// The book is created on the first call and shared by the later calls with the same symbol and the same sessions,
+// more sources (e.g., the equivalent symbols with a different quote currency) can be added by
+// ConsolidatedOrderBook.AddSource.
+func (environ *Environment) ConsolidatedOrderBook(symbol string, sessionNames ...string) (*ConsolidatedOrderBook, error) {
+	environ.consolidatedOrderBooksMutex.Lock()
+	defer environ.consolidatedOrderBooksMutex.Unlock()
+
+	if len(sessionNames) == 0 {
+		for name, session := range environ.sessions {
+			if _, ok := session.OrderBook(symbol); ok {
+				sessionNames = append(sessionNames, name)
+			}
+		}
+	} else {
+		sessionNames = append([]string(nil), sessionNames...)
+	}
+
+	if len(sessionNames) == 0 {
+		return nil, fmt.Errorf("no session subscribes the order book of %s", symbol)
+	}
+
+	sort.Strings(sessionNames)
+	key := consolidatedOrderBookKey(symbol, sessionNames)
+	if book, ok := environ.consolidatedOrderBooks[key]; ok {
+		return book, nil
+	}
+
+	book := NewConsolidatedOrderBook(symbol)
+	for _, name := range sessionNames {
+		session, ok := environ.sessions[name]
+		if !ok {
+			return nil, fmt.Errorf("session %s not found", name)
+		}
+
+		if err := book.AddSource(ConsolidatedBookSource{Session: session, Symbol: symbol}); err != nil {
+			return nil, err
+		}
+	}
+
+	environ.consolidatedOrderBooks[key] = book
+	return book, nil
+}
+
+// consolidatedOrderBookKey returns the cache key of the consolidated order book, the session names must be sorted.
+func consolidatedOrderBookKey(symbol string, sessionNames []string) string {
+	return symbol + ":" + strings.Join(sessionNames, ",")
+}
+
+// MarketDataHub returns the market data hub, it's nil if the hub is not enabled in the environment config.
+func (environ *Environment) MarketDataHub() *MarketDataHub {
+	return environ.marketDataHub
+}
+
+// BookMonitor returns the order book health monitor, it's nil if the monitor is not enabled in the environment config.
+// BookMonitor.IsHealthy is safe to call on the nil monitor.
+func (environ *Environment) BookMonitor() *BookMonitor {
+	return environ.bookMonitor
+}
+
+// StreamWatchdog returns the market data stream watchdog, it's nil if the watchdog is not enabled in the environment config.
+func (environ *Environment) StreamWatchdog() *StreamWatchdog {
+	return environ.streamWatchdog
+}
+
+func (environ *Environment) SetEnvironmentConfig(config *EnvironmentConfig) {
+	environ.environmentConfig = config
+}
+
 func (environ *Environment) SetLogging(config *LoggingConfig) {
 	environ.loggingConfig = config
 }
@@ -300,6 +382,19 @@ func (environ *Environment) IsBackTesting() bool {
 
 // Init prepares the data that will be used by the strategies
 func (environ *Environment) Init(ctx context.Context) (err error) {
+	// the market data streams are replaced before the session initialization binds the stream callbacks
+	if environ.environmentConfig != nil && environ.environmentConfig.MarketDataHub != nil && environ.marketDataHub == nil {
+		environ.marketDataHub = NewMarketDataHub(environ.environmentConfig.MarketDataHub)
+		for n := range environ.sessions {
+			var session = environ.sessions[n]
+			if session.IsInitialized {
+				continue
+			}
+
+			session.MarketDataStream = environ.marketDataHub.NewSessionStream(session)
+		}
+	}
+
 	for n := range environ.sessions {
 		var session = environ.sessions[n]
 		if err = session.Init(ctx, environ); err != nil {
@@ -321,6 +416,21 @@ func (environ *Environment) Start(ctx context.Context) (err error) {
 			return err
 		}
 	}
+
+	// the order books are created by InitSymbols, so that the book monitor is set up here
+	if environ.environmentConfig != nil && environ.environmentConfig.BookMonitor != nil && environ.bookMonitor == nil {
+		environ.bookMonitor = NewBookMonitor(*environ.environmentConfig.BookMonitor)
+		for n := range environ.sessions {
+			environ.bookMonitor.AddSession(environ.sessions[n])
+		}
+	}
+
+	if environ.environmentConfig != nil && environ.environmentConfig.StreamWatchdog != nil && environ.streamWatchdog == nil {
+		environ.streamWatchdog = NewStreamWatchdog(*environ.environmentConfig.StreamWatchdog)
+		for n := range environ.sessions {
+			environ.streamWatchdog.AddSession(environ.sessions[n])
+		}
+	}
 	return
 }
 
@@ -426,7 +536,35 @@ func (environ *Environment) Connect(ctx context.Context) error {
 			logger.Warnf("exchange session %s has no subscriptions", session.Name)
 		} else {
 			// add the subscribe requests to the stream
+			var subscribed = make(map[types.Subscription]struct{})
 			for _, s := range session.Subscriptions {
+				// the bar subscriptions are translated into the subscriptions the bars are built from
+				if s.Channel == types.KLineChannel && s.Options.Interval.IsBar() {
+					logger.Infof("building %s %s bars", s.Symbol, s.Options.Interval)
+
+					source, err := session.bindBarBuilder(s)
+					if err != nil {
+						return err
+					}
+					s = source
+				} else if s.Channel == types.KLineChannel {
+					// the intervals not supported by the exchange are synthesized from a base interval
+					if baseInterval, ok := session.syntheticBaseInterval(s.Options.Interval); ok {
+						logger.Infof("synthesizing %s %s klines from %s klines", s.Symbol, s.Options.Interval, baseInterval)
+
+						source, err := session.bindKLineAggregator(ctx, s, baseInterval, environ.startTime)
+						if err != nil {
+							return err
+						}
+						s = source
+					}
+				}
+
+				if _, ok := subscribed[s]; ok {
+					continue
+				}
+				subscribed[s] = struct{}{}
+
 				logger.Infof("subscribing %s %s %v", s.Symbol, s.Channel, s.Options)
 				session.MarketDataStream.Subscribe(s.Channel, s.Symbol, s.Options)
 			}
@@ -445,6 +583,14 @@ func (environ *Environment) Connect(ctx context.Context) error {
 		}
 	}
 
+	if environ.bookMonitor != nil {
+		go environ.bookMonitor.Run(ctx)
+	}
+
+	if environ.streamWatchdog != nil {
+		go environ.streamWatchdog.Run(ctx)
+	}
+
 	return nil
 }
 
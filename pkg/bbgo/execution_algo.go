@@ -0,0 +1,466 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OrderExecution is the interface of the order execution algorithms.
+// An execution buys or sells the target quantity of a symbol, and reports the execution quality when it's done.
+type OrderExecution interface {
+	Run(ctx context.Context) error
+	Done() <-chan struct{}
+	Shutdown(shutdownCtx context.Context)
+	Report() *ExecutionReport
+}
+
+var _ OrderExecution = &TwapExecution{}
+var _ OrderExecution = &VWAPExecution{}
+var _ OrderExecution = &POVExecution{}
+var _ OrderExecution = &IcebergExecution{}
+var _ OrderExecution = &ShortfallExecution{}
+
+// executionSchedule decides how much of the target quantity should be executed over time
+type executionSchedule interface {
+	// scheduledQuantity returns the cumulative quantity which should be executed by the given time
+	scheduledQuantity(now time.Time) fixedpoint.Value
+}
+
+// marketTradeHandler is implemented by the schedules driven by the market trades,
+// the own trades are passed as well, since they are also in the market trades
+type marketTradeHandler interface {
+	handleMarketTrade(trade types.Trade)
+	handleOwnTrade(trade types.Trade)
+}
+
+// AlgoExecution is the base of the execution algorithms driven by a schedule.
+// It places one order at a time. The order rests at the best price of its side while the execution keeps up with
+// the schedule, and crosses the spread when the execution falls behind the schedule by more than MaxLag.
+type AlgoExecution struct {
+	Session        *ExchangeSession
+	Symbol         string
+	Side           types.SideType
+	TargetQuantity fixedpoint.Value
+
+	// MaxSliceQuantity is the max quantity of an order, zero means no limit
+	MaxSliceQuantity fixedpoint.Value
+
+	// PriceLimit is the highest buy price or the lowest sell price, zero means no limit
+	PriceLimit fixedpoint.Value
+
+	// MaxLag is the ratio of the target quantity that the execution can fall behind the schedule with the maker orders,
+	// zero means the orders never cross the spread before the deadline
+	MaxLag fixedpoint.Value
+
+	// DeadlineTime is the time to execute the rest quantity by a market order, or by a limit order at the price limit
+	DeadlineTime time.Time
+
+	UpdateInterval time.Duration
+
+	// OrderExecutor submits the orders, it defaults to the session order executor.
+	// A strategy can set it to its GeneralOrderExecutor, so that the trades are collected into the strategy position.
+	OrderExecutor OrderExecutor
+
+	market           types.Market
+	marketDataStream types.Stream
+
+	userDataStream       types.Stream
+	userDataStreamCtx    context.Context
+	cancelUserDataStream context.CancelFunc
+
+	orderBook    *types.StreamOrderBook
+	activeOrders *ActiveOrderBook
+	orderStore   *core.OrderStore
+
+	executionCtx    context.Context
+	cancelExecution context.CancelFunc
+
+	stoppedC chan struct{}
+
+	// deadlineOrderSubmitted is set when the rest quantity is submitted at the deadline
+	deadlineOrderSubmitted bool
+
+	startTime time.Time
+	report    ExecutionReport
+
+	mu sync.Mutex
+}
+
+func (e *AlgoExecution) base() *AlgoExecution {
+	return e
+}
+
+func (e *AlgoExecution) connectMarketData(ctx context.Context) {
+	log.Infof("connecting market data stream...")
+	if err := e.marketDataStream.Connect(ctx); err != nil {
+		log.WithError(err).Errorf("market data stream connect error")
+	}
+}
+
+func (e *AlgoExecution) connectUserData(ctx context.Context) {
+	log.Infof("connecting user data stream...")
+	if err := e.userDataStream.Connect(ctx); err != nil {
+		log.WithError(err).Errorf("user data stream connect error")
+	}
+}
+
+// newOrder returns the order of the deficit between the scheduled quantity and the executed quantity
+func (e *AlgoExecution) newOrder(now time.Time, bestBid, bestAsk, executed, scheduled fixedpoint.Value) (types.SubmitOrder, bool) {
+	restQuantity := e.TargetQuantity.Sub(executed)
+	if restQuantity.Compare(e.market.MinQuantity) < 0 {
+		return types.SubmitOrder{}, false
+	}
+
+	if !e.DeadlineTime.IsZero() && now.After(e.DeadlineTime) {
+		if e.PriceLimit.IsZero() {
+			return types.SubmitOrder{
+				Symbol:   e.Symbol,
+				Side:     e.Side,
+				Type:     types.OrderTypeMarket,
+				Quantity: restQuantity,
+				Market:   e.market,
+			}, true
+		}
+
+		return types.SubmitOrder{
+			Symbol:      e.Symbol,
+			Side:        e.Side,
+			Type:        types.OrderTypeLimit,
+			Quantity:    restQuantity,
+			Price:       e.PriceLimit,
+			Market:      e.market,
+			TimeInForce: types.TimeInForceGTC,
+		}, true
+	}
+
+	deficit := fixedpoint.Min(scheduled, e.TargetQuantity).Sub(executed)
+	if deficit.Compare(e.market.MinQuantity) < 0 {
+		return types.SubmitOrder{}, false
+	}
+
+	quantity := deficit
+	if e.MaxSliceQuantity.Sign() > 0 {
+		quantity = fixedpoint.Min(quantity, e.MaxSliceQuantity)
+	}
+
+	// merge the rest quantity into this order if it's less than the min quantity
+	if restQuantity.Sub(quantity).Compare(e.market.MinQuantity) < 0 {
+		quantity = restQuantity
+	}
+
+	orderType := types.OrderTypeLimitMaker
+	price := bestBid
+	if e.Side == types.SideTypeSell {
+		price = bestAsk
+	}
+
+	if e.MaxLag.Sign() > 0 && deficit.Div(e.TargetQuantity).Compare(e.MaxLag) > 0 {
+		orderType = types.OrderTypeLimit
+		price = bestAsk
+		if e.Side == types.SideTypeSell {
+			price = bestBid
+		}
+	}
+
+	if e.PriceLimit.Sign() > 0 {
+		switch e.Side {
+		case types.SideTypeBuy:
+			price = fixedpoint.Min(price, e.PriceLimit)
+		case types.SideTypeSell:
+			price = fixedpoint.Max(price, e.PriceLimit)
+		}
+	}
+
+	quantity = AdjustQuantityByMinAmount(e.market.TruncateQuantity(quantity), price, e.market.MinNotional)
+	return types.SubmitOrder{
+		Symbol:      e.Symbol,
+		Side:        e.Side,
+		Type:        orderType,
+		Quantity:    quantity,
+		Price:       price,
+		Market:      e.market,
+		TimeInForce: types.TimeInForceGTC,
+	}, true
+}
+
+// adjustByBalance reduces the order quantity to the available balance, the price of the market order is the best ask
+func (e *AlgoExecution) adjustByBalance(orderForm types.SubmitOrder, bestAsk fixedpoint.Value) types.SubmitOrder {
+	switch e.Side {
+	case types.SideTypeSell:
+		if b, ok := e.Session.GetAccount().Balance(e.market.BaseCurrency); ok {
+			orderForm.Quantity = fixedpoint.Min(b.Available, orderForm.Quantity)
+		}
+
+	case types.SideTypeBuy:
+		price := orderForm.Price
+		if price.IsZero() {
+			price = bestAsk
+		}
+
+		if b, ok := e.Session.GetAccount().Balance(e.market.QuoteCurrency); ok {
+			orderForm.Quantity = AdjustQuantityByMaxAmount(orderForm.Quantity, price, b.Available)
+		}
+	}
+	return orderForm
+}
+
+func (e *AlgoExecution) executedQuantity() fixedpoint.Value {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.report.ExecutedQuantity
+}
+
+func (e *AlgoExecution) updateOrder(ctx context.Context, schedule executionSchedule) error {
+	bid, ask, ok := e.orderBook.BestBidAndAsk()
+	if !ok {
+		return fmt.Errorf("the %s order book is not ready", e.Symbol)
+	}
+
+	e.mu.Lock()
+	if e.report.ArrivalPrice.IsZero() {
+		e.report.ArrivalPrice = bid.Price.Add(ask.Price).Div(fixedpoint.Two)
+	}
+	e.mu.Unlock()
+
+	// the rest quantity was submitted at the deadline, the execution is done when the order is done
+	if e.deadlineOrderSubmitted {
+		if e.activeOrders.NumOfOrders() == 0 {
+			e.cancelExecution()
+		}
+		return nil
+	}
+
+	now := time.Now()
+	orderForm, ok := e.newOrder(now, bid.Price, ask.Price, e.executedQuantity(), schedule.scheduledQuantity(now))
+	if !ok {
+		return nil
+	}
+
+	// keep the active order if it's still on the price
+	if orders := e.activeOrders.Orders(); len(orders) > 0 {
+		if orders[0].Price.Eq(orderForm.Price) && orders[0].Type == orderForm.Type {
+			return nil
+		}
+
+		e.cancelActiveOrders()
+
+		// the executed quantity might be changed by the canceled order
+		orderForm, ok = e.newOrder(now, bid.Price, ask.Price, e.executedQuantity(), schedule.scheduledQuantity(now))
+		if !ok {
+			return nil
+		}
+	}
+
+	orderForm = e.adjustByBalance(orderForm, ask.Price)
+	if orderForm.Quantity.Compare(e.market.MinQuantity) < 0 {
+		return fmt.Errorf("insufficient balance for the %s %s order", e.Symbol, e.Side)
+	}
+
+	createdOrders, err := e.OrderExecutor.SubmitOrders(ctx, orderForm)
+	if err != nil {
+		return err
+	}
+
+	e.activeOrders.Add(createdOrders...)
+	e.orderStore.Add(createdOrders...)
+	e.deadlineOrderSubmitted = !e.DeadlineTime.IsZero() && now.After(e.DeadlineTime)
+
+	e.mu.Lock()
+	e.report.NumOfOrders += len(createdOrders)
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *AlgoExecution) cancelActiveOrders() {
+	gracefulCtx, gracefulCancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer gracefulCancel()
+	if err := e.activeOrders.GracefulCancel(gracefulCtx, e.Session.Exchange); err != nil {
+		log.WithError(err).Errorf("unable to cancel the %s execution orders", e.Symbol)
+	}
+}
+
+func (e *AlgoExecution) orderUpdater(ctx context.Context, schedule executionSchedule) {
+	updateLimiter := rate.NewLimiter(rate.Every(3*time.Second), 1)
+	ticker := time.NewTicker(e.UpdateInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		e.cancelActiveOrders()
+		e.cancelUserDataStream()
+		e.emitDone()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-e.orderBook.C:
+			if !updateLimiter.Allow() {
+				break
+			}
+
+			if e.cancelContextIfTargetQuantityFilled() {
+				return
+			}
+
+			if err := e.updateOrder(ctx, schedule); err != nil {
+				log.WithError(err).Errorf("order update failed")
+			}
+
+		case <-ticker.C:
+			if e.cancelContextIfTargetQuantityFilled() {
+				return
+			}
+
+			if err := e.updateOrder(ctx, schedule); err != nil {
+				log.WithError(err).Errorf("order update failed")
+			}
+		}
+	}
+}
+
+func (e *AlgoExecution) cancelContextIfTargetQuantityFilled() bool {
+	if e.TargetQuantity.Sub(e.executedQuantity()).Compare(e.market.MinQuantity) < 0 {
+		log.Infof("filled target quantity, canceling the order execution context")
+		e.cancelExecution()
+		return true
+	}
+	return false
+}
+
+func (e *AlgoExecution) handleTradeUpdate(trade types.Trade) {
+	if trade.Symbol != e.Symbol || !e.orderStore.Exists(trade.OrderID) {
+		return
+	}
+
+	log.Info(trade.String())
+
+	e.mu.Lock()
+	e.report.addTrade(trade)
+	e.mu.Unlock()
+}
+
+func (e *AlgoExecution) handleFilledOrder(order types.Order) {
+	log.Info(order.String())
+	e.cancelContextIfTargetQuantityFilled()
+}
+
+// run starts the execution of the schedule
+func (e *AlgoExecution) run(parentCtx context.Context, schedule executionSchedule) error {
+	if e.TargetQuantity.Sign() <= 0 {
+		return fmt.Errorf("target quantity should be positive")
+	}
+
+	var ok bool
+	e.market, ok = e.Session.Market(e.Symbol)
+	if !ok {
+		return fmt.Errorf("market %s not found", e.Symbol)
+	}
+
+	if e.UpdateInterval == 0 {
+		e.UpdateInterval = 10 * time.Second
+	}
+
+	if e.OrderExecutor == nil {
+		e.OrderExecutor = e.Session.OrderExecutor
+	}
+
+	e.mu.Lock()
+	e.startTime = time.Now()
+	e.stoppedC = make(chan struct{})
+	e.executionCtx, e.cancelExecution = context.WithCancel(parentCtx)
+	e.userDataStreamCtx, e.cancelUserDataStream = context.WithCancel(context.Background())
+	e.report = ExecutionReport{
+		Symbol:         e.Symbol,
+		Side:           e.Side,
+		TargetQuantity: e.TargetQuantity,
+		StartTime:      e.startTime,
+	}
+	e.mu.Unlock()
+
+	e.marketDataStream = e.Session.Exchange.NewStream()
+	e.marketDataStream.SetPublicOnly()
+	e.marketDataStream.Subscribe(types.BookChannel, e.Symbol, types.SubscribeOptions{})
+	if handler, ok := schedule.(marketTradeHandler); ok {
+		e.marketDataStream.Subscribe(types.MarketTradeChannel, e.Symbol, types.SubscribeOptions{})
+		e.marketDataStream.OnMarketTrade(types.TradeWith(e.Symbol, handler.handleMarketTrade))
+	}
+
+	e.orderBook = types.NewStreamBook(e.Symbol)
+	e.orderBook.BindStream(e.marketDataStream)
+	go e.connectMarketData(e.executionCtx)
+
+	e.userDataStream = e.Session.Exchange.NewStream()
+	e.userDataStream.OnTradeUpdate(e.handleTradeUpdate)
+	if handler, ok := schedule.(marketTradeHandler); ok {
+		e.userDataStream.OnTradeUpdate(func(trade types.Trade) {
+			if trade.Symbol == e.Symbol && e.orderStore.Exists(trade.OrderID) {
+				handler.handleOwnTrade(trade)
+			}
+		})
+	}
+
+	e.orderStore = core.NewOrderStore(e.Symbol)
+	e.orderStore.BindStream(e.userDataStream)
+	e.activeOrders = NewActiveOrderBook(e.Symbol)
+	e.activeOrders.OnFilled(e.handleFilledOrder)
+	e.activeOrders.BindStream(e.userDataStream)
+
+	go e.connectUserData(e.userDataStreamCtx)
+	go e.orderUpdater(e.executionCtx, schedule)
+	return nil
+}
+
+func (e *AlgoExecution) emitDone() {
+	e.mu.Lock()
+	e.report.EndTime = time.Now()
+	if e.stoppedC == nil {
+		e.stoppedC = make(chan struct{})
+	}
+	close(e.stoppedC)
+	e.mu.Unlock()
+}
+
+func (e *AlgoExecution) Done() (c <-chan struct{}) {
+	e.mu.Lock()
+	// if the channel is not allocated, it means it's not started yet, we need to return a closed channel
+	if e.stoppedC == nil {
+		e.stoppedC = make(chan struct{})
+		close(e.stoppedC)
+	}
+	c = e.stoppedC
+	e.mu.Unlock()
+	return c
+}
+
+// Shutdown stops the execution, cancels the active orders and waits for the execution to be done
+func (e *AlgoExecution) Shutdown(shutdownCtx context.Context) {
+	e.mu.Lock()
+	if e.cancelExecution != nil {
+		e.cancelExecution()
+	}
+	e.mu.Unlock()
+
+	select {
+	case <-shutdownCtx.Done():
+	case <-e.Done():
+	}
+}
+
+// Report returns the execution quality of the executed quantity
+func (e *AlgoExecution) Report() *ExecutionReport {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	report := e.report
+	return &report
+}
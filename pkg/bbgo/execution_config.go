@@ -0,0 +1,136 @@
+package bbgo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type ExecutionAlgorithm string
+
+const (
+	ExecutionAlgorithmTWAP      ExecutionAlgorithm = "twap"
+	ExecutionAlgorithmVWAP      ExecutionAlgorithm = "vwap"
+	ExecutionAlgorithmPOV       ExecutionAlgorithm = "pov"
+	ExecutionAlgorithmIceberg   ExecutionAlgorithm = "iceberg"
+	ExecutionAlgorithmShortfall ExecutionAlgorithm = "is"
+)
+
+// ExecutionConfig is the config of an order execution algorithm, strategies can embed it in their configs
+type ExecutionConfig struct {
+	Algorithm ExecutionAlgorithm `json:"algorithm"`
+
+	// Duration is the time until the deadline, it's required by twap, vwap and is
+	Duration types.Duration `json:"duration,omitempty"`
+
+	// SliceQuantity is the quantity of each twap order, and the max quantity of the orders of the other algorithms
+	SliceQuantity fixedpoint.Value `json:"sliceQuantity,omitempty"`
+
+	PriceLimit fixedpoint.Value `json:"priceLimit,omitempty"`
+	MaxLag     fixedpoint.Value `json:"maxLag,omitempty"`
+
+	UpdateInterval types.Duration `json:"updateInterval,omitempty"`
+
+	// NumOfTicks is the number of the ticks to improve the twap order price
+	NumOfTicks int `json:"numOfTicks,omitempty"`
+
+	// VolumeCurveInterval and VolumeCurveDays are the klines of the vwap volume curve
+	VolumeCurveInterval types.Interval `json:"volumeCurveInterval,omitempty"`
+	VolumeCurveDays     int            `json:"volumeCurveDays,omitempty"`
+
+	ParticipationRate fixedpoint.Value `json:"participationRate,omitempty"`
+	DisplayQuantity   fixedpoint.Value `json:"displayQuantity,omitempty"`
+	Urgency           float64          `json:"urgency,omitempty"`
+}
+
+func (c *ExecutionConfig) Validate() error {
+	switch c.Algorithm {
+	case ExecutionAlgorithmTWAP:
+		if c.SliceQuantity.Sign() <= 0 {
+			return fmt.Errorf("twap execution requires the slice quantity")
+		}
+
+	case ExecutionAlgorithmVWAP, ExecutionAlgorithmShortfall:
+		if c.Duration <= 0 {
+			return fmt.Errorf("%s execution requires the duration", c.Algorithm)
+		}
+
+	case ExecutionAlgorithmPOV:
+		if c.ParticipationRate.Sign() <= 0 || c.ParticipationRate.Compare(fixedpoint.One) >= 0 {
+			return fmt.Errorf("participation rate should be between 0 and 1")
+		}
+
+	case ExecutionAlgorithmIceberg:
+		if c.DisplayQuantity.Sign() <= 0 {
+			return fmt.Errorf("iceberg execution requires the display quantity")
+		}
+
+	default:
+		return fmt.Errorf("unsupported execution algorithm: %q", c.Algorithm)
+	}
+
+	if c.MaxLag.Sign() < 0 || c.Urgency < 0 {
+		return fmt.Errorf("maxLag and urgency should not be negative")
+	}
+
+	return nil
+}
+
+// NewExecution returns the execution of the quantity, the deadline is the duration from now.
+// The orders are submitted by the order executor, or by the session order executor if it's nil.
+func (c *ExecutionConfig) NewExecution(session *ExchangeSession, orderExecutor OrderExecutor, symbol string, side types.SideType, quantity fixedpoint.Value) (OrderExecution, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var deadlineTime time.Time
+	if c.Duration > 0 {
+		deadlineTime = time.Now().Add(c.Duration.Duration())
+	}
+
+	if c.Algorithm == ExecutionAlgorithmTWAP {
+		return &TwapExecution{
+			Session:        session,
+			Symbol:         symbol,
+			Side:           side,
+			TargetQuantity: quantity,
+			SliceQuantity:  c.SliceQuantity,
+			StopPrice:      c.PriceLimit,
+			NumOfTicks:     c.NumOfTicks,
+			UpdateInterval: c.UpdateInterval.Duration(),
+			DeadlineTime:   deadlineTime,
+			OrderExecutor:  orderExecutor,
+		}, nil
+	}
+
+	var execution interface {
+		OrderExecution
+		base() *AlgoExecution
+	}
+
+	switch c.Algorithm {
+	case ExecutionAlgorithmVWAP:
+		execution = &VWAPExecution{Interval: c.VolumeCurveInterval, NumOfDays: c.VolumeCurveDays}
+	case ExecutionAlgorithmPOV:
+		execution = &POVExecution{ParticipationRate: c.ParticipationRate}
+	case ExecutionAlgorithmIceberg:
+		execution = &IcebergExecution{DisplayQuantity: c.DisplayQuantity}
+	default:
+		execution = &ShortfallExecution{Urgency: c.Urgency}
+	}
+
+	e := execution.base()
+	e.Session = session
+	e.Symbol = symbol
+	e.Side = side
+	e.TargetQuantity = quantity
+	e.MaxSliceQuantity = c.SliceQuantity
+	e.PriceLimit = c.PriceLimit
+	e.MaxLag = c.MaxLag
+	e.DeadlineTime = deadlineTime
+	e.UpdateInterval = c.UpdateInterval.Duration()
+	e.OrderExecutor = orderExecutor
+	return execution, nil
+}
@@ -0,0 +1,32 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// IcebergExecution shows only a part of the target quantity on the order book.
+// The next part is placed when the displayed order is filled, and the order follows the best price within the price
+// limit. Set the price limit to the best price or a worse price to keep the order at a fixed price.
+type IcebergExecution struct {
+	AlgoExecution
+
+	// DisplayQuantity is the quantity of the displayed order
+	DisplayQuantity fixedpoint.Value
+}
+
+func (e *IcebergExecution) Run(ctx context.Context) error {
+	if e.DisplayQuantity.Sign() <= 0 {
+		return fmt.Errorf("display quantity should be positive")
+	}
+
+	e.MaxSliceQuantity = e.DisplayQuantity
+	return e.run(ctx, e)
+}
+
+func (e *IcebergExecution) scheduledQuantity(_ time.Time) fixedpoint.Value {
+	return e.TargetQuantity
+}
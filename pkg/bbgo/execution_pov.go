@@ -0,0 +1,89 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// maxRecentMarketTrades is the number of the recent market trades kept to exclude the own trades reported after them
+const maxRecentMarketTrades = 1000
+
+// POVExecution executes a percentage of the market volume, the market trades since the start of the execution
+// drive the schedule. The own trades are excluded from the market volume by the trade ID, so the execution doesn't
+// drive itself.
+type POVExecution struct {
+	AlgoExecution
+
+	// ParticipationRate is the ratio of the executed quantity to the market volume of the other traders, between 0 and 1
+	ParticipationRate fixedpoint.Value
+
+	marketVolume fixedpoint.Value
+
+	// recentMarketTrades is the quantities of the recent market trades by the trade ID,
+	// the own trades reported after their market trades are subtracted from the market volume by them
+	recentMarketTrades   map[uint64]fixedpoint.Value
+	recentMarketTradeIDs []uint64
+
+	// ownTradeIDs is the IDs of the own trades reported before their market trades
+	ownTradeIDs map[uint64]struct{}
+
+	volumeMu sync.Mutex
+}
+
+func (e *POVExecution) Run(ctx context.Context) error {
+	if e.ParticipationRate.Sign() <= 0 || e.ParticipationRate.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("participation rate should be between 0 and 1")
+	}
+
+	return e.run(ctx, e)
+}
+
+func (e *POVExecution) handleMarketTrade(trade types.Trade) {
+	e.volumeMu.Lock()
+	defer e.volumeMu.Unlock()
+
+	if _, ok := e.ownTradeIDs[trade.ID]; ok {
+		delete(e.ownTradeIDs, trade.ID)
+		return
+	}
+
+	e.marketVolume = e.marketVolume.Add(trade.Quantity)
+
+	if e.recentMarketTrades == nil {
+		e.recentMarketTrades = make(map[uint64]fixedpoint.Value)
+	}
+
+	e.recentMarketTrades[trade.ID] = trade.Quantity
+	e.recentMarketTradeIDs = append(e.recentMarketTradeIDs, trade.ID)
+	if len(e.recentMarketTradeIDs) > maxRecentMarketTrades {
+		delete(e.recentMarketTrades, e.recentMarketTradeIDs[0])
+		e.recentMarketTradeIDs = e.recentMarketTradeIDs[1:]
+	}
+}
+
+func (e *POVExecution) handleOwnTrade(trade types.Trade) {
+	e.volumeMu.Lock()
+	defer e.volumeMu.Unlock()
+
+	if quantity, ok := e.recentMarketTrades[trade.ID]; ok {
+		e.marketVolume = e.marketVolume.Sub(quantity)
+		delete(e.recentMarketTrades, trade.ID)
+		return
+	}
+
+	if e.ownTradeIDs == nil {
+		e.ownTradeIDs = make(map[uint64]struct{})
+	}
+	e.ownTradeIDs[trade.ID] = struct{}{}
+}
+
+func (e *POVExecution) scheduledQuantity(_ time.Time) fixedpoint.Value {
+	e.volumeMu.Lock()
+	defer e.volumeMu.Unlock()
+	return e.marketVolume.Mul(e.ParticipationRate)
+}
@@ -0,0 +1,85 @@
+package bbgo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ExecutionReport is the execution quality of an order execution.
+// The benchmark is the arrival price, the mid price when the order book is first available.
+type ExecutionReport struct {
+	Symbol         string           `json:"symbol"`
+	Side           types.SideType   `json:"side"`
+	TargetQuantity fixedpoint.Value `json:"targetQuantity"`
+
+	ExecutedQuantity    fixedpoint.Value `json:"executedQuantity"`
+	ExecutedQuoteAmount fixedpoint.Value `json:"executedQuoteAmount"`
+	Fees                types.ValueMap   `json:"fees,omitempty"`
+
+	ArrivalPrice fixedpoint.Value `json:"arrivalPrice"`
+	NumOfOrders  int              `json:"numOfOrders"`
+
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+}
+
+func (r *ExecutionReport) addTrade(trade types.Trade) {
+	r.ExecutedQuantity = r.ExecutedQuantity.Add(trade.Quantity)
+	r.ExecutedQuoteAmount = r.ExecutedQuoteAmount.Add(trade.QuoteQuantity)
+
+	if trade.Fee.Sign() > 0 {
+		if r.Fees == nil {
+			r.Fees = make(types.ValueMap)
+		}
+		r.Fees[trade.FeeCurrency] = r.Fees[trade.FeeCurrency].Add(trade.Fee)
+	}
+}
+
+// AveragePrice returns the average price of the trades, without the fees
+func (r *ExecutionReport) AveragePrice() fixedpoint.Value {
+	if r.ExecutedQuantity.IsZero() {
+		return fixedpoint.Zero
+	}
+	return r.ExecutedQuoteAmount.Div(r.ExecutedQuantity)
+}
+
+// Slippage returns the cost of the average price relative to the arrival price, it's positive when the average price
+// is worse than the arrival price, a higher buy price or a lower sell price
+func (r *ExecutionReport) Slippage() fixedpoint.Value {
+	if r.ArrivalPrice.IsZero() || r.ExecutedQuantity.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	slippage := r.AveragePrice().Sub(r.ArrivalPrice).Div(r.ArrivalPrice)
+	if r.Side == types.SideTypeSell {
+		return slippage.Neg()
+	}
+	return slippage
+}
+
+func (r *ExecutionReport) PlainText() string {
+	msg := fmt.Sprintf("%s %s execution: executed %s / %s @ %s, arrival price %s, slippage %.2f bps, %d orders",
+		r.Symbol, r.Side,
+		r.ExecutedQuantity.String(), r.TargetQuantity.String(),
+		r.AveragePrice().String(), r.ArrivalPrice.String(),
+		r.Slippage().Float64()*10000.0, r.NumOfOrders)
+
+	if !r.EndTime.IsZero() {
+		msg += fmt.Sprintf(", duration %s", r.EndTime.Sub(r.StartTime).Round(time.Second))
+	}
+
+	currencies := make([]string, 0, len(r.Fees))
+	for currency := range r.Fees {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		msg += fmt.Sprintf(", fee %s %s", r.Fees[currency].String(), currency)
+	}
+	return msg
+}
@@ -0,0 +1,51 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// ShortfallExecution minimizes the implementation shortfall, the cost against the arrival price, by the optimal
+// trajectory of Almgren and Chriss (2000). The rest quantity decays as
+//
+//	rest(t) = target * sinh(urgency * (1 - t / T)) / sinh(urgency)
+//
+// where T is the duration until the deadline. The urgency is kappa * T of the model, which grows with the risk aversion
+// and the volatility, and shrinks with the market impact. A higher urgency front-loads the execution to reduce
+// the exposure to the price moves, and the trajectory is linear (TWAP) when the urgency is zero.
+type ShortfallExecution struct {
+	AlgoExecution
+
+	Urgency float64
+}
+
+func (e *ShortfallExecution) Run(ctx context.Context) error {
+	if e.DeadlineTime.IsZero() {
+		return fmt.Errorf("implementation shortfall execution requires the deadline")
+	}
+
+	if e.Urgency < 0 {
+		return fmt.Errorf("urgency should not be negative")
+	}
+
+	return e.run(ctx, e)
+}
+
+func (e *ShortfallExecution) scheduledQuantity(now time.Time) fixedpoint.Value {
+	if e.Urgency == 0 {
+		return linearSchedule(e.TargetQuantity, e.startTime, e.DeadlineTime, now)
+	}
+
+	duration := e.DeadlineTime.Sub(e.startTime)
+	if duration <= 0 || !now.Before(e.DeadlineTime) {
+		return e.TargetQuantity
+	}
+
+	progress := math.Max(float64(now.Sub(e.startTime))/float64(duration), 0)
+	rest := math.Sinh(e.Urgency*(1-progress)) / math.Sinh(e.Urgency)
+	return e.TargetQuantity.Mul(fixedpoint.NewFromFloat(1 - rest))
+}
@@ -0,0 +1,210 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	. "github.com/c9s/bbgo/pkg/testing/testhelper"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var executionTestMarket = types.Market{
+	Symbol:          "BTCUSDT",
+	BaseCurrency:    "BTC",
+	QuoteCurrency:   "USDT",
+	PricePrecision:  2,
+	VolumePrecision: 4,
+	TickSize:        Number(0.01),
+	StepSize:        Number(0.0001),
+	MinQuantity:     Number(0.0001),
+	MinNotional:     Number(5),
+}
+
+func TestAlgoExecution_newOrder(t *testing.T) {
+	now := time.Now()
+	bid, ask := Number(29999), Number(30001)
+
+	e := &AlgoExecution{
+		Symbol:         "BTCUSDT",
+		Side:           types.SideTypeBuy,
+		TargetQuantity: Number(1),
+		DeadlineTime:   now.Add(time.Hour),
+		market:         executionTestMarket,
+	}
+
+	t.Run("passive", func(t *testing.T) {
+		order, ok := e.newOrder(now, bid, ask, Number(0.2), Number(0.25))
+		if assert.True(t, ok) {
+			assert.Equal(t, types.OrderTypeLimitMaker, order.Type)
+			assert.Equal(t, "29999", order.Price.String())
+			assert.Equal(t, "0.05", order.Quantity.String())
+		}
+	})
+
+	t.Run("ahead of the schedule", func(t *testing.T) {
+		_, ok := e.newOrder(now, bid, ask, Number(0.3), Number(0.25))
+		assert.False(t, ok)
+	})
+
+	t.Run("behind the schedule", func(t *testing.T) {
+		e.MaxLag = Number(0.1)
+		defer func() { e.MaxLag = fixedpoint.Zero }()
+
+		order, ok := e.newOrder(now, bid, ask, Number(0.2), Number(0.5))
+		if assert.True(t, ok) {
+			assert.Equal(t, types.OrderTypeLimit, order.Type)
+			assert.Equal(t, "30001", order.Price.String())
+			assert.Equal(t, "0.3", order.Quantity.String())
+		}
+
+		e.PriceLimit = Number(30000)
+		defer func() { e.PriceLimit = fixedpoint.Zero }()
+
+		order, _ = e.newOrder(now, bid, ask, Number(0.2), Number(0.5))
+		assert.Equal(t, "30000", order.Price.String())
+	})
+
+	t.Run("max slice quantity", func(t *testing.T) {
+		e.MaxSliceQuantity = Number(0.1)
+		defer func() { e.MaxSliceQuantity = fixedpoint.Zero }()
+
+		order, _ := e.newOrder(now, bid, ask, Number(0), Number(0.5))
+		assert.Equal(t, "0.1", order.Quantity.String())
+
+		// the rest quantity less than the min quantity is merged
+		order, _ = e.newOrder(now, bid, ask, Number(0.89995), Number(1))
+		assert.Equal(t, "0.1", order.Quantity.String())
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		order, ok := e.newOrder(now.Add(2*time.Hour), bid, ask, Number(0.2), Number(0.25))
+		if assert.True(t, ok) {
+			assert.Equal(t, types.OrderTypeMarket, order.Type)
+			assert.Equal(t, "0.8", order.Quantity.String())
+		}
+	})
+
+	t.Run("sell", func(t *testing.T) {
+		e.Side = types.SideTypeSell
+		defer func() { e.Side = types.SideTypeBuy }()
+
+		order, _ := e.newOrder(now, bid, ask, Number(0.2), Number(0.25))
+		assert.Equal(t, "30001", order.Price.String())
+	})
+}
+
+func TestVolumeCurve(t *testing.T) {
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var kLines []types.KLine
+	for d := 0; d < 2; d++ {
+		for h := 0; h < 24; h++ {
+			// the volume of the hours after 12:00 is 3 times of the volume of the hours before
+			volume := 1.0
+			if h >= 12 {
+				volume = 3.0
+			}
+
+			kLines = append(kLines, types.KLine{
+				StartTime: types.Time(day.Add(time.Duration(24*d+h) * time.Hour)),
+				Volume:    fixedpoint.NewFromFloat(volume * float64(d+1)),
+			})
+		}
+	}
+
+	c := newVolumeCurve(kLines, types.Interval1h)
+	assert.InDelta(t, 1.5, c.volumes[0], 1e-9)
+	assert.InDelta(t, 4.5, c.volumes[12], 1e-9)
+
+	start := day.Add(72*time.Hour + 11*time.Hour + 30*time.Minute)
+	assert.InDelta(t, 0.75+4.5, c.volume(start, start.Add(90*time.Minute)), 1e-9)
+
+	e := &VWAPExecution{curve: c}
+	e.TargetQuantity = Number(1)
+	e.startTime = start
+	e.DeadlineTime = start.Add(90 * time.Minute)
+	assert.InDelta(t, 0.75/5.25, e.scheduledQuantity(start.Add(30*time.Minute)).Float64(), 1e-6)
+	assert.Equal(t, "1", e.scheduledQuantity(e.DeadlineTime).String())
+}
+
+func TestShortfallExecution_scheduledQuantity(t *testing.T) {
+	start := time.Now()
+
+	e := &ShortfallExecution{}
+	e.TargetQuantity = Number(1)
+	e.startTime = start
+	e.DeadlineTime = start.Add(time.Hour)
+
+	half := start.Add(30 * time.Minute)
+	assert.InDelta(t, 0.5, e.scheduledQuantity(half).Float64(), 1e-6)
+
+	e.Urgency = 2
+	assert.InDelta(t, 0, e.scheduledQuantity(start).Float64(), 1e-6)
+	assert.InDelta(t, 0.6760, e.scheduledQuantity(half).Float64(), 1e-4, "a higher urgency front-loads the execution")
+	assert.Equal(t, "1", e.scheduledQuantity(e.DeadlineTime).String())
+}
+
+func TestPOVExecution_scheduledQuantity(t *testing.T) {
+	e := &POVExecution{ParticipationRate: Number(0.1)}
+	e.handleMarketTrade(types.Trade{ID: 1, Quantity: Number(2)})
+	e.handleMarketTrade(types.Trade{ID: 2, Quantity: Number(3)})
+	assert.Equal(t, "0.5", e.scheduledQuantity(time.Now()).String())
+
+	// the own trade reported after its market trade
+	e.handleOwnTrade(types.Trade{ID: 2, Quantity: Number(3)})
+	assert.Equal(t, "0.2", e.scheduledQuantity(time.Now()).String())
+
+	// the own trade reported before its market trade
+	e.handleOwnTrade(types.Trade{ID: 3, Quantity: Number(1)})
+	e.handleMarketTrade(types.Trade{ID: 3, Quantity: Number(1)})
+	e.handleMarketTrade(types.Trade{ID: 4, Quantity: Number(5)})
+	assert.Equal(t, "0.7", e.scheduledQuantity(time.Now()).String())
+}
+
+func TestExecutionReport(t *testing.T) {
+	r := &ExecutionReport{
+		Symbol:         "BTCUSDT",
+		Side:           types.SideTypeBuy,
+		TargetQuantity: Number(1),
+		ArrivalPrice:   Number(30000),
+		NumOfOrders:    2,
+	}
+
+	r.addTrade(types.Trade{Quantity: Number(0.5), QuoteQuantity: Number(15000), Fee: Number(0.0005), FeeCurrency: "BTC"})
+	r.addTrade(types.Trade{Quantity: Number(0.5), QuoteQuantity: Number(15030)})
+
+	assert.Equal(t, "30030", r.AveragePrice().String())
+	assert.Equal(t, "0.001", r.Slippage().String())
+	assert.Equal(t, "BTCUSDT BUY execution: executed 1 / 1 @ 30030, arrival price 30000, slippage 10.00 bps, 2 orders, fee 0.0005 BTC", r.PlainText())
+
+	r.Side = types.SideTypeSell
+	assert.Equal(t, "-0.001", r.Slippage().String())
+}
+
+func TestExecutionConfig_NewExecution(t *testing.T) {
+	c := &ExecutionConfig{Algorithm: ExecutionAlgorithmVWAP}
+	_, err := c.NewExecution(nil, nil, "BTCUSDT", types.SideTypeBuy, Number(1))
+	assert.Error(t, err)
+
+	c.Duration = types.Duration(time.Hour)
+	execution, err := c.NewExecution(nil, nil, "BTCUSDT", types.SideTypeBuy, Number(1))
+	if assert.NoError(t, err) && assert.IsType(t, &VWAPExecution{}, execution) {
+		vwap := execution.(*VWAPExecution)
+		assert.Equal(t, "BTCUSDT", vwap.Symbol)
+		assert.Equal(t, "1", vwap.TargetQuantity.String())
+		assert.False(t, vwap.DeadlineTime.IsZero())
+	}
+
+	c = &ExecutionConfig{Algorithm: ExecutionAlgorithmPOV, ParticipationRate: Number(0.1)}
+	execution, err = c.NewExecution(nil, nil, "BTCUSDT", types.SideTypeSell, Number(1))
+	if assert.NoError(t, err) {
+		assert.IsType(t, &POVExecution{}, execution)
+		assert.True(t, execution.(*POVExecution).DeadlineTime.IsZero())
+	}
+
+	c = &ExecutionConfig{Algorithm: "unknown"}
+	assert.Error(t, c.Validate())
+}
@@ -0,0 +1,129 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/batch"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VWAPExecution executes the target quantity by the deadline following the intraday volume curve,
+// which is the average volume of the time of the day of the historical klines.
+type VWAPExecution struct {
+	AlgoExecution
+
+	// Interval is the interval of the buckets of the volume curve, defaults to 5m
+	Interval types.Interval
+
+	// NumOfDays is the number of the days of the historical klines, defaults to 7
+	NumOfDays int
+
+	curve *volumeCurve
+}
+
+func (e *VWAPExecution) Run(ctx context.Context) error {
+	if e.DeadlineTime.IsZero() {
+		return fmt.Errorf("vwap execution requires the deadline")
+	}
+
+	if e.Interval == "" {
+		e.Interval = types.Interval5m
+	}
+
+	if e.NumOfDays == 0 {
+		e.NumOfDays = 7
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(e.NumOfDays) * 24 * time.Hour)
+
+	q := &batch.KLineBatchQuery{Exchange: e.Session.Exchange}
+	kLineC, errC := q.Query(ctx, e.Symbol, e.Interval, startTime, endTime)
+
+	var kLines []types.KLine
+	for k := range kLineC {
+		kLines = append(kLines, k)
+	}
+
+	if err := <-errC; err != nil {
+		return fmt.Errorf("unable to query the %s klines of the volume curve: %w", e.Symbol, err)
+	}
+
+	e.curve = newVolumeCurve(kLines, e.Interval)
+	return e.run(ctx, e)
+}
+
+func (e *VWAPExecution) scheduledQuantity(now time.Time) fixedpoint.Value {
+	total := e.curve.volume(e.startTime, e.DeadlineTime)
+	if total <= 0 {
+		return linearSchedule(e.TargetQuantity, e.startTime, e.DeadlineTime, now)
+	}
+
+	return e.TargetQuantity.Mul(fixedpoint.NewFromFloat(e.curve.volume(e.startTime, now) / total))
+}
+
+// volumeCurve is the average volume of each bucket of the day in UTC
+type volumeCurve struct {
+	interval time.Duration
+	volumes  []float64
+}
+
+func newVolumeCurve(kLines []types.KLine, interval types.Interval) *volumeCurve {
+	c := &volumeCurve{
+		interval: interval.Duration(),
+		volumes:  make([]float64, int(24*time.Hour/interval.Duration())),
+	}
+
+	counts := make([]int, len(c.volumes))
+	for _, k := range kLines {
+		i := c.bucket(k.StartTime.Time())
+		c.volumes[i] += k.Volume.Float64()
+		counts[i]++
+	}
+
+	for i := range c.volumes {
+		if counts[i] > 0 {
+			c.volumes[i] /= float64(counts[i])
+		}
+	}
+	return c
+}
+
+func (c *volumeCurve) bucket(t time.Time) int {
+	t = t.UTC()
+	sinceMidnight := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	return int(sinceMidnight/c.interval) % len(c.volumes)
+}
+
+// volume returns the expected volume between the times, the volume of a partial bucket is pro-rated
+func (c *volumeCurve) volume(from, to time.Time) float64 {
+	var sum float64
+	for t := from; t.Before(to); {
+		end := t.Truncate(c.interval).Add(c.interval)
+		if end.After(to) {
+			end = to
+		}
+
+		sum += c.volumes[c.bucket(t)] * float64(end.Sub(t)) / float64(c.interval)
+		t = end
+	}
+	return sum
+}
+
+// linearSchedule executes the target quantity evenly between the start time and the deadline
+func linearSchedule(target fixedpoint.Value, startTime, deadline, now time.Time) fixedpoint.Value {
+	duration := deadline.Sub(startTime)
+	if duration <= 0 || !now.Before(deadline) {
+		return target
+	}
+
+	elapsed := now.Sub(startTime)
+	if elapsed <= 0 {
+		return fixedpoint.Zero
+	}
+
+	return target.Mul(fixedpoint.NewFromFloat(float64(elapsed) / float64(duration)))
+}
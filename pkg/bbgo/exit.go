@@ -43,6 +43,7 @@ type ExitMethod struct {
 	RoiTakeProfit          *RoiTakeProfit          `json:"roiTakeProfit"`
 	TrailingStop           *TrailingStop2          `json:"trailingStop"`
 	HigherHighLowerLowStop *HigherHighLowerLowStop `json:"higherHighLowerLowStopLoss"`
+	ConditionExit          *ConditionExit          `json:"conditionExit"`
 
 	// Exit methods for short positions
 	// =================================================
@@ -93,6 +94,11 @@ func (e ExitMethod) String() string {
 		buf.WriteString("hhllStop: " + string(b) + ", ")
 	}
 
+	if e.ConditionExit != nil {
+		b, _ := json.Marshal(e.ConditionExit)
+		buf.WriteString("conditionExit: " + string(b) + ", ")
+	}
+
 	return buf.String()
 }
 
@@ -154,4 +160,8 @@ func (m *ExitMethod) Bind(session *ExchangeSession, orderExecutor *GeneralOrderE
 	if m.HigherHighLowerLowStop != nil {
 		m.HigherHighLowerLowStop.Bind(session, orderExecutor)
 	}
+
+	if m.ConditionExit != nil {
+		m.ConditionExit.Bind(session, orderExecutor)
+	}
 }
@@ -0,0 +1,90 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator/v2/expr"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ConditionExit closes the position when the indicator expression is true on the kline close, for example:
+//
+//	conditionExit:
+//	  interval: 15m
+//	  condition: "rsi(close, 14) > 70 and close > boll(close, 20, 2).up"
+type ConditionExit struct {
+	// inherit from the strategy
+	Symbol   string         `json:"symbol"`
+	Interval types.Interval `json:"interval"`
+
+	// Condition is the indicator expression, see the expr package for the syntax
+	Condition string `json:"condition"`
+
+	// Side is the side of the position to close, it could be "long" or "short", both sides are closed if it's empty
+	Side string `json:"side,omitempty"`
+
+	// Ratio is the ratio of the position to close, the whole position is closed by default
+	Ratio fixedpoint.Value `json:"ratio,omitempty"`
+
+	condition     *expr.Expression
+	orderExecutor *GeneralOrderExecutor
+}
+
+func (s *ConditionExit) Validate() error {
+	if s.Side != "" && s.Side != "long" && s.Side != "short" {
+		return fmt.Errorf("invalid condition exit side %q, valid sides: long, short", s.Side)
+	}
+
+	return expr.Check(s.Condition)
+}
+
+func (s *ConditionExit) Subscribe(session *ExchangeSession) {
+	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.Interval})
+}
+
+func (s *ConditionExit) Bind(session *ExchangeSession, orderExecutor *GeneralOrderExecutor) {
+	s.orderExecutor = orderExecutor
+
+	if err := s.Validate(); err != nil {
+		log.WithError(err).Errorf("invalid condition exit, condition exit is disabled")
+		return
+	}
+
+	condition, err := expr.Compile(session.Indicators(s.Symbol).KLines(s.Interval), s.Condition)
+	if err != nil {
+		log.WithError(err).Errorf("unable to compile the exit condition, condition exit is disabled")
+		return
+	}
+
+	s.condition = condition
+
+	ratio := s.Ratio
+	if ratio.IsZero() {
+		ratio = fixedpoint.One
+	}
+
+	position := orderExecutor.Position()
+	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, s.Interval, func(kline types.KLine) {
+		if position.IsClosed() || position.IsDust(kline.Close) || position.IsClosing() {
+			return
+		}
+
+		if (s.Side == "long" && !position.IsLong()) || (s.Side == "short" && !position.IsShort()) {
+			return
+		}
+
+		if !s.condition.Truthy() {
+			return
+		}
+
+		Notify("[conditionExit] %s exit condition %q is triggered at price %f", s.Symbol, s.Condition, kline.Close.Float64())
+
+		if err := orderExecutor.ClosePosition(context.Background(), ratio, "conditionExit"); err != nil {
+			log.WithError(err).Errorf("unable to close position by the exit condition")
+		}
+	}))
+}
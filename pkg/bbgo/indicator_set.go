@@ -1,6 +1,8 @@
 package bbgo
 
 import (
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 
 	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
@@ -18,6 +20,22 @@ type IndicatorSet struct {
 	// caches
 	kLines      map[types.Interval]*indicatorv2.KLineStream
 	closePrices map[types.Interval]*indicatorv2.PriceStream
+	resampled   map[resampleKey]*indicatorv2.KLineStream
+
+	// streams are the first recursive streams created of each key, their states are saved in the indicator snapshot
+	streams map[string]indicatorStream
+
+	// states are the stream states of the indicator snapshot, which are used when the streams are created
+	states map[string]*IndicatorState
+}
+
+type indicatorStream struct {
+	interval types.Interval
+	stream   indicatorv2.StateSnapshotter
+}
+
+type resampleKey struct {
+	base, interval types.Interval
 }
 
 func NewIndicatorSet(symbol string, stream types.Stream, store *MarketDataStore) *IndicatorSet {
@@ -28,9 +46,52 @@ func NewIndicatorSet(symbol string, stream types.Stream, store *MarketDataStore)
 
 		kLines:      make(map[types.Interval]*indicatorv2.KLineStream),
 		closePrices: make(map[types.Interval]*indicatorv2.PriceStream),
+		resampled:   make(map[resampleKey]*indicatorv2.KLineStream),
+		streams:     make(map[string]indicatorStream),
+		states:      make(map[string]*IndicatorState),
 	}
 }
 
+// restoreOrCreate creates a new stream of the key like the other indicator constructors. The stream is restored from
+// the indicator snapshot if its state is found, otherwise the stream is created from the klines of the interval.
+// The streams of the same key are fed by the same klines, so only the first one is kept for the snapshot.
+func restoreOrCreate[T indicatorv2.StateSnapshotter](
+	i *IndicatorSet, key string, interval types.Interval, create func(source *indicatorv2.KLineStream) T,
+) T {
+	kLines := i.KLines(interval)
+	s, ok := restore(i, key, kLines, create)
+	if !ok {
+		s = create(kLines)
+	}
+
+	if _, ok := i.streams[key]; !ok {
+		i.streams[key] = indicatorStream{interval: interval, stream: s}
+	}
+
+	return s
+}
+
+// restore restores the stream of the key from the state of the indicator snapshot. The state is kept for the
+// streams of the same key created later, and it's dropped once it can't be restored.
+func restore[T indicatorv2.StateSnapshotter](
+	i *IndicatorSet, key string, kLines *indicatorv2.KLineStream, create func(source *indicatorv2.KLineStream) T,
+) (T, bool) {
+	state, ok := i.states[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	s, err := indicatorv2.RestoreStream(kLines, state.State, state.Time, create)
+	if err != nil {
+		delete(i.states, key)
+		logrus.WithError(err).Warnf("unable to restore the %s %s indicator, rebuilding it from the klines", i.Symbol, key)
+		return s, false
+	}
+
+	return s, true
+}
+
 func (i *IndicatorSet) KLines(interval types.Interval) *indicatorv2.KLineStream {
 	if kLines, ok := i.kLines[interval]; ok {
 		return kLines
@@ -47,6 +108,26 @@ func (i *IndicatorSet) KLines(interval types.Interval) *indicatorv2.KLineStream
 	return kLines
 }
 
+// Resample returns the KLine stream of the interval aggregated from the klines of the base interval.
+// Unlike KLines(interval), the resampled klines are closed before the handlers of the base interval klines subscribed
+// after this call, which gives the same multi-timeframe alignment in the live trading and the backtest.
+func (i *IndicatorSet) Resample(baseInterval, interval types.Interval) *indicatorv2.KLineStream {
+	key := resampleKey{base: baseInterval, interval: interval}
+	if kLines, ok := i.resampled[key]; ok {
+		return kLines
+	}
+
+	kLines := indicatorv2.Resample(i.KLines(baseInterval), interval)
+	i.resampled[key] = kLines
+	return kLines
+}
+
+// Align aligns the series calculated from the klines resampled by Resample(baseInterval, interval), so that it can be
+// queried as of the time of a base interval kline. The series must be created before calling Align.
+func (i *IndicatorSet) Align(baseInterval, interval types.Interval, series types.Series) *indicatorv2.AlignedSeries {
+	return indicatorv2.Align(i.Resample(baseInterval, interval), series)
+}
+
 func (i *IndicatorSet) OPEN(interval types.Interval) *indicatorv2.PriceStream {
 	return indicatorv2.OpenPrices(i.KLines(interval))
 }
@@ -82,7 +163,10 @@ func (i *IndicatorSet) EMA(iw types.IntervalWindow) *indicatorv2.EWMAStream {
 }
 
 func (i *IndicatorSet) EWMA(iw types.IntervalWindow) *indicatorv2.EWMAStream {
-	return indicatorv2.EWMA2(i.CLOSE(iw.Interval), iw.Window)
+	key := fmt.Sprintf("EWMA:%s:%d", iw.Interval, iw.Window)
+	return restoreOrCreate(i, key, iw.Interval, func(source *indicatorv2.KLineStream) *indicatorv2.EWMAStream {
+		return indicatorv2.EWMA2(indicatorv2.ClosePrices(source), iw.Window)
+	})
 }
 
 func (i *IndicatorSet) STOCH(iw types.IntervalWindow, dPeriod int) *indicatorv2.StochStream {
@@ -94,21 +178,36 @@ func (i *IndicatorSet) BOLL(iw types.IntervalWindow, k float64) *indicatorv2.BOL
 }
 
 func (i *IndicatorSet) Keltner(iw types.IntervalWindow, atrLength int) *indicatorv2.KeltnerStream {
-	return indicatorv2.Keltner(i.KLines(iw.Interval), iw.Window, atrLength)
+	key := fmt.Sprintf("Keltner:%s:%d:%d", iw.Interval, iw.Window, atrLength)
+	return restoreOrCreate(i, key, iw.Interval, func(source *indicatorv2.KLineStream) *indicatorv2.KeltnerStream {
+		return indicatorv2.Keltner(source, iw.Window, atrLength)
+	})
 }
 
 func (i *IndicatorSet) MACD(interval types.Interval, shortWindow, longWindow, signalWindow int) *indicatorv2.MACDStream {
-	return indicatorv2.MACD2(i.CLOSE(interval), shortWindow, longWindow, signalWindow)
+	key := fmt.Sprintf("MACD:%s:%d:%d:%d", interval, shortWindow, longWindow, signalWindow)
+	return restoreOrCreate(i, key, interval, func(source *indicatorv2.KLineStream) *indicatorv2.MACDStream {
+		return indicatorv2.MACD2(indicatorv2.ClosePrices(source), shortWindow, longWindow, signalWindow)
+	})
 }
 
 func (i *IndicatorSet) ATR(interval types.Interval, window int) *indicatorv2.ATRStream {
-	return indicatorv2.ATR2(i.KLines(interval), window)
+	key := fmt.Sprintf("ATR:%s:%d", interval, window)
+	return restoreOrCreate(i, key, interval, func(source *indicatorv2.KLineStream) *indicatorv2.ATRStream {
+		return indicatorv2.ATR2(source, window)
+	})
 }
 
 func (i *IndicatorSet) ATRP(interval types.Interval, window int) *indicatorv2.ATRPStream {
-	return indicatorv2.ATRP2(i.KLines(interval), window)
+	key := fmt.Sprintf("ATRP:%s:%d", interval, window)
+	return restoreOrCreate(i, key, interval, func(source *indicatorv2.KLineStream) *indicatorv2.ATRPStream {
+		return indicatorv2.ATRP2(source, window)
+	})
 }
 
 func (i *IndicatorSet) ADX(interval types.Interval, window int) *indicatorv2.ADXStream {
-	return indicatorv2.ADX(i.KLines(interval), window)
+	key := fmt.Sprintf("ADX:%s:%d", interval, window)
+	return restoreOrCreate(i, key, interval, func(source *indicatorv2.KLineStream) *indicatorv2.ADXStream {
+		return indicatorv2.ADX(source, window)
+	})
 }
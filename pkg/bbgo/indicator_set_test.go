@@ -2,9 +2,11 @@ package bbgo
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -56,3 +58,49 @@ func TestIndicatorSet_EWMA(t *testing.T) {
 	emaLast := ema1m.Last(0)
 	assert.InDelta(t, 19424.224853515625, emaLast, 0.0000001)
 }
+
+func TestIndicatorSet_newInstances(t *testing.T) {
+	indicatorSet := newTestIndicatorSet()
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 7}
+
+	// the indicator constructors return new instances, only the first one of the key is kept for the snapshot
+	ema1 := indicatorSet.EWMA(iw)
+	ema2 := indicatorSet.EWMA(iw)
+	assert.NotSame(t, ema1, ema2)
+	assert.Equal(t, ema1.Last(0), ema2.Last(0))
+	assert.Same(t, ema1, indicatorSet.streams["EWMA:1m:7"].stream)
+
+	assert.NotSame(t, indicatorSet.ATR(types.Interval1m, 7), indicatorSet.ATR(types.Interval1m, 7))
+}
+
+func TestIndicatorSet_Align(t *testing.T) {
+	symbol := "BTCUSDT"
+	stream := types.NewStandardStream()
+	indicatorSet := NewIndicatorSet(symbol, &stream, NewMarketDataStore(symbol))
+
+	// the series is aligned with the resampled klines, no native 5m kline is received
+	kLines5m := indicatorSet.Resample(types.Interval1m, types.Interval5m)
+	close5m := indicatorSet.Align(types.Interval1m, types.Interval5m, indicatorv2.ClosePrices(kLines5m))
+
+	var values []float64
+	indicatorSet.KLines(types.Interval1m).OnUpdate(func(k types.KLine) {
+		if v, ok := close5m.AsOf(k.EndTime.Time(), 0); ok {
+			values = append(values, v)
+		}
+	})
+
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		stream.EmitKLineClosed(types.KLine{
+			Symbol:    symbol,
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Minute)),
+			EndTime:   types.Time(startTime.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			Close:     number(float64(i + 1)),
+			Closed:    true,
+		})
+	}
+
+	// the 5m kline closed by the 5th 1m kline is returned from then on
+	assert.Equal(t, []float64{5, 5, 5}, values)
+}
@@ -0,0 +1,250 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const indicatorSnapshotStoreID = "indicators"
+
+// IndicatorSnapshotConfig enables the indicator snapshots, which warm up the indicators from the persisted
+// klines and stream states on restart, so that only the klines after the last snapshot need to be queried from
+// the exchange.
+type IndicatorSnapshotConfig struct {
+	// MaxKLines is the max number of klines of each interval in a snapshot, defaults to KLinePreloadLimit
+	MaxKLines int `json:"maxKLines,omitempty"`
+}
+
+func (c *IndicatorSnapshotConfig) maxKLines() int {
+	if c.MaxKLines > 0 {
+		return c.MaxKLines
+	}
+	return int(KLinePreloadLimit)
+}
+
+// IndicatorSnapshot is the serializable state of the indicators of a symbol.
+//
+// The kline windows of the market data store are saved, so that the window based indicators, e.g., SMA and BOLL,
+// are rebuilt from them. The recursive streams of the IndicatorSet, e.g., EWMA and ATR, depend on the whole kline
+// history, so their states are saved as well and they are resumed from the states.
+//
+// The states of the v1 indicators of the StandardIndicatorSet are not saved, they are only rebuilt from the snapshot
+// klines. So the recursive v1 indicators, e.g., EWMA, ATR and RSI, may differ from the ones fed by the full kline
+// history until their warm-up periods pass, use the IndicatorSet streams if they should be resumed exactly.
+type IndicatorSnapshot struct {
+	Symbol string                           `json:"symbol"`
+	KLines map[types.Interval][]types.KLine `json:"klines"`
+	States map[string]*IndicatorState       `json:"states,omitempty"`
+	Time   time.Time                        `json:"time"`
+}
+
+// IndicatorState is the state of an indicator stream after the kline closed at the time
+type IndicatorState struct {
+	Interval types.Interval           `json:"interval"`
+	Time     time.Time                `json:"time"`
+	State    *indicatorv2.StreamState `json:"state"`
+}
+
+// LastKLine returns the last kline of the given interval in the snapshot
+func (s *IndicatorSnapshot) LastKLine(interval types.Interval) (types.KLine, bool) {
+	kLines, ok := s.KLines[interval]
+	if !ok || len(kLines) == 0 {
+		return types.KLine{}, false
+	}
+
+	return kLines[len(kLines)-1], true
+}
+
+// Snapshot returns the snapshot of the last maxKLines klines of each interval and the states of the recursive streams
+func (i *IndicatorSet) Snapshot(maxKLines int) *IndicatorSnapshot {
+	snapshot := &IndicatorSnapshot{
+		Symbol: i.Symbol,
+		KLines: make(map[types.Interval][]types.KLine),
+		States: make(map[string]*IndicatorState),
+		Time:   time.Now(),
+	}
+
+	for key, s := range i.streams {
+		lastKLine := i.KLines(s.interval).Last(0)
+		if lastKLine == nil {
+			continue
+		}
+
+		snapshot.States[key] = &IndicatorState{
+			Interval: s.interval,
+			Time:     lastKLine.EndTime.Time(),
+			State:    s.stream.SnapshotState(),
+		}
+	}
+
+	if i.store == nil {
+		return snapshot
+	}
+
+	for interval, window := range i.store.KLineWindows {
+		if window == nil || len(*window) == 0 {
+			continue
+		}
+
+		tail := window.Tail(maxKLines)
+		kLines := make([]types.KLine, len(tail))
+		copy(kLines, tail)
+		snapshot.KLines[interval] = kLines
+	}
+
+	return snapshot
+}
+
+// RestoreStates sets the stream states of the snapshot, the recursive streams are resumed from the states when they
+// are created. The state is dropped if the kline it's saved after is not in the kline history.
+func (i *IndicatorSet) RestoreStates(states map[string]*IndicatorState) {
+	for key, state := range states {
+		if state == nil || state.State == nil {
+			continue
+		}
+
+		i.states[key] = state
+	}
+}
+
+// SaveSnapshot persists the snapshot of the indicator set through the given persistence service
+func (i *IndicatorSet) SaveSnapshot(ps service.PersistenceService, sessionName string, maxKLines int) error {
+	store := ps.NewStore(indicatorSnapshotStoreID, sessionName, i.Symbol)
+	return store.Save(i.Snapshot(maxKLines))
+}
+
+// LoadIndicatorSnapshot loads the persisted indicator snapshot of the symbol,
+// it returns nil without error if there is no snapshot.
+func LoadIndicatorSnapshot(ps service.PersistenceService, sessionName, symbol string) (*IndicatorSnapshot, error) {
+	store := ps.NewStore(indicatorSnapshotStoreID, sessionName, symbol)
+
+	var snapshot *IndicatorSnapshot
+	if err := store.Load(&snapshot); err != nil {
+		if errors.Is(err, service.ErrPersistenceNotExists) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// SaveIndicatorSnapshots saves the indicator snapshots of all the symbols of the session
+func (session *ExchangeSession) SaveIndicatorSnapshots(ps service.PersistenceService, maxKLines int) error {
+	for symbol := range session.marketDataStores {
+		if err := session.Indicators(symbol).SaveSnapshot(ps, session.Name, maxKLines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreKLineSnapshot adds the snapshot klines of the interval to the market data store and queries the klines
+// after the snapshot until the end time. It returns false if the snapshot is missing or too old to be used.
+func (session *ExchangeSession) restoreKLineSnapshot(
+	ctx context.Context, store *MarketDataStore, snapshot *IndicatorSnapshot, interval types.Interval, endTime time.Time,
+	maxKLines int,
+) (bool, error) {
+	lastKLine, ok := snapshot.LastKLine(interval)
+	if !ok {
+		return false, nil
+	}
+
+	// the gap is larger than the snapshot window, the full preload costs less queries
+	gapStartTime := lastKLine.StartTime.Time().Add(interval.Duration())
+	if endTime.Sub(gapStartTime) > time.Duration(maxKLines)*interval.Duration() {
+		return false, nil
+	}
+
+	gapKLines, err := session.queryKLinesBetween(ctx, snapshot.Symbol, interval, gapStartTime, endTime)
+	if err != nil {
+		return false, err
+	}
+
+	if session.recorder != nil && len(gapKLines) > 0 {
+		if err := session.recorder.RecordKLineHistory(snapshot.Symbol, interval, gapKLines); err != nil {
+			return false, err
+		}
+	}
+
+	log.Infof("restored %d %s %s klines from the indicator snapshot, %d klines queried",
+		len(snapshot.KLines[interval]), snapshot.Symbol, interval, len(gapKLines))
+
+	for _, k := range snapshot.KLines[interval] {
+		store.AddKLine(k)
+	}
+
+	for _, k := range gapKLines {
+		store.AddKLine(k)
+	}
+
+	return true, nil
+}
+
+// queryKLinesBetween queries the closed klines started from the start time until the end time
+func (session *ExchangeSession) queryKLinesBetween(
+	ctx context.Context, symbol string, interval types.Interval, startTime, endTime time.Time,
+) ([]types.KLine, error) {
+	var allKLines []types.KLine
+	for startTime.Before(endTime) {
+		s := startTime
+		kLines, err := session.QueryKLines(ctx, symbol, interval, types.KLineQueryOptions{
+			StartTime: &s,
+			EndTime:   &endTime,
+			Limit:     1000,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, k := range kLines {
+			if k.StartTime.Before(startTime) || k.EndTime.After(endTime) {
+				continue
+			}
+
+			allKLines = append(allKLines, k)
+			startTime = k.StartTime.Time().Add(interval.Duration())
+		}
+
+		if len(kLines) < 1000 || !s.Before(startTime) {
+			break
+		}
+	}
+
+	return allKLines, nil
+}
+
+// SaveIndicatorSnapshots saves the indicator snapshots of all the sessions if the indicator snapshot is enabled.
+// It's called on shutdown, after the strategies are stopped.
+func (environ *Environment) SaveIndicatorSnapshots(ctx context.Context) error {
+	config := environ.indicatorSnapshotConfig()
+	if config == nil {
+		return nil
+	}
+
+	ps := GetIsolationFromContext(ctx).persistenceServiceFacade.Get()
+	for _, session := range environ.sessions {
+		if err := session.SaveIndicatorSnapshots(ps, config.maxKLines()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (environ *Environment) indicatorSnapshotConfig() *IndicatorSnapshotConfig {
+	if environ.BacktestService != nil || environ.environmentConfig == nil {
+		return nil
+	}
+
+	return environ.environmentConfig.IndicatorSnapshot
+}
@@ -0,0 +1,88 @@
+package bbgo
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestIndicatorSnapshot(t *testing.T) {
+	ctx := context.Background()
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ex := newTestIntervalExchange(startTime, 300)
+
+	// the previous run stopped after the first 200 klines
+	store := NewMarketDataStore("BTCUSDT")
+	for _, k := range ex.kLines[:200] {
+		store.AddKLine(k)
+	}
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 50}
+
+	ps := service.NewMemoryService()
+	set := NewIndicatorSet("BTCUSDT", &types.StandardStream{}, store)
+	set.EWMA(iw)
+	require.NoError(t, set.SaveSnapshot(ps, "max", 100))
+
+	snapshot, err := LoadIndicatorSnapshot(ps, "max", "BTCUSDT")
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	require.Len(t, snapshot.KLines[types.Interval1m], 100)
+	require.Contains(t, snapshot.States, "EWMA:1m:50")
+	assert.Equal(t, ex.kLines[199].EndTime.Time(), snapshot.States["EWMA:1m:50"].Time)
+
+	notExists, err := LoadIndicatorSnapshot(ps, "max", "ETHUSDT")
+	assert.NoError(t, err)
+	assert.Nil(t, notExists)
+
+	// restart at the end of the 300th kline, only the gap klines are queried
+	session := &ExchangeSession{Name: "max", ExchangeName: types.ExchangeMax, Exchange: ex}
+	restoredStore := NewMarketDataStore("BTCUSDT")
+	endTime := startTime.Add(300 * time.Minute)
+	restored, err := session.restoreKLineSnapshot(ctx, restoredStore, snapshot, types.Interval1m, endTime, 100)
+	require.NoError(t, err)
+	require.True(t, restored)
+	assert.Equal(t, 1, ex.queries)
+
+	window, ok := restoredStore.KLinesOfInterval(types.Interval1m)
+	require.True(t, ok)
+	require.Len(t, *window, 200)
+	assert.Equal(t, ex.kLines[100:], []types.KLine(*window))
+
+	// the EWMA is resumed from the state instead of the 200 klines of the store
+	fullStore := NewMarketDataStore("BTCUSDT")
+	for _, k := range ex.kLines {
+		fullStore.AddKLine(k)
+	}
+	expected := NewIndicatorSet("BTCUSDT", &types.StandardStream{}, fullStore).EWMA(iw)
+
+	restoredSet := NewIndicatorSet("BTCUSDT", &types.StandardStream{}, restoredStore)
+	restoredSet.RestoreStates(snapshot.States)
+	restoredEWMA := restoredSet.EWMA(iw)
+	assert.InDelta(t, expected.Last(0), restoredEWMA.Last(0), 1e-9)
+
+	// the streams of the same key are separate instances resumed from the same state
+	restoredEWMA2 := restoredSet.EWMA(iw)
+	assert.NotSame(t, restoredEWMA, restoredEWMA2)
+	assert.InDelta(t, expected.Last(0), restoredEWMA2.Last(0), 1e-9)
+
+	rebuilt := NewIndicatorSet("BTCUSDT", &types.StandardStream{}, restoredStore).EWMA(iw)
+	assert.Greater(t, math.Abs(expected.Last(0)-rebuilt.Last(0)), 1e-6)
+
+	// the gap is larger than the snapshot window
+	restored, err = session.restoreKLineSnapshot(ctx, NewMarketDataStore("BTCUSDT"), snapshot, types.Interval1m, endTime.Add(time.Minute), 100)
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	// the interval is not in the snapshot
+	restored, err = session.restoreKLineSnapshot(ctx, NewMarketDataStore("BTCUSDT"), snapshot, types.Interval5m, endTime, 100)
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/dynamic"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
@@ -94,6 +96,28 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
+	i.PrivateCommand("/streams", "Show Market Data Stream Health", func(reply interact.Reply) error {
+		watchdog := it.environment.StreamWatchdog()
+		if watchdog == nil {
+			reply.Message("Stream watchdog is not enabled")
+			return nil
+		}
+
+		statuses := watchdog.Statuses()
+		if len(statuses) == 0 {
+			reply.Message("No market data subscriptions")
+			return nil
+		}
+
+		message := "Market data streams:\n"
+		for _, status := range statuses {
+			message += "- " + status.String() + "\n"
+		}
+
+		reply.Message(message)
+		return nil
+	})
+
 	i.PrivateCommand("/balances", "Show balances", func(reply interact.Reply) error {
 		reply.Message("Please select an exchange session")
 		for name := range it.environment.Sessions() {
@@ -436,6 +460,58 @@ func (it *CoreInteraction) Commands(i *interact.Interact) {
 		return nil
 	})
 
+	i.PrivateCommand("/execute", "Execute Order: /execute SESSION SYMBOL SIDE QUANTITY ALGORITHM PARAMETER", func(sessionName, symbol, sideStr, quantityStr, algorithm, parameter string, reply interact.Reply) error {
+		session, ok := it.environment.Session(sessionName)
+		if !ok {
+			reply.Message(fmt.Sprintf("Session %s not found", sessionName))
+			return fmt.Errorf("session %s not found", sessionName)
+		}
+
+		side, err := types.StrToSideType(sideStr)
+		if err != nil {
+			reply.Message(err.Error())
+			return err
+		}
+
+		quantity, err := fixedpoint.NewFromString(quantityStr)
+		if err != nil {
+			reply.Message(fmt.Sprintf("%q is not a valid quantity", quantityStr))
+			return err
+		}
+
+		config, err := newExecutionConfig(ExecutionAlgorithm(algorithm), parameter)
+		if err != nil {
+			reply.Message(err.Error())
+			return err
+		}
+
+		execution, err := config.NewExecution(session, nil, strings.ToUpper(symbol), side, quantity)
+		if err != nil {
+			reply.Message(err.Error())
+			return err
+		}
+
+		// the execution is shut down with the other strategies
+		ctx := context.Background()
+		if err := execution.Run(ctx); err != nil {
+			reply.Message(fmt.Sprintf("Failed to start the execution, %s", err.Error()))
+			return err
+		}
+
+		OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+			defer wg.Done()
+			execution.Shutdown(ctx)
+		})
+
+		go func() {
+			<-execution.Done()
+			Notify(execution.Report().PlainText())
+		}()
+
+		reply.Message(fmt.Sprintf("The %s execution of %s %s %s is started", algorithm, symbol, side, quantity.String()))
+		return nil
+	})
+
 	// Position updater
 	i.PrivateCommand("/modifyposition", "Modify Strategy Position", func(reply interact.Reply) error {
 		// it.trader.exchangeStrategies
@@ -633,3 +709,37 @@ func generateStrategyButtonsForm(strategies map[string]SingleExchangeStrategy) [
 
 	return buttonsForm
 }
+
+// newExecutionConfig returns the execution config with the required parameter of the algorithm: the slice quantity of
+// twap, the duration of vwap and is, the participation rate of pov, or the display quantity of iceberg
+func newExecutionConfig(algorithm ExecutionAlgorithm, parameter string) (*ExecutionConfig, error) {
+	config := &ExecutionConfig{Algorithm: algorithm}
+
+	switch algorithm {
+	case ExecutionAlgorithmVWAP, ExecutionAlgorithmShortfall:
+		duration, err := time.ParseDuration(parameter)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid duration", parameter)
+		}
+
+		config.Duration = types.Duration(duration)
+		config.Urgency = 1.0
+
+	case ExecutionAlgorithmTWAP, ExecutionAlgorithmPOV, ExecutionAlgorithmIceberg:
+		value, err := fixedpoint.NewFromString(parameter)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid number", parameter)
+		}
+
+		switch algorithm {
+		case ExecutionAlgorithmTWAP:
+			config.SliceQuantity = value
+		case ExecutionAlgorithmPOV:
+			config.ParticipationRate = value
+		case ExecutionAlgorithmIceberg:
+			config.DisplayQuantity = value
+		}
+	}
+
+	return config, config.Validate()
+}
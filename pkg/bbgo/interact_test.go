@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -56,3 +57,21 @@ func Test_testInterface(t *testing.T) {
 	ok := testInterface(s, (*PositionCloser)(nil))
 	assert.True(t, ok)
 }
+
+func Test_newExecutionConfig(t *testing.T) {
+	config, err := newExecutionConfig(ExecutionAlgorithmVWAP, "2h")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2*time.Hour, config.Duration.Duration())
+	}
+
+	config, err = newExecutionConfig(ExecutionAlgorithmPOV, "10%")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.1", config.ParticipationRate.String())
+	}
+
+	_, err = newExecutionConfig(ExecutionAlgorithmIceberg, "0")
+	assert.Error(t, err)
+
+	_, err = newExecutionConfig("unknown", "1")
+	assert.Error(t, err)
+}
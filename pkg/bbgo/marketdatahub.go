@@ -0,0 +1,643 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// defaultMaxSubscriptionsPerConnection is the subscription cap of a websocket connection of the exchanges,
+// the exchanges not listed here are not sharded.
+var defaultMaxSubscriptionsPerConnection = map[types.ExchangeName]int{
+	// a single binance connection can listen to a maximum of 1024 streams
+	types.ExchangeBinance: 1024,
+
+	// a single kucoin connection can subscribe up to 300 topics
+	types.ExchangeKucoin: 300,
+}
+
+type MarketDataHubConfig struct {
+	// MaxSubscriptionsPerConnection overrides the subscription cap of a connection by the exchange name,
+	// zero means no cap
+	MaxSubscriptionsPerConnection map[string]int `json:"maxSubscriptionsPerConnection,omitempty"`
+}
+
+func (c *MarketDataHubConfig) maxSubscriptionsPerConnection(exchangeName types.ExchangeName) int {
+	if c != nil {
+		if n, ok := c.MaxSubscriptionsPerConnection[exchangeName.String()]; ok {
+			return n
+		}
+	}
+
+	return defaultMaxSubscriptionsPerConnection[exchangeName]
+}
+
+// MarketDataHub shares the public market data connections between the sessions of the same exchange.
+// The subscriptions of the sessions are deduplicated and sharded across the connections by the subscription cap
+// of the exchange, and the events are fanned out to the session streams that subscribe them.
+// The subscriptions of the same channel and symbol with different options are subscribed by different connections,
+// and the sessions joining a connected book subscription late receive the current book as the snapshot.
+type MarketDataHub struct {
+	config *MarketDataHubConfig
+
+	mu     sync.Mutex
+	groups map[string]*marketDataGroup
+}
+
+func NewMarketDataHub(config *MarketDataHubConfig) *MarketDataHub {
+	return &MarketDataHub{
+		config: config,
+		groups: make(map[string]*marketDataGroup),
+	}
+}
+
+// marketDataHubKey groups the sessions receiving the same public market data,
+// the futures sessions are separated from the spot and margin sessions.
+func marketDataHubKey(session *ExchangeSession) string {
+	key := session.ExchangeName.String()
+	if session.IsolatedFutures {
+		key += ":isolated_futures:" + session.IsolatedFuturesSymbol
+	} else if session.Futures {
+		key += ":futures"
+	}
+	return key
+}
+
+// NewSessionStream creates the hub stream of the session, the connections are created by the session exchange
+func (h *MarketDataHub) NewSessionStream(session *ExchangeSession) *MarketDataHubStream {
+	return h.NewStream(marketDataHubKey(session), session.ExchangeName, session.Exchange.NewStream)
+}
+
+// NewStream creates a hub stream of the group key, the connections of the group are created by the factory
+func (h *MarketDataHub) NewStream(key string, exchangeName types.ExchangeName, factory func() types.Stream) *MarketDataHubStream {
+	h.mu.Lock()
+	group, ok := h.groups[key]
+	if !ok {
+		group = &marketDataGroup{
+			key:              key,
+			exchangeName:     exchangeName,
+			factory:          factory,
+			maxSubscriptions: h.config.maxSubscriptionsPerConnection(exchangeName),
+			subscriptions:    make(map[string]types.Subscription),
+			topicShards:      make(map[string]*marketDataShard),
+		}
+		h.groups[key] = group
+	}
+	h.mu.Unlock()
+
+	standardStream := types.NewStandardStream()
+	stream := &MarketDataHubStream{
+		StandardStream: &standardStream,
+		group:          group,
+		subscribed:     make(map[string]struct{}),
+	}
+	stream.SetPublicOnly()
+
+	group.mu.Lock()
+	group.streams = append(group.streams, stream)
+	group.mu.Unlock()
+	return stream
+}
+
+// NumOfConnections returns the number of the connections of all the groups
+func (h *MarketDataHub) NumOfConnections() (n int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, group := range h.groups {
+		group.mu.Lock()
+		n += len(group.shards)
+		group.mu.Unlock()
+	}
+	return n
+}
+
+// hubSubscriptionKey is the key for deduplicating the subscriptions, the subscriptions of the same channel and symbol
+// share one topic except the klines of different intervals.
+func hubSubscriptionKey(channel types.Channel, symbol string, interval types.Interval) string {
+	if channel == types.KLineChannel {
+		return fmt.Sprintf("%s:%s:%s", channel, symbol, interval)
+	}
+	return fmt.Sprintf("%s:%s", channel, symbol)
+}
+
+// hubTopicKey is the key of a topic of the connections, the subscriptions of the same channel and symbol
+// with different options, e.g., the book depth, are different topics.
+func hubTopicKey(sub types.Subscription) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s", sub.Channel, sub.Symbol, sub.Options.Interval, sub.Options.Depth, sub.Options.Speed)
+}
+
+// reconnectRequestWindow is the window of the reconnect requests of the hub streams sharing a connection,
+// the connection is only reconnected when all of its hub streams request it within the window
+const reconnectRequestWindow = time.Minute
+
+type marketDataShard struct {
+	stream        types.Stream
+	subscriptions []types.Subscription
+	connected     bool
+
+	// reconnectRequests is the time of the last reconnect request of each hub stream, it's guarded by the group lock
+	reconnectRequests map[*MarketDataHubStream]time.Time
+
+	// topics maps the subscription keys to the topic keys of the shard, the events don't carry the subscribe options,
+	// so a shard has at most one topic of the same channel and symbol
+	topics map[string]string
+
+	// bookMu serializes the book events of the shard and the snapshots sent to the late subscribers
+	bookMu sync.Mutex
+	books  map[string]*types.SliceOrderBook
+}
+
+// subscribedBy returns true if the hub stream subscribes any topic of the shard
+func (shard *marketDataShard) subscribedBy(stream *MarketDataHubStream) bool {
+	for _, sub := range shard.subscriptions {
+		if stream.isSubscribed(hubTopicKey(sub)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (shard *marketDataShard) loadBook(book types.SliceOrderBook) {
+	b, ok := shard.books[book.Symbol]
+	if !ok {
+		b = types.NewSliceOrderBook(book.Symbol)
+		shard.books[book.Symbol] = b
+	}
+
+	b.Load(book)
+	b.Time = book.Time
+}
+
+func (shard *marketDataShard) updateBook(book types.SliceOrderBook) {
+	// the updates before the first snapshot are not cached, the late subscribers wait for the snapshot instead
+	if b, ok := shard.books[book.Symbol]; ok {
+		b.Update(book)
+		b.Time = book.Time
+	}
+}
+
+// sendBookSnapshot sends the current book to the hub stream joining the shard late, the exchange only sends
+// the snapshot on connect. The book events of the shard are blocked until the snapshot is sent,
+// so the later updates are applied on top of it.
+func (shard *marketDataShard) sendBookSnapshot(stream *MarketDataHubStream, symbol string) {
+	shard.bookMu.Lock()
+	defer shard.bookMu.Unlock()
+
+	b, ok := shard.books[symbol]
+	if !ok {
+		return
+	}
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: b.Symbol,
+		Time:   b.Time,
+		Bids:   b.Bids.Copy(),
+		Asks:   b.Asks.Copy(),
+	})
+}
+
+type marketDataGroup struct {
+	key              string
+	exchangeName     types.ExchangeName
+	factory          func() types.Stream
+	maxSubscriptions int
+
+	mu            sync.Mutex
+	streams       []*MarketDataHubStream
+	shards        []*marketDataShard
+	subscriptions map[string]types.Subscription
+
+	// topicShards maps the topic keys to the shards which the topics are allocated to
+	topicShards map[string]*marketDataShard
+
+	// pending is the subscriptions not assigned to any shard yet
+	pending []types.Subscription
+
+	// ctx is the context of the first connect, it's used for connecting the shards of the later subscriptions
+	ctx     context.Context
+	started bool
+}
+
+// addSubscriptions registers the deduplicated subscriptions of the stream, the subscriptions with the different
+// options are registered as different topics.
+func (g *marketDataGroup) addSubscriptions(subs []types.Subscription) {
+	for _, sub := range subs {
+		key := hubTopicKey(sub)
+		if _, ok := g.subscriptions[key]; ok {
+			continue
+		}
+
+		g.subscriptions[key] = sub
+		g.pending = append(g.pending, sub)
+	}
+
+	metricsMarketDataHubSubscriptions.With(prometheus.Labels{"exchange": g.exchangeName.String(), "group": g.key}).
+		Set(float64(len(g.subscriptions)))
+}
+
+// connect is called by the hub streams of the group, the shards are connected once all the hub streams of the group
+// are connected, so that the subscriptions of all the sessions are packed into the least connections.
+// The subscriptions added after that are connected with new shards.
+func (g *marketDataGroup) connect(ctx context.Context, stream *MarketDataHubStream) error {
+	g.mu.Lock()
+	g.addSubscriptions(stream.GetSubscriptions())
+	stream.connected = true
+	if g.ctx == nil {
+		g.ctx = ctx
+	}
+
+	if !g.started {
+		for _, s := range g.streams {
+			if !s.connected {
+				g.mu.Unlock()
+				return nil
+			}
+		}
+		g.started = true
+	}
+
+	// the books of the existing shards are sent to the stream joining late
+	var bookShards []*marketDataShard
+	var bookSymbols []string
+	for _, sub := range stream.GetSubscriptions() {
+		if sub.Channel != types.BookChannel {
+			continue
+		}
+
+		if shard, ok := g.topicShards[hubTopicKey(sub)]; ok {
+			bookShards = append(bookShards, shard)
+			bookSymbols = append(bookSymbols, sub.Symbol)
+		}
+	}
+
+	shards := g.allocateShards()
+	g.mu.Unlock()
+
+	for i, shard := range bookShards {
+		shard.sendBookSnapshot(stream, bookSymbols[i])
+	}
+
+	for _, shard := range shards {
+		log.Infof("[MarketDataHub] connecting %s market data connection with %d subscriptions", g.key, len(shard.subscriptions))
+
+		if err := shard.stream.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allocateShards packs the pending subscriptions into the new shards, it must be called with the lock.
+// The topics of the same channel and symbol with different options are allocated to different shards.
+func (g *marketDataGroup) allocateShards() (shards []*marketDataShard) {
+	for len(g.pending) > 0 {
+		shard := &marketDataShard{
+			stream:            g.factory(),
+			topics:            make(map[string]string),
+			books:             make(map[string]*types.SliceOrderBook),
+			reconnectRequests: make(map[*MarketDataHubStream]time.Time),
+		}
+
+		var rest []types.Subscription
+		for _, sub := range g.pending {
+			key := hubSubscriptionKey(sub.Channel, sub.Symbol, sub.Options.Interval)
+			if _, ok := shard.topics[key]; ok || (g.maxSubscriptions > 0 && len(shard.subscriptions) >= g.maxSubscriptions) {
+				rest = append(rest, sub)
+				continue
+			}
+
+			topic := hubTopicKey(sub)
+			shard.topics[key] = topic
+			shard.subscriptions = append(shard.subscriptions, sub)
+			g.topicShards[topic] = shard
+		}
+		g.pending = rest
+
+		shard.stream.SetPublicOnly()
+		for _, sub := range shard.subscriptions {
+			shard.stream.Subscribe(sub.Channel, sub.Symbol, sub.Options)
+		}
+
+		g.bindShard(shard)
+		g.shards = append(g.shards, shard)
+		shards = append(shards, shard)
+	}
+
+	return shards
+}
+
+func (g *marketDataGroup) bindShard(shard *marketDataShard) {
+	labels := prometheus.Labels{"exchange": g.exchangeName.String(), "group": g.key}
+	stream := shard.stream
+
+	stream.OnConnect(func() {
+		g.mu.Lock()
+		shard.reconnectRequests = make(map[*MarketDataHubStream]time.Time)
+		if !shard.connected {
+			shard.connected = true
+			metricsMarketDataHubConnections.With(labels).Inc()
+		}
+		g.mu.Unlock()
+
+		g.dispatch(shard, func(s *MarketDataHubStream) { s.EmitConnect() })
+	})
+
+	stream.OnDisconnect(func() {
+		g.mu.Lock()
+		if shard.connected {
+			shard.connected = false
+			metricsMarketDataHubConnections.With(labels).Dec()
+		}
+		g.mu.Unlock()
+
+		g.dispatch(shard, func(s *MarketDataHubStream) { s.EmitDisconnect() })
+	})
+
+	stream.OnStart(func() {
+		g.dispatch(shard, func(s *MarketDataHubStream) { s.EmitStart() })
+	})
+
+	stream.OnKLine(func(k types.KLine) {
+		g.dispatchEvent(shard, types.KLineChannel, k.Symbol, k.Interval, func(s *MarketDataHubStream) { s.EmitKLine(k) })
+	})
+
+	stream.OnKLineClosed(func(k types.KLine) {
+		observeMarketDataHubLatency(g, "kline", k.EndTime.Time())
+		g.dispatchEvent(shard, types.KLineChannel, k.Symbol, k.Interval, func(s *MarketDataHubStream) { s.EmitKLineClosed(k) })
+	})
+
+	stream.OnBookSnapshot(func(book types.SliceOrderBook) {
+		observeMarketDataHubLatency(g, "book", book.Time)
+
+		shard.bookMu.Lock()
+		defer shard.bookMu.Unlock()
+
+		shard.loadBook(book)
+		g.dispatchEvent(shard, types.BookChannel, book.Symbol, "", func(s *MarketDataHubStream) { s.EmitBookSnapshot(book) })
+	})
+
+	stream.OnBookUpdate(func(book types.SliceOrderBook) {
+		observeMarketDataHubLatency(g, "book", book.Time)
+
+		shard.bookMu.Lock()
+		defer shard.bookMu.Unlock()
+
+		shard.updateBook(book)
+		g.dispatchEvent(shard, types.BookChannel, book.Symbol, "", func(s *MarketDataHubStream) { s.EmitBookUpdate(book) })
+	})
+
+	stream.OnBookTickerUpdate(func(bookTicker types.BookTicker) {
+		g.dispatchEvent(shard, types.BookTickerChannel, bookTicker.Symbol, "", func(s *MarketDataHubStream) { s.EmitBookTickerUpdate(bookTicker) })
+	})
+
+	stream.OnMarketTrade(func(trade types.Trade) {
+		observeMarketDataHubLatency(g, "trade", trade.Time.Time())
+		g.dispatchEvent(shard, types.MarketTradeChannel, trade.Symbol, "", func(s *MarketDataHubStream) { s.EmitMarketTrade(trade) })
+	})
+
+	stream.OnAggTrade(func(trade types.Trade) {
+		observeMarketDataHubLatency(g, "aggTrade", trade.Time.Time())
+		g.dispatchEvent(shard, types.AggTradeChannel, trade.Symbol, "", func(s *MarketDataHubStream) { s.EmitAggTrade(trade) })
+	})
+
+	stream.OnForceOrder(func(info types.LiquidationInfo) {
+		g.dispatchEvent(shard, types.ForceOrderChannel, info.Symbol, "", func(s *MarketDataHubStream) { s.EmitForceOrder(info) })
+	})
+}
+
+func (g *marketDataGroup) hubStreams() []*MarketDataHubStream {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	streams := make([]*MarketDataHubStream, len(g.streams))
+	copy(streams, g.streams)
+	return streams
+}
+
+// dispatch emits the connection event to the hub streams having subscriptions in the shard
+func (g *marketDataGroup) dispatch(shard *marketDataShard, emit func(s *MarketDataHubStream)) {
+	for _, s := range g.hubStreams() {
+		if shard.subscribedBy(s) {
+			emit(s)
+		}
+	}
+}
+
+// dispatchEvent emits the market data event of the shard to the hub streams subscribing the topic of the shard
+func (g *marketDataGroup) dispatchEvent(shard *marketDataShard, channel types.Channel, symbol string, interval types.Interval, emit func(s *MarketDataHubStream)) {
+	topic, ok := shard.topics[hubSubscriptionKey(channel, symbol, interval)]
+	if !ok {
+		return
+	}
+
+	for _, s := range g.hubStreams() {
+		if s.isSubscribed(topic) {
+			emit(s)
+		}
+	}
+}
+
+// reconnect resyncs the hub stream without dropping the shared connections of the other hub streams.
+// The current books of the shards are sent to the hub stream as the snapshots, and a shard is only reconnected
+// when all of its hub streams request the reconnect within reconnectRequestWindow, e.g., the connection is silent
+// or its book is broken for every session.
+func (g *marketDataGroup) reconnect(stream *MarketDataHubStream) {
+	now := time.Now()
+
+	g.mu.Lock()
+	var reconnectShards, bookShards []*marketDataShard
+	var bookSymbols []string
+	for _, shard := range g.shards {
+		if !shard.subscribedBy(stream) {
+			continue
+		}
+
+		shard.reconnectRequests[stream] = now
+		if g.reconnectRequested(shard, now) {
+			shard.reconnectRequests = make(map[*MarketDataHubStream]time.Time)
+			reconnectShards = append(reconnectShards, shard)
+			continue
+		}
+
+		for _, sub := range shard.subscriptions {
+			if sub.Channel == types.BookChannel && stream.isSubscribed(hubTopicKey(sub)) {
+				bookShards = append(bookShards, shard)
+				bookSymbols = append(bookSymbols, sub.Symbol)
+			}
+		}
+	}
+	g.mu.Unlock()
+
+	for i, shard := range bookShards {
+		shard.sendBookSnapshot(stream, bookSymbols[i])
+	}
+
+	for _, shard := range reconnectShards {
+		log.Infof("[MarketDataHub] reconnecting %s market data connection requested by all of its sessions", g.key)
+		shard.stream.Reconnect()
+	}
+}
+
+// reconnectRequested returns true if all the hub streams of the shard requested the reconnect within the window,
+// it must be called with the lock
+func (g *marketDataGroup) reconnectRequested(shard *marketDataShard, now time.Time) bool {
+	for _, s := range g.streams {
+		if !shard.subscribedBy(s) {
+			continue
+		}
+
+		t, ok := shard.reconnectRequests[s]
+		if !ok || now.Sub(t) > reconnectRequestWindow {
+			return false
+		}
+	}
+	return true
+}
+
+// bookChecksumCalculator returns the checksum calculator of the shard receiving the book of the symbol for the hub stream
+func (g *marketDataGroup) bookChecksumCalculator(stream *MarketDataHubStream, symbol string) (types.BookChecksumCalculator, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, shard := range g.shards {
+		topic, ok := shard.topics[hubSubscriptionKey(types.BookChannel, symbol, "")]
+		if !ok || !stream.isSubscribed(topic) {
+			continue
+		}
+
+		calculator, ok := shard.stream.(types.BookChecksumCalculator)
+		return calculator, ok
+	}
+
+	return nil, false
+}
+
+// remove removes the hub stream from the group, the shards are closed when the group has no hub stream
+func (g *marketDataGroup) remove(stream *MarketDataHubStream) error {
+	g.mu.Lock()
+	for i, s := range g.streams {
+		if s == stream {
+			g.streams = append(g.streams[:i], g.streams[i+1:]...)
+			break
+		}
+	}
+
+	var shards []*marketDataShard
+	if len(g.streams) == 0 {
+		shards = g.shards
+		g.shards = nil
+	}
+	g.mu.Unlock()
+
+	for _, shard := range shards {
+		if err := shard.stream.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func observeMarketDataHubLatency(g *marketDataGroup, dataType string, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	metricsMarketDataHubLatency.With(prometheus.Labels{
+		"exchange":  g.exchangeName.String(),
+		"data_type": dataType,
+	}).Observe(time.Since(t).Seconds())
+}
+
+// MarketDataHubStream is the market data stream of a session, it receives the events of its subscriptions
+// from the shared connections of the hub.
+type MarketDataHubStream struct {
+	*types.StandardStream
+
+	group *marketDataGroup
+
+	subscribedMu sync.Mutex
+	subscribed   map[string]struct{}
+
+	// connected is guarded by the group lock
+	connected bool
+}
+
+func (s *MarketDataHubStream) Subscribe(channel types.Channel, symbol string, options types.SubscribeOptions) {
+	s.StandardStream.Subscribe(channel, symbol, options)
+
+	s.subscribedMu.Lock()
+	s.subscribed[hubTopicKey(types.Subscription{Channel: channel, Symbol: symbol, Options: options})] = struct{}{}
+	s.subscribedMu.Unlock()
+}
+
+func (s *MarketDataHubStream) isSubscribed(topic string) bool {
+	s.subscribedMu.Lock()
+	defer s.subscribedMu.Unlock()
+
+	_, ok := s.subscribed[topic]
+	return ok
+}
+
+// Resubscribe adds the new subscriptions to the hub, the removed subscriptions are only unsubscribed
+// from the session stream since the connections are shared.
+func (s *MarketDataHubStream) Resubscribe(fn func(oldSubs []types.Subscription) (newSubs []types.Subscription, err error)) error {
+	if err := s.StandardStream.Resubscribe(fn); err != nil {
+		return err
+	}
+
+	subscribed := make(map[string]struct{})
+	for _, sub := range s.GetSubscriptions() {
+		subscribed[hubTopicKey(sub)] = struct{}{}
+	}
+
+	s.subscribedMu.Lock()
+	s.subscribed = subscribed
+	s.subscribedMu.Unlock()
+
+	s.group.mu.Lock()
+	ctx := s.group.ctx
+	s.group.mu.Unlock()
+
+	if ctx == nil {
+		// the stream is not connected yet, the subscriptions are registered on connect
+		return nil
+	}
+
+	return s.group.connect(ctx, s)
+}
+
+// Connect registers the subscriptions to the hub, the shared connections are connected
+// when all the session streams of the same exchange are connected.
+func (s *MarketDataHubStream) Connect(ctx context.Context) error {
+	return s.group.connect(ctx, s)
+}
+
+// Reconnect resyncs the session stream, the current books of the shared connections are sent to it as the snapshots.
+// The shared connections are only reconnected when all of their session streams request it, so that the resync of
+// one session doesn't drop the market data of the other sessions.
+func (s *MarketDataHubStream) Reconnect() {
+	s.group.reconnect(s)
+}
+
+// CalculateBookChecksum calculates the book checksum by the shared connection receiving the book of the symbol,
+// since the exchange streams keep the raw book levels of the checksum. It returns 0 if the connection doesn't
+// support the checksum, the book updates of such connections don't carry the checksum either.
+func (s *MarketDataHubStream) CalculateBookChecksum(symbol string, bids, asks types.PriceVolumeSlice) int64 {
+	if calculator, ok := s.group.bookChecksumCalculator(s, symbol); ok {
+		return calculator.CalculateBookChecksum(symbol, bids, asks)
+	}
+	return 0
+}
+
+func (s *MarketDataHubStream) Close() error {
+	return s.group.remove(s)
+}
@@ -0,0 +1,257 @@
+package bbgo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testHubConnStream struct {
+	*types.StandardStream
+
+	connected  bool
+	reconnects int
+}
+
+func (s *testHubConnStream) Connect(ctx context.Context) error {
+	s.connected = true
+	s.EmitConnect()
+	return nil
+}
+
+func (s *testHubConnStream) Reconnect() {
+	s.reconnects++
+}
+
+func newTestHubStreamFactory(streams *[]*testHubConnStream) func() types.Stream {
+	return func() types.Stream {
+		standardStream := types.NewStandardStream()
+		stream := &testHubConnStream{StandardStream: &standardStream}
+		*streams = append(*streams, stream)
+		return stream
+	}
+}
+
+func joinPrices(pvs types.PriceVolumeSlice) string {
+	var prices []string
+	for _, pv := range pvs {
+		prices = append(prices, pv.Price.String())
+	}
+	return strings.Join(prices, ",")
+}
+
+func TestMarketDataHub(t *testing.T) {
+	ctx := context.Background()
+
+	var conns []*testHubConnStream
+	hub := NewMarketDataHub(&MarketDataHubConfig{
+		MaxSubscriptionsPerConnection: map[string]int{"binance": 2},
+	})
+
+	streamA := hub.NewStream("binance", types.ExchangeBinance, newTestHubStreamFactory(&conns))
+	streamB := hub.NewStream("binance", types.ExchangeBinance, newTestHubStreamFactory(&conns))
+
+	streamA.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+	streamA.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m})
+	streamB.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+	streamB.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval5m})
+	streamB.Subscribe(types.MarketTradeChannel, "ETHUSDT", types.SubscribeOptions{})
+
+	var connectedA, connectedB int
+	streamA.OnConnect(func() { connectedA++ })
+	streamB.OnConnect(func() { connectedB++ })
+
+	// the connections wait for all the session streams
+	require.NoError(t, streamA.Connect(ctx))
+	assert.Len(t, conns, 0)
+
+	require.NoError(t, streamB.Connect(ctx))
+
+	// 4 deduplicated subscriptions are sharded into 2 connections
+	require.Len(t, conns, 2)
+	assert.Equal(t, 2, hub.NumOfConnections())
+	assert.Len(t, conns[0].GetSubscriptions(), 2)
+	assert.Len(t, conns[1].GetSubscriptions(), 2)
+	assert.True(t, conns[0].connected)
+	assert.True(t, conns[1].connected)
+
+	// stream A has no subscription in the second connection
+	assert.Equal(t, 1, connectedA)
+	assert.Equal(t, 2, connectedB)
+
+	var booksA, booksB int
+	streamA.OnBookSnapshot(func(book types.SliceOrderBook) { booksA++ })
+	streamB.OnBookSnapshot(func(book types.SliceOrderBook) { booksB++ })
+
+	var klinesA, klinesB []types.Interval
+	streamA.OnKLineClosed(func(k types.KLine) { klinesA = append(klinesA, k.Interval) })
+	streamB.OnKLineClosed(func(k types.KLine) { klinesB = append(klinesB, k.Interval) })
+
+	var tradesA, tradesB int
+	streamA.OnMarketTrade(func(trade types.Trade) { tradesA++ })
+	streamB.OnMarketTrade(func(trade types.Trade) { tradesB++ })
+
+	for _, conn := range conns {
+		conn.EmitBookSnapshot(types.SliceOrderBook{Symbol: "BTCUSDT"})
+		conn.EmitKLineClosed(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m})
+		conn.EmitKLineClosed(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval5m})
+		conn.EmitMarketTrade(types.Trade{Symbol: "ETHUSDT"})
+	}
+
+	// the events are fanned out to the subscribers only, and only from the connection of the topic
+	assert.Equal(t, 1, booksA)
+	assert.Equal(t, 1, booksB)
+	assert.Equal(t, []types.Interval{types.Interval1m}, klinesA)
+	assert.Equal(t, []types.Interval{types.Interval5m}, klinesB)
+	assert.Equal(t, 0, tradesA)
+	assert.Equal(t, 1, tradesB)
+
+	// reconnecting stream A resyncs stream A only, the shared connection is kept for stream B
+	streamA.Reconnect()
+	assert.Equal(t, 0, conns[0].reconnects)
+	assert.Equal(t, 0, conns[1].reconnects)
+	assert.Equal(t, 2, booksA)
+	assert.Equal(t, 1, booksB)
+
+	// the shared connection is reconnected once all of its session streams request it,
+	// and the connection subscribed by stream B only is reconnected on its request
+	streamB.Reconnect()
+	assert.Equal(t, 1, conns[0].reconnects)
+	assert.Equal(t, 1, conns[1].reconnects)
+	assert.Equal(t, 2, booksA)
+	assert.Equal(t, 1, booksB)
+
+	// the reconnect requests are cleared after the reconnect
+	streamA.Reconnect()
+	assert.Equal(t, 1, conns[0].reconnects)
+	assert.Equal(t, 3, booksA)
+
+	// the new subscriptions are connected with a new connection
+	err := streamA.Resubscribe(func(oldSubs []types.Subscription) ([]types.Subscription, error) {
+		return append(oldSubs, types.Subscription{Channel: types.MarketTradeChannel, Symbol: "BNBUSDT"}), nil
+	})
+	require.NoError(t, err)
+	require.Len(t, conns, 3)
+	assert.Len(t, conns[2].GetSubscriptions(), 1)
+
+	conns[2].EmitMarketTrade(types.Trade{Symbol: "BNBUSDT"})
+	assert.Equal(t, 1, tradesA)
+	assert.Equal(t, 1, tradesB)
+}
+
+func TestMarketDataHub_SubscribeOptions(t *testing.T) {
+	ctx := context.Background()
+
+	var conns []*testHubConnStream
+	hub := NewMarketDataHub(&MarketDataHubConfig{})
+
+	streamA := hub.NewStream("binance", types.ExchangeBinance, newTestHubStreamFactory(&conns))
+	streamB := hub.NewStream("binance", types.ExchangeBinance, newTestHubStreamFactory(&conns))
+
+	streamA.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+	streamB.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevel5})
+
+	require.NoError(t, streamA.Connect(ctx))
+	require.NoError(t, streamB.Connect(ctx))
+
+	// the book of different depths are subscribed by different connections
+	require.Len(t, conns, 2)
+	assert.Equal(t, types.DepthLevelFull, conns[0].GetSubscriptions()[0].Options.Depth)
+	assert.Equal(t, types.DepthLevel5, conns[1].GetSubscriptions()[0].Options.Depth)
+
+	var booksA, booksB []int
+	streamA.OnBookSnapshot(func(book types.SliceOrderBook) { booksA = append(booksA, len(book.Bids)) })
+	streamB.OnBookSnapshot(func(book types.SliceOrderBook) { booksB = append(booksB, len(book.Bids)) })
+
+	conns[0].EmitBookSnapshot(types.SliceOrderBook{Symbol: "BTCUSDT", Bids: priceVolumesFromText("100,1;99,1;98,1")})
+	conns[1].EmitBookSnapshot(types.SliceOrderBook{Symbol: "BTCUSDT", Bids: priceVolumesFromText("100,1")})
+
+	assert.Equal(t, []int{3}, booksA)
+	assert.Equal(t, []int{1}, booksB)
+}
+
+func TestMarketDataHub_LateSubscriber(t *testing.T) {
+	ctx := context.Background()
+
+	var conns []*testHubConnStream
+	hub := NewMarketDataHub(&MarketDataHubConfig{})
+
+	streamA := hub.NewStream("binance", types.ExchangeBinance, newTestHubStreamFactory(&conns))
+	streamA.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+	require.NoError(t, streamA.Connect(ctx))
+	require.Len(t, conns, 1)
+
+	conns[0].EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   priceVolumesFromText("100,1;99,1"),
+		Asks:   priceVolumesFromText("101,1"),
+	})
+	conns[0].EmitBookUpdate(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   priceVolumesFromText("99,0;98,2"),
+	})
+
+	// the late stream shares the connection and receives the current book as the snapshot
+	streamB := hub.NewStream("binance", types.ExchangeBinance, newTestHubStreamFactory(&conns))
+	streamB.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+
+	var snapshots []types.SliceOrderBook
+	streamB.OnBookSnapshot(func(book types.SliceOrderBook) { snapshots = append(snapshots, book) })
+
+	require.NoError(t, streamB.Connect(ctx))
+	assert.Len(t, conns, 1)
+
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "100,98", joinPrices(snapshots[0].Bids))
+	assert.Equal(t, "101", joinPrices(snapshots[0].Asks))
+}
+
+// testChecksumConnStream calculates the checksum by the number of the levels and its connection index
+type testChecksumConnStream struct {
+	*testHubConnStream
+	index int
+}
+
+func (s *testChecksumConnStream) CalculateBookChecksum(_ string, bids, asks types.PriceVolumeSlice) int64 {
+	return int64(s.index*100 + len(bids) + len(asks))
+}
+
+func TestMarketDataHub_BookChecksum(t *testing.T) {
+	ctx := context.Background()
+
+	var conns []*testHubConnStream
+	factory := newTestHubStreamFactory(&conns)
+	hub := NewMarketDataHub(&MarketDataHubConfig{})
+
+	newStream := func() types.Stream {
+		return &testChecksumConnStream{testHubConnStream: factory().(*testHubConnStream), index: len(conns)}
+	}
+
+	streamA := hub.NewStream("okex", types.ExchangeOKEx, newStream)
+	streamB := hub.NewStream("okex", types.ExchangeOKEx, newStream)
+	streamA.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+	streamB.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevel5})
+	require.NoError(t, streamA.Connect(ctx))
+	require.NoError(t, streamB.Connect(ctx))
+	require.Len(t, conns, 2)
+
+	// the checksum is calculated by the connection of the book subscribed by the hub stream
+	bids := priceVolumesFromText("100,1;99,1")
+	assert.Equal(t, int64(102), streamA.CalculateBookChecksum("BTCUSDT", bids, nil))
+	assert.Equal(t, int64(202), streamB.CalculateBookChecksum("BTCUSDT", bids, nil))
+	assert.Equal(t, int64(0), streamA.CalculateBookChecksum("ETHUSDT", bids, nil), "the book is not subscribed")
+}
+
+func TestMarketDataHubKey(t *testing.T) {
+	spot := &ExchangeSession{ExchangeName: types.ExchangeBinance}
+	margin := &ExchangeSession{ExchangeName: types.ExchangeBinance, Margin: true}
+	futures := &ExchangeSession{ExchangeName: types.ExchangeBinance, Futures: true}
+
+	assert.Equal(t, marketDataHubKey(spot), marketDataHubKey(margin))
+	assert.NotEqual(t, marketDataHubKey(spot), marketDataHubKey(futures))
+}
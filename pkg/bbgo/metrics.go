@@ -83,6 +83,117 @@ var (
 		},
 	)
 
+	metricsBookHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_orderbook_healthy",
+			Help: "bbgo order book health status, 1 for healthy and 0 for unhealthy",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"symbol",
+		},
+	)
+
+	metricsBookUnhealthyTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bbgo_orderbook_unhealthy_total",
+			Help: "bbgo order book unhealthy events",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"symbol",
+			"reason", // reason: crossed, stale or checksum
+		},
+	)
+
+	metricsBookResyncTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bbgo_orderbook_resync_total",
+			Help: "bbgo order book resyncs",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"symbol",
+		},
+	)
+
+	metricsMarketDataHubConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_market_data_hub_connections",
+			Help: "bbgo market data hub connected websocket connections",
+		},
+		[]string{
+			"exchange", // exchange name
+			"group",    // the group of the sessions sharing the connections
+		},
+	)
+
+	metricsMarketDataHubSubscriptions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_market_data_hub_subscriptions",
+			Help: "bbgo market data hub deduplicated subscriptions",
+		},
+		[]string{
+			"exchange", // exchange name
+			"group",    // the group of the sessions sharing the connections
+		},
+	)
+
+	metricsMarketDataHubLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "bbgo_market_data_hub_latency_seconds",
+			Help:    "bbgo market data hub latency from the event time to the receive time",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		},
+		[]string{
+			"exchange",  // exchange name
+			"data_type", // kline, book, trade or aggTrade
+		},
+	)
+
+	metricsStreamHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_stream_subscription_healthy",
+			Help: "bbgo market data subscription health status checked by the stream watchdog, 1 for healthy and 0 for silent",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"channel",  // subscription channel
+			"symbol",   // subscription symbol
+			"interval", // kline interval
+		},
+	)
+
+	metricsStreamLastEventTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "bbgo_stream_subscription_last_event_time",
+			Help: "bbgo market data subscription last event unix timestamp",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"channel",  // subscription channel
+			"symbol",   // subscription symbol
+			"interval", // kline interval
+		},
+	)
+
+	metricsStreamRecoveryTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bbgo_stream_recovery_total",
+			Help: "bbgo market data stream recoveries of the silent subscriptions",
+		},
+		[]string{
+			"exchange", // exchange name
+			"session",  // session name
+			"action",   // resubscribe or reconnect
+		},
+	)
+
 	metricsLastUpdateTimeBalance = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "bbgo_last_update_time",
@@ -108,5 +219,14 @@ func init() {
 		metricsTradesTotal,
 		metricsTradingVolume,
 		metricsLastUpdateTimeBalance,
+		metricsBookHealthy,
+		metricsBookUnhealthyTotal,
+		metricsBookResyncTotal,
+		metricsMarketDataHubConnections,
+		metricsMarketDataHubSubscriptions,
+		metricsMarketDataHubLatency,
+		metricsStreamHealthy,
+		metricsStreamLastEventTime,
+		metricsStreamRecoveryTotal,
 	)
 }
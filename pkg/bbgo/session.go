@@ -16,6 +16,7 @@ import (
 	"github.com/c9s/bbgo/pkg/cache"
 	"github.com/c9s/bbgo/pkg/core"
 	"github.com/c9s/bbgo/pkg/exchange/retry"
+	"github.com/c9s/bbgo/pkg/streamrecorder"
 	"github.com/c9s/bbgo/pkg/util/templateutil"
 
 	exchange2 "github.com/c9s/bbgo/pkg/exchange"
@@ -93,6 +94,10 @@ type ExchangeSession struct {
 
 	UseHeikinAshi bool `json:"heikinAshi,omitempty" yaml:"heikinAshi,omitempty"`
 
+	// Record is the journal file path for recording the stream events of the session, the journal can be replayed
+	// by the replay command. The journal file ends with .gz is gzip compressed.
+	Record string `json:"record,omitempty" yaml:"record,omitempty"`
+
 	// Trades collects the executed trades from the exchange
 	// map: symbol -> []trade
 	Trades map[string]*types.TradeSlice `json:"-" yaml:"-"`
@@ -103,6 +108,12 @@ type ExchangeSession struct {
 	// orderBooks stores the streaming order book
 	orderBooks map[string]*types.StreamOrderBook
 
+	// barBuilders builds the bars of the bar interval subscriptions
+	barBuilders map[types.Subscription]*types.BarBuilder
+
+	// kLineAggregators synthesizes the klines of the intervals not supported by the exchange
+	kLineAggregators map[types.Subscription]*types.KLineAggregator
+
 	// startPrices is used for backtest
 	startPrices map[string]fixedpoint.Value
 
@@ -126,6 +137,8 @@ type ExchangeSession struct {
 	initializedSymbols map[string]struct{}
 
 	logger log.FieldLogger
+
+	recorder *streamrecorder.Recorder
 }
 
 func NewExchangeSession(name string, exchange types.Exchange) *ExchangeSession {
@@ -164,6 +177,46 @@ func NewExchangeSession(name string, exchange types.Exchange) *ExchangeSession {
 	return session
 }
 
+func (session *ExchangeSession) startRecorder(markets types.MarketMap, startTime time.Time) error {
+	recorder, err := streamrecorder.Create(session.Record)
+	if err != nil {
+		return err
+	}
+
+	err = recorder.RecordSession(streamrecorder.SessionInfo{
+		Name:            session.Name,
+		Exchange:        session.Exchange.Name(),
+		Margin:          session.Margin,
+		IsolatedMargin:  session.IsolatedMargin,
+		Futures:         session.Futures,
+		IsolatedFutures: session.IsolatedFutures,
+		StartTime:       startTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := recorder.RecordMarkets(markets); err != nil {
+		return err
+	}
+
+	recorder.BindStream(streamrecorder.StreamMarket, session.MarketDataStream)
+	recorder.BindStream(streamrecorder.StreamUser, session.UserDataStream)
+
+	session.logger.Infof("recording the stream events to %s", session.Record)
+	session.recorder = recorder
+	return nil
+}
+
+// CloseRecorder flushes and closes the stream event journal if the session is recording
+func (session *ExchangeSession) CloseRecorder() error {
+	if session.recorder == nil {
+		return nil
+	}
+
+	return session.recorder.Close()
+}
+
 func (session *ExchangeSession) GetAccount() (a *types.Account) {
 	session.accountMutex.Lock()
 	a = session.Account
@@ -222,6 +275,11 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 
 	session.markets = markets
 
+	// the futures markets share the global symbols with the spot markets, only the spot markets are registered
+	if !session.Futures {
+		types.DefaultSymbolRegistry.RegisterMarkets(session.ExchangeName, markets)
+	}
+
 	if feeRateProvider, ok := session.Exchange.(types.ExchangeDefaultFeeRates); ok {
 		defaultFeeRates := feeRateProvider.DefaultFeeRates()
 		if session.MakerFeeRate.IsZero() {
@@ -242,6 +300,13 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 		amountProtectExchange.SetModifyOrderAmountForFee(fees)
 	}
 
+	// the recorder is bound to the original market data stream, the heikin ashi klines are calculated again on replay
+	if session.Record != "" {
+		if err := session.startRecorder(markets, environ.startTime); err != nil {
+			return err
+		}
+	}
+
 	if session.UseHeikinAshi {
 		session.MarketDataStream = &types.HeikinAshiStream{
 			StandardStreamEmitter: session.MarketDataStream.(types.StandardStreamEmitter),
@@ -275,6 +340,12 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 
 			session.setAccount(account)
 			session.metricsBalancesUpdater(account.Balances())
+
+			if session.recorder != nil {
+				if err := session.recorder.RecordAccount(account); err != nil {
+					return err
+				}
+			}
 			logger.Infof("account %s balances:\n%s", session.Name, account.Balances().String())
 		}
 
@@ -469,7 +540,8 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 			session.orderBooks[sub.Symbol] = book
 
 		case types.KLineChannel:
-			if sub.Options.Interval == "" {
+			// the bars are built from the market trades, they have no history to preload
+			if sub.Options.Interval == "" || sub.Options.Interval.IsBar() {
 				continue
 			}
 
@@ -489,7 +561,36 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 	}
 
 	if !(environ.environmentConfig != nil && environ.environmentConfig.DisableHistoryKLinePreload) {
+		var snapshot *IndicatorSnapshot
+		if environ.indicatorSnapshotConfig() != nil && !disableMarketDataStore {
+			ps := GetIsolationFromContext(ctx).persistenceServiceFacade.Get()
+			s, err := LoadIndicatorSnapshot(ps, session.Name, symbol)
+			if err != nil {
+				log.WithError(err).Warnf("unable to load the %s indicator snapshot, preloading the klines", symbol)
+			}
+			snapshot = s
+
+			if snapshot != nil {
+				session.Indicators(symbol).RestoreStates(snapshot.States)
+			}
+		}
+
 		for interval := range klineSubscriptions {
+			if snapshot != nil {
+				restored, err := session.restoreKLineSnapshot(ctx, marketDataStore, snapshot, interval, environ.startTime,
+					environ.indicatorSnapshotConfig().maxKLines())
+				if err != nil {
+					return err
+				}
+
+				if restored {
+					if window, ok := marketDataStore.KLinesOfInterval(interval); ok && interval == minInterval && len(*window) > 0 {
+						session.lastPrices[symbol] = window.Last().Close
+					}
+					continue
+				}
+			}
+
 			// avoid querying the last unclosed kline
 			endTime := environ.startTime
 			var i int64
@@ -497,7 +598,7 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 				var duration time.Duration = time.Duration(-i * int64(interval.Duration()))
 				e := endTime.Add(duration)
 
-				kLines, err := session.Exchange.QueryKLines(ctx, symbol, interval, types.KLineQueryOptions{
+				kLines, err := session.QueryKLines(ctx, symbol, interval, types.KLineQueryOptions{
 					EndTime: &e,
 					Limit:   1000, // indicators need at least 100
 				})
@@ -510,6 +611,12 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 					continue
 				}
 
+				if session.recorder != nil {
+					if err := session.recorder.RecordKLineHistory(symbol, interval, kLines); err != nil {
+						return err
+					}
+				}
+
 				// update last prices by the given kline
 				lastKLine := kLines[len(kLines)-1]
 				if interval == minInterval {
@@ -0,0 +1,55 @@
+package bbgo
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// barSourceInterval is the kline interval the bars are built from in the back-test
+var barSourceInterval = types.Interval1m
+
+// bindBarBuilder builds the bars of the bar interval subscription and emits them as the klines of the market data
+// stream. The bars are built from the market trades, or from the 1m klines in the back-test since the market trades
+// are not stored. It returns the subscription the bars are built from.
+func (session *ExchangeSession) bindBarBuilder(sub types.Subscription) (types.Subscription, error) {
+	source := types.Subscription{Channel: types.MarketTradeChannel, Symbol: sub.Symbol}
+	if IsBackTesting {
+		source = types.Subscription{
+			Channel: types.KLineChannel,
+			Symbol:  sub.Symbol,
+			Options: types.SubscribeOptions{Interval: barSourceInterval},
+		}
+	}
+
+	if session.barBuilders == nil {
+		session.barBuilders = make(map[types.Subscription]*types.BarBuilder)
+	}
+
+	if _, ok := session.barBuilders[sub]; ok {
+		return source, nil
+	}
+
+	emitter, ok := session.MarketDataStream.(types.StandardStreamEmitter)
+	if !ok {
+		return source, fmt.Errorf("%s market data stream %T can not emit the %s bars", session.Name, session.MarketDataStream, sub.Options.Interval)
+	}
+
+	builder, err := types.NewBarBuilder(sub.Symbol, sub.Options.Interval)
+	if err != nil {
+		return source, err
+	}
+
+	builder.Exchange = session.ExchangeName
+	builder.OnBarUpdate(emitter.EmitKLine)
+	builder.OnBarClosed(emitter.EmitKLineClosed)
+
+	if IsBackTesting {
+		session.MarketDataStream.OnKLineClosed(types.KLineWith(sub.Symbol, barSourceInterval, builder.AddKLine))
+	} else {
+		session.MarketDataStream.OnMarketTrade(builder.AddTrade)
+	}
+
+	session.barBuilders[sub] = builder
+	return source, nil
+}
@@ -0,0 +1,41 @@
+package bbgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchangeSession_BindBarBuilder(t *testing.T) {
+	session, stream := newTestBookMonitorSession()
+
+	sub := types.Subscription{
+		Channel: types.KLineChannel,
+		Symbol:  "BTCUSDT",
+		Options: types.SubscribeOptions{Interval: "tick:2"},
+	}
+
+	source, err := session.bindBarBuilder(sub)
+	require.NoError(t, err)
+	assert.Equal(t, types.Subscription{Channel: types.MarketTradeChannel, Symbol: "BTCUSDT"}, source)
+
+	// binding the same subscription twice doesn't build the bars twice
+	_, err = session.bindBarBuilder(sub)
+	require.NoError(t, err)
+
+	var bars []types.KLine
+	stream.OnKLineClosed(types.KLineWith("BTCUSDT", "tick:2", func(k types.KLine) { bars = append(bars, k) }))
+
+	for i := 0; i < 5; i++ {
+		stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT", Price: fixedpoint.NewFromInt(100 + int64(i)), Quantity: fixedpoint.One})
+	}
+
+	require.Len(t, bars, 2)
+	assert.Equal(t, types.ExchangeBinance, bars[0].Exchange)
+	assert.Equal(t, "101", bars[0].Close.String())
+	assert.Equal(t, "103", bars[1].Close.String())
+}
@@ -0,0 +1,180 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// maxBaseKLinesPerQuery is the max number of the base klines of a query for synthesizing the klines
+const maxBaseKLinesPerQuery = 1000
+
+// syntheticBaseInterval returns the base interval for synthesizing the interval not supported by the exchange.
+// It returns false if the exchange supports the interval or the interval can't be synthesized.
+func (session *ExchangeSession) syntheticBaseInterval(interval types.Interval) (types.Interval, bool) {
+	provider, ok := session.Exchange.(types.CustomIntervalProvider)
+	if !ok || provider.IsSupportedInterval(interval) {
+		return "", false
+	}
+
+	return types.FindBaseInterval(interval, provider.SupportedInterval())
+}
+
+// QueryKLines queries the klines from the exchange, the intervals not supported by the exchange are synthesized
+// by aggregating the klines of a supported base interval.
+func (session *ExchangeSession) QueryKLines(
+	ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions,
+) ([]types.KLine, error) {
+	baseInterval, ok := session.syntheticBaseInterval(interval)
+	if !ok {
+		return session.Exchange.QueryKLines(ctx, symbol, interval, options)
+	}
+
+	return queryAggregatedKLines(ctx, session.Exchange, symbol, interval, baseInterval, options)
+}
+
+func queryAggregatedKLines(
+	ctx context.Context, ex types.Exchange, symbol string, interval, baseInterval types.Interval,
+	options types.KLineQueryOptions,
+) ([]types.KLine, error) {
+	limit := options.Limit
+	if limit <= 0 {
+		limit = maxBaseKLinesPerQuery
+	}
+
+	// one more window for the partial window of the start
+	ratio := int(interval.Duration() / baseInterval.Duration())
+	needed := (limit + 1) * ratio
+
+	var baseKLines []types.KLine
+	var seen = make(map[time.Time]struct{})
+	add := func(kLines []types.KLine) (added int) {
+		for _, k := range kLines {
+			if _, ok := seen[k.StartTime.Time()]; ok {
+				continue
+			}
+
+			seen[k.StartTime.Time()] = struct{}{}
+			baseKLines = append(baseKLines, k)
+			added++
+		}
+		return added
+	}
+
+	forward := options.StartTime != nil && options.EndTime == nil
+	if forward {
+		// the first window starts at or after the start time
+		startTime := types.KLineStartTime(*options.StartTime, interval)
+		if startTime.Before(*options.StartTime) {
+			startTime = startTime.Add(interval.Duration())
+		}
+		for len(baseKLines) < needed {
+			kLines, err := ex.QueryKLines(ctx, symbol, baseInterval, types.KLineQueryOptions{
+				StartTime: &startTime,
+				Limit:     min(needed-len(baseKLines), maxBaseKLinesPerQuery),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if add(kLines) == 0 {
+				break
+			}
+
+			startTime = kLines[len(kLines)-1].StartTime.Time().Add(baseInterval.Duration())
+		}
+	} else {
+		endTime := time.Now()
+		if options.EndTime != nil {
+			endTime = *options.EndTime
+		}
+
+		for len(baseKLines) < needed {
+			kLines, err := ex.QueryKLines(ctx, symbol, baseInterval, types.KLineQueryOptions{
+				StartTime: options.StartTime,
+				EndTime:   &endTime,
+				Limit:     min(needed-len(baseKLines), maxBaseKLinesPerQuery),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if add(kLines) == 0 {
+				break
+			}
+
+			endTime = kLines[0].StartTime.Time().Add(-time.Millisecond)
+			if options.StartTime != nil && endTime.Before(*options.StartTime) {
+				break
+			}
+		}
+	}
+
+	sort.Slice(baseKLines, func(i, j int) bool {
+		return baseKLines[i].StartTime.Before(baseKLines[j].StartTime.Time())
+	})
+
+	aggregated := types.AggregateKLines(baseKLines, interval, baseInterval)
+	if len(aggregated) > limit {
+		if forward {
+			aggregated = aggregated[:limit]
+		} else {
+			aggregated = aggregated[len(aggregated)-limit:]
+		}
+	}
+
+	return aggregated, nil
+}
+
+// bindKLineAggregator synthesizes the closed klines of the interval not supported by the exchange, and emits them
+// as the closed klines of the market data stream. The aggregator is seeded with the closed base klines of the
+// current window, so that the first synthesized kline is complete. It returns the base subscription.
+func (session *ExchangeSession) bindKLineAggregator(
+	ctx context.Context, sub types.Subscription, baseInterval types.Interval, now time.Time,
+) (types.Subscription, error) {
+	source := types.Subscription{
+		Channel: types.KLineChannel,
+		Symbol:  sub.Symbol,
+		Options: types.SubscribeOptions{Interval: baseInterval},
+	}
+
+	if session.kLineAggregators == nil {
+		session.kLineAggregators = make(map[types.Subscription]*types.KLineAggregator)
+	}
+
+	if _, ok := session.kLineAggregators[sub]; ok {
+		return source, nil
+	}
+
+	emitter, ok := session.MarketDataStream.(types.StandardStreamEmitter)
+	if !ok {
+		return source, fmt.Errorf("%s market data stream %T can not emit the synthesized %s klines", session.Name, session.MarketDataStream, sub.Options.Interval)
+	}
+
+	aggregator := types.NewKLineAggregator(sub.Symbol, sub.Options.Interval, baseInterval)
+
+	startTime := types.KLineStartTime(now, sub.Options.Interval)
+	kLines, err := session.Exchange.QueryKLines(ctx, sub.Symbol, baseInterval, types.KLineQueryOptions{
+		StartTime: &startTime,
+		EndTime:   &now,
+		Limit:     maxBaseKLinesPerQuery,
+	})
+	if err != nil {
+		return source, err
+	}
+
+	for _, k := range kLines {
+		// skip the base klines of the previous windows and the unclosed base kline
+		if !k.StartTime.Before(startTime) && k.EndTime.Before(now) {
+			aggregator.AddKLine(k)
+		}
+	}
+
+	aggregator.OnKLineClosed(emitter.EmitKLineClosed)
+	session.MarketDataStream.OnKLineClosed(aggregator.AddKLine)
+	session.kLineAggregators[sub] = aggregator
+	return source, nil
+}
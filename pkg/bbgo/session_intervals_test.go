@@ -0,0 +1,143 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// testIntervalExchange serves the 1m klines and supports only the 1m interval
+type testIntervalExchange struct {
+	types.Exchange
+
+	kLines  []types.KLine
+	queries int
+}
+
+func (e *testIntervalExchange) SupportedInterval() map[types.Interval]int {
+	return map[types.Interval]int{types.Interval1m: 60}
+}
+
+func (e *testIntervalExchange) IsSupportedInterval(interval types.Interval) bool {
+	return interval == types.Interval1m
+}
+
+func (e *testIntervalExchange) QueryKLines(
+	ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions,
+) ([]types.KLine, error) {
+	e.queries++
+
+	var kLines []types.KLine
+	for _, k := range e.kLines {
+		if options.StartTime != nil && k.StartTime.Before(*options.StartTime) {
+			continue
+		}
+		if options.EndTime != nil && k.StartTime.After(*options.EndTime) {
+			continue
+		}
+		kLines = append(kLines, k)
+	}
+
+	if options.Limit > 0 && len(kLines) > options.Limit {
+		if options.EndTime != nil {
+			kLines = kLines[len(kLines)-options.Limit:]
+		} else {
+			kLines = kLines[:options.Limit]
+		}
+	}
+	return kLines, nil
+}
+
+func newTestIntervalExchange(startTime time.Time, n int) *testIntervalExchange {
+	ex := &testIntervalExchange{}
+	for i := 0; i < n; i++ {
+		t := startTime.Add(time.Duration(i) * time.Minute)
+		ex.kLines = append(ex.kLines, types.KLine{
+			Exchange:  types.ExchangeMax,
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(t),
+			EndTime:   types.Time(t.Add(time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromInt(int64(i)),
+			High:      fixedpoint.NewFromInt(int64(i + 1)),
+			Low:       fixedpoint.NewFromInt(int64(i)),
+			Close:     fixedpoint.NewFromInt(int64(i + 1)),
+			Volume:    fixedpoint.One,
+		})
+	}
+	return ex
+}
+
+func TestExchangeSession_QueryKLines(t *testing.T) {
+	ctx := context.Background()
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ex := newTestIntervalExchange(startTime, 3000)
+	session := &ExchangeSession{Name: "max", ExchangeName: types.ExchangeMax, Exchange: ex}
+
+	// the end time is in the middle of a 3m window
+	endTime := startTime.Add(2999 * time.Minute)
+	kLines, err := session.QueryKLines(ctx, "BTCUSDT", types.Interval3m, types.KLineQueryOptions{EndTime: &endTime, Limit: 500})
+	require.NoError(t, err)
+	require.Len(t, kLines, 500)
+	assert.Greater(t, ex.queries, 1)
+
+	last := kLines[len(kLines)-1]
+	assert.Equal(t, types.Interval3m, last.Interval)
+	assert.Equal(t, startTime.Add(2997*time.Minute), last.StartTime.Time())
+	assert.Equal(t, "3", last.Volume.String())
+	assert.Equal(t, "2997", last.Open.String())
+	assert.Equal(t, "3000", last.Close.String())
+
+	queryStartTime := startTime.Add(time.Minute)
+	kLines, err = session.QueryKLines(ctx, "BTCUSDT", types.Interval3m, types.KLineQueryOptions{StartTime: &queryStartTime, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, kLines, 10)
+
+	// the window containing the start time is skipped
+	assert.Equal(t, startTime.Add(3*time.Minute), kLines[0].StartTime.Time())
+
+	// the supported interval is queried directly
+	ex.queries = 0
+	kLines, err = session.QueryKLines(ctx, "BTCUSDT", types.Interval1m, types.KLineQueryOptions{EndTime: &endTime, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, kLines, 10)
+	assert.Equal(t, 1, ex.queries)
+}
+
+func TestExchangeSession_BindKLineAggregator(t *testing.T) {
+	ctx := context.Background()
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ex := newTestIntervalExchange(startTime, 12)
+
+	stream := types.NewStandardStream()
+	session := &ExchangeSession{Name: "max", ExchangeName: types.ExchangeMax, Exchange: ex, MarketDataStream: &stream}
+
+	sub := types.Subscription{Channel: types.KLineChannel, Symbol: "BTCUSDT", Options: types.SubscribeOptions{Interval: types.Interval3m}}
+	baseInterval, ok := session.syntheticBaseInterval(types.Interval3m)
+	require.True(t, ok)
+
+	// the 9m kline of the current window is closed, the 10m kline is unclosed at the start
+	now := startTime.Add(10*time.Minute + 30*time.Second)
+	source, err := session.bindKLineAggregator(ctx, sub, baseInterval, now)
+	require.NoError(t, err)
+	assert.Equal(t, types.Interval1m, source.Options.Interval)
+
+	var kLines []types.KLine
+	stream.OnKLineClosed(types.KLineWith("BTCUSDT", types.Interval3m, func(k types.KLine) { kLines = append(kLines, k) }))
+
+	// the klines closed by the stream complete the seeded window
+	stream.EmitKLineClosed(ex.kLines[10])
+	assert.Len(t, kLines, 0)
+
+	stream.EmitKLineClosed(ex.kLines[11])
+	require.Len(t, kLines, 1)
+	assert.Equal(t, startTime.Add(9*time.Minute), kLines[0].StartTime.Time())
+	assert.Equal(t, "3", kLines[0].Volume.String())
+	assert.Equal(t, "9", kLines[0].Open.String())
+}
@@ -0,0 +1,401 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultStreamCheckInterval = 10 * time.Second
+	defaultStreamMaxSilence    = time.Minute
+)
+
+// StreamRecoveryAction is the action taken on a silent market data stream
+type StreamRecoveryAction string
+
+const (
+	StreamRecoveryResubscribe StreamRecoveryAction = "resubscribe"
+	StreamRecoveryReconnect   StreamRecoveryAction = "reconnect"
+)
+
+type StreamWatchdogConfig struct {
+	// CheckInterval is the interval of the periodic silence checks
+	CheckInterval types.Duration `json:"checkInterval,omitempty"`
+
+	// MaxSilence is the max duration without any event of a subscription.
+	// The kline subscriptions are allowed to be silent for one more kline interval.
+	MaxSilence types.Duration `json:"maxSilence,omitempty"`
+
+	// ChannelMaxSilence overrides MaxSilence by the channel, e.g. {"trade": "10m"} for the illiquid markets
+	ChannelMaxSilence map[types.Channel]types.Duration `json:"channelMaxSilence,omitempty"`
+
+	// RecoveryCooldown is the min duration between two recovery actions of the same stream, defaults to MaxSilence
+	RecoveryCooldown types.Duration `json:"recoveryCooldown,omitempty"`
+
+	// DisableRecovery disables resubscribing and reconnecting, the silent subscriptions are only reported
+	DisableRecovery bool `json:"disableRecovery,omitempty"`
+
+	// DisableNotification disables the notifications of the silent and recovered streams
+	DisableNotification bool `json:"disableNotification,omitempty"`
+}
+
+func (c *StreamWatchdogConfig) setDefaults() {
+	if c.CheckInterval == 0 {
+		c.CheckInterval = types.Duration(defaultStreamCheckInterval)
+	}
+
+	if c.MaxSilence == 0 {
+		c.MaxSilence = types.Duration(defaultStreamMaxSilence)
+	}
+
+	if c.RecoveryCooldown == 0 {
+		c.RecoveryCooldown = c.MaxSilence
+	}
+}
+
+func (c *StreamWatchdogConfig) maxSilence(sub types.Subscription) time.Duration {
+	if d, ok := c.ChannelMaxSilence[sub.Channel]; ok && d > 0 {
+		return d.Duration()
+	}
+
+	d := c.MaxSilence.Duration()
+	if sub.Channel == types.KLineChannel && sub.Options.Interval != "" {
+		// some exchanges only push the closed klines
+		d += sub.Options.Interval.Duration()
+	}
+	return d
+}
+
+// StreamHealthStatus is the health status of a market data subscription
+type StreamHealthStatus struct {
+	Session  string         `json:"session"`
+	Channel  types.Channel  `json:"channel"`
+	Symbol   string         `json:"symbol"`
+	Interval types.Interval `json:"interval,omitempty"`
+	Healthy  bool           `json:"healthy"`
+
+	// LastEventTime is the receive time of the last event, it's zero if no event is received since connected
+	LastEventTime time.Time     `json:"lastEventTime,omitempty"`
+	Silence       time.Duration `json:"silence"`
+
+	// Recoveries is the number of the recovery actions taken since the stream became silent
+	Recoveries int `json:"recoveries"`
+}
+
+func (s StreamHealthStatus) String() string {
+	name := fmt.Sprintf("%s %s %s", s.Session, s.Channel, s.Symbol)
+	if s.Interval != "" {
+		name += " " + s.Interval.String()
+	}
+
+	if s.Healthy {
+		return fmt.Sprintf("%s: healthy, last event %s ago", name, s.Silence.Truncate(time.Second))
+	}
+
+	return fmt.Sprintf("%s: silent for %s, %d recoveries", name, s.Silence.Truncate(time.Second), s.Recoveries)
+}
+
+type watchedStream struct {
+	session *ExchangeSession
+	stream  types.Stream
+
+	// connectTime is the time of the last connect, the silence of the subscriptions is counted from it
+	connectTime time.Time
+
+	lastEventTimes map[string]time.Time
+	silent         map[string]bool
+
+	recoveries   int
+	lastRecovery time.Time
+}
+
+// StreamWatchdog watches the market data streams of the sessions. A venue may stop pushing a channel while the
+// websocket connection is still alive, and the ping/pong keep-alive of the stream can't detect it.
+// The watchdog tracks the last event time of each subscription, resubscribes the silent stream at first and
+// reconnects it if the subscriptions are still silent after the cooldown.
+type StreamWatchdog struct {
+	config StreamWatchdogConfig
+
+	mu      sync.Mutex
+	streams map[string]*watchedStream
+
+	now func() time.Time
+}
+
+func NewStreamWatchdog(config StreamWatchdogConfig) *StreamWatchdog {
+	config.setDefaults()
+	return &StreamWatchdog{
+		config:  config,
+		streams: make(map[string]*watchedStream),
+		now:     time.Now,
+	}
+}
+
+// AddSession watches the market data stream of the session, it should be called before connecting the stream
+func (w *StreamWatchdog) AddSession(session *ExchangeSession) {
+	if session.MarketDataStream == nil {
+		return
+	}
+
+	w.mu.Lock()
+	if _, ok := w.streams[session.Name]; ok {
+		w.mu.Unlock()
+		return
+	}
+
+	s := &watchedStream{
+		session:        session,
+		stream:         session.MarketDataStream,
+		lastEventTimes: make(map[string]time.Time),
+		silent:         make(map[string]bool),
+	}
+	w.streams[session.Name] = s
+	w.mu.Unlock()
+
+	stream := session.MarketDataStream
+	stream.OnConnect(func() {
+		w.mu.Lock()
+		s.connectTime = w.now()
+		w.mu.Unlock()
+	})
+
+	stream.OnKLine(func(k types.KLine) {
+		w.touch(s, types.KLineChannel, k.Symbol, k.Interval)
+	})
+	stream.OnKLineClosed(func(k types.KLine) {
+		w.touch(s, types.KLineChannel, k.Symbol, k.Interval)
+	})
+	stream.OnBookSnapshot(func(book types.SliceOrderBook) {
+		w.touch(s, types.BookChannel, book.Symbol, "")
+	})
+	stream.OnBookUpdate(func(book types.SliceOrderBook) {
+		w.touch(s, types.BookChannel, book.Symbol, "")
+	})
+	stream.OnBookTickerUpdate(func(bookTicker types.BookTicker) {
+		w.touch(s, types.BookTickerChannel, bookTicker.Symbol, "")
+	})
+	stream.OnMarketTrade(func(trade types.Trade) {
+		w.touch(s, types.MarketTradeChannel, trade.Symbol, "")
+	})
+	stream.OnAggTrade(func(trade types.Trade) {
+		w.touch(s, types.AggTradeChannel, trade.Symbol, "")
+	})
+	stream.OnForceOrder(func(info types.LiquidationInfo) {
+		w.touch(s, types.ForceOrderChannel, info.Symbol, "")
+	})
+}
+
+func (w *StreamWatchdog) touch(s *watchedStream, channel types.Channel, symbol string, interval types.Interval) {
+	now := w.now()
+	key := hubSubscriptionKey(channel, symbol, interval)
+
+	w.mu.Lock()
+	s.lastEventTimes[key] = now
+	w.mu.Unlock()
+
+	metricsStreamLastEventTime.With(streamMetricsLabels(s, channel, symbol, interval)).Set(float64(now.Unix()))
+}
+
+// Statuses returns the health status of all the subscriptions of the watched streams
+func (w *StreamWatchdog) Statuses() []StreamHealthStatus {
+	if w == nil {
+		return nil
+	}
+
+	now := w.now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var statuses []StreamHealthStatus
+	for _, s := range w.streams {
+		statuses = append(statuses, w.statuses(s, now)...)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if a.Session != b.Session {
+			return a.Session < b.Session
+		}
+		return hubSubscriptionKey(a.Channel, a.Symbol, a.Interval) < hubSubscriptionKey(b.Channel, b.Symbol, b.Interval)
+	})
+	return statuses
+}
+
+// statuses must be called with the lock held
+func (w *StreamWatchdog) statuses(s *watchedStream, now time.Time) []StreamHealthStatus {
+	var statuses []StreamHealthStatus
+	for _, sub := range s.stream.GetSubscriptions() {
+		key := hubSubscriptionKey(sub.Channel, sub.Symbol, sub.Options.Interval)
+		lastEventTime := s.lastEventTimes[key]
+
+		since := lastEventTime
+		if since.Before(s.connectTime) {
+			since = s.connectTime
+		}
+
+		status := StreamHealthStatus{
+			Session:       s.session.Name,
+			Channel:       sub.Channel,
+			Symbol:        sub.Symbol,
+			Interval:      sub.Options.Interval,
+			Healthy:       true,
+			LastEventTime: lastEventTime,
+		}
+
+		// the silence is not counted until the stream is connected
+		if !since.IsZero() {
+			status.Silence = now.Sub(since)
+			status.Healthy = status.Silence <= w.config.maxSilence(sub)
+		}
+
+		if !status.Healthy {
+			status.Recoveries = s.recoveries
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// Run checks the streams periodically until the context is canceled
+func (w *StreamWatchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.CheckInterval.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			w.CheckAll()
+		}
+	}
+}
+
+// CheckAll checks the silence of the subscriptions of all the streams
+func (w *StreamWatchdog) CheckAll() {
+	w.mu.Lock()
+	var streams []*watchedStream
+	for _, s := range w.streams {
+		streams = append(streams, s)
+	}
+	w.mu.Unlock()
+
+	for _, s := range streams {
+		w.check(s)
+	}
+}
+
+func (w *StreamWatchdog) check(s *watchedStream) {
+	now := w.now()
+
+	w.mu.Lock()
+	statuses := w.statuses(s, now)
+
+	var silent, recovered []StreamHealthStatus
+	current := make(map[string]bool)
+	for _, status := range statuses {
+		key := hubSubscriptionKey(status.Channel, status.Symbol, status.Interval)
+		if !status.Healthy {
+			current[key] = true
+			if !s.silent[key] {
+				silent = append(silent, status)
+			}
+		} else if s.silent[key] {
+			recovered = append(recovered, status)
+		}
+
+		metricsStreamHealthy.With(streamMetricsLabels(s, status.Channel, status.Symbol, status.Interval)).Set(boolToFloat(status.Healthy))
+	}
+	s.silent = current
+
+	var action StreamRecoveryAction
+	if len(current) == 0 {
+		s.recoveries = 0
+	} else if !w.config.DisableRecovery && now.Sub(s.lastRecovery) >= w.config.RecoveryCooldown.Duration() {
+		// resubscribe at the first time, and reconnect if the resubscription doesn't help
+		action = StreamRecoveryResubscribe
+		if s.recoveries > 0 {
+			action = StreamRecoveryReconnect
+		}
+
+		s.recoveries++
+		s.lastRecovery = now
+	}
+	w.mu.Unlock()
+
+	for _, status := range silent {
+		log.Warnf("[StreamWatchdog] %s", status)
+		w.notify("%s market data stream %s %s is silent for %s", status.Session, status.Channel, status.Symbol, status.Silence.Truncate(time.Second))
+	}
+
+	for _, status := range recovered {
+		log.Infof("[StreamWatchdog] %s", status)
+		w.notify("%s market data stream %s %s is recovered", status.Session, status.Channel, status.Symbol)
+	}
+
+	if action != "" {
+		w.recover(s, action)
+	}
+}
+
+func (w *StreamWatchdog) recover(s *watchedStream, action StreamRecoveryAction) {
+	log.Warnf("[StreamWatchdog] recovering %s market data stream by %s", s.session.Name, action)
+	metricsStreamRecoveryTotal.With(map[string]string{
+		"exchange": s.session.ExchangeName.String(),
+		"session":  s.session.Name,
+		"action":   string(action),
+	}).Inc()
+
+	switch action {
+	case StreamRecoveryResubscribe:
+		err := s.stream.Resubscribe(func(oldSubs []types.Subscription) ([]types.Subscription, error) {
+			return oldSubs, nil
+		})
+		if err == nil {
+			return
+		}
+
+		log.WithError(err).Errorf("[StreamWatchdog] unable to resubscribe %s market data stream, reconnecting", s.session.Name)
+		s.stream.Reconnect()
+
+	case StreamRecoveryReconnect:
+		w.notify("reconnecting %s market data stream for the silent subscriptions", s.session.Name)
+		s.stream.Reconnect()
+	}
+}
+
+func (w *StreamWatchdog) notify(format string, args ...interface{}) {
+	if w.config.DisableNotification {
+		return
+	}
+
+	Notify(format, args...)
+}
+
+func streamMetricsLabels(s *watchedStream, channel types.Channel, symbol string, interval types.Interval) map[string]string {
+	return map[string]string{
+		"exchange": s.session.ExchangeName.String(),
+		"session":  s.session.Name,
+		"channel":  string(channel),
+		"symbol":   symbol,
+		"interval": string(interval),
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func drainReconnect(stream *types.StandardStream) bool {
+	select {
+	case <-stream.ReconnectC:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestStreamWatchdog(t *testing.T) {
+	now := time.Now()
+	session, stream := newTestBookMonitorSession()
+	stream.Subscribe(types.MarketTradeChannel, "BTCUSDT", types.SubscribeOptions{})
+	stream.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m})
+
+	watchdog := NewStreamWatchdog(StreamWatchdogConfig{
+		MaxSilence:          types.Duration(time.Minute),
+		DisableNotification: true,
+	})
+	watchdog.now = func() time.Time { return now }
+	watchdog.AddSession(session)
+
+	// the silence is not counted before connected
+	watchdog.now = func() time.Time { return now.Add(time.Hour) }
+	watchdog.CheckAll()
+	for _, status := range watchdog.Statuses() {
+		assert.True(t, status.Healthy)
+	}
+	assert.False(t, drainReconnect(stream))
+
+	watchdog.now = func() time.Time { return now }
+	stream.EmitConnect()
+	stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT"})
+	stream.EmitKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m})
+
+	// the trades are silent, the klines are allowed to be silent for one more interval
+	watchdog.now = func() time.Time { return now.Add(90 * time.Second) }
+	watchdog.CheckAll()
+
+	statuses := watchdog.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, types.KLineChannel, statuses[0].Channel)
+	assert.True(t, statuses[0].Healthy)
+	assert.Equal(t, types.MarketTradeChannel, statuses[1].Channel)
+	assert.False(t, statuses[1].Healthy)
+	assert.Equal(t, 1, statuses[1].Recoveries)
+
+	// the stream is resubscribed at first, the standard stream resubscribes by reconnecting
+	assert.True(t, drainReconnect(stream))
+
+	// the recovery is throttled by the cooldown
+	watchdog.now = func() time.Time { return now.Add(100 * time.Second) }
+	watchdog.CheckAll()
+	assert.False(t, drainReconnect(stream))
+
+	// still silent after the cooldown, reconnect
+	watchdog.now = func() time.Time { return now.Add(160 * time.Second) }
+	watchdog.CheckAll()
+	assert.True(t, drainReconnect(stream))
+	assert.Equal(t, 2, watchdog.Statuses()[1].Recoveries)
+
+	stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT"})
+	stream.EmitKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m})
+	watchdog.CheckAll()
+	for _, status := range watchdog.Statuses() {
+		assert.True(t, status.Healthy)
+		assert.Equal(t, 0, status.Recoveries)
+	}
+}
+
+func TestStreamWatchdog_ChannelMaxSilence(t *testing.T) {
+	now := time.Now()
+	session, stream := newTestBookMonitorSession()
+	stream.Subscribe(types.MarketTradeChannel, "BTCUSDT", types.SubscribeOptions{})
+
+	watchdog := NewStreamWatchdog(StreamWatchdogConfig{
+		MaxSilence: types.Duration(time.Minute),
+		ChannelMaxSilence: map[types.Channel]types.Duration{
+			types.MarketTradeChannel: types.Duration(10 * time.Minute),
+		},
+		DisableRecovery:     true,
+		DisableNotification: true,
+	})
+	watchdog.now = func() time.Time { return now }
+	watchdog.AddSession(session)
+	stream.EmitConnect()
+
+	watchdog.now = func() time.Time { return now.Add(5 * time.Minute) }
+	watchdog.CheckAll()
+	assert.True(t, watchdog.Statuses()[0].Healthy)
+
+	watchdog.now = func() time.Time { return now.Add(11 * time.Minute) }
+	watchdog.CheckAll()
+	assert.False(t, watchdog.Statuses()[0].Healthy)
+	assert.False(t, drainReconnect(stream))
+
+	// the nil watchdog has no status
+	var nilWatchdog *StreamWatchdog
+	assert.Nil(t, nilWatchdog.Statuses())
+}
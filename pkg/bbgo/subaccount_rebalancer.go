@@ -0,0 +1,107 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SubAccountTransfer is a transfer made by the SubAccountRebalancer
+type SubAccountTransfer struct {
+	SubAccount string                  `json:"subAccount"`
+	Asset      string                  `json:"asset"`
+	Amount     fixedpoint.Value        `json:"amount"`
+	Direction  types.TransferDirection `json:"direction"`
+}
+
+func (t SubAccountTransfer) String() string {
+	if t.Direction == types.TransferIn {
+		return fmt.Sprintf("transfer %s %s from master to %s", t.Amount.String(), t.Asset, t.SubAccount)
+	}
+	return fmt.Sprintf("transfer %s %s from %s to master", t.Amount.String(), t.Asset, t.SubAccount)
+}
+
+// SubAccountRebalancer moves the asset between the master account and the sub-accounts,
+// so that the balances of the sub-accounts match the target amounts.
+type SubAccountRebalancer struct {
+	Service types.SubAccountService
+
+	// MinTransferAmount skips the transfers smaller than the amount
+	MinTransferAmount fixedpoint.Value
+
+	// DryRun only returns the transfers without executing them
+	DryRun bool
+}
+
+func NewSubAccountRebalancer(session *ExchangeSession) (*SubAccountRebalancer, error) {
+	service, ok := session.Exchange.(types.SubAccountService)
+	if !ok {
+		return nil, fmt.Errorf("exchange %s does not support the sub-account service", session.ExchangeName)
+	}
+
+	return &SubAccountRebalancer{Service: service}, nil
+}
+
+// Rebalance transfers the asset between the master account and the sub-accounts to reach the target amounts.
+// The surpluses are transferred to the master account before the deficits are transferred out, so that the
+// master account can fund the deficits with the surpluses.
+func (r *SubAccountRebalancer) Rebalance(
+	ctx context.Context, asset string, targets map[string]fixedpoint.Value,
+) ([]SubAccountTransfer, error) {
+	subAccounts := make([]string, 0, len(targets))
+	for subAccount := range targets {
+		subAccounts = append(subAccounts, subAccount)
+	}
+	sort.Strings(subAccounts)
+
+	var surpluses, deficits []SubAccountTransfer
+	for _, subAccount := range subAccounts {
+		balances, err := r.Service.QuerySubAccountBalances(ctx, subAccount)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query the balances of the sub-account %s: %w", subAccount, err)
+		}
+
+		balance := balances[asset]
+		diff := targets[subAccount].Sub(balance.Total())
+		switch diff.Sign() {
+		case 1:
+			deficits = append(deficits, SubAccountTransfer{
+				SubAccount: subAccount, Asset: asset, Amount: diff, Direction: types.TransferIn,
+			})
+
+		case -1:
+			// the locked balance can't be transferred
+			amount := fixedpoint.Min(diff.Neg(), balance.Available)
+			surpluses = append(surpluses, SubAccountTransfer{
+				SubAccount: subAccount, Asset: asset, Amount: amount, Direction: types.TransferOut,
+			})
+		}
+	}
+
+	var transfers []SubAccountTransfer
+	for _, transfer := range append(surpluses, deficits...) {
+		if transfer.Amount.Sign() <= 0 || transfer.Amount.Compare(r.MinTransferAmount) < 0 {
+			continue
+		}
+
+		if r.DryRun {
+			log.Infof("[dry run] %s", transfer)
+			transfers = append(transfers, transfer)
+			continue
+		}
+
+		log.Infof("%s", transfer)
+		if err := r.Service.TransferSubAccountAsset(ctx, transfer.SubAccount, transfer.Asset, transfer.Amount, transfer.Direction); err != nil {
+			return transfers, fmt.Errorf("unable to %s: %w", transfer, err)
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
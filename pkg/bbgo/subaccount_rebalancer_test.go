@@ -0,0 +1,68 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testSubAccountService struct {
+	balances  map[string]types.BalanceMap
+	transfers []SubAccountTransfer
+}
+
+func (s *testSubAccountService) QuerySubAccounts(ctx context.Context) (subAccounts []types.SubAccount, err error) {
+	for name := range s.balances {
+		subAccounts = append(subAccounts, types.SubAccount{Name: name})
+	}
+	return subAccounts, nil
+}
+
+func (s *testSubAccountService) QuerySubAccountBalances(ctx context.Context, subAccount string) (types.BalanceMap, error) {
+	return s.balances[subAccount], nil
+}
+
+func (s *testSubAccountService) TransferSubAccountAsset(
+	ctx context.Context, subAccount, asset string, amount fixedpoint.Value, io types.TransferDirection,
+) error {
+	s.transfers = append(s.transfers, SubAccountTransfer{SubAccount: subAccount, Asset: asset, Amount: amount, Direction: io})
+	return nil
+}
+
+func TestSubAccountRebalancer_Rebalance(t *testing.T) {
+	service := &testSubAccountService{
+		balances: map[string]types.BalanceMap{
+			"a": {"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(100)}},
+			"b": {"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(500), Locked: fixedpoint.NewFromInt(200)}},
+			"c": {"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(299)}},
+		},
+	}
+
+	rebalancer := &SubAccountRebalancer{Service: service, MinTransferAmount: fixedpoint.NewFromInt(5)}
+	transfers, err := rebalancer.Rebalance(context.Background(), "USDT", map[string]fixedpoint.Value{
+		"a": fixedpoint.NewFromInt(300),
+		"b": fixedpoint.NewFromInt(100),
+		"c": fixedpoint.NewFromInt(300),
+		"d": fixedpoint.NewFromInt(50),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transfers, service.transfers)
+
+	// the surplus is transferred first and capped by the available balance, the transfer to c is too small
+	require.Len(t, transfers, 3)
+	assert.Equal(t, SubAccountTransfer{SubAccount: "b", Asset: "USDT", Amount: fixedpoint.NewFromInt(500), Direction: types.TransferOut}, transfers[0])
+	assert.Equal(t, SubAccountTransfer{SubAccount: "a", Asset: "USDT", Amount: fixedpoint.NewFromInt(200), Direction: types.TransferIn}, transfers[1])
+	assert.Equal(t, SubAccountTransfer{SubAccount: "d", Asset: "USDT", Amount: fixedpoint.NewFromInt(50), Direction: types.TransferIn}, transfers[2])
+
+	service.transfers = nil
+	rebalancer.DryRun = true
+	transfers, err = rebalancer.Rebalance(context.Background(), "USDT", map[string]fixedpoint.Value{"a": fixedpoint.Zero})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	assert.Empty(t, service.transfers)
+}
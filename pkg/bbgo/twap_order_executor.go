@@ -26,6 +26,9 @@ type TwapExecution struct {
 	UpdateInterval time.Duration
 	DeadlineTime   time.Time
 
+	// OrderExecutor submits the orders, it defaults to the session order executor
+	OrderExecutor OrderExecutor
+
 	market           types.Market
 	marketDataStream types.Stream
 
@@ -48,6 +51,8 @@ type TwapExecution struct {
 
 	state int
 
+	report ExecutionReport
+
 	mu sync.Mutex
 }
 
@@ -204,6 +209,14 @@ func (e *TwapExecution) updateOrder(ctx context.Context) error {
 	book := e.orderBook.Copy()
 	sideBook := book.SideBook(e.Side)
 
+	if bid, ask, ok := e.orderBook.BestBidAndAsk(); ok {
+		e.mu.Lock()
+		if e.report.ArrivalPrice.IsZero() {
+			e.report.ArrivalPrice = bid.Price.Add(ask.Price).Div(fixedpoint.Two)
+		}
+		e.mu.Unlock()
+	}
+
 	first, ok := sideBook.First()
 	if !ok {
 		return fmt.Errorf("empty %s %s side book", e.Symbol, e.Side)
@@ -273,13 +286,17 @@ func (e *TwapExecution) updateOrder(ctx context.Context) error {
 		return err
 	}
 
-	createdOrders, err := e.Session.OrderExecutor.SubmitOrders(ctx, orderForm)
+	createdOrders, err := e.OrderExecutor.SubmitOrders(ctx, orderForm)
 	if err != nil {
 		return err
 	}
 
 	e.activeMakerOrders.Add(createdOrders...)
 	e.orderStore.Add(createdOrders...)
+
+	e.mu.Lock()
+	e.report.NumOfOrders += len(createdOrders)
+	e.mu.Unlock()
 	return nil
 }
 
@@ -364,6 +381,10 @@ func (e *TwapExecution) handleTradeUpdate(trade types.Trade) {
 
 	e.position.AddTrade(trade)
 	log.Infof("position updated: %+v", e.position)
+
+	e.mu.Lock()
+	e.report.addTrade(trade)
+	e.mu.Unlock()
 }
 
 func (e *TwapExecution) handleFilledOrder(order types.Order) {
@@ -379,12 +400,22 @@ func (e *TwapExecution) Run(parentCtx context.Context) error {
 	e.stoppedC = make(chan struct{})
 	e.executionCtx, e.cancelExecution = context.WithCancel(parentCtx)
 	e.userDataStreamCtx, e.cancelUserDataStream = context.WithCancel(context.Background())
+	e.report = ExecutionReport{
+		Symbol:         e.Symbol,
+		Side:           e.Side,
+		TargetQuantity: e.TargetQuantity,
+		StartTime:      time.Now(),
+	}
 	e.mu.Unlock()
 
 	if e.UpdateInterval == 0 {
 		e.UpdateInterval = 10 * time.Second
 	}
 
+	if e.OrderExecutor == nil {
+		e.OrderExecutor = e.Session.OrderExecutor
+	}
+
 	var ok bool
 	e.market, ok = e.Session.Market(e.Symbol)
 	if !ok {
@@ -420,6 +451,7 @@ func (e *TwapExecution) Run(parentCtx context.Context) error {
 
 func (e *TwapExecution) emitDone() {
 	e.mu.Lock()
+	e.report.EndTime = time.Now()
 	if e.stoppedC == nil {
 		e.stoppedC = make(chan struct{})
 	}
@@ -466,3 +498,11 @@ func (e *TwapExecution) Shutdown(shutdownCtx context.Context) {
 		}
 	}
 }
+
+// Report returns the execution quality of the executed quantity
+func (e *TwapExecution) Report() *ExecutionReport {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	report := e.report
+	return &report
+}
@@ -5,15 +5,20 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/c9s/bbgo/pkg/exchange"
+	"github.com/c9s/bbgo/pkg/testing/conformance"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
-// go run ./cmd/bbgo kline --exchange=binance --symbol=BTCUSDT
+// go run -tags exchangetest ./cmd/bbgo exchange-test --exchange=binance --symbol=BTCUSDT --record=conformance.json
 var exchangeTestCmd = &cobra.Command{
 	Use:   "exchange-test",
 	Short: "test the exchange",
@@ -30,6 +35,29 @@ var exchangeTestCmd = &cobra.Command{
 			return err
 		}
 
+		symbol, err := cmd.Flags().GetString("symbol")
+		if err != nil {
+			return err
+		}
+
+		since, err := cmd.Flags().GetDuration("since")
+		if err != nil {
+			return err
+		}
+
+		recordFile, err := cmd.Flags().GetString("record")
+		if err != nil {
+			return err
+		}
+
+		// the api clients use the default transport if the transport is not specified, so the responses can be
+		// recorded as the fixtures of the conformance tests.
+		var recorder *httptesting.Recorder
+		if len(recordFile) > 0 {
+			recorder = httptesting.NewRecorder(http.DefaultTransport)
+			http.DefaultTransport = recorder
+		}
+
 		exMinimal, err := exchange.NewWithEnvVarPrefix(exchangeName, "")
 		if err != nil {
 			return err
@@ -45,11 +73,32 @@ var exchangeTestCmd = &cobra.Command{
 			log.Infof("types.ExchangeMarketDataService: ✅ (%T)", service)
 		}
 
-		if ex, ok := exMinimal.(types.Exchange); ok {
-			log.Infof("types.Exchange: ✅ (%T)", ex)
+		ex, ok := exMinimal.(types.Exchange)
+		if !ok {
+			return nil
+		}
+
+		log.Infof("types.Exchange: ✅ (%T)", ex)
+
+		if len(symbol) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		report := conformance.Run(ctx, ex, conformance.Config{
+			Symbol:    symbol,
+			StartTime: now.Add(-since),
+			EndTime:   now,
+		})
+		fmt.Print(report.String())
+
+		if recorder != nil {
+			if err := recorder.Save(recordFile); err != nil {
+				return err
+			}
+			log.Infof("%d responses are recorded to %s", len(recorder.Recordings()), recordFile)
 		}
 
-		_ = ctx
 		// cmdutil.WaitForSignal(ctx, syscall.SIGINT, syscall.SIGTERM)
 		return nil
 	},
@@ -57,6 +106,9 @@ var exchangeTestCmd = &cobra.Command{
 
 func init() {
 	exchangeTestCmd.Flags().String("exchange", "", "session name")
+	exchangeTestCmd.Flags().String("symbol", "", "the symbol of the conformance scenarios, the scenarios are skipped if it's empty")
+	exchangeTestCmd.Flags().Duration("since", 7*24*time.Hour, "the time range of the history scenarios")
+	exchangeTestCmd.Flags().String("record", "", "record the responses into the file as the conformance test fixtures")
 	exchangeTestCmd.MarkFlagRequired("exchange")
 
 	RootCmd.AddCommand(exchangeTestCmd)
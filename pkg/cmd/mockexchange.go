@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/cmd/cmdutil"
+	"github.com/c9s/bbgo/pkg/mockexchange"
+)
+
+// go run ./cmd/bbgo mock-exchange --mock-config config/mock-exchange.yaml
+var mockExchangeCmd = &cobra.Command{
+	Use:   "mock-exchange",
+	Short: "run a local binance compatible exchange server for integration testing",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile, err := cmd.Flags().GetString("mock-config")
+		if err != nil {
+			return err
+		}
+
+		bind, err := cmd.Flags().GetString("bind")
+		if err != nil {
+			return err
+		}
+
+		config, err := mockexchange.LoadConfig(configFile)
+		if err != nil {
+			return err
+		}
+
+		server, err := mockexchange.NewServer(config)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		errC := make(chan error, 1)
+		go func() {
+			errC <- server.Run(ctx, bind)
+		}()
+
+		log.Infof("set the following environment variables to connect the binance session to the mock exchange:")
+		log.Infof("  BINANCE_API_BASE_URL=http://%s", bind)
+		log.Infof("  BINANCE_API_WS_URL=ws://%s", bind)
+
+		go func() {
+			cmdutil.WaitForSignal(ctx, syscall.SIGINT, syscall.SIGTERM)
+			cancel()
+		}()
+
+		return <-errC
+	},
+}
+
+func init() {
+	mockExchangeCmd.Flags().String("mock-config", "config/mock-exchange.yaml", "the mock exchange config file")
+	mockExchangeCmd.Flags().String("bind", mockexchange.DefaultBindAddress, "the address the mock exchange server listens on")
+	RootCmd.AddCommand(mockExchangeCmd)
+}
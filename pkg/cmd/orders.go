@@ -146,14 +146,13 @@ var listOrdersCmd = &cobra.Command{
 }
 
 var executeOrderCmd = &cobra.Command{
-	Use:          "execute-order --session SESSION --symbol SYMBOL --side SIDE --target-quantity TOTAL_QUANTITY --slice-quantity SLICE_QUANTITY",
+	Use:          "execute-order --session SESSION --symbol SYMBOL --side SIDE --target-quantity TOTAL_QUANTITY [--algorithm twap|vwap|pov|iceberg|is] [--slice-quantity SLICE_QUANTITY]",
 	Short:        "execute buy/sell on the balance/position you have on specific symbol",
 	SilenceUsage: true,
 	PreRunE: cobraInitRequired([]string{
 		"symbol",
 		"side",
 		"target-quantity",
-		"slice-quantity",
 	}),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
@@ -199,15 +198,43 @@ var executeOrderCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		if len(sliceQuantityS) == 0 {
-			return errors.New("--slice-quantity can not be empty")
-		}
 
 		sliceQuantity, err := fixedpoint.NewFromString(sliceQuantityS)
 		if err != nil {
 			return err
 		}
 
+		algorithm, err := cmd.Flags().GetString("algorithm")
+		if err != nil {
+			return err
+		}
+
+		var numberFlags = map[string]fixedpoint.Value{
+			"max-lag":            fixedpoint.Zero,
+			"participation-rate": fixedpoint.Zero,
+			"display-quantity":   fixedpoint.Zero,
+		}
+		for name := range numberFlags {
+			s, err := cmd.Flags().GetString(name)
+			if err != nil {
+				return err
+			}
+
+			if numberFlags[name], err = fixedpoint.NewFromString(s); err != nil {
+				return fmt.Errorf("invalid --%s: %w", name, err)
+			}
+		}
+
+		urgency, err := cmd.Flags().GetFloat64("urgency")
+		if err != nil {
+			return err
+		}
+
+		volumeDays, err := cmd.Flags().GetInt("volume-days")
+		if err != nil {
+			return err
+		}
+
 		numOfPriceTicks, err := cmd.Flags().GetInt("price-ticks")
 		if err != nil {
 			return err
@@ -233,11 +260,6 @@ var executeOrderCmd = &cobra.Command{
 			return err
 		}
 
-		var deadlineTime time.Time
-		if deadlineDuration > 0 {
-			deadlineTime = time.Now().Add(deadlineDuration)
-		}
-
 		environ := bbgo.NewEnvironment()
 		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
 			return err
@@ -255,16 +277,23 @@ var executeOrderCmd = &cobra.Command{
 		executionCtx, cancelExecution := context.WithCancel(ctx)
 		defer cancelExecution()
 
-		execution := &bbgo.TwapExecution{
-			Session:        session,
-			Symbol:         symbol,
-			Side:           side,
-			TargetQuantity: targetQuantity,
-			SliceQuantity:  sliceQuantity,
-			StopPrice:      stopPrice,
-			NumOfTicks:     numOfPriceTicks,
-			UpdateInterval: updateInterval,
-			DeadlineTime:   deadlineTime,
+		config := &bbgo.ExecutionConfig{
+			Algorithm:         bbgo.ExecutionAlgorithm(algorithm),
+			Duration:          types.Duration(deadlineDuration),
+			SliceQuantity:     sliceQuantity,
+			PriceLimit:        stopPrice,
+			MaxLag:            numberFlags["max-lag"],
+			UpdateInterval:    types.Duration(updateInterval),
+			NumOfTicks:        numOfPriceTicks,
+			VolumeCurveDays:   volumeDays,
+			ParticipationRate: numberFlags["participation-rate"],
+			DisplayQuantity:   numberFlags["display-quantity"],
+			Urgency:           urgency,
+		}
+
+		execution, err := config.NewExecution(session, nil, symbol, side, targetQuantity)
+		if err != nil {
+			return err
 		}
 
 		if err := execution.Run(executionCtx); err != nil {
@@ -290,6 +319,7 @@ var executeOrderCmd = &cobra.Command{
 
 		}
 
+		log.Info(execution.Report().PlainText())
 		return nil
 	},
 }
@@ -417,11 +447,17 @@ func init() {
 	executeOrderCmd.Flags().String("symbol", "", "the trading pair, like btcusdt")
 	executeOrderCmd.Flags().String("side", "", "the trading side: buy or sell")
 	executeOrderCmd.Flags().String("target-quantity", "", "target quantity")
-	executeOrderCmd.Flags().String("slice-quantity", "", "slice quantity")
+	executeOrderCmd.Flags().String("slice-quantity", "0", "the quantity of each twap order, or the max order quantity of the other algorithms")
 	executeOrderCmd.Flags().String("stop-price", "0", "stop price")
 	executeOrderCmd.Flags().Duration("update-interval", time.Second*10, "order update time")
-	executeOrderCmd.Flags().Duration("deadline", 0, "deadline of the order execution")
+	executeOrderCmd.Flags().Duration("deadline", 0, "the duration until the deadline of the order execution, required by vwap and is")
 	executeOrderCmd.Flags().Int("price-ticks", 0, "the number of price tick for the jump spread, default to 0")
+	executeOrderCmd.Flags().String("algorithm", "twap", "the execution algorithm: twap, vwap, pov, iceberg or is")
+	executeOrderCmd.Flags().String("max-lag", "0", "the ratio of the target quantity the execution can fall behind the schedule before crossing the spread")
+	executeOrderCmd.Flags().String("participation-rate", "0", "the participation rate of the market volume, for pov")
+	executeOrderCmd.Flags().String("display-quantity", "0", "the displayed quantity of the order, for iceberg")
+	executeOrderCmd.Flags().Float64("urgency", 1.0, "the urgency of the implementation shortfall trajectory, for is")
+	executeOrderCmd.Flags().Int("volume-days", 7, "the number of the days of the volume curve, for vwap")
 
 	RootCmd.AddCommand(listOrdersCmd)
 	RootCmd.AddCommand(getOrderCmd)
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/streamrecorder"
+)
+
+// go run ./cmd/bbgo replay --config config/grid.yaml --journal binance.jsonl.gz
+var replayCmd = &cobra.Command{
+	Use:          "replay",
+	Short:        "replay the recorded stream events of the live sessions with the strategies",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		journals, err := cmd.Flags().GetStringArray("journal")
+		if err != nil {
+			return err
+		}
+
+		if len(journals) == 0 {
+			return errors.New("--journal option is required")
+		}
+
+		speed, err := cmd.Flags().GetFloat64("speed")
+		if err != nil {
+			return err
+		}
+
+		configFile, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		if len(configFile) == 0 {
+			return errors.New("--config option is required")
+		}
+
+		userConfig, err := bbgo.Load(configFile, true)
+		if err != nil {
+			return err
+		}
+
+		// the markets are loaded from the journal instead of the markets cache
+		if err := os.Setenv("DISABLE_MARKETS_CACHE", "true"); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		environ := bbgo.NewEnvironment()
+		if userConfig.Environment != nil {
+			environ.SetEnvironmentConfig(userConfig.Environment)
+		}
+
+		var replayers []*streamrecorder.Replayer
+		var startTime time.Time
+		for _, journal := range journals {
+			replayer, err := streamrecorder.LoadReplayer(journal)
+			if err != nil {
+				return errors.Wrapf(err, "unable to load the journal %s", journal)
+			}
+
+			replayers = append(replayers, replayer)
+
+			if startTime.IsZero() || replayer.StartTime().Before(startTime) {
+				startTime = replayer.StartTime()
+			}
+
+			info := replayer.Session
+			session := environ.AddExchange(info.Name, replayer.Exchange)
+			session.ExchangeName = info.Exchange
+			session.Margin = info.Margin
+			session.IsolatedMargin = info.IsolatedMargin
+			session.Futures = info.Futures
+			session.IsolatedFutures = info.IsolatedFutures
+			session.MarketDataStream = replayer.MarketDataStream
+			session.UserDataStream = replayer.UserDataStream
+
+			if sessionConfig, ok := userConfig.Sessions[info.Name]; ok {
+				session.UseHeikinAshi = sessionConfig.UseHeikinAshi
+				session.MakerFeeRate = sessionConfig.MakerFeeRate
+				session.TakerFeeRate = sessionConfig.TakerFeeRate
+			}
+		}
+
+		environ.SetStartTime(startTime)
+
+		if err := environ.Init(ctx); err != nil {
+			return err
+		}
+
+		trader := bbgo.NewTrader(environ)
+		if err := trader.Configure(userConfig); err != nil {
+			return err
+		}
+
+		if err := trader.Initialize(ctx); err != nil {
+			return err
+		}
+
+		if err := trader.Run(ctx); err != nil {
+			return err
+		}
+
+		log.Infof("replaying %d sessions from %s...", len(replayers), startTime)
+		if err := streamrecorder.ReplayAll(ctx, speed, replayers...); err != nil {
+			return err
+		}
+
+		log.Infof("replay completed, shutting down trader...")
+
+		gracefulShutdownPeriod := 30 * time.Second
+		shtCtx, cancelShutdown := context.WithTimeout(bbgo.NewTodoContextWithExistingIsolation(ctx), gracefulShutdownPeriod)
+		bbgo.Shutdown(shtCtx)
+		cancelShutdown()
+		return nil
+	},
+}
+
+func init() {
+	replayCmd.Flags().StringArray("journal", nil, "the journal file recorded by the session record option, can be specified multiple times")
+	replayCmd.Flags().Float64("speed", 0, "the replay speed relative to the recorded time, 0 replays the events as fast as possible")
+	RootCmd.AddCommand(replayCmd)
+}
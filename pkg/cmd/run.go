@@ -213,6 +213,10 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		log.WithError(err).Errorf("can not save strategy persistence states")
 	}
 
+	if err := environ.SaveIndicatorSnapshots(shtCtx); err != nil {
+		log.WithError(err).Errorf("can not save indicator snapshots")
+	}
+
 	cancelShutdown()
 
 	for _, session := range environ.Sessions() {
@@ -222,6 +226,9 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		if err := session.UserDataStream.Close(); err != nil {
 			log.WithError(err).Errorf("[%s] user data stream close error", session.Name)
 		}
+		if err := session.CloseRecorder(); err != nil {
+			log.WithError(err).Errorf("[%s] stream recorder close error", session.Name)
+		}
 	}
 
 	return nil
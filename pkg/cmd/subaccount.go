@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func init() {
+	transferCmd.PersistentFlags().String("session", "", "exchange session name")
+	transferCmd.PersistentFlags().Bool("yes", false, "skip the confirmation")
+
+	internalTransferCmd.Flags().String("asset", "", "asset")
+	internalTransferCmd.Flags().String("amount", "", "amount")
+	internalTransferCmd.Flags().String("from", "spot", "from account type: spot, margin, futures or funding")
+	internalTransferCmd.Flags().String("to", "", "to account type: spot, margin, futures or funding")
+	transferCmd.AddCommand(internalTransferCmd)
+
+	subAccountTransferCmd.Flags().String("sub-account", "", "sub-account name, the email of binance, the subAcct of okex or the member id of bybit")
+	subAccountTransferCmd.Flags().String("asset", "", "asset")
+	subAccountTransferCmd.Flags().String("amount", "", "amount")
+	subAccountTransferCmd.Flags().String("direction", "in", "in: master to sub-account, out: sub-account to master")
+	transferCmd.AddCommand(subAccountTransferCmd)
+
+	subAccountsCmd.Flags().Bool("balances", false, "query the balances of the sub-accounts")
+	transferCmd.AddCommand(subAccountsCmd)
+
+	RootCmd.AddCommand(transferCmd)
+}
+
+// go run ./cmd/bbgo transfer --session=binance
+var transferCmd = &cobra.Command{
+	Use:          "transfer",
+	Short:        "transfer assets between the accounts and the sub-accounts",
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := cobraLoadDotenv(cmd, args); err != nil {
+			return err
+		}
+
+		if err := cobraLoadConfig(cmd, args); err != nil {
+			return err
+		}
+
+		environ := bbgo.NewEnvironment()
+
+		if userConfig == nil {
+			return errors.New("user config is not loaded")
+		}
+
+		if err := environ.ConfigureExchangeSessions(userConfig); err != nil {
+			return err
+		}
+
+		sessionName, err := cmd.Flags().GetString("session")
+		if err != nil {
+			return err
+		}
+
+		session, ok := environ.Session(sessionName)
+		if !ok {
+			return fmt.Errorf("session %s not found", sessionName)
+		}
+
+		selectedSession = session
+		return nil
+	},
+}
+
+// go run ./cmd/bbgo transfer internal --session=binance --asset=USDT --amount=100 --from=spot --to=futures
+var internalTransferCmd = &cobra.Command{
+	Use:          "internal --session=SESSION_NAME --asset=ASSET --amount=AMOUNT --from=ACCOUNT_TYPE --to=ACCOUNT_TYPE",
+	Short:        "transfer the asset between the spot, margin, futures and funding accounts",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		asset, amount, err := transferAssetAmountFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		from, err := transferAccountTypeFlag(cmd, "from")
+		if err != nil {
+			return err
+		}
+
+		to, err := transferAccountTypeFlag(cmd, "to")
+		if err != nil {
+			return err
+		}
+
+		service, ok := selectedSession.Exchange.(types.InternalTransferService)
+		if !ok {
+			return fmt.Errorf("exchange %s does not support the internal transfer", selectedSession.ExchangeName)
+		}
+
+		if !transferConfirmed(cmd, fmt.Sprintf("transfer %s %s from %s to %s?", amount.String(), asset, from, to)) {
+			return nil
+		}
+
+		if err := service.InternalTransfer(ctx, asset, amount, from, to); err != nil {
+			return err
+		}
+
+		log.Infof("transferred %s %s from %s to %s", amount.String(), asset, from, to)
+		return nil
+	},
+}
+
+// go run ./cmd/bbgo transfer sub-account --session=binance --sub-account=SUB_ACCOUNT --asset=USDT --amount=100 --direction=in
+var subAccountTransferCmd = &cobra.Command{
+	Use:          "sub-account --session=SESSION_NAME --sub-account=SUB_ACCOUNT --asset=ASSET --amount=AMOUNT --direction=in|out",
+	Short:        "transfer the asset between the master account and the sub-account",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		subAccount, err := cmd.Flags().GetString("sub-account")
+		if err != nil {
+			return err
+		}
+
+		if subAccount == "" {
+			return errors.New("--sub-account is required")
+		}
+
+		asset, amount, err := transferAssetAmountFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		directionStr, err := cmd.Flags().GetString("direction")
+		if err != nil {
+			return err
+		}
+
+		var direction types.TransferDirection
+		var message string
+		switch strings.ToLower(directionStr) {
+		case "in":
+			direction = types.TransferIn
+			message = fmt.Sprintf("transfer %s %s from master to %s?", amount.String(), asset, subAccount)
+		case "out":
+			direction = types.TransferOut
+			message = fmt.Sprintf("transfer %s %s from %s to master?", amount.String(), asset, subAccount)
+		default:
+			return fmt.Errorf("invalid direction %q, valid directions: in, out", directionStr)
+		}
+
+		service, ok := selectedSession.Exchange.(types.SubAccountService)
+		if !ok {
+			return fmt.Errorf("exchange %s does not support the sub-account service", selectedSession.ExchangeName)
+		}
+
+		if !transferConfirmed(cmd, message) {
+			return nil
+		}
+
+		return service.TransferSubAccountAsset(ctx, subAccount, asset, amount, direction)
+	},
+}
+
+// go run ./cmd/bbgo transfer sub-accounts --session=binance --balances
+var subAccountsCmd = &cobra.Command{
+	Use:          "sub-accounts --session=SESSION_NAME",
+	Short:        "list the sub-accounts",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		showBalances, err := cmd.Flags().GetBool("balances")
+		if err != nil {
+			return err
+		}
+
+		service, ok := selectedSession.Exchange.(types.SubAccountService)
+		if !ok {
+			return fmt.Errorf("exchange %s does not support the sub-account service", selectedSession.ExchangeName)
+		}
+
+		subAccounts, err := service.QuerySubAccounts(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, subAccount := range subAccounts {
+			log.Infof("sub-account: %s label: %s frozen: %v created at: %s", subAccount.Name, subAccount.Label, subAccount.Frozen, subAccount.CreationTime)
+
+			if !showBalances {
+				continue
+			}
+
+			balances, err := service.QuerySubAccountBalances(ctx, subAccount.Name)
+			if err != nil {
+				return err
+			}
+
+			balances.Print()
+		}
+
+		return nil
+	},
+}
+
+func transferAssetAmountFlags(cmd *cobra.Command) (string, fixedpoint.Value, error) {
+	asset, err := cmd.Flags().GetString("asset")
+	if err != nil {
+		return "", fixedpoint.Zero, err
+	}
+
+	if asset == "" {
+		return "", fixedpoint.Zero, errors.New("--asset is required")
+	}
+
+	amountStr, err := cmd.Flags().GetString("amount")
+	if err != nil {
+		return "", fixedpoint.Zero, err
+	}
+
+	amount, err := fixedpoint.NewFromString(amountStr)
+	if err != nil {
+		return "", fixedpoint.Zero, fmt.Errorf("invalid amount %q: %w", amountStr, err)
+	}
+
+	if amount.Sign() <= 0 {
+		return "", fixedpoint.Zero, fmt.Errorf("amount %s must be positive", amountStr)
+	}
+
+	return strings.ToUpper(asset), amount, nil
+}
+
+func transferAccountTypeFlag(cmd *cobra.Command, name string) (types.AccountType, error) {
+	s, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return "", err
+	}
+
+	accountType := types.AccountType(strings.ToLower(s))
+	switch accountType {
+	case types.AccountTypeSpot, types.AccountTypeMargin, types.AccountTypeFutures, types.AccountTypeFunding:
+		return accountType, nil
+	}
+
+	return "", fmt.Errorf("invalid --%s account type %q, valid account types: spot, margin, futures, funding", name, s)
+}
+
+func transferConfirmed(cmd *cobra.Command, message string) bool {
+	yes, err := cmd.Flags().GetBool("yes")
+	if err == nil && yes {
+		return true
+	}
+
+	return confirmation(message)
+}
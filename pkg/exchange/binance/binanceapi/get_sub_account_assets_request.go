@@ -0,0 +1,30 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type SubAccountBalance struct {
+	Asset  string           `json:"asset"`
+	Free   fixedpoint.Value `json:"free"`
+	Locked fixedpoint.Value `json:"locked"`
+}
+
+type SubAccountAssets struct {
+	Balances []SubAccountBalance `json:"balances"`
+}
+
+// GetSubAccountAssetsRequest queries the spot assets of the sub-account
+//
+//go:generate requestgen -method GET -url "/sapi/v3/sub-account/assets" -type GetSubAccountAssetsRequest -responseType .SubAccountAssets
+type GetSubAccountAssetsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	email string `param:"email"`
+}
+
+func (c *RestClient) NewGetSubAccountAssetsRequest() *GetSubAccountAssetsRequest {
+	return &GetSubAccountAssetsRequest{client: c}
+}
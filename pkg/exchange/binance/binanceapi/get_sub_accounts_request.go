@@ -0,0 +1,33 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type SubAccount struct {
+	Email                       string                     `json:"email"`
+	IsFreeze                    bool                       `json:"isFreeze"`
+	CreateTime                  types.MillisecondTimestamp `json:"createTime"`
+	IsManagedSubAccount         bool                       `json:"isManagedSubAccount"`
+	IsAssetManagementSubAccount bool                       `json:"isAssetManagementSubAccount"`
+}
+
+type SubAccountList struct {
+	SubAccounts []SubAccount `json:"subAccounts"`
+}
+
+//go:generate requestgen -method GET -url "/sapi/v1/sub-account/list" -type GetSubAccountsRequest -responseType .SubAccountList
+type GetSubAccountsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	email    *string `param:"email"`
+	isFreeze *bool   `param:"isFreeze"`
+	page     *int    `param:"page"`
+	limit    *int    `param:"limit"`
+}
+
+func (c *RestClient) NewGetSubAccountsRequest() *GetSubAccountsRequest {
+	return &GetSubAccountsRequest{client: c}
+}
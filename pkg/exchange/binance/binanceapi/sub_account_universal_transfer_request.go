@@ -0,0 +1,39 @@
+package binanceapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+type SubAccountType string
+
+const (
+	SubAccountTypeSpot           SubAccountType = "SPOT"
+	SubAccountTypeUsdtFuture     SubAccountType = "USDT_FUTURE"
+	SubAccountTypeCoinFuture     SubAccountType = "COIN_FUTURE"
+	SubAccountTypeMargin         SubAccountType = "MARGIN"
+	SubAccountTypeIsolatedMargin SubAccountType = "ISOLATED_MARGIN"
+)
+
+// SubAccountUniversalTransferRequest transfers the asset between the master account and the sub-accounts,
+// the email of the master account is omitted.
+//
+//go:generate requestgen -method POST -url "/sapi/v1/sub-account/universalTransfer" -type SubAccountUniversalTransferRequest -responseType .TransferResponse
+type SubAccountUniversalTransferRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	fromEmail       *string        `param:"fromEmail"`
+	toEmail         *string        `param:"toEmail"`
+	fromAccountType SubAccountType `param:"fromAccountType"`
+	toAccountType   SubAccountType `param:"toAccountType"`
+
+	asset  string `param:"asset"`
+	amount string `param:"amount"`
+}
+
+func (c *RestClient) NewSubAccountUniversalTransferRequest() *SubAccountUniversalTransferRequest {
+	return &SubAccountUniversalTransferRequest{
+		client:          c,
+		fromAccountType: SubAccountTypeSpot,
+		toAccountType:   SubAccountTypeSpot,
+	}
+}
@@ -15,6 +15,16 @@ const (
 	TransferAssetTypeMarginToMain         TransferAssetType = "MARGIN_MAIN"
 	TransferAssetTypeMainToIsolatedMargin TransferAssetType = "MAIN_ISOLATED_MARGIN"
 	TransferAssetTypeIsolatedMarginToMain TransferAssetType = "ISOLATED_MARGIN_MAIN"
+	TransferAssetTypeMainToUMFuture       TransferAssetType = "MAIN_UMFUTURE"
+	TransferAssetTypeUMFutureToMain       TransferAssetType = "UMFUTURE_MAIN"
+	TransferAssetTypeMarginToUMFuture     TransferAssetType = "MARGIN_UMFUTURE"
+	TransferAssetTypeUMFutureToMargin     TransferAssetType = "UMFUTURE_MARGIN"
+	TransferAssetTypeMainToFunding        TransferAssetType = "MAIN_FUNDING"
+	TransferAssetTypeFundingToMain        TransferAssetType = "FUNDING_MAIN"
+	TransferAssetTypeMarginToFunding      TransferAssetType = "MARGIN_FUNDING"
+	TransferAssetTypeFundingToMargin      TransferAssetType = "FUNDING_MARGIN"
+	TransferAssetTypeUMFutureToFunding    TransferAssetType = "UMFUTURE_FUNDING"
+	TransferAssetTypeFundingToUMFuture    TransferAssetType = "FUNDING_UMFUTURE"
 )
 
 //go:generate requestgen -method POST -url "/sapi/v1/asset/transfer" -type TransferAssetRequest -responseType .TransferResponse
@@ -3,6 +3,7 @@ package binance
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -106,6 +107,14 @@ func New(key, secret string) *Exchange {
 		client.BaseURL = BinanceUSBaseURL
 	}
 
+	if override := os.Getenv("BINANCE_API_BASE_URL"); len(override) > 0 {
+		client.BaseURL = override
+	}
+
+	if override := os.Getenv("BINANCE_FUTURES_API_BASE_URL"); len(override) > 0 {
+		futuresClient.BaseURL = override
+	}
+
 	client2 := binanceapi.NewClient(client.BaseURL)
 	futuresClient2 := binanceapi.NewFuturesRestClient(futuresClient.BaseURL)
 
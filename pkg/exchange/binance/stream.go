@@ -3,6 +3,7 @@ package binance
 import (
 	"context"
 	"net"
+	"os"
 	"time"
 
 	"github.com/c9s/bbgo/pkg/depth"
@@ -298,13 +299,20 @@ func (s *Stream) getEndpointUrl(listenKey string) string {
 	var url string
 
 	if s.IsFutures {
-		url = FuturesWebSocketURL + "/ws"
+		url = FuturesWebSocketURL
+		if override := os.Getenv("BINANCE_FUTURES_API_WS_URL"); len(override) > 0 {
+			url = override
+		}
+	} else if override := os.Getenv("BINANCE_API_WS_URL"); len(override) > 0 {
+		url = override
 	} else if isBinanceUs() {
-		url = BinanceUSWebSocketURL + "/ws"
+		url = BinanceUSWebSocketURL
 	} else {
-		url = WebSocketURL + "/ws"
+		url = WebSocketURL
 	}
 
+	url += "/ws"
+
 	if !s.PublicOnly {
 		url += "/" + listenKey
 	}
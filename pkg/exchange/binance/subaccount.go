@@ -0,0 +1,113 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	_ types.SubAccountService       = &Exchange{}
+	_ types.InternalTransferService = &Exchange{}
+)
+
+// the wallet names used by the universal transfer api
+var accountTypeWallets = map[types.AccountType]string{
+	types.AccountTypeSpot:    "MAIN",
+	types.AccountTypeMargin:  "MARGIN",
+	types.AccountTypeFutures: "UMFUTURE",
+	types.AccountTypeFunding: "FUNDING",
+}
+
+func (e *Exchange) QuerySubAccounts(ctx context.Context) ([]types.SubAccount, error) {
+	const limit = 200
+
+	var subAccounts []types.SubAccount
+	for page := 1; ; page++ {
+		resp, err := e.client2.NewGetSubAccountsRequest().Page(page).Limit(limit).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range resp.SubAccounts {
+			subAccounts = append(subAccounts, types.SubAccount{
+				Name:         a.Email,
+				Frozen:       a.IsFreeze,
+				CreationTime: types.Time(a.CreateTime.Time()),
+			})
+		}
+
+		if len(resp.SubAccounts) < limit {
+			return subAccounts, nil
+		}
+	}
+}
+
+func (e *Exchange) QuerySubAccountBalances(ctx context.Context, subAccount string) (types.BalanceMap, error) {
+	resp, err := e.client2.NewGetSubAccountAssetsRequest().Email(subAccount).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := types.BalanceMap{}
+	for _, b := range resp.Balances {
+		balances[b.Asset] = types.Balance{
+			Currency:  b.Asset,
+			Available: b.Free,
+			Locked:    b.Locked,
+		}
+	}
+	return balances, nil
+}
+
+// TransferSubAccountAsset transfers the asset between the spot accounts of the master and the sub-account,
+// the email of the master account is omitted in the request.
+func (e *Exchange) TransferSubAccountAsset(
+	ctx context.Context, subAccount, asset string, amount fixedpoint.Value, io types.TransferDirection,
+) error {
+	req := e.client2.NewSubAccountUniversalTransferRequest()
+	req.Asset(asset)
+	req.Amount(amount.String())
+
+	switch io {
+	case types.TransferIn:
+		req.ToEmail(subAccount)
+	case types.TransferOut:
+		req.FromEmail(subAccount)
+	default:
+		return fmt.Errorf("unexpected transfer direction: %d given", io)
+	}
+
+	resp, err := req.Do(ctx)
+	return logResponse(resp, err, req)
+}
+
+// InternalTransfer transfers the asset between the spot, cross margin, USDT-M futures and funding wallets
+func (e *Exchange) InternalTransfer(
+	ctx context.Context, asset string, amount fixedpoint.Value, from, to types.AccountType,
+) error {
+	fromWallet, ok := accountTypeWallets[from]
+	if !ok {
+		return fmt.Errorf("unsupported transfer account type: %s", from)
+	}
+
+	toWallet, ok := accountTypeWallets[to]
+	if !ok {
+		return fmt.Errorf("unsupported transfer account type: %s", to)
+	}
+
+	if from == to {
+		return fmt.Errorf("can not transfer %s from %s to the same account", asset, from)
+	}
+
+	req := e.client2.NewTransferAssetRequest()
+	req.Asset(asset)
+	req.Amount(amount.String())
+	req.TransferType(binanceapi.TransferAssetType(fromWallet + "_" + toWallet))
+
+	resp, err := req.Do(ctx)
+	return logResponse(resp, err, req)
+}
@@ -0,0 +1,47 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type BorrowRepayResponse struct {
+	Coin   string           `json:"coin"`
+	Amount fixedpoint.Value `json:"amount"`
+}
+
+// BorrowRequest borrows the coin manually in the unified trading account.
+//
+//go:generate PostRequest -url "/v5/account/borrow" -type BorrowRequest -responseDataType .BorrowRepayResponse
+type BorrowRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	coin   string `param:"coin"`
+	amount string `param:"amount"`
+}
+
+func (c *RestClient) NewBorrowRequest() *BorrowRequest {
+	return &BorrowRequest{
+		client: c,
+	}
+}
+
+// RepayRequest repays the liability of the coin in the unified trading account.
+//
+//go:generate PostRequest -url "/v5/account/repay" -type RepayRequest -responseDataType .BorrowRepayResponse
+type RepayRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	coin   string `param:"coin"`
+	amount string `param:"amount"`
+}
+
+func (c *RestClient) NewRepayRequest() *RepayRequest {
+	return &RepayRequest{
+		client: c,
+	}
+}
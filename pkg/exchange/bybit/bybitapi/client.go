@@ -25,6 +25,7 @@ const (
 	RestBaseURL         = "https://api.bybit.com"
 	WsSpotPublicSpotUrl = "wss://stream.bybit.com/v5/public/spot"
 	WsSpotPrivateUrl    = "wss://stream.bybit.com/v5/private"
+	WsLinearPublicUrl   = "wss://stream.bybit.com/v5/public/linear"
 )
 
 // defaultRequestWindowMilliseconds specify how long an HTTP request is valid. It is also used to prevent replay attacks.
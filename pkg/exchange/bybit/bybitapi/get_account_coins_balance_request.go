@@ -0,0 +1,41 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type CoinBalance struct {
+	Coin            string           `json:"coin"`
+	WalletBalance   fixedpoint.Value `json:"walletBalance"`
+	TransferBalance fixedpoint.Value `json:"transferBalance"`
+	Bonus           fixedpoint.Value `json:"bonus"`
+}
+
+type AccountCoinsBalance struct {
+	AccountType AccountType   `json:"accountType"`
+	MemberId    string        `json:"memberId"`
+	Balance     []CoinBalance `json:"balance"`
+}
+
+// GetAccountCoinsBalanceRequest queries the coin balances of the account type of the master or the sub-account.
+//
+//go:generate GetRequest -url "/v5/asset/transfer/query-account-coins-balance" -type GetAccountCoinsBalanceRequest -responseDataType .AccountCoinsBalance
+type GetAccountCoinsBalanceRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	memberId    *string     `param:"memberId,query"`
+	accountType AccountType `param:"accountType,query"`
+	coin        *string     `param:"coin,query"`
+}
+
+func (c *RestClient) NewGetAccountCoinsBalanceRequest() *GetAccountCoinsBalanceRequest {
+	return &GetAccountCoinsBalanceRequest{
+		client:      c,
+		accountType: AccountTypeUnified,
+	}
+}
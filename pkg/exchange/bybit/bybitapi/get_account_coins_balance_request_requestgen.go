@@ -0,0 +1,207 @@
+// Code generated by "requestgen -method GET -responseType .APIResponse -responseDataField Result -url /v5/asset/transfer/query-account-coins-balance -type GetAccountCoinsBalanceRequest -responseDataType .AccountCoinsBalance"; DO NOT EDIT.
+
+package bybitapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (g *GetAccountCoinsBalanceRequest) MemberId(memberId string) *GetAccountCoinsBalanceRequest {
+	g.memberId = &memberId
+	return g
+}
+
+func (g *GetAccountCoinsBalanceRequest) AccountType(accountType AccountType) *GetAccountCoinsBalanceRequest {
+	g.accountType = accountType
+	return g
+}
+
+func (g *GetAccountCoinsBalanceRequest) Coin(coin string) *GetAccountCoinsBalanceRequest {
+	g.coin = &coin
+	return g
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (g *GetAccountCoinsBalanceRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check memberId field -> json key memberId
+	if g.memberId != nil {
+		memberId := *g.memberId
+
+		// assign parameter of memberId
+		params["memberId"] = memberId
+	} else {
+	}
+	// check accountType field -> json key accountType
+	accountType := g.accountType
+
+	// TEMPLATE check-valid-values
+	switch accountType {
+	case AccountTypeSpot, AccountTypeUnified, AccountTypeFund:
+		params["accountType"] = accountType
+
+	default:
+		return nil, fmt.Errorf("accountType value %v is invalid", accountType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of accountType
+	params["accountType"] = accountType
+	// check coin field -> json key coin
+	if g.coin != nil {
+		coin := *g.coin
+
+		// assign parameter of coin
+		params["coin"] = coin
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (g *GetAccountCoinsBalanceRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (g *GetAccountCoinsBalanceRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := g.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if g.isVarSlice(_v) {
+			g.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (g *GetAccountCoinsBalanceRequest) GetParametersJSON() ([]byte, error) {
+	params, err := g.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (g *GetAccountCoinsBalanceRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (g *GetAccountCoinsBalanceRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (g *GetAccountCoinsBalanceRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (g *GetAccountCoinsBalanceRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (g *GetAccountCoinsBalanceRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := g.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (g *GetAccountCoinsBalanceRequest) GetPath() string {
+	return "/v5/asset/transfer/query-account-coins-balance"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (g *GetAccountCoinsBalanceRequest) Do(ctx context.Context) (*AccountCoinsBalance, error) {
+
+	// no body params
+	var params interface{}
+	query, err := g.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = g.GetPath()
+
+	req, err := g.client.NewAuthenticatedRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data AccountCoinsBalance
+	if err := json.Unmarshal(apiResponse.Result, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,35 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type APIKeyInfo struct {
+	Id          string `json:"id"`
+	Note        string `json:"note"`
+	ApiKey      string `json:"apiKey"`
+	ReadOnly    int    `json:"readOnly"`
+	IsMaster    bool   `json:"isMaster"`
+	ParentUid   string `json:"parentUid"`
+	UserID      int64  `json:"userID"`
+	Unified     int    `json:"unified"`
+	Uta         int    `json:"uta"`
+	CreatedAt   string `json:"createdAt"`
+	ExpiredAt   string `json:"expiredAt"`
+	VipLevel    string `json:"vipLevel"`
+	MktMakerLvl string `json:"mktMakerLevel"`
+}
+
+// GetAPIKeyInfoRequest queries the information of the api key, the user id is used by the sub-account transfers.
+//
+//go:generate GetRequest -url "/v5/user/query-api" -type GetAPIKeyInfoRequest -responseDataType .APIKeyInfo
+type GetAPIKeyInfoRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewGetAPIKeyInfoRequest() *GetAPIKeyInfoRequest {
+	return &GetAPIKeyInfoRequest{client: c}
+}
@@ -0,0 +1,69 @@
+package bybitapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type ExecutionsResponse struct {
+	Category       Category    `json:"category"`
+	List           []Execution `json:"list"`
+	NextPageCursor string      `json:"nextPageCursor"`
+}
+
+type Execution struct {
+	Symbol      string           `json:"symbol"`
+	OrderId     string           `json:"orderId"`
+	OrderLinkId string           `json:"orderLinkId"`
+	Side        Side             `json:"side"`
+	OrderPrice  fixedpoint.Value `json:"orderPrice"`
+	OrderQty    fixedpoint.Value `json:"orderQty"`
+	LeavesQty   fixedpoint.Value `json:"leavesQty"`
+	OrderType   OrderType        `json:"orderType"`
+	ExecFee     fixedpoint.Value `json:"execFee"`
+	ExecId      string           `json:"execId"`
+	ExecPrice   fixedpoint.Value `json:"execPrice"`
+	ExecQty     fixedpoint.Value `json:"execQty"`
+	ExecType    ExecType         `json:"execType"`
+	ExecValue   fixedpoint.Value `json:"execValue"`
+	FeeRate     fixedpoint.Value `json:"feeRate"`
+	MarkPrice   fixedpoint.Value `json:"markPrice"`
+	IsMaker     bool             `json:"isMaker"`
+	ClosedSize  fixedpoint.Value `json:"closedSize"`
+
+	ExecTime types.MillisecondTimestamp `json:"execTime"`
+}
+
+//go:generate GetRequest -url "/v5/execution/list" -type GetExecutionsRequest -responseDataType .ExecutionsResponse
+type GetExecutionsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	category    Category  `param:"category,query" validValues:"linear"`
+	symbol      *string   `param:"symbol,query"`
+	orderId     *string   `param:"orderId,query"`
+	orderLinkId *string   `param:"orderLinkId,query"`
+	execType    *ExecType `param:"execType,query"`
+
+	// startTime and endTime interval should be less than 7 days, the past 7 days data is returned by default
+	startTime *time.Time `param:"startTime,query,milliseconds"`
+	endTime   *time.Time `param:"endTime,query,milliseconds"`
+
+	// limit for data size per page. [1, 100]. Default: 50
+	limit  *uint64 `param:"limit,query"`
+	cursor *string `param:"cursor,query"`
+}
+
+// NewGetExecutionsRequest is descending order by execTime
+func (c *RestClient) NewGetExecutionsRequest() *GetExecutionsRequest {
+	return &GetExecutionsRequest{
+		client:   c,
+		category: CategoryLinear,
+	}
+}
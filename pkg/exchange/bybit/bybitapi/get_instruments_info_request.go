@@ -10,8 +10,9 @@ import (
 //go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
 
 type InstrumentsInfo struct {
-	Category Category     `json:"category"`
-	List     []Instrument `json:"list"`
+	Category       Category     `json:"category"`
+	List           []Instrument `json:"list"`
+	NextPageCursor string       `json:"nextPageCursor"`
 }
 
 type Instrument struct {
@@ -21,6 +22,14 @@ type Instrument struct {
 	Innovation    string `json:"innovation"`
 	Status        Status `json:"status"`
 	MarginTrading string `json:"marginTrading"`
+
+	// The following fields are only available in the linear category
+	ContractType    string           `json:"contractType"`
+	SettleCoin      string           `json:"settleCoin"`
+	FundingInterval int              `json:"fundingInterval"`
+	LeverageFilter  *LeverageFilter  `json:"leverageFilter,omitempty"`
+	PriceScale      fixedpoint.Value `json:"priceScale"`
+
 	LotSizeFilter struct {
 		BasePrecision  fixedpoint.Value `json:"basePrecision"`
 		QuotePrecision fixedpoint.Value `json:"quotePrecision"`
@@ -28,18 +37,33 @@ type Instrument struct {
 		MaxOrderQty    fixedpoint.Value `json:"maxOrderQty"`
 		MinOrderAmt    fixedpoint.Value `json:"minOrderAmt"`
 		MaxOrderAmt    fixedpoint.Value `json:"maxOrderAmt"`
+
+		// QtyStep is the step to increase/reduce order quantity, linear only
+		QtyStep fixedpoint.Value `json:"qtyStep"`
+		// MinNotionalValue is the minimum notional value, linear only
+		MinNotionalValue fixedpoint.Value `json:"minNotionalValue"`
 	} `json:"lotSizeFilter"`
 
 	PriceFilter struct {
 		TickSize fixedpoint.Value `json:"tickSize"`
+
+		// MinPrice and MaxPrice are only available in the linear category
+		MinPrice fixedpoint.Value `json:"minPrice"`
+		MaxPrice fixedpoint.Value `json:"maxPrice"`
 	} `json:"priceFilter"`
 }
 
+type LeverageFilter struct {
+	MinLeverage  fixedpoint.Value `json:"minLeverage"`
+	MaxLeverage  fixedpoint.Value `json:"maxLeverage"`
+	LeverageStep fixedpoint.Value `json:"leverageStep"`
+}
+
 //go:generate GetRequest -url "/v5/market/instruments-info" -type GetInstrumentsInfoRequest -responseDataType .InstrumentsInfo
 type GetInstrumentsInfoRequest struct {
 	client requestgen.APIClient
 
-	category Category `param:"category,query" validValues:"spot"`
+	category Category `param:"category,query" validValues:"spot,linear"`
 	symbol   *string  `param:"symbol,query"`
 
 	// limit is invalid if category spot.
@@ -0,0 +1,66 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type PositionInfoResponse struct {
+	Category       Category       `json:"category"`
+	List           []PositionInfo `json:"list"`
+	NextPageCursor string         `json:"nextPageCursor"`
+}
+
+type PositionInfo struct {
+	// PositionIdx is used to identify positions in different position modes.
+	// 0: one-way mode, 1: hedge-mode Buy side, 2: hedge-mode Sell side
+	PositionIdx int          `json:"positionIdx"`
+	Symbol      string       `json:"symbol"`
+	Side        PositionSide `json:"side"`
+	// Size is the position size, it's always positive
+	Size          fixedpoint.Value `json:"size"`
+	AvgPrice      fixedpoint.Value `json:"avgPrice"`
+	PositionValue fixedpoint.Value `json:"positionValue"`
+	// TradeMode 0: cross margin, 1: isolated margin
+	TradeMode      int              `json:"tradeMode"`
+	PositionStatus string           `json:"positionStatus"`
+	Leverage       fixedpoint.Value `json:"leverage"`
+	MarkPrice      fixedpoint.Value `json:"markPrice"`
+	LiqPrice       fixedpoint.Value `json:"liqPrice"`
+	BustPrice      fixedpoint.Value `json:"bustPrice"`
+	PositionIM     fixedpoint.Value `json:"positionIM"`
+	PositionMM     fixedpoint.Value `json:"positionMM"`
+	TakeProfit     fixedpoint.Value `json:"takeProfit"`
+	StopLoss       fixedpoint.Value `json:"stopLoss"`
+	UnrealisedPnl  fixedpoint.Value `json:"unrealisedPnl"`
+	CumRealisedPnl fixedpoint.Value `json:"cumRealisedPnl"`
+
+	CreatedTime types.MillisecondTimestamp `json:"createdTime"`
+	UpdatedTime types.MillisecondTimestamp `json:"updatedTime"`
+}
+
+//go:generate GetRequest -url "/v5/position/list" -type GetPositionInfoRequest -responseDataType .PositionInfoResponse
+type GetPositionInfoRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	category Category `param:"category,query" validValues:"linear"`
+	symbol   *string  `param:"symbol,query"`
+	// settleCoin is required if the symbol is not given
+	settleCoin *string `param:"settleCoin,query"`
+
+	// limit for data size per page. [1, 200]. Default: 20
+	limit  *uint64 `param:"limit,query"`
+	cursor *string `param:"cursor,query"`
+}
+
+func (c *RestClient) NewGetPositionInfoRequest() *GetPositionInfoRequest {
+	return &GetPositionInfoRequest{
+		client:   c,
+		category: CategoryLinear,
+	}
+}
@@ -0,0 +1,40 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type SubMemberStatus int
+
+const (
+	SubMemberStatusNormal      SubMemberStatus = 1
+	SubMemberStatusLoginBanned SubMemberStatus = 2
+	SubMemberStatusFrozen      SubMemberStatus = 4
+)
+
+type SubMember struct {
+	Uid         string          `json:"uid"`
+	Username    string          `json:"username"`
+	MemberType  int             `json:"memberType"`
+	Status      SubMemberStatus `json:"status"`
+	AccountMode int             `json:"accountMode"`
+	Remark      string          `json:"remark"`
+}
+
+type SubMembersResponse struct {
+	SubMembers []SubMember `json:"subMembers"`
+}
+
+// GetSubMembersRequest queries the sub-accounts of the master account
+//
+//go:generate GetRequest -url "/v5/user/query-sub-members" -type GetSubMembersRequest -responseDataType .SubMembersResponse
+type GetSubMembersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+}
+
+func (c *RestClient) NewGetSubMembersRequest() *GetSubMembersRequest {
+	return &GetSubMembersRequest{client: c}
+}
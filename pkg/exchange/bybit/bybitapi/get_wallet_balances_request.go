@@ -77,7 +77,7 @@ type GetWalletBalancesRequest struct {
 	// Account type
 	// - Unified account: UNIFIED (trade spot/linear/options), CONTRACT(trade inverse)
 	// - Normal account: CONTRACT, SPOT
-	accountType AccountType `param:"accountType,query" validValues:"SPOT"`
+	accountType AccountType `param:"accountType,query" validValues:"SPOT,UNIFIED"`
 	// Coin name
 	// - If not passed, it returns non-zero asset info
 	// - You can pass multiple coins to query, separated by comma. USDT,USDC
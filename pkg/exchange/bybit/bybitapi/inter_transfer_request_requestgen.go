@@ -0,0 +1,231 @@
+// Code generated by "requestgen -method POST -responseType .APIResponse -responseDataField Result -url /v5/asset/transfer/inter-transfer -type InterTransferRequest -responseDataType .TransferResponse"; DO NOT EDIT.
+
+package bybitapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (i *InterTransferRequest) TransferId(transferId string) *InterTransferRequest {
+	i.transferId = transferId
+	return i
+}
+
+func (i *InterTransferRequest) Coin(coin string) *InterTransferRequest {
+	i.coin = coin
+	return i
+}
+
+func (i *InterTransferRequest) Amount(amount string) *InterTransferRequest {
+	i.amount = amount
+	return i
+}
+
+func (i *InterTransferRequest) FromAccountType(fromAccountType AccountType) *InterTransferRequest {
+	i.fromAccountType = fromAccountType
+	return i
+}
+
+func (i *InterTransferRequest) ToAccountType(toAccountType AccountType) *InterTransferRequest {
+	i.toAccountType = toAccountType
+	return i
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (i *InterTransferRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (i *InterTransferRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check transferId field -> json key transferId
+	transferId := i.transferId
+
+	// assign parameter of transferId
+	params["transferId"] = transferId
+	// check coin field -> json key coin
+	coin := i.coin
+
+	// assign parameter of coin
+	params["coin"] = coin
+	// check amount field -> json key amount
+	amount := i.amount
+
+	// assign parameter of amount
+	params["amount"] = amount
+	// check fromAccountType field -> json key fromAccountType
+	fromAccountType := i.fromAccountType
+
+	// TEMPLATE check-valid-values
+	switch fromAccountType {
+	case AccountTypeSpot, AccountTypeUnified, AccountTypeFund:
+		params["fromAccountType"] = fromAccountType
+
+	default:
+		return nil, fmt.Errorf("fromAccountType value %v is invalid", fromAccountType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of fromAccountType
+	params["fromAccountType"] = fromAccountType
+	// check toAccountType field -> json key toAccountType
+	toAccountType := i.toAccountType
+
+	// TEMPLATE check-valid-values
+	switch toAccountType {
+	case AccountTypeSpot, AccountTypeUnified, AccountTypeFund:
+		params["toAccountType"] = toAccountType
+
+	default:
+		return nil, fmt.Errorf("toAccountType value %v is invalid", toAccountType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of toAccountType
+	params["toAccountType"] = toAccountType
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (i *InterTransferRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := i.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if i.isVarSlice(_v) {
+			i.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (i *InterTransferRequest) GetParametersJSON() ([]byte, error) {
+	params, err := i.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (i *InterTransferRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (i *InterTransferRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (i *InterTransferRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (i *InterTransferRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (i *InterTransferRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := i.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (i *InterTransferRequest) GetPath() string {
+	return "/v5/asset/transfer/inter-transfer"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (i *InterTransferRequest) Do(ctx context.Context) (*TransferResponse, error) {
+
+	params, err := i.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = i.GetPath()
+
+	req, err := i.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := i.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data TransferResponse
+	if err := json.Unmarshal(apiResponse.Result, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -16,7 +16,7 @@ type PlaceOrderResponse struct {
 type PlaceOrderRequest struct {
 	client requestgen.AuthenticatedAPIClient
 
-	category    Category    `param:"category" validValues:"spot"`
+	category    Category    `param:"category" validValues:"spot,linear"`
 	symbol      string      `param:"symbol"`
 	side        Side        `param:"side" validValues:"Buy,Sell"`
 	orderType   OrderType   `param:"orderType" validValues:"Market,Limit"`
@@ -24,7 +24,8 @@ type PlaceOrderRequest struct {
 	orderLinkId string      `param:"orderLinkId"`
 	timeInForce TimeInForce `param:"timeInForce"`
 
-	isLeverage       *bool   `param:"isLeverage"`
+	// isLeverage is valid for the unified spot trading only. 0 (default): false then spot trading, 1: true then margin trading
+	isLeverage       *int    `param:"isLeverage"`
 	price            *string `param:"price"`
 	triggerDirection *int    `param:"triggerDirection"`
 	// orderFilter default spot
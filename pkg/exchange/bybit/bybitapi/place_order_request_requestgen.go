@@ -46,7 +46,7 @@ func (p *PlaceOrderRequest) TimeInForce(timeInForce TimeInForce) *PlaceOrderRequ
 	return p
 }
 
-func (p *PlaceOrderRequest) IsLeverage(isLeverage bool) *PlaceOrderRequest {
+func (p *PlaceOrderRequest) IsLeverage(isLeverage int) *PlaceOrderRequest {
 	p.isLeverage = &isLeverage
 	return p
 }
@@ -171,7 +171,7 @@ func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 
 	// TEMPLATE check-valid-values
 	switch category {
-	case "spot":
+	case "spot", "linear":
 		params["category"] = category
 
 	default:
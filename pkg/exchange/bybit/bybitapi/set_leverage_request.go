@@ -0,0 +1,28 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type SetLeverageResponse struct{}
+
+//go:generate PostRequest -url "/v5/position/set-leverage" -type SetLeverageRequest -responseDataType .SetLeverageResponse
+type SetLeverageRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	category Category `param:"category" validValues:"linear"`
+	symbol   string   `param:"symbol"`
+	// buyLeverage and sellLeverage must be the same in the one-way mode
+	buyLeverage  string `param:"buyLeverage"`
+	sellLeverage string `param:"sellLeverage"`
+}
+
+func (c *RestClient) NewSetLeverageRequest() *SetLeverageRequest {
+	return &SetLeverageRequest{
+		client:   c,
+		category: CategoryLinear,
+	}
+}
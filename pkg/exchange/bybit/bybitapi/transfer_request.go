@@ -0,0 +1,59 @@
+package bybitapi
+
+import (
+	"github.com/c9s/requestgen"
+	"github.com/google/uuid"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Result
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Result
+
+type TransferResponse struct {
+	TransferId string `json:"transferId"`
+	Status     string `json:"status"`
+}
+
+// InterTransferRequest transfers the coin between the account types of the same uid.
+//
+//go:generate PostRequest -url "/v5/asset/transfer/inter-transfer" -type InterTransferRequest -responseDataType .TransferResponse
+type InterTransferRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	transferId      string      `param:"transferId"`
+	coin            string      `param:"coin"`
+	amount          string      `param:"amount"`
+	fromAccountType AccountType `param:"fromAccountType"`
+	toAccountType   AccountType `param:"toAccountType"`
+}
+
+func (c *RestClient) NewInterTransferRequest() *InterTransferRequest {
+	return &InterTransferRequest{
+		client:     c,
+		transferId: uuid.NewString(),
+	}
+}
+
+// UniversalTransferRequest transfers the coin between the master and the sub-accounts, it's only available to the
+// api key of the master account.
+//
+//go:generate PostRequest -url "/v5/asset/transfer/universal-transfer" -type UniversalTransferRequest -responseDataType .TransferResponse
+type UniversalTransferRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	transferId      string      `param:"transferId"`
+	coin            string      `param:"coin"`
+	amount          string      `param:"amount"`
+	fromMemberId    int64       `param:"fromMemberId"`
+	toMemberId      int64       `param:"toMemberId"`
+	fromAccountType AccountType `param:"fromAccountType"`
+	toAccountType   AccountType `param:"toAccountType"`
+}
+
+func (c *RestClient) NewUniversalTransferRequest() *UniversalTransferRequest {
+	return &UniversalTransferRequest{
+		client:          c,
+		transferId:      uuid.NewString(),
+		fromAccountType: AccountTypeUnified,
+		toAccountType:   AccountTypeUnified,
+	}
+}
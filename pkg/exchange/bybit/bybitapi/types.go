@@ -40,6 +40,8 @@ type Category string
 
 const (
 	CategorySpot Category = "spot"
+	// CategoryLinear is the USDT/USDC perpetual and futures category
+	CategoryLinear Category = "linear"
 )
 
 type Status string
@@ -127,4 +129,30 @@ const (
 
 type AccountType string
 
-const AccountTypeSpot AccountType = "SPOT"
+const (
+	AccountTypeSpot AccountType = "SPOT"
+	// AccountTypeUnified is the unified trading account, which trades spot, margin and linear contracts.
+	AccountTypeUnified AccountType = "UNIFIED"
+	// AccountTypeFund is the funding account, which is used for the deposits, withdrawals and transfers.
+	AccountTypeFund AccountType = "FUND"
+)
+
+type ExecType string
+
+const (
+	ExecTypeTrade    ExecType = "Trade"
+	ExecTypeAdlTrade ExecType = "AdlTrade"
+	// ExecTypeFunding is the funding fee settlement of the linear perpetual contracts
+	ExecTypeFunding   ExecType = "Funding"
+	ExecTypeBustTrade ExecType = "BustTrade"
+	ExecTypeSettle    ExecType = "Settle"
+)
+
+type PositionSide string
+
+const (
+	PositionSideBuy  PositionSide = "Buy"
+	PositionSideSell PositionSide = "Sell"
+	// PositionSideNone means the position is empty
+	PositionSideNone PositionSide = ""
+)
@@ -0,0 +1,251 @@
+// Code generated by "requestgen -method POST -responseType .APIResponse -responseDataField Result -url /v5/asset/transfer/universal-transfer -type UniversalTransferRequest -responseDataType .TransferResponse"; DO NOT EDIT.
+
+package bybitapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (u *UniversalTransferRequest) TransferId(transferId string) *UniversalTransferRequest {
+	u.transferId = transferId
+	return u
+}
+
+func (u *UniversalTransferRequest) Coin(coin string) *UniversalTransferRequest {
+	u.coin = coin
+	return u
+}
+
+func (u *UniversalTransferRequest) Amount(amount string) *UniversalTransferRequest {
+	u.amount = amount
+	return u
+}
+
+func (u *UniversalTransferRequest) FromMemberId(fromMemberId int64) *UniversalTransferRequest {
+	u.fromMemberId = fromMemberId
+	return u
+}
+
+func (u *UniversalTransferRequest) ToMemberId(toMemberId int64) *UniversalTransferRequest {
+	u.toMemberId = toMemberId
+	return u
+}
+
+func (u *UniversalTransferRequest) FromAccountType(fromAccountType AccountType) *UniversalTransferRequest {
+	u.fromAccountType = fromAccountType
+	return u
+}
+
+func (u *UniversalTransferRequest) ToAccountType(toAccountType AccountType) *UniversalTransferRequest {
+	u.toAccountType = toAccountType
+	return u
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (u *UniversalTransferRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (u *UniversalTransferRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check transferId field -> json key transferId
+	transferId := u.transferId
+
+	// assign parameter of transferId
+	params["transferId"] = transferId
+	// check coin field -> json key coin
+	coin := u.coin
+
+	// assign parameter of coin
+	params["coin"] = coin
+	// check amount field -> json key amount
+	amount := u.amount
+
+	// assign parameter of amount
+	params["amount"] = amount
+	// check fromMemberId field -> json key fromMemberId
+	fromMemberId := u.fromMemberId
+
+	// assign parameter of fromMemberId
+	params["fromMemberId"] = fromMemberId
+	// check toMemberId field -> json key toMemberId
+	toMemberId := u.toMemberId
+
+	// assign parameter of toMemberId
+	params["toMemberId"] = toMemberId
+	// check fromAccountType field -> json key fromAccountType
+	fromAccountType := u.fromAccountType
+
+	// TEMPLATE check-valid-values
+	switch fromAccountType {
+	case AccountTypeSpot, AccountTypeUnified, AccountTypeFund:
+		params["fromAccountType"] = fromAccountType
+
+	default:
+		return nil, fmt.Errorf("fromAccountType value %v is invalid", fromAccountType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of fromAccountType
+	params["fromAccountType"] = fromAccountType
+	// check toAccountType field -> json key toAccountType
+	toAccountType := u.toAccountType
+
+	// TEMPLATE check-valid-values
+	switch toAccountType {
+	case AccountTypeSpot, AccountTypeUnified, AccountTypeFund:
+		params["toAccountType"] = toAccountType
+
+	default:
+		return nil, fmt.Errorf("toAccountType value %v is invalid", toAccountType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of toAccountType
+	params["toAccountType"] = toAccountType
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (u *UniversalTransferRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := u.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if u.isVarSlice(_v) {
+			u.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (u *UniversalTransferRequest) GetParametersJSON() ([]byte, error) {
+	params, err := u.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (u *UniversalTransferRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (u *UniversalTransferRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (u *UniversalTransferRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (u *UniversalTransferRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (u *UniversalTransferRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := u.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (u *UniversalTransferRequest) GetPath() string {
+	return "/v5/asset/transfer/universal-transfer"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (u *UniversalTransferRequest) Do(ctx context.Context) (*TransferResponse, error) {
+
+	params, err := u.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = u.GetPath()
+
+	req, err := u.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := u.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data TransferResponse
+	if err := json.Unmarshal(apiResponse.Result, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -328,15 +328,31 @@ func v3ToGlobalTrade(trade v3.Trade) (*types.Trade, error) {
 func toGlobalBalanceMap(events []bybitapi.WalletBalances) types.BalanceMap {
 	bm := types.BalanceMap{}
 	for _, event := range events {
-		if event.AccountType != bybitapi.AccountTypeSpot {
-			continue
-		}
+		switch event.AccountType {
+		case bybitapi.AccountTypeSpot:
+			for _, obj := range event.Coins {
+				bm[obj.Coin] = types.Balance{
+					Currency:  obj.Coin,
+					Available: obj.Free,
+					Locked:    obj.Locked,
+				}
+			}
 
-		for _, obj := range event.Coins {
-			bm[obj.Coin] = types.Balance{
-				Currency:  obj.Coin,
-				Available: obj.Free,
-				Locked:    obj.Locked,
+		case bybitapi.AccountTypeUnified:
+			// the unified account doesn't provide the free balance, the locked balance includes the spot order
+			// locked balance and the initial margin of the derivatives orders.
+			for _, obj := range event.Coins {
+				locked := obj.Locked.Add(obj.TotalOrderIM)
+				available := obj.WalletBalance.Sub(locked)
+				bm[obj.Coin] = types.Balance{
+					Currency:          obj.Coin,
+					Available:         available,
+					Locked:            locked,
+					Borrowed:          obj.BorrowAmount,
+					Interest:          obj.AccruedInterest,
+					NetAsset:          available.Add(locked).Sub(obj.BorrowAmount).Sub(obj.AccruedInterest),
+					MaxWithdrawAmount: obj.AvailableToWithdraw,
+				}
 			}
 		}
 	}
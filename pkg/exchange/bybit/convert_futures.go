@@ -0,0 +1,199 @@
+package bybit
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// hashStringID converts the UUID format id of the linear category into uint64.
+// linear and inverse order id format: 42f4f364-82e1-49d3-ad1d-cd8cf9aa308d (UUID format)
+func hashStringID(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// settleCoinOfSymbol returns the settle coin of the linear contract.
+// USDT perpetual contracts are settled in USDT, USDC contracts (BTCPERP, BTC-26JUN26) are settled in USDC.
+func settleCoinOfSymbol(symbol string) string {
+	if strings.HasSuffix(symbol, "USDT") {
+		return "USDT"
+	}
+
+	return "USDC"
+}
+
+func toGlobalFuturesMarket(m bybitapi.Instrument) types.Market {
+	return types.Market{
+		Exchange:        types.ExchangeBybit,
+		Symbol:          m.Symbol,
+		LocalSymbol:     m.Symbol,
+		PricePrecision:  m.PriceFilter.TickSize.NumFractionalDigits(),
+		VolumePrecision: m.LotSizeFilter.QtyStep.NumFractionalDigits(),
+		QuoteCurrency:   m.QuoteCoin,
+		BaseCurrency:    m.BaseCoin,
+		MinNotional:     m.LotSizeFilter.MinNotionalValue,
+		MinAmount:       m.LotSizeFilter.MinNotionalValue,
+
+		// quantity
+		MinQuantity: m.LotSizeFilter.MinOrderQty,
+		MaxQuantity: m.LotSizeFilter.MaxOrderQty,
+		StepSize:    m.LotSizeFilter.QtyStep,
+
+		// price
+		MinPrice: m.PriceFilter.MinPrice,
+		MaxPrice: m.PriceFilter.MaxPrice,
+		TickSize: m.PriceFilter.TickSize,
+	}
+}
+
+// toGlobalFuturesOrder converts the order of the linear category, unlike the spot order, the quantity of
+// the market buy order is always in base coin.
+func toGlobalFuturesOrder(order bybitapi.Order) (*types.Order, error) {
+	side, err := toGlobalSideType(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType, err := toGlobalOrderType(order.OrderType)
+	if err != nil {
+		return nil, err
+	}
+
+	timeInForce, err := toGlobalTimeInForce(order.TimeInForce)
+	if err != nil {
+		return nil, err
+	}
+
+	var status types.OrderStatus
+	if order.OrderStatus == bybitapi.OrderStatusPartiallyFilledCanceled {
+		status = types.OrderStatusCanceled
+	} else {
+		status, err = processOtherOrderStatus(order.OrderStatus)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: order.OrderLinkId,
+			Symbol:        order.Symbol,
+			Side:          side,
+			Type:          orderType,
+			Quantity:      order.Qty,
+			Price:         order.Price,
+			TimeInForce:   timeInForce,
+			ReduceOnly:    order.ReduceOnly,
+		},
+		Exchange:         types.ExchangeBybit,
+		OrderID:          hashStringID(order.OrderId),
+		UUID:             order.OrderId,
+		Status:           status,
+		ExecutedQuantity: order.CumExecQty,
+		IsWorking:        status == types.OrderStatusNew || status == types.OrderStatusPartiallyFilled,
+		CreationTime:     types.Time(order.CreatedTime.Time()),
+		UpdateTime:       types.Time(order.UpdatedTime.Time()),
+		IsFutures:        true,
+	}, nil
+}
+
+func toGlobalFuturesTrade(exec bybitapi.Execution) (*types.Trade, error) {
+	side, err := toGlobalSideType(exec.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Trade{
+		ID:            hashStringID(exec.ExecId),
+		OrderID:       hashStringID(exec.OrderId),
+		Exchange:      types.ExchangeBybit,
+		Price:         exec.ExecPrice,
+		Quantity:      exec.ExecQty,
+		QuoteQuantity: exec.ExecPrice.Mul(exec.ExecQty),
+		Symbol:        exec.Symbol,
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		IsMaker:       exec.IsMaker,
+		Time:          types.Time(exec.ExecTime),
+		Fee:           exec.ExecFee,
+		FeeCurrency:   settleCoinOfSymbol(exec.Symbol),
+		IsFutures:     true,
+	}, nil
+}
+
+// toGlobalFundingFee converts the funding execution into the funding fee. The execFee of the funding execution
+// is positive when the fee is paid, so it's negated here to match the FundingFee convention.
+func toGlobalFundingFee(exec bybitapi.Execution) types.FundingFee {
+	return types.FundingFee{
+		Exchange:    types.ExchangeBybit,
+		Symbol:      exec.Symbol,
+		Asset:       settleCoinOfSymbol(exec.Symbol),
+		Amount:      exec.ExecFee.Neg(),
+		FundingRate: exec.FeeRate,
+		TxnID:       exec.ExecId,
+		Time:        exec.ExecTime.Time(),
+	}
+}
+
+func toGlobalFuturesPosition(p bybitapi.PositionInfo) types.FuturesPosition {
+	base := p.Size
+	if p.Side == bybitapi.PositionSideSell {
+		base = base.Neg()
+	}
+
+	return types.FuturesPosition{
+		Symbol:                 p.Symbol,
+		QuoteCurrency:          settleCoinOfSymbol(p.Symbol),
+		Base:                   base,
+		Quote:                  p.PositionValue.Mul(fixedpoint.NewFromInt(int64(-base.Sign()))),
+		AverageCost:            p.AvgPrice,
+		ApproximateAverageCost: p.AvgPrice,
+		Isolated:               p.TradeMode == 1,
+		UpdateTime:             p.UpdatedTime.Time().UnixMilli(),
+		PositionRisk: &types.PositionRisk{
+			Leverage:         p.Leverage,
+			LiquidationPrice: p.LiqPrice,
+		},
+	}
+}
+
+func toGlobalFuturesPositions(positions []bybitapi.PositionInfo) types.FuturesPositionMap {
+	m := make(types.FuturesPositionMap)
+	for _, p := range positions {
+		m[p.Symbol] = toGlobalFuturesPosition(p)
+	}
+	return m
+}
+
+func toGlobalFuturesAccountInfo(w bybitapi.WalletBalances, positions types.FuturesPositionMap) *types.FuturesAccountInfo {
+	assets := make(types.FuturesAssetMap)
+	for _, c := range w.Coins {
+		assets[c.Coin] = types.FuturesUserAsset{
+			Asset:                  c.Coin,
+			InitialMargin:          c.TotalPositionIM.Add(c.TotalOrderIM),
+			MaintMargin:            c.TotalPositionMM,
+			MarginBalance:          c.Equity,
+			MaxWithdrawAmount:      c.AvailableToWithdraw,
+			OpenOrderInitialMargin: c.TotalOrderIM,
+			PositionInitialMargin:  c.TotalPositionIM,
+			UnrealizedProfit:       c.UnrealisedPnl,
+			WalletBalance:          c.WalletBalance,
+		}
+	}
+
+	return &types.FuturesAccountInfo{
+		Assets:                     assets,
+		Positions:                  positions,
+		TotalInitialMargin:         w.TotalInitialMargin,
+		TotalMaintMargin:           w.TotalMaintenanceMargin,
+		TotalMarginBalance:         w.TotalMarginBalance,
+		TotalPositionInitialMargin: w.TotalInitialMargin,
+		TotalUnrealizedProfit:      w.TotalPerpUPL,
+		TotalWalletBalance:         w.TotalWalletBalance,
+	}
+}
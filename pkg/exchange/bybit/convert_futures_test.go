@@ -0,0 +1,116 @@
+package bybit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_settleCoinOfSymbol(t *testing.T) {
+	assert.Equal(t, "USDT", settleCoinOfSymbol("BTCUSDT"))
+	assert.Equal(t, "USDC", settleCoinOfSymbol("BTCPERP"))
+	assert.Equal(t, "USDC", settleCoinOfSymbol("BTC-26JUN26"))
+}
+
+func Test_toGlobalFuturesPosition(t *testing.T) {
+	updatedTime := types.MillisecondTimestamp(time.UnixMilli(1700000000000))
+
+	t.Run("short", func(t *testing.T) {
+		p := toGlobalFuturesPosition(bybitapi.PositionInfo{
+			Symbol:        "BTCUSDT",
+			Side:          bybitapi.PositionSideSell,
+			Size:          fixedpoint.NewFromFloat(0.1),
+			AvgPrice:      fixedpoint.NewFromInt(30000),
+			PositionValue: fixedpoint.NewFromInt(3000),
+			TradeMode:     1,
+			Leverage:      fixedpoint.NewFromInt(10),
+			LiqPrice:      fixedpoint.NewFromInt(32000),
+			UpdatedTime:   updatedTime,
+		})
+
+		assert.Equal(t, "BTCUSDT", p.Symbol)
+		assert.Equal(t, "USDT", p.QuoteCurrency)
+		assert.Equal(t, fixedpoint.NewFromFloat(-0.1), p.Base)
+		assert.Equal(t, fixedpoint.NewFromInt(3000), p.Quote)
+		assert.True(t, p.Isolated)
+		assert.Equal(t, fixedpoint.NewFromInt(10), p.PositionRisk.Leverage)
+		assert.Equal(t, fixedpoint.NewFromInt(32000), p.PositionRisk.LiquidationPrice)
+	})
+
+	t.Run("long", func(t *testing.T) {
+		p := toGlobalFuturesPosition(bybitapi.PositionInfo{
+			Symbol:        "ETHPERP",
+			Side:          bybitapi.PositionSideBuy,
+			Size:          fixedpoint.NewFromInt(2),
+			AvgPrice:      fixedpoint.NewFromInt(2000),
+			PositionValue: fixedpoint.NewFromInt(4000),
+			UpdatedTime:   updatedTime,
+		})
+
+		assert.Equal(t, "USDC", p.QuoteCurrency)
+		assert.Equal(t, fixedpoint.NewFromInt(2), p.Base)
+		assert.Equal(t, fixedpoint.NewFromInt(-4000), p.Quote)
+		assert.False(t, p.Isolated)
+	})
+}
+
+func Test_toGlobalFuturesTrade(t *testing.T) {
+	exec := bybitapi.Execution{
+		Symbol:    "BTCUSDT",
+		OrderId:   "42f4f364-82e1-49d3-ad1d-cd8cf9aa308d",
+		Side:      bybitapi.SideBuy,
+		ExecFee:   fixedpoint.NewFromFloat(0.33),
+		ExecId:    "e2b8a1b6-4e0a-5b8c-9e2b-3f5c1d2e9a77",
+		ExecPrice: fixedpoint.NewFromInt(30000),
+		ExecQty:   fixedpoint.NewFromFloat(0.02),
+		ExecType:  bybitapi.ExecTypeTrade,
+		IsMaker:   false,
+		ExecTime:  types.MillisecondTimestamp(time.UnixMilli(1700000000000)),
+	}
+
+	trade, err := toGlobalFuturesTrade(exec)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Trade{
+		ID:            hashStringID(exec.ExecId),
+		OrderID:       hashStringID(exec.OrderId),
+		Exchange:      types.ExchangeBybit,
+		Price:         exec.ExecPrice,
+		Quantity:      exec.ExecQty,
+		QuoteQuantity: fixedpoint.NewFromInt(600),
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		IsBuyer:       true,
+		IsMaker:       false,
+		Time:          types.Time(exec.ExecTime),
+		Fee:           exec.ExecFee,
+		FeeCurrency:   "USDT",
+		IsFutures:     true,
+	}, trade)
+}
+
+func Test_toGlobalFundingFee(t *testing.T) {
+	exec := bybitapi.Execution{
+		Symbol:   "BTCUSDT",
+		ExecFee:  fixedpoint.NewFromFloat(0.12),
+		ExecId:   "funding-1",
+		ExecType: bybitapi.ExecTypeFunding,
+		FeeRate:  fixedpoint.NewFromFloat(0.0001),
+		ExecTime: types.MillisecondTimestamp(time.UnixMilli(1700000000000)),
+	}
+
+	fee := toGlobalFundingFee(exec)
+	assert.Equal(t, types.FundingFee{
+		Exchange:    types.ExchangeBybit,
+		Symbol:      "BTCUSDT",
+		Asset:       "USDT",
+		Amount:      fixedpoint.NewFromFloat(-0.12),
+		FundingRate: fixedpoint.NewFromFloat(0.0001),
+		TxnID:       "funding-1",
+		Time:        time.UnixMilli(1700000000000),
+	}, fee)
+}
@@ -48,6 +48,11 @@ func TestToGlobalMarket(t *testing.T) {
 			MaxOrderQty    fixedpoint.Value `json:"maxOrderQty"`
 			MinOrderAmt    fixedpoint.Value `json:"minOrderAmt"`
 			MaxOrderAmt    fixedpoint.Value `json:"maxOrderAmt"`
+
+			// QtyStep is the step to increase/reduce order quantity, linear only
+			QtyStep fixedpoint.Value `json:"qtyStep"`
+			// MinNotionalValue is the minimum notional value, linear only
+			MinNotionalValue fixedpoint.Value `json:"minNotionalValue"`
 		}{
 			BasePrecision:  fixedpoint.NewFromFloat(0.000001),
 			QuotePrecision: fixedpoint.NewFromFloat(0.00000001),
@@ -58,6 +63,10 @@ func TestToGlobalMarket(t *testing.T) {
 		},
 		PriceFilter: struct {
 			TickSize fixedpoint.Value `json:"tickSize"`
+
+			// MinPrice and MaxPrice are only available in the linear category
+			MinPrice fixedpoint.Value `json:"minPrice"`
+			MaxPrice fixedpoint.Value `json:"maxPrice"`
 		}{
 			TickSize: fixedpoint.NewFromFloat(0.01),
 		},
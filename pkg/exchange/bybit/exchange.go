@@ -47,12 +47,22 @@ var (
 	_ types.ExchangeTradeService      = &Exchange{}
 	_ types.Exchange                  = &Exchange{}
 	_ types.ExchangeOrderQueryService = &Exchange{}
+	_ types.MarginExchange            = &Exchange{}
+	_ types.MarginBorrowRepayService  = &Exchange{}
+	_ types.FuturesExchange           = &Exchange{}
+	_ types.FuturesPositionService    = &Exchange{}
 )
 
 type Exchange struct {
+	types.MarginSettings
+	types.FuturesSettings
+
 	key, secret string
 	client      *bybitapi.RestClient
 	v3client    *v3.Client
+
+	// orderUUIDs resolves the hashed order ids of the linear category
+	orderUUIDs *orderUUIDMap
 }
 
 func New(key, secret string) (*Exchange, error) {
@@ -68,9 +78,10 @@ func New(key, secret string) (*Exchange, error) {
 	return &Exchange{
 		key: key,
 		// pragma: allowlist nextline secret
-		secret:   secret,
-		client:   client,
-		v3client: v3.NewClient(client),
+		secret:     secret,
+		client:     client,
+		v3client:   v3.NewClient(client),
+		orderUUIDs: newOrderUUIDMap(),
 	}, nil
 }
 
@@ -84,7 +95,29 @@ func (e *Exchange) PlatformFeeCurrency() string {
 	return ""
 }
 
+// category returns the product category of the current session. The unified trading account trades spot and
+// margin in the spot category, and the USDT/USDC contracts in the linear category.
+func (e *Exchange) category() bybitapi.Category {
+	if e.IsFutures {
+		return bybitapi.CategoryLinear
+	}
+	return bybitapi.CategorySpot
+}
+
+// accountType returns the wallet account type, margin and futures trading are only supported by the
+// unified trading account.
+func (e *Exchange) accountType() bybitapi.AccountType {
+	if e.IsFutures || e.IsMargin {
+		return bybitapi.AccountTypeUnified
+	}
+	return bybitapi.AccountTypeSpot
+}
+
 func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	if e.IsFutures {
+		return e.queryFuturesMarkets(ctx)
+	}
+
 	if err := sharedRateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
 	}
@@ -107,7 +140,7 @@ func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticke
 		return nil, fmt.Errorf("ticker order rate limiter wait error: %w", err)
 	}
 
-	s, err := e.client.NewGetTickersRequest().Symbol(symbol).DoWithResponseTime(ctx)
+	s, err := e.client.NewGetTickersRequest().Category(e.category()).Symbol(symbol).DoWithResponseTime(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call ticker, symbol: %s, err: %w", symbol, err)
 	}
@@ -138,7 +171,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 	if err := sharedRateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
 	}
-	allTickers, err := e.client.NewGetTickersRequest().DoWithResponseTime(ctx)
+	allTickers, err := e.client.NewGetTickersRequest().Category(e.category()).DoWithResponseTime(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call ticker, err: %w", err)
 	}
@@ -153,7 +186,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
 	cursor := ""
 	for {
-		req := e.client.NewGetOpenOrderRequest().Symbol(symbol)
+		req := e.client.NewGetOpenOrderRequest().Category(e.category()).Symbol(symbol)
 		if len(cursor) != 0 {
 			// the default limit is 20.
 			req = req.Cursor(cursor)
@@ -168,7 +201,7 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 		}
 
 		for _, order := range res.List {
-			order, err := toGlobalOrder(order)
+			order, err := e.toGlobalOrder(order)
 			if err != nil {
 				return nil, fmt.Errorf("failed to convert order, err: %v", err)
 			}
@@ -186,6 +219,13 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 }
 
 func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	if e.IsFutures {
+		var err error
+		if q, err = e.orderUUIDs.resolve(q); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(q.OrderID) == 0 && len(q.ClientOrderID) == 0 {
 		return nil, errors.New("one of OrderID/ClientOrderID is required parameter")
 	}
@@ -194,7 +234,7 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 		return nil, errors.New("only accept one parameter of OrderID/ClientOrderID")
 	}
 
-	req := e.client.NewGetOrderHistoriesRequest()
+	req := e.client.NewGetOrderHistoriesRequest().Category(e.category())
 	if len(q.Symbol) != 0 {
 		req.Symbol(q.Symbol)
 	}
@@ -215,10 +255,14 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 		return nil, fmt.Errorf("unexpected order length, queryConfig: %+v", q)
 	}
 
-	return toGlobalOrder(res.List[0])
+	return e.toGlobalOrder(res.List[0])
 }
 
 func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (trades []types.Trade, err error) {
+	if e.IsFutures {
+		return e.queryFuturesOrderTrades(ctx, q)
+	}
+
 	if len(q.ClientOrderID) != 0 {
 		log.Warn("!!!BYBIT EXCHANGE API NOTICE!!! Bybit does not support searching for trades using OrderClientId.")
 	}
@@ -226,6 +270,7 @@ func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (tr
 	if len(q.OrderID) == 0 {
 		return nil, errors.New("orderID is required parameter")
 	}
+
 	req := e.v3client.NewGetTradesRequest().OrderId(q.OrderID)
 
 	if len(q.Symbol) != 0 {
@@ -262,7 +307,7 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		return nil, fmt.Errorf("order.Market.Symbol is required: %+v", order)
 	}
 
-	req := e.client.NewPlaceOrderRequest()
+	req := e.client.NewPlaceOrderRequest().Category(e.category())
 	req.Symbol(order.Market.Symbol)
 
 	// set order type
@@ -281,8 +326,9 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 
 	// set quantity
 	orderQty := order.Quantity
-	// if the order is market buy, the quantity is quote coin, instead of base coin. so we need to convert it.
-	if order.Type == types.OrderTypeMarket && order.Side == types.SideTypeBuy {
+	// if the spot order is market buy, the quantity is quote coin, instead of base coin. so we need to convert it.
+	// the quantity of the linear contracts is always in base coin.
+	if !e.IsFutures && order.Type == types.OrderTypeMarket && order.Side == types.SideTypeBuy {
 		ticker, err := e.QueryTicker(ctx, order.Market.Symbol)
 		if err != nil {
 			return nil, err
@@ -307,6 +353,15 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		req.TimeInForce(bybitapi.TimeInForceGTC)
 	}
 
+	if e.IsMargin {
+		// borrow automatically when the balance is insufficient
+		req.IsLeverage(1)
+	}
+
+	if e.IsFutures && (order.ReduceOnly || order.ClosePosition) {
+		req.ReduceOnly(true)
+	}
+
 	// set client order id
 	if len(order.ClientOrderID) > maxOrderIdLen {
 		return nil, fmt.Errorf("unexpected length of order id, got: %d", len(order.ClientOrderID))
@@ -328,9 +383,15 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		return nil, fmt.Errorf("unexpected order id, resp: %#v, order: %#v", res, order)
 	}
 
-	intOrderId, err := strconv.ParseUint(res.OrderId, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse orderId: %s", res.OrderId)
+	var intOrderId uint64
+	if e.IsFutures {
+		intOrderId = hashStringID(res.OrderId)
+		e.orderUUIDs.Add(intOrderId, res.OrderId)
+	} else {
+		intOrderId, err = strconv.ParseUint(res.OrderId, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse orderId: %s", res.OrderId)
+		}
 	}
 
 	return &types.Order{
@@ -343,6 +404,8 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		IsWorking:        true,
 		CreationTime:     types.Time(timeNow),
 		UpdateTime:       types.Time(timeNow),
+		IsMargin:         e.IsMargin,
+		IsFutures:        e.IsFutures,
 	}, nil
 }
 
@@ -352,7 +415,7 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (err
 	}
 
 	for _, order := range orders {
-		req := e.client.NewCancelOrderRequest()
+		req := e.client.NewCancelOrderRequest().Category(e.category())
 
 		reqId := ""
 		switch {
@@ -396,6 +459,10 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (err
 }
 
 func (e *Exchange) QueryClosedOrders(ctx context.Context, symbol string, since, util time.Time, lastOrderID uint64) (orders []types.Order, err error) {
+	if e.IsFutures {
+		return e.queryFuturesClosedOrders(ctx, symbol, since, util, lastOrderID)
+	}
+
 	if !since.IsZero() || !util.IsZero() {
 		log.Warn("!!!BYBIT EXCHANGE API NOTICE!!! the since/until conditions will not be effected on SPOT account, bybit exchange does not support time-range-based query currently")
 	}
@@ -413,7 +480,7 @@ func (e *Exchange) QueryClosedOrders(ctx context.Context, symbol string, since,
 	}
 
 	for _, order := range res.List {
-		o, err2 := toGlobalOrder(order)
+		o, err2 := e.toGlobalOrder(order)
 		if err2 != nil {
 			err = multierr.Append(err, err2)
 			continue
@@ -444,6 +511,10 @@ It supports to query records up to 180 days.
 Otherwise, the result is sorted by tradeId in `descend`. **
 */
 func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) (trades []types.Trade, err error) {
+	if e.IsFutures {
+		return e.queryFuturesTrades(ctx, symbol, options)
+	}
+
 	// using v3 client, since the v5 API does not support feeCurrency.
 	req := e.v3client.NewGetTradesRequest()
 	req.Symbol(symbol)
@@ -496,6 +567,10 @@ func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *type
 }
 
 func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	if e.IsFutures || e.IsMargin {
+		return e.queryUnifiedAccount(ctx)
+	}
+
 	balanceMap, err := e.QueryAccountBalances(ctx)
 	if err != nil {
 		return nil, err
@@ -517,7 +592,7 @@ func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap,
 		return nil, fmt.Errorf("query account balances rate limiter wait error: %w", err)
 	}
 
-	req := e.client.NewGetWalletBalancesRequest()
+	req := e.client.NewGetWalletBalancesRequest().AccountType(e.accountType())
 	accounts, err := req.Do(ctx)
 	if err != nil {
 		return nil, err
@@ -534,7 +609,7 @@ A k-line's start time is inclusive, but end time is not(startTime + interval - 1
 e.q. 15m interval k line can be represented as 00:00:00.000 ~ 00:14:59.999
 */
 func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
-	req := e.client.NewGetKLinesRequest().Symbol(symbol)
+	req := e.client.NewGetKLinesRequest().Category(e.category()).Symbol(symbol)
 	intervalStr, err := toLocalInterval(interval)
 	if err != nil {
 		return nil, err
@@ -565,7 +640,7 @@ func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval type
 		return nil, fmt.Errorf("failed to call k line, err: %w", err)
 	}
 
-	if resp.Category != bybitapi.CategorySpot {
+	if resp.Category != e.category() {
 		return nil, fmt.Errorf("unexpected category: %s", resp.Category)
 	}
 
@@ -600,5 +675,29 @@ func (e *Exchange) GetAllFeeRates(ctx context.Context) (bybitapi.FeeRates, error
 }
 
 func (e *Exchange) NewStream() types.Stream {
-	return NewStream(e.key, e.secret, e)
+	stream := NewStream(e.key, e.secret, e)
+	stream.MarginSettings = e.MarginSettings
+	stream.FuturesSettings = e.FuturesSettings
+	stream.orderUUIDs = e.orderUUIDs
+	return stream
+}
+
+func (e *Exchange) toGlobalOrder(order bybitapi.Order) (*types.Order, error) {
+	if e.IsFutures {
+		o, err := toGlobalFuturesOrder(order)
+		if err != nil {
+			return nil, err
+		}
+
+		e.orderUUIDs.Add(o.OrderID, o.UUID)
+		return o, nil
+	}
+
+	o, err := toGlobalOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	o.IsMargin = e.IsMargin
+	return o, nil
 }
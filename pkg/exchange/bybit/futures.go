@@ -0,0 +1,378 @@
+package bybit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	// maxFuturesQueryLimit is the max page size of the linear instruments, positions and executions.
+	maxFuturesQueryLimit = 100
+
+	// maxOrderHistoryPeriod is the max time range of the order history and execution query of the linear category.
+	maxOrderHistoryPeriod = 7 * 24 * time.Hour
+)
+
+func (e *Exchange) queryFuturesMarkets(ctx context.Context) (types.MarketMap, error) {
+	marketMap := types.MarketMap{}
+	cursor := ""
+	for {
+		if err := sharedRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetInstrumentsInfoRequest().
+			Category(bybitapi.CategoryLinear).
+			Limit(maxFuturesQueryLimit)
+		if len(cursor) != 0 {
+			req.Cursor(cursor)
+		}
+
+		instruments, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get linear instruments, err: %v", err)
+		}
+
+		for _, s := range instruments.List {
+			marketMap.Add(toGlobalFuturesMarket(s))
+		}
+
+		if len(instruments.NextPageCursor) == 0 {
+			break
+		}
+		cursor = instruments.NextPageCursor
+	}
+
+	return marketMap, nil
+}
+
+// queryFuturesClosedOrders queries the closed orders of the linear category. Unlike the spot category, the linear
+// category supports the time range query, the interval of the since and until should be less than 7 days.
+//
+// The order id of the linear category is the hash of the order UUID, which can't be used to page the orders, so the
+// lastOrderID is ignored, all the orders in the time range are queried by the page cursor.
+func (e *Exchange) queryFuturesClosedOrders(ctx context.Context, symbol string, since, until time.Time, _ uint64) (orders []types.Order, err error) {
+	if since.IsZero() {
+		since = time.Now().Add(-maxOrderHistoryPeriod)
+	}
+	if until.IsZero() || until.Sub(since) > maxOrderHistoryPeriod {
+		until = since.Add(maxOrderHistoryPeriod)
+	}
+
+	cursor := ""
+	for {
+		if err := closedOrderQueryLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("query closed order rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetOrderHistoriesRequest().
+			Category(bybitapi.CategoryLinear).
+			Symbol(symbol).
+			StartTime(since).
+			EndTime(until).
+			Limit(defaultQueryLimit)
+		if len(cursor) != 0 {
+			req.Cursor(cursor)
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call get order histories error: %w", err)
+		}
+
+		var errs error
+		for _, order := range res.List {
+			o, err := e.toGlobalOrder(order)
+			if err != nil {
+				errs = multierr.Append(errs, err)
+				continue
+			}
+
+			if o.Status.Closed() {
+				orders = append(orders, *o)
+			}
+		}
+		if errs != nil {
+			return nil, errs
+		}
+
+		if len(res.NextPageCursor) == 0 || len(res.List) == 0 {
+			break
+		}
+		cursor = res.NextPageCursor
+	}
+
+	return types.SortOrdersAscending(orders), nil
+}
+
+// queryFuturesTrades queries the trade executions of the linear category. The funding executions are excluded,
+// they are emitted as the funding fee by the stream.
+//
+// The executions are returned in the descending order, so all the executions in the time range are queried by
+// the page cursor, and the oldest ones up to the limit are returned, the trades after them are queried by the next
+// call from the time of the last trade.
+func (e *Exchange) queryFuturesTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) (trades []types.Trade, err error) {
+	var startTime, endTime time.Time
+	switch {
+	case options.StartTime != nil:
+		startTime = options.StartTime.UTC()
+		endTime = startTime.Add(maxOrderHistoryPeriod)
+		if options.EndTime != nil && options.EndTime.Before(endTime) {
+			endTime = options.EndTime.UTC()
+		}
+
+	case options.EndTime != nil:
+		endTime = options.EndTime.UTC()
+		startTime = endTime.Add(-maxOrderHistoryPeriod)
+
+	default:
+		endTime = time.Now()
+		startTime = endTime.Add(-maxOrderHistoryPeriod)
+	}
+
+	limit := int(options.Limit)
+	if limit <= 0 {
+		limit = maxFuturesQueryLimit
+	}
+
+	cursor := ""
+	for {
+		req := e.client.NewGetExecutionsRequest().
+			Category(bybitapi.CategoryLinear).
+			Symbol(symbol).
+			ExecType(bybitapi.ExecTypeTrade).
+			StartTime(startTime).
+			EndTime(endTime).
+			Limit(maxFuturesQueryLimit)
+		if len(cursor) != 0 {
+			req.Cursor(cursor)
+		}
+
+		if err := queryOrderTradeRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("trade rate limiter wait error: %w", err)
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query executions, err: %w", err)
+		}
+
+		for _, exec := range res.List {
+			trade, err := toGlobalFuturesTrade(exec)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert execution: %+v, err: %w", exec, err)
+			}
+
+			trades = append(trades, *trade)
+		}
+
+		if len(res.NextPageCursor) == 0 || len(res.List) == 0 {
+			break
+		}
+		cursor = res.NextPageCursor
+	}
+
+	trades = types.SortTradesAscending(trades)
+	if len(trades) > limit {
+		trades = trades[:limit]
+	}
+
+	return trades, nil
+}
+
+// queryFuturesOrderTrades queries the executions of the order. The order is resolved by the order UUID, or by the
+// client order id if the UUID of the hashed order id is unknown.
+func (e *Exchange) queryFuturesOrderTrades(ctx context.Context, q types.OrderQuery) (trades []types.Trade, err error) {
+	q, err = e.orderUUIDs.resolve(q)
+	if err != nil {
+		return nil, err
+	}
+
+	req := e.client.NewGetExecutionsRequest().Category(bybitapi.CategoryLinear)
+	switch {
+	case len(q.OrderID) != 0:
+		req.OrderId(q.OrderID)
+
+	case len(q.ClientOrderID) != 0:
+		req.OrderLinkId(q.ClientOrderID)
+
+	default:
+		return nil, errors.New("one of OrderID/ClientOrderID is required parameter")
+	}
+
+	if len(q.Symbol) != 0 {
+		req.Symbol(q.Symbol)
+	}
+	req.Limit(maxFuturesQueryLimit)
+
+	if err := queryOrderTradeRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("trade rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order trades, err: %w", err)
+	}
+
+	var errs error
+	for _, exec := range res.List {
+		if exec.ExecType == bybitapi.ExecTypeFunding {
+			continue
+		}
+
+		trade, err := toGlobalFuturesTrade(exec)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		trades = append(trades, *trade)
+	}
+	if errs != nil {
+		return nil, errs
+	}
+
+	return trades, nil
+}
+
+// futuresSettleCoins are the settle coins of the linear contracts
+var futuresSettleCoins = []string{"USDT", "USDC"}
+
+// QueryFuturesPositions queries the positions of the linear contracts of all the settle coins.
+func (e *Exchange) QueryFuturesPositions(ctx context.Context) (types.FuturesPositionMap, error) {
+	positions := types.FuturesPositionMap{}
+	for _, settleCoin := range futuresSettleCoins {
+		ps, err := e.queryFuturesPositions(ctx, settleCoin)
+		if err != nil {
+			return nil, err
+		}
+
+		for symbol, p := range ps {
+			positions[symbol] = p
+		}
+	}
+
+	return positions, nil
+}
+
+// queryFuturesPositions queries the positions of the linear contracts which are settled in the given coin.
+func (e *Exchange) queryFuturesPositions(ctx context.Context, settleCoin string) (types.FuturesPositionMap, error) {
+	var positions []bybitapi.PositionInfo
+	cursor := ""
+	for {
+		if err := sharedRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("position rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetPositionInfoRequest().
+			Category(bybitapi.CategoryLinear).
+			SettleCoin(settleCoin).
+			Limit(maxFuturesQueryLimit)
+		if len(cursor) != 0 {
+			req.Cursor(cursor)
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query positions, err: %w", err)
+		}
+
+		positions = append(positions, res.List...)
+		if len(res.NextPageCursor) == 0 {
+			break
+		}
+		cursor = res.NextPageCursor
+	}
+
+	return toGlobalFuturesPositions(positions), nil
+}
+
+func (e *Exchange) QueryPositionRisk(ctx context.Context, symbol string) (*types.PositionRisk, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("position rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetPositionInfoRequest().
+		Category(bybitapi.CategoryLinear).
+		Symbol(symbol).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query position, err: %w", err)
+	}
+
+	if len(res.List) == 0 {
+		return nil, fmt.Errorf("position of %s not found", symbol)
+	}
+
+	return toGlobalFuturesPosition(res.List[0]).PositionRisk, nil
+}
+
+// SetLeverage sets the leverage of both the long and short side of the given symbol.
+func (e *Exchange) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if err := orderRateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("leverage rate limiter wait error: %w", err)
+	}
+
+	lv := strconv.Itoa(leverage)
+	_, err := e.client.NewSetLeverageRequest().
+		Category(bybitapi.CategoryLinear).
+		Symbol(symbol).
+		BuyLeverage(lv).
+		SellLeverage(lv).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to set leverage of %s, err: %w", symbol, err)
+	}
+
+	return nil
+}
+
+// queryUnifiedAccount queries the account of the unified trading account, the futures info is included when the
+// futures mode is enabled.
+func (e *Exchange) queryUnifiedAccount(ctx context.Context) (*types.Account, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query account balances rate limiter wait error: %w", err)
+	}
+
+	accounts, err := e.client.NewGetWalletBalancesRequest().AccountType(bybitapi.AccountTypeUnified).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	acct := &types.Account{
+		AccountType: types.AccountTypeMargin,
+		// MakerFeeRate bybit doesn't support global maker fee rate.
+		MakerFeeRate: fixedpoint.Zero,
+		// TakerFeeRate bybit doesn't support global taker fee rate.
+		TakerFeeRate: fixedpoint.Zero,
+	}
+	acct.UpdateBalances(toGlobalBalanceMap(accounts.List))
+
+	if len(accounts.List) == 0 {
+		return acct, nil
+	}
+
+	wallet := accounts.List[0]
+	acct.TotalAccountValue = wallet.TotalEquity
+
+	if e.IsFutures {
+		acct.AccountType = types.AccountTypeFutures
+
+		positions, err := e.QueryFuturesPositions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		acct.FuturesInfo = toGlobalFuturesAccountInfo(wallet, positions)
+	}
+
+	return acct, nil
+}
@@ -0,0 +1,63 @@
+package bybit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// BorrowMarginAsset borrows the asset manually in the unified trading account. The isolated margin is not supported
+// by the unified trading account, so the isolated margin settings are ignored.
+func (e *Exchange) BorrowMarginAsset(ctx context.Context, asset string, amount fixedpoint.Value) error {
+	if err := orderRateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("borrow rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewBorrowRequest().Coin(asset).Amount(amount.String()).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to borrow %s %s, err: %w", amount.String(), asset, err)
+	}
+
+	log.Infof("borrowed margin asset %s %s", res.Amount.String(), res.Coin)
+	return nil
+}
+
+func (e *Exchange) RepayMarginAsset(ctx context.Context, asset string, amount fixedpoint.Value) error {
+	if err := orderRateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("repay rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewRepayRequest().Coin(asset).Amount(amount.String()).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to repay %s %s, err: %w", amount.String(), asset, err)
+	}
+
+	log.Infof("repaid margin asset %s %s", res.Amount.String(), res.Coin)
+	return nil
+}
+
+func (e *Exchange) QueryMarginAssetMaxBorrowable(ctx context.Context, asset string) (amount fixedpoint.Value, err error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return fixedpoint.Zero, fmt.Errorf("query account balances rate limiter wait error: %w", err)
+	}
+
+	accounts, err := e.client.NewGetWalletBalancesRequest().
+		AccountType(bybitapi.AccountTypeUnified).
+		Coin(asset).
+		Do(ctx)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	for _, account := range accounts.List {
+		for _, coin := range account.Coins {
+			if coin.Coin == asset {
+				return coin.AvailableToBorrow, nil
+			}
+		}
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("asset %s not found in the unified account", asset)
+}
@@ -0,0 +1,86 @@
+package bybit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// maxOrderUUIDs is the max number of the order UUIDs kept by orderUUIDMap, the oldest ones are evicted first.
+const maxOrderUUIDs = 10000
+
+// orderUUIDMap maps the hashed order id of the linear category back to the order UUID.
+// The callers query the orders with strconv.FormatUint(order.OrderID, 10), which is the hash of the UUID,
+// so the UUID has to be resolved before it's sent to bybit.
+type orderUUIDMap struct {
+	mu    sync.Mutex
+	uuids map[uint64]string
+	ids   []uint64
+}
+
+func newOrderUUIDMap() *orderUUIDMap {
+	return &orderUUIDMap{
+		uuids: make(map[uint64]string),
+	}
+}
+
+func (m *orderUUIDMap) Add(orderID uint64, uuid string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.uuids[orderID]; ok {
+		return
+	}
+
+	m.uuids[orderID] = uuid
+	m.ids = append(m.ids, orderID)
+	if len(m.ids) > maxOrderUUIDs {
+		delete(m.uuids, m.ids[0])
+		m.ids = m.ids[1:]
+	}
+}
+
+func (m *orderUUIDMap) Get(orderID uint64) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uuid, ok := m.uuids[orderID]
+	return uuid, ok
+}
+
+// resolve converts the order id of the query into the order UUID. The order UUID is passed through as is.
+// If the hashed order id is unknown, the query falls back to the client order id.
+func (m *orderUUIDMap) resolve(q types.OrderQuery) (types.OrderQuery, error) {
+	if len(q.OrderID) == 0 || strings.Contains(q.OrderID, "-") {
+		return q, nil
+	}
+
+	orderID, err := strconv.ParseUint(q.OrderID, 10, 64)
+	if err != nil {
+		return q, fmt.Errorf("unexpected order id %s, err: %w", q.OrderID, err)
+	}
+
+	if uuid, ok := m.Get(orderID); ok {
+		q.OrderID = uuid
+		q.ClientOrderID = ""
+		return q, nil
+	}
+
+	if len(q.ClientOrderID) == 0 {
+		return q, fmt.Errorf("the uuid of the order id %s is unknown, query the order by the client order id instead", q.OrderID)
+	}
+
+	q.OrderID = ""
+	return q, nil
+}
@@ -0,0 +1,57 @@
+package bybit
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_orderUUIDMap(t *testing.T) {
+	uuid := "42f4f364-82e1-49d3-ad1d-cd8cf9aa308d"
+	orderID := hashStringID(uuid)
+
+	m := newOrderUUIDMap()
+	m.Add(orderID, uuid)
+
+	t.Run("hashed order id", func(t *testing.T) {
+		q, err := m.resolve(types.OrderQuery{
+			Symbol:        "BTCUSDT",
+			OrderID:       strconv.FormatUint(orderID, 10),
+			ClientOrderID: "client-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, types.OrderQuery{Symbol: "BTCUSDT", OrderID: uuid}, q)
+	})
+
+	t.Run("uuid", func(t *testing.T) {
+		q, err := m.resolve(types.OrderQuery{OrderID: uuid})
+		require.NoError(t, err)
+		assert.Equal(t, uuid, q.OrderID)
+	})
+
+	t.Run("unknown order id falls back to the client order id", func(t *testing.T) {
+		q, err := m.resolve(types.OrderQuery{OrderID: "12345", ClientOrderID: "client-1"})
+		require.NoError(t, err)
+		assert.Equal(t, types.OrderQuery{ClientOrderID: "client-1"}, q)
+
+		_, err = m.resolve(types.OrderQuery{OrderID: "12345"})
+		assert.Error(t, err)
+	})
+
+	t.Run("evict the oldest", func(t *testing.T) {
+		m := newOrderUUIDMap()
+		for i := 0; i <= maxOrderUUIDs; i++ {
+			m.Add(uint64(i), strconv.Itoa(i))
+		}
+
+		_, ok := m.Get(0)
+		assert.False(t, ok)
+
+		_, ok = m.Get(maxOrderUUIDs)
+		assert.True(t, ok)
+	})
+}
@@ -56,18 +56,24 @@ type StreamDataProvider interface {
 //go:generate callbackgen -type Stream
 type Stream struct {
 	types.StandardStream
+	types.MarginSettings
+	types.FuturesSettings
 
 	key, secret        string
 	streamDataProvider StreamDataProvider
 	feeRateProvider    *feeRatePoller
 	marketsInfo        types.MarketMap
 
+	// orderUUIDs is shared with the exchange, so that the orders from the stream can be queried by the order id
+	orderUUIDs *orderUUIDMap
+
 	bookEventCallbacks        []func(e BookEvent)
 	marketTradeEventCallbacks []func(e []MarketTradeEvent)
 	walletEventCallbacks      []func(e []bybitapi.WalletBalances)
 	kLineEventCallbacks       []func(e KLineEvent)
 	orderEventCallbacks       []func(e []OrderEvent)
 	tradeEventCallbacks       []func(e []TradeEvent)
+	positionEventCallbacks    []func(e []PositionEvent)
 }
 
 func NewStream(key, secret string, userDataProvider StreamDataProvider) *Stream {
@@ -90,8 +96,10 @@ func NewStream(key, secret string, userDataProvider StreamDataProvider) *Stream
 			return
 		}
 
-		// get account fee rate
-		go stream.feeRateProvider.Start(ctx)
+		// get account fee rate, the fee of the linear contracts is provided by the execution event.
+		if !stream.IsFutures {
+			go stream.feeRateProvider.Start(ctx)
+		}
 
 		stream.marketsInfo, err = stream.streamDataProvider.QueryMarkets(ctx)
 		if err != nil {
@@ -109,6 +117,7 @@ func NewStream(key, secret string, userDataProvider StreamDataProvider) *Stream
 	stream.OnWalletEvent(stream.handleWalletEvent)
 	stream.OnOrderEvent(stream.handleOrderEvent)
 	stream.OnTradeEvent(stream.handleTradeEvent)
+	stream.OnPositionEvent(stream.handlePositionEvent)
 	return stream
 }
 
@@ -162,6 +171,9 @@ func (s *Stream) createEndpoint(_ context.Context) (string, error) {
 	var url string
 	if s.PublicOnly {
 		url = bybitapi.WsSpotPublicSpotUrl
+		if s.IsFutures {
+			url = bybitapi.WsLinearPublicUrl
+		}
 	} else {
 		url = bybitapi.WsSpotPrivateUrl
 	}
@@ -193,6 +205,9 @@ func (s *Stream) dispatchEvent(event interface{}) {
 	case []TradeEvent:
 		s.EmitTradeEvent(e)
 
+	case []PositionEvent:
+		s.EmitPositionEvent(e)
+
 	}
 }
 
@@ -267,6 +282,10 @@ func (s *Stream) parseWebSocketEvent(in []byte) (interface{}, error) {
 			var trades []TradeEvent
 			return trades, json.Unmarshal(e.WebSocketTopicEvent.Data, &trades)
 
+		case TopicTypePosition:
+			var positions []PositionEvent
+			return positions, json.Unmarshal(e.WebSocketTopicEvent.Data, &positions)
+
 		}
 	}
 
@@ -307,13 +326,18 @@ func (s *Stream) handlerConnect() {
 			return
 		}
 
+		topics := []string{
+			string(TopicTypeWallet),
+			string(TopicTypeOrder),
+			string(TopicTypeTrade),
+		}
+		if s.IsFutures {
+			topics = append(topics, string(TopicTypePosition))
+		}
+
 		if err := s.Conn.WriteJSON(WebsocketOp{
-			Op: WsOpTypeSubscribe,
-			Args: []string{
-				string(TopicTypeWallet),
-				string(TopicTypeOrder),
-				string(TopicTypeTrade),
-			},
+			Op:   WsOpTypeSubscribe,
+			Args: topics,
 		}); err != nil {
 			log.WithError(err).Error("failed to send subscription request")
 			return
@@ -402,11 +426,25 @@ func (s *Stream) handleWalletEvent(events []bybitapi.WalletBalances) {
 
 func (s *Stream) handleOrderEvent(events []OrderEvent) {
 	for _, event := range events {
-		if event.Category != bybitapi.CategorySpot {
-			return
-		}
+		var gOrder *types.Order
+		var err error
+		switch {
+		case event.Category == bybitapi.CategorySpot && !s.IsFutures:
+			gOrder, err = toGlobalOrder(event.Order)
+			if err == nil {
+				gOrder.IsMargin = s.IsMargin
+			}
 
-		gOrder, err := toGlobalOrder(event.Order)
+		case event.Category == bybitapi.CategoryLinear && s.IsFutures:
+			gOrder, err = toGlobalFuturesOrder(event.Order)
+			if err == nil {
+				s.orderUUIDs.Add(gOrder.OrderID, gOrder.UUID)
+			}
+
+		default:
+			// the unified trading account pushes the orders of all categories, skip the ones of the other sessions.
+			continue
+		}
 		if err != nil {
 			if orderLogLimiter.Allow() {
 				log.WithError(err).Error("failed to convert to global order")
@@ -440,7 +478,16 @@ func (s *Stream) handleKLineEvent(klineEvent KLineEvent) {
 }
 
 func (s *Stream) handleTradeEvent(events []TradeEvent) {
+	if s.IsFutures {
+		s.handleFuturesTradeEvent(events)
+		return
+	}
+
 	for _, event := range events {
+		if event.Category != bybitapi.CategorySpot {
+			continue
+		}
+
 		feeRate, found := s.feeRateProvider.Get(event.Symbol)
 		if !found {
 			feeRate = symbolFeeDetail{
@@ -480,3 +527,45 @@ func (s *Stream) handleTradeEvent(events []TradeEvent) {
 		s.StandardStream.EmitTradeUpdate(*gTrade)
 	}
 }
+
+// handleFuturesTradeEvent handles the executions of the linear category, the funding executions are emitted as the
+// funding fee.
+func (s *Stream) handleFuturesTradeEvent(events []TradeEvent) {
+	for _, event := range events {
+		if event.Category != bybitapi.CategoryLinear {
+			continue
+		}
+
+		exec := event.toExecution()
+		switch exec.ExecType {
+		case bybitapi.ExecTypeFunding:
+			s.StandardStream.EmitFundingFee(toGlobalFundingFee(exec))
+
+		case bybitapi.ExecTypeTrade, bybitapi.ExecTypeAdlTrade, bybitapi.ExecTypeBustTrade:
+			gTrade, err := toGlobalFuturesTrade(exec)
+			if err != nil {
+				if tradeLogLimiter.Allow() {
+					log.WithError(err).Errorf("unable to convert: %+v", event)
+				}
+				continue
+			}
+			s.StandardStream.EmitTradeUpdate(*gTrade)
+		}
+	}
+}
+
+func (s *Stream) handlePositionEvent(events []PositionEvent) {
+	var positions []bybitapi.PositionInfo
+	for _, event := range events {
+		if event.Category != bybitapi.CategoryLinear {
+			continue
+		}
+		positions = append(positions, event.PositionInfo)
+	}
+
+	if len(positions) == 0 {
+		return
+	}
+
+	s.StandardStream.EmitFuturesPositionUpdate(toGlobalFuturesPositions(positions))
+}
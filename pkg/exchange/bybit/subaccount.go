@@ -0,0 +1,175 @@
+package bybit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/bybit/bybitapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	_ types.SubAccountService       = &Exchange{}
+	_ types.InternalTransferService = &Exchange{}
+)
+
+// the asset apis: 5 requests per second
+var transferRateLimiter = rate.NewLimiter(rate.Every(time.Second/5), 5)
+
+func (e *Exchange) QuerySubAccounts(ctx context.Context) ([]types.SubAccount, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("sub-account rate limiter wait error: %w", err)
+	}
+
+	resp, err := e.client.NewGetSubMembersRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sub members, err: %w", err)
+	}
+
+	subAccounts := make([]types.SubAccount, 0, len(resp.SubMembers))
+	for _, m := range resp.SubMembers {
+		subAccounts = append(subAccounts, types.SubAccount{
+			Name:   m.Uid,
+			Label:  m.Username,
+			Frozen: m.Status != bybitapi.SubMemberStatusNormal,
+		})
+	}
+	return subAccounts, nil
+}
+
+// QuerySubAccountBalances queries the balances of the unified trading account of the sub-account
+func (e *Exchange) QuerySubAccountBalances(ctx context.Context, subAccount string) (types.BalanceMap, error) {
+	if err := transferRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("transfer rate limiter wait error: %w", err)
+	}
+
+	resp, err := e.client.NewGetAccountCoinsBalanceRequest().
+		MemberId(subAccount).
+		AccountType(bybitapi.AccountTypeUnified).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the balances of sub member %s, err: %w", subAccount, err)
+	}
+
+	balances := types.BalanceMap{}
+	for _, b := range resp.Balance {
+		if b.WalletBalance.IsZero() {
+			continue
+		}
+
+		balances[b.Coin] = types.Balance{
+			Currency:  b.Coin,
+			Available: b.TransferBalance,
+			Locked:    b.WalletBalance.Sub(b.TransferBalance),
+		}
+	}
+	return balances, nil
+}
+
+// TransferSubAccountAsset transfers the asset between the unified trading accounts of the master and the sub-account
+func (e *Exchange) TransferSubAccountAsset(
+	ctx context.Context, subAccount, asset string, amount fixedpoint.Value, io types.TransferDirection,
+) error {
+	subMemberId, err := strconv.ParseInt(subAccount, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid sub member id %q, err: %w", subAccount, err)
+	}
+
+	masterId, err := e.queryUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := e.client.NewUniversalTransferRequest().
+		Coin(asset).
+		Amount(amount.String())
+
+	switch io {
+	case types.TransferIn:
+		req.FromMemberId(masterId).ToMemberId(subMemberId)
+	case types.TransferOut:
+		req.FromMemberId(subMemberId).ToMemberId(masterId)
+	default:
+		return fmt.Errorf("unexpected transfer direction: %d given", io)
+	}
+
+	if err := transferRateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("transfer rate limiter wait error: %w", err)
+	}
+
+	resp, err := req.Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to transfer %s %s with sub member %s, err: %w", amount, asset, subAccount, err)
+	}
+
+	log.Infof("universal transfer response: %+v", resp)
+	return nil
+}
+
+// InternalTransfer transfers the asset between the funding and the unified trading accounts. The spot, margin and
+// futures accounts are the same unified trading account, the transfers between them are no-ops.
+func (e *Exchange) InternalTransfer(
+	ctx context.Context, asset string, amount fixedpoint.Value, from, to types.AccountType,
+) error {
+	fromAccount, err := toLocalTransferAccountType(from)
+	if err != nil {
+		return err
+	}
+
+	toAccount, err := toLocalTransferAccountType(to)
+	if err != nil {
+		return err
+	}
+
+	if fromAccount == toAccount {
+		log.Infof("skip the transfer of %s %s from %s to %s, they are the same unified trading account", amount, asset, from, to)
+		return nil
+	}
+
+	if err := transferRateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("transfer rate limiter wait error: %w", err)
+	}
+
+	resp, err := e.client.NewInterTransferRequest().
+		Coin(asset).
+		Amount(amount.String()).
+		FromAccountType(fromAccount).
+		ToAccountType(toAccount).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to transfer %s %s from %s to %s, err: %w", amount, asset, from, to, err)
+	}
+
+	log.Infof("inter transfer response: %+v", resp)
+	return nil
+}
+
+// queryUserID queries the user id of the api key, which is the member id of the master account
+func (e *Exchange) queryUserID(ctx context.Context) (int64, error) {
+	if err := sharedRateLimiter.Wait(ctx); err != nil {
+		return 0, fmt.Errorf("api key info rate limiter wait error: %w", err)
+	}
+
+	info, err := e.client.NewGetAPIKeyInfoRequest().Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query api key info, err: %w", err)
+	}
+
+	return info.UserID, nil
+}
+
+func toLocalTransferAccountType(accountType types.AccountType) (bybitapi.AccountType, error) {
+	switch accountType {
+	case types.AccountTypeSpot, types.AccountTypeMargin, types.AccountTypeFutures:
+		return bybitapi.AccountTypeUnified, nil
+	case types.AccountTypeFunding:
+		return bybitapi.AccountTypeFund, nil
+	}
+
+	return "", fmt.Errorf("unsupported transfer account type: %s", accountType)
+}
@@ -108,6 +108,7 @@ const (
 	TopicTypeOrder       TopicType = "order"
 	TopicTypeKLine       TopicType = "kline"
 	TopicTypeTrade       TopicType = "execution"
+	TopicTypePosition    TopicType = "position"
 )
 
 type DataType string
@@ -233,6 +234,12 @@ type OrderEvent struct {
 	Category bybitapi.Category `json:"category"`
 }
 
+type PositionEvent struct {
+	bybitapi.PositionInfo
+
+	Category bybitapi.Category `json:"category"`
+}
+
 type KLineEvent struct {
 	KLines []KLine
 
@@ -342,6 +349,32 @@ type TradeEvent struct {
 	TradeIv string `json:"tradeIv"`
 }
 
+// toExecution converts the trade event of the linear category into the execution, so the trade event and the
+// execution from the restful api share the same conversion.
+func (t *TradeEvent) toExecution() bybitapi.Execution {
+	return bybitapi.Execution{
+		Symbol:      t.Symbol,
+		OrderId:     t.OrderId,
+		OrderLinkId: t.OrderLinkId,
+		Side:        t.Side,
+		OrderPrice:  t.OrderPrice,
+		OrderQty:    t.OrderQty,
+		LeavesQty:   t.LeavesQty,
+		OrderType:   t.OrderType,
+		ExecFee:     t.ExecFee,
+		ExecId:      t.ExecId,
+		ExecPrice:   t.ExecPrice,
+		ExecQty:     t.ExecQty,
+		ExecType:    bybitapi.ExecType(t.ExecType),
+		ExecValue:   t.ExecValue,
+		FeeRate:     t.FeeRate,
+		MarkPrice:   t.MarkPrice,
+		IsMaker:     t.IsMaker,
+		ClosedSize:  t.ClosedSize,
+		ExecTime:    t.ExecTime,
+	}
+}
+
 func (t *TradeEvent) toGlobalTrade(symbolFee symbolFeeDetail) (*types.Trade, error) {
 	if t.Category != bybitapi.CategorySpot {
 		return nil, fmt.Errorf("unexected category: %s", t.Category)
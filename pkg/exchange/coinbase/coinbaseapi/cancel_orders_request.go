@@ -0,0 +1,30 @@
+package coinbaseapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type CancelOrderResult struct {
+	Success       bool   `json:"success"`
+	FailureReason string `json:"failure_reason"`
+	OrderId       string `json:"order_id"`
+}
+
+type CancelOrdersResponse struct {
+	Results []CancelOrderResult `json:"results"`
+}
+
+//go:generate PostRequest -url "/api/v3/brokerage/orders/batch_cancel" -type CancelOrdersRequest -responseType .CancelOrdersResponse
+type CancelOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	// orderIds is limited to 100 per request
+	orderIds []string `param:"order_ids"`
+}
+
+func (c *RestClient) NewCancelOrdersRequest() *CancelOrdersRequest {
+	return &CancelOrdersRequest{client: c}
+}
@@ -0,0 +1,159 @@
+package coinbaseapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/c9s/requestgen"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultHTTPTimeout = time.Second * 15
+
+	RestBaseURL = "https://api.coinbase.com"
+
+	// WsMarketDataURL is the endpoint of the market data channels, e.g. level2 and market_trades
+	WsMarketDataURL = "wss://advanced-trade-ws.coinbase.com"
+	// WsUserURL is the endpoint of the user order data
+	WsUserURL = "wss://advanced-trade-ws-user.coinbase.com"
+)
+
+type RestClient struct {
+	requestgen.BaseAPIClient
+
+	signer *JWTSigner
+}
+
+func NewClient() *RestClient {
+	u, err := url.Parse(RestBaseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &RestClient{
+		BaseAPIClient: requestgen.BaseAPIClient{
+			BaseURL: u,
+			HttpClient: &http.Client{
+				Timeout: defaultHTTPTimeout,
+			},
+		},
+	}
+}
+
+// Auth sets up the CDP API key, the key name is in the format of `organizations/{org_id}/apiKeys/{key_id}` and the
+// secret is the ECDSA private key in PEM format or the base64 encoded Ed25519 private key.
+func (c *RestClient) Auth(keyName, secret string) error {
+	signer, err := NewJWTSigner(keyName, secret)
+	if err != nil {
+		return err
+	}
+
+	c.signer = signer
+	return nil
+}
+
+// Signer returns the JWT signer, it's nil if the client is not authenticated.
+func (c *RestClient) Signer() *JWTSigner {
+	return c.signer
+}
+
+// NewAuthenticatedRequest creates new http request for authenticated routes.
+func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	if c.signer == nil {
+		return nil, errors.New("empty api key or secret")
+	}
+
+	rel, err := url.Parse(refURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		rel.RawQuery = params.Encode()
+	}
+
+	pathURL := c.BaseURL.ResolveReference(rel)
+	body, err := castPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// See https://docs.cdp.coinbase.com/advanced-trade/docs/rest-api-auth
+	//
+	// The uri claim is the request method and the host + path without the query string.
+	token, err := c.signer.Sign(method + " " + pathURL.Host + pathURL.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign jwt: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+token)
+	return req, nil
+}
+
+// SendRequest sends the request and converts the non-2xx responses into the APIError.
+func (c *RestClient) SendRequest(req *http.Request) (*requestgen.Response, error) {
+	resp, err := c.BaseAPIClient.SendRequest(req)
+	if err != nil {
+		if resp != nil && len(resp.Body) > 0 {
+			var apiErr APIError
+			if jsonErr := json.Unmarshal(resp.Body, &apiErr); jsonErr == nil && (apiErr.ErrorType != "" || apiErr.Message != "") {
+				apiErr.StatusCode = resp.StatusCode
+				return resp, &apiErr
+			}
+		}
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func castPayload(payload interface{}) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+
+	switch v := payload.(type) {
+	case string:
+		return []byte(v), nil
+
+	case []byte:
+		return v, nil
+
+	}
+	return json.Marshal(payload)
+}
+
+/*
+sample:
+
+{
+  "error": "INVALID_ARGUMENT",
+  "error_details": "account is not available",
+  "message": "account is not available"
+}
+*/
+
+// APIError is returned by the server with a non-2xx status code.
+type APIError struct {
+	StatusCode   int    `json:"-"`
+	ErrorType    string `json:"error"`
+	ErrorDetails string `json:"error_details"`
+	Message      string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status: %d, error: %s, message: %s", e.StatusCode, e.ErrorType, e.Message)
+}
@@ -0,0 +1,58 @@
+package coinbaseapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestRestClient_NewAuthenticatedRequest(t *testing.T) {
+	_, secret := newTestECKey(t)
+	client := NewClient()
+	assert.NoError(t, client.Auth(testKeyName, secret))
+
+	params := url.Values{}
+	params.Set("limit", "250")
+	req, err := client.NewAuthenticatedRequest(context.Background(), http.MethodGet, "/api/v3/brokerage/accounts", params, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.coinbase.com/api/v3/brokerage/accounts?limit=250", req.URL.String())
+
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	segments := strings.Split(token, ".")
+	assert.Len(t, segments, 3)
+
+	// the query string is excluded from the uri claim
+	var claims jwtClaims
+	decodeSegment(t, segments[1], &claims)
+	assert.Equal(t, "GET api.coinbase.com/api/v3/brokerage/accounts", claims.Uri)
+
+	_, err = NewClient().NewAuthenticatedRequest(context.Background(), http.MethodGet, "/api/v3/brokerage/accounts", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestRestClient_SendRequest(t *testing.T) {
+	_, secret := newTestECKey(t)
+	client := NewClient()
+	assert.NoError(t, client.Auth(testKeyName, secret))
+
+	transport := &httptesting.MockTransport{}
+	client.HttpClient.Transport = transport
+	transport.GET("/api/v3/brokerage/accounts", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusBadRequest,
+			`{"error":"INVALID_ARGUMENT","error_details":"account is not available","message":"account is not available"}`), nil
+	})
+
+	_, err := client.NewGetAccountsRequest().Do(context.Background())
+	assert.Equal(t, &APIError{
+		StatusCode:   http.StatusBadRequest,
+		ErrorType:    "INVALID_ARGUMENT",
+		ErrorDetails: "account is not available",
+		Message:      "account is not available",
+	}, err)
+}
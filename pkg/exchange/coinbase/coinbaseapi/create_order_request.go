@@ -0,0 +1,71 @@
+package coinbaseapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+// The sizes and the prices of the order configuration are decimal strings.
+
+type MarketIoc struct {
+	QuoteSize string `json:"quote_size,omitempty"`
+	BaseSize  string `json:"base_size,omitempty"`
+}
+
+type LimitGtc struct {
+	BaseSize   string `json:"base_size"`
+	LimitPrice string `json:"limit_price"`
+	PostOnly   bool   `json:"post_only"`
+}
+
+type LimitIoc struct {
+	BaseSize   string `json:"base_size"`
+	LimitPrice string `json:"limit_price"`
+}
+
+// OrderConfiguration has one and only one of the fields, the field decides the order type and the time in force.
+type OrderConfiguration struct {
+	MarketMarketIoc *MarketIoc `json:"market_market_ioc,omitempty"`
+	LimitLimitGtc   *LimitGtc  `json:"limit_limit_gtc,omitempty"`
+	LimitLimitFok   *LimitIoc  `json:"limit_limit_fok,omitempty"`
+	SorLimitIoc     *LimitIoc  `json:"sor_limit_ioc,omitempty"`
+}
+
+type CreateOrderSuccessResponse struct {
+	OrderId       string `json:"order_id"`
+	ProductId     string `json:"product_id"`
+	Side          Side   `json:"side"`
+	ClientOrderId string `json:"client_order_id"`
+}
+
+type CreateOrderErrorResponse struct {
+	Error                 string `json:"error"`
+	Message               string `json:"message"`
+	ErrorDetails          string `json:"error_details"`
+	PreviewFailureReason  string `json:"preview_failure_reason"`
+	NewOrderFailureReason string `json:"new_order_failure_reason"`
+}
+
+type CreateOrderResponse struct {
+	Success         bool                        `json:"success"`
+	FailureReason   string                      `json:"failure_reason"`
+	OrderId         string                      `json:"order_id"`
+	SuccessResponse *CreateOrderSuccessResponse `json:"success_response"`
+	ErrorResponse   *CreateOrderErrorResponse   `json:"error_response"`
+}
+
+//go:generate PostRequest -url "/api/v3/brokerage/orders" -type CreateOrderRequest -responseType .CreateOrderResponse
+type CreateOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	clientOrderId      string             `param:"client_order_id,required"`
+	productId          string             `param:"product_id,required"`
+	side               Side               `param:"side" validValues:"BUY,SELL"`
+	orderConfiguration OrderConfiguration `param:"order_configuration"`
+}
+
+func (c *RestClient) NewCreateOrderRequest() *CreateOrderRequest {
+	return &CreateOrderRequest{client: c}
+}
@@ -0,0 +1,218 @@
+// Code generated by "requestgen -method POST -url /api/v3/brokerage/orders -type CreateOrderRequest -responseType .CreateOrderResponse"; DO NOT EDIT.
+
+package coinbaseapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (c *CreateOrderRequest) ClientOrderId(clientOrderId string) *CreateOrderRequest {
+	c.clientOrderId = clientOrderId
+	return c
+}
+
+func (c *CreateOrderRequest) ProductId(productId string) *CreateOrderRequest {
+	c.productId = productId
+	return c
+}
+
+func (c *CreateOrderRequest) Side(side Side) *CreateOrderRequest {
+	c.side = side
+	return c
+}
+
+func (c *CreateOrderRequest) OrderConfiguration(orderConfiguration OrderConfiguration) *CreateOrderRequest {
+	c.orderConfiguration = orderConfiguration
+	return c
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (c *CreateOrderRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (c *CreateOrderRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check clientOrderId field -> json key client_order_id
+	clientOrderId := c.clientOrderId
+
+	// TEMPLATE check-required
+	if len(clientOrderId) == 0 {
+		return nil, fmt.Errorf("client_order_id is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of clientOrderId
+	params["client_order_id"] = clientOrderId
+	// check productId field -> json key product_id
+	productId := c.productId
+
+	// TEMPLATE check-required
+	if len(productId) == 0 {
+		return nil, fmt.Errorf("product_id is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of productId
+	params["product_id"] = productId
+	// check side field -> json key side
+	side := c.side
+
+	// TEMPLATE check-valid-values
+	switch side {
+	case "BUY", "SELL":
+		params["side"] = side
+
+	default:
+		return nil, fmt.Errorf("side value %v is invalid", side)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of side
+	params["side"] = side
+	// check orderConfiguration field -> json key order_configuration
+	orderConfiguration := c.orderConfiguration
+
+	// assign parameter of orderConfiguration
+	params["order_configuration"] = orderConfiguration
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (c *CreateOrderRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := c.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if c.isVarSlice(_v) {
+			c.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (c *CreateOrderRequest) GetParametersJSON() ([]byte, error) {
+	params, err := c.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (c *CreateOrderRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (c *CreateOrderRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (c *CreateOrderRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (c *CreateOrderRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (c *CreateOrderRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := c.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (c *CreateOrderRequest) GetPath() string {
+	return "/api/v3/brokerage/orders"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (c *CreateOrderRequest) Do(ctx context.Context) (*CreateOrderResponse, error) {
+
+	params, err := c.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = c.GetPath()
+
+	req, err := c.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse CreateOrderResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &apiResponse, nil
+}
@@ -0,0 +1,40 @@
+package coinbaseapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type Account struct {
+	Uuid             string `json:"uuid"`
+	Name             string `json:"name"`
+	Currency         string `json:"currency"`
+	AvailableBalance Money  `json:"available_balance"`
+	Default          bool   `json:"default"`
+	Active           bool   `json:"active"`
+	Type             string `json:"type"`
+	Ready            bool   `json:"ready"`
+	Hold             Money  `json:"hold"`
+}
+
+type AccountsResponse struct {
+	Accounts []Account `json:"accounts"`
+	HasNext  bool      `json:"has_next"`
+	Cursor   string    `json:"cursor"`
+	Size     int       `json:"size"`
+}
+
+//go:generate GetRequest -url "/api/v3/brokerage/accounts" -type GetAccountsRequest -responseType .AccountsResponse
+type GetAccountsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	// limit is the max number of the accounts, the max value is 250
+	limit  *uint64 `param:"limit,query"`
+	cursor *string `param:"cursor,query"`
+}
+
+func (c *RestClient) NewGetAccountsRequest() *GetAccountsRequest {
+	return &GetAccountsRequest{client: c}
+}
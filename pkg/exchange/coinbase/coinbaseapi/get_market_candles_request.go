@@ -0,0 +1,43 @@
+package coinbaseapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type Candle struct {
+	Start  types.MillisecondTimestamp `json:"start"`
+	Low    fixedpoint.Value           `json:"low"`
+	High   fixedpoint.Value           `json:"high"`
+	Open   fixedpoint.Value           `json:"open"`
+	Close  fixedpoint.Value           `json:"close"`
+	Volume fixedpoint.Value           `json:"volume"`
+}
+
+type CandlesResponse struct {
+	Candles []Candle `json:"candles"`
+}
+
+// GetMarketCandlesRequest returns at most 350 candles in descending order.
+//
+//go:generate GetRequest -url "/api/v3/brokerage/market/products/:productId/candles" -type GetMarketCandlesRequest -responseType .CandlesResponse
+type GetMarketCandlesRequest struct {
+	client requestgen.APIClient
+
+	productId   string      `param:"productId,slug,required"`
+	start       time.Time   `param:"start,query,seconds"`
+	end         time.Time   `param:"end,query,seconds"`
+	granularity Granularity `param:"granularity,query"`
+	limit       *uint64     `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetMarketCandlesRequest() *GetMarketCandlesRequest {
+	return &GetMarketCandlesRequest{client: c}
+}
@@ -0,0 +1,236 @@
+// Code generated by "requestgen -method GET -url /api/v3/brokerage/market/products/:productId/candles -type GetMarketCandlesRequest -responseType .CandlesResponse"; DO NOT EDIT.
+
+package coinbaseapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+func (g *GetMarketCandlesRequest) Start(start time.Time) *GetMarketCandlesRequest {
+	g.start = start
+	return g
+}
+
+func (g *GetMarketCandlesRequest) End(end time.Time) *GetMarketCandlesRequest {
+	g.end = end
+	return g
+}
+
+func (g *GetMarketCandlesRequest) Granularity(granularity Granularity) *GetMarketCandlesRequest {
+	g.granularity = granularity
+	return g
+}
+
+func (g *GetMarketCandlesRequest) Limit(limit uint64) *GetMarketCandlesRequest {
+	g.limit = &limit
+	return g
+}
+
+func (g *GetMarketCandlesRequest) ProductId(productId string) *GetMarketCandlesRequest {
+	g.productId = productId
+	return g
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (g *GetMarketCandlesRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check start field -> json key start
+	start := g.start
+
+	// assign parameter of start
+	// convert time.Time to seconds time stamp
+	params["start"] = strconv.FormatInt(start.Unix(), 10)
+	// check end field -> json key end
+	end := g.end
+
+	// assign parameter of end
+	// convert time.Time to seconds time stamp
+	params["end"] = strconv.FormatInt(end.Unix(), 10)
+	// check granularity field -> json key granularity
+	granularity := g.granularity
+
+	// TEMPLATE check-valid-values
+	switch granularity {
+	case GranularityOneMinute, GranularityFiveMinute, GranularityFifteenMinute, GranularityThirtyMinute, GranularityOneHour, GranularityTwoHour, GranularitySixHour, GranularityOneDay:
+		params["granularity"] = granularity
+
+	default:
+		return nil, fmt.Errorf("granularity value %v is invalid", granularity)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of granularity
+	params["granularity"] = granularity
+	// check limit field -> json key limit
+	if g.limit != nil {
+		limit := *g.limit
+
+		// assign parameter of limit
+		params["limit"] = limit
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (g *GetMarketCandlesRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (g *GetMarketCandlesRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := g.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if g.isVarSlice(_v) {
+			g.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (g *GetMarketCandlesRequest) GetParametersJSON() ([]byte, error) {
+	params, err := g.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (g *GetMarketCandlesRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check productId field -> json key productId
+	productId := g.productId
+
+	// TEMPLATE check-required
+	if len(productId) == 0 {
+		return nil, fmt.Errorf("productId is required, empty string given")
+	}
+	// END TEMPLATE check-required
+
+	// assign parameter of productId
+	params["productId"] = productId
+
+	return params, nil
+}
+
+func (g *GetMarketCandlesRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (g *GetMarketCandlesRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (g *GetMarketCandlesRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (g *GetMarketCandlesRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := g.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (g *GetMarketCandlesRequest) GetPath() string {
+	return "/api/v3/brokerage/market/products/:productId/candles"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (g *GetMarketCandlesRequest) Do(ctx context.Context) (*CandlesResponse, error) {
+
+	// no body params
+	var params interface{}
+	query, err := g.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = g.GetPath()
+	slugs, err := g.GetSlugsMap()
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL = g.applySlugsToUrl(apiURL, slugs)
+
+	req, err := g.client.NewRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse CandlesResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &apiResponse, nil
+}
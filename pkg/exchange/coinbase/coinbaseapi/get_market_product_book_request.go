@@ -0,0 +1,40 @@
+package coinbaseapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type PriceLevel struct {
+	Price fixedpoint.Value `json:"price"`
+	Size  fixedpoint.Value `json:"size"`
+}
+
+type PriceBook struct {
+	ProductId string       `json:"product_id"`
+	Bids      []PriceLevel `json:"bids"`
+	Asks      []PriceLevel `json:"asks"`
+	Time      time.Time    `json:"time"`
+}
+
+type ProductBookResponse struct {
+	PriceBook PriceBook `json:"pricebook"`
+}
+
+//go:generate GetRequest -url "/api/v3/brokerage/market/product_book" -type GetMarketProductBookRequest -responseType .ProductBookResponse
+type GetMarketProductBookRequest struct {
+	client requestgen.APIClient
+
+	productId string  `param:"product_id,query"`
+	limit     *uint64 `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetMarketProductBookRequest() *GetMarketProductBookRequest {
+	return &GetMarketProductBookRequest{client: c}
+}
@@ -0,0 +1,19 @@
+package coinbaseapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+//go:generate GetRequest -url "/api/v3/brokerage/market/products/:productId" -type GetMarketProductRequest -responseType .Product
+type GetMarketProductRequest struct {
+	client requestgen.APIClient
+
+	productId string `param:"productId,slug,required"`
+}
+
+func (c *RestClient) NewGetMarketProductRequest() *GetMarketProductRequest {
+	return &GetMarketProductRequest{client: c}
+}
@@ -0,0 +1,54 @@
+package coinbaseapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type Product struct {
+	ProductId                string           `json:"product_id"`
+	Price                    fixedpoint.Value `json:"price"`
+	PricePercentageChange24h fixedpoint.Value `json:"price_percentage_change_24h"`
+	Volume24h                fixedpoint.Value `json:"volume_24h"`
+	BaseIncrement            fixedpoint.Value `json:"base_increment"`
+	QuoteIncrement           fixedpoint.Value `json:"quote_increment"`
+	PriceIncrement           fixedpoint.Value `json:"price_increment"`
+	BaseMinSize              fixedpoint.Value `json:"base_min_size"`
+	BaseMaxSize              fixedpoint.Value `json:"base_max_size"`
+	QuoteMinSize             fixedpoint.Value `json:"quote_min_size"`
+	QuoteMaxSize             fixedpoint.Value `json:"quote_max_size"`
+	BaseCurrencyId           string           `json:"base_currency_id"`
+	QuoteCurrencyId          string           `json:"quote_currency_id"`
+	Status                   string           `json:"status"`
+	CancelOnly               bool             `json:"cancel_only"`
+	LimitOnly                bool             `json:"limit_only"`
+	PostOnly                 bool             `json:"post_only"`
+	TradingDisabled          bool             `json:"trading_disabled"`
+	IsDisabled               bool             `json:"is_disabled"`
+	ProductType              ProductType      `json:"product_type"`
+}
+
+type ProductsResponse struct {
+	Products    []Product `json:"products"`
+	NumProducts int       `json:"num_products"`
+}
+
+//go:generate GetRequest -url "/api/v3/brokerage/market/products" -type GetMarketProductsRequest -responseType .ProductsResponse
+type GetMarketProductsRequest struct {
+	client requestgen.APIClient
+
+	productType ProductType `param:"product_type,query" validValues:"SPOT,FUTURE"`
+	limit       *uint64     `param:"limit,query"`
+	offset      *uint64     `param:"offset,query"`
+}
+
+func (c *RestClient) NewGetMarketProductsRequest() *GetMarketProductsRequest {
+	return &GetMarketProductsRequest{
+		client:      c,
+		productType: ProductTypeSpot,
+	}
+}
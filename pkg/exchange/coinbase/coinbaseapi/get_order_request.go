@@ -0,0 +1,52 @@
+package coinbaseapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type Order struct {
+	OrderId              string             `json:"order_id"`
+	ProductId            string             `json:"product_id"`
+	UserId               string             `json:"user_id"`
+	OrderConfiguration   OrderConfiguration `json:"order_configuration"`
+	Side                 Side               `json:"side"`
+	ClientOrderId        string             `json:"client_order_id"`
+	Status               OrderStatus        `json:"status"`
+	TimeInForce          TimeInForce        `json:"time_in_force"`
+	CreatedTime          time.Time          `json:"created_time"`
+	LastFillTime         *time.Time         `json:"last_fill_time"`
+	CompletionPercentage fixedpoint.Value   `json:"completion_percentage"`
+	FilledSize           fixedpoint.Value   `json:"filled_size"`
+	AverageFilledPrice   fixedpoint.Value   `json:"average_filled_price"`
+	NumberOfFills        fixedpoint.Value   `json:"number_of_fills"`
+	FilledValue          fixedpoint.Value   `json:"filled_value"`
+	PendingCancel        bool               `json:"pending_cancel"`
+	SizeInQuote          bool               `json:"size_in_quote"`
+	TotalFees            fixedpoint.Value   `json:"total_fees"`
+	OrderType            OrderType          `json:"order_type"`
+	RejectReason         string             `json:"reject_reason"`
+	Settled              bool               `json:"settled"`
+	ProductType          ProductType        `json:"product_type"`
+}
+
+type OrderResponse struct {
+	Order Order `json:"order"`
+}
+
+//go:generate GetRequest -url "/api/v3/brokerage/orders/historical/:orderId" -type GetOrderRequest -responseType .OrderResponse
+type GetOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	orderId string `param:"orderId,slug,required"`
+}
+
+func (c *RestClient) NewGetOrderRequest() *GetOrderRequest {
+	return &GetOrderRequest{client: c}
+}
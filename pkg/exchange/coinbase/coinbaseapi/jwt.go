@@ -0,0 +1,170 @@
+package coinbaseapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	jwtIssuer = "cdp"
+	// jwtExpiration is the max lifetime of the JWT accepted by the server
+	jwtExpiration = 2 * time.Minute
+)
+
+// JWTSigner signs the JSON Web Tokens with the CDP API key. Both of the ECDSA (ES256) and Ed25519 (EdDSA) keys are
+// supported.
+type JWTSigner struct {
+	keyName    string
+	privateKey crypto.Signer
+
+	// nowFunc is used to mock the current time in the tests
+	nowFunc func() time.Time
+}
+
+func NewJWTSigner(keyName, secret string) (*JWTSigner, error) {
+	if len(keyName) == 0 || len(secret) == 0 {
+		return nil, errors.New("empty api key or secret")
+	}
+
+	privateKey, err := parsePrivateKey(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTSigner{
+		keyName:    keyName,
+		privateKey: privateKey,
+		nowFunc:    time.Now,
+	}, nil
+}
+
+func parsePrivateKey(secret string) (crypto.Signer, error) {
+	// the new lines of the PEM are usually escaped in the environment variables
+	secret = strings.TrimSpace(strings.ReplaceAll(secret, `\n`, "\n"))
+
+	block, _ := pem.Decode([]byte(secret))
+	if block == nil {
+		// the Ed25519 keys are provided in base64 without the PEM block
+		raw, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil || len(raw) != ed25519.PrivateKeySize {
+			return nil, errors.New("unexpected secret, it must be an ECDSA key in PEM format or a base64 encoded Ed25519 key")
+		}
+		return ed25519.PrivateKey(raw), nil
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			return k, nil
+		case ed25519.PrivateKey:
+			return k, nil
+		}
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+
+	return nil, fmt.Errorf("unsupported pem block type: %s", block.Type)
+}
+
+type jwtHeader struct {
+	Alg   string `json:"alg"`
+	Kid   string `json:"kid"`
+	Nonce string `json:"nonce"`
+	Typ   string `json:"typ"`
+}
+
+type jwtClaims struct {
+	Sub string `json:"sub"`
+	Iss string `json:"iss"`
+	Nbf int64  `json:"nbf"`
+	Exp int64  `json:"exp"`
+	// Uri is only required by the REST requests
+	Uri string `json:"uri,omitempty"`
+}
+
+// Sign generates a JWT for the given uri, the uri is in the format of `GET api.coinbase.com/api/v3/brokerage/accounts`.
+// The websocket subscriptions use an empty uri.
+func (s *JWTSigner) Sign(uri string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	alg := "ES256"
+	if _, ok := s.privateKey.(ed25519.PrivateKey); ok {
+		alg = "EdDSA"
+	}
+
+	header, err := json.Marshal(jwtHeader{
+		Alg:   alg,
+		Kid:   s.keyName,
+		Nonce: hex.EncodeToString(nonce),
+		Typ:   "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	now := s.nowFunc()
+	claims, err := json.Marshal(jwtClaims{
+		Sub: s.keyName,
+		Iss: jwtIssuer,
+		Nbf: now.Unix(),
+		Exp: now.Add(jwtExpiration).Unix(),
+		Uri: uri,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature, err := s.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (s *JWTSigner) sign(input []byte) ([]byte, error) {
+	switch key := s.privateKey.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, input), nil
+
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(input)
+		r, ss, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			return nil, err
+		}
+
+		// the ES256 signature is the concatenation of the 32 bytes big endian r and s
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		ss.FillBytes(signature[size:])
+		return signature, nil
+	}
+
+	return nil, fmt.Errorf("unsupported private key type: %T", s.privateKey)
+}
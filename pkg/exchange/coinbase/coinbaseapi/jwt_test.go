@@ -0,0 +1,124 @@
+package coinbaseapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeyName = "organizations/org-id/apiKeys/key-id"
+
+// newTestECKey generates an ECDSA key and returns the key with the PEM format whose new lines are escaped.
+func newTestECKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	secret := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	return key, strings.ReplaceAll(secret, "\n", `\n`)
+}
+
+func decodeSegment(t *testing.T, segment string, v interface{}) {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(raw, v))
+}
+
+func TestJWTSigner_SignES256(t *testing.T) {
+	key, secret := newTestECKey(t)
+	signer, err := NewJWTSigner(testKeyName, secret)
+	assert.NoError(t, err)
+
+	now := time.Unix(1717027200, 0)
+	signer.nowFunc = func() time.Time { return now }
+
+	token, err := signer.Sign("GET api.coinbase.com/api/v3/brokerage/accounts")
+	assert.NoError(t, err)
+
+	segments := strings.Split(token, ".")
+	assert.Len(t, segments, 3)
+
+	var header jwtHeader
+	decodeSegment(t, segments[0], &header)
+	assert.Equal(t, "ES256", header.Alg)
+	assert.Equal(t, testKeyName, header.Kid)
+	assert.Equal(t, "JWT", header.Typ)
+	assert.Len(t, header.Nonce, 32)
+
+	var claims jwtClaims
+	decodeSegment(t, segments[1], &claims)
+	assert.Equal(t, jwtClaims{
+		Sub: testKeyName,
+		Iss: "cdp",
+		Nbf: 1717027200,
+		Exp: 1717027320,
+		Uri: "GET api.coinbase.com/api/v3/brokerage/accounts",
+	}, claims)
+
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	assert.NoError(t, err)
+	assert.Len(t, signature, 64)
+
+	digest := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], r, s))
+}
+
+func TestJWTSigner_SignEdDSA(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	signer, err := NewJWTSigner(testKeyName, base64.StdEncoding.EncodeToString(priv))
+	assert.NoError(t, err)
+
+	// the websocket subscriptions don't have the uri claim
+	token, err := signer.Sign("")
+	assert.NoError(t, err)
+
+	segments := strings.Split(token, ".")
+	assert.Len(t, segments, 3)
+
+	var header jwtHeader
+	decodeSegment(t, segments[0], &header)
+	assert.Equal(t, "EdDSA", header.Alg)
+
+	var claims map[string]interface{}
+	decodeSegment(t, segments[1], &claims)
+	assert.NotContains(t, claims, "uri")
+
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(pub, []byte(segments[0]+"."+segments[1]), signature))
+}
+
+func TestNewJWTSigner(t *testing.T) {
+	_, err := NewJWTSigner("", "secret")
+	assert.Error(t, err)
+
+	_, err = NewJWTSigner(testKeyName, "invalid secret")
+	assert.Error(t, err)
+
+	// PKCS8 encoded ECDSA key
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	signer, err := NewJWTSigner(testKeyName, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	assert.NoError(t, err)
+	assert.IsType(t, &ecdsa.PrivateKey{}, signer.privateKey)
+}
@@ -0,0 +1,54 @@
+package coinbaseapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type Fill struct {
+	EntryId            string             `json:"entry_id"`
+	TradeId            string             `json:"trade_id"`
+	OrderId            string             `json:"order_id"`
+	TradeTime          time.Time          `json:"trade_time"`
+	TradeType          string             `json:"trade_type"`
+	Price              fixedpoint.Value   `json:"price"`
+	Size               fixedpoint.Value   `json:"size"`
+	Commission         fixedpoint.Value   `json:"commission"`
+	ProductId          string             `json:"product_id"`
+	SequenceTimestamp  time.Time          `json:"sequence_timestamp"`
+	LiquidityIndicator LiquidityIndicator `json:"liquidity_indicator"`
+	// SizeInQuote is true if the size is in the quote currency
+	SizeInQuote bool   `json:"size_in_quote"`
+	UserId      string `json:"user_id"`
+	Side        Side   `json:"side"`
+}
+
+type FillsResponse struct {
+	Fills  []Fill `json:"fills"`
+	Cursor string `json:"cursor"`
+}
+
+// ListFillsRequest returns the fills in descending order by the trade time.
+//
+//go:generate GetRequest -url "/api/v3/brokerage/orders/historical/fills" -type ListFillsRequest -responseType .FillsResponse
+type ListFillsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	orderId                *string    `param:"order_id,query"`
+	productId              *string    `param:"product_id,query"`
+	startSequenceTimestamp *time.Time `param:"start_sequence_timestamp,query" timeFormat:"RFC3339"`
+	endSequenceTimestamp   *time.Time `param:"end_sequence_timestamp,query" timeFormat:"RFC3339"`
+
+	limit  *uint64 `param:"limit,query"`
+	cursor *string `param:"cursor,query"`
+}
+
+func (c *RestClient) NewListFillsRequest() *ListFillsRequest {
+	return &ListFillsRequest{client: c}
+}
@@ -0,0 +1,235 @@
+// Code generated by "requestgen -method GET -url /api/v3/brokerage/orders/historical/fills -type ListFillsRequest -responseType .FillsResponse"; DO NOT EDIT.
+
+package coinbaseapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+func (l *ListFillsRequest) OrderId(orderId string) *ListFillsRequest {
+	l.orderId = &orderId
+	return l
+}
+
+func (l *ListFillsRequest) ProductId(productId string) *ListFillsRequest {
+	l.productId = &productId
+	return l
+}
+
+func (l *ListFillsRequest) StartSequenceTimestamp(startSequenceTimestamp time.Time) *ListFillsRequest {
+	l.startSequenceTimestamp = &startSequenceTimestamp
+	return l
+}
+
+func (l *ListFillsRequest) EndSequenceTimestamp(endSequenceTimestamp time.Time) *ListFillsRequest {
+	l.endSequenceTimestamp = &endSequenceTimestamp
+	return l
+}
+
+func (l *ListFillsRequest) Limit(limit uint64) *ListFillsRequest {
+	l.limit = &limit
+	return l
+}
+
+func (l *ListFillsRequest) Cursor(cursor string) *ListFillsRequest {
+	l.cursor = &cursor
+	return l
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (l *ListFillsRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check orderId field -> json key order_id
+	if l.orderId != nil {
+		orderId := *l.orderId
+
+		// assign parameter of orderId
+		params["order_id"] = orderId
+	} else {
+	}
+	// check productId field -> json key product_id
+	if l.productId != nil {
+		productId := *l.productId
+
+		// assign parameter of productId
+		params["product_id"] = productId
+	} else {
+	}
+	// check startSequenceTimestamp field -> json key start_sequence_timestamp
+	if l.startSequenceTimestamp != nil {
+		startSequenceTimestamp := *l.startSequenceTimestamp
+
+		// assign parameter of startSequenceTimestamp
+		params["start_sequence_timestamp"] = startSequenceTimestamp.Format("2006-01-02T15:04:05Z07:00")
+	} else {
+	}
+	// check endSequenceTimestamp field -> json key end_sequence_timestamp
+	if l.endSequenceTimestamp != nil {
+		endSequenceTimestamp := *l.endSequenceTimestamp
+
+		// assign parameter of endSequenceTimestamp
+		params["end_sequence_timestamp"] = endSequenceTimestamp.Format("2006-01-02T15:04:05Z07:00")
+	} else {
+	}
+	// check limit field -> json key limit
+	if l.limit != nil {
+		limit := *l.limit
+
+		// assign parameter of limit
+		params["limit"] = limit
+	} else {
+	}
+	// check cursor field -> json key cursor
+	if l.cursor != nil {
+		cursor := *l.cursor
+
+		// assign parameter of cursor
+		params["cursor"] = cursor
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (l *ListFillsRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (l *ListFillsRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := l.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if l.isVarSlice(_v) {
+			l.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (l *ListFillsRequest) GetParametersJSON() ([]byte, error) {
+	params, err := l.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (l *ListFillsRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (l *ListFillsRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (l *ListFillsRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (l *ListFillsRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (l *ListFillsRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := l.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (l *ListFillsRequest) GetPath() string {
+	return "/api/v3/brokerage/orders/historical/fills"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (l *ListFillsRequest) Do(ctx context.Context) (*FillsResponse, error) {
+
+	// no body params
+	var params interface{}
+	query, err := l.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = l.GetPath()
+
+	req, err := l.client.NewAuthenticatedRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := l.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse FillsResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &apiResponse, nil
+}
@@ -0,0 +1,36 @@
+package coinbaseapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+
+type OrdersResponse struct {
+	Orders  []Order `json:"orders"`
+	HasNext bool    `json:"has_next"`
+	Cursor  string  `json:"cursor"`
+}
+
+// ListOrdersRequest returns the orders in descending order by the created time.
+//
+//go:generate GetRequest -url "/api/v3/brokerage/orders/historical/batch" -type ListOrdersRequest -responseType .OrdersResponse
+type ListOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	productId   *string      `param:"product_ids,query"`
+	orderStatus *OrderStatus `param:"order_status,query"`
+	startDate   *time.Time   `param:"start_date,query" timeFormat:"RFC3339"`
+	endDate     *time.Time   `param:"end_date,query" timeFormat:"RFC3339"`
+	productType *ProductType `param:"product_type,query"`
+
+	limit  *uint64 `param:"limit,query"`
+	cursor *string `param:"cursor,query"`
+}
+
+func (c *RestClient) NewListOrdersRequest() *ListOrdersRequest {
+	return &ListOrdersRequest{client: c}
+}
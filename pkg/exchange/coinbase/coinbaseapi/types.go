@@ -0,0 +1,77 @@
+package coinbaseapi
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+type Side string
+
+const (
+	SideBuy  Side = "BUY"
+	SideSell Side = "SELL"
+)
+
+type ProductType string
+
+const (
+	ProductTypeSpot   ProductType = "SPOT"
+	ProductTypeFuture ProductType = "FUTURE"
+)
+
+type OrderType string
+
+const (
+	OrderTypeMarket    OrderType = "MARKET"
+	OrderTypeLimit     OrderType = "LIMIT"
+	OrderTypeStop      OrderType = "STOP"
+	OrderTypeStopLimit OrderType = "STOP_LIMIT"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending      OrderStatus = "PENDING"
+	OrderStatusQueued       OrderStatus = "QUEUED"
+	OrderStatusOpen         OrderStatus = "OPEN"
+	OrderStatusFilled       OrderStatus = "FILLED"
+	OrderStatusCancelled    OrderStatus = "CANCELLED"
+	OrderStatusCancelQueued OrderStatus = "CANCEL_QUEUED"
+	OrderStatusExpired      OrderStatus = "EXPIRED"
+	OrderStatusFailed       OrderStatus = "FAILED"
+	OrderStatusUnknown      OrderStatus = "UNKNOWN_ORDER_STATUS"
+)
+
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GOOD_UNTIL_CANCELLED"
+	TimeInForceGTD TimeInForce = "GOOD_UNTIL_DATE_TIME"
+	TimeInForceIOC TimeInForce = "IMMEDIATE_OR_CANCEL"
+	TimeInForceFOK TimeInForce = "FILL_OR_KILL"
+)
+
+type LiquidityIndicator string
+
+const (
+	LiquidityIndicatorMaker   LiquidityIndicator = "MAKER"
+	LiquidityIndicatorTaker   LiquidityIndicator = "TAKER"
+	LiquidityIndicatorUnknown LiquidityIndicator = "UNKNOWN_LIQUIDITY_INDICATOR"
+)
+
+type Granularity string
+
+const (
+	GranularityOneMinute     Granularity = "ONE_MINUTE"
+	GranularityFiveMinute    Granularity = "FIVE_MINUTE"
+	GranularityFifteenMinute Granularity = "FIFTEEN_MINUTE"
+	GranularityThirtyMinute  Granularity = "THIRTY_MINUTE"
+	GranularityOneHour       Granularity = "ONE_HOUR"
+	GranularityTwoHour       Granularity = "TWO_HOUR"
+	GranularitySixHour       Granularity = "SIX_HOUR"
+	GranularityOneDay        Granularity = "ONE_DAY"
+)
+
+type Money struct {
+	Value    fixedpoint.Value `json:"value"`
+	Currency string           `json:"currency"`
+}
@@ -0,0 +1,30 @@
+package coinbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/conformance"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestExchange_Conformance(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	recordings, err := httptesting.LoadRecordings("coinbaseapi/testdata/conformance.json")
+	assert.NoError(t, err)
+	transport.Replay(recordings)
+
+	conformance.RunTest(t, ex, conformance.Config{
+		Symbol:    "BTCUSD",
+		StartTime: time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		Stream: &conformance.StreamConfig{
+			Messages: []string{
+				`{"channel":"l2_data","client_id":"","timestamp":"2024-05-30T00:00:01.123Z","sequence_num":0,"events":[{"type":"snapshot","product_id":"BTC-USD","updates":[{"side":"bid","event_time":"1970-01-01T00:00:00Z","price_level":"67801.9","new_quantity":"0.5"},{"side":"offer","event_time":"1970-01-01T00:00:00Z","price_level":"67802.01","new_quantity":"0.12"}]}]}`,
+				`{"channel":"market_trades","client_id":"","timestamp":"2024-05-30T00:00:01.123Z","sequence_num":1,"events":[{"type":"update","trades":[{"trade_id":"643960311","product_id":"BTC-USD","price":"67801.9","size":"0.01","side":"SELL","time":"2024-05-30T00:00:01.1Z"}]}]}`,
+			},
+		},
+	})
+}
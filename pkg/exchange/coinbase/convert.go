@@ -0,0 +1,358 @@
+package coinbase
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/coinbase/coinbaseapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	// localSymbols caches the product ids of the markets queried from the server
+	localSymbols sync.Map
+
+	quoteCurrencies = []string{"USDC", "USDT", "USD", "EUR", "GBP", "BTC", "ETH"}
+)
+
+func toLocalSymbol(symbol string) string {
+	if s, ok := localSymbols.Load(symbol); ok {
+		return s.(string)
+	}
+
+	for _, quote := range quoteCurrencies {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return symbol[:len(symbol)-len(quote)] + "-" + quote
+		}
+	}
+
+	log.Warnf("failed to look up local symbol from %s", symbol)
+	return symbol
+}
+
+func toGlobalSymbol(productId string) string {
+	return strings.ReplaceAll(productId, "-", "")
+}
+
+// quoteCurrency returns the quote currency of the product id, e.g. USD of BTC-USD.
+func quoteCurrency(productId string) string {
+	if idx := strings.LastIndex(productId, "-"); idx >= 0 {
+		return productId[idx+1:]
+	}
+	return ""
+}
+
+func toGlobalMarket(p coinbaseapi.Product) types.Market {
+	return types.Market{
+		Exchange:        types.ExchangeCoinbase,
+		Symbol:          toGlobalSymbol(p.ProductId),
+		LocalSymbol:     p.ProductId,
+		PricePrecision:  p.PriceIncrement.NumFractionalDigits(),
+		VolumePrecision: p.BaseIncrement.NumFractionalDigits(),
+		QuoteCurrency:   p.QuoteCurrencyId,
+		BaseCurrency:    p.BaseCurrencyId,
+		MinNotional:     p.QuoteMinSize,
+		MinAmount:       p.QuoteMinSize,
+
+		// quantity
+		MinQuantity: p.BaseMinSize,
+		MaxQuantity: p.BaseMaxSize,
+		StepSize:    p.BaseIncrement,
+
+		// price
+		MinPrice: p.PriceIncrement,
+		TickSize: p.PriceIncrement,
+	}
+}
+
+// toGlobalTicker converts the product stats and the best bid/ask into the ticker. The 24h high and low prices are
+// not provided by the server.
+func toGlobalTicker(p coinbaseapi.Product, book *coinbaseapi.PriceBook, time time.Time) types.Ticker {
+	ticker := types.Ticker{
+		Time:   time,
+		Volume: p.Volume24h,
+		Last:   p.Price,
+		Open:   p.Price.Div(fixedpoint.One.Add(p.PricePercentageChange24h.Div(fixedpoint.NewFromInt(100)))),
+	}
+
+	if book != nil {
+		if len(book.Bids) > 0 {
+			ticker.Buy = book.Bids[0].Price
+		}
+		if len(book.Asks) > 0 {
+			ticker.Sell = book.Asks[0].Price
+		}
+	}
+
+	return ticker
+}
+
+var (
+	// supportedIntervals is the map of the supported intervals and the seconds of them.
+	supportedIntervals = map[types.Interval]int{
+		types.Interval1m:  60,
+		types.Interval5m:  60 * 5,
+		types.Interval15m: 60 * 15,
+		types.Interval30m: 60 * 30,
+		types.Interval1h:  60 * 60,
+		types.Interval2h:  60 * 60 * 2,
+		types.Interval6h:  60 * 60 * 6,
+		types.Interval1d:  60 * 60 * 24,
+	}
+
+	toLocalGranularity = map[types.Interval]coinbaseapi.Granularity{
+		types.Interval1m:  coinbaseapi.GranularityOneMinute,
+		types.Interval5m:  coinbaseapi.GranularityFiveMinute,
+		types.Interval15m: coinbaseapi.GranularityFifteenMinute,
+		types.Interval30m: coinbaseapi.GranularityThirtyMinute,
+		types.Interval1h:  coinbaseapi.GranularityOneHour,
+		types.Interval2h:  coinbaseapi.GranularityTwoHour,
+		types.Interval6h:  coinbaseapi.GranularitySixHour,
+		types.Interval1d:  coinbaseapi.GranularityOneDay,
+	}
+)
+
+func toGlobalKLine(symbol string, interval types.Interval, c coinbaseapi.Candle, now time.Time) types.KLine {
+	startTime := c.Start.Time()
+	endTime := startTime.Add(interval.Duration() - time.Millisecond)
+	return types.KLine{
+		Exchange:  types.ExchangeCoinbase,
+		Symbol:    symbol,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(endTime),
+		Interval:  interval,
+		Open:      c.Open,
+		Close:     c.Close,
+		High:      c.High,
+		Low:       c.Low,
+		Volume:    c.Volume,
+		Closed:    endTime.Before(now),
+	}
+}
+
+func toGlobalBalanceMap(accounts []coinbaseapi.Account) types.BalanceMap {
+	balanceMap := types.BalanceMap{}
+	for _, a := range accounts {
+		// there might be multiple accounts (e.g. vaults) of a currency
+		balance := balanceMap[a.Currency]
+		balance.Currency = a.Currency
+		balance.Available = balance.Available.Add(a.AvailableBalance.Value)
+		balance.Locked = balance.Locked.Add(a.Hold.Value)
+		balanceMap[a.Currency] = balance
+	}
+	return balanceMap
+}
+
+func toGlobalSideType(side coinbaseapi.Side) (types.SideType, error) {
+	switch side {
+	case coinbaseapi.SideBuy:
+		return types.SideTypeBuy, nil
+
+	case coinbaseapi.SideSell:
+		return types.SideTypeSell, nil
+
+	default:
+		return types.SideType(side), fmt.Errorf("unexpected side: %s", side)
+	}
+}
+
+func toLocalSide(side types.SideType) (coinbaseapi.Side, error) {
+	switch side {
+	case types.SideTypeBuy:
+		return coinbaseapi.SideBuy, nil
+
+	case types.SideTypeSell:
+		return coinbaseapi.SideSell, nil
+
+	default:
+		return "", fmt.Errorf("side type %s not supported", side)
+	}
+}
+
+func toGlobalOrderType(orderType coinbaseapi.OrderType, postOnly bool) (types.OrderType, error) {
+	switch orderType {
+	case coinbaseapi.OrderTypeMarket:
+		return types.OrderTypeMarket, nil
+
+	case coinbaseapi.OrderTypeLimit:
+		if postOnly {
+			return types.OrderTypeLimitMaker, nil
+		}
+		return types.OrderTypeLimit, nil
+
+	case coinbaseapi.OrderTypeStopLimit:
+		return types.OrderTypeStopLimit, nil
+
+	default:
+		return types.OrderType(orderType), fmt.Errorf("unexpected order type: %s", orderType)
+	}
+}
+
+func toGlobalTimeInForce(tif coinbaseapi.TimeInForce) (types.TimeInForce, error) {
+	switch tif {
+	case coinbaseapi.TimeInForceGTC, coinbaseapi.TimeInForceGTD:
+		return types.TimeInForceGTC, nil
+
+	case coinbaseapi.TimeInForceIOC:
+		return types.TimeInForceIOC, nil
+
+	case coinbaseapi.TimeInForceFOK:
+		return types.TimeInForceFOK, nil
+
+	default:
+		return types.TimeInForce(tif), fmt.Errorf("unexpected time in force: %s", tif)
+	}
+}
+
+func toGlobalOrderStatus(status coinbaseapi.OrderStatus, filledSize fixedpoint.Value) (types.OrderStatus, error) {
+	switch status {
+	// the order is still working until the cancellation is processed
+	case coinbaseapi.OrderStatusPending, coinbaseapi.OrderStatusQueued, coinbaseapi.OrderStatusOpen, coinbaseapi.OrderStatusCancelQueued:
+		if filledSize.IsZero() {
+			return types.OrderStatusNew, nil
+		}
+		return types.OrderStatusPartiallyFilled, nil
+
+	case coinbaseapi.OrderStatusFilled:
+		return types.OrderStatusFilled, nil
+
+	case coinbaseapi.OrderStatusCancelled, coinbaseapi.OrderStatusExpired:
+		return types.OrderStatusCanceled, nil
+
+	case coinbaseapi.OrderStatusFailed:
+		return types.OrderStatusRejected, nil
+
+	default:
+		return types.OrderStatus(status), fmt.Errorf("unexpected order status: %s", status)
+	}
+}
+
+// orderSizeAndPrice extracts the base size and the limit price from the order configuration. The base size is zero if
+// the order is a market buy order with the quote size.
+func orderSizeAndPrice(config coinbaseapi.OrderConfiguration) (size, price fixedpoint.Value, postOnly bool, err error) {
+	var sizeStr, priceStr string
+	switch {
+	case config.MarketMarketIoc != nil:
+		sizeStr = config.MarketMarketIoc.BaseSize
+
+	case config.LimitLimitGtc != nil:
+		sizeStr, priceStr, postOnly = config.LimitLimitGtc.BaseSize, config.LimitLimitGtc.LimitPrice, config.LimitLimitGtc.PostOnly
+
+	case config.LimitLimitFok != nil:
+		sizeStr, priceStr = config.LimitLimitFok.BaseSize, config.LimitLimitFok.LimitPrice
+
+	case config.SorLimitIoc != nil:
+		sizeStr, priceStr = config.SorLimitIoc.BaseSize, config.SorLimitIoc.LimitPrice
+	}
+
+	if len(sizeStr) > 0 {
+		if size, err = fixedpoint.NewFromString(sizeStr); err != nil {
+			return size, price, postOnly, fmt.Errorf("unexpected base size: %s, err: %w", sizeStr, err)
+		}
+	}
+
+	if len(priceStr) > 0 {
+		if price, err = fixedpoint.NewFromString(priceStr); err != nil {
+			return size, price, postOnly, fmt.Errorf("unexpected limit price: %s, err: %w", priceStr, err)
+		}
+	}
+
+	return size, price, postOnly, nil
+}
+
+func toGlobalOrder(order coinbaseapi.Order) (*types.Order, error) {
+	side, err := toGlobalSideType(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, price, postOnly, err := orderSizeAndPrice(order.OrderConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType, err := toGlobalOrderType(order.OrderType, postOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	timeInForce, err := toGlobalTimeInForce(order.TimeInForce)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := toGlobalOrderStatus(order.Status, order.FilledSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if orderType == types.OrderTypeMarket {
+		price = order.AverageFilledPrice
+		// the market buy orders are submitted with the quote size
+		if quantity.IsZero() {
+			quantity = order.FilledSize
+		}
+	}
+
+	updateTime := order.CreatedTime
+	if order.LastFillTime != nil {
+		updateTime = *order.LastFillTime
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: order.ClientOrderId,
+			Symbol:        toGlobalSymbol(order.ProductId),
+			Side:          side,
+			Type:          orderType,
+			Quantity:      quantity,
+			Price:         price,
+			TimeInForce:   timeInForce,
+		},
+		Exchange:         types.ExchangeCoinbase,
+		OrderID:          timeOrderedID(order.CreatedTime, order.OrderId),
+		UUID:             order.OrderId,
+		Status:           status,
+		ExecutedQuantity: order.FilledSize,
+		IsWorking:        status == types.OrderStatusNew || status == types.OrderStatusPartiallyFilled,
+		CreationTime:     types.Time(order.CreatedTime),
+		UpdateTime:       types.Time(updateTime),
+	}, nil
+}
+
+// toGlobalTrade converts the fill into the trade, the fill doesn't carry the creation time of its order, so the order
+// id is resolved by the caller.
+func toGlobalTrade(fill coinbaseapi.Fill, orderID uint64) (*types.Trade, error) {
+	side, err := toGlobalSideType(fill.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := fill.Size
+	if fill.SizeInQuote {
+		if fill.Price.IsZero() {
+			return nil, fmt.Errorf("unexpected zero price of trade: %s", fill.TradeId)
+		}
+		quantity = fill.Size.Div(fill.Price)
+	}
+
+	return &types.Trade{
+		ID:            timeOrderedID(fill.TradeTime, fill.TradeId),
+		OrderID:       orderID,
+		Exchange:      types.ExchangeCoinbase,
+		Price:         fill.Price,
+		Quantity:      quantity,
+		QuoteQuantity: fill.Price.Mul(quantity),
+		Symbol:        toGlobalSymbol(fill.ProductId),
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		IsMaker:       fill.LiquidityIndicator == coinbaseapi.LiquidityIndicatorMaker,
+		Time:          types.Time(fill.TradeTime),
+		// the commission is charged in the quote currency
+		Fee:         fill.Commission,
+		FeeCurrency: quoteCurrency(fill.ProductId),
+	}, nil
+}
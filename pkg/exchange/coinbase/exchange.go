@@ -0,0 +1,688 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/coinbase/coinbaseapi"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	ID = "coinbase"
+
+	// maxKLineLimit is the max number of the candles in a request
+	maxKLineLimit = 350
+	// maxProductQueryLimit is the page size of the products
+	maxProductQueryLimit = 250
+	// maxAccountQueryLimit is the page size of the accounts
+	maxAccountQueryLimit = 250
+	// maxCancelOrderLimit is the max number of the orders in a batch cancellation
+	maxCancelOrderLimit = 100
+	maxOrderQueryLimit  = 1000
+	maxTradeQueryLimit  = 1000
+
+	// tradeQueryWindow is the initial time window of the fills query, the fills are queried window by window in
+	// ascending order, and the window grows up to maxTradeQueryWindow while there are no fills
+	tradeQueryWindow    = 24 * time.Hour
+	maxTradeQueryWindow = 30 * 24 * time.Hour
+)
+
+// https://docs.cdp.coinbase.com/advanced-trade/docs/rest-api-rate-limits
+var (
+	// publicRateLimiter is used by the public endpoints, 10r/s per IP
+	publicRateLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 5)
+	// privateRateLimiter is used by the private endpoints, 30r/s per user
+	privateRateLimiter = rate.NewLimiter(rate.Every(40*time.Millisecond), 10)
+
+	log = logrus.WithFields(logrus.Fields{
+		"exchange": ID,
+	})
+
+	_ types.ExchangeMinimal             = &Exchange{}
+	_ types.Exchange                    = &Exchange{}
+	_ types.ExchangeAccountService      = &Exchange{}
+	_ types.ExchangeMarketDataService   = &Exchange{}
+	_ types.ExchangeTradeService        = &Exchange{}
+	_ types.ExchangeOrderQueryService   = &Exchange{}
+	_ types.ExchangeTradeHistoryService = &Exchange{}
+	_ types.CustomIntervalProvider      = &Exchange{}
+)
+
+type Exchange struct {
+	client *coinbaseapi.RestClient
+
+	// orderIDs resolves the UUIDs of the order ids, it's shared with the stream
+	orderIDs *orderIDMap
+}
+
+// New creates the Coinbase Advanced Trade exchange, the key is the CDP API key name and the secret is the private key
+// of the API key.
+func New(key, secret string) (*Exchange, error) {
+	client := coinbaseapi.NewClient()
+	if len(key) > 0 && len(secret) > 0 {
+		if err := client.Auth(key, secret); err != nil {
+			return nil, fmt.Errorf("failed to set up the api key: %w", err)
+		}
+	}
+
+	return &Exchange{
+		client:   client,
+		orderIDs: newOrderIDMap(),
+	}, nil
+}
+
+func (e *Exchange) Name() types.ExchangeName {
+	return types.ExchangeCoinbase
+}
+
+func (e *Exchange) PlatformFeeCurrency() string {
+	return ""
+}
+
+func (e *Exchange) NewStream() types.Stream {
+	stream := NewStream(e.client.Signer(), e)
+	stream.orderIDs = e.orderIDs
+	return stream
+}
+
+func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	markets := types.MarketMap{}
+	for offset := uint64(0); ; offset += maxProductQueryLimit {
+		if err := publicRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+		}
+
+		res, err := e.client.NewGetMarketProductsRequest().
+			Limit(maxProductQueryLimit).
+			Offset(offset).
+			Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query products: %w", err)
+		}
+
+		for _, p := range res.Products {
+			if p.IsDisabled || p.TradingDisabled || p.ProductType != coinbaseapi.ProductTypeSpot {
+				continue
+			}
+
+			market := toGlobalMarket(p)
+			localSymbols.Store(market.Symbol, market.LocalSymbol)
+			markets.Add(market)
+		}
+
+		if len(res.Products) < maxProductQueryLimit {
+			break
+		}
+	}
+
+	return markets, nil
+}
+
+// QueryTicker queries the product stats and the best bid/ask of the symbol.
+func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	productId := toLocalSymbol(symbol)
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
+	}
+
+	product, err := e.client.NewGetMarketProductRequest().ProductId(productId).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product, symbol: %s, err: %w", symbol, err)
+	}
+
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
+	}
+
+	book, err := e.client.NewGetMarketProductBookRequest().ProductId(productId).Limit(1).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product book, symbol: %s, err: %w", symbol, err)
+	}
+
+	ticker := toGlobalTicker(*product, &book.PriceBook, time.Now())
+	return &ticker, nil
+}
+
+func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
+	if len(symbols) == 0 {
+		return nil, errors.New("symbols are required, the tickers of all the products are not supported")
+	}
+
+	tickers := make(map[string]types.Ticker, len(symbols))
+	for _, s := range symbols {
+		ticker, err := e.QueryTicker(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+
+		tickers[s] = *ticker
+	}
+
+	return tickers, nil
+}
+
+/*
+QueryKLines queries the k lines, the server requires both the start time and the end time, so the time range is
+calculated from the limit once one of them is not specified.
+*/
+func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	granularity, ok := toLocalGranularity[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	limit := options.Limit
+	if limit <= 0 || limit > maxKLineLimit {
+		limit = maxKLineLimit
+	}
+
+	window := time.Duration(limit-1) * interval.Duration()
+	now := time.Now()
+	var start, end time.Time
+	switch {
+	case options.StartTime != nil:
+		start = *options.StartTime
+		end = start.Add(window)
+		if options.EndTime != nil && options.EndTime.Before(end) {
+			end = *options.EndTime
+		}
+
+	case options.EndTime != nil:
+		end = *options.EndTime
+		start = end.Add(-window)
+
+	default:
+		end = now
+		start = end.Add(-window)
+	}
+
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("klines rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetMarketCandlesRequest().
+		ProductId(toLocalSymbol(symbol)).
+		Granularity(granularity).
+		Start(start).
+		End(end).
+		Limit(uint64(limit)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines, symbol: %s, err: %w", symbol, err)
+	}
+
+	kLines := make([]types.KLine, 0, len(res.Candles))
+	// the candles are in descending order
+	for i := len(res.Candles) - 1; i >= 0; i-- {
+		kLines = append(kLines, toGlobalKLine(symbol, interval, res.Candles[i], now))
+	}
+
+	return kLines, nil
+}
+
+func (e *Exchange) SupportedInterval() map[types.Interval]int {
+	return supportedIntervals
+}
+
+func (e *Exchange) IsSupportedInterval(interval types.Interval) bool {
+	_, ok := supportedIntervals[interval]
+	return ok
+}
+
+func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	balances, err := e.QueryAccountBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	account := types.NewAccount()
+	account.AccountType = types.AccountTypeSpot
+	account.UpdateBalances(balances)
+	return account, nil
+}
+
+func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap, error) {
+	var accounts []coinbaseapi.Account
+	cursor := ""
+	for {
+		if err := privateRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("account rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetAccountsRequest().Limit(maxAccountQueryLimit)
+		if len(cursor) > 0 {
+			req.Cursor(cursor)
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query accounts: %w", err)
+		}
+
+		accounts = append(accounts, res.Accounts...)
+		if !res.HasNext || len(res.Cursor) == 0 {
+			break
+		}
+		cursor = res.Cursor
+	}
+
+	return toGlobalBalanceMap(accounts), nil
+}
+
+func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (createdOrder *types.Order, err error) {
+	side, err := toLocalSide(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	var config coinbaseapi.OrderConfiguration
+	switch order.Type {
+	case types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		size, price := order.Market.FormatQuantity(order.Quantity), order.Market.FormatPrice(order.Price)
+		switch {
+		case order.Type == types.OrderTypeLimitMaker:
+			config.LimitLimitGtc = &coinbaseapi.LimitGtc{BaseSize: size, LimitPrice: price, PostOnly: true}
+
+		case order.TimeInForce == types.TimeInForceIOC:
+			config.SorLimitIoc = &coinbaseapi.LimitIoc{BaseSize: size, LimitPrice: price}
+
+		case order.TimeInForce == types.TimeInForceFOK:
+			config.LimitLimitFok = &coinbaseapi.LimitIoc{BaseSize: size, LimitPrice: price}
+
+		case order.TimeInForce == "" || order.TimeInForce == types.TimeInForceGTC:
+			config.LimitLimitGtc = &coinbaseapi.LimitGtc{BaseSize: size, LimitPrice: price}
+
+		default:
+			return nil, fmt.Errorf("time in force %s not supported", order.TimeInForce)
+		}
+
+	case types.OrderTypeMarket:
+		// the market buy orders are submitted with the quote size
+		if order.Side == types.SideTypeBuy {
+			price := order.Price
+			if price.IsZero() {
+				ticker, err := e.QueryTicker(ctx, order.Symbol)
+				if err != nil {
+					return nil, err
+				}
+				price = ticker.Sell
+			}
+			config.MarketMarketIoc = &coinbaseapi.MarketIoc{QuoteSize: order.Market.FormatPrice(order.Quantity.Mul(price))}
+		} else {
+			config.MarketMarketIoc = &coinbaseapi.MarketIoc{BaseSize: order.Market.FormatQuantity(order.Quantity)}
+		}
+
+	default:
+		return nil, fmt.Errorf("order type %s not supported", order.Type)
+	}
+
+	// the client order id is required by the server
+	clientOrderId := order.ClientOrderID
+	if len(clientOrderId) == 0 {
+		clientOrderId = uuid.New().String()
+	}
+
+	if err := privateRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("place order rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewCreateOrderRequest().
+		ClientOrderId(clientOrderId).
+		ProductId(toLocalSymbol(order.Symbol)).
+		Side(side).
+		OrderConfiguration(config).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order, order: %#v, err: %w", order, err)
+	}
+
+	if !res.Success {
+		if res.ErrorResponse != nil {
+			return nil, fmt.Errorf("failed to place order, reason: %s, error: %s, message: %s",
+				res.FailureReason, res.ErrorResponse.Error, res.ErrorResponse.Message)
+		}
+		return nil, fmt.Errorf("failed to place order, reason: %s", res.FailureReason)
+	}
+
+	orderId := res.OrderId
+	if res.SuccessResponse != nil && len(res.SuccessResponse.OrderId) > 0 {
+		orderId = res.SuccessResponse.OrderId
+	}
+
+	return e.QueryOrder(ctx, types.OrderQuery{Symbol: order.Symbol, OrderID: orderId})
+}
+
+func (e *Exchange) queryOrders(ctx context.Context, req *coinbaseapi.ListOrdersRequest) (orders []coinbaseapi.Order, err error) {
+	cursor := ""
+	for {
+		if err := privateRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("orders rate limiter wait error: %w", err)
+		}
+
+		if len(cursor) > 0 {
+			req.Cursor(cursor)
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, res.Orders...)
+		if !res.HasNext || len(res.Cursor) == 0 {
+			break
+		}
+		cursor = res.Cursor
+	}
+
+	return orders, nil
+}
+
+func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	res, err := e.queryOrders(ctx, e.client.NewListOrdersRequest().
+		ProductId(toLocalSymbol(symbol)).
+		OrderStatus(coinbaseapi.OrderStatusOpen).
+		Limit(maxOrderQueryLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open orders, symbol: %s, err: %w", symbol, err)
+	}
+
+	for _, o := range res {
+		order, err := e.toGlobalOrder(o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert order, err: %w", err)
+		}
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
+
+// toGlobalOrder converts the order and registers its order id, so that the order can be queried by the order id.
+func (e *Exchange) toGlobalOrder(o coinbaseapi.Order) (*types.Order, error) {
+	order, err := toGlobalOrder(o)
+	if err != nil {
+		return nil, err
+	}
+
+	e.orderIDs.Add(order.OrderID, order.UUID)
+	return order, nil
+}
+
+// orderUUID returns the order UUID of the order query, the order id is either the UUID or the decimal order id.
+func (e *Exchange) orderUUID(q types.OrderQuery) (string, error) {
+	if len(q.OrderID) == 0 {
+		return "", errors.New("order id is required")
+	}
+
+	if strings.Contains(q.OrderID, "-") {
+		return q.OrderID, nil
+	}
+
+	orderID, err := strconv.ParseUint(q.OrderID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("unexpected order id %s, err: %w", q.OrderID, err)
+	}
+
+	uuid, ok := e.orderIDs.UUID(orderID)
+	if !ok {
+		return "", fmt.Errorf("the uuid of order %s is unknown, query the order by the uuid instead", q.OrderID)
+	}
+
+	return uuid, nil
+}
+
+// CancelOrders cancels the orders by the order uuid in batches, the orders without the uuid are skipped with errors.
+func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (errs error) {
+	var orderIds []string
+	for _, order := range orders {
+		if len(order.UUID) == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("the order uuid is required, order: %s", order.String()))
+			continue
+		}
+		orderIds = append(orderIds, order.UUID)
+	}
+
+	for len(orderIds) > 0 {
+		batch := orderIds
+		if len(batch) > maxCancelOrderLimit {
+			batch = batch[:maxCancelOrderLimit]
+		}
+		orderIds = orderIds[len(batch):]
+
+		if err := privateRateLimiter.Wait(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("cancel order rate limiter wait error: %w", err))
+			continue
+		}
+
+		res, err := e.client.NewCancelOrdersRequest().OrderIds(batch).Do(ctx)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to cancel orders, order ids: %v, err: %w", batch, err))
+			continue
+		}
+
+		for _, r := range res.Results {
+			if !r.Success {
+				errs = multierr.Append(errs, fmt.Errorf("failed to cancel order, order id: %s, reason: %s", r.OrderId, r.FailureReason))
+			}
+		}
+	}
+
+	return errs
+}
+
+// QueryOrder queries the order by the order uuid or the decimal order id in the OrderID field, the server doesn't
+// support querying the order by the client order id.
+func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	orderId, err := e.orderUUID(q)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := privateRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query order rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetOrderRequest().OrderId(orderId).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order, query: %+v, err: %w", q, err)
+	}
+
+	return e.toGlobalOrder(res.Order)
+}
+
+func (e *Exchange) queryFills(ctx context.Context, req *coinbaseapi.ListFillsRequest, limit int) (fills []coinbaseapi.Fill, err error) {
+	cursor := ""
+	for {
+		if err := privateRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("fills rate limiter wait error: %w", err)
+		}
+
+		if len(cursor) > 0 {
+			req.Cursor(cursor)
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		fills = append(fills, res.Fills...)
+		if len(res.Cursor) == 0 || len(res.Fills) == 0 || (limit > 0 && len(fills) >= limit) {
+			break
+		}
+		cursor = res.Cursor
+	}
+
+	if limit > 0 && len(fills) > limit {
+		fills = fills[:limit]
+	}
+	return fills, nil
+}
+
+// QueryOrderTrades queries the trades of the order by the order uuid or the decimal order id in the OrderID field.
+func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) ([]types.Trade, error) {
+	orderId, err := e.orderUUID(q)
+	if err != nil {
+		return nil, err
+	}
+
+	fills, err := e.queryFills(ctx, e.client.NewListFillsRequest().OrderId(orderId).Limit(maxTradeQueryLimit), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order trades, query: %+v, err: %w", q, err)
+	}
+
+	return e.toGlobalTrades(ctx, fills)
+}
+
+// toGlobalTrades converts the fills into the trades in ascending order, the orders of the fills are queried if their
+// order ids are unknown.
+func (e *Exchange) toGlobalTrades(ctx context.Context, fills []coinbaseapi.Fill) ([]types.Trade, error) {
+	var errs error
+	res := make([]types.Trade, 0, len(fills))
+	for _, f := range fills {
+		orderID, ok := e.orderIDs.OrderID(f.OrderId)
+		if !ok {
+			order, err := e.QueryOrder(ctx, types.OrderQuery{OrderID: f.OrderId})
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to query the order of trade %s, err: %w", f.TradeId, err))
+				continue
+			}
+			orderID = order.OrderID
+		}
+
+		trade, err := toGlobalTrade(f, orderID)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		res = append(res, *trade)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time.Time())
+	})
+	return res, errs
+}
+
+/*
+QueryClosedOrders queries the finished orders in the time range.
+
+** The order ids increase with the creation time, the orders after the lastOrderID are queried from its creation time. **
+*/
+func (e *Exchange) QueryClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) (orders []types.Order, err error) {
+	if lastOrderID > 0 {
+		if t := timeOfID(lastOrderID); t.After(since) {
+			since = t
+		}
+	}
+
+	res, err := e.queryOrders(ctx, e.client.NewListOrdersRequest().
+		ProductId(toLocalSymbol(symbol)).
+		StartDate(since).
+		EndDate(until).
+		Limit(maxOrderQueryLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed orders, symbol: %s, err: %w", symbol, err)
+	}
+
+	for _, o := range res {
+		order, err := e.toGlobalOrder(o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert order, err: %w", err)
+		}
+
+		if order.IsWorking || order.OrderID <= lastOrderID {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	return orders, nil
+}
+
+/*
+QueryTrades queries the trades in the time range in ascending order.
+
+The server returns the fills in descending order, so the fills are queried window by window from the start time, and
+the earliest trades up to the limit are returned.
+
+** The trade ids increase with the trade time, the trades after the LastTradeID are queried from its trade time. **
+*/
+func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) ([]types.Trade, error) {
+	limit := int(options.Limit)
+	if limit <= 0 || limit > maxTradeQueryLimit {
+		limit = maxTradeQueryLimit
+	}
+
+	endTime := time.Now()
+	if options.EndTime != nil {
+		endTime = *options.EndTime
+	}
+
+	startTime := endTime.Add(-tradeQueryWindow)
+	if options.StartTime != nil {
+		startTime = *options.StartTime
+	}
+
+	if options.LastTradeID > 0 {
+		if t := timeOfID(options.LastTradeID); t.After(startTime) {
+			startTime = t
+		}
+	}
+
+	// the window is doubled when there is no fill in it, so that the sparse trades are queried with fewer requests
+	var trades []types.Trade
+	window := tradeQueryWindow
+	for windowStart := startTime; windowStart.Before(endTime) && len(trades) < limit; windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		if windowEnd.After(endTime) {
+			windowEnd = endTime
+		}
+
+		fills, err := e.queryFills(ctx, e.client.NewListFillsRequest().
+			ProductId(toLocalSymbol(symbol)).
+			StartSequenceTimestamp(windowStart).
+			EndSequenceTimestamp(windowEnd).
+			Limit(maxTradeQueryLimit), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query trades, symbol: %s, err: %w", symbol, err)
+		}
+
+		if len(fills) == 0 && window < maxTradeQueryWindow {
+			window *= 2
+			continue
+		}
+
+		windowTrades, err := e.toGlobalTrades(ctx, fills)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range windowTrades {
+			if t.ID > options.LastTradeID {
+				trades = append(trades, t)
+			}
+		}
+	}
+
+	if len(trades) > limit {
+		trades = trades[:limit]
+	}
+
+	return trades, nil
+}
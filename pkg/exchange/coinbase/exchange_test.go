@@ -0,0 +1,417 @@
+package coinbase
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	productsUrl     = "/api/v3/brokerage/market/products"
+	productUrl      = "/api/v3/brokerage/market/products/BTC-USD"
+	productBookUrl  = "/api/v3/brokerage/market/product_book"
+	candlesUrl      = "/api/v3/brokerage/market/products/BTC-USD/candles"
+	accountsUrl     = "/api/v3/brokerage/accounts"
+	createOrderUrl  = "/api/v3/brokerage/orders"
+	cancelOrdersUrl = "/api/v3/brokerage/orders/batch_cancel"
+	orderUrl        = "/api/v3/brokerage/orders/historical/11111-00000-000000"
+	listOrdersUrl   = "/api/v3/brokerage/orders/historical/batch"
+	fillsUrl        = "/api/v3/brokerage/orders/historical/fills"
+
+	testOrderId = "11111-00000-000000"
+)
+
+func newTestExchange(t *testing.T) (*Exchange, *httptesting.MockTransport) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	ex, err := New("organizations/org-id/apiKeys/key-id", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})))
+	assert.NoError(t, err)
+
+	transport := &httptesting.MockTransport{}
+	ex.client.HttpClient.Transport = transport
+	return ex, transport
+}
+
+func readFixture(t *testing.T, name string) string {
+	content, err := os.ReadFile("coinbaseapi/testdata/" + name)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func serveFixture(t *testing.T, name string) httptesting.RoundTripFunc {
+	file := readFixture(t, name)
+	return func(req *http.Request) (*http.Response, error) {
+		assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "Bearer "))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("key", "invalid secret")
+	assert.Error(t, err)
+
+	// the public only exchange
+	ex, err := New("", "")
+	assert.NoError(t, err)
+	assert.Nil(t, ex.client.Signer())
+}
+
+func TestExchange_QueryMarkets(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_market_products_request.json")
+	transport.GET(productsUrl, func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "SPOT", req.URL.Query().Get("product_type"))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	// the trading disabled products are skipped
+	assert.Len(t, markets, 1)
+	assert.Equal(t, types.Market{
+		Exchange:        types.ExchangeCoinbase,
+		Symbol:          "BTCUSD",
+		LocalSymbol:     "BTC-USD",
+		PricePrecision:  2,
+		VolumePrecision: 8,
+		QuoteCurrency:   "USD",
+		BaseCurrency:    "BTC",
+		MinNotional:     fixedpoint.One,
+		MinAmount:       fixedpoint.One,
+		MinQuantity:     fixedpoint.MustNewFromString("0.00000001"),
+		MaxQuantity:     fixedpoint.NewFromInt(3400),
+		StepSize:        fixedpoint.MustNewFromString("0.00000001"),
+		MinPrice:        fixedpoint.MustNewFromString("0.01"),
+		TickSize:        fixedpoint.MustNewFromString("0.01"),
+	}, markets["BTCUSD"])
+	assert.Equal(t, "BTC-USD", toLocalSymbol("BTCUSD"))
+}
+
+func TestExchange_QueryTicker(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_market_product_request.json")
+	transport.GET(productUrl, func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+	transport.GET(productBookUrl, func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "BTC-USD", req.URL.Query().Get("product_id"))
+		assert.Equal(t, "1", req.URL.Query().Get("limit"))
+		return httptesting.BuildResponseString(http.StatusOK, readFixture(t, "get_market_product_book_request.json")), nil
+	})
+
+	ticker, err := ex.QueryTicker(context.Background(), "BTCUSD")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.MustNewFromString("67801.91"), ticker.Last)
+	assert.Equal(t, fixedpoint.MustNewFromString("67801.9"), ticker.Buy)
+	assert.Equal(t, fixedpoint.MustNewFromString("67802.01"), ticker.Sell)
+	assert.Equal(t, fixedpoint.MustNewFromString("12345.67890123"), ticker.Volume)
+	assert.InDelta(t, 66799.91, ticker.Open.Float64(), 0.01)
+
+	_, err = ex.QueryTickers(context.Background())
+	assert.Error(t, err)
+}
+
+func TestExchange_QueryKLines(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_market_candles_request.json")
+	startTime := time.Unix(1717027200, 0)
+	transport.GET(candlesUrl, func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "ONE_MINUTE", query.Get("granularity"))
+		assert.Equal(t, "1717027200", query.Get("start"))
+		assert.Equal(t, "1717027260", query.Get("end"))
+		assert.Equal(t, "2", query.Get("limit"))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	kLines, err := ex.QueryKLines(context.Background(), "BTCUSD", types.Interval1m, types.KLineQueryOptions{
+		StartTime: &startTime,
+		Limit:     2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, kLines, 2)
+
+	// the descending candles are reversed
+	assert.Equal(t, types.KLine{
+		Exchange:  types.ExchangeCoinbase,
+		Symbol:    "BTCUSD",
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+		Interval:  types.Interval1m,
+		Open:      fixedpoint.MustNewFromString("67800.1"),
+		Close:     fixedpoint.MustNewFromString("67801.9"),
+		High:      fixedpoint.MustNewFromString("67860.1"),
+		Low:       fixedpoint.MustNewFromString("67748.3"),
+		Volume:    fixedpoint.MustNewFromString("18.550531"),
+		Closed:    true,
+	}, kLines[0])
+	assert.Equal(t, types.Time(startTime.Add(time.Minute)), kLines[1].StartTime)
+
+	_, err = ex.QueryKLines(context.Background(), "BTCUSD", types.Interval4h, types.KLineQueryOptions{})
+	assert.ErrorContains(t, err, "unsupported interval")
+}
+
+func TestExchange_QueryAccountBalances(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	transport.GET(accountsUrl, serveFixture(t, "get_accounts_request.json"))
+
+	balances, err := ex.QueryAccountBalances(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, types.BalanceMap{
+		"BTC": {
+			Currency:  "BTC",
+			Available: fixedpoint.MustNewFromString("1.23"),
+			Locked:    fixedpoint.MustNewFromString("0.1"),
+		},
+		"USD": {
+			Currency:  "USD",
+			Available: fixedpoint.MustNewFromString("1000.5"),
+			Locked:    fixedpoint.Zero,
+		},
+	}, balances)
+}
+
+func TestExchange_SubmitOrder(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	createFile := readFixture(t, "create_order_request.json")
+	transport.POST(createOrderUrl, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+
+		var payload map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "BTC-USD", payload["product_id"])
+		assert.Equal(t, "BUY", payload["side"])
+		assert.Equal(t, "0000-00000-000000", payload["client_order_id"])
+		assert.Equal(t, map[string]interface{}{
+			"limit_limit_gtc": map[string]interface{}{
+				"base_size":   "0.00100000",
+				"limit_price": "60000.00",
+				"post_only":   false,
+			},
+		}, payload["order_configuration"])
+		return httptesting.BuildResponseString(http.StatusOK, createFile), nil
+	})
+	transport.GET(orderUrl, serveFixture(t, "get_order_request.json"))
+
+	market := types.Market{
+		Symbol:          "BTCUSD",
+		PricePrecision:  2,
+		VolumePrecision: 8,
+		StepSize:        fixedpoint.MustNewFromString("0.00000001"),
+		TickSize:        fixedpoint.MustNewFromString("0.01"),
+	}
+	order, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+		ClientOrderID: "0000-00000-000000",
+		Symbol:        "BTCUSD",
+		Side:          types.SideTypeBuy,
+		Type:          types.OrderTypeLimit,
+		Quantity:      fixedpoint.MustNewFromString("0.001"),
+		Price:         fixedpoint.NewFromInt(60000),
+		Market:        market,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: "0000-00000-000000",
+			Symbol:        "BTCUSD",
+			Side:          types.SideTypeBuy,
+			Type:          types.OrderTypeLimit,
+			Quantity:      fixedpoint.MustNewFromString("0.001"),
+			Price:         fixedpoint.NewFromInt(60000),
+			TimeInForce:   types.TimeInForceGTC,
+		},
+		Exchange:         types.ExchangeCoinbase,
+		OrderID:          timeOrderedID(time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), testOrderId),
+		UUID:             testOrderId,
+		Status:           types.OrderStatusPartiallyFilled,
+		ExecutedQuantity: fixedpoint.MustNewFromString("0.0005"),
+		IsWorking:        true,
+		CreationTime:     types.Time(time.Date(2024, 5, 30, 0, 0, 0, 123000000, time.UTC)),
+		UpdateTime:       types.Time(time.Date(2024, 5, 30, 0, 0, 1, 456000000, time.UTC)),
+	}, order)
+
+	_, err = ex.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSD",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeStopMarket,
+		Quantity: fixedpoint.One,
+	})
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestExchange_SubmitOrder_Failed(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	transport.POST(createOrderUrl, func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `{
+		  "success": false,
+		  "failure_reason": "UNKNOWN_FAILURE_REASON",
+		  "order_id": "",
+		  "error_response": {
+		    "error": "INSUFFICIENT_FUND",
+		    "message": "Insufficient balance in source account"
+		  }
+		}`), nil
+	})
+
+	_, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSD",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.One,
+	})
+	assert.ErrorContains(t, err, "INSUFFICIENT_FUND")
+}
+
+func TestExchange_QueryClosedOrders(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	since := time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC)
+	transport.GET(listOrdersUrl, func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "BTC-USD", query.Get("product_ids"))
+		assert.Equal(t, "2024-05-30T00:00:00Z", query.Get("start_date"))
+		return httptesting.BuildResponseString(http.StatusOK, readFixture(t, "list_orders_request.json")), nil
+	})
+
+	orders, err := ex.QueryClosedOrders(context.Background(), "BTCUSD", since, since.Add(time.Hour), 0)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+
+	// sorted by the creation time
+	assert.Equal(t, types.OrderTypeLimitMaker, orders[0].Type)
+	assert.Equal(t, types.OrderStatusCanceled, orders[0].Status)
+
+	// the quantity of the market buy order is the filled size
+	assert.Equal(t, types.OrderTypeMarket, orders[1].Type)
+	assert.Equal(t, types.OrderStatusFilled, orders[1].Status)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.00147"), orders[1].Quantity)
+	assert.Equal(t, fixedpoint.MustNewFromString("67801.9"), orders[1].Price)
+	assert.Equal(t, types.TimeInForceIOC, orders[1].TimeInForce)
+}
+
+func TestExchange_QueryTrades(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	startTime := time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC)
+	transport.GET(fillsUrl, func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "BTC-USD", query.Get("product_id"))
+		assert.Equal(t, "2024-05-30T00:00:00Z", query.Get("start_sequence_timestamp"))
+		return httptesting.BuildResponseString(http.StatusOK, readFixture(t, "list_fills_request.json")), nil
+	})
+
+	// the unknown order of the fill is queried
+	transport.GET(orderUrl, serveFixture(t, "get_order_request.json"))
+	marketOrderID := timeOrderedID(startTime, "33333-00000-000000")
+	ex.orderIDs.Add(marketOrderID, "33333-00000-000000")
+
+	endTime := startTime.Add(time.Hour)
+	trades, err := ex.QueryTrades(context.Background(), "BTCUSD", &types.TradeQueryOptions{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+		Limit:     100,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+
+	// the fills are in ascending order and the size in the quote currency is converted
+	tradeTime := time.Date(2024, 5, 30, 0, 0, 0, 500000000, time.UTC)
+	assert.Equal(t, types.Trade{
+		ID:            timeOrderedID(tradeTime, "1111-11111-111110"),
+		OrderID:       marketOrderID,
+		Exchange:      types.ExchangeCoinbase,
+		Price:         fixedpoint.NewFromInt(50000),
+		Quantity:      fixedpoint.MustNewFromString("0.002"),
+		QuoteQuantity: fixedpoint.NewFromInt(100),
+		Symbol:        "BTCUSD",
+		Side:          types.SideTypeBuy,
+		IsBuyer:       true,
+		IsMaker:       false,
+		Time:          types.Time(tradeTime),
+		Fee:           fixedpoint.MustNewFromString("0.6"),
+		FeeCurrency:   "USD",
+	}, trades[0])
+	assert.True(t, trades[1].IsMaker)
+	assert.Equal(t, timeOrderedID(startTime, testOrderId), trades[1].OrderID)
+
+	// the trade ids increase with the trade time
+	assert.Less(t, trades[0].ID, trades[1].ID)
+
+	// the trades after the last trade id
+	trades, err = ex.QueryTrades(context.Background(), "BTCUSD", &types.TradeQueryOptions{
+		StartTime:   &startTime,
+		EndTime:     &endTime,
+		Limit:       100,
+		LastTradeID: trades[0].ID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.True(t, trades[0].IsMaker)
+}
+
+func TestExchange_QueryOrder(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	transport.GET(orderUrl, serveFixture(t, "get_order_request.json"))
+	transport.GET(fillsUrl, func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, testOrderId, req.URL.Query().Get("order_id"))
+		return httptesting.BuildResponseString(http.StatusOK, readFixture(t, "list_fills_request.json")), nil
+	})
+
+	// the decimal order id is unknown before the order is queried by the uuid
+	orderID := timeOrderedID(time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), testOrderId)
+	_, err := ex.QueryOrder(context.Background(), types.OrderQuery{OrderID: strconv.FormatUint(orderID, 10)})
+	assert.ErrorContains(t, err, "unknown")
+
+	order, err := ex.QueryOrder(context.Background(), types.OrderQuery{OrderID: testOrderId})
+	assert.NoError(t, err)
+	assert.Equal(t, orderID, order.OrderID)
+
+	// the order and its trades are queried by the decimal order id, which is how the strategies query them
+	q := types.OrderQuery{Symbol: "BTCUSD", OrderID: strconv.FormatUint(order.OrderID, 10)}
+	order, err = ex.QueryOrder(context.Background(), q)
+	assert.NoError(t, err)
+	assert.Equal(t, testOrderId, order.UUID)
+
+	ex.orderIDs.Add(timeOrderedID(time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), "33333-00000-000000"), "33333-00000-000000")
+	trades, err := ex.QueryOrderTrades(context.Background(), q)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, trades)
+}
+
+func TestExchange_CancelOrders(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	transport.POST(cancelOrdersUrl, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"order_ids":["11111-00000-000000","44444-00000-000000"]}`, string(body))
+		return httptesting.BuildResponseString(http.StatusOK, readFixture(t, "cancel_orders_request.json")), nil
+	})
+
+	err := ex.CancelOrders(context.Background(),
+		types.Order{UUID: testOrderId},
+		types.Order{UUID: "44444-00000-000000"},
+		types.Order{OrderID: 1},
+	)
+	assert.ErrorContains(t, err, "the order uuid is required")
+	assert.ErrorContains(t, err, "UNKNOWN_CANCEL_ORDER")
+}
@@ -0,0 +1,89 @@
+package coinbase
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// idHashBits is the number of the low bits of the time ordered id which are taken from the hash of the UUID
+const idHashBits = 24
+
+// maxOrderIDs is the max number of the orders kept by orderIDMap, the oldest ones are evicted first.
+const maxOrderIDs = 10000
+
+// hashStringID converts the UUID format id into uint64.
+func hashStringID(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// timeOrderedID converts the UUID into an uint64 id which increases with the time. The high bits are the unix
+// seconds of the given time, and the low bits are the hash of the UUID.
+//
+// The order ids and the trade ids are ordered by the creation time and the trade time, so that the closed orders and
+// the trades can be paginated by the last order id and the last trade id.
+func timeOrderedID(t time.Time, uuid string) uint64 {
+	return uint64(t.Unix())<<idHashBits | hashStringID(uuid)&(1<<idHashBits-1)
+}
+
+// timeOfID returns the time of the time ordered id, truncated to the second.
+func timeOfID(id uint64) time.Time {
+	return time.Unix(int64(id>>idHashBits), 0)
+}
+
+// orderIDMap maps the order ids to the order UUIDs and vice versa.
+//
+// The order id can't be converted back into the UUID, which is required by the server to query the order and its
+// fills. And the fills don't carry the creation time of their orders, so the order ids of the fills are resolved by
+// the UUIDs of the orders.
+type orderIDMap struct {
+	mu       sync.Mutex
+	uuids    map[uint64]string
+	orderIDs map[string]uint64
+	ids      []uint64
+}
+
+func newOrderIDMap() *orderIDMap {
+	return &orderIDMap{
+		uuids:    make(map[uint64]string),
+		orderIDs: make(map[string]uint64),
+	}
+}
+
+func (m *orderIDMap) Add(orderID uint64, uuid string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.uuids[orderID]; ok {
+		return
+	}
+
+	m.uuids[orderID] = uuid
+	m.orderIDs[uuid] = orderID
+	m.ids = append(m.ids, orderID)
+	if len(m.ids) > maxOrderIDs {
+		delete(m.orderIDs, m.uuids[m.ids[0]])
+		delete(m.uuids, m.ids[0])
+		m.ids = m.ids[1:]
+	}
+}
+
+// UUID returns the UUID of the order id
+func (m *orderIDMap) UUID(orderID uint64) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	uuid, ok := m.uuids[orderID]
+	return uuid, ok
+}
+
+// OrderID returns the order id of the UUID
+func (m *orderIDMap) OrderID(uuid string) (uint64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	orderID, ok := m.orderIDs[uuid]
+	return orderID, ok
+}
@@ -0,0 +1,334 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/coinbase/coinbaseapi"
+	"github.com/c9s/bbgo/pkg/exchange/retry"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	marketTradeLogLimiter = rate.NewLimiter(rate.Every(time.Minute), 1)
+	orderLogLimiter       = rate.NewLimiter(rate.Every(time.Minute), 1)
+)
+
+// ExchangeProvider provides the functions to query the balances and the trades of the orders, the user channel only
+// pushes the order updates, so the trades and the balances are queried once the orders are filled.
+type ExchangeProvider interface {
+	QueryAccountBalances(ctx context.Context) (types.BalanceMap, error)
+	QueryOrderTrades(ctx context.Context, q types.OrderQuery) ([]types.Trade, error)
+}
+
+//go:generate callbackgen -type Stream
+type Stream struct {
+	types.StandardStream
+
+	signer           *coinbaseapi.JWTSigner
+	exchangeProvider ExchangeProvider
+
+	// filledQuantities is the cumulative quantity of the orders, it's used to detect the new fills of the orders
+	filledQuantities map[string]fixedpoint.Value
+	// tradeIds is the set of the emitted trades
+	tradeIds map[uint64]struct{}
+	mu       sync.Mutex
+
+	// orderIDs keeps the order ids consistent with the ones returned by the exchange
+	orderIDs *orderIDMap
+
+	level2EventCallbacks       []func(e []Level2Event)
+	marketTradesEventCallbacks []func(e []MarketTradesEvent)
+	userEventCallbacks         []func(e []UserEvent)
+}
+
+func NewStream(signer *coinbaseapi.JWTSigner, exchangeProvider ExchangeProvider) *Stream {
+	stream := &Stream{
+		StandardStream:   types.NewStandardStream(),
+		signer:           signer,
+		exchangeProvider: exchangeProvider,
+		filledQuantities: make(map[string]fixedpoint.Value),
+		tradeIds:         make(map[uint64]struct{}),
+		orderIDs:         newOrderIDMap(),
+	}
+
+	stream.SetEndpointCreator(stream.createEndpoint)
+	stream.SetParser(parseWebSocketEvent)
+	stream.SetDispatcher(stream.dispatchEvent)
+	stream.OnConnect(stream.handleConnect)
+	stream.OnAuth(stream.handleAuth)
+
+	stream.OnLevel2Event(stream.handleLevel2Event)
+	stream.OnMarketTradesEvent(stream.handleMarketTradesEvent)
+	stream.OnUserEvent(stream.handleUserEvent)
+	return stream
+}
+
+// createEndpoint returns the user order data endpoint if the stream only subscribes the user channel, the market data
+// endpoint serves both the market data and the user channels.
+func (s *Stream) createEndpoint(_ context.Context) (string, error) {
+	if s.PublicOnly || len(s.Subscriptions) > 0 {
+		return coinbaseapi.WsMarketDataURL, nil
+	}
+	return coinbaseapi.WsUserURL, nil
+}
+
+func (s *Stream) dispatchEvent(event interface{}) {
+	switch e := event.(type) {
+	case *WebSocketEvent:
+		if err := e.IsValid(); err != nil {
+			log.WithError(err).Error("invalid event")
+			return
+		}
+
+		if e.IsAuthenticated() {
+			s.EmitAuth()
+		}
+
+	case []Level2Event:
+		s.EmitLevel2Event(e)
+
+	case []MarketTradesEvent:
+		s.EmitMarketTradesEvent(e)
+
+	case []UserEvent:
+		s.EmitUserEvent(e)
+	}
+}
+
+func (s *Stream) handleConnect() {
+	// the server closes the subscriptions without any updates in 60-90 seconds, the heartbeats channel keeps them alive
+	if err := s.writeRequest(WsEventTypeSubscribe, ChannelHeartbeats); err != nil {
+		return
+	}
+
+	if err := s.syncSubscriptions(WsEventTypeSubscribe); err != nil {
+		return
+	}
+
+	if s.PublicOnly {
+		return
+	}
+
+	_ = s.writeRequest(WsEventTypeSubscribe, ChannelUser)
+}
+
+// writeRequest sends the request with a new JWT, the JWT is required by the user channel and expires in 2 minutes.
+func (s *Stream) writeRequest(event WsEventType, channel Channel, productIds ...string) error {
+	req := WebsocketRequest{
+		Type:       event,
+		ProductIds: productIds,
+		Channel:    channel,
+	}
+
+	if s.signer != nil {
+		jwt, err := s.signer.Sign("")
+		if err != nil {
+			log.WithError(err).Errorf("failed to sign the %s request", channel)
+			return err
+		}
+		req.JWT = jwt
+	}
+
+	log.Infof("%s channel: %s %v", event, channel, productIds)
+	if err := s.Conn.WriteJSON(req); err != nil {
+		log.WithError(err).Errorf("failed to %s %s", event, channel)
+		return err
+	}
+
+	return nil
+}
+
+// syncSubscriptions groups the product ids by the channel, each channel is subscribed by one request.
+func (s *Stream) syncSubscriptions(event WsEventType) error {
+	if event != WsEventTypeUnsubscribe && event != WsEventTypeSubscribe {
+		return fmt.Errorf("unexpected subscription type: %v", event)
+	}
+
+	var channels []Channel
+	productIds := map[Channel][]string{}
+	for _, subscription := range s.Subscriptions {
+		channel, err := convertSubscription(subscription)
+		if err != nil {
+			log.WithError(err).Errorf("convert error, subscription: %+v", subscription)
+			return err
+		}
+
+		if _, ok := productIds[channel]; !ok {
+			channels = append(channels, channel)
+		}
+		productIds[channel] = append(productIds[channel], toLocalSymbol(subscription.Symbol))
+	}
+
+	for _, channel := range channels {
+		if err := s.writeRequest(event, channel, productIds[channel]...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Stream) Unsubscribe() {
+	// errors are handled in the syncSubscriptions, so they are skipped here.
+	_ = s.syncSubscriptions(WsEventTypeUnsubscribe)
+	s.Resubscribe(func(old []types.Subscription) (new []types.Subscription, err error) {
+		// clear the subscriptions
+		return []types.Subscription{}, nil
+	})
+}
+
+// convertSubscription converts the subscription into the channel, the level2 channel only pushes the full order book.
+func convertSubscription(sub types.Subscription) (Channel, error) {
+	switch sub.Channel {
+	case types.BookChannel:
+		return ChannelLevel2, nil
+
+	case types.MarketTradeChannel:
+		return ChannelMarketTrades, nil
+	}
+
+	return "", fmt.Errorf("unsupported stream channel: %s", sub.Channel)
+}
+
+func (s *Stream) handleAuth() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var balances types.BalanceMap
+	var err error
+	err = retry.GeneralBackoff(ctx, func() error {
+		balances, err = s.exchangeProvider.QueryAccountBalances(ctx)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Error("no more attempts to retrieve balances")
+		return
+	}
+
+	s.EmitBalanceSnapshot(balances)
+}
+
+func (s *Stream) handleLevel2Event(events []Level2Event) {
+	for _, e := range events {
+		switch e.Type {
+		case WsEventTypeSnapshot:
+			s.EmitBookSnapshot(e.OrderBook())
+
+		case WsEventTypeUpdate:
+			s.EmitBookUpdate(e.OrderBook())
+		}
+	}
+}
+
+func (s *Stream) handleMarketTradesEvent(events []MarketTradesEvent) {
+	for _, e := range events {
+		// the snapshot contains the recent trades before the subscription
+		if e.Type != WsEventTypeUpdate {
+			continue
+		}
+
+		for _, t := range e.Trades {
+			trade, err := t.toGlobalTrade()
+			if err != nil {
+				if marketTradeLogLimiter.Allow() {
+					log.WithError(err).Error("failed to convert to market trade")
+				}
+				continue
+			}
+
+			s.EmitMarketTrade(trade)
+		}
+	}
+}
+
+func (s *Stream) handleUserEvent(events []UserEvent) {
+	for _, e := range events {
+		for _, o := range e.Orders {
+			order, err := o.toGlobalOrder(e.Time)
+			if err != nil {
+				if orderLogLimiter.Allow() {
+					log.WithError(err).Error("failed to convert to global order")
+				}
+				continue
+			}
+
+			// the order id of the submitted order is set by the exchange
+			if orderID, ok := s.orderIDs.OrderID(order.UUID); ok {
+				order.OrderID = orderID
+			} else {
+				s.orderIDs.Add(order.OrderID, order.UUID)
+			}
+
+			s.EmitOrderUpdate(*order)
+
+			// the snapshot is used to initialize the filled quantities
+			if s.updateFilledQuantity(o) && e.Type == WsEventTypeUpdate {
+				go s.queryOrderTrades(o.OrderId, order.Symbol)
+			}
+		}
+	}
+}
+
+// updateFilledQuantity returns true if the order has new fills, the finished orders are removed from the map.
+func (s *Stream) updateFilledQuantity(o UserOrder) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filled := o.CumulativeQuantity.Compare(s.filledQuantities[o.OrderId]) > 0
+	switch o.Status {
+	case coinbaseapi.OrderStatusFilled, coinbaseapi.OrderStatusCancelled,
+		coinbaseapi.OrderStatusExpired, coinbaseapi.OrderStatusFailed:
+		delete(s.filledQuantities, o.OrderId)
+	default:
+		s.filledQuantities[o.OrderId] = o.CumulativeQuantity
+	}
+
+	return filled
+}
+
+// queryOrderTrades queries the trades of the order, emits the trades which haven't been emitted and updates the balances.
+func (s *Stream) queryOrderTrades(orderId, symbol string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var trades []types.Trade
+	err := retry.GeneralBackoff(ctx, func() (err error) {
+		trades, err = s.exchangeProvider.QueryOrderTrades(ctx, types.OrderQuery{Symbol: symbol, OrderID: orderId})
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Errorf("failed to query the trades of order %s", orderId)
+		return
+	}
+
+	for _, trade := range trades {
+		if s.isNewTrade(trade.ID) {
+			s.EmitTradeUpdate(trade)
+		}
+	}
+
+	balances, err := s.exchangeProvider.QueryAccountBalances(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to query balances")
+		return
+	}
+
+	s.EmitBalanceUpdate(balances)
+}
+
+func (s *Stream) isNewTrade(id uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tradeIds[id]; ok {
+		return false
+	}
+	s.tradeIds[id] = struct{}{}
+	return true
+}
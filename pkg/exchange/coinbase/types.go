@@ -0,0 +1,297 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/coinbase/coinbaseapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type Channel string
+
+const (
+	ChannelLevel2        Channel = "level2"
+	ChannelMarketTrades  Channel = "market_trades"
+	ChannelUser          Channel = "user"
+	ChannelHeartbeats    Channel = "heartbeats"
+	ChannelSubscriptions Channel = "subscriptions"
+
+	// ChannelL2Data is the channel name of the messages pushed by the level2 channel
+	ChannelL2Data Channel = "l2_data"
+)
+
+type WsEventType string
+
+const (
+	WsEventTypeSubscribe   WsEventType = "subscribe"
+	WsEventTypeUnsubscribe WsEventType = "unsubscribe"
+	WsEventTypeError       WsEventType = "error"
+
+	WsEventTypeSnapshot WsEventType = "snapshot"
+	WsEventTypeUpdate   WsEventType = "update"
+)
+
+type WebsocketRequest struct {
+	Type       WsEventType `json:"type"`
+	ProductIds []string    `json:"product_ids,omitempty"`
+	Channel    Channel     `json:"channel"`
+	JWT        string      `json:"jwt,omitempty"`
+}
+
+/*
+WebSocketEvent is the envelope of all the messages, the events are decoded by the channel.
+
+sample:
+
+	{
+	  "channel": "l2_data",
+	  "client_id": "",
+	  "timestamp": "2023-02-09T20:32:50.714964855Z",
+	  "sequence_num": 0,
+	  "events": [...]
+	}
+
+error:
+
+	{
+	  "type": "error",
+	  "message": "authentication failure"
+	}
+*/
+type WebSocketEvent struct {
+	Type        WsEventType     `json:"type"`
+	Message     string          `json:"message"`
+	Channel     Channel         `json:"channel"`
+	ClientId    string          `json:"client_id"`
+	Timestamp   time.Time       `json:"timestamp"`
+	SequenceNum uint64          `json:"sequence_num"`
+	Events      json.RawMessage `json:"events"`
+}
+
+func (w *WebSocketEvent) IsValid() error {
+	if w.Type == WsEventTypeError {
+		return fmt.Errorf("websocket error: %s", w.Message)
+	}
+	return nil
+}
+
+type SubscriptionsEvent struct {
+	Subscriptions map[Channel][]string `json:"subscriptions"`
+}
+
+// IsAuthenticated returns true if the user channel is subscribed.
+func (w *WebSocketEvent) IsAuthenticated() bool {
+	if w.Channel != ChannelSubscriptions {
+		return false
+	}
+
+	var events []SubscriptionsEvent
+	if err := json.Unmarshal(w.Events, &events); err != nil {
+		return false
+	}
+
+	for _, e := range events {
+		if _, ok := e.Subscriptions[ChannelUser]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+type BookSide string
+
+const (
+	BookSideBid   BookSide = "bid"
+	BookSideOffer BookSide = "offer"
+)
+
+type BookUpdate struct {
+	Side        BookSide         `json:"side"`
+	EventTime   time.Time        `json:"event_time"`
+	PriceLevel  fixedpoint.Value `json:"price_level"`
+	NewQuantity fixedpoint.Value `json:"new_quantity"`
+}
+
+// Level2Event is the full order book snapshot or the incremental updates, the price level with zero quantity is
+// removed from the order book.
+type Level2Event struct {
+	Type      WsEventType  `json:"type"`
+	ProductId string       `json:"product_id"`
+	Updates   []BookUpdate `json:"updates"`
+
+	// Time is the timestamp of the message
+	Time time.Time `json:"-"`
+}
+
+func (e *Level2Event) OrderBook() types.SliceOrderBook {
+	book := types.SliceOrderBook{
+		Symbol: toGlobalSymbol(e.ProductId),
+		Time:   e.Time,
+	}
+
+	for _, u := range e.Updates {
+		pv := types.PriceVolume{Price: u.PriceLevel, Volume: u.NewQuantity}
+		switch u.Side {
+		case BookSideBid:
+			book.Bids = append(book.Bids, pv)
+		case BookSideOffer:
+			book.Asks = append(book.Asks, pv)
+		}
+	}
+
+	return book
+}
+
+type MarketTrade struct {
+	TradeId   string           `json:"trade_id"`
+	ProductId string           `json:"product_id"`
+	Price     fixedpoint.Value `json:"price"`
+	Size      fixedpoint.Value `json:"size"`
+	Side      coinbaseapi.Side `json:"side"`
+	Time      time.Time        `json:"time"`
+}
+
+func (t *MarketTrade) toGlobalTrade() (types.Trade, error) {
+	side, err := toGlobalSideType(t.Side)
+	if err != nil {
+		return types.Trade{}, err
+	}
+
+	return types.Trade{
+		ID:            hashStringID(t.TradeId),
+		Exchange:      types.ExchangeCoinbase,
+		Price:         t.Price,
+		Quantity:      t.Size,
+		QuoteQuantity: t.Price.Mul(t.Size),
+		Symbol:        toGlobalSymbol(t.ProductId),
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		Time:          types.Time(t.Time),
+	}, nil
+}
+
+type MarketTradesEvent struct {
+	Type   WsEventType   `json:"type"`
+	Trades []MarketTrade `json:"trades"`
+}
+
+type UserOrder struct {
+	OrderId            string                  `json:"order_id"`
+	ClientOrderId      string                  `json:"client_order_id"`
+	CumulativeQuantity fixedpoint.Value        `json:"cumulative_quantity"`
+	LeavesQuantity     fixedpoint.Value        `json:"leaves_quantity"`
+	AvgPrice           fixedpoint.Value        `json:"avg_price"`
+	TotalFees          fixedpoint.Value        `json:"total_fees"`
+	Status             coinbaseapi.OrderStatus `json:"status"`
+	ProductId          string                  `json:"product_id"`
+	CreationTime       time.Time               `json:"creation_time"`
+	OrderSide          coinbaseapi.Side        `json:"order_side"`
+	// OrderType is in the title case, e.g. Limit, Market and Stop Limit
+	OrderType   string                  `json:"order_type"`
+	TimeInForce coinbaseapi.TimeInForce `json:"time_in_force"`
+	LimitPrice  fixedpoint.Value        `json:"limit_price"`
+	PostOnly    bool                    `json:"post_only"`
+}
+
+func (o *UserOrder) toGlobalOrder(updateTime time.Time) (*types.Order, error) {
+	side, err := toGlobalSideType(o.OrderSide)
+	if err != nil {
+		return nil, err
+	}
+
+	localOrderType := coinbaseapi.OrderType(strings.ReplaceAll(strings.ToUpper(o.OrderType), " ", "_"))
+	orderType, err := toGlobalOrderType(localOrderType, o.PostOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	timeInForce := types.TimeInForceGTC
+	if len(o.TimeInForce) > 0 {
+		if timeInForce, err = toGlobalTimeInForce(o.TimeInForce); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := toGlobalOrderStatus(o.Status, o.CumulativeQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	price := o.LimitPrice
+	if orderType == types.OrderTypeMarket {
+		price = o.AvgPrice
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: o.ClientOrderId,
+			Symbol:        toGlobalSymbol(o.ProductId),
+			Side:          side,
+			Type:          orderType,
+			Quantity:      o.CumulativeQuantity.Add(o.LeavesQuantity),
+			Price:         price,
+			TimeInForce:   timeInForce,
+		},
+		Exchange:         types.ExchangeCoinbase,
+		OrderID:          timeOrderedID(o.CreationTime, o.OrderId),
+		UUID:             o.OrderId,
+		Status:           status,
+		ExecutedQuantity: o.CumulativeQuantity,
+		IsWorking:        status == types.OrderStatusNew || status == types.OrderStatusPartiallyFilled,
+		CreationTime:     types.Time(o.CreationTime),
+		UpdateTime:       types.Time(updateTime),
+	}, nil
+}
+
+type UserEvent struct {
+	Type   WsEventType `json:"type"`
+	Orders []UserOrder `json:"orders"`
+
+	// Time is the timestamp of the message
+	Time time.Time `json:"-"`
+}
+
+func parseWebSocketEvent(in []byte) (interface{}, error) {
+	var e WebSocketEvent
+	if err := json.Unmarshal(in, &e); err != nil {
+		return nil, err
+	}
+
+	switch e.Channel {
+	case ChannelHeartbeats:
+		return &types.WebsocketPongEvent{}, nil
+
+	case ChannelL2Data:
+		var events []Level2Event
+		if err := json.Unmarshal(e.Events, &events); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into Level2Event: %+v, err: %w", string(e.Events), err)
+		}
+		for i := range events {
+			events[i].Time = e.Timestamp
+		}
+		return events, nil
+
+	case ChannelMarketTrades:
+		var events []MarketTradesEvent
+		if err := json.Unmarshal(e.Events, &events); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into MarketTradesEvent: %+v, err: %w", string(e.Events), err)
+		}
+		return events, nil
+
+	case ChannelUser:
+		var events []UserEvent
+		if err := json.Unmarshal(e.Events, &events); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into UserEvent: %+v, err: %w", string(e.Events), err)
+		}
+		for i := range events {
+			events[i].Time = e.Timestamp
+		}
+		return events, nil
+	}
+
+	return &e, nil
+}
@@ -0,0 +1,235 @@
+package coinbase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_parseWebSocketEvent(t *testing.T) {
+	t.Run("level2 snapshot", func(t *testing.T) {
+		in := `{
+		  "channel": "l2_data",
+		  "client_id": "",
+		  "timestamp": "2024-05-30T00:00:01.123Z",
+		  "sequence_num": 0,
+		  "events": [
+		    {
+		      "type": "snapshot",
+		      "product_id": "BTC-USD",
+		      "updates": [
+		        {"side": "bid", "event_time": "1970-01-01T00:00:00Z", "price_level": "67801.9", "new_quantity": "0.5"},
+		        {"side": "offer", "event_time": "1970-01-01T00:00:00Z", "price_level": "67802.01", "new_quantity": "0.12"}
+		      ]
+		    }
+		  ]
+		}`
+		e, err := parseWebSocketEvent([]byte(in))
+		assert.NoError(t, err)
+		events, ok := e.([]Level2Event)
+		assert.True(t, ok)
+		assert.Len(t, events, 1)
+		assert.Equal(t, WsEventTypeSnapshot, events[0].Type)
+		assert.Equal(t, types.SliceOrderBook{
+			Symbol: "BTCUSD",
+			Time:   time.Date(2024, 5, 30, 0, 0, 1, 123000000, time.UTC),
+			Bids:   types.PriceVolumeSlice{{Price: fixedpoint.MustNewFromString("67801.9"), Volume: fixedpoint.MustNewFromString("0.5")}},
+			Asks:   types.PriceVolumeSlice{{Price: fixedpoint.MustNewFromString("67802.01"), Volume: fixedpoint.MustNewFromString("0.12")}},
+		}, events[0].OrderBook())
+	})
+
+	t.Run("market trades", func(t *testing.T) {
+		in := `{
+		  "channel": "market_trades",
+		  "client_id": "",
+		  "timestamp": "2024-05-30T00:00:01.123Z",
+		  "sequence_num": 1,
+		  "events": [
+		    {
+		      "type": "update",
+		      "trades": [
+		        {"trade_id": "643960311", "product_id": "BTC-USD", "price": "67801.9", "size": "0.01", "side": "SELL", "time": "2024-05-30T00:00:01.1Z"}
+		      ]
+		    }
+		  ]
+		}`
+		e, err := parseWebSocketEvent([]byte(in))
+		assert.NoError(t, err)
+		events, ok := e.([]MarketTradesEvent)
+		assert.True(t, ok)
+		assert.Len(t, events, 1)
+
+		trade, err := events[0].Trades[0].toGlobalTrade()
+		assert.NoError(t, err)
+		assert.Equal(t, types.Trade{
+			ID:            hashStringID("643960311"),
+			Exchange:      types.ExchangeCoinbase,
+			Price:         fixedpoint.MustNewFromString("67801.9"),
+			Quantity:      fixedpoint.MustNewFromString("0.01"),
+			QuoteQuantity: fixedpoint.MustNewFromString("678.019"),
+			Symbol:        "BTCUSD",
+			Side:          types.SideTypeSell,
+			Time:          types.Time(time.Date(2024, 5, 30, 0, 0, 1, 100000000, time.UTC)),
+		}, trade)
+	})
+
+	t.Run("user", func(t *testing.T) {
+		in := `{
+		  "channel": "user",
+		  "client_id": "",
+		  "timestamp": "2024-05-30T00:00:02Z",
+		  "sequence_num": 2,
+		  "events": [
+		    {
+		      "type": "update",
+		      "orders": [
+		        {
+		          "order_id": "11111-00000-000000",
+		          "client_order_id": "0000-00000-000000",
+		          "cumulative_quantity": "0.0005",
+		          "leaves_quantity": "0.0005",
+		          "avg_price": "60000",
+		          "total_fees": "0.18",
+		          "status": "OPEN",
+		          "product_id": "BTC-USD",
+		          "creation_time": "2024-05-30T00:00:00.123Z",
+		          "order_side": "BUY",
+		          "order_type": "Limit",
+		          "time_in_force": "GOOD_UNTIL_CANCELLED",
+		          "limit_price": "60000",
+		          "post_only": true
+		        }
+		      ]
+		    }
+		  ]
+		}`
+		e, err := parseWebSocketEvent([]byte(in))
+		assert.NoError(t, err)
+		events, ok := e.([]UserEvent)
+		assert.True(t, ok)
+		assert.Len(t, events, 1)
+
+		order, err := events[0].Orders[0].toGlobalOrder(events[0].Time)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Order{
+			SubmitOrder: types.SubmitOrder{
+				ClientOrderID: "0000-00000-000000",
+				Symbol:        "BTCUSD",
+				Side:          types.SideTypeBuy,
+				Type:          types.OrderTypeLimitMaker,
+				Quantity:      fixedpoint.MustNewFromString("0.001"),
+				Price:         fixedpoint.NewFromInt(60000),
+				TimeInForce:   types.TimeInForceGTC,
+			},
+			Exchange:         types.ExchangeCoinbase,
+			OrderID:          timeOrderedID(time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC), "11111-00000-000000"),
+			UUID:             "11111-00000-000000",
+			Status:           types.OrderStatusPartiallyFilled,
+			ExecutedQuantity: fixedpoint.MustNewFromString("0.0005"),
+			IsWorking:        true,
+			CreationTime:     types.Time(time.Date(2024, 5, 30, 0, 0, 0, 123000000, time.UTC)),
+			UpdateTime:       types.Time(time.Date(2024, 5, 30, 0, 0, 2, 0, time.UTC)),
+		}, order)
+	})
+
+	t.Run("subscriptions", func(t *testing.T) {
+		in := `{
+		  "channel": "subscriptions",
+		  "client_id": "",
+		  "timestamp": "2024-05-30T00:00:00Z",
+		  "sequence_num": 3,
+		  "events": [{"subscriptions": {"heartbeats": ["heartbeats"], "user": ["2222-000000-000000"]}}]
+		}`
+		e, err := parseWebSocketEvent([]byte(in))
+		assert.NoError(t, err)
+		event, ok := e.(*WebSocketEvent)
+		assert.True(t, ok)
+		assert.NoError(t, event.IsValid())
+		assert.True(t, event.IsAuthenticated())
+	})
+
+	t.Run("heartbeats", func(t *testing.T) {
+		in := `{"channel":"heartbeats","client_id":"","timestamp":"2024-05-30T00:00:00Z","sequence_num":4,"events":[{"current_time":"2024-05-30 00:00:00.1 +0000 UTC","heartbeat_counter":3049}]}`
+		e, err := parseWebSocketEvent([]byte(in))
+		assert.NoError(t, err)
+		assert.IsType(t, &types.WebsocketPongEvent{}, e)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"type":"error","message":"authentication failure"}`))
+		assert.NoError(t, err)
+		event, ok := e.(*WebSocketEvent)
+		assert.True(t, ok)
+		assert.ErrorContains(t, event.IsValid(), "authentication failure")
+	})
+}
+
+type mockExchangeProvider struct {
+	trades []types.Trade
+}
+
+func (m *mockExchangeProvider) QueryAccountBalances(ctx context.Context) (types.BalanceMap, error) {
+	return types.BalanceMap{"BTC": {Currency: "BTC", Available: fixedpoint.One}}, nil
+}
+
+func (m *mockExchangeProvider) QueryOrderTrades(ctx context.Context, q types.OrderQuery) ([]types.Trade, error) {
+	return m.trades, nil
+}
+
+func TestStream_handleUserEvent(t *testing.T) {
+	provider := &mockExchangeProvider{
+		trades: []types.Trade{{ID: 1, Symbol: "BTCUSD"}},
+	}
+	stream := NewStream(nil, provider)
+
+	tradeC := make(chan types.Trade, 2)
+	stream.OnTradeUpdate(func(trade types.Trade) { tradeC <- trade })
+	balanceC := make(chan types.BalanceMap, 2)
+	stream.OnBalanceUpdate(func(balances types.BalanceMap) { balanceC <- balances })
+
+	order := UserOrder{
+		OrderId:            "11111-00000-000000",
+		CumulativeQuantity: fixedpoint.Zero,
+		LeavesQuantity:     fixedpoint.One,
+		Status:             "OPEN",
+		ProductId:          "BTC-USD",
+		OrderSide:          "BUY",
+		OrderType:          "Limit",
+	}
+
+	// the snapshot doesn't trigger the trade query
+	stream.handleUserEvent([]UserEvent{{Type: WsEventTypeSnapshot, Orders: []UserOrder{order}}})
+	assert.Len(t, tradeC, 0)
+
+	order.CumulativeQuantity = fixedpoint.NewFromFloat(0.5)
+	stream.handleUserEvent([]UserEvent{{Type: WsEventTypeUpdate, Orders: []UserOrder{order}}})
+	select {
+	case trade := <-tradeC:
+		assert.Equal(t, uint64(1), trade.ID)
+	case <-time.After(time.Second):
+		t.Fatal("trade update is not emitted")
+	}
+
+	select {
+	case balances := <-balanceC:
+		assert.Equal(t, fixedpoint.One, balances["BTC"].Available)
+	case <-time.After(time.Second):
+		t.Fatal("balance update is not emitted")
+	}
+
+	// the emitted trades are skipped
+	order.CumulativeQuantity = fixedpoint.One
+	order.Status = "FILLED"
+	stream.handleUserEvent([]UserEvent{{Type: WsEventTypeUpdate, Orders: []UserOrder{order}}})
+	select {
+	case <-balanceC:
+	case <-time.After(time.Second):
+		t.Fatal("balance update is not emitted")
+	}
+	assert.Len(t, tradeC, 0)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/c9s/bbgo/pkg/exchange/binance"
 	"github.com/c9s/bbgo/pkg/exchange/bitget"
 	"github.com/c9s/bbgo/pkg/exchange/bybit"
+	"github.com/c9s/bbgo/pkg/exchange/coinbase"
+	"github.com/c9s/bbgo/pkg/exchange/gateio"
 	"github.com/c9s/bbgo/pkg/exchange/kucoin"
 	"github.com/c9s/bbgo/pkg/exchange/max"
 	"github.com/c9s/bbgo/pkg/exchange/okex"
@@ -48,6 +50,12 @@ func New(n types.ExchangeName, key, secret, passphrase string) (types.ExchangeMi
 	case types.ExchangeBybit:
 		return bybit.New(key, secret)
 
+	case types.ExchangeGateIO:
+		return gateio.New(key, secret), nil
+
+	case types.ExchangeCoinbase:
+		return coinbase.New(key, secret)
+
 	default:
 		return nil, fmt.Errorf("unsupported exchange: %v", n)
 
@@ -0,0 +1,30 @@
+package gateio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/conformance"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestExchange_Conformance(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	recordings, err := httptesting.LoadRecordings("gateioapi/testdata/conformance.json")
+	assert.NoError(t, err)
+	transport.Replay(recordings)
+
+	conformance.RunTest(t, ex, conformance.Config{
+		Symbol:    "BTCUSDT",
+		StartTime: time.Unix(1717027200, 0),
+		EndTime:   time.Unix(1717113600, 0),
+		Stream: &conformance.StreamConfig{
+			Messages: []string{
+				`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.order_book","event":"update","result":{"t":1717031187100,"lastUpdateId":48791820,"s":"BTC_USDT","bids":[["66051.2","0.5"]],"asks":[["66051.3","0.3"]]}}`,
+				`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.trades","event":"update","result":{"id":309143071,"create_time":1717031187,"create_time_ms":"1717031187213.4578","side":"sell","currency_pair":"BTC_USDT","amount":"0.5","price":"66051.2","range":"2107-2107"}}`,
+			},
+		},
+	})
+}
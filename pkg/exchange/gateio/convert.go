@@ -0,0 +1,428 @@
+package gateio
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/gateio/gateioapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	// clientOrderIdPrefix is required by the user defined order text.
+	clientOrderIdPrefix = "t-"
+	// maxClientOrderIdLen is the max length of the order text without the prefix.
+	maxClientOrderIdLen = 28
+)
+
+var (
+	// localSymbols caches the local symbols of the markets queried from the server, it complements the generated
+	// symbolMap with the newly listed markets.
+	localSymbols sync.Map
+
+	quoteCurrencies = []string{"USDT", "USDC", "BTC", "ETH", "TRY", "EUR", "USD"}
+)
+
+//go:generate go run generate_symbol_map.go
+func toLocalSymbol(symbol string) string {
+	if s, ok := symbolMap[symbol]; ok {
+		return s
+	}
+
+	if s, ok := localSymbols.Load(symbol); ok {
+		return s.(string)
+	}
+
+	if s, ok := types.DefaultSymbolRegistry.LocalSymbol(types.ExchangeGateIO, symbol); ok {
+		return s
+	}
+
+	for _, quote := range quoteCurrencies {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return symbol[:len(symbol)-len(quote)] + "_" + quote
+		}
+	}
+
+	log.Warnf("failed to look up local symbol from %s", symbol)
+	return symbol
+}
+
+func toGlobalSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "_", "")
+}
+
+func toGlobalMarket(m gateioapi.CurrencyPair) types.Market {
+	tickSize := fixedpoint.NewFromFloat(math.Pow10(-m.Precision))
+	stepSize := fixedpoint.NewFromFloat(math.Pow10(-m.AmountPrecision))
+	return types.Market{
+		Exchange:        types.ExchangeGateIO,
+		Symbol:          toGlobalSymbol(m.Id),
+		LocalSymbol:     m.Id,
+		PricePrecision:  m.Precision,
+		VolumePrecision: m.AmountPrecision,
+		QuoteCurrency:   m.Quote,
+		BaseCurrency:    m.Base,
+		MinNotional:     m.MinQuoteAmount,
+		MinAmount:       m.MinQuoteAmount,
+
+		// quantity
+		MinQuantity: fixedpoint.Max(m.MinBaseAmount, stepSize),
+		MaxQuantity: m.MaxBaseAmount,
+		StepSize:    stepSize,
+
+		// price
+		MinPrice: tickSize,
+		TickSize: tickSize,
+	}
+}
+
+func toGlobalTicker(t gateioapi.Ticker, time time.Time) types.Ticker {
+	return types.Ticker{
+		Time:   time,
+		Volume: t.BaseVolume,
+		Last:   t.Last,
+		// Open is calculated from the last price and the change percentage since the open price is not provided.
+		Open: t.Last.Div(fixedpoint.One.Add(t.ChangePercentage.Div(fixedpoint.NewFromInt(100)))),
+		High: t.High24h,
+		Low:  t.Low24h,
+		Buy:  t.HighestBid,
+		Sell: t.LowestAsk,
+	}
+}
+
+var (
+	// supportedIntervals is the map of the supported intervals and the seconds of them.
+	supportedIntervals = map[types.Interval]int{
+		types.Interval1m:  60,
+		types.Interval5m:  60 * 5,
+		types.Interval15m: 60 * 15,
+		types.Interval30m: 60 * 30,
+		types.Interval1h:  60 * 60,
+		types.Interval4h:  60 * 60 * 4,
+		types.Interval1d:  60 * 60 * 24,
+		types.Interval1w:  60 * 60 * 24 * 7,
+		types.Interval1mo: 60 * 60 * 24 * 30,
+	}
+
+	toLocalInterval = map[types.Interval]gateioapi.CandlestickInterval{
+		types.Interval1m:  gateioapi.CandlestickInterval1m,
+		types.Interval5m:  gateioapi.CandlestickInterval5m,
+		types.Interval15m: gateioapi.CandlestickInterval15m,
+		types.Interval30m: gateioapi.CandlestickInterval30m,
+		types.Interval1h:  gateioapi.CandlestickInterval1h,
+		types.Interval4h:  gateioapi.CandlestickInterval4h,
+		types.Interval1d:  gateioapi.CandlestickInterval1d,
+		types.Interval1w:  gateioapi.CandlestickInterval7d,
+		types.Interval1mo: gateioapi.CandlestickInterval30d,
+	}
+)
+
+func toGlobalKLine(symbol string, interval types.Interval, c gateioapi.Candlestick) types.KLine {
+	startTime := c.StartTime.Time()
+	return types.KLine{
+		Exchange:    types.ExchangeGateIO,
+		Symbol:      symbol,
+		StartTime:   types.Time(startTime),
+		EndTime:     types.Time(startTime.Add(interval.Duration() - time.Millisecond)),
+		Interval:    interval,
+		Open:        c.Open,
+		Close:       c.Close,
+		High:        c.High,
+		Low:         c.Low,
+		Volume:      c.BaseVolume,
+		QuoteVolume: c.QuoteVolume,
+		Closed:      c.WindowClosed,
+	}
+}
+
+func toGlobalBalanceMap(accounts []gateioapi.SpotAccount) types.BalanceMap {
+	balanceMap := make(types.BalanceMap, len(accounts))
+	for _, a := range accounts {
+		balanceMap[a.Currency] = types.Balance{
+			Currency:  a.Currency,
+			Available: a.Available,
+			Locked:    a.Locked,
+		}
+	}
+	return balanceMap
+}
+
+func toGlobalSideType(side gateioapi.Side) (types.SideType, error) {
+	switch side {
+	case gateioapi.SideBuy:
+		return types.SideTypeBuy, nil
+
+	case gateioapi.SideSell:
+		return types.SideTypeSell, nil
+
+	default:
+		return types.SideType(side), fmt.Errorf("unexpected side: %s", side)
+	}
+}
+
+func toGlobalOrderType(orderType gateioapi.OrderType, tif gateioapi.TimeInForce) (types.OrderType, error) {
+	switch orderType {
+	case gateioapi.OrderTypeMarket:
+		return types.OrderTypeMarket, nil
+
+	case gateioapi.OrderTypeLimit:
+		if tif == gateioapi.TimeInForcePOC {
+			return types.OrderTypeLimitMaker, nil
+		}
+		return types.OrderTypeLimit, nil
+
+	default:
+		return types.OrderType(orderType), fmt.Errorf("unexpected order type: %s", orderType)
+	}
+}
+
+func toGlobalTimeInForce(tif gateioapi.TimeInForce) (types.TimeInForce, error) {
+	switch tif {
+	// the post-only orders are converted to the LIMIT_MAKER orders with GTC
+	case gateioapi.TimeInForceGTC, gateioapi.TimeInForcePOC:
+		return types.TimeInForceGTC, nil
+
+	case gateioapi.TimeInForceIOC:
+		return types.TimeInForceIOC, nil
+
+	case gateioapi.TimeInForceFOK:
+		return types.TimeInForceFOK, nil
+
+	default:
+		return types.TimeInForce(tif), fmt.Errorf("unexpected time in force: %s", tif)
+	}
+}
+
+func toGlobalOrderStatus(order gateioapi.Order) (types.OrderStatus, error) {
+	switch order.Status {
+	case gateioapi.OrderStatusOpen:
+		if order.FilledAmount.IsZero() {
+			return types.OrderStatusNew, nil
+		}
+		return types.OrderStatusPartiallyFilled, nil
+
+	case gateioapi.OrderStatusClosed:
+		// the ioc orders are closed with finish_as=ioc, they may not be fully filled.
+		if order.FinishAs == gateioapi.FinishAsIOC && !order.Left.IsZero() && !isMarketBuy(order) {
+			return types.OrderStatusCanceled, nil
+		}
+		return types.OrderStatusFilled, nil
+
+	case gateioapi.OrderStatusCancelled:
+		return types.OrderStatusCanceled, nil
+
+	default:
+		return types.OrderStatus(order.Status), fmt.Errorf("unexpected order status: %s", order.Status)
+	}
+}
+
+func isMarketBuy(order gateioapi.Order) bool {
+	return order.Type == gateioapi.OrderTypeMarket && order.Side == gateioapi.SideBuy
+}
+
+func toGlobalOrder(order gateioapi.Order) (*types.Order, error) {
+	side, err := toGlobalSideType(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType, err := toGlobalOrderType(order.Type, order.TimeInForce)
+	if err != nil {
+		return nil, err
+	}
+
+	// the market orders are always ioc orders.
+	timeInForce := types.TimeInForceIOC
+	if order.Type != gateioapi.OrderTypeMarket {
+		timeInForce, err = toGlobalTimeInForce(order.TimeInForce)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := toGlobalOrderStatus(order)
+	if err != nil {
+		return nil, err
+	}
+
+	orderId, err := strconv.ParseUint(order.Id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected order id: %s, err: %w", order.Id, err)
+	}
+
+	price := order.Price
+	quantity := order.Amount
+	if order.Type == gateioapi.OrderTypeMarket {
+		price = order.AvgDealPrice
+		// the amount of the market buy orders is in the quote currency, use the filled amount as the quantity
+		// once the order is finished, otherwise estimate it by the average deal price.
+		if order.Side == gateioapi.SideBuy {
+			switch {
+			case order.Status != gateioapi.OrderStatusOpen:
+				quantity = order.FilledAmount
+			case !order.AvgDealPrice.IsZero():
+				quantity = order.Amount.Div(order.AvgDealPrice)
+			}
+		}
+	}
+
+	return &types.Order{
+		SubmitOrder: types.SubmitOrder{
+			ClientOrderID: strings.TrimPrefix(order.Text, clientOrderIdPrefix),
+			Symbol:        toGlobalSymbol(order.CurrencyPair),
+			Side:          side,
+			Type:          orderType,
+			Quantity:      quantity,
+			Price:         price,
+			TimeInForce:   timeInForce,
+		},
+		Exchange:         types.ExchangeGateIO,
+		OrderID:          orderId,
+		UUID:             order.Id,
+		Status:           status,
+		ExecutedQuantity: order.FilledAmount,
+		IsWorking:        status == types.OrderStatusNew || status == types.OrderStatusPartiallyFilled,
+		CreationTime:     types.Time(order.CreateTimeMs.Time()),
+		UpdateTime:       types.Time(order.UpdateTimeMs.Time()),
+	}, nil
+}
+
+func toGlobalTrade(trade gateioapi.Trade) (*types.Trade, error) {
+	side, err := toGlobalSideType(trade.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	tradeId, err := strconv.ParseUint(trade.Id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected trade id: %s, err: %w", trade.Id, err)
+	}
+
+	orderId, err := strconv.ParseUint(trade.OrderId, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected order id: %s, err: %w", trade.OrderId, err)
+	}
+
+	fee, feeCurrency := trade.Fee, trade.FeeCurrency
+	// the fee is deducted by GT or the point card if the fee currency is not provided
+	if feeCurrency == "" || (fee.IsZero() && !trade.GtFee.IsZero()) {
+		fee, feeCurrency = trade.GtFee, "GT"
+	}
+
+	return &types.Trade{
+		ID:            tradeId,
+		OrderID:       orderId,
+		Exchange:      types.ExchangeGateIO,
+		Price:         trade.Price,
+		Quantity:      trade.Amount,
+		QuoteQuantity: trade.Price.Mul(trade.Amount),
+		Symbol:        toGlobalSymbol(trade.CurrencyPair),
+		Side:          side,
+		IsBuyer:       side == types.SideTypeBuy,
+		IsMaker:       trade.Role == gateioapi.RoleMaker,
+		Time:          types.Time(trade.CreateTimeMs.Time()),
+		Fee:           fee,
+		FeeCurrency:   feeCurrency,
+	}, nil
+}
+
+func toLocalSide(side types.SideType) (gateioapi.Side, error) {
+	switch side {
+	case types.SideTypeBuy:
+		return gateioapi.SideBuy, nil
+
+	case types.SideTypeSell:
+		return gateioapi.SideSell, nil
+
+	default:
+		return "", fmt.Errorf("side type %s not supported", side)
+	}
+}
+
+func toLocalTimeInForce(orderType types.OrderType, tif types.TimeInForce) (gateioapi.TimeInForce, error) {
+	if orderType == types.OrderTypeLimitMaker {
+		return gateioapi.TimeInForcePOC, nil
+	}
+
+	switch tif {
+	case types.TimeInForceGTC, "":
+		return gateioapi.TimeInForceGTC, nil
+
+	case types.TimeInForceIOC:
+		return gateioapi.TimeInForceIOC, nil
+
+	case types.TimeInForceFOK:
+		return gateioapi.TimeInForceFOK, nil
+
+	default:
+		return "", fmt.Errorf("time in force %s not supported", tif)
+	}
+}
+
+func toGlobalDepositStatus(status gateioapi.DepositStatus) types.DepositStatus {
+	switch status {
+	case gateioapi.DepositStatusDone:
+		return types.DepositSuccess
+
+	case gateioapi.DepositStatusCancel:
+		return types.DepositCancelled
+
+	case gateioapi.DepositStatusFail, gateioapi.DepositStatusInvalid:
+		return types.DepositRejected
+
+	default:
+		return types.DepositPending
+	}
+}
+
+func toGlobalDeposit(d gateioapi.Deposit) types.Deposit {
+	return types.Deposit{
+		Exchange:      types.ExchangeGateIO,
+		Time:          types.Time(d.Timestamp.Time()),
+		Amount:        d.Amount,
+		Asset:         d.Currency,
+		Address:       d.Address,
+		AddressTag:    d.Memo,
+		TransactionID: d.Txid,
+		Status:        toGlobalDepositStatus(d.Status),
+	}
+}
+
+func toGlobalWithdrawStatus(status gateioapi.DepositStatus) string {
+	switch status {
+	case gateioapi.DepositStatusDone:
+		// make it compatible with binance
+		return "completed"
+
+	case gateioapi.DepositStatusCancel:
+		return "canceled"
+
+	case gateioapi.DepositStatusFail, gateioapi.DepositStatusInvalid:
+		return "failed"
+
+	default:
+		return strings.ToLower(string(status))
+	}
+}
+
+func toGlobalWithdraw(w gateioapi.Withdrawal) types.Withdraw {
+	return types.Withdraw{
+		Exchange:               types.ExchangeGateIO,
+		Asset:                  w.Currency,
+		Amount:                 w.Amount,
+		Address:                w.Address,
+		AddressTag:             w.Memo,
+		Status:                 toGlobalWithdrawStatus(w.Status),
+		TransactionID:          w.Txid,
+		TransactionFee:         w.Fee,
+		TransactionFeeCurrency: w.Currency,
+		WithdrawOrderID:        w.Id,
+		ApplyTime:              types.Time(w.Timestamp.Time()),
+		Network:                w.Chain,
+	}
+}
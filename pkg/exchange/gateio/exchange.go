@@ -0,0 +1,646 @@
+package gateio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/gateio/gateioapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	ID = "gateio"
+
+	// PlatformToken is the platform currency of Gate.io, it can be used to deduct the trading fee.
+	PlatformToken = "GT"
+
+	defaultKLineLimit = 1000
+	// maxOrderQueryLimit is the max limit of the open orders and the finished orders
+	maxOrderQueryLimit = 100
+	maxTradeQueryLimit = 1000
+	// maxWalletQueryLimit is the max limit of the deposit and withdrawal records
+	maxWalletQueryLimit = 500
+
+	// maxHistoryQueryWindow is the max time range of the wallet records and the trades
+	maxHistoryQueryWindow = 30 * 24 * time.Hour
+)
+
+// https://www.gate.io/docs/developers/apiv4/#frequency-limit-rule
+var (
+	// publicRateLimiter is used by the public endpoints, 200r/10s per endpoint
+	publicRateLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 10)
+	// accountRateLimiter is used by the account and wallet endpoints
+	accountRateLimiter = rate.NewLimiter(rate.Every(200*time.Millisecond), 5)
+	// orderRateLimiter is used by the order placement and cancellation, 10r/s per currency pair
+	orderRateLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 10)
+	// queryOrderRateLimiter is used by the order and trade query endpoints, 200r/10s
+	queryOrderRateLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 10)
+
+	log = logrus.WithFields(logrus.Fields{
+		"exchange": ID,
+	})
+
+	_ types.ExchangeMinimal             = &Exchange{}
+	_ types.Exchange                    = &Exchange{}
+	_ types.ExchangeAccountService      = &Exchange{}
+	_ types.ExchangeMarketDataService   = &Exchange{}
+	_ types.ExchangeTradeService        = &Exchange{}
+	_ types.ExchangeOrderQueryService   = &Exchange{}
+	_ types.ExchangeTradeHistoryService = &Exchange{}
+	_ types.ExchangeTransferService     = &Exchange{}
+	_ types.CustomIntervalProvider      = &Exchange{}
+	_ types.ExchangeDefaultFeeRates     = &Exchange{}
+)
+
+type Exchange struct {
+	key, secret string
+	client      *gateioapi.RestClient
+}
+
+func New(key, secret string) *Exchange {
+	client := gateioapi.NewClient()
+	if len(key) > 0 && len(secret) > 0 {
+		client.Auth(key, secret)
+	}
+
+	return &Exchange{
+		key: key,
+		// pragma: allowlist nextline secret
+		secret: secret,
+		client: client,
+	}
+}
+
+func (e *Exchange) Name() types.ExchangeName {
+	return types.ExchangeGateIO
+}
+
+func (e *Exchange) PlatformFeeCurrency() string {
+	return PlatformToken
+}
+
+func (e *Exchange) NewStream() types.Stream {
+	return NewStream(e.key, e.secret, e)
+}
+
+func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	pairs, err := e.client.NewGetCurrencyPairsRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query currency pairs: %w", err)
+	}
+
+	markets := types.MarketMap{}
+	for _, pair := range pairs {
+		if pair.TradeStatus == gateioapi.TradeStatusUntradable {
+			continue
+		}
+
+		market := toGlobalMarket(pair)
+		localSymbols.Store(market.Symbol, market.LocalSymbol)
+		markets.Add(market)
+	}
+
+	return markets, nil
+}
+
+func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
+	}
+
+	tickers, err := e.client.NewGetTickersRequest().CurrencyPair(toLocalSymbol(symbol)).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticker, symbol: %s, err: %w", symbol, err)
+	}
+
+	if len(tickers) != 1 {
+		return nil, fmt.Errorf("unexpected ticker length, exp: 1, got: %d", len(tickers))
+	}
+
+	ticker := toGlobalTicker(tickers[0], time.Now())
+	return &ticker, nil
+}
+
+func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
+	tickers := map[string]types.Ticker{}
+	if len(symbols) == 1 {
+		ticker, err := e.QueryTicker(ctx, symbols[0])
+		if err != nil {
+			return nil, err
+		}
+
+		tickers[symbols[0]] = *ticker
+		return tickers, nil
+	}
+
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
+	}
+
+	allTickers, err := e.client.NewGetTickersRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickers: %w", err)
+	}
+
+	now := time.Now()
+	for _, t := range allTickers {
+		tickers[toGlobalSymbol(t.CurrencyPair)] = toGlobalTicker(t, now)
+	}
+
+	if len(symbols) == 0 {
+		return tickers, nil
+	}
+
+	filtered := make(map[string]types.Ticker, len(symbols))
+	for _, s := range symbols {
+		if t, ok := tickers[s]; ok {
+			filtered[s] = t
+		}
+	}
+	return filtered, nil
+}
+
+/*
+QueryKLines queries the k lines, the `limit` is conflicted with `from` and `to` on the server side, so the time range
+is calculated from the limit once the start time or the end time is specified.
+*/
+func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	localInterval, ok := toLocalInterval[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	if err := publicRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("klines rate limiter wait error: %w", err)
+	}
+
+	limit := uint64(options.Limit)
+	if limit == 0 || limit > defaultKLineLimit {
+		limit = defaultKLineLimit
+	}
+
+	req := e.client.NewGetCandlesticksRequest().CurrencyPair(toLocalSymbol(symbol)).Interval(localInterval)
+	window := time.Duration(limit-1) * interval.Duration()
+	switch {
+	case options.StartTime != nil:
+		to := options.StartTime.Add(window)
+		if options.EndTime != nil && options.EndTime.Before(to) {
+			to = *options.EndTime
+		}
+		req.From(*options.StartTime).To(to)
+
+	case options.EndTime != nil:
+		req.From(options.EndTime.Add(-window)).To(*options.EndTime)
+
+	default:
+		req.Limit(limit)
+	}
+
+	candlesticks, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines, symbol: %s, err: %w", symbol, err)
+	}
+
+	kLines := make([]types.KLine, 0, len(candlesticks))
+	for _, c := range candlesticks {
+		kLines = append(kLines, toGlobalKLine(symbol, interval, c))
+	}
+
+	// the candlesticks are in ascending order
+	return kLines, nil
+}
+
+func (e *Exchange) SupportedInterval() map[types.Interval]int {
+	return supportedIntervals
+}
+
+func (e *Exchange) IsSupportedInterval(interval types.Interval) bool {
+	_, ok := supportedIntervals[interval]
+	return ok
+}
+
+func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	balances, err := e.QueryAccountBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	account := types.NewAccount()
+	account.AccountType = types.AccountTypeSpot
+	account.UpdateBalances(balances)
+	return account, nil
+}
+
+func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap, error) {
+	if err := accountRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("account rate limiter wait error: %w", err)
+	}
+
+	accounts, err := e.client.NewGetSpotAccountsRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spot accounts: %w", err)
+	}
+
+	return toGlobalBalanceMap(accounts), nil
+}
+
+func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (createdOrder *types.Order, err error) {
+	if len(order.ClientOrderID) > maxClientOrderIdLen {
+		return nil, fmt.Errorf("unexpected length of client order id, got: %d", len(order.ClientOrderID))
+	}
+
+	side, err := toLocalSide(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	req := e.client.NewPlaceOrderRequest().
+		CurrencyPair(toLocalSymbol(order.Symbol)).
+		Side(side)
+
+	if len(order.ClientOrderID) > 0 {
+		req.Text(clientOrderIdPrefix + order.ClientOrderID)
+	}
+
+	switch order.Type {
+	case types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		tif, err := toLocalTimeInForce(order.Type, order.TimeInForce)
+		if err != nil {
+			return nil, err
+		}
+
+		req.OrderType(gateioapi.OrderTypeLimit).
+			TimeInForce(tif).
+			Price(order.Market.FormatPrice(order.Price)).
+			Amount(order.Market.FormatQuantity(order.Quantity))
+
+	case types.OrderTypeMarket:
+		amount := order.Market.FormatQuantity(order.Quantity)
+		// the amount of the market buy orders is in the quote currency
+		if order.Side == types.SideTypeBuy {
+			price := order.Price
+			if price.IsZero() {
+				ticker, err := e.QueryTicker(ctx, order.Symbol)
+				if err != nil {
+					return nil, err
+				}
+				price = ticker.Sell
+			}
+			amount = order.Market.FormatPrice(order.Quantity.Mul(price))
+		}
+
+		req.OrderType(gateioapi.OrderTypeMarket).
+			TimeInForce(gateioapi.TimeInForceIOC).
+			Amount(amount)
+
+	default:
+		return nil, fmt.Errorf("order type %s not supported", order.Type)
+	}
+
+	if err := orderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("place order rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order, order: %#v, err: %w", order, err)
+	}
+
+	return toGlobalOrder(*res)
+}
+
+func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
+	for page := uint64(1); ; page++ {
+		if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("open orders rate limiter wait error: %w", err)
+		}
+
+		res, err := e.client.NewGetOrdersRequest().
+			CurrencyPair(toLocalSymbol(symbol)).
+			Status(gateioapi.OrderQueryStatusOpen).
+			Page(page).
+			Limit(maxOrderQueryLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query open orders, symbol: %s, err: %w", symbol, err)
+		}
+
+		for _, o := range res {
+			order, err := toGlobalOrder(o)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert order, err: %w", err)
+			}
+			orders = append(orders, *order)
+		}
+
+		if len(res) < maxOrderQueryLimit {
+			break
+		}
+	}
+
+	return orders, nil
+}
+
+func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) (errs error) {
+	for _, order := range orders {
+		orderId, err := toLocalOrderId(order.UUID, order.OrderID, order.ClientOrderID)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+
+		if err := orderRateLimiter.Wait(ctx); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("cancel order rate limiter wait, order id: %s, error: %w", orderId, err))
+			continue
+		}
+
+		_, err = e.client.NewCancelOrderRequest().
+			OrderId(orderId).
+			CurrencyPair(toLocalSymbol(order.Symbol)).
+			Do(ctx)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to cancel order, order id: %s, err: %w", orderId, err))
+		}
+	}
+
+	return errs
+}
+
+// toLocalOrderId returns the order id, the user defined text can also be used as the order id.
+func toLocalOrderId(uuid string, orderId uint64, clientOrderId string) (string, error) {
+	switch {
+	case len(uuid) > 0:
+		return uuid, nil
+	case orderId > 0:
+		return strconv.FormatUint(orderId, 10), nil
+	case len(clientOrderId) > 0:
+		return clientOrderIdPrefix + clientOrderId, nil
+	default:
+		return "", errors.New("the order uuid, order id and client order id are all empty")
+	}
+}
+
+func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.Order, error) {
+	orderId, err := toLocalOrderId("", 0, q.ClientOrderID)
+	if len(q.OrderID) > 0 {
+		orderId, err = q.OrderID, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query order rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetOrderRequest().
+		OrderId(orderId).
+		CurrencyPair(toLocalSymbol(q.Symbol)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order, query: %+v, err: %w", q, err)
+	}
+
+	return toGlobalOrder(*res)
+}
+
+func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) ([]types.Trade, error) {
+	if len(q.OrderID) == 0 {
+		return nil, errors.New("order id is required")
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("order trades rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetMyTradesRequest().
+		CurrencyPair(toLocalSymbol(q.Symbol)).
+		OrderId(q.OrderID).
+		Limit(maxTradeQueryLimit).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order trades, query: %+v, err: %w", q, err)
+	}
+
+	return toGlobalTrades(res)
+}
+
+func toGlobalTrades(trades []gateioapi.Trade) ([]types.Trade, error) {
+	var errs error
+	res := make([]types.Trade, 0, len(trades))
+	for _, t := range trades {
+		trade, err := toGlobalTrade(t)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		res = append(res, *trade)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time.Time())
+	})
+	return res, errs
+}
+
+/*
+QueryClosedOrders queries the finished orders in the time range. The time range is limited to 30 days.
+
+** The lastOrderID is only used to filter the orders, the server doesn't support querying the orders by the order id. **
+*/
+func (e *Exchange) QueryClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) (orders []types.Order, err error) {
+	if until.Sub(since) > maxHistoryQueryWindow {
+		until = since.Add(maxHistoryQueryWindow)
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("closed orders rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewGetOrdersRequest().
+		CurrencyPair(toLocalSymbol(symbol)).
+		Status(gateioapi.OrderQueryStatusFinished).
+		From(since).
+		To(until).
+		Limit(maxOrderQueryLimit).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed orders, symbol: %s, err: %w", symbol, err)
+	}
+
+	for _, o := range res {
+		order, err := toGlobalOrder(o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert order, err: %w", err)
+		}
+
+		if order.OrderID <= lastOrderID {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreationTime.Before(orders[j].CreationTime.Time())
+	})
+	return orders, nil
+}
+
+/*
+QueryTrades queries the trades in the time range, the trades of the last 7 days are returned if the start time is not
+specified. The time range is limited to 30 days.
+
+** The lastTradeId is only used to filter the trades, the server doesn't support querying the trades by the trade id. **
+*/
+func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *types.TradeQueryOptions) ([]types.Trade, error) {
+	req := e.client.NewGetMyTradesRequest().CurrencyPair(toLocalSymbol(symbol))
+
+	limit := uint64(options.Limit)
+	if limit == 0 || limit > maxTradeQueryLimit {
+		limit = maxTradeQueryLimit
+	}
+	req.Limit(limit)
+
+	if options.StartTime != nil {
+		req.From(*options.StartTime)
+
+		endTime := options.StartTime.Add(maxHistoryQueryWindow)
+		if options.EndTime != nil && options.EndTime.Before(endTime) {
+			endTime = *options.EndTime
+		}
+		req.To(endTime)
+	} else if options.EndTime != nil {
+		req.From(options.EndTime.Add(-maxHistoryQueryWindow)).To(*options.EndTime)
+	}
+
+	if err := queryOrderRateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("trades rate limiter wait error: %w", err)
+	}
+
+	res, err := req.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades, symbol: %s, err: %w", symbol, err)
+	}
+
+	trades, err := toGlobalTrades(res)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.LastTradeID == 0 {
+		return trades, nil
+	}
+
+	filtered := trades[:0]
+	for _, t := range trades {
+		if t.ID > options.LastTradeID {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, nil
+}
+
+func (e *Exchange) QueryDepositHistory(ctx context.Context, asset string, since, until time.Time) (allDeposits []types.Deposit, err error) {
+	for startTime := since; startTime.Before(until); startTime = startTime.Add(maxHistoryQueryWindow) {
+		endTime := startTime.Add(maxHistoryQueryWindow)
+		if endTime.After(until) {
+			endTime = until
+		}
+
+		for offset := uint64(0); ; offset += maxWalletQueryLimit {
+			if err := accountRateLimiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("deposit history rate limiter wait error: %w", err)
+			}
+
+			req := e.client.NewGetDepositsRequest().
+				From(startTime).
+				To(endTime).
+				Limit(maxWalletQueryLimit).
+				Offset(offset)
+			if len(asset) > 0 {
+				req.Currency(asset)
+			}
+
+			deposits, err := req.Do(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query deposit history, asset: %s, err: %w", asset, err)
+			}
+
+			for _, d := range deposits {
+				allDeposits = append(allDeposits, toGlobalDeposit(d))
+			}
+
+			if len(deposits) < maxWalletQueryLimit {
+				break
+			}
+		}
+	}
+
+	sort.Slice(allDeposits, func(i, j int) bool {
+		return allDeposits[i].Time.Before(allDeposits[j].Time.Time())
+	})
+	return allDeposits, nil
+}
+
+func (e *Exchange) QueryWithdrawHistory(ctx context.Context, asset string, since, until time.Time) (allWithdraws []types.Withdraw, err error) {
+	for startTime := since; startTime.Before(until); startTime = startTime.Add(maxHistoryQueryWindow) {
+		endTime := startTime.Add(maxHistoryQueryWindow)
+		if endTime.After(until) {
+			endTime = until
+		}
+
+		for offset := uint64(0); ; offset += maxWalletQueryLimit {
+			if err := accountRateLimiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("withdraw history rate limiter wait error: %w", err)
+			}
+
+			req := e.client.NewGetWithdrawalsRequest().
+				From(startTime).
+				To(endTime).
+				Limit(maxWalletQueryLimit).
+				Offset(offset)
+			if len(asset) > 0 {
+				req.Currency(asset)
+			}
+
+			withdraws, err := req.Do(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query withdraw history, asset: %s, err: %w", asset, err)
+			}
+
+			for _, w := range withdraws {
+				allWithdraws = append(allWithdraws, toGlobalWithdraw(w))
+			}
+
+			if len(withdraws) < maxWalletQueryLimit {
+				break
+			}
+		}
+	}
+
+	sort.Slice(allWithdraws, func(i, j int) bool {
+		return allWithdraws[i].ApplyTime.Before(allWithdraws[j].ApplyTime.Time())
+	})
+	return allWithdraws, nil
+}
+
+// DefaultFeeRates returns the default fee rates of the VIP 0 users.
+func (e *Exchange) DefaultFeeRates() types.ExchangeFee {
+	return types.ExchangeFee{
+		MakerFeeRate: fixedpoint.NewFromFloat(0.01 * 0.2), // 0.2%
+		TakerFeeRate: fixedpoint.NewFromFloat(0.01 * 0.2), // 0.2%
+	}
+}
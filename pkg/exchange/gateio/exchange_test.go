@@ -0,0 +1,453 @@
+package gateio
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	currencyPairsUrl = "/api/v4/spot/currency_pairs"
+	tickersUrl       = "/api/v4/spot/tickers"
+	candlesticksUrl  = "/api/v4/spot/candlesticks"
+	accountsUrl      = "/api/v4/spot/accounts"
+	ordersUrl        = "/api/v4/spot/orders"
+	myTradesUrl      = "/api/v4/spot/my_trades"
+	depositsUrl      = "/api/v4/wallet/deposits"
+	withdrawalsUrl   = "/api/v4/wallet/withdrawals"
+)
+
+func newTestExchange(t *testing.T) (*Exchange, *httptesting.MockTransport) {
+	ex := New("key", "secret")
+	transport := &httptesting.MockTransport{}
+	ex.client.HttpClient.Transport = transport
+	return ex, transport
+}
+
+func readFixture(t *testing.T, name string) string {
+	content, err := os.ReadFile("gateioapi/testdata/" + name)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func assertAuthenticated(t *testing.T, req *http.Request) {
+	assert.Equal(t, "key", req.Header.Get("KEY"))
+	assert.NotEmpty(t, req.Header.Get("SIGN"))
+	assert.NotEmpty(t, req.Header.Get("Timestamp"))
+}
+
+func TestExchange_QueryMarkets(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_currency_pairs_request.json")
+	transport.GET(currencyPairsUrl, func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	markets, err := ex.QueryMarkets(context.Background())
+	assert.NoError(t, err)
+
+	// the untradable pairs are skipped
+	assert.Len(t, markets, 1)
+	assert.Equal(t, types.Market{
+		Exchange:        types.ExchangeGateIO,
+		Symbol:          "BTCUSDT",
+		LocalSymbol:     "BTC_USDT",
+		PricePrecision:  1,
+		VolumePrecision: 6,
+		QuoteCurrency:   "USDT",
+		BaseCurrency:    "BTC",
+		MinNotional:     fixedpoint.NewFromFloat(3),
+		MinAmount:       fixedpoint.NewFromFloat(3),
+		MinQuantity:     fixedpoint.MustNewFromString("0.00001"),
+		MaxQuantity:     fixedpoint.Zero,
+		StepSize:        fixedpoint.MustNewFromString("0.000001"),
+		MinPrice:        fixedpoint.MustNewFromString("0.1"),
+		TickSize:        fixedpoint.MustNewFromString("0.1"),
+	}, markets["BTCUSDT"])
+}
+
+func TestExchange_QueryTicker(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_tickers_request.json")
+	transport.GET(tickersUrl, func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "BTC_USDT", req.URL.Query().Get("currency_pair"))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	ticker, err := ex.QueryTicker(context.Background(), "BTCUSDT")
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.MustNewFromString("66051.2"), ticker.Last)
+	assert.Equal(t, fixedpoint.MustNewFromString("66051.2"), ticker.Buy)
+	assert.Equal(t, fixedpoint.MustNewFromString("66051.3"), ticker.Sell)
+	assert.Equal(t, fixedpoint.MustNewFromString("66500"), ticker.High)
+	assert.Equal(t, fixedpoint.MustNewFromString("64550.1"), ticker.Low)
+	assert.Equal(t, fixedpoint.MustNewFromString("5129.938251"), ticker.Volume)
+	assert.InDelta(t, 65011.02, ticker.Open.Float64(), 0.01)
+}
+
+func TestExchange_QueryKLines(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_candlesticks_request.json")
+	startTime := time.Unix(1717027200, 0)
+	transport.GET(candlesticksUrl, func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "BTC_USDT", query.Get("currency_pair"))
+		assert.Equal(t, "1m", query.Get("interval"))
+		assert.Equal(t, "1717027200", query.Get("from"))
+		assert.Equal(t, "1717027260", query.Get("to"))
+		// limit is conflicted with from and to
+		assert.NotContains(t, query, "limit")
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	kLines, err := ex.QueryKLines(context.Background(), "BTCUSDT", types.Interval1m, types.KLineQueryOptions{
+		StartTime: &startTime,
+		Limit:     2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, kLines, 2)
+	assert.Equal(t, types.KLine{
+		Exchange:    types.ExchangeGateIO,
+		Symbol:      "BTCUSDT",
+		StartTime:   types.Time(startTime),
+		EndTime:     types.Time(startTime.Add(time.Minute - time.Millisecond)),
+		Interval:    types.Interval1m,
+		Open:        fixedpoint.MustNewFromString("67800.1"),
+		Close:       fixedpoint.MustNewFromString("67801.9"),
+		High:        fixedpoint.MustNewFromString("67860.1"),
+		Low:         fixedpoint.MustNewFromString("67748.3"),
+		Volume:      fixedpoint.MustNewFromString("18.550531"),
+		QuoteVolume: fixedpoint.MustNewFromString("1257866.52631"),
+		Closed:      true,
+	}, kLines[0])
+	assert.False(t, kLines[1].Closed)
+
+	_, err = ex.QueryKLines(context.Background(), "BTCUSDT", types.Interval2h, types.KLineQueryOptions{})
+	assert.ErrorContains(t, err, "unsupported interval")
+}
+
+func TestExchange_QueryAccountBalances(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_spot_accounts_request.json")
+	transport.GET(accountsUrl, func(req *http.Request) (*http.Response, error) {
+		assertAuthenticated(t, req)
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	balances, err := ex.QueryAccountBalances(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, types.BalanceMap{
+		"USDT": {
+			Currency:  "USDT",
+			Available: fixedpoint.MustNewFromString("1032.45"),
+			Locked:    fixedpoint.NewFromFloat(100),
+		},
+		"BTC": {
+			Currency:  "BTC",
+			Available: fixedpoint.MustNewFromString("0.0153"),
+			Locked:    fixedpoint.Zero,
+		},
+	}, balances)
+}
+
+func TestExchange_SubmitOrder(t *testing.T) {
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		LocalSymbol:     "BTC_USDT",
+		PricePrecision:  1,
+		VolumePrecision: 6,
+		StepSize:        fixedpoint.MustNewFromString("0.000001"),
+		TickSize:        fixedpoint.MustNewFromString("0.1"),
+	}
+
+	t.Run("limit order", func(t *testing.T) {
+		ex, transport := newTestExchange(t)
+		file := readFixture(t, "place_order_request.json")
+		transport.POST(ordersUrl, func(req *http.Request) (*http.Response, error) {
+			assertAuthenticated(t, req)
+
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+
+			params := map[string]string{}
+			assert.NoError(t, json.Unmarshal(body, &params))
+			assert.Equal(t, map[string]string{
+				"text":          "t-1701853526",
+				"currency_pair": "BTC_USDT",
+				"type":          "limit",
+				"account":       "spot",
+				"side":          "buy",
+				"amount":        "0.001000",
+				"price":         "60000.0",
+				"time_in_force": "gtc",
+			}, params)
+			return httptesting.BuildResponseString(http.StatusCreated, file), nil
+		})
+
+		order, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+			ClientOrderID: "1701853526",
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			Type:          types.OrderTypeLimit,
+			Quantity:      fixedpoint.MustNewFromString("0.001"),
+			Price:         fixedpoint.NewFromFloat(60000),
+			Market:        market,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Order{
+			SubmitOrder: types.SubmitOrder{
+				ClientOrderID: "1701853526",
+				Symbol:        "BTCUSDT",
+				Side:          types.SideTypeBuy,
+				Type:          types.OrderTypeLimit,
+				Quantity:      fixedpoint.MustNewFromString("0.001"),
+				Price:         fixedpoint.NewFromFloat(60000),
+				TimeInForce:   types.TimeInForceGTC,
+			},
+			Exchange:         types.ExchangeGateIO,
+			OrderID:          591423987437,
+			UUID:             "591423987437",
+			Status:           types.OrderStatusNew,
+			ExecutedQuantity: fixedpoint.Zero,
+			IsWorking:        true,
+			CreationTime:     types.Time(types.NewMillisecondTimestampFromInt(1717031187321).Time()),
+			UpdateTime:       types.Time(types.NewMillisecondTimestampFromInt(1717031187321).Time()),
+		}, order)
+	})
+
+	t.Run("market buy order uses the quote amount", func(t *testing.T) {
+		ex, transport := newTestExchange(t)
+		file := readFixture(t, "place_order_request.json")
+		transport.POST(ordersUrl, func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+
+			params := map[string]string{}
+			assert.NoError(t, json.Unmarshal(body, &params))
+			assert.Equal(t, "market", params["type"])
+			assert.Equal(t, "ioc", params["time_in_force"])
+			assert.Equal(t, "60.0", params["amount"])
+			assert.NotContains(t, params, "price")
+			return httptesting.BuildResponseString(http.StatusCreated, file), nil
+		})
+
+		_, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeMarket,
+			Quantity: fixedpoint.MustNewFromString("0.001"),
+			Price:    fixedpoint.NewFromFloat(60000),
+			Market:   market,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		ex, transport := newTestExchange(t)
+		transport.POST(ordersUrl, func(req *http.Request) (*http.Response, error) {
+			return httptesting.BuildResponseString(http.StatusBadRequest, `{"label":"BALANCE_NOT_ENOUGH","message":"Not enough balance"}`), nil
+		})
+
+		_, err := ex.SubmitOrder(context.Background(), types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: fixedpoint.MustNewFromString("0.001"),
+			Price:    fixedpoint.NewFromFloat(60000),
+			Market:   market,
+		})
+		assert.ErrorContains(t, err, "BALANCE_NOT_ENOUGH")
+	})
+}
+
+func TestExchange_CancelOrders(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "place_order_request.json")
+	transport.DELETE(ordersUrl+"/591423987437", func(req *http.Request) (*http.Response, error) {
+		assertAuthenticated(t, req)
+		assert.Equal(t, "BTC_USDT", req.URL.Query().Get("currency_pair"))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+	transport.DELETE(ordersUrl+"/t-1701853526", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	err := ex.CancelOrders(context.Background(),
+		types.Order{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT"}, OrderID: 591423987437},
+		types.Order{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT", ClientOrderID: "1701853526"}},
+	)
+	assert.NoError(t, err)
+
+	err = ex.CancelOrders(context.Background(), types.Order{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT"}})
+	assert.Error(t, err)
+}
+
+func TestExchange_QueryClosedOrders(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_orders_request.json")
+	since := time.Unix(1717000000, 0)
+	until := since.Add(time.Hour)
+	transport.GET(ordersUrl, func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "BTC_USDT", query.Get("currency_pair"))
+		assert.Equal(t, "finished", query.Get("status"))
+		assert.Equal(t, strconv.FormatInt(since.Unix(), 10), query.Get("from"))
+		assert.Equal(t, strconv.FormatInt(until.Unix(), 10), query.Get("to"))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	orders, err := ex.QueryClosedOrders(context.Background(), "BTCUSDT", since, until, 0)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+
+	// ascending order by creation time
+	marketOrder, limitOrder := orders[0], orders[1]
+	assert.Equal(t, uint64(591423880012), marketOrder.OrderID)
+	assert.Equal(t, types.OrderTypeMarket, marketOrder.Type)
+	assert.Equal(t, types.OrderStatusFilled, marketOrder.Status)
+	assert.Equal(t, fixedpoint.MustNewFromString("0.001"), marketOrder.Quantity)
+	assert.Equal(t, fixedpoint.MustNewFromString("65999.9984"), marketOrder.Price)
+	assert.Equal(t, "apiv4", marketOrder.ClientOrderID)
+
+	assert.Equal(t, uint64(591423987437), limitOrder.OrderID)
+	assert.Equal(t, types.OrderStatusFilled, limitOrder.Status)
+	assert.Equal(t, "1701853526", limitOrder.ClientOrderID)
+	assert.False(t, limitOrder.IsWorking)
+
+	orders, err = ex.QueryClosedOrders(context.Background(), "BTCUSDT", since, until, 591423880012)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+}
+
+func TestExchange_QueryTrades(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_my_trades_request.json")
+	since := time.Unix(1717000000, 0)
+	transport.GET(myTradesUrl, func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "BTC_USDT", query.Get("currency_pair"))
+		assert.Equal(t, "1000", query.Get("limit"))
+		assert.Equal(t, strconv.FormatInt(since.Unix(), 10), query.Get("from"))
+		assert.Equal(t, strconv.FormatInt(since.Add(maxHistoryQueryWindow).Unix(), 10), query.Get("to"))
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	trades, err := ex.QueryTrades(context.Background(), "BTCUSDT", &types.TradeQueryOptions{StartTime: &since})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Trade{
+		{
+			ID:            9151370011,
+			OrderID:       591423880012,
+			Exchange:      types.ExchangeGateIO,
+			Price:         fixedpoint.MustNewFromString("65999.9984"),
+			Quantity:      fixedpoint.MustNewFromString("0.001"),
+			QuoteQuantity: fixedpoint.MustNewFromString("65.9999984"),
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			IsMaker:       false,
+			Time:          types.Time(types.NewMillisecondTimestampFromInt(1717031100425).Time()),
+			// the fee is deducted by GT
+			Fee:         fixedpoint.MustNewFromString("0.0098"),
+			FeeCurrency: "GT",
+		},
+		{
+			ID:            9151380912,
+			OrderID:       591423987437,
+			Exchange:      types.ExchangeGateIO,
+			Price:         fixedpoint.NewFromFloat(60000),
+			Quantity:      fixedpoint.MustNewFromString("0.001"),
+			QuoteQuantity: fixedpoint.NewFromFloat(60),
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			IsMaker:       true,
+			Time:          types.Time(types.NewMillisecondTimestampFromInt(1717031240118).Time()),
+			Fee:           fixedpoint.MustNewFromString("0.000002"),
+			FeeCurrency:   "BTC",
+		},
+	}, trades)
+
+	trades, err = ex.QueryTrades(context.Background(), "BTCUSDT", &types.TradeQueryOptions{StartTime: &since, LastTradeID: 9151370011})
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+}
+
+func TestExchange_QueryDepositHistory(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_deposits_request.json")
+	until := time.Unix(1717000000, 0)
+	since := until.Add(-45 * 24 * time.Hour)
+
+	var windows [][2]string
+	transport.GET(depositsUrl, func(req *http.Request) (*http.Response, error) {
+		assertAuthenticated(t, req)
+		query := req.URL.Query()
+		assert.Equal(t, "USDT", query.Get("currency"))
+		windows = append(windows, [2]string{query.Get("from"), query.Get("to")})
+		if len(windows) > 1 {
+			return httptesting.BuildResponseString(http.StatusOK, "[]"), nil
+		}
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	deposits, err := ex.QueryDepositHistory(context.Background(), "USDT", since, until)
+	assert.NoError(t, err)
+
+	// the time range is split into the 30 days windows
+	assert.Equal(t, [][2]string{
+		{strconv.FormatInt(since.Unix(), 10), strconv.FormatInt(since.Add(maxHistoryQueryWindow).Unix(), 10)},
+		{strconv.FormatInt(since.Add(maxHistoryQueryWindow).Unix(), 10), strconv.FormatInt(until.Unix(), 10)},
+	}, windows)
+	assert.Equal(t, []types.Deposit{
+		{
+			Exchange:      types.ExchangeGateIO,
+			Time:          types.Time(time.Unix(1716812160, 0)),
+			Amount:        fixedpoint.NewFromFloat(1000),
+			Asset:         "USDT",
+			Address:       "0x1f9840a85d5af5bf1d1762f925bdaddc4201f984",
+			TransactionID: "0x2a1a4ef6f6fb0b1b5b3d14a2a5b4bd1a7d2d4b04e8f3d1b1a9d2e0f4f1c5a6b7",
+			Status:        types.DepositSuccess,
+		},
+	}, deposits)
+}
+
+func TestExchange_QueryWithdrawHistory(t *testing.T) {
+	ex, transport := newTestExchange(t)
+	file := readFixture(t, "get_withdrawals_request.json")
+	until := time.Unix(1717000000, 0)
+	since := until.Add(-7 * 24 * time.Hour)
+	transport.GET(withdrawalsUrl, func(req *http.Request) (*http.Response, error) {
+		assertAuthenticated(t, req)
+		return httptesting.BuildResponseString(http.StatusOK, file), nil
+	})
+
+	withdraws, err := ex.QueryWithdrawHistory(context.Background(), "", since, until)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Withdraw{
+		{
+			Exchange:               types.ExchangeGateIO,
+			Asset:                  "USDT",
+			Amount:                 fixedpoint.NewFromFloat(500),
+			Address:                "TXmVpin5vq5gdZsciyyjdZgKRUju4st1wM",
+			Status:                 "completed",
+			TransactionID:          "1a3f6d4c9b2e8f7a6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3e",
+			TransactionFee:         fixedpoint.One,
+			TransactionFeeCurrency: "USDT",
+			WithdrawOrderID:        "w13389675",
+			ApplyTime:              types.Time(time.Unix(1716898560, 0)),
+			Network:                "TRX",
+		},
+	}, withdraws)
+}
@@ -0,0 +1,22 @@
+package gateioapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+//go:generate DeleteRequest -url "/spot/orders/:orderId" -type CancelOrderRequest -responseType .Order
+type CancelOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	// orderId is the order id or the user defined text (prefixed with `t-`) of the order.
+	orderId      string `param:"orderId,slug,required"`
+	currencyPair string `param:"currency_pair,query"`
+}
+
+func (c *RestClient) NewCancelOrderRequest() *CancelOrderRequest {
+	return &CancelOrderRequest{client: c}
+}
@@ -0,0 +1,183 @@
+package gateioapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/c9s/requestgen"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultHTTPTimeout = time.Second * 15
+
+	RestBaseURL  = "https://api.gateio.ws/api/v4"
+	WebSocketURL = "wss://api.gateio.ws/ws/v4/"
+)
+
+type RestClient struct {
+	requestgen.BaseAPIClient
+
+	key, secret string
+}
+
+func NewClient() *RestClient {
+	u, err := url.Parse(RestBaseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	return &RestClient{
+		BaseAPIClient: requestgen.BaseAPIClient{
+			BaseURL: u,
+			HttpClient: &http.Client{
+				Timeout: defaultHTTPTimeout,
+			},
+		},
+	}
+}
+
+func (c *RestClient) Auth(key, secret string) {
+	c.key = key
+	// pragma: allowlist secret
+	c.secret = secret
+}
+
+// NewRequest creates new http request for the public routes. Gate.io's base url has a path prefix (/api/v4), so the
+// relative url is joined to the base path instead of being resolved against it.
+func (c *RestClient) NewRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	body, err := castPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	pathURL := c.resolveURL(refURL, params)
+	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	return req, nil
+}
+
+// NewAuthenticatedRequest creates new http request for authenticated routes.
+func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL string, params url.Values, payload interface{}) (*http.Request, error) {
+	if len(c.key) == 0 {
+		return nil, errors.New("empty api key")
+	}
+
+	if len(c.secret) == 0 {
+		return nil, errors.New("empty api secret")
+	}
+
+	body, err := castPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	pathURL := c.resolveURL(refURL, params)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	// See https://www.gate.io/docs/developers/apiv4/#apiv4-signed-request-requirements
+	//
+	// Method + "\n" + URL path + "\n" + query string + "\n" + HexEncode(SHA512(request body)) + "\n" + timestamp
+	bodyHash := sha512.Sum512(body)
+	signKey := method + "\n" +
+		pathURL.Path + "\n" +
+		pathURL.RawQuery + "\n" +
+		hex.EncodeToString(bodyHash[:]) + "\n" +
+		timestamp
+
+	req, err := http.NewRequestWithContext(ctx, method, pathURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("KEY", c.key)
+	req.Header.Add("Timestamp", timestamp)
+	req.Header.Add("SIGN", Sign(signKey, c.secret))
+	return req, nil
+}
+
+// SendRequest sends the request and converts the non-2xx responses into the APIError.
+func (c *RestClient) SendRequest(req *http.Request) (*requestgen.Response, error) {
+	resp, err := c.BaseAPIClient.SendRequest(req)
+	if err != nil {
+		if resp != nil && len(resp.Body) > 0 {
+			var apiErr APIError
+			if jsonErr := json.Unmarshal(resp.Body, &apiErr); jsonErr == nil && apiErr.Label != "" {
+				return resp, &apiErr
+			}
+		}
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (c *RestClient) resolveURL(refURL string, params url.Values) *url.URL {
+	u := *c.BaseURL
+	u.Path = c.BaseURL.Path + refURL
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+	return &u
+}
+
+func Sign(payload string, secret string) string {
+	var sig = hmac.New(sha512.New, []byte(secret))
+	_, err := sig.Write([]byte(payload))
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(sig.Sum(nil))
+}
+
+func castPayload(payload interface{}) ([]byte, error) {
+	if payload == nil {
+		return nil, nil
+	}
+
+	switch v := payload.(type) {
+	case string:
+		return []byte(v), nil
+
+	case []byte:
+		return v, nil
+
+	}
+	return json.Marshal(payload)
+}
+
+/*
+sample:
+
+{
+  "label": "INVALID_PARAM_VALUE",
+  "message": "Invalid currency_pair"
+}
+*/
+
+// APIError is returned by the server with a non-2xx status code, the response of the successful requests is the data
+// itself without any envelope.
+type APIError struct {
+	Label   string `json:"label"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("label: %s, message: %s", e.Label, e.Message)
+}
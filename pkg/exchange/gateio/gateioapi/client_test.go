@@ -0,0 +1,31 @@
+package gateioapi
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestClient_NewAuthenticatedRequest(t *testing.T) {
+	client := NewClient()
+	client.Auth("key", "secret")
+
+	params := url.Values{}
+	params.Set("currency_pair", "BTC_USDT")
+	req, err := client.NewAuthenticatedRequest(context.Background(), http.MethodGet, "/spot/orders", params, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.gateio.ws/api/v4/spot/orders?currency_pair=BTC_USDT", req.URL.String())
+	assert.Equal(t, "key", req.Header.Get("KEY"))
+
+	emptyBodyHash := sha512.Sum512(nil)
+	signKey := "GET\n/api/v4/spot/orders\ncurrency_pair=BTC_USDT\n" + hex.EncodeToString(emptyBodyHash[:]) + "\n" + req.Header.Get("Timestamp")
+	assert.Equal(t, Sign(signKey, "secret"), req.Header.Get("SIGN"))
+
+	_, err = NewClient().NewAuthenticatedRequest(context.Background(), http.MethodGet, "/spot/orders", nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package gateioapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type CandlestickInterval string
+
+const (
+	CandlestickInterval10s CandlestickInterval = "10s"
+	CandlestickInterval1m  CandlestickInterval = "1m"
+	CandlestickInterval5m  CandlestickInterval = "5m"
+	CandlestickInterval15m CandlestickInterval = "15m"
+	CandlestickInterval30m CandlestickInterval = "30m"
+	CandlestickInterval1h  CandlestickInterval = "1h"
+	CandlestickInterval4h  CandlestickInterval = "4h"
+	CandlestickInterval8h  CandlestickInterval = "8h"
+	CandlestickInterval1d  CandlestickInterval = "1d"
+	CandlestickInterval7d  CandlestickInterval = "7d"
+	CandlestickInterval30d CandlestickInterval = "30d"
+)
+
+// Candlestick is parsed from the array:
+//
+//	[
+//	  "1539852480",  // unix timestamp with second precision
+//	  "971519.677",  // trading volume in quote currency
+//	  "0.0021724",   // closing price
+//	  "0.0021922",   // highest price
+//	  "0.0021724",   // lowest price
+//	  "0.0021737",   // opening price
+//	  "445.6",       // trading volume in base currency
+//	  "true"         // whether the window is closed
+//	]
+type Candlestick struct {
+	StartTime    types.MillisecondTimestamp
+	QuoteVolume  fixedpoint.Value
+	Close        fixedpoint.Value
+	High         fixedpoint.Value
+	Low          fixedpoint.Value
+	Open         fixedpoint.Value
+	BaseVolume   fixedpoint.Value
+	WindowClosed bool
+}
+
+func (c *Candlestick) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw) < 7 {
+		return fmt.Errorf("unexpected candlestick length: %d, data: %q", len(raw), data)
+	}
+
+	fields := []interface{}{&c.StartTime, &c.QuoteVolume, &c.Close, &c.High, &c.Low, &c.Open, &c.BaseVolume}
+	for i, f := range fields {
+		if err := json.Unmarshal(raw[i], f); err != nil {
+			return fmt.Errorf("failed to parse candlestick field %d: %w", i, err)
+		}
+	}
+
+	if len(raw) > 7 {
+		var closed string
+		if err := json.Unmarshal(raw[7], &closed); err != nil {
+			return fmt.Errorf("failed to parse candlestick window closed: %w", err)
+		}
+		c.WindowClosed = closed == "true"
+	}
+
+	return nil
+}
+
+//go:generate GetRequest -url "/spot/candlesticks" -type GetCandlesticksRequest -responseType []Candlestick
+type GetCandlesticksRequest struct {
+	client requestgen.APIClient
+
+	currencyPair string              `param:"currency_pair,query"`
+	interval     CandlestickInterval `param:"interval,query"`
+
+	// limit is the maximum number of records to be returned, the max value is 1000.
+	// It's conflicted with from and to.
+	limit *uint64 `param:"limit,query"`
+
+	from *time.Time `param:"from,query,seconds"`
+	to   *time.Time `param:"to,query,seconds"`
+}
+
+func (c *RestClient) NewGetCandlesticksRequest() *GetCandlesticksRequest {
+	return &GetCandlesticksRequest{client: c}
+}
@@ -0,0 +1,36 @@
+package gateioapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type CurrencyPair struct {
+	Id              string           `json:"id"`
+	Base            string           `json:"base"`
+	Quote           string           `json:"quote"`
+	Fee             fixedpoint.Value `json:"fee"`
+	MinBaseAmount   fixedpoint.Value `json:"min_base_amount"`
+	MinQuoteAmount  fixedpoint.Value `json:"min_quote_amount"`
+	MaxBaseAmount   fixedpoint.Value `json:"max_base_amount"`
+	MaxQuoteAmount  fixedpoint.Value `json:"max_quote_amount"`
+	AmountPrecision int              `json:"amount_precision"`
+	Precision       int              `json:"precision"`
+	TradeStatus     TradeStatus      `json:"trade_status"`
+	SellStart       int64            `json:"sell_start"`
+	BuyStart        int64            `json:"buy_start"`
+}
+
+//go:generate GetRequest -url "/spot/currency_pairs" -type GetCurrencyPairsRequest -responseType []CurrencyPair
+type GetCurrencyPairsRequest struct {
+	client requestgen.APIClient
+}
+
+func (c *RestClient) NewGetCurrencyPairsRequest() *GetCurrencyPairsRequest {
+	return &GetCurrencyPairsRequest{client: c}
+}
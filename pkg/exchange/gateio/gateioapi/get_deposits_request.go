@@ -0,0 +1,45 @@
+package gateioapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type Deposit struct {
+	Id        string                     `json:"id"`
+	Txid      string                     `json:"txid"`
+	Timestamp types.MillisecondTimestamp `json:"timestamp"`
+	Amount    fixedpoint.Value           `json:"amount"`
+	Currency  string                     `json:"currency"`
+	Address   string                     `json:"address"`
+	Memo      string                     `json:"memo"`
+	Status    DepositStatus              `json:"status"`
+	Chain     string                     `json:"chain"`
+}
+
+//go:generate GetRequest -url "/wallet/deposits" -type GetDepositsRequest -responseType []Deposit
+type GetDepositsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currency *string `param:"currency,query"`
+
+	// the maximum range of from and to is 30 days, the last 7 days are returned by default.
+	from *time.Time `param:"from,query,seconds"`
+	to   *time.Time `param:"to,query,seconds"`
+
+	// limit is the maximum number of records to be returned, the max value is 500.
+	limit  *uint64 `param:"limit,query"`
+	offset *uint64 `param:"offset,query"`
+}
+
+func (c *RestClient) NewGetDepositsRequest() *GetDepositsRequest {
+	return &GetDepositsRequest{client: c}
+}
@@ -0,0 +1,51 @@
+package gateioapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type Trade struct {
+	Id           string                     `json:"id"`
+	CreateTimeMs types.MillisecondTimestamp `json:"create_time_ms"`
+	CurrencyPair string                     `json:"currency_pair"`
+	Side         Side                       `json:"side"`
+	Role         Role                       `json:"role"`
+	Amount       fixedpoint.Value           `json:"amount"`
+	Price        fixedpoint.Value           `json:"price"`
+	OrderId      string                     `json:"order_id"`
+	Fee          fixedpoint.Value           `json:"fee"`
+	FeeCurrency  string                     `json:"fee_currency"`
+	PointFee     fixedpoint.Value           `json:"point_fee"`
+	GtFee        fixedpoint.Value           `json:"gt_fee"`
+	Text         string                     `json:"text"`
+}
+
+//go:generate GetRequest -url "/spot/my_trades" -type GetMyTradesRequest -responseType []Trade
+type GetMyTradesRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currencyPair *string `param:"currency_pair,query"`
+	orderId      *string `param:"order_id,query"`
+
+	// page starts from 1
+	page *uint64 `param:"page,query"`
+	// limit is the maximum number of records to be returned, the max value is 1000.
+	limit *uint64 `param:"limit,query"`
+
+	from *time.Time `param:"from,query,seconds"`
+	to   *time.Time `param:"to,query,seconds"`
+}
+
+// NewGetMyTradesRequest is descending order by create time
+func (c *RestClient) NewGetMyTradesRequest() *GetMyTradesRequest {
+	return &GetMyTradesRequest{client: c}
+}
@@ -0,0 +1,38 @@
+package gateioapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+//go:generate GetRequest -url "/spot/orders" -type GetOrdersRequest -responseType []Order
+type GetOrdersRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currencyPair string           `param:"currency_pair,query"`
+	status       OrderQueryStatus `param:"status,query" validValues:"open,finished"`
+	side         *Side            `param:"side,query"`
+
+	// page starts from 1
+	page *uint64 `param:"page,query"`
+	// limit is the maximum number of records to be returned, the max value is 1000 for the finished orders
+	// and 100 for the open orders.
+	limit *uint64 `param:"limit,query"`
+
+	// from and to are only available for the finished orders.
+	from *time.Time `param:"from,query,seconds"`
+	to   *time.Time `param:"to,query,seconds"`
+}
+
+// NewGetOrdersRequest is descending order by create time
+func (c *RestClient) NewGetOrdersRequest() *GetOrdersRequest {
+	return &GetOrdersRequest{
+		client: c,
+		status: OrderQueryStatusOpen,
+	}
+}
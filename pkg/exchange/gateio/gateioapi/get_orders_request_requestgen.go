@@ -0,0 +1,267 @@
+// Code generated by "requestgen -method GET -url /spot/orders -type GetOrdersRequest -responseType []Order"; DO NOT EDIT.
+
+package gateioapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+func (g *GetOrdersRequest) CurrencyPair(currencyPair string) *GetOrdersRequest {
+	g.currencyPair = currencyPair
+	return g
+}
+
+func (g *GetOrdersRequest) Status(status OrderQueryStatus) *GetOrdersRequest {
+	g.status = status
+	return g
+}
+
+func (g *GetOrdersRequest) Side(side Side) *GetOrdersRequest {
+	g.side = &side
+	return g
+}
+
+func (g *GetOrdersRequest) Page(page uint64) *GetOrdersRequest {
+	g.page = &page
+	return g
+}
+
+func (g *GetOrdersRequest) Limit(limit uint64) *GetOrdersRequest {
+	g.limit = &limit
+	return g
+}
+
+func (g *GetOrdersRequest) From(from time.Time) *GetOrdersRequest {
+	g.from = &from
+	return g
+}
+
+func (g *GetOrdersRequest) To(to time.Time) *GetOrdersRequest {
+	g.to = &to
+	return g
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (g *GetOrdersRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+	// check currencyPair field -> json key currency_pair
+	currencyPair := g.currencyPair
+
+	// assign parameter of currencyPair
+	params["currency_pair"] = currencyPair
+	// check status field -> json key status
+	status := g.status
+
+	// TEMPLATE check-valid-values
+	switch status {
+	case "open", "finished":
+		params["status"] = status
+
+	default:
+		return nil, fmt.Errorf("status value %v is invalid", status)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of status
+	params["status"] = status
+	// check side field -> json key side
+	if g.side != nil {
+		side := *g.side
+
+		// TEMPLATE check-valid-values
+		switch side {
+		case SideBuy, SideSell:
+			params["side"] = side
+
+		default:
+			return nil, fmt.Errorf("side value %v is invalid", side)
+
+		}
+		// END TEMPLATE check-valid-values
+
+		// assign parameter of side
+		params["side"] = side
+	} else {
+	}
+	// check page field -> json key page
+	if g.page != nil {
+		page := *g.page
+
+		// assign parameter of page
+		params["page"] = page
+	} else {
+	}
+	// check limit field -> json key limit
+	if g.limit != nil {
+		limit := *g.limit
+
+		// assign parameter of limit
+		params["limit"] = limit
+	} else {
+	}
+	// check from field -> json key from
+	if g.from != nil {
+		from := *g.from
+
+		// assign parameter of from
+		// convert time.Time to seconds time stamp
+		params["from"] = strconv.FormatInt(from.Unix(), 10)
+	} else {
+	}
+	// check to field -> json key to
+	if g.to != nil {
+		to := *g.to
+
+		// assign parameter of to
+		// convert time.Time to seconds time stamp
+		params["to"] = strconv.FormatInt(to.Unix(), 10)
+	} else {
+	}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (g *GetOrdersRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (g *GetOrdersRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := g.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if g.isVarSlice(_v) {
+			g.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (g *GetOrdersRequest) GetParametersJSON() ([]byte, error) {
+	params, err := g.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (g *GetOrdersRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (g *GetOrdersRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (g *GetOrdersRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (g *GetOrdersRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (g *GetOrdersRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := g.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (g *GetOrdersRequest) GetPath() string {
+	return "/spot/orders"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (g *GetOrdersRequest) Do(ctx context.Context) ([]Order, error) {
+
+	// no body params
+	var params interface{}
+	query, err := g.GetQueryParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiURL string
+
+	apiURL = g.GetPath()
+
+	req, err := g.client.NewAuthenticatedRequest(ctx, "GET", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse []Order
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return apiResponse, nil
+}
@@ -0,0 +1,29 @@
+package gateioapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type SpotAccount struct {
+	Currency  string           `json:"currency"`
+	Available fixedpoint.Value `json:"available"`
+	Locked    fixedpoint.Value `json:"locked"`
+	UpdateId  int64            `json:"update_id"`
+}
+
+//go:generate GetRequest -url "/spot/accounts" -type GetSpotAccountsRequest -responseType []SpotAccount
+type GetSpotAccountsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currency *string `param:"currency,query"`
+}
+
+func (c *RestClient) NewGetSpotAccountsRequest() *GetSpotAccountsRequest {
+	return &GetSpotAccountsRequest{client: c}
+}
@@ -0,0 +1,35 @@
+package gateioapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type Ticker struct {
+	CurrencyPair     string           `json:"currency_pair"`
+	Last             fixedpoint.Value `json:"last"`
+	LowestAsk        fixedpoint.Value `json:"lowest_ask"`
+	HighestBid       fixedpoint.Value `json:"highest_bid"`
+	ChangePercentage fixedpoint.Value `json:"change_percentage"`
+	BaseVolume       fixedpoint.Value `json:"base_volume"`
+	QuoteVolume      fixedpoint.Value `json:"quote_volume"`
+	High24h          fixedpoint.Value `json:"high_24h"`
+	Low24h           fixedpoint.Value `json:"low_24h"`
+}
+
+//go:generate GetRequest -url "/spot/tickers" -type GetTickersRequest -responseType []Ticker
+type GetTickersRequest struct {
+	client requestgen.APIClient
+
+	// currencyPair returns the tickers of all the currency pairs if it's not specified.
+	currencyPair *string `param:"currency_pair,query"`
+}
+
+func (c *RestClient) NewGetTickersRequest() *GetTickersRequest {
+	return &GetTickersRequest{client: c}
+}
@@ -0,0 +1,48 @@
+package gateioapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type Withdrawal struct {
+	Id              string                     `json:"id"`
+	Txid            string                     `json:"txid"`
+	WithdrawOrderId string                     `json:"withdraw_order_id"`
+	Timestamp       types.MillisecondTimestamp `json:"timestamp"`
+	Amount          fixedpoint.Value           `json:"amount"`
+	Fee             fixedpoint.Value           `json:"fee"`
+	Currency        string                     `json:"currency"`
+	Address         string                     `json:"address"`
+	Memo            string                     `json:"memo"`
+	// Status shares the same values with the deposit status.
+	Status DepositStatus `json:"status"`
+	Chain  string        `json:"chain"`
+}
+
+//go:generate GetRequest -url "/wallet/withdrawals" -type GetWithdrawalsRequest -responseType []Withdrawal
+type GetWithdrawalsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currency *string `param:"currency,query"`
+
+	// the maximum range of from and to is 30 days, the last 7 days are returned by default.
+	from *time.Time `param:"from,query,seconds"`
+	to   *time.Time `param:"to,query,seconds"`
+
+	// limit is the maximum number of records to be returned, the max value is 500.
+	limit  *uint64 `param:"limit,query"`
+	offset *uint64 `param:"offset,query"`
+}
+
+func (c *RestClient) NewGetWithdrawalsRequest() *GetWithdrawalsRequest {
+	return &GetWithdrawalsRequest{client: c}
+}
@@ -0,0 +1,64 @@
+package gateioapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET
+//go:generate -command PostRequest requestgen -method POST
+//go:generate -command DeleteRequest requestgen -method DELETE
+
+type Order struct {
+	Id           string                     `json:"id"`
+	Text         string                     `json:"text"`
+	AmendText    string                     `json:"amend_text"`
+	CreateTimeMs types.MillisecondTimestamp `json:"create_time_ms"`
+	UpdateTimeMs types.MillisecondTimestamp `json:"update_time_ms"`
+	Status       OrderStatus                `json:"status"`
+	CurrencyPair string                     `json:"currency_pair"`
+	Type         OrderType                  `json:"type"`
+	Account      AccountType                `json:"account"`
+	Side         Side                       `json:"side"`
+	// Amount is the base amount, or the quote amount of the market buy orders.
+	Amount      fixedpoint.Value `json:"amount"`
+	Price       fixedpoint.Value `json:"price"`
+	TimeInForce TimeInForce      `json:"time_in_force"`
+	Iceberg     fixedpoint.Value `json:"iceberg"`
+	// Left is the amount left to fill.
+	Left fixedpoint.Value `json:"left"`
+	// FilledAmount is the amount traded to fill.
+	FilledAmount fixedpoint.Value `json:"filled_amount"`
+	// FilledTotal is the total filled in quote currency.
+	FilledTotal  fixedpoint.Value `json:"filled_total"`
+	AvgDealPrice fixedpoint.Value `json:"avg_deal_price"`
+	Fee          fixedpoint.Value `json:"fee"`
+	FeeCurrency  string           `json:"fee_currency"`
+	PointFee     fixedpoint.Value `json:"point_fee"`
+	GtFee        fixedpoint.Value `json:"gt_fee"`
+	FinishAs     FinishAs         `json:"finish_as"`
+}
+
+//go:generate PostRequest -url "/spot/orders" -type PlaceOrderRequest -responseType .Order
+type PlaceOrderRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	// text is the user defined information, it must be prefixed with `t-`.
+	text         *string      `param:"text"`
+	currencyPair string       `param:"currency_pair"`
+	orderType    OrderType    `param:"type" validValues:"limit,market"`
+	account      AccountType  `param:"account"`
+	side         Side         `param:"side" validValues:"buy,sell"`
+	amount       string       `param:"amount"`
+	price        *string      `param:"price"`
+	timeInForce  *TimeInForce `param:"time_in_force" validValues:"gtc,ioc,poc,fok"`
+}
+
+func (c *RestClient) NewPlaceOrderRequest() *PlaceOrderRequest {
+	return &PlaceOrderRequest{
+		client:  c,
+		account: AccountTypeSpot,
+	}
+}
@@ -0,0 +1,288 @@
+// Code generated by "requestgen -method POST -url /spot/orders -type PlaceOrderRequest -responseType .Order"; DO NOT EDIT.
+
+package gateioapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (p *PlaceOrderRequest) Text(text string) *PlaceOrderRequest {
+	p.text = &text
+	return p
+}
+
+func (p *PlaceOrderRequest) CurrencyPair(currencyPair string) *PlaceOrderRequest {
+	p.currencyPair = currencyPair
+	return p
+}
+
+func (p *PlaceOrderRequest) OrderType(orderType OrderType) *PlaceOrderRequest {
+	p.orderType = orderType
+	return p
+}
+
+func (p *PlaceOrderRequest) Account(account AccountType) *PlaceOrderRequest {
+	p.account = account
+	return p
+}
+
+func (p *PlaceOrderRequest) Side(side Side) *PlaceOrderRequest {
+	p.side = side
+	return p
+}
+
+func (p *PlaceOrderRequest) Amount(amount string) *PlaceOrderRequest {
+	p.amount = amount
+	return p
+}
+
+func (p *PlaceOrderRequest) Price(price string) *PlaceOrderRequest {
+	p.price = &price
+	return p
+}
+
+func (p *PlaceOrderRequest) TimeInForce(timeInForce TimeInForce) *PlaceOrderRequest {
+	p.timeInForce = &timeInForce
+	return p
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (p *PlaceOrderRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (p *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check text field -> json key text
+	if p.text != nil {
+		text := *p.text
+
+		// assign parameter of text
+		params["text"] = text
+	} else {
+	}
+	// check currencyPair field -> json key currency_pair
+	currencyPair := p.currencyPair
+
+	// assign parameter of currencyPair
+	params["currency_pair"] = currencyPair
+	// check orderType field -> json key type
+	orderType := p.orderType
+
+	// TEMPLATE check-valid-values
+	switch orderType {
+	case "limit", "market":
+		params["type"] = orderType
+
+	default:
+		return nil, fmt.Errorf("type value %v is invalid", orderType)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of orderType
+	params["type"] = orderType
+	// check account field -> json key account
+	account := p.account
+
+	// TEMPLATE check-valid-values
+	switch account {
+	case AccountTypeSpot:
+		params["account"] = account
+
+	default:
+		return nil, fmt.Errorf("account value %v is invalid", account)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of account
+	params["account"] = account
+	// check side field -> json key side
+	side := p.side
+
+	// TEMPLATE check-valid-values
+	switch side {
+	case "buy", "sell":
+		params["side"] = side
+
+	default:
+		return nil, fmt.Errorf("side value %v is invalid", side)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of side
+	params["side"] = side
+	// check amount field -> json key amount
+	amount := p.amount
+
+	// assign parameter of amount
+	params["amount"] = amount
+	// check price field -> json key price
+	if p.price != nil {
+		price := *p.price
+
+		// assign parameter of price
+		params["price"] = price
+	} else {
+	}
+	// check timeInForce field -> json key time_in_force
+	if p.timeInForce != nil {
+		timeInForce := *p.timeInForce
+
+		// TEMPLATE check-valid-values
+		switch timeInForce {
+		case "gtc", "ioc", "poc", "fok":
+			params["time_in_force"] = timeInForce
+
+		default:
+			return nil, fmt.Errorf("time_in_force value %v is invalid", timeInForce)
+
+		}
+		// END TEMPLATE check-valid-values
+
+		// assign parameter of timeInForce
+		params["time_in_force"] = timeInForce
+	} else {
+	}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (p *PlaceOrderRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := p.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if p.isVarSlice(_v) {
+			p.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (p *PlaceOrderRequest) GetParametersJSON() ([]byte, error) {
+	params, err := p.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (p *PlaceOrderRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (p *PlaceOrderRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (p *PlaceOrderRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (p *PlaceOrderRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (p *PlaceOrderRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := p.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (p *PlaceOrderRequest) GetPath() string {
+	return "/spot/orders"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (p *PlaceOrderRequest) Do(ctx context.Context) (*Order, error) {
+
+	params, err := p.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = p.GetPath()
+
+	req, err := p.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := p.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse Order
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &apiResponse, nil
+}
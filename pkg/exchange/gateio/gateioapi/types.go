@@ -0,0 +1,94 @@
+package gateioapi
+
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+type OrderType string
+
+const (
+	OrderTypeLimit  OrderType = "limit"
+	OrderTypeMarket OrderType = "market"
+)
+
+type AccountType string
+
+const (
+	AccountTypeSpot AccountType = "spot"
+)
+
+type TimeInForce string
+
+const (
+	// TimeInForceGTC is GoodTillCancelled
+	TimeInForceGTC TimeInForce = "gtc"
+	// TimeInForceIOC is ImmediateOrCancelled, taker only
+	TimeInForceIOC TimeInForce = "ioc"
+	// TimeInForcePOC is PendingOrCancelled, makes a post-only order that always enjoys a maker fee
+	TimeInForcePOC TimeInForce = "poc"
+	// TimeInForceFOK is FillOrKill, fill either completely or none
+	TimeInForceFOK TimeInForce = "fok"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusClosed    OrderStatus = "closed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// OrderQueryStatus is the status used to filter the orders, the closed and cancelled orders are all "finished".
+type OrderQueryStatus string
+
+const (
+	OrderQueryStatusOpen     OrderQueryStatus = "open"
+	OrderQueryStatusFinished OrderQueryStatus = "finished"
+)
+
+// FinishAs describes how the order is finished.
+type FinishAs string
+
+const (
+	FinishAsOpen      FinishAs = "open"
+	FinishAsFilled    FinishAs = "filled"
+	FinishAsCancelled FinishAs = "cancelled"
+	FinishAsIOC       FinishAs = "ioc"
+	FinishAsSTP       FinishAs = "stp"
+)
+
+type TradeStatus string
+
+const (
+	TradeStatusUntradable TradeStatus = "untradable"
+	TradeStatusBuyable    TradeStatus = "buyable"
+	TradeStatusSellable   TradeStatus = "sellable"
+	TradeStatusTradable   TradeStatus = "tradable"
+)
+
+type Role string
+
+const (
+	RoleTaker Role = "taker"
+	RoleMaker Role = "maker"
+)
+
+type DepositStatus string
+
+const (
+	DepositStatusDone    DepositStatus = "DONE"
+	DepositStatusCancel  DepositStatus = "CANCEL"
+	DepositStatusRequest DepositStatus = "REQUEST"
+	DepositStatusManual  DepositStatus = "MANUAL"
+	DepositStatusBCode   DepositStatus = "BCODE"
+	DepositStatusExtPend DepositStatus = "EXTPEND"
+	DepositStatusFail    DepositStatus = "FAIL"
+	DepositStatusInvalid DepositStatus = "INVALID"
+	DepositStatusVerify  DepositStatus = "VERIFY"
+	DepositStatusProces  DepositStatus = "PROCES"
+	DepositStatusPend    DepositStatus = "PEND"
+	DepositStatusDMove   DepositStatus = "DMOVE"
+)
@@ -0,0 +1,63 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/c9s/bbgo/pkg/exchange/gateio/gateioapi"
+)
+
+var packageTemplate = template.Must(template.New("").Parse(`// Code generated by go generate; DO NOT EDIT.
+package gateio
+
+var symbolMap = map[string]string{
+{{- range $k, $v := . }}
+	{{ printf "%q" $k }}: {{ printf "%q" $v }},
+{{- end }}
+}
+`))
+
+type Market struct {
+	Id string `json:"id"`
+}
+
+func main() {
+	const apiUrl = gateioapi.RestBaseURL + "/spot/currency_pairs"
+
+	resp, err := http.Get(apiUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	var markets []Market
+	if err := json.NewDecoder(resp.Body).Decode(&markets); err != nil {
+		log.Fatal(err)
+	}
+
+	var data = map[string]string{}
+	for _, m := range markets {
+		key := strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(m.Id)), "_", "")
+		data[key] = m.Id
+	}
+
+	f, err := os.Create("symbols.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer f.Close()
+
+	err = packageTemplate.Execute(f, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
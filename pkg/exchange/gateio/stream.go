@@ -0,0 +1,290 @@
+package gateio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/gateio/gateioapi"
+	"github.com/c9s/bbgo/pkg/exchange/retry"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	// orderBookUpdateInterval is the push interval of the limited-level order book snapshots
+	orderBookUpdateInterval = "100ms"
+
+	// allPairs subscribes the private channels of all the currency pairs
+	allPairs = "!all"
+)
+
+var (
+	marketTradeLogLimiter = rate.NewLimiter(rate.Every(time.Minute), 1)
+	tradeLogLimiter       = rate.NewLimiter(rate.Every(time.Minute), 1)
+	orderLogLimiter       = rate.NewLimiter(rate.Every(time.Minute), 1)
+	kLineLogLimiter       = rate.NewLimiter(rate.Every(time.Minute), 1)
+)
+
+// AccountBalanceProvider provides a function to query all balances at streaming connected and emit balance snapshot.
+type AccountBalanceProvider interface {
+	QueryAccountBalances(ctx context.Context) (types.BalanceMap, error)
+}
+
+//go:generate callbackgen -type Stream
+type Stream struct {
+	types.StandardStream
+
+	key, secret     string
+	balanceProvider AccountBalanceProvider
+
+	bookEventCallbacks        []func(e BookEvent)
+	kLineEventCallbacks       []func(e KLineEvent)
+	marketTradeEventCallbacks []func(e MarketTradeEvent)
+	orderEventCallbacks       []func(e []OrderEvent)
+	userTradeEventCallbacks   []func(e []UserTradeEvent)
+	balanceEventCallbacks     []func(e []BalanceEvent)
+}
+
+func NewStream(key, secret string, balanceProvider AccountBalanceProvider) *Stream {
+	stream := &Stream{
+		StandardStream: types.NewStandardStream(),
+		// pragma: allowlist nextline secret
+		key:             key,
+		secret:          secret,
+		balanceProvider: balanceProvider,
+	}
+
+	stream.SetEndpointCreator(func(_ context.Context) (string, error) { return gateioapi.WebSocketURL, nil })
+	stream.SetParser(parseWebSocketEvent)
+	stream.SetDispatcher(stream.dispatchEvent)
+	stream.SetHeartBeat(stream.ping)
+	stream.OnConnect(stream.handleConnect)
+	stream.OnAuth(stream.handleAuth)
+
+	stream.OnBookEvent(stream.handleBookEvent)
+	stream.OnKLineEvent(stream.handleKLineEvent)
+	stream.OnMarketTradeEvent(stream.handleMarketTradeEvent)
+	stream.OnOrderEvent(stream.handleOrderEvent)
+	stream.OnUserTradeEvent(stream.handleUserTradeEvent)
+	stream.OnBalanceEvent(stream.handleBalanceEvent)
+	return stream
+}
+
+func (s *Stream) dispatchEvent(event interface{}) {
+	switch e := event.(type) {
+	case *WebSocketEvent:
+		if err := e.IsValid(); err != nil {
+			log.WithError(err).Error("invalid event")
+			return
+		}
+
+		// the private channels are authenticated by each subscription, so the auth event is emitted once the
+		// balance channel is subscribed.
+		if e.IsAuthenticated() && e.Channel == ChannelBalances {
+			s.EmitAuth()
+		}
+
+	case *BookEvent:
+		s.EmitBookEvent(*e)
+
+	case *KLineEvent:
+		s.EmitKLineEvent(*e)
+
+	case *MarketTradeEvent:
+		s.EmitMarketTradeEvent(*e)
+
+	case []OrderEvent:
+		s.EmitOrderEvent(e)
+
+	case []UserTradeEvent:
+		s.EmitUserTradeEvent(e)
+
+	case []BalanceEvent:
+		s.EmitBalanceEvent(e)
+	}
+}
+
+// ping implements the Gate.io application level ping pong.
+func (s *Stream) ping(conn *websocket.Conn) error {
+	if err := conn.WriteJSON(newWebsocketRequest(ChannelPing, "")); err != nil {
+		log.WithError(err).Error("ping error")
+		return err
+	}
+
+	return nil
+}
+
+func (s *Stream) handleConnect() {
+	if err := s.syncSubscriptions(WsEventTypeSubscribe); err != nil {
+		return
+	}
+
+	if s.PublicOnly {
+		return
+	}
+
+	for _, channel := range []Channel{ChannelOrders, ChannelUserTrades} {
+		req := newWebsocketRequest(channel, WsEventTypeSubscribe, allPairs)
+		req.sign(s.key, s.secret)
+		if err := s.Conn.WriteJSON(req); err != nil {
+			log.WithError(err).Errorf("failed to subscribe %s", channel)
+			return
+		}
+	}
+
+	// the balance channel has no payload
+	req := newWebsocketRequest(ChannelBalances, WsEventTypeSubscribe)
+	req.sign(s.key, s.secret)
+	if err := s.Conn.WriteJSON(req); err != nil {
+		log.WithError(err).Errorf("failed to subscribe %s", ChannelBalances)
+	}
+}
+
+func (s *Stream) syncSubscriptions(event WsEventType) error {
+	if event != WsEventTypeUnsubscribe && event != WsEventTypeSubscribe {
+		return fmt.Errorf("unexpected subscription type: %v", event)
+	}
+
+	logger := log.WithField("event", event)
+	for _, subscription := range s.Subscriptions {
+		req, err := convertSubscription(subscription, event)
+		if err != nil {
+			logger.WithError(err).Errorf("convert error, subscription: %+v", subscription)
+			return err
+		}
+
+		logger.Infof("%s channel: %s %+v", event, req.Channel, req.Payload)
+		if err := s.Conn.WriteJSON(req); err != nil {
+			logger.WithError(err).Error("failed to send request")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Stream) Unsubscribe() {
+	// errors are handled in the syncSubscriptions, so they are skipped here.
+	_ = s.syncSubscriptions(WsEventTypeUnsubscribe)
+	s.Resubscribe(func(old []types.Subscription) (new []types.Subscription, err error) {
+		// clear the subscriptions
+		return []types.Subscription{}, nil
+	})
+}
+
+func convertSubscription(sub types.Subscription, event WsEventType) (WebsocketRequest, error) {
+	symbol := toLocalSymbol(sub.Symbol)
+	switch sub.Channel {
+	case types.BookChannel:
+		depth := "20"
+		switch sub.Options.Depth {
+		case types.DepthLevel1, types.DepthLevel5:
+			depth = "5"
+		case types.DepthLevel10:
+			depth = "10"
+		case types.DepthLevel50:
+			depth = "50"
+		case types.DepthLevelFull, types.DepthLevel200, types.DepthLevel400:
+			depth = "100"
+		}
+		return newWebsocketRequest(ChannelOrderBook, event, symbol, depth, orderBookUpdateInterval), nil
+
+	case types.KLineChannel:
+		interval, ok := toLocalInterval[sub.Options.Interval]
+		if !ok {
+			return WebsocketRequest{}, fmt.Errorf("unsupported interval: %s", sub.Options.Interval)
+		}
+		return newWebsocketRequest(ChannelCandlesticks, event, string(interval), symbol), nil
+
+	case types.MarketTradeChannel:
+		return newWebsocketRequest(ChannelTrades, event, symbol), nil
+	}
+
+	return WebsocketRequest{}, fmt.Errorf("unsupported stream channel: %s", sub.Channel)
+}
+
+func (s *Stream) handleAuth() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var balances types.BalanceMap
+	var err error
+	err = retry.GeneralBackoff(ctx, func() error {
+		balances, err = s.balanceProvider.QueryAccountBalances(ctx)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Error("no more attempts to retrieve balances")
+		return
+	}
+
+	s.EmitBalanceSnapshot(balances)
+}
+
+// handleBookEvent emits the book snapshots since the order book channel pushes the limited-level snapshots.
+func (s *Stream) handleBookEvent(e BookEvent) {
+	s.EmitBookSnapshot(e.OrderBook())
+}
+
+func (s *Stream) handleKLineEvent(e KLineEvent) {
+	kline, err := e.toGlobalKLine()
+	if err != nil {
+		if kLineLogLimiter.Allow() {
+			log.WithError(err).Error("failed to convert to global k line")
+		}
+		return
+	}
+
+	if kline.Closed {
+		s.EmitKLineClosed(kline)
+	} else {
+		s.EmitKLine(kline)
+	}
+}
+
+func (s *Stream) handleMarketTradeEvent(e MarketTradeEvent) {
+	trade, err := e.toGlobalTrade()
+	if err != nil {
+		if marketTradeLogLimiter.Allow() {
+			log.WithError(err).Error("failed to convert to market trade")
+		}
+		return
+	}
+
+	s.EmitMarketTrade(trade)
+}
+
+func (s *Stream) handleOrderEvent(events []OrderEvent) {
+	for _, event := range events {
+		order, err := event.toGlobalOrder()
+		if err != nil {
+			if orderLogLimiter.Allow() {
+				log.WithError(err).Error("failed to convert to global order")
+			}
+			continue
+		}
+
+		s.EmitOrderUpdate(*order)
+	}
+}
+
+func (s *Stream) handleUserTradeEvent(events []UserTradeEvent) {
+	for _, event := range events {
+		trade, err := event.toGlobalTrade()
+		if err != nil {
+			if tradeLogLimiter.Allow() {
+				log.WithError(err).Errorf("unable to convert: %+v", event)
+			}
+			continue
+		}
+
+		s.EmitTradeUpdate(*trade)
+	}
+}
+
+func (s *Stream) handleBalanceEvent(events []BalanceEvent) {
+	s.EmitBalanceUpdate(toGlobalBalanceUpdate(events))
+}
@@ -0,0 +1,366 @@
+package gateio
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/exchange/gateio/gateioapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type Channel string
+
+const (
+	ChannelPing         Channel = "spot.ping"
+	ChannelPong         Channel = "spot.pong"
+	ChannelOrderBook    Channel = "spot.order_book"
+	ChannelCandlesticks Channel = "spot.candlesticks"
+	ChannelTrades       Channel = "spot.trades"
+	ChannelOrders       Channel = "spot.orders"
+	ChannelUserTrades   Channel = "spot.usertrades"
+	ChannelBalances     Channel = "spot.balances"
+)
+
+// IsPrivate returns true if the channel requires the authentication.
+func (c Channel) IsPrivate() bool {
+	switch c {
+	case ChannelOrders, ChannelUserTrades, ChannelBalances:
+		return true
+	}
+	return false
+}
+
+type WsEventType string
+
+const (
+	WsEventTypeSubscribe   WsEventType = "subscribe"
+	WsEventTypeUnsubscribe WsEventType = "unsubscribe"
+	WsEventTypeUpdate      WsEventType = "update"
+	// WsEventTypeAll is used by the order book snapshots
+	WsEventTypeAll WsEventType = "all"
+)
+
+type WsAuth struct {
+	Method string `json:"method"`
+	Key    string `json:"KEY"`
+	Sign   string `json:"SIGN"`
+}
+
+type WebsocketRequest struct {
+	Time    int64       `json:"time"`
+	Channel Channel     `json:"channel"`
+	Event   WsEventType `json:"event,omitempty"`
+	Payload []string    `json:"payload,omitempty"`
+	Auth    *WsAuth     `json:"auth,omitempty"`
+}
+
+// sign signs the request of the private channels.
+//
+// See https://www.gate.io/docs/developers/apiv4/ws/en/#authentication
+func (r *WebsocketRequest) sign(key, secret string) {
+	payload := fmt.Sprintf("channel=%s&event=%s&time=%d", r.Channel, r.Event, r.Time)
+	r.Auth = &WsAuth{
+		Method: "api_key",
+		Key:    key,
+		Sign:   gateioapi.Sign(payload, secret),
+	}
+}
+
+type WsError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+/*
+sample:
+
+	{
+	  "time": 1606292218,
+	  "time_ms": 1606292218231,
+	  "channel": "spot.trades",
+	  "event": "subscribe",
+	  "error": null,
+	  "result": {
+	    "status": "success"
+	  }
+	}
+*/
+type WebSocketEvent struct {
+	Time    int64                      `json:"time"`
+	TimeMs  types.MillisecondTimestamp `json:"time_ms"`
+	Channel Channel                    `json:"channel"`
+	Event   WsEventType                `json:"event"`
+	Error   *WsError                   `json:"error"`
+	Result  json.RawMessage            `json:"result"`
+}
+
+func (w *WebSocketEvent) IsValid() error {
+	if w.Error != nil {
+		return fmt.Errorf("failed to %s %s, code: %d, message: %s", w.Event, w.Channel, w.Error.Code, w.Error.Message)
+	}
+	return nil
+}
+
+// IsAuthenticated returns true if a private channel is subscribed successfully.
+func (w *WebSocketEvent) IsAuthenticated() bool {
+	return w.Event == WsEventTypeSubscribe && w.Channel.IsPrivate() && w.Error == nil
+}
+
+type BookEvent struct {
+	UpdateTime   types.MillisecondTimestamp `json:"t"`
+	LastUpdateId int64                      `json:"lastUpdateId"`
+	Symbol       string                     `json:"s"`
+	Bids         types.PriceVolumeSlice     `json:"bids"`
+	Asks         types.PriceVolumeSlice     `json:"asks"`
+}
+
+func (e *BookEvent) OrderBook() types.SliceOrderBook {
+	return types.SliceOrderBook{
+		Symbol: toGlobalSymbol(e.Symbol),
+		Time:   e.UpdateTime.Time(),
+		Bids:   e.Bids,
+		Asks:   e.Asks,
+	}
+}
+
+type KLineEvent struct {
+	StartTime types.MillisecondTimestamp `json:"t"`
+	// QuoteVolume is the trading volume in the quote currency.
+	QuoteVolume fixedpoint.Value `json:"v"`
+	Close       fixedpoint.Value `json:"c"`
+	High        fixedpoint.Value `json:"h"`
+	Low         fixedpoint.Value `json:"l"`
+	Open        fixedpoint.Value `json:"o"`
+	// Name is the name of the subscription, in the format of <interval>_<currency pair>
+	Name string `json:"n"`
+	// BaseVolume is the trading volume in the base currency.
+	BaseVolume   fixedpoint.Value `json:"a"`
+	WindowClosed bool             `json:"w"`
+}
+
+func (e *KLineEvent) toGlobalKLine() (types.KLine, error) {
+	localInterval, localSymbol, ok := strings.Cut(e.Name, "_")
+	if !ok {
+		return types.KLine{}, fmt.Errorf("unexpected kline name: %s", e.Name)
+	}
+
+	interval, err := toGlobalInterval(gateioapi.CandlestickInterval(localInterval))
+	if err != nil {
+		return types.KLine{}, err
+	}
+
+	return toGlobalKLine(toGlobalSymbol(localSymbol), interval, gateioapi.Candlestick{
+		StartTime:    e.StartTime,
+		QuoteVolume:  e.QuoteVolume,
+		Close:        e.Close,
+		High:         e.High,
+		Low:          e.Low,
+		Open:         e.Open,
+		BaseVolume:   e.BaseVolume,
+		WindowClosed: e.WindowClosed,
+	}), nil
+}
+
+func toGlobalInterval(interval gateioapi.CandlestickInterval) (types.Interval, error) {
+	for k, v := range toLocalInterval {
+		if v == interval {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected interval: %s", interval)
+}
+
+type MarketTradeEvent struct {
+	Id           uint64                     `json:"id"`
+	CreateTimeMs types.MillisecondTimestamp `json:"create_time_ms"`
+	Side         gateioapi.Side             `json:"side"`
+	CurrencyPair string                     `json:"currency_pair"`
+	Amount       fixedpoint.Value           `json:"amount"`
+	Price        fixedpoint.Value           `json:"price"`
+}
+
+func (e *MarketTradeEvent) toGlobalTrade() (types.Trade, error) {
+	side, err := toGlobalSideType(e.Side)
+	if err != nil {
+		return types.Trade{}, err
+	}
+
+	return types.Trade{
+		ID:            e.Id,
+		Exchange:      types.ExchangeGateIO,
+		Price:         e.Price,
+		Quantity:      e.Amount,
+		QuoteQuantity: e.Price.Mul(e.Amount),
+		Symbol:        toGlobalSymbol(e.CurrencyPair),
+		Side:          side,
+		// the side of the market trades is the taker side
+		IsBuyer: side == types.SideTypeBuy,
+		IsMaker: false,
+		Time:    types.Time(e.CreateTimeMs.Time()),
+	}, nil
+}
+
+type OrderEventType string
+
+const (
+	OrderEventTypePut    OrderEventType = "put"
+	OrderEventTypeUpdate OrderEventType = "update"
+	OrderEventTypeFinish OrderEventType = "finish"
+)
+
+// OrderEvent is the order pushed by the server, the status is not provided, it's derived from the event and the
+// finish_as field instead.
+type OrderEvent struct {
+	gateioapi.Order
+
+	Event OrderEventType `json:"event"`
+}
+
+func (e *OrderEvent) toGlobalOrder() (*types.Order, error) {
+	order := e.Order
+	switch e.Event {
+	case OrderEventTypePut, OrderEventTypeUpdate:
+		order.Status = gateioapi.OrderStatusOpen
+
+	case OrderEventTypeFinish:
+		order.Status = gateioapi.OrderStatusClosed
+		if order.FinishAs == gateioapi.FinishAsCancelled || order.FinishAs == gateioapi.FinishAsSTP {
+			order.Status = gateioapi.OrderStatusCancelled
+		}
+
+	default:
+		return nil, fmt.Errorf("unexpected order event: %s", e.Event)
+	}
+
+	// the filled amount is not provided by the old version of the server
+	if order.FilledAmount.IsZero() && !isMarketBuy(order) {
+		order.FilledAmount = order.Amount.Sub(order.Left)
+	}
+
+	return toGlobalOrder(order)
+}
+
+type UserTradeEvent struct {
+	Id           uint64                     `json:"id"`
+	OrderId      string                     `json:"order_id"`
+	CurrencyPair string                     `json:"currency_pair"`
+	CreateTimeMs types.MillisecondTimestamp `json:"create_time_ms"`
+	Side         gateioapi.Side             `json:"side"`
+	Amount       fixedpoint.Value           `json:"amount"`
+	Role         gateioapi.Role             `json:"role"`
+	Price        fixedpoint.Value           `json:"price"`
+	Fee          fixedpoint.Value           `json:"fee"`
+	FeeCurrency  string                     `json:"fee_currency"`
+	PointFee     fixedpoint.Value           `json:"point_fee"`
+	GtFee        fixedpoint.Value           `json:"gt_fee"`
+	Text         string                     `json:"text"`
+}
+
+func (e *UserTradeEvent) toGlobalTrade() (*types.Trade, error) {
+	return toGlobalTrade(gateioapi.Trade{
+		Id:           strconv.FormatUint(e.Id, 10),
+		CreateTimeMs: e.CreateTimeMs,
+		CurrencyPair: e.CurrencyPair,
+		Side:         e.Side,
+		Role:         e.Role,
+		Amount:       e.Amount,
+		Price:        e.Price,
+		OrderId:      e.OrderId,
+		Fee:          e.Fee,
+		FeeCurrency:  e.FeeCurrency,
+		PointFee:     e.PointFee,
+		GtFee:        e.GtFee,
+		Text:         e.Text,
+	})
+}
+
+type BalanceEvent struct {
+	TimestampMs types.MillisecondTimestamp `json:"timestamp_ms"`
+	Currency    string                     `json:"currency"`
+	Change      fixedpoint.Value           `json:"change"`
+	Total       fixedpoint.Value           `json:"total"`
+	Available   fixedpoint.Value           `json:"available"`
+	Freeze      fixedpoint.Value           `json:"freeze"`
+}
+
+func toGlobalBalanceUpdate(events []BalanceEvent) types.BalanceMap {
+	balances := make(types.BalanceMap, len(events))
+	for _, e := range events {
+		locked := e.Freeze
+		if locked.IsZero() {
+			locked = e.Total.Sub(e.Available)
+		}
+
+		balances[e.Currency] = types.Balance{
+			Currency:  e.Currency,
+			Available: e.Available,
+			Locked:    locked,
+		}
+	}
+	return balances
+}
+
+func parseWebSocketEvent(in []byte) (interface{}, error) {
+	var e WebSocketEvent
+	if err := json.Unmarshal(in, &e); err != nil {
+		return nil, err
+	}
+
+	if e.Channel == ChannelPong {
+		return &types.WebsocketPongEvent{}, nil
+	}
+
+	if e.Event != WsEventTypeUpdate && e.Event != WsEventTypeAll {
+		return &e, nil
+	}
+
+	switch e.Channel {
+	case ChannelOrderBook:
+		var book BookEvent
+		if err := json.Unmarshal(e.Result, &book); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into BookEvent: %+v, err: %w", string(e.Result), err)
+		}
+		return &book, nil
+
+	case ChannelCandlesticks:
+		var kLine KLineEvent
+		if err := json.Unmarshal(e.Result, &kLine); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into KLineEvent: %+v, err: %w", string(e.Result), err)
+		}
+		return &kLine, nil
+
+	case ChannelTrades:
+		var trade MarketTradeEvent
+		if err := json.Unmarshal(e.Result, &trade); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into MarketTradeEvent: %+v, err: %w", string(e.Result), err)
+		}
+		return &trade, nil
+
+	case ChannelOrders:
+		var orders []OrderEvent
+		return orders, json.Unmarshal(e.Result, &orders)
+
+	case ChannelUserTrades:
+		var trades []UserTradeEvent
+		return trades, json.Unmarshal(e.Result, &trades)
+
+	case ChannelBalances:
+		var balances []BalanceEvent
+		return balances, json.Unmarshal(e.Result, &balances)
+	}
+
+	return nil, fmt.Errorf("unhandled websocket event: %+v", string(in))
+}
+
+func newWebsocketRequest(channel Channel, event WsEventType, payload ...string) WebsocketRequest {
+	return WebsocketRequest{
+		Time:    time.Now().Unix(),
+		Channel: channel,
+		Event:   event,
+		Payload: payload,
+	}
+}
@@ -0,0 +1,181 @@
+package gateio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_parseWebSocketEvent(t *testing.T) {
+	t.Run("pong", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.pong","event":"","result":null}`))
+		assert.NoError(t, err)
+		assert.Equal(t, &types.WebsocketPongEvent{}, e)
+	})
+
+	t.Run("subscribe failed", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.balances","event":"subscribe","error":{"code":4,"message":"Authentication required"},"result":null}`))
+		assert.NoError(t, err)
+
+		event, ok := e.(*WebSocketEvent)
+		assert.True(t, ok)
+		assert.ErrorContains(t, event.IsValid(), "Authentication required")
+		assert.False(t, event.IsAuthenticated())
+	})
+
+	t.Run("private channel subscribed", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.balances","event":"subscribe","error":null,"result":{"status":"success"}}`))
+		assert.NoError(t, err)
+
+		event, ok := e.(*WebSocketEvent)
+		assert.True(t, ok)
+		assert.NoError(t, event.IsValid())
+		assert.True(t, event.IsAuthenticated())
+	})
+
+	t.Run("order book", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.order_book","event":"update","result":{"t":1717031187100,"lastUpdateId":48791820,"s":"BTC_USDT","bids":[["66051.2","0.5"],["66051.1","1.2"]],"asks":[["66051.3","0.3"]]}}`))
+		assert.NoError(t, err)
+
+		book, ok := e.(*BookEvent)
+		assert.True(t, ok)
+		assert.Equal(t, types.SliceOrderBook{
+			Symbol: "BTCUSDT",
+			Time:   types.NewMillisecondTimestampFromInt(1717031187100).Time(),
+			Bids: types.PriceVolumeSlice{
+				{Price: fixedpoint.MustNewFromString("66051.2"), Volume: fixedpoint.MustNewFromString("0.5")},
+				{Price: fixedpoint.MustNewFromString("66051.1"), Volume: fixedpoint.MustNewFromString("1.2")},
+			},
+			Asks: types.PriceVolumeSlice{
+				{Price: fixedpoint.MustNewFromString("66051.3"), Volume: fixedpoint.MustNewFromString("0.3")},
+			},
+		}, book.OrderBook())
+	})
+
+	t.Run("candlestick", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.candlesticks","event":"update","result":{"t":"1717031160","v":"2362.32035","c":"66051.2","h":"66060","l":"66040.1","o":"66045","n":"1m_BTC_USDT","a":"0.0357","w":true}}`))
+		assert.NoError(t, err)
+
+		event, ok := e.(*KLineEvent)
+		assert.True(t, ok)
+
+		kLine, err := event.toGlobalKLine()
+		assert.NoError(t, err)
+		startTime := time.Unix(1717031160, 0)
+		assert.Equal(t, types.KLine{
+			Exchange:    types.ExchangeGateIO,
+			Symbol:      "BTCUSDT",
+			StartTime:   types.Time(startTime),
+			EndTime:     types.Time(startTime.Add(time.Minute - time.Millisecond)),
+			Interval:    types.Interval1m,
+			Open:        fixedpoint.NewFromFloat(66045),
+			Close:       fixedpoint.MustNewFromString("66051.2"),
+			High:        fixedpoint.NewFromFloat(66060),
+			Low:         fixedpoint.MustNewFromString("66040.1"),
+			Volume:      fixedpoint.MustNewFromString("0.0357"),
+			QuoteVolume: fixedpoint.MustNewFromString("2362.32035"),
+			Closed:      true,
+		}, kLine)
+	})
+
+	t.Run("market trade", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031187,"time_ms":1717031187123,"channel":"spot.trades","event":"update","result":{"id":309143071,"create_time":1717031187,"create_time_ms":"1717031187213.4578","side":"sell","currency_pair":"BTC_USDT","amount":"0.5","price":"66051.2","range":"2107-2107"}}`))
+		assert.NoError(t, err)
+
+		event, ok := e.(*MarketTradeEvent)
+		assert.True(t, ok)
+
+		trade, err := event.toGlobalTrade()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(309143071), trade.ID)
+		assert.Equal(t, "BTCUSDT", trade.Symbol)
+		assert.Equal(t, types.SideTypeSell, trade.Side)
+		assert.Equal(t, fixedpoint.MustNewFromString("33025.6"), trade.QuoteQuantity)
+	})
+
+	t.Run("orders", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031240,"time_ms":1717031240123,"channel":"spot.orders","event":"update","result":[{"id":"591423987437","user":1000001,"text":"t-1701853526","create_time":"1717031187","create_time_ms":"1717031187321","update_time":"1717031240","update_time_ms":"1717031240118","event":"finish","currency_pair":"BTC_USDT","type":"limit","account":"spot","side":"buy","amount":"0.001","price":"60000","time_in_force":"poc","left":"0.0004","filled_total":"36","avg_deal_price":"60000","fee":"0.0000012","fee_currency":"BTC","point_fee":"0","gt_fee":"0","rebated_fee":"0","rebated_fee_currency":"USDT","finish_as":"cancelled"}]}`))
+		assert.NoError(t, err)
+
+		events, ok := e.([]OrderEvent)
+		assert.True(t, ok)
+		assert.Len(t, events, 1)
+
+		order, err := events[0].toGlobalOrder()
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderStatusCanceled, order.Status)
+		assert.Equal(t, types.OrderTypeLimitMaker, order.Type)
+		assert.Equal(t, "1701853526", order.ClientOrderID)
+		// the filled amount is derived from the left amount
+		assert.Equal(t, fixedpoint.MustNewFromString("0.0006"), order.ExecutedQuantity)
+		assert.False(t, order.IsWorking)
+	})
+
+	t.Run("user trades", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031240,"time_ms":1717031240123,"channel":"spot.usertrades","event":"update","result":[{"id":9151380912,"user_id":1000001,"order_id":"591423987437","currency_pair":"BTC_USDT","create_time":1717031240,"create_time_ms":"1717031240118.123","side":"buy","amount":"0.0006","role":"maker","price":"60000","fee":"0.0000012","fee_currency":"BTC","point_fee":"0","gt_fee":"0","text":"t-1701853526"}]}`))
+		assert.NoError(t, err)
+
+		events, ok := e.([]UserTradeEvent)
+		assert.True(t, ok)
+		assert.Len(t, events, 1)
+
+		trade, err := events[0].toGlobalTrade()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(9151380912), trade.ID)
+		assert.Equal(t, uint64(591423987437), trade.OrderID)
+		assert.True(t, trade.IsMaker)
+		assert.Equal(t, "BTC", trade.FeeCurrency)
+	})
+
+	t.Run("balances", func(t *testing.T) {
+		e, err := parseWebSocketEvent([]byte(`{"time":1717031240,"time_ms":1717031240123,"channel":"spot.balances","event":"update","result":[{"timestamp":"1717031240","timestamp_ms":"1717031240118","user":"1000001","currency":"USDT","change":"-36","total":"1096.45","available":"1060.45"}]}`))
+		assert.NoError(t, err)
+
+		events, ok := e.([]BalanceEvent)
+		assert.True(t, ok)
+		assert.Equal(t, types.BalanceMap{
+			"USDT": {
+				Currency:  "USDT",
+				Available: fixedpoint.MustNewFromString("1060.45"),
+				Locked:    fixedpoint.NewFromFloat(36),
+			},
+		}, toGlobalBalanceUpdate(events))
+	})
+}
+
+func Test_convertSubscription(t *testing.T) {
+	req, err := convertSubscription(types.Subscription{
+		Symbol:  "BTCUSDT",
+		Channel: types.BookChannel,
+		Options: types.SubscribeOptions{Depth: types.DepthLevel50},
+	}, WsEventTypeSubscribe)
+	assert.NoError(t, err)
+	assert.Equal(t, ChannelOrderBook, req.Channel)
+	assert.Equal(t, []string{"BTC_USDT", "50", "100ms"}, req.Payload)
+
+	req, err = convertSubscription(types.Subscription{
+		Symbol:  "ETHUSDT",
+		Channel: types.KLineChannel,
+		Options: types.SubscribeOptions{Interval: types.Interval1h},
+	}, WsEventTypeUnsubscribe)
+	assert.NoError(t, err)
+	assert.Equal(t, ChannelCandlesticks, req.Channel)
+	assert.Equal(t, WsEventTypeUnsubscribe, req.Event)
+	assert.Equal(t, []string{"1h", "ETH_USDT"}, req.Payload)
+
+	_, err = convertSubscription(types.Subscription{Symbol: "BTCUSDT", Channel: types.AggTradeChannel}, WsEventTypeSubscribe)
+	assert.Error(t, err)
+}
+
+func Test_toLocalSymbol(t *testing.T) {
+	assert.Equal(t, "BTC_USDT", toLocalSymbol("BTCUSDT"))
+	// falls back to the quote currency suffix
+	assert.Equal(t, "NEWCOIN_USDT", toLocalSymbol("NEWCOINUSDT"))
+
+	localSymbols.Store("NEWCOINTRY", "NEWCOIN_TRY")
+	assert.Equal(t, "NEWCOIN_TRY", toLocalSymbol("NEWCOINTRY"))
+}
@@ -17,6 +17,8 @@ import (
 var packageTemplate = template.Must(template.New("").Parse(`// Code generated by go generate; DO NOT EDIT.
 package kucoin
 
+import "github.com/c9s/bbgo/pkg/types"
+
 var symbolMap = map[string]string{
 {{- range $k, $v := . }}
 	{{ printf "%q" $k }}: {{ printf "%q" $v }},
@@ -29,6 +31,10 @@ func toLocalSymbol(symbol string) string {
 		return s
 	}
 
+	if s, ok := types.DefaultSymbolRegistry.LocalSymbol(types.ExchangeKucoin, symbol); ok {
+		return s
+	}
+
 	return symbol
 }
 `))
@@ -1,6 +1,8 @@
 // Code generated by go generate; DO NOT EDIT.
 package kucoin
 
+import "github.com/c9s/bbgo/pkg/types"
+
 var symbolMap = map[string]string{
 	"1EARTHUSDT":     "1EARTH-USDT",
 	"1INCHUSDT":      "1INCH-USDT",
@@ -1113,5 +1115,9 @@ func toLocalSymbol(symbol string) string {
 		return s
 	}
 
+	if s, ok := types.DefaultSymbolRegistry.LocalSymbol(types.ExchangeKucoin, symbol); ok {
+		return s
+	}
+
 	return symbol
 }
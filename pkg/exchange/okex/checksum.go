@@ -0,0 +1,126 @@
+package okex
+
+import (
+	"hash/crc32"
+	"strings"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// checksumDepth is the number of the price levels of each side used for the book checksum
+const checksumDepth = 25
+
+// rawBookLevel is a price level in the format sent by the server
+type rawBookLevel struct {
+	volume      fixedpoint.Value
+	price, size string
+}
+
+type rawBook struct {
+	bids, asks map[fixedpoint.Value]rawBookLevel
+}
+
+func newRawBook() *rawBook {
+	return &rawBook{
+		bids: make(map[fixedpoint.Value]rawBookLevel),
+		asks: make(map[fixedpoint.Value]rawBookLevel),
+	}
+}
+
+func (b *rawBook) update(levels map[fixedpoint.Value]rawBookLevel, pvs PriceVolumeOrderSlice) {
+	for _, pv := range pvs {
+		if pv.Volume.IsZero() {
+			delete(levels, pv.Price)
+			continue
+		}
+
+		levels[pv.Price] = rawBookLevel{volume: pv.Volume, price: pv.RawPrice, size: pv.RawVolume}
+	}
+}
+
+// rawBookMap keeps the raw price and size strings of the book levels of each instrument received by a stream.
+// OKX calculates the checksum from the strings it sends, e.g., "0.10" and "0.1" have different checksums,
+// so the strings can't be restored from the fixedpoint values of the local book.
+type rawBookMap struct {
+	mu    sync.Mutex
+	books map[string]*rawBook
+}
+
+func newRawBookMap() *rawBookMap {
+	return &rawBookMap{
+		books: make(map[string]*rawBook),
+	}
+}
+
+// Update applies the book event of the books channel, the snapshot replaces the levels of the instrument.
+func (m *rawBookMap) Update(event BookEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	book, ok := m.books[event.InstrumentID]
+	if !ok || event.Action == ActionTypeSnapshot {
+		book = newRawBook()
+		m.books[event.InstrumentID] = book
+	}
+
+	for _, data := range event.Data {
+		book.update(book.bids, data.Bids)
+		book.update(book.asks, data.Asks)
+	}
+}
+
+// Reset removes the levels of all the instruments, the books are sent again by the snapshots after resubscribing
+func (m *rawBookMap) Reset() {
+	m.mu.Lock()
+	m.books = make(map[string]*rawBook)
+	m.mu.Unlock()
+}
+
+// Format returns the raw strings of the price level, the formatted fixedpoint values are returned if the level
+// is unknown or the size is changed.
+func (m *rawBookMap) Format(instID string, side types.SideType, pv types.PriceVolume) (string, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if book, ok := m.books[instID]; ok {
+		levels := book.bids
+		if side == types.SideTypeSell {
+			levels = book.asks
+		}
+
+		if level, ok := levels[pv.Price]; ok && level.volume.Eq(pv.Volume) {
+			return level.price, level.size
+		}
+	}
+
+	return pv.Price.String(), pv.Volume.String()
+}
+
+// CalculateBookChecksum calculates the checksum of the top 25 levels in the format of
+// "bid1Price:bid1Size:ask1Price:ask1Size:bid2Price:bid2Size:...", the crc32 value is a signed 32-bit integer.
+// The prices and the sizes are the raw strings received by the stream.
+//
+// See https://www.okx.com/docs-v5/en/#order-book-trading-market-data-ws-order-book-channel
+func (s *Stream) CalculateBookChecksum(symbol string, bids, asks types.PriceVolumeSlice) int64 {
+	instID := toLocalSymbol(symbol)
+	if s.IsFutures {
+		instID = toLocalFuturesSymbol(symbol)
+	}
+
+	var fields []string
+	for i := 0; i < checksumDepth; i++ {
+		if i < len(bids) {
+			price, size := s.rawBooks.Format(instID, types.SideTypeBuy, bids[i])
+			fields = append(fields, price, size)
+		}
+
+		if i < len(asks) {
+			price, size := s.rawBooks.Format(instID, types.SideTypeSell, asks[i])
+			fields = append(fields, price, size)
+		}
+	}
+
+	return int64(int32(crc32.ChecksumIEEE([]byte(strings.Join(fields, ":")))))
+}
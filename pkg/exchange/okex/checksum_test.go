@@ -0,0 +1,69 @@
+package okex
+
+import (
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStream_CalculateBookChecksum(t *testing.T) {
+	stream := NewStream(nil, nil)
+
+	bids := types.PriceVolumeSlice{
+		{Price: fixedpoint.MustNewFromString("3366.1"), Volume: fixedpoint.MustNewFromString("7")},
+		{Price: fixedpoint.MustNewFromString("3366"), Volume: fixedpoint.MustNewFromString("6")},
+	}
+	asks := types.PriceVolumeSlice{
+		{Price: fixedpoint.MustNewFromString("3366.8"), Volume: fixedpoint.MustNewFromString("9")},
+		{Price: fixedpoint.MustNewFromString("3368"), Volume: fixedpoint.MustNewFromString("8")},
+		{Price: fixedpoint.MustNewFromString("3372"), Volume: fixedpoint.MustNewFromString("8")},
+	}
+
+	t.Run("formatted values of the unknown levels", func(t *testing.T) {
+		expected := int64(int32(crc32.ChecksumIEEE([]byte("3366.1:7:3366.8:9:3366:6:3368:8:3372:8"))))
+		assert.Equal(t, expected, stream.CalculateBookChecksum("ETHUSDT", bids, asks))
+	})
+
+	t.Run("raw strings of the stream levels", func(t *testing.T) {
+		snapshot, err := parseWebSocketEvent([]byte(`{"arg":{"channel":"books","instId":"ETH-USDT"},"action":"snapshot","data":[{` +
+			`"asks":[["3366.80","9.0","0","1"],["3368.00","8.0","0","1"],["3372.00","8.0","0","1"]],` +
+			`"bids":[["3366.10","7.0","0","1"],["3366.00","1.0","0","1"]],"ts":"1597026383085","checksum":0}]}`))
+		assert.NoError(t, err)
+		stream.rawBooks.Update(*snapshot.(*BookEvent))
+
+		update, err := parseWebSocketEvent([]byte(`{"arg":{"channel":"books","instId":"ETH-USDT"},"action":"update","data":[{` +
+			`"asks":[],"bids":[["3366.00","6.0","0","1"]],"ts":"1597026383086","checksum":0}]}`))
+		assert.NoError(t, err)
+		stream.rawBooks.Update(*update.(*BookEvent))
+
+		expected := int64(int32(crc32.ChecksumIEEE([]byte("3366.10:7.0:3366.80:9.0:3366.00:6.0:3368.00:8.0:3372.00:8.0"))))
+		assert.Equal(t, expected, stream.CalculateBookChecksum("ETHUSDT", bids, asks))
+
+		// the size of the level is changed by a later update of the local book
+		bids[1].Volume = fixedpoint.MustNewFromString("5")
+		expected = int64(int32(crc32.ChecksumIEEE([]byte("3366.10:7.0:3366.80:9.0:3366:5:3368.00:8.0:3372.00:8.0"))))
+		assert.Equal(t, expected, stream.CalculateBookChecksum("ETHUSDT", bids, asks))
+	})
+
+	t.Run("reset on disconnect", func(t *testing.T) {
+		stream.EmitDisconnect()
+
+		expected := int64(int32(crc32.ChecksumIEEE([]byte("3366.1:7:3366.8:9:3366:5:3368:8:3372:8"))))
+		assert.Equal(t, expected, stream.CalculateBookChecksum("ETHUSDT", bids, asks))
+	})
+
+	t.Run("the raw books are not shared by the streams", func(t *testing.T) {
+		snapshot, err := parseWebSocketEvent([]byte(`{"arg":{"channel":"books","instId":"ETH-USDT"},"action":"snapshot","data":[{` +
+			`"asks":[["3366.80","9.0","0","1"]],"bids":[["3366.10","7.0","0","1"]],"ts":"1597026383085","checksum":0}]}`))
+		assert.NoError(t, err)
+		stream.rawBooks.Update(*snapshot.(*BookEvent))
+
+		other := NewStream(nil, nil)
+		expected := int64(int32(crc32.ChecksumIEEE([]byte("3366.1:7:3366.8:9"))))
+		assert.Equal(t, expected, other.CalculateBookChecksum("ETHUSDT", bids[:1], asks[:1]))
+	})
+}
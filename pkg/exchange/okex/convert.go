@@ -11,7 +11,8 @@ import (
 )
 
 func toGlobalSymbol(symbol string) string {
-	return strings.ReplaceAll(symbol, "-", "")
+	// the perpetual swap shares the same global symbol with the spot, e.g. BTC-USDT-SWAP -> BTCUSDT
+	return strings.ReplaceAll(strings.TrimSuffix(symbol, swapSuffix), "-", "")
 }
 
 //go:generate sh -c "echo \"package okex\nvar spotSymbolMap = map[string]string{\n\" $(curl -s -L 'https://www.okx.com/api/v5/public/instruments?instType=SPOT' | jq -r '.data[] | \"\\(.instId | sub(\"-\" ; \"\") | tojson ): \\( .instId | tojson),\n\"') \"\n}\" > symbols.go"
@@ -21,6 +22,11 @@ func toLocalSymbol(symbol string) string {
 		return s
 	}
 
+	// the newly listed markets are registered when the markets are loaded
+	if s, ok := types.DefaultSymbolRegistry.LocalSymbol(types.ExchangeOKEx, symbol); ok {
+		return s
+	}
+
 	log.Errorf("failed to look up local symbol from %s", symbol)
 	return symbol
 }
@@ -45,6 +51,8 @@ func toGlobalBalance(account *okexapi.Account) types.BalanceMap {
 			Currency:  balanceDetail.Currency,
 			Available: balanceDetail.CashBalance,
 			Locked:    balanceDetail.Frozen,
+			Borrowed:  balanceDetail.Liability.Abs(),
+			Interest:  balanceDetail.Interest.Abs(),
 		}
 	}
 	return balanceMap
@@ -80,13 +88,20 @@ func convertIntervalToCandle(interval types.Interval) string {
 	return "candle" + s
 }
 
-func convertSubscription(s types.Subscription) (WebsocketSubscription, error) {
+// convertSubscription converts the subscription into the websocket subscription, the instrument id of the perpetual
+// swap is used when isFutures is true.
+func convertSubscription(s types.Subscription, isFutures bool) (WebsocketSubscription, error) {
+	instId := toLocalSymbol(s.Symbol)
+	if isFutures {
+		instId = toLocalFuturesSymbol(s.Symbol)
+	}
+
 	switch s.Channel {
 	case types.KLineChannel:
 		// Channel names are:
 		return WebsocketSubscription{
 			Channel:      Channel(convertIntervalToCandle(s.Options.Interval)),
-			InstrumentID: toLocalSymbol(s.Symbol),
+			InstrumentID: instId,
 		}, nil
 
 	case types.BookChannel:
@@ -111,17 +126,17 @@ func convertSubscription(s types.Subscription) (WebsocketSubscription, error) {
 
 		return WebsocketSubscription{
 			Channel:      ch,
-			InstrumentID: toLocalSymbol(s.Symbol),
+			InstrumentID: instId,
 		}, nil
 	case types.BookTickerChannel:
 		return WebsocketSubscription{
 			Channel:      ChannelBooks5,
-			InstrumentID: toLocalSymbol(s.Symbol),
+			InstrumentID: instId,
 		}, nil
 	case types.MarketTradeChannel:
 		return WebsocketSubscription{
 			Channel:      ChannelMarketTrades,
-			InstrumentID: toLocalSymbol(s.Symbol),
+			InstrumentID: instId,
 		}, nil
 	}
 
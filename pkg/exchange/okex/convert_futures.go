@@ -0,0 +1,235 @@
+package okex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// swapSuffix is the suffix of the perpetual swap instrument id, e.g. BTC-USDT-SWAP
+const swapSuffix = "-SWAP"
+
+func toLocalFuturesSymbol(symbol string) string {
+	return toLocalSymbol(symbol) + swapSuffix
+}
+
+// contractSpec is the contract value and the lot size of the perpetual swap, the lot size is in contracts.
+type contractSpec struct {
+	Value   fixedpoint.Value
+	LotSize fixedpoint.Value
+}
+
+// contractValueMap stores the contract specs of the perpetual swaps. The size of the swap orders, trades and
+// positions is in contracts, so we need the contract value to convert it from and into the base currency quantity.
+//
+// The specs are set by QueryMarkets, a missing spec is loaded from the instrument API on demand, since the markets
+// may be loaded from the cache without calling QueryMarkets.
+type contractValueMap struct {
+	client *okexapi.RestClient
+
+	mu sync.RWMutex
+	m  map[string]contractSpec
+}
+
+func newContractValueMap(client *okexapi.RestClient) *contractValueMap {
+	return &contractValueMap{
+		client: client,
+		m:      make(map[string]contractSpec),
+	}
+}
+
+func (c *contractValueMap) Set(symbol string, value, lotSize fixedpoint.Value) {
+	c.mu.Lock()
+	c.m[symbol] = contractSpec{Value: value, LotSize: lotSize}
+	c.mu.Unlock()
+}
+
+// Get returns the contract spec of the symbol, the instrument is queried if the spec is not found.
+func (c *contractValueMap) Get(ctx context.Context, symbol string) (contractSpec, error) {
+	c.mu.RLock()
+	spec, ok := c.m[symbol]
+	c.mu.RUnlock()
+	if ok {
+		return spec, nil
+	}
+
+	if c.client == nil {
+		return contractSpec{}, fmt.Errorf("contract value of %s not found", symbol)
+	}
+
+	if err := queryMarketLimiter.Wait(ctx); err != nil {
+		return contractSpec{}, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	instruments, err := c.client.NewGetInstrumentsInfoRequest().
+		InstType(okexapi.InstrumentTypeSwap).
+		InstId(toLocalFuturesSymbol(symbol)).
+		Do(ctx)
+	if err != nil {
+		return contractSpec{}, fmt.Errorf("failed to query the contract value of %s, err: %w", symbol, err)
+	}
+
+	if len(instruments) != 1 || instruments[0].ContractValue.IsZero() {
+		return contractSpec{}, fmt.Errorf("contract value of %s not found, instruments: %+v", symbol, instruments)
+	}
+
+	c.Set(symbol, instruments[0].ContractValue, instruments[0].LotSize)
+	return c.Get(ctx, symbol)
+}
+
+// toContracts converts the base currency quantity into the number of contracts, rounded down to the lot size.
+func (c *contractValueMap) toContracts(ctx context.Context, symbol string, quantity fixedpoint.Value) (fixedpoint.Value, error) {
+	spec, err := c.Get(ctx, symbol)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	contracts := quantity.Div(spec.Value)
+	if spec.LotSize.Sign() > 0 {
+		contracts = contracts.Div(spec.LotSize).Floor().Mul(spec.LotSize)
+	}
+
+	if contracts.Sign() <= 0 {
+		return fixedpoint.Zero, fmt.Errorf("quantity %s of %s is less than one lot of %s contracts", quantity, symbol, spec.LotSize)
+	}
+
+	return contracts, nil
+}
+
+func (c *contractValueMap) toGlobalOrder(ctx context.Context, o *types.Order) error {
+	spec, err := c.Get(ctx, o.Symbol)
+	if err != nil {
+		return err
+	}
+
+	o.Quantity = o.Quantity.Mul(spec.Value)
+	o.ExecutedQuantity = o.ExecutedQuantity.Mul(spec.Value)
+	o.IsFutures = true
+	return nil
+}
+
+func (c *contractValueMap) toGlobalTrade(ctx context.Context, t *types.Trade) error {
+	spec, err := c.Get(ctx, t.Symbol)
+	if err != nil {
+		return err
+	}
+
+	t.Quantity = t.Quantity.Mul(spec.Value)
+	t.QuoteQuantity = t.Price.Mul(t.Quantity)
+	t.IsFutures = true
+	return nil
+}
+
+func toGlobalFuturesMarket(instrument okexapi.InstrumentInfo) types.Market {
+	ctVal := instrument.ContractValue
+	stepSize := instrument.LotSize.Mul(ctVal)
+	return types.Market{
+		Exchange:    types.ExchangeOKEx,
+		Symbol:      toGlobalSymbol(instrument.InstrumentID),
+		LocalSymbol: instrument.InstrumentID,
+
+		// the swap instrument doesn't have the base and quote currency, use the contract value currency
+		// and the settle currency instead.
+		QuoteCurrency: instrument.SettleCurrency,
+		BaseCurrency:  instrument.ContractValueCurrency,
+
+		PricePrecision:  instrument.TickSize.NumFractionalDigits(),
+		VolumePrecision: stepSize.NumFractionalDigits(),
+
+		TickSize:    instrument.TickSize,
+		StepSize:    stepSize,
+		MinQuantity: instrument.MinSize.Mul(ctVal),
+
+		// OKEx does not offer minimal notional, use 1 USD here.
+		MinNotional: fixedpoint.One,
+		MinAmount:   fixedpoint.One,
+	}
+}
+
+func toGlobalFuturesPosition(p okexapi.AccountPosition, ctVal fixedpoint.Value) types.FuturesPosition {
+	base := p.Position.Mul(ctVal)
+	// in the hedge mode, the position of the short side is positive.
+	if p.PositionSide == "short" {
+		base = base.Abs().Neg()
+	}
+
+	symbol := toGlobalSymbol(p.InstrumentID)
+	return types.FuturesPosition{
+		Symbol:                 symbol,
+		QuoteCurrency:          p.Currency,
+		Base:                   base,
+		Quote:                  base.Mul(p.AvgPrice).Neg(),
+		AverageCost:            p.AvgPrice,
+		ApproximateAverageCost: p.AvgPrice,
+		Isolated:               p.MarginMode == okexapi.MarginModeIsolated,
+		UpdateTime:             p.UpdatedTime.Time().UnixMilli(),
+		PositionRisk: &types.PositionRisk{
+			Leverage:         p.Leverage,
+			LiquidationPrice: p.LiquidationPrice,
+		},
+	}
+}
+
+func toGlobalFuturesPositions(
+	ctx context.Context, positions []okexapi.AccountPosition, contractValues *contractValueMap,
+) (types.FuturesPositionMap, error) {
+	m := make(types.FuturesPositionMap)
+	for _, p := range positions {
+		if p.InstrumentType != okexapi.InstrumentTypeSwap {
+			continue
+		}
+
+		symbol := toGlobalSymbol(p.InstrumentID)
+		spec, err := contractValues.Get(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		m[symbol] = toGlobalFuturesPosition(p, spec.Value)
+	}
+	return m, nil
+}
+
+func toGlobalFuturesAccountInfo(account *okexapi.Account, positions types.FuturesPositionMap) *types.FuturesAccountInfo {
+	assets := make(types.FuturesAssetMap)
+	totalUpl := fixedpoint.Zero
+	totalWallet := fixedpoint.Zero
+	for _, d := range account.Details {
+		assets[d.Currency] = types.FuturesUserAsset{
+			Asset:             d.Currency,
+			MarginBalance:     d.Equity,
+			MaxWithdrawAmount: d.AvailableBalance,
+			UnrealizedProfit:  d.UnrealizedProfitAndLoss,
+			WalletBalance:     d.CashBalance,
+		}
+		totalUpl = totalUpl.Add(d.UnrealizedProfitAndLoss)
+		totalWallet = totalWallet.Add(d.CashBalance)
+	}
+
+	return &types.FuturesAccountInfo{
+		Assets:                assets,
+		Positions:             positions,
+		TotalInitialMargin:    account.InitialMargin,
+		TotalMaintMargin:      account.MaintMargin,
+		TotalMarginBalance:    account.AdjustedEquity,
+		TotalUnrealizedProfit: totalUpl,
+		TotalWalletBalance:    totalWallet,
+	}
+}
+
+// toGlobalFundingFee converts the funding fee bill, the balance change is positive when the fee is received.
+func toGlobalFundingFee(bill okexapi.Bill) types.FundingFee {
+	return types.FundingFee{
+		Exchange: types.ExchangeOKEx,
+		Symbol:   toGlobalSymbol(bill.InstrumentID),
+		Asset:    bill.Currency,
+		Amount:   bill.BalanceChange,
+		TxnID:    strconv.FormatInt(int64(bill.BillId), 10),
+		Time:     bill.Timestamp.Time(),
+	}
+}
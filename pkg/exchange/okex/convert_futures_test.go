@@ -0,0 +1,124 @@
+package okex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_toGlobalSymbol_swap(t *testing.T) {
+	assert.Equal(t, "BTCUSDT", toGlobalSymbol("BTC-USDT-SWAP"))
+	assert.Equal(t, "BTCUSDT", toGlobalSymbol("BTC-USDT"))
+	assert.Equal(t, "BTC-USDT-SWAP", toLocalFuturesSymbol("BTCUSDT"))
+}
+
+func Test_toGlobalFuturesMarket(t *testing.T) {
+	market := toGlobalFuturesMarket(okexapi.InstrumentInfo{
+		InstrumentType:        string(okexapi.InstrumentTypeSwap),
+		InstrumentID:          "BTC-USDT-SWAP",
+		SettleCurrency:        "USDT",
+		ContractValue:         fixedpoint.NewFromFloat(0.01),
+		ContractValueCurrency: "BTC",
+		ContractType:          "linear",
+		TickSize:              fixedpoint.NewFromFloat(0.1),
+		LotSize:               fixedpoint.NewFromFloat(0.01),
+		MinSize:               fixedpoint.NewFromFloat(0.01),
+	})
+
+	assert.Equal(t, "BTCUSDT", market.Symbol)
+	assert.Equal(t, "BTC-USDT-SWAP", market.LocalSymbol)
+	assert.Equal(t, "BTC", market.BaseCurrency)
+	assert.Equal(t, "USDT", market.QuoteCurrency)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.0001), market.StepSize)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.0001), market.MinQuantity)
+	assert.Equal(t, 4, market.VolumePrecision)
+	assert.Equal(t, 1, market.PricePrecision)
+}
+
+func Test_contractValueMap(t *testing.T) {
+	ctx := context.Background()
+	m := newContractValueMap(nil)
+	m.Set("BTCUSDT", fixedpoint.NewFromFloat(0.01), fixedpoint.NewFromFloat(0.1))
+
+	contracts, err := m.toContracts(ctx, "BTCUSDT", fixedpoint.NewFromFloat(0.01234))
+	assert.NoError(t, err)
+	assert.Equal(t, fixedpoint.NewFromFloat(1.2), contracts)
+
+	_, err = m.toContracts(ctx, "BTCUSDT", fixedpoint.NewFromFloat(0.0005))
+	assert.Error(t, err)
+
+	// the contract value is never assumed to be one
+	_, err = m.toContracts(ctx, "ETHUSDT", fixedpoint.One)
+	assert.Error(t, err)
+
+	order := types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Quantity: fixedpoint.NewFromInt(3),
+		},
+		ExecutedQuantity: fixedpoint.NewFromInt(1),
+	}
+	assert.NoError(t, m.toGlobalOrder(ctx, &order))
+	assert.Equal(t, fixedpoint.NewFromFloat(0.03), order.Quantity)
+	assert.Equal(t, fixedpoint.NewFromFloat(0.01), order.ExecutedQuantity)
+	assert.True(t, order.IsFutures)
+
+	trade := types.Trade{
+		Symbol:   "BTCUSDT",
+		Price:    fixedpoint.NewFromInt(30000),
+		Quantity: fixedpoint.NewFromInt(2),
+	}
+	assert.NoError(t, m.toGlobalTrade(ctx, &trade))
+	assert.Equal(t, fixedpoint.NewFromFloat(0.02), trade.Quantity)
+	assert.Equal(t, fixedpoint.NewFromInt(600), trade.QuoteQuantity)
+	assert.True(t, trade.IsFutures)
+}
+
+func Test_toGlobalFuturesPosition(t *testing.T) {
+	p := toGlobalFuturesPosition(okexapi.AccountPosition{
+		InstrumentType:   okexapi.InstrumentTypeSwap,
+		InstrumentID:     "BTC-USDT-SWAP",
+		MarginMode:       okexapi.MarginModeCross,
+		PositionSide:     "net",
+		Position:         fixedpoint.NewFromInt(-5),
+		AvgPrice:         fixedpoint.NewFromInt(30000),
+		LiquidationPrice: fixedpoint.NewFromInt(45000),
+		Leverage:         fixedpoint.NewFromInt(3),
+		Currency:         "USDT",
+		UpdatedTime:      types.MillisecondTimestamp(time.UnixMilli(1700000000000)),
+	}, fixedpoint.NewFromFloat(0.01))
+
+	assert.Equal(t, "BTCUSDT", p.Symbol)
+	assert.Equal(t, fixedpoint.NewFromFloat(-0.05), p.Base)
+	assert.Equal(t, fixedpoint.NewFromInt(1500), p.Quote)
+	assert.False(t, p.Isolated)
+	assert.Equal(t, fixedpoint.NewFromInt(3), p.PositionRisk.Leverage)
+	assert.Equal(t, fixedpoint.NewFromInt(45000), p.PositionRisk.LiquidationPrice)
+}
+
+func Test_toGlobalFundingFee(t *testing.T) {
+	fee := toGlobalFundingFee(okexapi.Bill{
+		BillId:         types.StrInt64(623950854533513219),
+		InstrumentType: okexapi.InstrumentTypeSwap,
+		InstrumentID:   "BTC-USDT-SWAP",
+		Currency:       "USDT",
+		Type:           okexapi.BillTypeFundingFee,
+		BalanceChange:  fixedpoint.NewFromFloat(-0.35),
+		Timestamp:      types.MillisecondTimestamp(time.UnixMilli(1700000000000)),
+	})
+
+	assert.Equal(t, types.FundingFee{
+		Exchange: types.ExchangeOKEx,
+		Symbol:   "BTCUSDT",
+		Asset:    "USDT",
+		Amount:   fixedpoint.NewFromFloat(-0.35),
+		TxnID:    "623950854533513219",
+		Time:     time.UnixMilli(1700000000000),
+	}, fee)
+}
@@ -64,11 +64,23 @@ var log = logrus.WithFields(logrus.Fields{
 
 var ErrSymbolRequired = errors.New("symbol is a required parameter")
 
+var (
+	_ types.MarginExchange           = &Exchange{}
+	_ types.MarginBorrowRepayService = &Exchange{}
+	_ types.FuturesExchange          = &Exchange{}
+	_ types.FuturesPositionService   = &Exchange{}
+)
+
 type Exchange struct {
+	types.MarginSettings
+	types.FuturesSettings
+
 	key, secret, passphrase string
 
 	client      *okexapi.RestClient
 	timeNowFunc func() time.Time
+
+	contractValues *contractValueMap
 }
 
 func New(key, secret, passphrase string) *Exchange {
@@ -79,11 +91,12 @@ func New(key, secret, passphrase string) *Exchange {
 	}
 
 	return &Exchange{
-		key:         key,
-		secret:      secret,
-		passphrase:  passphrase,
-		client:      client,
-		timeNowFunc: time.Now,
+		key:            key,
+		secret:         secret,
+		passphrase:     passphrase,
+		client:         client,
+		timeNowFunc:    time.Now,
+		contractValues: newContractValueMap(client),
 	}
 }
 
@@ -92,6 +105,10 @@ func (e *Exchange) Name() types.ExchangeName {
 }
 
 func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	if e.IsFutures {
+		return e.queryFuturesMarkets(ctx)
+	}
+
 	if err := queryMarketLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
 	}
@@ -140,7 +157,7 @@ func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticke
 		return nil, fmt.Errorf("ticker rate limiter wait error: %w", err)
 	}
 
-	symbol = toLocalSymbol(symbol)
+	symbol = e.toLocalSymbol(symbol)
 	marketTicker, err := e.client.NewGetTickerRequest().InstId(symbol).Do(ctx)
 	if err != nil {
 		return nil, err
@@ -158,7 +175,7 @@ func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[str
 		return nil, fmt.Errorf("tickers rate limiter wait error: %w", err)
 	}
 
-	marketTickers, err := e.client.NewGetTickersRequest().Do(ctx)
+	marketTickers, err := e.client.NewGetTickersRequest().InstType(e.marketInstrumentType()).Do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +206,10 @@ func (e *Exchange) PlatformFeeCurrency() string {
 }
 
 func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	if e.IsFutures || e.IsMargin {
+		return e.queryMarginAccount(ctx)
+	}
+
 	bals, err := e.QueryAccountBalances(ctx)
 	if err != nil {
 		return nil, err
@@ -219,15 +240,38 @@ func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap,
 func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
 	orderReq := e.client.NewPlaceOrderRequest()
 
-	orderReq.InstrumentID(toLocalSymbol(order.Symbol))
+	orderReq.InstrumentID(e.toLocalSymbol(order.Symbol))
 	orderReq.Side(toLocalSideType(order.Side))
 	orderReq.Size(order.Market.FormatQuantity(order.Quantity))
+	orderReq.TradeMode(e.tradeMode())
+
+	if e.IsMargin {
+		orderReq.Currency(order.Market.QuoteCurrency)
+	}
+
+	if e.IsFutures {
+		// the size of the swap is in contracts
+		contracts, err := e.contractValues.toContracts(ctx, order.Symbol, order.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		orderReq.Size(contracts.String())
+		if order.ReduceOnly || order.ClosePosition {
+			orderReq.ReduceOnly(true)
+		}
+	}
 
 	// set price field for limit orders
 	switch order.Type {
 	case types.OrderTypeStopLimit, types.OrderTypeLimit, types.OrderTypeLimitMaker:
 		orderReq.Price(order.Market.FormatPrice(order.Price))
 	case types.OrderTypeMarket:
+		if e.IsFutures {
+			// the target currency is only applicable to the spot market orders
+			break
+		}
+
 		// Because our order.Quantity unit is base coin, so we indicate the target currency to Base.
 		if order.Side == types.SideTypeBuy {
 			orderReq.Size(order.Market.FormatQuantity(order.Quantity))
@@ -287,6 +331,8 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		IsWorking:        true,
 		CreationTime:     types.Time(timeNow),
 		UpdateTime:       types.Time(timeNow),
+		IsMargin:         e.IsMargin,
+		IsFutures:        e.IsFutures,
 	}, nil
 
 	// TODO: move this to batch place orders interface
@@ -324,7 +370,7 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 // QueryOpenOrders retrieves the pending orders. The data returned is ordered by createdTime, and we utilized the
 // `After` parameter to acquire all orders.
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
-	instrumentID := toLocalSymbol(symbol)
+	instrumentID := e.toLocalSymbol(symbol)
 
 	nextCursor := int64(0)
 	for {
@@ -333,6 +379,7 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 		}
 
 		req := e.client.NewGetOpenOrdersRequest().
+			InstrumentType(e.instrumentType()).
 			InstrumentID(instrumentID).
 			After(strconv.FormatInt(nextCursor, 10))
 		openOrders, err := req.Do(ctx)
@@ -345,6 +392,9 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 			if err != nil {
 				return nil, fmt.Errorf("failed to convert order, err: %v", err)
 			}
+			if err := e.toGlobalOrder(ctx, o); err != nil {
+				return nil, err
+			}
 
 			orders = append(orders, *o)
 		}
@@ -376,7 +426,7 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 		}
 
 		req := e.client.NewCancelOrderRequest()
-		req.InstrumentID(toLocalSymbol(order.Symbol))
+		req.InstrumentID(e.toLocalSymbol(order.Symbol))
 		req.OrderID(strconv.FormatUint(order.OrderID, 10))
 		if len(order.ClientOrderID) > 0 {
 			if ok := clientOrderIdRegex.MatchString(order.ClientOrderID); !ok {
@@ -397,7 +447,12 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 }
 
 func (e *Exchange) NewStream() types.Stream {
-	return NewStream(e.client, e)
+	stream := NewStream(e.client, e)
+	stream.MarginSettings = e.MarginSettings
+	stream.FuturesSettings = e.FuturesSettings
+	stream.kLineStream.FuturesSettings = e.FuturesSettings
+	stream.contractValues = e.contractValues
+	return stream
 }
 
 func (e *Exchange) QueryKLines(
@@ -412,7 +467,7 @@ func (e *Exchange) QueryKLines(
 		return nil, fmt.Errorf("failed to get interval: %w", err)
 	}
 
-	req := e.client.NewGetCandlesRequest().InstrumentID(toLocalSymbol(symbol))
+	req := e.client.NewGetCandlesRequest().InstrumentID(e.toLocalSymbol(symbol))
 	req.Bar(intervalParam)
 
 	if options.StartTime != nil {
@@ -445,7 +500,7 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 		return nil, errors.New("okex.QueryOrder: OrderId or ClientOrderId is required parameter")
 	}
 	req := e.client.NewGetOrderDetailsRequest()
-	req.InstrumentID(toLocalSymbol(q.Symbol)).
+	req.InstrumentID(e.toLocalSymbol(q.Symbol)).
 		OrderID(q.OrderID).
 		ClientOrderID(q.ClientOrderID)
 
@@ -456,7 +511,15 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 		return nil, err
 	}
 
-	return toGlobalOrder(order)
+	o, err := toGlobalOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.toGlobalOrder(ctx, o); err != nil {
+		return nil, err
+	}
+	return o, nil
 }
 
 // QueryOrderTrades quires order trades can query trades in last 3 months.
@@ -465,9 +528,9 @@ func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (tr
 		log.Warn("!!!OKEX EXCHANGE API NOTICE!!! Okex does not support searching for trades using OrderClientId.")
 	}
 
-	req := e.client.NewGetTransactionHistoryRequest()
+	req := e.client.NewGetTransactionHistoryRequest().InstrumentType(e.instrumentType())
 	if len(q.Symbol) != 0 {
-		req.InstrumentID(toLocalSymbol(q.Symbol))
+		req.InstrumentID(e.toLocalSymbol(q.Symbol))
 	}
 
 	if len(q.OrderID) != 0 {
@@ -486,7 +549,7 @@ func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (tr
 		trades = append(trades, tradeToGlobal(trade))
 	}
 
-	return trades, nil
+	return e.toGlobalTrades(ctx, trades)
 }
 
 /*
@@ -523,7 +586,8 @@ func (e *Exchange) QueryClosedOrders(
 	}
 
 	res, err := e.client.NewGetOrderHistoryRequest().
-		InstrumentID(toLocalSymbol(symbol)).
+		InstrumentType(e.instrumentType()).
+		InstrumentID(e.toLocalSymbol(symbol)).
 		StartTime(since).
 		EndTime(until).
 		Limit(defaultQueryLimit).
@@ -540,6 +604,10 @@ func (e *Exchange) QueryClosedOrders(
 			err = multierr.Append(err, err2)
 			continue
 		}
+		if err2 := e.toGlobalOrder(ctx, o); err2 != nil {
+			err = multierr.Append(err, err2)
+			continue
+		}
 
 		orders = append(orders, *o)
 	}
@@ -602,25 +670,35 @@ func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *type
 
 	if timeNow.Sub(newStartTime) <= threeDaysHistoricalPeriod {
 		c := e.client.NewGetThreeDaysTransactionHistoryRequest().
-			InstrumentID(toLocalSymbol(symbol)).
+			InstrumentType(e.instrumentType()).
+			InstrumentID(e.toLocalSymbol(symbol)).
 			StartTime(newStartTime).
 			EndTime(endTime).
 			Limit(uint64(limit))
-		return getTrades(ctx, limit, func(ctx context.Context, billId string) ([]okexapi.Trade, error) {
+		trades, err = getTrades(ctx, limit, func(ctx context.Context, billId string) ([]okexapi.Trade, error) {
 			c.Before(billId)
 			return c.Do(ctx)
 		})
+		if err != nil {
+			return nil, err
+		}
+		return e.toGlobalTrades(ctx, trades)
 	}
 
 	c := e.client.NewGetTransactionHistoryRequest().
-		InstrumentID(toLocalSymbol(symbol)).
+		InstrumentType(e.instrumentType()).
+		InstrumentID(e.toLocalSymbol(symbol)).
 		StartTime(newStartTime).
 		EndTime(endTime).
 		Limit(uint64(limit))
-	return getTrades(ctx, limit, func(ctx context.Context, billId string) ([]okexapi.Trade, error) {
+	trades, err = getTrades(ctx, limit, func(ctx context.Context, billId string) ([]okexapi.Trade, error) {
 		c.Before(billId)
 		return c.Do(ctx)
 	})
+	if err != nil {
+		return nil, err
+	}
+	return e.toGlobalTrades(ctx, trades)
 }
 
 func getTrades(ctx context.Context, limit int64, doFunc func(ctx context.Context, billId string) ([]okexapi.Trade, error)) (trades []types.Trade, err error) {
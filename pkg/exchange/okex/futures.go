@@ -0,0 +1,210 @@
+package okex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	// Rate Limit: 10 requests per 2 seconds, Rate limit rule: UserID
+	queryPositionLimiter = rate.NewLimiter(rate.Every(200*time.Millisecond), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: UserID
+	setLeverageLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+)
+
+// instrumentType returns the instrument type of the orders and trades of the current session.
+func (e *Exchange) instrumentType() okexapi.InstrumentType {
+	switch {
+	case e.IsFutures:
+		return okexapi.InstrumentTypeSwap
+	case e.IsMargin:
+		return okexapi.InstrumentTypeMARGIN
+	default:
+		return okexapi.InstrumentTypeSpot
+	}
+}
+
+// marketInstrumentType returns the instrument type of the markets and tickers, the margin trading shares the spot
+// instruments.
+func (e *Exchange) marketInstrumentType() okexapi.InstrumentType {
+	if e.IsFutures {
+		return okexapi.InstrumentTypeSwap
+	}
+	return okexapi.InstrumentTypeSpot
+}
+
+func (e *Exchange) tradeMode() okexapi.TradeMode {
+	switch {
+	case e.IsFutures && e.IsIsolatedFutures, e.IsMargin && e.IsIsolatedMargin:
+		return okexapi.TradeModeIsolated
+	case e.IsFutures, e.IsMargin:
+		return okexapi.TradeModeCross
+	default:
+		return okexapi.TradeModeCash
+	}
+}
+
+func (e *Exchange) toLocalSymbol(symbol string) string {
+	if e.IsFutures {
+		return toLocalFuturesSymbol(symbol)
+	}
+	return toLocalSymbol(symbol)
+}
+
+func (e *Exchange) toGlobalOrder(ctx context.Context, o *types.Order) error {
+	if e.IsFutures {
+		return e.contractValues.toGlobalOrder(ctx, o)
+	}
+
+	o.IsMargin = e.IsMargin
+	o.IsIsolated = e.IsIsolatedMargin
+	return nil
+}
+
+func (e *Exchange) toGlobalTrades(ctx context.Context, trades []types.Trade) ([]types.Trade, error) {
+	for i := range trades {
+		if e.IsFutures {
+			if err := e.contractValues.toGlobalTrade(ctx, &trades[i]); err != nil {
+				return nil, err
+			}
+		} else {
+			trades[i].IsMargin = e.IsMargin
+			trades[i].IsIsolated = e.IsIsolatedMargin
+		}
+	}
+	return trades, nil
+}
+
+func (e *Exchange) queryFuturesMarkets(ctx context.Context) (types.MarketMap, error) {
+	if err := queryMarketLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("markets rate limiter wait error: %w", err)
+	}
+
+	instruments, err := e.client.NewGetInstrumentsInfoRequest().
+		InstType(okexapi.InstrumentTypeSwap).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := types.MarketMap{}
+	for _, instrument := range instruments {
+		// only the USDT/USDC margined swaps are supported
+		if instrument.ContractType != "linear" {
+			continue
+		}
+
+		market := toGlobalFuturesMarket(instrument)
+		e.contractValues.Set(market.Symbol, instrument.ContractValue, instrument.LotSize)
+		types.DefaultSymbolRegistry.SetContractMultiplier(types.ExchangeOKEx, market.Symbol, instrument.ContractValue)
+		markets[market.Symbol] = market
+	}
+
+	return markets, nil
+}
+
+// QueryFuturesPositions queries the positions of the perpetual swaps.
+func (e *Exchange) QueryFuturesPositions(ctx context.Context) (types.FuturesPositionMap, error) {
+	if err := queryPositionLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("position rate limiter wait error: %w", err)
+	}
+
+	positions, err := e.client.NewGetPositionsRequest().
+		InstrumentType(okexapi.InstrumentTypeSwap).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions, err: %w", err)
+	}
+
+	return toGlobalFuturesPositions(ctx, positions, e.contractValues)
+}
+
+func (e *Exchange) QueryPositionRisk(ctx context.Context, symbol string) (*types.PositionRisk, error) {
+	if err := queryPositionLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("position rate limiter wait error: %w", err)
+	}
+
+	positions, err := e.client.NewGetPositionsRequest().
+		InstrumentType(okexapi.InstrumentTypeSwap).
+		InstrumentID(toLocalFuturesSymbol(symbol)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query position, err: %w", err)
+	}
+
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("position of %s not found", symbol)
+	}
+
+	spec, err := e.contractValues.Get(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGlobalFuturesPosition(positions[0], spec.Value).PositionRisk, nil
+}
+
+// SetLeverage sets the leverage of the perpetual swap with the margin mode of the current session.
+func (e *Exchange) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if err := setLeverageLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("leverage rate limiter wait error: %w", err)
+	}
+
+	marginMode := okexapi.MarginModeCross
+	if e.IsIsolatedFutures {
+		marginMode = okexapi.MarginModeIsolated
+	}
+
+	_, err := e.client.NewSetLeverageRequest().
+		InstrumentID(toLocalFuturesSymbol(symbol)).
+		Leverage(strconv.Itoa(leverage)).
+		MarginMode(marginMode).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to set leverage of %s, err: %w", symbol, err)
+	}
+
+	return nil
+}
+
+// queryMarginAccount queries the account of the margin and futures session, the futures info is included when the
+// futures mode is enabled.
+func (e *Exchange) queryMarginAccount(ctx context.Context) (*types.Account, error) {
+	if err := queryAccountLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("account rate limiter wait error: %w", err)
+	}
+
+	accounts, err := e.client.NewGetAccountInfoRequest().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accounts) != 1 {
+		return nil, fmt.Errorf("unexpected length of balances: %v", accounts)
+	}
+
+	account := types.NewAccount()
+	account.AccountType = types.AccountTypeMargin
+	account.UpdateBalances(toGlobalBalance(&accounts[0]))
+	account.MarginRatio = accounts[0].MarginRatio
+	account.TotalAccountValue = accounts[0].TotalEquityInUSD
+
+	if e.IsFutures {
+		positions, err := e.QueryFuturesPositions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		account.AccountType = types.AccountTypeFutures
+		account.FuturesInfo = toGlobalFuturesAccountInfo(&accounts[0], positions)
+	}
+
+	return account, nil
+}
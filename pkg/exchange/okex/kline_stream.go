@@ -10,6 +10,7 @@ import (
 //go:generate callbackgen -type KLineStream -interface
 type KLineStream struct {
 	types.StandardStream
+	types.FuturesSettings
 
 	kLineEventCallbacks []func(candle KLineEvent)
 }
@@ -39,7 +40,7 @@ func (s *KLineStream) handleConnect() {
 			continue
 		}
 
-		sub, err := convertSubscription(subscription)
+		sub, err := convertSubscription(subscription, s.IsFutures)
 		if err != nil {
 			log.WithError(err).Errorf("subscription convert error")
 			continue
@@ -85,7 +86,7 @@ func (s *KLineStream) dispatchEvent(e interface{}) {
 func (s *KLineStream) Unsubscribe() {
 	// errors are handled in the syncSubscriptions, so they are skipped here.
 	if len(s.StandardStream.Subscriptions) != 0 {
-		_ = syncSubscriptions(s.StandardStream.Conn, s.StandardStream.Subscriptions, WsEventTypeUnsubscribe)
+		_ = syncSubscriptions(s.StandardStream.Conn, s.StandardStream.Subscriptions, WsEventTypeUnsubscribe, s.IsFutures)
 	}
 	s.Resubscribe(func(old []types.Subscription) (new []types.Subscription, err error) {
 		// clear the subscriptions
@@ -0,0 +1,70 @@
+package okex
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+var (
+	// Rate Limit: 1 request per second, Rate limit rule: UserID
+	borrowRepayLimiter = rate.NewLimiter(rate.Every(time.Second), 1)
+	// Rate Limit: 20 requests per 2 seconds, Rate limit rule: UserID
+	queryMaxLoanLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+)
+
+// BorrowMarginAsset borrows the asset manually, it's only applicable to the spot mode with borrowing enabled.
+func (e *Exchange) BorrowMarginAsset(ctx context.Context, asset string, amount fixedpoint.Value) error {
+	return e.borrowRepay(ctx, okexapi.MarginSideBorrow, asset, amount)
+}
+
+func (e *Exchange) RepayMarginAsset(ctx context.Context, asset string, amount fixedpoint.Value) error {
+	return e.borrowRepay(ctx, okexapi.MarginSideRepay, asset, amount)
+}
+
+func (e *Exchange) borrowRepay(ctx context.Context, side okexapi.MarginSide, asset string, amount fixedpoint.Value) error {
+	if err := borrowRepayLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("borrow repay rate limiter wait error: %w", err)
+	}
+
+	res, err := e.client.NewSpotManualBorrowRepayRequest().
+		Currency(asset).
+		Side(side).
+		Amount(amount.String()).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to %s %s %s, err: %w", side, amount.String(), asset, err)
+	}
+
+	for _, r := range res {
+		log.Infof("%s margin asset %s %s", r.Side, r.Amount.String(), r.Currency)
+	}
+	return nil
+}
+
+func (e *Exchange) QueryMarginAssetMaxBorrowable(ctx context.Context, asset string) (amount fixedpoint.Value, err error) {
+	if err := queryMaxLoanLimiter.Wait(ctx); err != nil {
+		return fixedpoint.Zero, fmt.Errorf("max loan rate limiter wait error: %w", err)
+	}
+
+	loans, err := e.client.NewGetMaxLoanRequest().
+		MarginMode(okexapi.MarginModeCross).
+		Currency(asset).
+		Do(ctx)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	for _, loan := range loans {
+		if loan.Currency == asset || loan.MarginCurrency == asset {
+			return loan.MaxLoan, nil
+		}
+	}
+
+	return fixedpoint.Zero, fmt.Errorf("max loan of %s not found", asset)
+}
@@ -0,0 +1,59 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type TransferAccount string
+
+const (
+	TransferAccountFunding TransferAccount = "6"
+	TransferAccountTrading TransferAccount = "18"
+)
+
+type TransferType string
+
+const (
+	TransferTypeInternal       TransferType = "0"
+	TransferTypeMasterToSub    TransferType = "1"
+	TransferTypeSubToMaster    TransferType = "2"
+	TransferTypeSubToMasterSub TransferType = "3"
+	TransferTypeSubToSub       TransferType = "4"
+)
+
+type TransferResponse struct {
+	TransferID string `json:"transId"`
+	Currency   string `json:"ccy"`
+	ClientID   string `json:"clientId"`
+	From       string `json:"from"`
+	Amount     string `json:"amt"`
+	To         string `json:"to"`
+}
+
+// AssetTransferRequest transfers the asset between the funding and the trading accounts,
+// or between the master account and the sub-accounts.
+//
+//go:generate PostRequest -url "/api/v5/asset/transfer" -type AssetTransferRequest -responseDataType []TransferResponse
+type AssetTransferRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currency     string          `param:"ccy"`
+	amount       string          `param:"amt"`
+	from         TransferAccount `param:"from" validValues:"6,18"`
+	to           TransferAccount `param:"to" validValues:"6,18"`
+	subAccount   *string         `param:"subAcct"`
+	transferType TransferType    `param:"type" validValues:"0,1,2,3,4"`
+	clientID     *string         `param:"clientId"`
+}
+
+func (c *RestClient) NewAssetTransferRequest() *AssetTransferRequest {
+	return &AssetTransferRequest{
+		client:       c,
+		from:         TransferAccountTrading,
+		to:           TransferAccountTrading,
+		transferType: TransferTypeInternal,
+	}
+}
@@ -20,12 +20,24 @@ type BalanceDetail struct {
 	EquityInUSD             fixedpoint.Value           `json:"eqUsd"`
 	UpdateTime              types.MillisecondTimestamp `json:"uTime"`
 	UnrealizedProfitAndLoss fixedpoint.Value           `json:"upl"`
+
+	// The following fields are only applicable to the margin account.
+	AvailableBalance fixedpoint.Value `json:"availBal"`
+	Liability        fixedpoint.Value `json:"liab"`
+	Interest         fixedpoint.Value `json:"interest"`
+	MaxLoan          fixedpoint.Value `json:"maxLoan"`
 }
 
 type Account struct {
 	TotalEquityInUSD fixedpoint.Value           `json:"totalEq"`
 	UpdateTime       types.MillisecondTimestamp `json:"uTime"`
 	Details          []BalanceDetail            `json:"details"`
+
+	// The following fields are only applicable to the margin account.
+	AdjustedEquity fixedpoint.Value `json:"adjEq"`
+	MarginRatio    fixedpoint.Value `json:"mgnRatio"`
+	InitialMargin  fixedpoint.Value `json:"imr"`
+	MaintMargin    fixedpoint.Value `json:"mmr"`
 }
 
 //go:generate GetRequest -url "/api/v5/account/balance" -type GetAccountInfoRequest -responseDataType []Account
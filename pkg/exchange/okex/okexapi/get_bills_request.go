@@ -0,0 +1,68 @@
+package okexapi
+
+import (
+	"time"
+
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type BillType string
+
+const (
+	BillTypeTrade      BillType = "2"
+	BillTypeInterest   BillType = "7"
+	BillTypeFundingFee BillType = "8"
+)
+
+type Bill struct {
+	BillId         types.StrInt64             `json:"billId"`
+	InstrumentType InstrumentType             `json:"instType"`
+	InstrumentID   string                     `json:"instId"`
+	Currency       string                     `json:"ccy"`
+	MarginMode     MarginMode                 `json:"mgnMode"`
+	Type           BillType                   `json:"type"`
+	SubType        string                     `json:"subType"`
+	Balance        fixedpoint.Value           `json:"bal"`
+	BalanceChange  fixedpoint.Value           `json:"balChg"`
+	Size           fixedpoint.Value           `json:"sz"`
+	Price          fixedpoint.Value           `json:"px"`
+	Pnl            fixedpoint.Value           `json:"pnl"`
+	Fee            fixedpoint.Value           `json:"fee"`
+	OrderId        string                     `json:"ordId"`
+	Timestamp      types.MillisecondTimestamp `json:"ts"`
+}
+
+// GetBillsRequest queries the bills of the last 7 days.
+//
+//go:generate GetRequest -url "/api/v5/account/bills" -type GetBillsRequest -responseDataType []Bill
+type GetBillsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	instrumentType *InstrumentType `param:"instType,query"`
+	instrumentID   *string         `param:"instId,query"`
+	currency       *string         `param:"ccy,query"`
+	billType       *BillType       `param:"type,query"`
+
+	// Pagination of data to return records earlier than the requested bill ID.
+	after *string `param:"after,query"`
+	// Pagination of data to return records newer than the requested bill ID.
+	before *string `param:"before,query"`
+
+	startTime *time.Time `param:"begin,query,milliseconds"`
+	endTime   *time.Time `param:"end,query,milliseconds"`
+
+	// limit for data size per page. Default: 100
+	limit *uint64 `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetBillsRequest() *GetBillsRequest {
+	return &GetBillsRequest{
+		client: c,
+	}
+}
@@ -10,18 +10,20 @@ import (
 //go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
 
 type InstrumentInfo struct {
-	InstrumentType        string                     `json:"instType"`
-	InstrumentID          string                     `json:"instId"`
-	BaseCurrency          string                     `json:"baseCcy"`
-	QuoteCurrency         string                     `json:"quoteCcy"`
-	SettleCurrency        string                     `json:"settleCcy"`
-	ContractValue         string                     `json:"ctVal"`
-	ContractMultiplier    string                     `json:"ctMult"`
-	ContractValueCurrency string                     `json:"ctValCcy"`
-	ListTime              types.MillisecondTimestamp `json:"listTime"`
-	ExpiryTime            types.MillisecondTimestamp `json:"expTime"`
-	TickSize              fixedpoint.Value           `json:"tickSz"`
-	LotSize               fixedpoint.Value           `json:"lotSz"`
+	InstrumentType        string           `json:"instType"`
+	InstrumentID          string           `json:"instId"`
+	BaseCurrency          string           `json:"baseCcy"`
+	QuoteCurrency         string           `json:"quoteCcy"`
+	SettleCurrency        string           `json:"settleCcy"`
+	ContractValue         fixedpoint.Value `json:"ctVal"`
+	ContractMultiplier    string           `json:"ctMult"`
+	ContractValueCurrency string           `json:"ctValCcy"`
+	// ContractType is linear or inverse, only applicable to FUTURES/SWAP
+	ContractType string                     `json:"ctType"`
+	ListTime     types.MillisecondTimestamp `json:"listTime"`
+	ExpiryTime   types.MillisecondTimestamp `json:"expTime"`
+	TickSize     fixedpoint.Value           `json:"tickSz"`
+	LotSize      fixedpoint.Value           `json:"lotSz"`
 
 	// MinSize = min order size
 	MinSize fixedpoint.Value `json:"minSz"`
@@ -34,7 +36,7 @@ type InstrumentInfo struct {
 type GetInstrumentsInfoRequest struct {
 	client requestgen.APIClient
 
-	instType InstrumentType `param:"instType,query" validValues:"SPOT"`
+	instType InstrumentType `param:"instType,query" validValues:"SPOT,SWAP"`
 
 	instId *string `param:"instId,query"`
 }
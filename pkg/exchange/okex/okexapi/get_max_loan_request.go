@@ -0,0 +1,35 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type MaxLoan struct {
+	InstrumentID   string           `json:"instId"`
+	MarginMode     MarginMode       `json:"mgnMode"`
+	MarginCurrency string           `json:"mgnCcy"`
+	MaxLoan        fixedpoint.Value `json:"maxLoan"`
+	Currency       string           `json:"ccy"`
+	Side           SideType         `json:"side"`
+}
+
+//go:generate GetRequest -url "/api/v5/account/max-loan" -type GetMaxLoanRequest -responseDataType []MaxLoan
+type GetMaxLoanRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	marginMode   MarginMode `param:"mgnMode,query" validValues:"cross,isolated"`
+	instrumentID *string    `param:"instId,query"`
+	currency     *string    `param:"ccy,query"`
+}
+
+func (c *RestClient) NewGetMaxLoanRequest() *GetMaxLoanRequest {
+	return &GetMaxLoanRequest{
+		client:     c,
+		marginMode: MarginModeCross,
+	}
+}
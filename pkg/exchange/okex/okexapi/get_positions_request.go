@@ -0,0 +1,58 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type MarginMode string
+
+const (
+	MarginModeCross    MarginMode = "cross"
+	MarginModeIsolated MarginMode = "isolated"
+)
+
+type AccountPosition struct {
+	InstrumentType InstrumentType `json:"instType"`
+	InstrumentID   string         `json:"instId"`
+	MarginMode     MarginMode     `json:"mgnMode"`
+	PositionId     string         `json:"posId"`
+	// PositionSide is net in the one-way mode, long or short in the hedge mode
+	PositionSide string `json:"posSide"`
+	// Position is the quantity of positions in contracts, it's signed in the net mode.
+	Position         fixedpoint.Value `json:"pos"`
+	AvgPrice         fixedpoint.Value `json:"avgPx"`
+	MarkPrice        fixedpoint.Value `json:"markPx"`
+	LiquidationPrice fixedpoint.Value `json:"liqPx"`
+	Leverage         fixedpoint.Value `json:"lever"`
+	NotionalUsd      fixedpoint.Value `json:"notionalUsd"`
+	UnrealizedPnl    fixedpoint.Value `json:"upl"`
+	RealizedPnl      fixedpoint.Value `json:"realizedPnl"`
+	InitialMargin    fixedpoint.Value `json:"imr"`
+	MaintMargin      fixedpoint.Value `json:"mmr"`
+	MarginRatio      fixedpoint.Value `json:"mgnRatio"`
+	FundingFee       fixedpoint.Value `json:"fundingFee"`
+	Currency         string           `json:"ccy"`
+
+	CreatedTime types.MillisecondTimestamp `json:"cTime"`
+	UpdatedTime types.MillisecondTimestamp `json:"uTime"`
+}
+
+//go:generate GetRequest -url "/api/v5/account/positions" -type GetPositionsRequest -responseDataType []AccountPosition
+type GetPositionsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	instrumentType *InstrumentType `param:"instType,query" validValues:"MARGIN,SWAP,FUTURES,OPTION"`
+	instrumentID   *string         `param:"instId,query"`
+}
+
+func (c *RestClient) NewGetPositionsRequest() *GetPositionsRequest {
+	return &GetPositionsRequest{
+		client: c,
+	}
+}
@@ -0,0 +1,34 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type SubAccount struct {
+	Type         string                     `json:"type"`
+	Enable       bool                       `json:"enable"`
+	SubAccount   string                     `json:"subAcct"`
+	UID          string                     `json:"uid"`
+	Label        string                     `json:"label"`
+	CreationTime types.MillisecondTimestamp `json:"ts"`
+}
+
+//go:generate GetRequest -url "/api/v5/users/subaccount/list" -type GetSubAccountsRequest -responseDataType []SubAccount
+type GetSubAccountsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	subAccount *string `param:"subAcct,query"`
+	after      *string `param:"after,query"`
+	limit      *string `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetSubAccountsRequest() *GetSubAccountsRequest {
+	return &GetSubAccountsRequest{
+		client: c,
+	}
+}
@@ -40,7 +40,7 @@ type MarketTicker struct {
 type GetTickersRequest struct {
 	client requestgen.APIClient
 
-	instType InstrumentType `param:"instType,query" validValues:"SPOT"`
+	instType InstrumentType `param:"instType,query" validValues:"SPOT,SWAP"`
 }
 
 func (c *RestClient) NewGetTickersRequest() *GetTickersRequest {
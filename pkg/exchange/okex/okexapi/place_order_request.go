@@ -60,6 +60,14 @@ type PlaceOrderRequest struct {
 	// Only applicable to SPOT Market Orders
 	// Default is quote_ccy for buy, base_ccy for sell
 	targetCurrency *TargetCurrency `param:"tgtCcy" validValues:"quote_ccy,base_ccy"`
+
+	// Margin currency
+	// Only applicable to cross MARGIN orders in Single-currency margin.
+	currency *string `param:"ccy"`
+
+	// Whether orders can only reduce in position size.
+	// Only applicable to MARGIN orders, and FUTURES/SWAP orders in net mode
+	reduceOnly *bool `param:"reduceOnly"`
 }
 
 func (c *RestClient) NewPlaceOrderRequest() *PlaceOrderRequest {
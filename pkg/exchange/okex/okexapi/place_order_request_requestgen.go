@@ -56,6 +56,16 @@ func (r *PlaceOrderRequest) TargetCurrency(targetCurrency TargetCurrency) *Place
 	return r
 }
 
+func (r *PlaceOrderRequest) Currency(currency string) *PlaceOrderRequest {
+	r.currency = &currency
+	return r
+}
+
+func (r *PlaceOrderRequest) ReduceOnly(reduceOnly bool) *PlaceOrderRequest {
+	r.reduceOnly = &reduceOnly
+	return r
+}
+
 // GetQueryParameters builds and checks the query parameters and returns url.Values
 func (r *PlaceOrderRequest) GetQueryParameters() (url.Values, error) {
 	var params = map[string]interface{}{}
@@ -172,6 +182,22 @@ func (r *PlaceOrderRequest) GetParameters() (map[string]interface{}, error) {
 		params["tgtCcy"] = targetCurrency
 	} else {
 	}
+	// check currency field -> json key ccy
+	if r.currency != nil {
+		currency := *r.currency
+
+		// assign parameter of currency
+		params["ccy"] = currency
+	} else {
+	}
+	// check reduceOnly field -> json key reduceOnly
+	if r.reduceOnly != nil {
+		reduceOnly := *r.reduceOnly
+
+		// assign parameter of reduceOnly
+		params["reduceOnly"] = reduceOnly
+	} else {
+	}
 
 	return params, nil
 }
@@ -0,0 +1,31 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type LeverageResponse struct {
+	InstrumentID string     `json:"instId"`
+	Leverage     string     `json:"lever"`
+	MarginMode   MarginMode `json:"mgnMode"`
+	PositionSide string     `json:"posSide"`
+}
+
+//go:generate PostRequest -url "/api/v5/account/set-leverage" -type SetLeverageRequest -responseDataType []LeverageResponse
+type SetLeverageRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	instrumentID string     `param:"instId"`
+	leverage     string     `param:"lever"`
+	marginMode   MarginMode `param:"mgnMode" validValues:"cross,isolated"`
+}
+
+func (c *RestClient) NewSetLeverageRequest() *SetLeverageRequest {
+	return &SetLeverageRequest{
+		client:     c,
+		marginMode: MarginModeCross,
+	}
+}
@@ -0,0 +1,200 @@
+// Code generated by "requestgen -method POST -responseType .APIResponse -responseDataField Data -url /api/v5/account/set-leverage -type SetLeverageRequest -responseDataType []LeverageResponse"; DO NOT EDIT.
+
+package okexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (s *SetLeverageRequest) InstrumentID(instrumentID string) *SetLeverageRequest {
+	s.instrumentID = instrumentID
+	return s
+}
+
+func (s *SetLeverageRequest) Leverage(leverage string) *SetLeverageRequest {
+	s.leverage = leverage
+	return s
+}
+
+func (s *SetLeverageRequest) MarginMode(marginMode MarginMode) *SetLeverageRequest {
+	s.marginMode = marginMode
+	return s
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (s *SetLeverageRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (s *SetLeverageRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check instrumentID field -> json key instId
+	instrumentID := s.instrumentID
+
+	// assign parameter of instrumentID
+	params["instId"] = instrumentID
+	// check leverage field -> json key lever
+	leverage := s.leverage
+
+	// assign parameter of leverage
+	params["lever"] = leverage
+	// check marginMode field -> json key mgnMode
+	marginMode := s.marginMode
+
+	// TEMPLATE check-valid-values
+	switch marginMode {
+	case "cross", "isolated":
+		params["mgnMode"] = marginMode
+
+	default:
+		return nil, fmt.Errorf("mgnMode value %v is invalid", marginMode)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of marginMode
+	params["mgnMode"] = marginMode
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (s *SetLeverageRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := s.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if s.isVarSlice(_v) {
+			s.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (s *SetLeverageRequest) GetParametersJSON() ([]byte, error) {
+	params, err := s.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (s *SetLeverageRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (s *SetLeverageRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (s *SetLeverageRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (s *SetLeverageRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (s *SetLeverageRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := s.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (s *SetLeverageRequest) GetPath() string {
+	return "/api/v5/account/set-leverage"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (s *SetLeverageRequest) Do(ctx context.Context) ([]LeverageResponse, error) {
+
+	params, err := s.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = s.GetPath()
+
+	req, err := s.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data []LeverageResponse
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
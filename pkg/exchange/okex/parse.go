@@ -38,6 +38,12 @@ const (
 	ChannelAccount      Channel = "account"
 	ChannelMarketTrades Channel = "trades"
 	ChannelOrderTrades  Channel = "orders"
+
+	// ChannelPositions pushes the positions of the swaps when the position is changed or every 5 seconds.
+	ChannelPositions Channel = "positions"
+	// ChannelBalanceAndPosition pushes the balance and position changes, the event type tells why it's changed,
+	// e.g. funding_fee.
+	ChannelBalanceAndPosition Channel = "balance_and_position"
 )
 
 type ActionType string
@@ -92,6 +98,24 @@ func parseWebSocketEvent(in []byte) (interface{}, error) {
 
 		return orderTrade, nil
 
+	case ChannelPositions:
+		var positions []okexapi.AccountPosition
+		err := json.Unmarshal(event.Data, &positions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into positions: %+v, err: %w", string(event.Data), err)
+		}
+
+		return positions, nil
+
+	case ChannelBalanceAndPosition:
+		var balanceAndPositions []BalanceAndPositionEvent
+		err := json.Unmarshal(event.Data, &balanceAndPositions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into BalanceAndPositionEvent: %+v, err: %w", string(event.Data), err)
+		}
+
+		return balanceAndPositions, nil
+
 	default:
 		if strings.HasPrefix(string(event.Arg.Channel), string(ChannelCandlePrefix)) {
 			// TODO: Support kline subscription. The kline requires another URL to subscribe, which is why we cannot
@@ -231,6 +255,11 @@ type PriceVolumeOrder struct {
 	NumLiquidated int
 	// NumOrders is the number of orders at the price.
 	NumOrders int
+
+	// RawPrice and RawVolume are the price and the size in the format sent by the server,
+	// the book checksum is calculated from them.
+	RawPrice  string
+	RawVolume string
 }
 
 type PriceVolumeOrderSlice []PriceVolumeOrder
@@ -249,7 +278,7 @@ func (slice *PriceVolumeOrderSlice) UnmarshalJSON(b []byte) error {
 //
 //	[["8476.98", "415", "0", "13"], ["8477", "7", "0", "2"], ... ]
 func ParsePriceVolumeOrderSliceJSON(b []byte) (slice PriceVolumeOrderSlice, err error) {
-	var as [][]fixedpoint.Value
+	var as [][]string
 
 	err = json.Unmarshal(b, &as)
 	if err != nil {
@@ -257,11 +286,25 @@ func ParsePriceVolumeOrderSliceJSON(b []byte) (slice PriceVolumeOrderSlice, err
 	}
 
 	for _, a := range as {
+		if len(a) < 4 {
+			return slice, fmt.Errorf("unexpected price volume order: %v", a)
+		}
+
+		var values [4]fixedpoint.Value
+		for i := range values {
+			values[i], err = fixedpoint.NewFromString(a[i])
+			if err != nil {
+				return slice, fmt.Errorf("failed to parse price volume order %v, err: %w", a, err)
+			}
+		}
+
 		var pv PriceVolumeOrder
-		pv.Price = a[0]
-		pv.Volume = a[1]
-		pv.NumLiquidated = a[2].Int()
-		pv.NumOrders = a[3].Int()
+		pv.Price = values[0]
+		pv.Volume = values[1]
+		pv.NumLiquidated = values[2].Int()
+		pv.NumOrders = values[3].Int()
+		pv.RawPrice = a[0]
+		pv.RawVolume = a[1]
 
 		slice = append(slice, pv)
 	}
@@ -315,6 +358,32 @@ func parseAccount(v []byte) (*okexapi.Account, error) {
 	return &accounts[0], nil
 }
 
+type BalanceAndPositionEventType string
+
+const (
+	BalanceAndPositionEventTypeFundingFee BalanceAndPositionEventType = "funding_fee"
+)
+
+type BalanceAndPositionEvent struct {
+	PushTime  types.MillisecondTimestamp  `json:"pTime"`
+	EventType BalanceAndPositionEventType `json:"eventType"`
+	BalData   []struct {
+		Currency    string                     `json:"ccy"`
+		CashBalance fixedpoint.Value           `json:"cashBal"`
+		UpdateTime  types.MillisecondTimestamp `json:"uTime"`
+	} `json:"balData"`
+	PosData []struct {
+		PositionId   string                     `json:"posId"`
+		InstrumentID string                     `json:"instId"`
+		MarginMode   okexapi.MarginMode         `json:"mgnMode"`
+		PositionSide string                     `json:"posSide"`
+		Position     fixedpoint.Value           `json:"pos"`
+		Currency     string                     `json:"ccy"`
+		AvgPrice     fixedpoint.Value           `json:"avgPx"`
+		UpdateTime   types.MillisecondTimestamp `json:"uTime"`
+	} `json:"posData"`
+}
+
 type OrderTradeEvent struct {
 	okexapi.OrderDetail
 
@@ -120,6 +120,8 @@ func Test_parseWebSocketEvent_accountEvent(t *testing.T) {
 					UnrealizedProfitAndLoss: fixedpoint.Zero,
 				},
 			},
+			AdjustedEquity: fixedpoint.NewFromFloat(91884.850256),
+			MarginRatio:    fixedpoint.NewFromFloat(100000),
 		}
 
 		res, err := parseWebSocketEvent([]byte(in))
@@ -166,6 +168,8 @@ func TestParsePriceVolumeOrderSliceJSON(t *testing.T) {
 				},
 				NumLiquidated: fixedpoint.Zero.Int(),
 				NumOrders:     fixedpoint.NewFromFloat(13).Int(),
+				RawPrice:      "8476.98",
+				RawVolume:     "415",
 			},
 			{
 				PriceVolume: types.PriceVolume{
@@ -174,6 +178,8 @@ func TestParsePriceVolumeOrderSliceJSON(t *testing.T) {
 				},
 				NumLiquidated: fixedpoint.Zero.Int(),
 				NumOrders:     fixedpoint.NewFromFloat(2).Int(),
+				RawPrice:      "8477",
+				RawVolume:     "7",
 			},
 		}
 		bids := PriceVolumeOrderSlice{
@@ -184,6 +190,8 @@ func TestParsePriceVolumeOrderSliceJSON(t *testing.T) {
 				},
 				NumLiquidated: fixedpoint.Zero.Int(),
 				NumOrders:     fixedpoint.NewFromFloat(12).Int(),
+				RawPrice:      "8476",
+				RawVolume:     "256",
 			},
 		}
 
@@ -202,7 +210,6 @@ func TestParsePriceVolumeOrderSliceJSON(t *testing.T) {
 	})
 
 	t.Run("unexpected asks", func(t *testing.T) {
-		t.Skip("this will cause panic, so i skip it")
 		in := `
 {
   "arg": {
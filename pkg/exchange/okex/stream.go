@@ -37,10 +37,24 @@ type WebsocketLogin struct {
 //go:generate callbackgen -type Stream -interface
 type Stream struct {
 	types.StandardStream
+	types.MarginSettings
+	types.FuturesSettings
+
 	kLineStream *KLineStream
 
 	client          *okexapi.RestClient
 	balanceProvider types.ExchangeAccountService
+	contractValues  *contractValueMap
+
+	// rawBooks keeps the raw strings of the book levels for the book checksum
+	rawBooks *rawBookMap
+
+	// lastFundingBillId is the bill id of the last emitted funding fee
+	lastFundingBillId int64
+
+	// fundingFeeC passes the push time of the funding fee events to fundingFeeWorker, the bills are not queried in
+	// the dispatcher, since the query would block all the other events of the connection
+	fundingFeeC chan time.Time
 
 	// public callbacks
 	kLineEventCallbacks       []func(candle KLineEvent)
@@ -48,6 +62,9 @@ type Stream struct {
 	accountEventCallbacks     []func(account okexapi.Account)
 	orderTradesEventCallbacks []func(orderTrades []OrderTradeEvent)
 	marketTradeEventCallbacks []func(tradeDetail []MarketTradeEvent)
+
+	positionEventCallbacks           []func(positions []okexapi.AccountPosition)
+	balanceAndPositionEventCallbacks []func(event BalanceAndPositionEvent)
 }
 
 func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccountService) *Stream {
@@ -56,6 +73,9 @@ func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccount
 		balanceProvider: balanceProvider,
 		StandardStream:  types.NewStandardStream(),
 		kLineStream:     NewKLineStream(),
+		contractValues:  newContractValueMap(client),
+		rawBooks:        newRawBookMap(),
+		fundingFeeC:     make(chan time.Time, 1),
 	}
 
 	stream.SetParser(parseWebSocketEvent)
@@ -67,7 +87,10 @@ func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccount
 	stream.OnAccountEvent(stream.handleAccountEvent)
 	stream.OnMarketTradeEvent(stream.handleMarketTradeEvent)
 	stream.OnOrderTradesEvent(stream.handleOrderDetailsEvent)
+	stream.OnPositionEvent(stream.handlePositionEvent)
+	stream.OnBalanceAndPositionEvent(stream.handleBalanceAndPositionEvent)
 	stream.OnConnect(stream.handleConnect)
+	stream.OnDisconnect(stream.rawBooks.Reset)
 	stream.OnAuth(stream.subscribePrivateChannels(stream.emitBalanceSnapshot))
 	stream.kLineStream.OnKLineClosed(stream.EmitKLineClosed)
 	stream.kLineStream.OnKLine(stream.EmitKLine)
@@ -75,7 +98,7 @@ func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccount
 	return stream
 }
 
-func syncSubscriptions(conn *websocket.Conn, subscriptions []types.Subscription, opType WsEventType) error {
+func syncSubscriptions(conn *websocket.Conn, subscriptions []types.Subscription, opType WsEventType, isFutures bool) error {
 	if opType != WsEventTypeUnsubscribe && opType != WsEventTypeSubscribe {
 		return fmt.Errorf("unexpected subscription type: %v", opType)
 	}
@@ -83,7 +106,7 @@ func syncSubscriptions(conn *websocket.Conn, subscriptions []types.Subscription,
 	logger := log.WithField("opType", opType)
 	var topics []WebsocketSubscription
 	for _, subscription := range subscriptions {
-		topic, err := convertSubscription(subscription)
+		topic, err := convertSubscription(subscription, isFutures)
 		if err != nil {
 			logger.WithError(err).Errorf("convert error, subscription: %+v", subscription)
 			return err
@@ -106,13 +129,14 @@ func syncSubscriptions(conn *websocket.Conn, subscriptions []types.Subscription,
 
 func (s *Stream) Unsubscribe() {
 	// errors are handled in the syncSubscriptions, so they are skipped here.
-	_ = syncSubscriptions(s.StandardStream.Conn, s.StandardStream.Subscriptions, WsEventTypeUnsubscribe)
+	_ = syncSubscriptions(s.StandardStream.Conn, s.StandardStream.Subscriptions, WsEventTypeUnsubscribe, s.IsFutures)
 	s.Resubscribe(func(old []types.Subscription) (new []types.Subscription, err error) {
 		// clear the subscriptions
 		return []types.Subscription{}, nil
 	})
 
 	s.kLineStream.Unsubscribe()
+	s.rawBooks.Reset()
 }
 
 func (s *Stream) Connect(ctx context.Context) error {
@@ -122,6 +146,10 @@ func (s *Stream) Connect(ctx context.Context) error {
 	if err := s.kLineStream.Connect(ctx); err != nil {
 		return err
 	}
+
+	if !s.PublicOnly {
+		go s.fundingFeeWorker(ctx)
+	}
 	return nil
 }
 
@@ -153,7 +181,7 @@ func (s *Stream) handleConnect() {
 	if s.PublicOnly {
 		var subs []WebsocketSubscription
 		for _, subscription := range s.Subscriptions {
-			sub, err := convertSubscription(subscription)
+			sub, err := convertSubscription(subscription, s.IsFutures)
 			if err != nil {
 				log.WithError(err).Errorf("subscription convert error")
 				continue
@@ -193,7 +221,14 @@ func (s *Stream) subscribePrivateChannels(next func()) func() {
 	return func() {
 		var subs = []WebsocketSubscription{
 			{Channel: ChannelAccount},
-			{Channel: "orders", InstrumentType: string(okexapi.InstrumentTypeSpot)},
+			{Channel: ChannelOrderTrades, InstrumentType: string(s.instrumentType())},
+		}
+
+		if s.IsFutures {
+			subs = append(subs,
+				WebsocketSubscription{Channel: ChannelPositions, InstrumentType: string(okexapi.InstrumentTypeSwap)},
+				WebsocketSubscription{Channel: ChannelBalanceAndPosition},
+			)
 		}
 
 		// https://www.okx.com/docs-v5/zh/#overview-websocket-connect
@@ -239,7 +274,18 @@ func (s *Stream) handleOrderDetailsEvent(orderTrades []OrderTradeEvent) {
 					log.WithError(err).Errorf("failed to convert global trade")
 				}
 			} else {
-				s.EmitTradeUpdate(trade)
+				if s.IsFutures {
+					err = s.contractValues.toGlobalTrade(context.Background(), &trade)
+				} else {
+					trade.IsMargin = s.IsMargin
+					trade.IsIsolated = s.IsIsolatedMargin
+				}
+
+				if err != nil {
+					log.WithError(err).Errorf("failed to convert the futures trade: %+v", trade)
+				} else {
+					s.EmitTradeUpdate(trade)
+				}
 			}
 		}
 
@@ -249,7 +295,18 @@ func (s *Stream) handleOrderDetailsEvent(orderTrades []OrderTradeEvent) {
 				log.WithError(err).Errorf("failed to convert global order")
 			}
 		} else {
-			s.EmitOrderUpdate(*order)
+			if s.IsFutures {
+				err = s.contractValues.toGlobalOrder(context.Background(), order)
+			} else {
+				order.IsMargin = s.IsMargin
+				order.IsIsolated = s.IsIsolatedMargin
+			}
+
+			if err != nil {
+				log.WithError(err).Errorf("failed to convert the futures order: %+v", order)
+			} else {
+				s.EmitOrderUpdate(*order)
+			}
 		}
 	}
 }
@@ -260,7 +317,16 @@ func (s *Stream) handleAccountEvent(account okexapi.Account) {
 }
 
 func (s *Stream) handleBookEvent(data BookEvent) {
+	// the raw levels are recorded before the book is updated, the checksum is verified on the book update
+	if data.channel == ChannelBooks {
+		s.rawBooks.Update(data)
+	}
+
 	book := data.Book()
+	if len(data.Data) > 0 {
+		book.Checksum = int64(data.Data[0].Checksum)
+	}
+
 	switch data.Action {
 	case ActionTypeSnapshot:
 		s.EmitBookSnapshot(book)
@@ -320,5 +386,101 @@ func (s *Stream) dispatchEvent(e interface{}) {
 	case []MarketTradeEvent:
 		s.EmitMarketTradeEvent(et)
 
+	case []okexapi.AccountPosition:
+		s.EmitPositionEvent(et)
+
+	case []BalanceAndPositionEvent:
+		for _, evt := range et {
+			s.EmitBalanceAndPositionEvent(evt)
+		}
+
+	}
+}
+
+func (s *Stream) instrumentType() okexapi.InstrumentType {
+	switch {
+	case s.IsFutures:
+		return okexapi.InstrumentTypeSwap
+	case s.IsMargin:
+		return okexapi.InstrumentTypeMARGIN
+	default:
+		return okexapi.InstrumentTypeSpot
+	}
+}
+
+func (s *Stream) handlePositionEvent(positions []okexapi.AccountPosition) {
+	if len(positions) == 0 {
+		return
+	}
+
+	futuresPositions, err := toGlobalFuturesPositions(context.Background(), positions, s.contractValues)
+	if err != nil {
+		log.WithError(err).Error("failed to convert the futures positions")
+		return
+	}
+
+	s.EmitFuturesPositionUpdate(futuresPositions)
+}
+
+// handleBalanceAndPositionEvent emits the funding fees. The event doesn't carry the funding fee amount, so the funding
+// fee bills are queried by fundingFeeWorker.
+func (s *Stream) handleBalanceAndPositionEvent(event BalanceAndPositionEvent) {
+	if event.EventType != BalanceAndPositionEventTypeFundingFee {
+		return
+	}
+
+	select {
+	case s.fundingFeeC <- event.PushTime.Time():
+	default:
+		// the pending query is not started yet, it covers the bills of this event as well
+	}
+}
+
+func (s *Stream) fundingFeeWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case pushTime := <-s.fundingFeeC:
+			s.emitFundingFees(ctx, pushTime)
+		}
+	}
+}
+
+// emitFundingFees queries the funding fee bills after the last emitted one and emits them
+func (s *Stream) emitFundingFees(ctx context.Context, pushTime time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req := s.client.NewGetBillsRequest().
+		InstrumentType(okexapi.InstrumentTypeSwap).
+		BillType(okexapi.BillTypeFundingFee)
+	if s.lastFundingBillId > 0 {
+		req.Before(strconv.FormatInt(s.lastFundingBillId, 10))
+	} else {
+		// the funding fee is settled at the push time, a small window is enough to cover all positions.
+		req.StartTime(pushTime.Add(-time.Minute))
+	}
+
+	var bills []okexapi.Bill
+	err := retry.GeneralBackoff(ctx, func() (err error) {
+		bills, err = req.Do(ctx)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to query funding fee bills")
+		return
+	}
+
+	// the bills are in descending order
+	for i := len(bills) - 1; i >= 0; i-- {
+		bill := bills[i]
+		if int64(bill.BillId) <= s.lastFundingBillId {
+			continue
+		}
+
+		s.EmitFundingFee(toGlobalFundingFee(bill))
+		s.lastFundingBillId = int64(bill.BillId)
 	}
 }
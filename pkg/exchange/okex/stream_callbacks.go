@@ -56,6 +56,26 @@ func (s *Stream) EmitMarketTradeEvent(tradeDetail []MarketTradeEvent) {
 	}
 }
 
+func (s *Stream) OnPositionEvent(cb func(positions []okexapi.AccountPosition)) {
+	s.positionEventCallbacks = append(s.positionEventCallbacks, cb)
+}
+
+func (s *Stream) EmitPositionEvent(positions []okexapi.AccountPosition) {
+	for _, cb := range s.positionEventCallbacks {
+		cb(positions)
+	}
+}
+
+func (s *Stream) OnBalanceAndPositionEvent(cb func(event BalanceAndPositionEvent)) {
+	s.balanceAndPositionEventCallbacks = append(s.balanceAndPositionEventCallbacks, cb)
+}
+
+func (s *Stream) EmitBalanceAndPositionEvent(event BalanceAndPositionEvent) {
+	for _, cb := range s.balanceAndPositionEventCallbacks {
+		cb(event)
+	}
+}
+
 type StreamEventHub interface {
 	OnKLineEvent(cb func(candle KLineEvent))
 
@@ -66,4 +86,8 @@ type StreamEventHub interface {
 	OnOrderTradesEvent(cb func(orderTrades []OrderTradeEvent))
 
 	OnMarketTradeEvent(cb func(tradeDetail []MarketTradeEvent))
+
+	OnPositionEvent(cb func(positions []okexapi.AccountPosition))
+
+	OnBalanceAndPositionEvent(cb func(event BalanceAndPositionEvent))
 }
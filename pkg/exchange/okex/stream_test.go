@@ -2,6 +2,7 @@ package okex
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"strconv"
 	"testing"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
 	"github.com/c9s/bbgo/pkg/testutil"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -186,3 +189,55 @@ func TestStream(t *testing.T) {
 		<-c
 	})
 }
+
+func TestStream_handleBalanceAndPositionEvent(t *testing.T) {
+	ex := New("key", "secret", "passphrase")
+	transport := &httptesting.MockTransport{}
+	ex.client.HttpClient.Transport = transport
+
+	release := make(chan struct{})
+	transport.GET("/api/v5/account/bills", func(req *http.Request) (*http.Response, error) {
+		<-release
+		return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+			{"billId":"623950854533513219","instType":"SWAP","instId":"BTC-USDT-SWAP","ccy":"USDT","type":"8","balChg":"-0.35","ts":"1700000000000"}
+		]}`), nil
+	})
+
+	s := NewStream(ex.client, ex)
+	feeC := make(chan types.FundingFee, 1)
+	s.OnFundingFee(func(fee types.FundingFee) {
+		feeC <- fee
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.fundingFeeWorker(ctx)
+
+	event := BalanceAndPositionEvent{
+		PushTime:  types.MillisecondTimestamp(time.UnixMilli(1700000000000)),
+		EventType: BalanceAndPositionEventTypeFundingFee,
+	}
+
+	dispatched := make(chan struct{})
+	go func() {
+		s.handleBalanceAndPositionEvent(event)
+		s.handleBalanceAndPositionEvent(event)
+		close(dispatched)
+	}()
+
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("the dispatcher is blocked by the funding fee bills query")
+	}
+
+	close(release)
+	select {
+	case fee := <-feeC:
+		assert.Equal(t, "BTCUSDT", fee.Symbol)
+		assert.Equal(t, fixedpoint.NewFromFloat(-0.35), fee.Amount)
+		assert.Equal(t, "623950854533513219", fee.TxnID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the funding fee is not emitted")
+	}
+}
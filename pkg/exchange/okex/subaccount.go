@@ -0,0 +1,155 @@
+package okex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	_ types.SubAccountService       = &Exchange{}
+	_ types.InternalTransferService = &Exchange{}
+)
+
+var (
+	// Rate Limit: 2 requests per 2 seconds, Rate limit rule: UserID
+	querySubAccountLimiter = rate.NewLimiter(rate.Every(time.Second), 1)
+	// Rate Limit: 1 request per second, Rate limit rule: UserID + Currency
+	transferLimiter = rate.NewLimiter(rate.Every(time.Second), 1)
+)
+
+const subAccountQueryLimit = 100
+
+func (e *Exchange) QuerySubAccounts(ctx context.Context) ([]types.SubAccount, error) {
+	var subAccounts []types.SubAccount
+	var after string
+	for {
+		if err := querySubAccountLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("sub-account rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetSubAccountsRequest().Limit(strconv.Itoa(subAccountQueryLimit))
+		if after != "" {
+			req.After(after)
+		}
+
+		resp, err := req.Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range resp {
+			subAccounts = append(subAccounts, types.SubAccount{
+				Name:         a.SubAccount,
+				Label:        a.Label,
+				Frozen:       !a.Enable,
+				CreationTime: types.Time(a.CreationTime.Time()),
+			})
+		}
+
+		if len(resp) < subAccountQueryLimit {
+			return subAccounts, nil
+		}
+
+		// the sub-accounts are sorted by the creation time in descending order
+		after = strconv.FormatInt(resp[len(resp)-1].CreationTime.Time().UnixMilli(), 10)
+	}
+}
+
+func (e *Exchange) QuerySubAccountBalances(ctx context.Context, subAccount string) (types.BalanceMap, error) {
+	if err := queryAccountLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("account rate limiter wait error: %w", err)
+	}
+
+	accounts, err := e.client.NewGetSubAccountBalancesRequest().SubAccount(subAccount).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accounts) != 1 {
+		return nil, fmt.Errorf("unexpected length of sub-account balances: %v", accounts)
+	}
+
+	return toGlobalBalance(&accounts[0]), nil
+}
+
+// TransferSubAccountAsset transfers the asset between the trading accounts of the master and the sub-account
+func (e *Exchange) TransferSubAccountAsset(
+	ctx context.Context, subAccount, asset string, amount fixedpoint.Value, io types.TransferDirection,
+) error {
+	req := e.client.NewAssetTransferRequest().
+		Currency(asset).
+		Amount(amount.String()).
+		SubAccount(subAccount)
+
+	switch io {
+	case types.TransferIn:
+		req.TransferType(okexapi.TransferTypeMasterToSub)
+	case types.TransferOut:
+		req.TransferType(okexapi.TransferTypeSubToMaster)
+	default:
+		return fmt.Errorf("unexpected transfer direction: %d given", io)
+	}
+
+	return e.doTransfer(ctx, req)
+}
+
+// InternalTransfer transfers the asset between the funding and the trading accounts. The spot, margin and futures
+// accounts are the same trading account of the unified account, the transfers between them are no-ops.
+func (e *Exchange) InternalTransfer(
+	ctx context.Context, asset string, amount fixedpoint.Value, from, to types.AccountType,
+) error {
+	fromAccount, err := toLocalTransferAccount(from)
+	if err != nil {
+		return err
+	}
+
+	toAccount, err := toLocalTransferAccount(to)
+	if err != nil {
+		return err
+	}
+
+	if fromAccount == toAccount {
+		log.Infof("skip the transfer of %s %s from %s to %s, they are the same trading account", amount, asset, from, to)
+		return nil
+	}
+
+	req := e.client.NewAssetTransferRequest().
+		Currency(asset).
+		Amount(amount.String()).
+		From(fromAccount).
+		To(toAccount)
+	return e.doTransfer(ctx, req)
+}
+
+func (e *Exchange) doTransfer(ctx context.Context, req *okexapi.AssetTransferRequest) error {
+	if err := transferLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("transfer rate limiter wait error: %w", err)
+	}
+
+	resp, err := req.Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("transfer response: %+v", resp)
+	return nil
+}
+
+func toLocalTransferAccount(accountType types.AccountType) (okexapi.TransferAccount, error) {
+	switch accountType {
+	case types.AccountTypeSpot, types.AccountTypeMargin, types.AccountTypeFutures:
+		return okexapi.TransferAccountTrading, nil
+	case types.AccountTypeFunding:
+		return okexapi.TransferAccountFunding, nil
+	}
+
+	return "", fmt.Errorf("unsupported transfer account type: %s", accountType)
+}
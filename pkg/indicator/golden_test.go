@@ -0,0 +1,283 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/c9s/bbgo/pkg/testing/golden"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type goldenSeries interface {
+	Last(i int) float64
+	Length() int
+}
+
+// lazySeries reads the series created by the first update of the indicator, it returns nil before that
+type lazySeries func() goldenSeries
+
+func (f lazySeries) Last(i int) float64 {
+	if s := f(); s != nil {
+		return s.Last(i)
+	}
+	return 0
+}
+
+func (f lazySeries) Length() int {
+	if s := f(); s != nil {
+		return s.Length()
+	}
+	return 0
+}
+
+func goldenCase(
+	name, reference string, skip int, tolerance float64, knownDeviation string,
+	build func() (push func(k types.KLine), series goldenSeries),
+) golden.Case {
+	return golden.Case{
+		Name:           name,
+		Reference:      reference,
+		Skip:           skip,
+		Tolerance:      tolerance,
+		KnownDeviation: knownDeviation,
+		Calculate: func(kLines []types.KLine) []float64 {
+			push, series := build()
+			return golden.Collect(kLines, push, series)
+		},
+	}
+}
+
+const (
+	knownDeviationCCI        = "the mean deviation is the root mean square of the deviations instead of the mean absolute deviation"
+	knownDeviationOBV        = "the volume is compared with the previous price instead of comparing the close prices"
+	knownDeviationEMASeed    = "the EMA is seeded by the first value instead of the SMA of the first window"
+	knownDeviationRMASeed    = "the RMA is the adjusted EWM seeded by the first value instead of the Wilder smoothing seeded by the SMA"
+	knownDeviationHULL       = "the moving averages are EMAs instead of WMAs"
+	knownDeviationVIDYA      = "the VIDYA is seeded by the first value instead of 0 after the first window"
+	knownDeviationSSF        = "the filter is seeded by zeros instead of the source values"
+	knownDeviationKlinger    = "the volume force is weighted by the daily measurement (dm / cm) instead of the signed volume"
+	knownDeviationSupertrend = "the bands are calculated from the first kline instead of after the ATR warm-up, the trend line differs until the bands are reset"
+	knownDeviationPSAR       = "the SAR is the previous low or high during the warm-up window, and the acceleration factor is increased before the reversal check"
+)
+
+// The cases compare the whole output after the warm-up of the reference, so the seeding differences are not
+// skipped. The EMA and the RMA based indicators are seeded by the first value like pandas-ta with presma=False,
+// they are compared with the pandas-ta references and their TA-Lib references are known deviations.
+//
+// The indicators without a reference implementation in TA-Lib or pandas-ta are not covered here:
+// Drift, WeightedDrift, FisherTransform, GHFilter, KalmanFilter, UtBotAlert, VolumeProfile, PivotHigh, PivotLow,
+// PivotSupertrend and Line.
+func Test_Golden(t *testing.T) {
+	iw := func(window int) types.IntervalWindow {
+		return types.IntervalWindow{Interval: golden.Interval, Window: window}
+	}
+	closeOf := func(update func(v float64)) func(k types.KLine) {
+		return func(k types.KLine) {
+			update(k.Close.Float64())
+		}
+	}
+
+	cases := []golden.Case{
+		goldenCase("SMA", "sma_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &SMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("EWMA", "pta_ema_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &EWMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("EWMA/TA-Lib", "ema_20", 0, 0, knownDeviationEMASeed, func() (func(k types.KLine), goldenSeries) {
+			inc := &EWMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("DEMA", "pta_dema_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &DEMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("DEMA/TA-Lib", "dema_20", 0, 0, knownDeviationEMASeed, func() (func(k types.KLine), goldenSeries) {
+			inc := &DEMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("TEMA", "pta_tema_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &TEMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("TEMA/TA-Lib", "tema_20", 0, 0, knownDeviationEMASeed, func() (func(k types.KLine), goldenSeries) {
+			inc := &TEMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("TILL", "pta_t3_10", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &TILL{IntervalWindow: iw(10), VolumeFactor: 0.7}
+			return inc.PushK, inc
+		}),
+		goldenCase("ZLEMA", "pta_zlma_21", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &ZLEMA{IntervalWindow: iw(21)}
+			return inc.PushK, inc
+		}),
+		goldenCase("HULL", "pta_hma_16", 0, 0, knownDeviationHULL, func() (func(k types.KLine), goldenSeries) {
+			inc := &HULL{IntervalWindow: iw(16)}
+			return inc.PushK, inc
+		}),
+		// the first value of pandas-ta alma is a placeholder 0
+		goldenCase("ALMA", "pta_alma_10", 1, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &ALMA{IntervalWindow: iw(10), Offset: 0.85, Sigma: 6}
+			return closeOf(inc.Update), inc
+		}),
+		goldenCase("VIDYA", "pta_vidya_14", 0, 0, knownDeviationVIDYA, func() (func(k types.KLine), goldenSeries) {
+			inc := &VIDYA{IntervalWindow: iw(14)}
+			return inc.PushK, inc
+		}),
+		goldenCase("SSF", "pta_ssf_10_2", 0, 0, knownDeviationSSF, func() (func(k types.KLine), goldenSeries) {
+			inc := &SSF{IntervalWindow: iw(10), Poles: 2}
+			return inc.PushK, inc
+		}),
+		goldenCase("RMA", "pta_rma_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &RMA{IntervalWindow: iw(14), Adjust: true}
+			return inc.PushK, inc
+		}),
+		goldenCase("WWMA", "pta_wwma_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &WWMA{IntervalWindow: iw(14)}
+			return inc.PushK, inc
+		}),
+		goldenCase("CA", "fml_cma", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &CA{Interval: golden.Interval}
+			return inc.PushK, inc
+		}),
+		goldenCase("GMA", "fml_gma_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &GMA{IntervalWindow: iw(20)}
+			return inc.PushK, lazySeries(func() goldenSeries {
+				if inc.SMA == nil {
+					return nil
+				}
+				return inc
+			})
+		}),
+		goldenCase("TMA", "trima_21", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &TMA{IntervalWindow: iw(21)}
+			return inc.PushK, inc
+		}),
+		goldenCase("StdDev", "stddev_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &StdDev{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("RSI", "rsi_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &RSI{IntervalWindow: iw(14)}
+			return inc.PushK, inc
+		}),
+		goldenCase("TSI", "pta_tsi_13_25", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &TSI{Interval: golden.Interval, FastWindow: 13, SlowWindow: 25}
+			return inc.PushK, inc
+		}),
+		goldenCase("ATR", "pta_atr_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &ATR{IntervalWindow: iw(14)}
+			return inc.PushK, inc
+		}),
+		goldenCase("ATR/TA-Lib", "atr_14", 0, 0, knownDeviationRMASeed, func() (func(k types.KLine), goldenSeries) {
+			inc := &ATR{IntervalWindow: iw(14)}
+			return inc.PushK, inc
+		}),
+		goldenCase("BOLL.UpBand", "bbands_20_upper", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &BOLL{IntervalWindow: iw(20), K: 2}
+			return inc.PushK, &inc.UpBand
+		}),
+		goldenCase("BOLL.DownBand", "bbands_20_lower", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &BOLL{IntervalWindow: iw(20), K: 2}
+			return inc.PushK, &inc.DownBand
+		}),
+		goldenCase("MACD", "pta_macd_12_26_9", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &MACDLegacy{MACDConfig: MACDConfig{IntervalWindow: iw(9), ShortPeriod: 12, LongPeriod: 26}}
+			return inc.PushK, inc
+		}),
+		goldenCase("MACD.Histogram", "pta_macd_12_26_9_hist", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &MACDLegacy{MACDConfig: MACDConfig{IntervalWindow: iw(9), ShortPeriod: 12, LongPeriod: 26}}
+			return inc.PushK, &inc.Histogram
+		}),
+		goldenCase("STOCH.K", "stochf_14_3_k", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &STOCH{IntervalWindow: iw(14)}
+			return inc.PushK, &inc.K
+		}),
+		goldenCase("STOCH.D", "stochf_14_3_d", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &STOCH{IntervalWindow: iw(14)}
+			return inc.PushK, &inc.D
+		}),
+		goldenCase("CCI", "cci_20", 0, 0, knownDeviationCCI, func() (func(k types.KLine), goldenSeries) {
+			inc := &CCI{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("OBV", "obv", 0, 0, knownDeviationOBV, func() (func(k types.KLine), goldenSeries) {
+			inc := &OBV{IntervalWindow: iw(0)}
+			return inc.PushK, &inc.Values
+		}),
+		goldenCase("AD", "ad", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &AD{IntervalWindow: iw(0)}
+			return func(k types.KLine) {
+				inc.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+			}, inc
+		}),
+		goldenCase("EMV", "pta_eom_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &EMV{IntervalWindow: iw(14)}
+			return inc.PushK, inc
+		}),
+		goldenCase("VWAP", "pta_vwap", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &VWAP{IntervalWindow: iw(0)}
+			return inc.PushK, inc
+		}),
+		goldenCase("VWMA", "pta_vwma_20", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &VWMA{IntervalWindow: iw(20)}
+			return inc.PushK, inc
+		}),
+		goldenCase("KlingerOscillator", "pta_kvo_34_55", 0, 0, knownDeviationKlinger, func() (func(k types.KLine), goldenSeries) {
+			inc := &KlingerOscillator{
+				IntervalWindow: iw(0),
+				Fast:           &EWMA{IntervalWindow: iw(34)},
+				Slow:           &EWMA{IntervalWindow: iw(55)},
+			}
+			return inc.PushK, inc
+		}),
+		goldenCase("DMI.ADX", "pta_adx_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &DMI{IntervalWindow: iw(14), ADXSmoothing: 14}
+			return inc.PushK, lazySeries(func() goldenSeries {
+				if inc.ADX == nil {
+					return nil
+				}
+				return inc.ADX
+			})
+		}),
+		goldenCase("DMI.ADX/TA-Lib", "adx_14", 0, 0, knownDeviationRMASeed, func() (func(k types.KLine), goldenSeries) {
+			inc := &DMI{IntervalWindow: iw(14), ADXSmoothing: 14}
+			return inc.PushK, lazySeries(func() goldenSeries {
+				if inc.ADX == nil {
+					return nil
+				}
+				return inc.ADX
+			})
+		}),
+		goldenCase("DMI.DIPlus", "pta_dmp_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &DMI{IntervalWindow: iw(14), ADXSmoothing: 14}
+			return inc.PushK, lazySeries(func() goldenSeries {
+				if inc.DIPlus == nil {
+					return nil
+				}
+				return inc.DIPlus
+			})
+		}),
+		goldenCase("DMI.DIMinus", "pta_dmn_14", 0, 0, "", func() (func(k types.KLine), goldenSeries) {
+			inc := &DMI{IntervalWindow: iw(14), ADXSmoothing: 14}
+			return inc.PushK, lazySeries(func() goldenSeries {
+				if inc.DIMinus == nil {
+					return nil
+				}
+				return inc.DIMinus
+			})
+		}),
+		goldenCase("Supertrend", "pta_supertrend_10_3", 0, 0, knownDeviationSupertrend, func() (func(k types.KLine), goldenSeries) {
+			inc := &Supertrend{IntervalWindow: iw(10), ATRMultiplier: 3, AverageTrueRange: &ATR{IntervalWindow: iw(10)}}
+			return inc.PushK, inc
+		}),
+		goldenCase("PSAR", "sar", 0, 0, knownDeviationPSAR, func() (func(k types.KLine), goldenSeries) {
+			inc := &PSAR{IntervalWindow: iw(3)}
+			return inc.PushK, inc
+		}),
+	}
+
+	golden.RunTest(t, cases)
+}
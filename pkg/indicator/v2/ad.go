@@ -0,0 +1,28 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// ADStream is the Accumulation/Distribution line, the cumulative sum of the money flow volumes,
+// money flow volume = ((close - low) - (high - close)) / (high - low) * volume
+type ADStream struct {
+	*types.Float64Series
+}
+
+func AD(source KLineSubscription) *ADStream {
+	s := &ADStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low, closePrice := k.High.Float64(), k.Low.Float64(), k.Close.Float64()
+
+		var moneyFlowVolume float64
+		if high != low {
+			moneyFlowVolume = ((2*closePrice - high - low) / (high - low)) * k.Volume.Float64()
+		}
+
+		s.PushAndEmit(s.Slice.Last(0) + moneyFlowVolume)
+		s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
@@ -13,6 +13,8 @@ type ADXStream struct {
 	Plus, Minus *types.Float64Series
 
 	window            int
+	atr               *ATRStream
+	dmp, dmn          *RMAStream
 	prevHigh, prevLow fixedpoint.Value
 }
 
@@ -28,6 +30,9 @@ func ADX(source KLineSubscription, window int) *ADXStream {
 			window:    window,
 			Plus:      types.NewFloat64Series(),
 			Minus:     types.NewFloat64Series(),
+			atr:       atr,
+			dmp:       sdmp,
+			dmn:       sdmn,
 			prevHigh:  fixedpoint.Zero,
 			prevLow:   fixedpoint.Zero,
 			RMAStream: RMA2(adx, window, true),
@@ -0,0 +1,75 @@
+package indicatorv2
+
+import (
+	"sort"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// AlignedSeries records the values of a series with the end time of the kline that updated them,
+// so that a higher-timeframe series can be queried "as of" the time of a lower-timeframe event
+// without looking ahead into the unclosed higher-timeframe kline.
+type AlignedSeries struct {
+	series types.Series
+
+	times  []time.Time
+	values floats.Slice
+}
+
+// Align aligns the series calculated from the given kline source, the series must be created before calling Align,
+// so that it's updated before the aligned series records its value.
+func Align(source KLineSubscription, series types.Series) *AlignedSeries {
+	s := &AlignedSeries{series: series}
+
+	// the series is already calculated from the historical klines, align the values from the end
+	n := source.Length()
+	if l := series.Length(); l < n {
+		n = l
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		s.times = append(s.times, source.Last(i).EndTime.Time())
+		s.values.Push(series.Last(i))
+	}
+
+	backfilling := true
+	source.AddSubscriber(func(k types.KLine) {
+		if backfilling || series.Length() == 0 {
+			return
+		}
+
+		s.times = append(s.times, k.EndTime.Time())
+		s.values.Push(series.Last(0))
+
+		if len(s.times) > MaxNumOfKLines {
+			s.times = s.times[len(s.times)-1-MaxNumOfKLines:]
+			s.values = s.values[len(s.values)-1-MaxNumOfKLines:]
+		}
+	})
+	backfilling = false
+
+	return s
+}
+
+// Length returns the number of the aligned values
+func (s *AlignedSeries) Length() int {
+	return len(s.values)
+}
+
+// AsOf returns the i-th previous value of the klines closed at or before the given time, for example,
+// AsOf(k.EndTime.Time(), 0) in the 1m kline handler returns the value of the last closed 15m kline.
+// It returns false if there is no such value.
+func (s *AlignedSeries) AsOf(t time.Time, i int) (float64, bool) {
+	// the number of the klines closed at or before t
+	n := sort.Search(len(s.times), func(j int) bool {
+		return s.times[j].After(t)
+	})
+
+	if i < 0 || n-1-i < 0 {
+		return 0, false
+	}
+
+	return s.values[n-1-i], true
+}
@@ -0,0 +1,64 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ALMAStream is the Arnaud Legoux Moving Average, a moving average weighted by a gaussian distribution.
+// The offset (0~1) moves the peak of the distribution, 0.85 is the common setting,
+// and the sigma controls the sharpness of the distribution, 6 is the common setting.
+// The source value is emitted before the window is filled.
+//
+// Note the offset of the v1 ALMA is mirrored, the offset o of v1 is the offset 1 - o here.
+type ALMAStream struct {
+	*types.Float64Series
+
+	rawValues *types.Queue
+	weights   []float64
+	sum       float64
+	window    int
+}
+
+func ALMA(source types.Float64Source, window int, offset float64, sigma int) *ALMAStream {
+	checkWindow(window)
+
+	s := &ALMAStream{
+		Float64Series: types.NewFloat64Series(),
+		rawValues:     types.NewQueue(window),
+		weights:       make([]float64, window),
+		window:        window,
+	}
+
+	m := offset * float64(window-1)
+	sig := float64(window) / float64(sigma)
+	for i := 0; i < window; i++ {
+		diff := float64(i) - m
+		wt := math.Exp(-diff * diff / 2. / sig / sig)
+		s.sum += wt
+		s.weights[i] = wt
+	}
+
+	s.Bind(source, s)
+	return s
+}
+
+func (s *ALMAStream) Calculate(v float64) float64 {
+	s.rawValues.Update(v)
+	if s.rawValues.Length() < s.window {
+		return v
+	}
+
+	// the weights are ordered from the oldest value to the latest value,
+	// so the peak of the distribution is close to the latest value when the offset is close to 1
+	var value float64
+	for i := 0; i < s.window; i++ {
+		value += s.weights[s.window-i-1] * s.rawValues.Last(i)
+	}
+	return value / s.sum
+}
+
+func (s *ALMAStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
@@ -3,10 +3,12 @@ package indicatorv2
 type ATRStream struct {
 	// embedded struct
 	*RMAStream
+
+	tr *TRStream
 }
 
 func ATR2(source KLineSubscription, window int) *ATRStream {
 	tr := TR2(source)
 	rma := RMA2(tr, window, true)
-	return &ATRStream{RMAStream: rma}
+	return &ATRStream{RMAStream: rma, tr: tr}
 }
@@ -0,0 +1,79 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const MaxNumOfBooks = 1_000
+
+// BookStream pushes the order book copies of the stream order book updates to the subscribers,
+// the book features like BookImbalance and MicroPrice are calculated from the book stream.
+//
+//go:generate callbackgen -type BookStream
+type BookStream struct {
+	updateCallbacks []func(book types.OrderBook)
+
+	depth int
+	books []types.OrderBook
+}
+
+func (s *BookStream) Length() int {
+	return len(s.books)
+}
+
+func (s *BookStream) Last(i int) types.OrderBook {
+	l := len(s.books)
+	if i < 0 || l-1-i < 0 {
+		return nil
+	}
+
+	return s.books[l-1-i]
+}
+
+// AddSubscriber adds the subscriber function and push historical books to the subscriber
+func (s *BookStream) AddSubscriber(f func(book types.OrderBook)) {
+	s.OnUpdate(f)
+
+	if len(s.books) == 0 {
+		return
+	}
+
+	// push historical books to the subscriber
+	for _, book := range s.books {
+		f(book)
+	}
+}
+
+// Push pushes the book to the subscribers, it's used for feeding the recorded books in back-testing
+func (s *BookStream) Push(book types.OrderBook) {
+	s.books = append(s.books, book)
+	if len(s.books) > MaxNumOfBooks {
+		s.books = s.books[len(s.books)-MaxNumOfBooks:]
+	}
+
+	s.EmitUpdate(book)
+}
+
+// Books creates a book stream bound to the stream order book, the book is copied with the given depth
+// on every snapshot and update, zero depth copies the whole book.
+func Books(source *types.StreamOrderBook, depth int) *BookStream {
+	s := &BookStream{depth: depth}
+
+	if source == nil {
+		return s
+	}
+
+	source.OnSnapshot(func(_ types.SliceOrderBook) {
+		s.Push(source.CopyDepth(s.depth))
+	})
+	source.OnUpdate(func(_ types.SliceOrderBook) {
+		s.Push(source.CopyDepth(s.depth))
+	})
+	return s
+}
+
+type BookSubscription interface {
+	AddSubscriber(f func(book types.OrderBook))
+	Length() int
+	Last(i int) types.OrderBook
+}
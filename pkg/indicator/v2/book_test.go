@@ -0,0 +1,170 @@
+package indicatorv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestBook(t time.Time, bids, asks [][2]float64) *types.SliceOrderBook {
+	book := types.NewSliceOrderBook("BTCUSDT")
+	book.Load(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   t,
+		Bids:   newTestPriceVolumes(bids),
+		Asks:   newTestPriceVolumes(asks),
+	})
+	return book
+}
+
+func newTestPriceVolumes(levels [][2]float64) (pvs types.PriceVolumeSlice) {
+	for _, level := range levels {
+		pvs = append(pvs, types.PriceVolume{
+			Price:  fixedpoint.NewFromFloat(level[0]),
+			Volume: fixedpoint.NewFromFloat(level[1]),
+		})
+	}
+	return pvs
+}
+
+func TestBookFeatures(t *testing.T) {
+	now := time.Now()
+	books := Books(nil, 0)
+
+	imbalance := BookImbalance(books, 2)
+	microPrice := MicroPrice(books)
+	bidDepth := BookDepth(books, types.SideTypeBuy, 10)
+	askVWAP := BookVWAP(books, types.SideTypeSell, fixedpoint.NewFromFloat(3))
+
+	books.Push(newTestBook(now,
+		[][2]float64{{100, 3}, {99.95, 1}, {99, 10}},
+		[][2]float64{{100.1, 1}, {100.2, 3}}))
+
+	// (4 - 4) / 8
+	assert.InDelta(t, 0.0, imbalance.Last(0), 1e-9)
+
+	// (100 * 1 + 100.1 * 3) / 4
+	assert.InDelta(t, 100.075, microPrice.Last(0), 1e-6)
+
+	// mid 100.05, 10 bps = 0.10005, the levels >= 99.94995 are counted
+	assert.InDelta(t, 4.0, bidDepth.Last(0), 1e-9)
+
+	// (100.1 * 1 + 100.2 * 2) / 3
+	assert.InDelta(t, 100.166666, askVWAP.Last(0), 1e-6)
+
+	// not enough depth for the vwap
+	books.Push(newTestBook(now,
+		[][2]float64{{100, 3}},
+		[][2]float64{{100.1, 1}}))
+	assert.Equal(t, 2, imbalance.Length())
+	assert.Equal(t, 1, askVWAP.Length())
+	assert.InDelta(t, 0.5, imbalance.Last(0), 1e-9)
+
+	// the late subscribers receive the historical books
+	late := MicroPrice(books)
+	assert.Equal(t, 2, late.Length())
+}
+
+func TestOFI(t *testing.T) {
+	now := time.Now()
+	books := Books(nil, 0)
+	ofi := OFI(books)
+
+	books.Push(newTestBook(now, [][2]float64{{100, 2}}, [][2]float64{{101, 2}}))
+	assert.Equal(t, 0, ofi.Length())
+
+	// bid queue grows by 1
+	books.Push(newTestBook(now, [][2]float64{{100, 3}}, [][2]float64{{101, 2}}))
+	require.Equal(t, 1, ofi.Length())
+	assert.InDelta(t, 1.0, ofi.Last(0), 1e-9)
+
+	// ask price moves down with the new queue of 4
+	books.Push(newTestBook(now, [][2]float64{{100, 3}}, [][2]float64{{100.5, 4}}))
+	assert.InDelta(t, -4.0, ofi.Last(0), 1e-9)
+
+	// bid price moves down, the previous bid queue is removed
+	books.Push(newTestBook(now, [][2]float64{{99.5, 1}}, [][2]float64{{100.5, 4}}))
+	assert.InDelta(t, -3.0, ofi.Last(0), 1e-9)
+}
+
+func TestQueueDepletion(t *testing.T) {
+	now := time.Now()
+	books := Books(nil, 0)
+	bidDepletion := QueueDepletion(books, types.SideTypeBuy)
+	askDepletion := QueueDepletion(books, types.SideTypeSell)
+
+	books.Push(newTestBook(now, [][2]float64{{100, 10}}, [][2]float64{{101, 5}}))
+	assert.Equal(t, 0, bidDepletion.Length())
+
+	// 4 bids are filled in 2 seconds, the ask queue grows
+	books.Push(newTestBook(now.Add(2*time.Second), [][2]float64{{100, 6}}, [][2]float64{{101, 8}}))
+	require.Equal(t, 1, bidDepletion.Length())
+	assert.InDelta(t, 2.0, bidDepletion.Last(0), 1e-9)
+	assert.InDelta(t, 0.0, askDepletion.Last(0), 1e-9)
+
+	// the ask queue is depleted and the best ask moves up
+	books.Push(newTestBook(now.Add(4*time.Second), [][2]float64{{100, 6}}, [][2]float64{{102, 1}}))
+	assert.InDelta(t, 4.0, askDepletion.Last(0), 1e-9)
+	assert.InDelta(t, 0.0, bidDepletion.Last(0), 1e-9)
+}
+
+func TestBooks(t *testing.T) {
+	now := time.Now()
+	stream := types.NewStandardStream()
+	streamBook := types.NewStreamBook("BTCUSDT")
+	streamBook.BindStream(&stream)
+
+	books := Books(streamBook, 1)
+	imbalance := BookImbalance(books, 0)
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Bids:   newTestPriceVolumes([][2]float64{{100, 3}, {99, 100}}),
+		Asks:   newTestPriceVolumes([][2]float64{{101, 1}}),
+	})
+
+	require.Equal(t, 1, books.Length())
+	assert.Len(t, books.Last(0).SideBook(types.SideTypeBuy), 1)
+
+	// the book is copied with depth 1
+	assert.InDelta(t, 0.5, imbalance.Last(0), 1e-9)
+
+	stream.EmitBookUpdate(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   now,
+		Asks:   newTestPriceVolumes([][2]float64{{101, 3}}),
+	})
+	assert.Equal(t, 2, books.Length())
+	assert.InDelta(t, 0.0, imbalance.Last(0), 1e-9)
+}
+
+func TestBookFeatures_Truncate(t *testing.T) {
+	now := time.Now()
+	books := Books(nil, 0)
+
+	streams := map[string]interface{ Length() int }{
+		"imbalance":      BookImbalance(books, 0),
+		"microprice":     MicroPrice(books),
+		"depth":          BookDepth(books, types.SideTypeBuy, 10),
+		"vwap":           BookVWAP(books, types.SideTypeSell, fixedpoint.One),
+		"ofi":            OFI(books),
+		"queueDepletion": QueueDepletion(books, types.SideTypeBuy),
+	}
+
+	for i := 0; i < MaxNumOfBooks*2; i++ {
+		books.Push(newTestBook(now.Add(time.Duration(i)*time.Second),
+			[][2]float64{{100, float64(i%5 + 1)}},
+			[][2]float64{{101, float64(i%3 + 1)}}))
+	}
+
+	for name, s := range streams {
+		assert.LessOrEqual(t, s.Length(), MaxNumOfBooks, name)
+		assert.Greater(t, s.Length(), 0, name)
+	}
+}
@@ -0,0 +1,59 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// BookDepthStream calculates the base volume of the side book within the given basis points from the mid price
+type BookDepthStream struct {
+	*types.Float64Series
+
+	side types.SideType
+	bps  fixedpoint.Value
+}
+
+// BookDepth creates the depth series, e.g., BookDepth(books, types.SideTypeBuy, 10) sums the bid volume
+// priced within 10 bps below the mid price
+func BookDepth(source BookSubscription, side types.SideType, bps float64) *BookDepthStream {
+	s := &BookDepthStream{
+		Float64Series: types.NewFloat64Series(),
+		side:          side,
+		bps:           fixedpoint.NewFromFloat(bps),
+	}
+
+	source.AddSubscriber(func(book types.OrderBook) {
+		if v, ok := s.Calculate(book); ok {
+			s.PushAndEmit(v)
+			s.Truncate()
+		}
+	})
+	return s
+}
+
+func (s *BookDepthStream) Calculate(book types.OrderBook) (float64, bool) {
+	bid, ask, ok := topOfBook(book)
+	if !ok {
+		return 0.0, false
+	}
+
+	mid := midPrice(bid, ask)
+	delta := mid.Mul(s.bps).Div(fixedpoint.NewFromInt(10_000))
+
+	depth := fixedpoint.Zero
+	for _, pv := range book.SideBook(s.side) {
+		if s.side == types.SideTypeBuy && pv.Price.Compare(mid.Sub(delta)) < 0 {
+			break
+		} else if s.side == types.SideTypeSell && pv.Price.Compare(mid.Add(delta)) > 0 {
+			break
+		}
+
+		depth = depth.Add(pv.Volume)
+	}
+
+	return depth.Float64(), true
+}
+
+func (s *BookDepthStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfBooks)
+}
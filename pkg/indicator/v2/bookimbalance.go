@@ -0,0 +1,55 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// BookImbalanceStream calculates the volume imbalance of the top N levels,
+// (bidVolume - askVolume) / (bidVolume + askVolume), ranges from -1 to 1.
+type BookImbalanceStream struct {
+	*types.Float64Series
+
+	levels int
+}
+
+// BookImbalance creates the top N levels imbalance, zero levels uses the whole book
+func BookImbalance(source BookSubscription, levels int) *BookImbalanceStream {
+	s := &BookImbalanceStream{
+		Float64Series: types.NewFloat64Series(),
+		levels:        levels,
+	}
+
+	source.AddSubscriber(func(book types.OrderBook) {
+		s.PushAndEmit(s.Calculate(book))
+		s.Truncate()
+	})
+	return s
+}
+
+func (s *BookImbalanceStream) Calculate(book types.OrderBook) float64 {
+	bidVolume := book.SideBook(types.SideTypeBuy).CopyDepth(s.levels).SumDepth()
+	askVolume := book.SideBook(types.SideTypeSell).CopyDepth(s.levels).SumDepth()
+
+	total := bidVolume.Add(askVolume)
+	if total.IsZero() {
+		return 0.0
+	}
+
+	return bidVolume.Sub(askVolume).Div(total).Float64()
+}
+
+func (s *BookImbalanceStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfBooks)
+}
+
+// topOfBook returns the best bid and the best ask, the book without both sides is skipped by the book features
+func topOfBook(book types.OrderBook) (bid, ask types.PriceVolume, ok bool) {
+	bid, ok1 := book.BestBid()
+	ask, ok2 := book.BestAsk()
+	return bid, ask, ok1 && ok2
+}
+
+func midPrice(bid, ask types.PriceVolume) fixedpoint.Value {
+	return bid.Price.Add(ask.Price).Div(fixedpoint.Two)
+}
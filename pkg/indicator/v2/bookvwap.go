@@ -0,0 +1,47 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// BookVWAPStream calculates the average fill price of taking the given quantity from the side book,
+// the books without enough depth are skipped.
+type BookVWAPStream struct {
+	*types.Float64Series
+
+	side     types.SideType
+	quantity fixedpoint.Value
+}
+
+// BookVWAP creates the VWAP-to-size series, side is the side book to take,
+// e.g., types.SideTypeSell for the price of buying the quantity
+func BookVWAP(source BookSubscription, side types.SideType, quantity fixedpoint.Value) *BookVWAPStream {
+	s := &BookVWAPStream{
+		Float64Series: types.NewFloat64Series(),
+		side:          side,
+		quantity:      quantity,
+	}
+
+	source.AddSubscriber(func(book types.OrderBook) {
+		if v, ok := s.Calculate(book); ok {
+			s.PushAndEmit(v)
+			s.Truncate()
+		}
+	})
+	return s
+}
+
+func (s *BookVWAPStream) Calculate(book types.OrderBook) (float64, bool) {
+	pvs := book.SideBook(s.side)
+	if pvs.SumDepth().Compare(s.quantity) < 0 {
+		return 0.0, false
+	}
+
+	price := pvs.AverageDepthPrice(s.quantity)
+	return price.Float64(), !price.IsZero()
+}
+
+func (s *BookVWAPStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfBooks)
+}
@@ -0,0 +1,198 @@
+package indicatorv2
+
+import (
+	"math"
+	"sort"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// maxPendingPairValues is the max number of the values of a series waiting for the value of the other series
+// at the same kline time, the oldest one is dropped when it's exceeded
+const maxPendingPairValues = 100
+
+// pairStream pairs the values of 2 series by the start time of the klines that updated them,
+// and calculates the value when both of them are updated for the same kline time.
+// The values without a pair, e.g., the kline of one symbol is missing, are dropped.
+type pairStream struct {
+	*types.Float64Series
+
+	a, b   floats.Slice
+	window int
+
+	// pending is the values waiting for the other series, keyed by the kline start time in milliseconds
+	pending [2]map[int64]float64
+
+	// last is the kline start time of the last paired values
+	last int64
+
+	calculate func(a, b floats.Slice) float64
+}
+
+func newPairStream(
+	sourceA KLineSubscription, a types.Series, sourceB KLineSubscription, b types.Series, window int,
+	calculate func(a, b floats.Slice) float64,
+) *pairStream {
+	checkWindow(window)
+	s := &pairStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		pending:       [2]map[int64]float64{{}, {}},
+		calculate:     calculate,
+	}
+
+	// the series are already calculated from the historical klines, pair the values from the end like Align
+	history := [2]map[int64]float64{alignedValues(sourceA, a), alignedValues(sourceB, b)}
+	times := make([]int64, 0, len(history[0]))
+	for t := range history[0] {
+		if _, ok := history[1][t]; ok {
+			times = append(times, t)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	for _, t := range times {
+		s.pair(t, history[0][t], history[1][t])
+	}
+
+	s.subscribe(0, sourceA, a)
+	s.subscribe(1, sourceB, b)
+	return s
+}
+
+// alignedValues returns the values of the series calculated from the historical klines by the kline start time
+func alignedValues(source KLineSubscription, series types.Series) map[int64]float64 {
+	n := source.Length()
+	if l := series.Length(); l < n {
+		n = l
+	}
+
+	values := make(map[int64]float64, n)
+	for i := 0; i < n; i++ {
+		values[source.Last(i).StartTime.Time().UnixMilli()] = series.Last(i)
+	}
+	return values
+}
+
+// subscribe subscribes the kline source of a series, the series must be subscribed before,
+// so that it's updated before the pair stream reads its value
+func (s *pairStream) subscribe(leg int, source KLineSubscription, series types.Series) {
+	backfilling := true
+	source.AddSubscriber(func(k types.KLine) {
+		if backfilling || series.Length() == 0 {
+			return
+		}
+
+		s.push(leg, k.StartTime.Time().UnixMilli(), series.Last(0))
+	})
+	backfilling = false
+}
+
+func (s *pairStream) push(leg int, t int64, v float64) {
+	if t <= s.last {
+		return
+	}
+
+	other := s.pending[1-leg]
+	if o, ok := other[t]; ok {
+		if leg == 0 {
+			s.pair(t, v, o)
+		} else {
+			s.pair(t, o, v)
+		}
+		return
+	}
+
+	pending := s.pending[leg]
+	pending[t] = v
+	if len(pending) > maxPendingPairValues {
+		oldest := t
+		for pt := range pending {
+			if pt < oldest {
+				oldest = pt
+			}
+		}
+		delete(pending, oldest)
+	}
+}
+
+func (s *pairStream) pair(t int64, a, b float64) {
+	s.last = t
+	for _, pending := range s.pending {
+		for pt := range pending {
+			if pt <= t {
+				delete(pending, pt)
+			}
+		}
+	}
+
+	s.a.Push(a)
+	s.b.Push(b)
+	s.PushAndEmit(s.calculate(s.a.Tail(s.window), s.b.Tail(s.window)))
+
+	s.a = s.a.Truncate(s.window)
+	s.b = s.b.Truncate(s.window)
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
+
+// covariance returns the population covariance of a and b, and the population variances of them
+func covariance(a, b floats.Slice) (cov, varA, varB float64) {
+	n := float64(len(a))
+	if n == 0 {
+		return 0, 0, 0
+	}
+
+	meanA, meanB := a.Mean(), b.Mean()
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+
+	return cov / n, varA / n, varB / n
+}
+
+// CorrelationStream is the rolling Pearson correlation coefficient of 2 series
+type CorrelationStream struct {
+	*pairStream
+}
+
+// Correlation creates the rolling correlation of the series a and b calculated from the given kline sources,
+// the values are paired by the kline time, and the value is 0 if any of them is constant.
+// The series must be created before calling Correlation, so that they're updated before it reads their values.
+func Correlation(sourceA KLineSubscription, a types.Series, sourceB KLineSubscription, b types.Series, window int) *CorrelationStream {
+	return &CorrelationStream{
+		pairStream: newPairStream(sourceA, a, sourceB, b, window, func(a, b floats.Slice) float64 {
+			cov, varA, varB := covariance(a, b)
+			if varA == 0 || varB == 0 {
+				return 0
+			}
+
+			return cov / math.Sqrt(varA*varB)
+		}),
+	}
+}
+
+// BetaStream is the rolling beta of a series against a benchmark series, cov(a, b) / var(b)
+type BetaStream struct {
+	*pairStream
+}
+
+// Beta creates the rolling beta of the series against the benchmark, usually both of them are returns,
+// the values are paired by the kline time like Correlation, and the value is 0 if the benchmark is constant
+func Beta(
+	source KLineSubscription, series types.Series, benchmarkSource KLineSubscription, benchmark types.Series, window int,
+) *BetaStream {
+	return &BetaStream{
+		pairStream: newPairStream(source, series, benchmarkSource, benchmark, window, func(a, b floats.Slice) float64 {
+			cov, _, varB := covariance(a, b)
+			if varB == 0 {
+				return 0
+			}
+
+			return cov / varB
+		}),
+	}
+}
@@ -0,0 +1,123 @@
+package indicatorv2
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func closedKLine(minute int, price float64) types.KLine {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(minute) * time.Minute)
+	return types.KLine{
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+		Close:     fixedpoint.NewFromFloat(price),
+		Closed:    true,
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	sourceA, sourceB := &KLineStream{}, &KLineStream{}
+	a, b := ClosePrices(sourceA), ClosePrices(sourceB)
+	corr := Correlation(sourceA, a, sourceB, b, 5)
+	beta := Beta(sourceB, b, sourceA, a, 5)
+
+	minute := 0
+	push := func(va, vb float64) {
+		sourceA.BackFill([]types.KLine{closedKLine(minute, va)})
+		sourceB.BackFill([]types.KLine{closedKLine(minute, vb)})
+		minute++
+	}
+
+	for i := 1; i <= 5; i++ {
+		sourceA.BackFill([]types.KLine{closedKLine(minute, float64(i))})
+		assert.Equal(t, i-1, corr.Length(), "the value is calculated when both of the series are updated")
+		sourceB.BackFill([]types.KLine{closedKLine(minute, -2.0*float64(i))})
+		minute++
+	}
+
+	assert.InDelta(t, -1.0, corr.Last(0), 1e-9)
+	assert.InDelta(t, -2.0, beta.Last(0), 1e-9)
+
+	for _, v := range []float64{3, 1, 4, 1, 5} {
+		push(v, 1)
+	}
+	assert.Equal(t, 0.0, corr.Last(0), "the correlation with a constant series")
+	assert.Equal(t, 0.0, beta.Last(0))
+
+	for _, v := range [][2]float64{{1, 2}, {2, 1}, {3, 4}, {4, 3}, {5, 5}} {
+		push(v[0], v[1])
+	}
+	assert.InDelta(t, 0.8, corr.Last(0), 1e-9)
+	assert.InDelta(t, 0.8, beta.Last(0), 1e-9)
+}
+
+func TestCorrelation_PairByKLineTime(t *testing.T) {
+	sourceA, sourceB := &KLineStream{}, &KLineStream{}
+	a, b := ClosePrices(sourceA), ClosePrices(sourceB)
+	corr := Correlation(sourceA, a, sourceB, b, 3)
+
+	// the kline of minute 1 is missing in b, and b is delivered after a
+	sourceA.BackFill([]types.KLine{closedKLine(0, 1), closedKLine(1, 100), closedKLine(2, 2)})
+	assert.Equal(t, 0, corr.Length())
+
+	sourceB.BackFill([]types.KLine{closedKLine(0, 2), closedKLine(2, 4)})
+	assert.Equal(t, 2, corr.Length(), "the value of minute 1 is dropped")
+	assert.InDelta(t, 1.0, corr.Last(0), 1e-9)
+
+	// b is delivered before a
+	sourceB.BackFill([]types.KLine{closedKLine(3, 6)})
+	assert.Equal(t, 2, corr.Length())
+	sourceA.BackFill([]types.KLine{closedKLine(3, 3)})
+	assert.Equal(t, 3, corr.Length())
+	assert.InDelta(t, 1.0, corr.Last(0), 1e-9, "the pairs are (1, 2), (2, 4) and (3, 6)")
+
+	// a late kline of a paired time is ignored
+	sourceB.BackFill([]types.KLine{closedKLine(1, -100)})
+	sourceA.BackFill([]types.KLine{closedKLine(4, 4)})
+	sourceB.BackFill([]types.KLine{closedKLine(4, 8)})
+	assert.Equal(t, 4, corr.Length())
+	assert.InDelta(t, 1.0, corr.Last(0), 1e-9)
+}
+
+func TestCorrelation_History(t *testing.T) {
+	kLines := buildTestKLines(50)
+	newStreams := func() (*KLineStream, *PriceStream, *KLineStream, *PriceStream) {
+		sourceA, sourceB := &KLineStream{}, &KLineStream{}
+		return sourceA, ClosePrices(sourceA), sourceB, HighPrices(sourceB)
+	}
+
+	liveA, liveCloses, liveB, liveHighs := newStreams()
+	live := Correlation(liveA, liveCloses, liveB, liveHighs, 10)
+
+	sourceA, closes, sourceB, highs := newStreams()
+	for _, s := range []*KLineStream{liveA, sourceA} {
+		s.BackFill(kLines[:40])
+	}
+	for _, s := range []*KLineStream{liveB, sourceB} {
+		s.BackFill(kLines[5:40])
+	}
+
+	corr := Correlation(sourceA, closes, sourceB, highs, 10)
+	assert.Equal(t, 35, corr.Length(), "the history is paired by the kline time")
+
+	for _, s := range []*KLineStream{liveA, liveB, sourceA, sourceB} {
+		s.BackFill(kLines[40:])
+	}
+	assert.Equal(t, 45, corr.Length())
+	assert.Equal(t, live.Slice, corr.Slice, "the same values as the stream created before the history")
+
+	var a, b floats.Slice
+	for _, k := range kLines[40:] {
+		a.Push(k.Close.Float64())
+		b.Push(k.High.Float64())
+	}
+	cov, varA, varB := covariance(a, b)
+	assert.InDelta(t, cov/math.Sqrt(varA*varB), corr.Last(0), 1e-9)
+}
@@ -0,0 +1,29 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// DEMAStream is the Double Exponential Moving Average: 2 * EMA - EMA(EMA)
+type DEMAStream struct {
+	*types.Float64Series
+
+	ema1, ema2 *EWMAStream
+}
+
+func DEMA(source types.Float64Source, window int) *DEMAStream {
+	ema1 := EWMA2(source, window)
+	s := &DEMAStream{
+		Float64Series: types.NewFloat64Series(),
+		ema1:          ema1,
+		ema2:          EWMA2(ema1, window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *DEMAStream) Calculate(_ float64) float64 {
+	return 2.0*s.ema1.Last(0) - s.ema2.Last(0)
+}
+
+func (s *DEMAStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
@@ -0,0 +1,86 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DMIStream is the Directional Movement Index, the embedded series is the ADX smoothed with the ADX smoothing window,
+// and the DIPlus and DIMinus are the positive and the negative directional indicators.
+// The values are emitted after the window is filled.
+type DMIStream struct {
+	*RMAStream
+
+	DIPlus, DIMinus *types.Float64Series
+
+	window            int
+	atr               *ATRStream
+	dmp, dmn          *RMAStream
+	prevHigh, prevLow float64
+	count             int
+}
+
+func DMI(source KLineSubscription, window, adxSmoothing int) *DMIStream {
+	var (
+		atr = ATR2(source, window)
+		dx  = types.NewFloat64Series()
+		s   = &DMIStream{
+			RMAStream: RMA2(dx, adxSmoothing, true),
+			DIPlus:    types.NewFloat64Series(),
+			DIMinus:   types.NewFloat64Series(),
+			window:    window,
+			atr:       atr,
+			dmp:       RMA2(nil, window, true),
+			dmn:       RMA2(nil, window, true),
+		}
+	)
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low := k.High.Float64(), k.Low.Float64()
+		s.count++
+		if s.count == 1 {
+			s.prevHigh, s.prevLow = high, low
+			return
+		}
+
+		up, dn := high-s.prevHigh, s.prevLow-low
+		s.prevHigh, s.prevLow = high, low
+
+		pos := 0.0
+		if up > dn && up > 0. {
+			pos = up
+		}
+
+		neg := 0.0
+		if dn > up && dn > 0. {
+			neg = dn
+		}
+
+		s.dmp.PushAndEmit(s.dmp.Calculate(pos))
+		s.dmn.PushAndEmit(s.dmn.Calculate(neg))
+		s.dmp.Truncate()
+		s.dmn.Truncate()
+
+		// the window is filled with the directional movements after the first kline
+		if s.count <= s.window {
+			return
+		}
+
+		ratio := 100. / s.atr.Last(0)
+		dmp, dmn := s.dmp.Last(0), s.dmn.Last(0)
+		s.DIPlus.PushAndEmit(ratio * dmp)
+		s.DIMinus.PushAndEmit(ratio * dmn)
+		s.DIPlus.Slice = s.DIPlus.Slice.Truncate(MaxNumOfRMA)
+		s.DIMinus.Slice = s.DIMinus.Slice.Truncate(MaxNumOfRMA)
+
+		if dmp+dmn == 0 {
+			dx.PushAndEmit(0)
+		} else {
+			dx.PushAndEmit(100. * math.Abs(dmp-dmn) / (dmp + dmn))
+		}
+		dx.Slice = dx.Slice.Truncate(MaxNumOfRMA)
+		s.Truncate()
+	})
+	return s
+}
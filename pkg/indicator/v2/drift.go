@@ -0,0 +1,54 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DriftStream is the drift of the log returns, the mean of the log returns minus half of their variance.
+// The drift is 0 before the window is filled.
+type DriftStream struct {
+	*types.Float64Series
+
+	changes *types.Queue
+	window  int
+	last    float64
+}
+
+func Drift(source types.Float64Source, window int) *DriftStream {
+	checkWindow(window)
+
+	s := &DriftStream{
+		Float64Series: types.NewFloat64Series(),
+		changes:       types.NewQueue(window),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *DriftStream) Calculate(v float64) float64 {
+	if s.last == 0 {
+		s.last = v
+		return 0
+	}
+
+	var change float64
+	if v != 0 {
+		change = math.Log(v / s.last)
+		s.last = v
+	}
+
+	s.changes.Update(change)
+	if s.changes.Length() < s.window {
+		return 0
+	}
+
+	stdev := types.Stdev(s.changes, s.window)
+	return types.Mean(s.changes, s.window) - stdev*stdev*0.5
+}
+
+func (s *DriftStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
@@ -0,0 +1,47 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+const DefaultEMVScale = 100000000.0
+
+// EMVStream is the Ease of Movement, the SMA of the mid-price moves divided by the box ratio,
+// box ratio = volume / scale / (high - low)
+type EMVStream struct {
+	*SMAStream
+
+	scale        float64
+	prevH, prevL float64
+}
+
+// EMV creates the ease of movement stream, the default scale is used if the scale is zero
+func EMV(source KLineSubscription, window int, scale float64) *EMVStream {
+	if scale == 0 {
+		scale = DefaultEMVScale
+	}
+
+	ratio := types.NewFloat64Series()
+	s := &EMVStream{
+		SMAStream: SMA(ratio, window),
+		scale:     scale,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low := k.High.Float64(), k.Low.Float64()
+		if s.prevH == 0 {
+			s.prevH, s.prevL = high, low
+			return
+		}
+
+		distanceMoved := (high+low)/2. - (s.prevH+s.prevL)/2.
+		s.prevH, s.prevL = high, low
+		if high == low || k.Volume.IsZero() {
+			ratio.PushAndEmit(0)
+			return
+		}
+
+		boxRatio := k.Volume.Float64() / s.scale / (high - low)
+		ratio.PushAndEmit(distanceMoved / boxRatio)
+		ratio.Slice = ratio.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
@@ -2,6 +2,8 @@ package indicatorv2
 
 import "github.com/c9s/bbgo/pkg/types"
 
+const MaxNumOfEWMA = 5_000
+
 type EWMAStream struct {
 	*types.Float64Series
 
@@ -20,11 +22,12 @@ func EWMA2(source types.Float64Source, window int) *EWMAStream {
 }
 
 func (s *EWMAStream) Calculate(v float64) float64 {
-	last := s.Slice.Last(0)
-	if last == 0.0 {
+	// the first value is the seed, a zero value can't tell whether the EMA is seeded,
+	// e.g., the first MACD value is zero
+	if s.Slice.Length() == 0 {
 		return v
 	}
 
 	m := s.multiplier
-	return (1.0-m)*last + m*v
+	return (1.0-m)*s.Slice.Last(0) + m*v
 }
@@ -0,0 +1,253 @@
+// Package expr compiles the indicator expressions into the indicator/v2 series graphs.
+//
+// An expression is built from the price series (open, high, low, close, volume, hlc3), the numbers,
+// the indicator functions, e.g., ema(close, 9), and the operators:
+//
+//	arithmetic:  + - * /
+//	comparison:  < <= > >= == !=
+//	logical:     and or not (or && || !)
+//
+// The multi-output indicators expose their outputs as fields, e.g., boll(close, 20, 2).down,
+// and the previous values can be referred by the offset, e.g., close[1] is the previous close price.
+// The comparisons and the logical operators return 1 for true and 0 for false.
+// The values are NaN until the history is long enough for the offsets and the windows, and the comparisons and the
+// crosses with them are false, so a condition never fires on the missing history. For example:
+//
+//	cross(ema(close, 9), ema(close, 21))
+//	rsi(close, 14) < 30 and close > boll(close, 20, 2).down
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const maxNumOfValues = 5_000
+
+// Expression is the compiled expression, it's updated on every kline of the source after the indicators are updated
+type Expression struct {
+	*types.Float64Series
+
+	source string
+	root   node
+}
+
+// Compile parses the expression and builds the indicator graph on the kline source.
+// The expression is evaluated on the historical klines of the source as well.
+func Compile(source indicatorv2.KLineSubscription, expression string) (*Expression, error) {
+	ast, err := parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse expression %q: %w", expression, err)
+	}
+
+	c := newCompiler(source)
+	root, err := c.compile(ast)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile expression %q: %w", expression, err)
+	}
+
+	if r, ok := root.(*recordNode); ok {
+		root = r.fields[r.defaultField]
+	}
+
+	return &Expression{
+		Float64Series: c.derive(root),
+		source:        expression,
+		root:          root,
+	}, nil
+}
+
+// MustCompile is like Compile but panics if the expression can not be compiled
+func MustCompile(source indicatorv2.KLineSubscription, expression string) *Expression {
+	e, err := Compile(source, expression)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Truthy returns true if the last value of the expression is true, i.e., non-zero
+func (e *Expression) Truthy() bool {
+	return e.Length() > 0 && truthy(e.Last(0))
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+type compiler struct {
+	source indicatorv2.KLineSubscription
+
+	// cache stores the compiled nodes by the expression string, so that the same indicator is only built once
+	cache map[string]node
+}
+
+func newCompiler(source indicatorv2.KLineSubscription) *compiler {
+	return &compiler{
+		source: source,
+		cache:  make(map[string]node),
+	}
+}
+
+func (c *compiler) compile(ast astNode) (node, error) {
+	key := ast.String()
+	if n, ok := c.cache[key]; ok {
+		return n, nil
+	}
+
+	n, err := c.compileNode(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache[key] = n
+	return n, nil
+}
+
+func (c *compiler) compileNode(ast astNode) (node, error) {
+	switch a := ast.(type) {
+	case *numberLit:
+		return &constNode{value: a.value}, nil
+
+	case *identExpr:
+		mapper, ok := priceMappers[a.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown series %q, available series: %s", a.name, strings.Join(priceNames(), ", "))
+		}
+		return &seriesNode{series: indicatorv2.Price(c.source, mapper)}, nil
+
+	case *fieldExpr:
+		target, err := c.compile(a.target)
+		if err != nil {
+			return nil, err
+		}
+
+		r, ok := target.(*recordNode)
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %q", a.target, a.field)
+		}
+
+		field, ok := r.fields[a.field]
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %q, available fields: %s", a.target, a.field, strings.Join(r.fieldNames(), ", "))
+		}
+		return field, nil
+
+	case *indexExpr:
+		target, err := c.compile(a.target)
+		if err != nil {
+			return nil, err
+		}
+		return &offsetNode{x: target, offset: a.offset}, nil
+
+	case *unaryExpr:
+		x, err := c.compile(a.x)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: a.op, x: x}, nil
+
+	case *binaryExpr:
+		l, err := c.compile(a.l)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := c.compile(a.r)
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: a.op, l: l, r: r}, nil
+
+	case *callExpr:
+		f, ok := functions[a.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q", a.name)
+		}
+
+		args := make([]node, len(a.args))
+		for i, arg := range a.args {
+			n, err := c.compile(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = n
+		}
+
+		n, err := f(c, &arguments{nodes: args})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.name, err)
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("unsupported expression %s", ast)
+}
+
+// series returns the series of the node, a derived series is created if the node is not an indicator series
+func (c *compiler) series(n node) types.Float64Source {
+	switch t := n.(type) {
+	case *seriesNode:
+		return t.series
+	case *recordNode:
+		return c.series(t.fields[t.defaultField])
+	}
+
+	return c.derive(n)
+}
+
+// derive creates the series that is updated with the node value on every kline,
+// the historical values are evaluated from the historical values of the upstream series.
+func (c *compiler) derive(n node) *types.Float64Series {
+	s := types.NewFloat64Series()
+
+	numOfHistory := c.source.Length()
+	c.source.AddSubscriber(func(k types.KLine) {
+		i := 0
+		if numOfHistory > 0 {
+			numOfHistory--
+			i = numOfHistory
+		}
+
+		s.PushAndEmit(n.eval(i))
+		s.Slice = s.Slice.Truncate(maxNumOfValues)
+	})
+	return s
+}
+
+func (r *recordNode) fieldNames() []string {
+	var names []string
+	for name := range r.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var priceMappers = map[string]types.KLineValueMapper{
+	"open":   types.KLineOpenPriceMapper,
+	"high":   types.KLineHighPriceMapper,
+	"low":    types.KLineLowPriceMapper,
+	"close":  types.KLineClosePriceMapper,
+	"volume": types.KLineVolumeMapper,
+	"hlc3":   types.KLineHLC3Mapper,
+}
+
+func priceNames() []string {
+	var names []string
+	for name := range priceMappers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check checks if the expression can be compiled, it can be used to validate the expressions in the config
+func Check(expression string) error {
+	_, err := Compile(&indicatorv2.KLineStream{}, expression)
+	return err
+}
@@ -0,0 +1,157 @@
+package expr
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestKLine(o, h, l, c float64) types.KLine {
+	return types.KLine{
+		Open:   fixedpoint.NewFromFloat(o),
+		High:   fixedpoint.NewFromFloat(h),
+		Low:    fixedpoint.NewFromFloat(l),
+		Close:  fixedpoint.NewFromFloat(c),
+		Volume: fixedpoint.One,
+	}
+}
+
+func emitCloses(stream *types.StandardStream, closes ...float64) {
+	for _, c := range closes {
+		stream.EmitKLineClosed(newTestKLine(c, c+1, c-1, c))
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"rsi(close,14) < 30 and close > boll(close,20,2).down", "((rsi(close,14) < 30) and (close > boll(close,20,2).down))"},
+		{"a || b && not c > d", "(a or (b and (not (c > d))))"},
+		{"-close[1] + !x", "((- close[1]) + (not x))"},
+		{"CROSS(EMA(close, 9), ema(close, 21))", "cross(ema(close,9),ema(close,21))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := parse(tt.input)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, node.String())
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{"", "1 +", "ema(close, 9", "close[-1]", "close.", "1 $ 2", "and 1"} {
+		_, err := parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := indicatorv2.KLines(stream, "", "")
+
+	for _, input := range []string{"foo", "bar(close)", "ema(close)", "ema(close, close)", "ema(close, 1.5)", "close.up", "boll(close, 20).foo"} {
+		_, err := Compile(kLines, input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := indicatorv2.KLines(stream, "", "")
+
+	ema := indicatorv2.EWMA2(indicatorv2.ClosePrices(kLines), 3)
+	boll := indicatorv2.BOLL(indicatorv2.ClosePrices(kLines), 5, 2)
+
+	spread := MustCompile(kLines, "(close - ema(close, 3)) * 2")
+	lower := MustCompile(kLines, "boll(close, 5, 2).down")
+	prevClose := MustCompile(kLines, "close[1]")
+	change := MustCompile(kLines, "change(close)")
+	nested := MustCompile(kLines, "sma(close - open, 2)")
+
+	emitCloses(stream, 10, 11, 12, 11, 13, 15, 14)
+
+	assert.InDelta(t, (14-ema.Last(0))*2, spread.Last(0), 1e-9)
+	assert.InDelta(t, boll.DownBand.Last(0), lower.Last(0), 1e-9)
+	assert.Equal(t, 15.0, prevClose.Last(0))
+	assert.Equal(t, -1.0, change.Last(0))
+	assert.Equal(t, 0.0, nested.Last(0))
+	assert.Equal(t, 7, spread.Length())
+}
+
+func TestCompile_Conditions(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := indicatorv2.KLines(stream, "", "")
+
+	crossOver := MustCompile(kLines, "crossover(close, sma(close, 3))")
+	crossUnder := MustCompile(kLines, "crossunder(close, sma(close, 3))")
+	cond := MustCompile(kLines, "close > 10 and not close > 12")
+
+	var signals []float64
+	crossOver.OnUpdate(func(v float64) {
+		signals = append(signals, v)
+	})
+
+	emitCloses(stream, 12, 11, 10, 9, 13, 14, 12, 8)
+	assert.Equal(t, []float64{0, 0, 0, 0, 1, 0, 0, 0}, signals)
+	assert.Equal(t, []float64{1, 1, 0, 0, 0, 0, 1, 0}, []float64(cond.Slice))
+	assert.Equal(t, 1.0, crossUnder.Last(1))
+	assert.False(t, crossUnder.Truthy())
+	assert.False(t, cond.Truthy())
+}
+
+func TestCompile_History(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := indicatorv2.KLines(stream, "", "")
+	emitCloses(stream, 1, 2, 3, 4)
+
+	// the expression compiled after the klines are received is evaluated on the historical klines
+	e := MustCompile(kLines, "close - close[1]")
+	assert.True(t, math.IsNaN(e.Slice[0]), "there is no previous close of the first kline")
+	assert.Equal(t, []float64{1, 1, 1}, []float64(e.Slice[1:]))
+
+	emitCloses(stream, 6)
+	assert.Equal(t, 2.0, e.Last(0))
+}
+
+func TestCompile_ShortHistory(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := indicatorv2.KLines(stream, "", "")
+
+	crossOver := MustCompile(kLines, "crossover(close, 30000)")
+	crossUnder := MustCompile(kLines, "crossunder(close, 30000)")
+	changed := MustCompile(kLines, "close[1] != close")
+	prevClose := MustCompile(kLines, "prev(close, 2)")
+	highest := MustCompile(kLines, "highest(close, 3)")
+	nested := MustCompile(kLines, "highest(close[1], 2) < close")
+
+	emitCloses(stream, 31000)
+	assert.False(t, crossOver.Truthy(), "the first kline doesn't cross without the previous close")
+	assert.False(t, crossUnder.Truthy())
+	assert.False(t, changed.Truthy(), "the comparison with the missing value is false")
+	assert.False(t, nested.Truthy())
+	assert.True(t, math.IsNaN(prevClose.Last(0)))
+	assert.True(t, math.IsNaN(highest.Last(0)), "the window is not filled")
+
+	emitCloses(stream, 29000, 30500)
+	assert.Equal(t, []float64{0, 0, 1}, []float64(crossOver.Slice))
+	assert.Equal(t, []float64{0, 1, 0}, []float64(crossUnder.Slice))
+	assert.Equal(t, 31000.0, prevClose.Last(0))
+	assert.True(t, math.IsNaN(highest.Last(1)))
+	assert.Equal(t, 31000.0, highest.Last(0))
+	assert.Equal(t, []float64{0, 0, 0}, []float64(nested.Slice))
+
+	emitCloses(stream, 32000)
+	assert.True(t, nested.Truthy())
+}
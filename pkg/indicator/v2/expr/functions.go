@@ -0,0 +1,445 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type function func(c *compiler, args *arguments) (node, error)
+
+// functions are the built-in functions of the expression language
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		// moving averages of a series
+		"sma":   seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.SMA(s, w) }),
+		"ema":   seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.EWMA2(s, w) }),
+		"ewma":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.EWMA2(s, w) }),
+		"rma":   seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.RMA2(s, w, true) }),
+		"smma":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.SMMA2(s, w) }),
+		"wwma":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.WWMA(s, w) }),
+		"dema":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.DEMA(s, w) }),
+		"tema":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.TEMA(s, w) }),
+		"zlema": seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.ZLEMA(s, w) }),
+		"hull":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.HULL(s, w) }),
+		"tma":   seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.TMA(s, w) }),
+		"gma":   seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.GMA(s, w) }),
+		"vidya": seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.VIDYA(s, w) }),
+		"alma":  almaFunc,
+		"till":  tillFunc,
+		"ssf":   ssfFunc,
+
+		// oscillators and statistics of a series
+		"rsi":    seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.RSI2(s, w) }),
+		"cci":    seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.CCI(s, w) }),
+		"stddev": seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.StdDev(s, w) }),
+		"fisher": seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.FisherTransform(s, w) }),
+		"drift":  seriesWindowFunc(func(s types.Float64Source, w int) types.Float64Source { return indicatorv2.Drift(s, w) }),
+		"tsi":    tsiFunc,
+		"boll":   bollFunc,
+		"macd":   macdFunc,
+
+		// kline indicators
+		"atr":        klineWindowFunc(func(k indicatorv2.KLineSubscription, w int) types.Float64Source { return indicatorv2.ATR2(k, w) }),
+		"atrp":       klineWindowFunc(func(k indicatorv2.KLineSubscription, w int) types.Float64Source { return indicatorv2.ATRP2(k, w) }),
+		"vwma":       klineWindowFunc(func(k indicatorv2.KLineSubscription, w int) types.Float64Source { return indicatorv2.VWMA(k, w) }),
+		"psar":       klineWindowFunc(func(k indicatorv2.KLineSubscription, w int) types.Float64Source { return indicatorv2.PSAR(k, w) }),
+		"linreg":     klineWindowFunc(func(k indicatorv2.KLineSubscription, w int) types.Float64Source { return indicatorv2.LinReg(k, w) }),
+		"obv":        klineFunc(func(k indicatorv2.KLineSubscription) types.Float64Source { return indicatorv2.OBV(k) }),
+		"ad":         klineFunc(func(k indicatorv2.KLineSubscription) types.Float64Source { return indicatorv2.AD(k) }),
+		"vwap":       vwapFunc,
+		"adx":        adxFunc,
+		"dmi":        dmiFunc,
+		"supertrend": supertrendFunc,
+
+		// signals and math
+		"cross":      crossFunc(true, true),
+		"crossover":  crossFunc(true, false),
+		"crossunder": crossFunc(false, true),
+		"prev":       prevFunc,
+		"change":     changeFunc,
+		"highest":    windowFunc(func(values []float64) float64 { return types.Highest(types.NewFloat64Series(values...), len(values)) }),
+		"lowest":     windowFunc(func(values []float64) float64 { return types.Lowest(types.NewFloat64Series(values...), len(values)) }),
+		"abs":        mathFunc(1, func(v []float64) float64 { return math.Abs(v[0]) }),
+		"min":        mathFunc(2, func(v []float64) float64 { return math.Min(v[0], v[1]) }),
+		"max":        mathFunc(2, func(v []float64) float64 { return math.Max(v[0], v[1]) }),
+	}
+}
+
+// arguments are the compiled arguments of the function call
+type arguments struct {
+	nodes []node
+}
+
+func (a *arguments) expect(min, max int) error {
+	if len(a.nodes) < min || len(a.nodes) > max {
+		if min == max {
+			return fmt.Errorf("expecting %d arguments, got %d", min, len(a.nodes))
+		}
+		return fmt.Errorf("expecting %d to %d arguments, got %d", min, max, len(a.nodes))
+	}
+	return nil
+}
+
+// number returns the constant number argument, the default value is used if the argument is omitted
+func (a *arguments) number(i int, defaultValue float64) (float64, error) {
+	if i >= len(a.nodes) {
+		return defaultValue, nil
+	}
+
+	n, ok := a.nodes[i].(*constNode)
+	if !ok {
+		return 0, fmt.Errorf("argument %d must be a number", i+1)
+	}
+	return n.value, nil
+}
+
+// window returns the positive integer argument
+func (a *arguments) window(i int, defaultValue int) (int, error) {
+	v, err := a.number(i, float64(defaultValue))
+	if err != nil {
+		return 0, err
+	}
+
+	if v != math.Trunc(v) || v < 1 {
+		return 0, fmt.Errorf("argument %d must be a positive integer, got %v", i+1, v)
+	}
+	return int(v), nil
+}
+
+func seriesWindowFunc(f func(source types.Float64Source, window int) types.Float64Source) function {
+	return func(c *compiler, args *arguments) (node, error) {
+		if err := args.expect(2, 2); err != nil {
+			return nil, err
+		}
+
+		w, err := args.window(1, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return &seriesNode{series: f(c.series(args.nodes[0]), w)}, nil
+	}
+}
+
+func klineWindowFunc(f func(source indicatorv2.KLineSubscription, window int) types.Float64Source) function {
+	return func(c *compiler, args *arguments) (node, error) {
+		if err := args.expect(1, 1); err != nil {
+			return nil, err
+		}
+
+		w, err := args.window(0, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return &seriesNode{series: f(c.source, w)}, nil
+	}
+}
+
+func klineFunc(f func(source indicatorv2.KLineSubscription) types.Float64Source) function {
+	return func(c *compiler, args *arguments) (node, error) {
+		if err := args.expect(0, 0); err != nil {
+			return nil, err
+		}
+
+		return &seriesNode{series: f(c.source)}, nil
+	}
+}
+
+// almaFunc: alma(source, window, offset = 0.85, sigma = 6)
+func almaFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(2, 4); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := args.number(2, 0.85)
+	if err != nil {
+		return nil, err
+	}
+
+	sigma, err := args.window(3, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seriesNode{series: indicatorv2.ALMA(c.series(args.nodes[0]), w, offset, sigma)}, nil
+}
+
+// tillFunc: till(source, window, volumeFactor = 0.7)
+func tillFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(2, 3); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	factor, err := args.number(2, indicatorv2.DefaultTillVolumeFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seriesNode{series: indicatorv2.TILL(c.series(args.nodes[0]), w, factor)}, nil
+}
+
+// ssfFunc: ssf(source, window, poles = 2)
+func ssfFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(2, 3); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	poles, err := args.window(2, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seriesNode{series: indicatorv2.SSF(c.series(args.nodes[0]), w, poles)}, nil
+}
+
+// tsiFunc: tsi(source, fast = 13, slow = 25)
+func tsiFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 3); err != nil {
+		return nil, err
+	}
+
+	fast, err := args.window(1, indicatorv2.DefaultTSIFastWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	slow, err := args.window(2, indicatorv2.DefaultTSISlowWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seriesNode{series: indicatorv2.TSI(c.series(args.nodes[0]), fast, slow)}, nil
+}
+
+// bollFunc: boll(source, window, k = 2), fields: mid (default), up, down, band
+func bollFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(2, 3); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := args.number(2, 2.0)
+	if err != nil {
+		return nil, err
+	}
+
+	boll := indicatorv2.BOLL(c.series(args.nodes[0]), w, k)
+	return &recordNode{
+		defaultField: "mid",
+		fields: map[string]node{
+			"mid":  &seriesNode{series: boll.SMA},
+			"up":   &seriesNode{series: boll.UpBand},
+			"down": &seriesNode{series: boll.DownBand},
+			"band": &seriesNode{series: boll.Float64Series},
+		},
+	}, nil
+}
+
+// macdFunc: macd(source, fast = 12, slow = 26, signal = 9), fields: macd (default), signal, histogram
+func macdFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 4); err != nil {
+		return nil, err
+	}
+
+	var windows [3]int
+	for i, defaultWindow := range []int{12, 26, 9} {
+		w, err := args.window(i+1, defaultWindow)
+		if err != nil {
+			return nil, err
+		}
+		windows[i] = w
+	}
+
+	macd := indicatorv2.MACD2(c.series(args.nodes[0]), windows[0], windows[1], windows[2])
+	return &recordNode{
+		defaultField: "macd",
+		fields: map[string]node{
+			"macd":      &seriesNode{series: macd.SubtractStream},
+			"signal":    &seriesNode{series: macd.Signal},
+			"histogram": &seriesNode{series: macd.Histogram},
+		},
+	}, nil
+}
+
+// vwapFunc: vwap(window = 0), the vwap is accumulated from the start if the window is omitted
+func vwapFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(0, 1); err != nil {
+		return nil, err
+	}
+
+	w := 0
+	if len(args.nodes) > 0 {
+		v, err := args.window(0, 0)
+		if err != nil {
+			return nil, err
+		}
+		w = v
+	}
+
+	return &seriesNode{series: indicatorv2.VWAP(c.source, w)}, nil
+}
+
+// adxFunc: adx(window), fields: adx (default), plus, minus
+func adxFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 1); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	adx := indicatorv2.ADX(c.source, w)
+	return &recordNode{
+		defaultField: "adx",
+		fields: map[string]node{
+			"adx":   &seriesNode{series: adx.RMAStream},
+			"plus":  &seriesNode{series: adx.Plus},
+			"minus": &seriesNode{series: adx.Minus},
+		},
+	}, nil
+}
+
+// dmiFunc: dmi(window, adxSmoothing = window), fields: adx (default), plus, minus
+func dmiFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 2); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	smoothing, err := args.window(1, w)
+	if err != nil {
+		return nil, err
+	}
+
+	dmi := indicatorv2.DMI(c.source, w, smoothing)
+	return &recordNode{
+		defaultField: "adx",
+		fields: map[string]node{
+			"adx":   &seriesNode{series: dmi.RMAStream},
+			"plus":  &seriesNode{series: dmi.DIPlus},
+			"minus": &seriesNode{series: dmi.DIMinus},
+		},
+	}, nil
+}
+
+// supertrendFunc: supertrend(window, multiplier = 3), fields: trend (default), support, resistance
+func supertrendFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 2); err != nil {
+		return nil, err
+	}
+
+	w, err := args.window(0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	multiplier, err := args.number(1, 3.0)
+	if err != nil {
+		return nil, err
+	}
+
+	st := indicatorv2.Supertrend(c.source, w, multiplier)
+	return &recordNode{
+		defaultField: "trend",
+		fields: map[string]node{
+			"trend":      &seriesNode{series: st.Float64Series},
+			"support":    &seriesNode{series: st.Support},
+			"resistance": &seriesNode{series: st.Resistance},
+		},
+	}, nil
+}
+
+// crossFunc returns 1 when the first argument crosses the second argument
+func crossFunc(over, under bool) function {
+	return func(c *compiler, args *arguments) (node, error) {
+		if err := args.expect(2, 2); err != nil {
+			return nil, err
+		}
+
+		return &crossNode{a: args.nodes[0], b: args.nodes[1], over: over, under: under}, nil
+	}
+}
+
+// prevFunc: prev(x, offset = 1), the same as x[offset]
+func prevFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 2); err != nil {
+		return nil, err
+	}
+
+	offset, err := args.window(1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offsetNode{x: args.nodes[0], offset: offset}, nil
+}
+
+// changeFunc: change(x, offset = 1), the difference between the value and the previous value
+func changeFunc(c *compiler, args *arguments) (node, error) {
+	if err := args.expect(1, 2); err != nil {
+		return nil, err
+	}
+
+	offset, err := args.window(1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &binaryNode{op: "-", l: args.nodes[0], r: &offsetNode{x: args.nodes[0], offset: offset}}, nil
+}
+
+func windowFunc(f func(values []float64) float64) function {
+	return func(c *compiler, args *arguments) (node, error) {
+		if err := args.expect(2, 2); err != nil {
+			return nil, err
+		}
+
+		w, err := args.window(1, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return &windowNode{x: args.nodes[0], window: w, fn: f}, nil
+	}
+}
+
+func mathFunc(numOfArgs int, f func(values []float64) float64) function {
+	return func(c *compiler, args *arguments) (node, error) {
+		if err := args.expect(numOfArgs, numOfArgs); err != nil {
+			return nil, err
+		}
+
+		return &funcNode{args: args.nodes, fn: f}, nil
+	}
+}
@@ -0,0 +1,116 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenDot
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at %d", t.text, t.pos)
+}
+
+// operators are sorted by the length, so that the longer operators are matched first
+var operators = []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "+", "-", "*", "/", "!"}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: value, pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(string(runes[start:i])), pos: start})
+
+		default:
+			kind := tokenOperator
+			switch r {
+			case '(':
+				kind = tokenLParen
+			case ')':
+				kind = tokenRParen
+			case '[':
+				kind = tokenLBracket
+			case ']':
+				kind = tokenRBracket
+			case ',':
+				kind = tokenComma
+			case '.':
+				kind = tokenDot
+			}
+
+			if kind != tokenOperator {
+				tokens = append(tokens, token{kind: kind, text: string(r), pos: i})
+				i++
+				continue
+			}
+
+			op := matchOperator(runes[i:])
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at %d", r, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+func matchOperator(runes []rune) string {
+	for _, op := range operators {
+		if len(runes) >= len(op) && string(runes[:len(op)]) == op {
+			return op
+		}
+	}
+	return ""
+}
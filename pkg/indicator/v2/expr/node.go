@@ -0,0 +1,185 @@
+package expr
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// node is the compiled expression node, eval returns the value of the i-th last kline,
+// NaN if the history is not long enough
+type node interface {
+	eval(i int) float64
+}
+
+type constNode struct {
+	value float64
+}
+
+func (n *constNode) eval(_ int) float64 {
+	return n.value
+}
+
+// seriesNode reads the value from the indicator series
+type seriesNode struct {
+	series types.Float64Source
+}
+
+func (n *seriesNode) eval(i int) float64 {
+	if i >= n.series.Length() {
+		return math.NaN()
+	}
+	return n.series.Last(i)
+}
+
+// recordNode is the multi-output indicator, like the bollinger bands, the default field is used when no field is selected
+type recordNode struct {
+	fields       map[string]node
+	defaultField string
+}
+
+func (n *recordNode) eval(i int) float64 {
+	return n.fields[n.defaultField].eval(i)
+}
+
+type offsetNode struct {
+	x      node
+	offset int
+}
+
+func (n *offsetNode) eval(i int) float64 {
+	return n.x.eval(i + n.offset)
+}
+
+type unaryNode struct {
+	op string
+	x  node
+}
+
+func (n *unaryNode) eval(i int) float64 {
+	v := n.x.eval(i)
+	switch n.op {
+	case "-":
+		return -v
+	case "not":
+		return boolValue(!truthy(v))
+	}
+	return math.NaN()
+}
+
+type binaryNode struct {
+	op   string
+	l, r node
+}
+
+func (n *binaryNode) eval(i int) float64 {
+	switch n.op {
+	case "and":
+		return boolValue(truthy(n.l.eval(i)) && truthy(n.r.eval(i)))
+	case "or":
+		return boolValue(truthy(n.l.eval(i)) || truthy(n.r.eval(i)))
+	}
+
+	a, b := n.l.eval(i), n.r.eval(i)
+	switch n.op {
+	case "<", "<=", ">", ">=", "==", "!=":
+		// the comparisons with the missing values are false
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return 0
+		}
+	}
+
+	switch n.op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		if b == 0 {
+			return math.NaN()
+		}
+		return a / b
+	case "<":
+		return boolValue(a < b)
+	case "<=":
+		return boolValue(a <= b)
+	case ">":
+		return boolValue(a > b)
+	case ">=":
+		return boolValue(a >= b)
+	case "==":
+		return boolValue(a == b)
+	case "!=":
+		return boolValue(a != b)
+	}
+	return math.NaN()
+}
+
+// funcNode evaluates the function of the argument values at the same index
+type funcNode struct {
+	args []node
+	fn   func(args []float64) float64
+}
+
+func (n *funcNode) eval(i int) float64 {
+	values := make([]float64, len(n.args))
+	for j, arg := range n.args {
+		values[j] = arg.eval(i)
+	}
+	return n.fn(values)
+}
+
+// windowNode evaluates the function of the last window values of the argument
+type windowNode struct {
+	x      node
+	window int
+	fn     func(values []float64) float64
+}
+
+func (n *windowNode) eval(i int) float64 {
+	values := make([]float64, n.window)
+	for j := 0; j < n.window; j++ {
+		values[j] = n.x.eval(i + j)
+		if math.IsNaN(values[j]) {
+			return math.NaN()
+		}
+	}
+	return n.fn(values)
+}
+
+type crossNode struct {
+	a, b        node
+	over, under bool
+}
+
+func (n *crossNode) eval(i int) float64 {
+	a0, b0 := n.a.eval(i), n.b.eval(i)
+	a1, b1 := n.a.eval(i+1), n.b.eval(i+1)
+	for _, v := range []float64{a0, b0, a1, b1} {
+		// there is no cross without the previous values
+		if math.IsNaN(v) {
+			return 0
+		}
+	}
+
+	if n.over && a0 > b0 && a1 <= b1 {
+		return 1
+	}
+	if n.under && a0 < b0 && a1 >= b1 {
+		return 1
+	}
+	return 0
+}
+
+func truthy(v float64) bool {
+	return v != 0 && !math.IsNaN(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,295 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// astNode is the node of the parsed expression
+type astNode interface {
+	String() string
+}
+
+type numberLit struct {
+	value float64
+}
+
+func (n *numberLit) String() string {
+	return strconv.FormatFloat(n.value, 'f', -1, 64)
+}
+
+type identExpr struct {
+	name string
+}
+
+func (n *identExpr) String() string {
+	return n.name
+}
+
+type callExpr struct {
+	name string
+	args []astNode
+}
+
+func (n *callExpr) String() string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.String()
+	}
+	return n.name + "(" + strings.Join(args, ",") + ")"
+}
+
+type fieldExpr struct {
+	target astNode
+	field  string
+}
+
+func (n *fieldExpr) String() string {
+	return n.target.String() + "." + n.field
+}
+
+// indexExpr refers to the previous value of the target, e.g., close[1] is the previous close price
+type indexExpr struct {
+	target astNode
+	offset int
+}
+
+func (n *indexExpr) String() string {
+	return n.target.String() + "[" + strconv.Itoa(n.offset) + "]"
+}
+
+type unaryExpr struct {
+	op string
+	x  astNode
+}
+
+func (n *unaryExpr) String() string {
+	return "(" + n.op + " " + n.x.String() + ")"
+}
+
+type binaryExpr struct {
+	op   string
+	l, r astNode
+}
+
+func (n *binaryExpr) String() string {
+	return "(" + n.l.String() + " " + n.op + " " + n.r.String() + ")"
+}
+
+// binaryPrecedences defines the precedences of the binary operators, the higher binds tighter
+var binaryPrecedences = map[string]int{
+	"or":  1,
+	"and": 2,
+	"==":  3,
+	"!=":  3,
+	"<":   4,
+	"<=":  4,
+	">":   4,
+	">=":  4,
+	"+":   5,
+	"-":   5,
+	"*":   6,
+	"/":   6,
+}
+
+// operatorAliases maps the symbol operators to the keyword operators
+var operatorAliases = map[string]string{
+	"&&": "and",
+	"||": "or",
+	"!":  "not",
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses the expression into the syntax tree
+func parse(input string) (astNode, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("expecting %q, got %s", text, t)
+	}
+	return nil
+}
+
+// binaryOperator returns the normalized binary operator of the token
+func binaryOperator(t token) (string, bool) {
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return "", false
+	}
+
+	op := t.text
+	if alias, ok := operatorAliases[op]; ok {
+		op = alias
+	}
+
+	_, ok := binaryPrecedences[op]
+	return op, ok
+}
+
+func (p *parser) parseBinary(minPrecedence int) (astNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := binaryOperator(p.peek())
+		if !ok || binaryPrecedences[op] < minPrecedence {
+			return left, nil
+		}
+
+		p.next()
+		right, err := p.parseBinary(binaryPrecedences[op] + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpr{op: op, l: left, r: right}
+	}
+}
+
+func (p *parser) parseUnary() (astNode, error) {
+	t := p.peek()
+	op := t.text
+	if alias, ok := operatorAliases[op]; ok && t.kind == tokenOperator {
+		op = alias
+	}
+
+	switch {
+	case (t.kind == tokenOperator || t.kind == tokenIdent) && op == "not":
+		p.next()
+		// not binds looser than the comparisons, e.g., not close > open
+		x, err := p.parseBinary(binaryPrecedences["=="])
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "not", x: x}, nil
+
+	case t.kind == tokenOperator && op == "-":
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", x: x}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (astNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokenDot:
+			p.next()
+			t := p.next()
+			if t.kind != tokenIdent {
+				return nil, fmt.Errorf("expecting field name, got %s", t)
+			}
+			node = &fieldExpr{target: node, field: t.text}
+
+		case tokenLBracket:
+			p.next()
+			t := p.next()
+			if t.kind != tokenNumber || t.value != float64(int(t.value)) || t.value < 0 {
+				return nil, fmt.Errorf("expecting non-negative integer offset, got %s", t)
+			}
+			if err := p.expect(tokenRBracket, "]"); err != nil {
+				return nil, err
+			}
+			node = &indexExpr{target: node, offset: int(t.value)}
+
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (astNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return &numberLit{value: t.value}, nil
+
+	case tokenLParen:
+		node, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	case tokenIdent:
+		if _, isKeyword := binaryPrecedences[t.text]; isKeyword || t.text == "not" {
+			return nil, fmt.Errorf("unexpected %s", t)
+		}
+
+		if p.peek().kind != tokenLParen {
+			return &identExpr{name: t.text}, nil
+		}
+
+		p.next()
+		call := &callExpr{name: t.text}
+		if p.peek().kind == tokenRParen {
+			p.next()
+			return call, nil
+		}
+
+		for {
+			arg, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+
+			t := p.next()
+			if t.kind == tokenRParen {
+				return call, nil
+			} else if t.kind != tokenComma {
+				return nil, fmt.Errorf("expecting \",\" or \")\", got %s", t)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %s", t)
+}
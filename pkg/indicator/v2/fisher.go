@@ -0,0 +1,49 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// FisherTransformStream converts the values into the gaussian distribution by the fisher transform
+// of the value position in the highest-lowest range of the window
+type FisherTransformStream struct {
+	*types.Float64Series
+
+	rawValues *types.Queue
+	window    int
+}
+
+func FisherTransform(source types.Float64Source, window int) *FisherTransformStream {
+	checkWindow(window)
+
+	s := &FisherTransformStream{
+		Float64Series: types.NewFloat64Series(),
+		rawValues:     types.NewQueue(window),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *FisherTransformStream) Calculate(v float64) float64 {
+	s.rawValues.Update(v)
+	highest := s.rawValues.Highest(s.window)
+	lowest := s.rawValues.Lowest(s.window)
+	if highest == lowest {
+		return 0
+	}
+
+	x := 2*((v-lowest)/(highest-lowest)) - 1
+	if x == 1 {
+		x = 0.9999
+	} else if x == -1 {
+		x = -0.9999
+	}
+	return 0.5 * math.Log((1+x)/(1-x))
+}
+
+func (s *FisherTransformStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
@@ -0,0 +1,147 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/optimize"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const DefaultGARCHRefitInterval = 10
+
+// GARCHStream is the GARCH(1,1) forecast of the volatility of the next period:
+//
+//	sigma^2[t+1] = omega + alpha * r[t]^2 + beta * sigma^2[t]
+//
+// The source is the returns (see LogReturns), and the mean of the returns is assumed to be zero.
+// The parameters are fitted by the maximum likelihood on the returns of the window with the variance targeting,
+// omega = (1 - alpha - beta) * mean(r^2), and they are refitted every RefitInterval updates.
+//
+// The value is the forecast volatility (the square root of the variance), it's the root mean square of the returns
+// until the window is filled.
+type GARCHStream struct {
+	*types.Float64Series
+
+	// Omega, Alpha and Beta are the fitted parameters
+	Omega, Alpha, Beta float64
+
+	// RefitInterval is the number of updates between the fits, defaults to DefaultGARCHRefitInterval
+	RefitInterval int
+
+	window  int
+	returns floats.Slice
+
+	// variance is the conditional variance of the last return
+	variance float64
+
+	// x is the unconstrained parameters of the last fit
+	x       []float64
+	updates int
+}
+
+func GARCH(source types.Float64Source, window int) *GARCHStream {
+	if window < 10 {
+		panic("the window of garch can not be less than 10")
+	}
+
+	s := &GARCHStream{
+		Float64Series: types.NewFloat64Series(),
+		RefitInterval: DefaultGARCHRefitInterval,
+		window:        window,
+		x:             []float64{math.Log(0.1 / 0.05), math.Log(0.85 / 0.05)},
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *GARCHStream) Calculate(r float64) float64 {
+	s.returns.Push(r)
+	s.returns = s.returns.Truncate(s.window)
+
+	if s.returns.Length() < s.window {
+		return math.Sqrt(meanSquare(s.returns))
+	}
+
+	if s.Alpha+s.Beta == 0 || s.updates%max(s.RefitInterval, 1) == 0 {
+		s.fit()
+	} else {
+		// the conditional variance of r is updated by the previous return
+		prev := s.returns.Last(1)
+		s.variance = s.Omega + s.Alpha*prev*prev + s.Beta*s.variance
+	}
+	s.updates++
+
+	// the forecast of the next period
+	return math.Sqrt(s.Omega + s.Alpha*r*r + s.Beta*s.variance)
+}
+
+func (s *GARCHStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
+
+// fit fits the parameters by minimizing the negative log likelihood, starting from the last fitted parameters
+func (s *GARCHStream) fit() {
+	target := meanSquare(s.returns)
+	if target == 0 {
+		s.Omega, s.Alpha, s.Beta, s.variance = 0, 0, 0, 0
+		return
+	}
+
+	problem := optimize.Problem{
+		Func: func(x []float64) float64 {
+			alpha, beta := garchParameters(x)
+			_, nll := garchFilter(s.returns, target*(1-alpha-beta), alpha, beta, target)
+			return nll
+		},
+	}
+
+	result, err := optimize.Minimize(problem, s.x, &optimize.Settings{MajorIterations: 200}, &optimize.NelderMead{})
+	if err == nil && result != nil && !math.IsNaN(result.F) && !math.IsInf(result.F, 0) {
+		copy(s.x, result.X)
+	}
+
+	s.Alpha, s.Beta = garchParameters(s.x)
+	s.Omega = target * (1 - s.Alpha - s.Beta)
+
+	// the variance of the last return, which is the base of the forecast
+	variances, _ := garchFilter(s.returns, s.Omega, s.Alpha, s.Beta, target)
+	last := len(variances) - 1
+	s.variance = variances[last]
+}
+
+// garchParameters maps the unconstrained parameters to alpha >= 0, beta >= 0 and alpha + beta < 1
+func garchParameters(x []float64) (alpha, beta float64) {
+	ea, eb := math.Exp(x[0]), math.Exp(x[1])
+	d := 1.0 + ea + eb
+	return ea / d, eb / d
+}
+
+// garchFilter returns the conditional variances of the returns and the negative log likelihood (without the constant)
+func garchFilter(returns floats.Slice, omega, alpha, beta, initial float64) (variances floats.Slice, nll float64) {
+	variance := initial
+	for i, r := range returns {
+		if i > 0 {
+			prev := returns[i-1]
+			variance = omega + alpha*prev*prev + beta*variance
+		}
+
+		variance = math.Max(variance, 1e-20)
+		variances.Push(variance)
+		nll += math.Log(variance) + r*r/variance
+	}
+	return variances, nll
+}
+
+func meanSquare(values floats.Slice) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v * v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,35 @@
+package indicatorv2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestGARCH(t *testing.T) {
+	const omega, alpha, beta = 1e-6, 0.1, 0.85
+
+	r := rand.New(rand.NewSource(7))
+	source := types.NewFloat64Series()
+	garch := GARCH(source, 500)
+
+	variance := omega / (1 - alpha - beta)
+	var ret float64
+	for i := 0; i < 1000; i++ {
+		variance = omega + alpha*ret*ret + beta*variance
+		ret = math.Sqrt(variance) * r.NormFloat64()
+		source.PushAndEmit(ret)
+	}
+
+	assert.Less(t, garch.Alpha+garch.Beta, 1.0)
+	assert.InDelta(t, alpha+beta, garch.Alpha+garch.Beta, 0.1)
+	assert.Greater(t, garch.Omega, 0.0)
+
+	// the forecast of the next variance from the true parameters
+	expected := math.Sqrt(omega + alpha*ret*ret + beta*variance)
+	assert.InDelta(t, expected, garch.Last(0), expected*0.3)
+}
@@ -0,0 +1,42 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// GarmanKlassStream is the Garman-Klass volatility estimator, which estimates the volatility from the OHLC prices:
+//
+//	sigma^2 = mean(0.5 * ln(high / low)^2 - (2 * ln(2) - 1) * ln(close / open)^2)
+//
+// The value is the volatility per kline, it's not annualized.
+type GarmanKlassStream struct {
+	*types.Float64Series
+
+	window int
+	terms  *types.Queue
+}
+
+func GarmanKlass(source KLineSubscription, window int) *GarmanKlassStream {
+	checkWindow(window)
+	s := &GarmanKlassStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		terms:         types.NewQueue(window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		openPrice, high, low, closePrice := k.Open.Float64(), k.High.Float64(), k.Low.Float64(), k.Close.Float64()
+		if low <= 0 || openPrice <= 0 {
+			return
+		}
+
+		hl := math.Log(high / low)
+		co := math.Log(closePrice / openPrice)
+		s.terms.Update(0.5*hl*hl - (2.0*math.Ln2-1.0)*co*co)
+		s.PushAndEmit(math.Sqrt(math.Max(s.terms.Mean(s.window), 0)))
+		s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
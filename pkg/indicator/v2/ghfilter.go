@@ -0,0 +1,50 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// GHFilterStream is the alpha-beta (g-h) filter, the filter gain is derived from the tracking index
+// of the maneuverability uncertainty and the measurement uncertainty
+type GHFilterStream struct {
+	*types.Float64Series
+
+	window          int
+	a               float64 // maneuverability uncertainty
+	b               float64 // measurement uncertainty
+	lastMeasurement float64
+}
+
+func GHFilter(source types.Float64Source, window int) *GHFilterStream {
+	s := &GHFilterStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *GHFilterStream) Calculate(v float64) float64 {
+	uncertainty := math.Abs(v - s.lastMeasurement)
+	if s.Length() == 0 {
+		s.a = 0
+		s.b = uncertainty / 2
+		s.lastMeasurement = v
+		return v
+	}
+
+	multiplier := 2.0 / float64(1+s.window) // EMA multiplier
+	s.a = multiplier*(v-s.lastMeasurement) + (1-multiplier)*s.a
+	s.b = multiplier*uncertainty/2 + (1-multiplier)*s.b
+	lambda := s.a / s.b
+	lambda2 := lambda * lambda
+	alpha := (-lambda2 + math.Sqrt(lambda2*lambda2+16*lambda2)) / 8
+	s.lastMeasurement = v
+	return alpha*v + (1-alpha)*s.Slice.Last(0)
+}
+
+func (s *GHFilterStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
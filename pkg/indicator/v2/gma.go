@@ -0,0 +1,33 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// GMAStream is the Geometric Moving Average, the exponent of the SMA of the log values
+type GMAStream struct {
+	*types.Float64Series
+
+	sma *SMAStream
+}
+
+func GMA(source types.Float64Source, window int) *GMAStream {
+	s := &GMAStream{
+		Float64Series: types.NewFloat64Series(),
+		sma:           SMA(nil, window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *GMAStream) Calculate(v float64) float64 {
+	s.sma.PushAndEmit(s.sma.Calculate(math.Log(v)))
+	s.sma.Truncate()
+	return math.Exp(s.sma.Last(0))
+}
+
+func (s *GMAStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
@@ -0,0 +1,157 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/c9s/bbgo/pkg/testing/golden"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type goldenSeries interface {
+	Last(i int) float64
+	Length() int
+}
+
+// goldenCase calculates the series built on the kline stream
+func goldenCase(
+	name, reference string, skip int, tolerance float64, knownDeviation string,
+	build func(source *KLineStream) goldenSeries,
+) golden.Case {
+	return golden.Case{
+		Name:           name,
+		Reference:      reference,
+		Skip:           skip,
+		Tolerance:      tolerance,
+		KnownDeviation: knownDeviation,
+		Calculate: func(kLines []types.KLine) []float64 {
+			source := &KLineStream{}
+			series := build(source)
+			return golden.Collect(kLines, func(k types.KLine) {
+				source.BackFill([]types.KLine{k})
+			}, series)
+		},
+	}
+}
+
+func closes(source *KLineStream) types.Float64Source {
+	return ClosePrices(source)
+}
+
+// percentage converts the ratio series to the percentage
+type percentage struct {
+	goldenSeries
+}
+
+func (p percentage) Last(i int) float64 {
+	return p.goldenSeries.Last(i) * 100.0
+}
+
+// simpleReturns emits the simple returns of the source, v[t] / v[t-1] - 1
+func simpleReturns(source types.Float64Source) *types.Float64Series {
+	s := types.NewFloat64Series()
+	prev := 0.0
+	s.Subscribe(source, func(v float64) {
+		if prev != 0 {
+			s.PushAndEmit(v/prev - 1)
+		}
+		prev = v
+	})
+	return s
+}
+
+const (
+	knownDeviationRSI        = "the RSI is the ratio of the simple average gain and loss (Cutler) instead of the Wilder smoothing"
+	knownDeviationCCI        = "the mean deviation is the root mean square of the deviations instead of the mean absolute deviation"
+	knownDeviationEMASeed    = "the EMA is seeded by the first value instead of the SMA of the first window"
+	knownDeviationRMASeed    = "the RMA is the adjusted EWM seeded by the first value instead of the Wilder smoothing seeded by the SMA"
+	knownDeviationADX        = "the DX is smoothed from the first directional movement, before the directional indicators are warmed up"
+	knownDeviationHULL       = "the moving averages are EMAs instead of WMAs"
+	knownDeviationALMA       = "the peak of the weights is close to the latest value (TradingView) instead of the oldest one"
+	knownDeviationVIDYA      = "the VIDYA is seeded by the first value instead of 0 after the first window"
+	knownDeviationSSF        = "the filter is seeded by zeros instead of the source values"
+	knownDeviationKlinger    = "the volume force is weighted by the daily measurement (dm / cm) instead of the signed volume"
+	knownDeviationSupertrend = "the bands are calculated from the first kline instead of after the ATR warm-up, the trend line differs until the bands are reset"
+	knownDeviationPSAR       = "the SAR is the previous low or high during the warm-up window, and the acceleration factor is increased before the reversal check"
+)
+
+// The cases compare the whole output after the warm-up of the reference, so the seeding differences are not
+// skipped. The EMA and the RMA based indicators are seeded by the first value like pandas-ta with presma=False,
+// they are compared with the pandas-ta references and their TA-Lib references are known deviations.
+//
+// The indicators without a reference implementation in TA-Lib or pandas-ta are not covered here:
+// Drift, WeightedDrift, FisherTransform (pandas-ta fisher is a different algorithm), GHFilter, KalmanFilter,
+// UTBotAlert, VolumeProfile, Pivot, PivotHigh, PivotLow, PivotSupertrend, Line, Hurst, GARCH and HMMRegime.
+func Test_Golden(t *testing.T) {
+	cases := []golden.Case{
+		goldenCase("SMA", "sma_20", 0, 0, "", func(s *KLineStream) goldenSeries { return SMA(closes(s), 20) }),
+		goldenCase("EWMA", "pta_ema_20", 0, 0, "", func(s *KLineStream) goldenSeries { return EWMA2(closes(s), 20) }),
+		goldenCase("EWMA/TA-Lib", "ema_20", 0, 0, knownDeviationEMASeed, func(s *KLineStream) goldenSeries { return EWMA2(closes(s), 20) }),
+		goldenCase("DEMA", "pta_dema_20", 0, 0, "", func(s *KLineStream) goldenSeries { return DEMA(closes(s), 20) }),
+		goldenCase("DEMA/TA-Lib", "dema_20", 0, 0, knownDeviationEMASeed, func(s *KLineStream) goldenSeries { return DEMA(closes(s), 20) }),
+		goldenCase("TEMA", "pta_tema_20", 0, 0, "", func(s *KLineStream) goldenSeries { return TEMA(closes(s), 20) }),
+		goldenCase("TEMA/TA-Lib", "tema_20", 0, 0, knownDeviationEMASeed, func(s *KLineStream) goldenSeries { return TEMA(closes(s), 20) }),
+		goldenCase("TILL", "pta_t3_10", 0, 0, "", func(s *KLineStream) goldenSeries { return TILL(closes(s), 10, 0.7) }),
+		goldenCase("ZLEMA", "pta_zlma_21", 0, 0, "", func(s *KLineStream) goldenSeries { return ZLEMA(closes(s), 21) }),
+		goldenCase("HULL", "pta_hma_16", 0, 0, knownDeviationHULL, func(s *KLineStream) goldenSeries { return HULL(closes(s), 16) }),
+		goldenCase("ALMA", "pta_alma_10", 0, 0, knownDeviationALMA, func(s *KLineStream) goldenSeries { return ALMA(closes(s), 10, 0.85, 6) }),
+		goldenCase("VIDYA", "pta_vidya_14", 0, 0, knownDeviationVIDYA, func(s *KLineStream) goldenSeries { return VIDYA(closes(s), 14) }),
+		goldenCase("SSF/2", "pta_ssf_10_2", 0, 0, knownDeviationSSF, func(s *KLineStream) goldenSeries { return SSF(closes(s), 10, 2) }),
+		goldenCase("SSF/3", "pta_ssf_10_3", 0, 0, knownDeviationSSF, func(s *KLineStream) goldenSeries { return SSF(closes(s), 10, 3) }),
+		goldenCase("RMA", "pta_rma_14", 0, 0, "", func(s *KLineStream) goldenSeries { return RMA2(closes(s), 14, true) }),
+		goldenCase("WWMA", "pta_wwma_14", 0, 0, "", func(s *KLineStream) goldenSeries { return WWMA(closes(s), 14) }),
+		goldenCase("SMMA", "fml_smma_14", 0, 0, "", func(s *KLineStream) goldenSeries { return SMMA2(closes(s), 14) }),
+		goldenCase("CMA", "fml_cma", 0, 0, "", func(s *KLineStream) goldenSeries { return CMA2(closes(s)) }),
+		goldenCase("GMA", "fml_gma_20", 0, 0, "", func(s *KLineStream) goldenSeries { return GMA(closes(s), 20) }),
+		goldenCase("TMA", "trima_21", 0, 0, "", func(s *KLineStream) goldenSeries { return TMA(closes(s), 21) }),
+		goldenCase("StdDev", "stddev_20", 0, 0, "", func(s *KLineStream) goldenSeries { return StdDev(closes(s), 20) }),
+		goldenCase("Volatility", "stddev_20", 0, 0, "", func(s *KLineStream) goldenSeries { return Volatility(closes(s), 20) }),
+		goldenCase("ZScore", "pta_zscore_20", 0, 0, "", func(s *KLineStream) goldenSeries { return ZScore(closes(s), 20) }),
+		goldenCase("LogReturns", "pta_log_return", 0, 0, "", func(s *KLineStream) goldenSeries { return LogReturns(closes(s)) }),
+		goldenCase("LinReg", "linearreg_slope_14", 0, 0, "", func(s *KLineStream) goldenSeries { return LinReg(s, 14) }),
+		goldenCase("Correlation", "correl_20", 0, 0, "", func(s *KLineStream) goldenSeries {
+			return Correlation(s, HighPrices(s), s, LowPrices(s), 20)
+		}),
+		goldenCase("Beta", "beta_20", 0, 0, "", func(s *KLineStream) goldenSeries {
+			return Beta(s, simpleReturns(HighPrices(s)), s, simpleReturns(ClosePrices(s)), 20)
+		}),
+		goldenCase("Parkinson", "fml_parkinson_20", 0, 0, "", func(s *KLineStream) goldenSeries { return Parkinson(s, 20) }),
+		goldenCase("GarmanKlass", "fml_garman_klass_20", 0, 0, "", func(s *KLineStream) goldenSeries { return GarmanKlass(s, 20) }),
+		goldenCase("YangZhang", "fml_yang_zhang_20", 0, 0, "", func(s *KLineStream) goldenSeries { return YangZhang(s, 20) }),
+		goldenCase("RSI", "rsi_14", 0, 0, knownDeviationRSI, func(s *KLineStream) goldenSeries { return RSI2(closes(s), 14) }),
+		goldenCase("TSI", "pta_tsi_13_25", 0, 0, "", func(s *KLineStream) goldenSeries { return TSI(closes(s), 13, 25) }),
+		goldenCase("ATR", "pta_atr_14", 0, 0, "", func(s *KLineStream) goldenSeries { return ATR2(s, 14) }),
+		goldenCase("ATR/TA-Lib", "atr_14", 0, 0, knownDeviationRMASeed, func(s *KLineStream) goldenSeries { return ATR2(s, 14) }),
+		goldenCase("ATRP", "pta_natr_14", 0, 0, "", func(s *KLineStream) goldenSeries { return percentage{ATRP2(s, 14)} }),
+		goldenCase("ATRP/TA-Lib", "natr_14", 0, 0, knownDeviationRMASeed, func(s *KLineStream) goldenSeries { return percentage{ATRP2(s, 14)} }),
+		goldenCase("Keltner.Mid", "pta_ema_20", 0, 0, "", func(s *KLineStream) goldenSeries { return Keltner(s, 20, 14).Mid }),
+		goldenCase("BOLL.UpBand", "bbands_20_upper", 0, 0, "", func(s *KLineStream) goldenSeries { return BOLL(closes(s), 20, 2).UpBand }),
+		goldenCase("BOLL.SMA", "bbands_20_middle", 0, 0, "", func(s *KLineStream) goldenSeries { return BOLL(closes(s), 20, 2).SMA }),
+		goldenCase("BOLL.DownBand", "bbands_20_lower", 0, 0, "", func(s *KLineStream) goldenSeries { return BOLL(closes(s), 20, 2).DownBand }),
+		goldenCase("MACD", "pta_macd_12_26_9", 0, 0, "", func(s *KLineStream) goldenSeries { return MACD2(closes(s), 12, 26, 9) }),
+		goldenCase("MACD.Signal", "pta_macd_12_26_9_signal", 0, 0, "", func(s *KLineStream) goldenSeries { return MACD2(closes(s), 12, 26, 9).Signal }),
+		goldenCase("MACD.Histogram", "pta_macd_12_26_9_hist", 0, 0, "", func(s *KLineStream) goldenSeries { return MACD2(closes(s), 12, 26, 9).Histogram }),
+		goldenCase("Stoch.K", "stochf_14_3_k", 0, 0, "", func(s *KLineStream) goldenSeries { return &Stoch(s, 14, 3).K }),
+		goldenCase("Stoch.D", "stochf_14_3_d", 0, 0, "", func(s *KLineStream) goldenSeries { return &Stoch(s, 14, 3).D }),
+		goldenCase("CCI", "cci_20", 0, 0, knownDeviationCCI, func(s *KLineStream) goldenSeries { return CCI(HLC3(s), 20) }),
+		goldenCase("OBV", "obv", 0, 0, "", func(s *KLineStream) goldenSeries { return OBV(s) }),
+		goldenCase("AD", "ad", 0, 0, "", func(s *KLineStream) goldenSeries { return AD(s) }),
+		goldenCase("EMV", "pta_eom_14", 0, 0, "", func(s *KLineStream) goldenSeries { return EMV(s, 14, 0) }),
+		goldenCase("VWAP", "pta_vwap", 0, 0, "", func(s *KLineStream) goldenSeries { return VWAP(s, 0) }),
+		goldenCase("VWMA", "pta_vwma_20", 0, 0, "", func(s *KLineStream) goldenSeries { return VWMA(s, 20) }),
+		goldenCase("Klinger", "pta_kvo_34_55", 0, 0, knownDeviationKlinger, func(s *KLineStream) goldenSeries { return KlingerOscillator(s, 34, 55) }),
+		goldenCase("ADX", "pta_adx_14", 0, 0, knownDeviationADX, func(s *KLineStream) goldenSeries { return ADX(s, 14) }),
+		goldenCase("ADX/TA-Lib", "adx_14", 0, 0, knownDeviationRMASeed, func(s *KLineStream) goldenSeries { return ADX(s, 14) }),
+		goldenCase("ADX.Plus", "pta_dmp_14", 0, 0, "", func(s *KLineStream) goldenSeries { return ADX(s, 14).Plus }),
+		goldenCase("ADX.Plus/TA-Lib", "plus_di_14", 0, 0, knownDeviationRMASeed, func(s *KLineStream) goldenSeries { return ADX(s, 14).Plus }),
+		goldenCase("ADX.Minus", "pta_dmn_14", 0, 0, "", func(s *KLineStream) goldenSeries { return ADX(s, 14).Minus }),
+		goldenCase("ADX.Minus/TA-Lib", "minus_di_14", 0, 0, knownDeviationRMASeed, func(s *KLineStream) goldenSeries { return ADX(s, 14).Minus }),
+		goldenCase("DMI", "pta_adx_14", 0, 0, "", func(s *KLineStream) goldenSeries { return DMI(s, 14, 14) }),
+		goldenCase("DMI/TA-Lib", "adx_14", 0, 0, knownDeviationRMASeed, func(s *KLineStream) goldenSeries { return DMI(s, 14, 14) }),
+		goldenCase("DMI.DIPlus", "pta_dmp_14", 0, 0, "", func(s *KLineStream) goldenSeries { return DMI(s, 14, 14).DIPlus }),
+		goldenCase("DMI.DIMinus", "pta_dmn_14", 0, 0, "", func(s *KLineStream) goldenSeries { return DMI(s, 14, 14).DIMinus }),
+		goldenCase("Supertrend", "pta_supertrend_10_3", 0, 0, knownDeviationSupertrend, func(s *KLineStream) goldenSeries { return Supertrend(s, 10, 3) }),
+		goldenCase("PSAR", "sar", 0, 0, knownDeviationPSAR, func(s *KLineStream) goldenSeries { return PSAR(s, 2) }),
+	}
+
+	golden.RunTest(t, cases)
+}
@@ -0,0 +1,345 @@
+package indicatorv2
+
+import (
+	"math"
+	"sort"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const DefaultHMMRefitInterval = 10
+
+const (
+	hmmInitialIterations = 50
+	hmmRefitIterations   = 10
+	hmmMinVariance       = 1e-12
+
+	// hmmMinTransition keeps every regime reachable, so that a regime is not dropped by a long calm period
+	hmmMinTransition = 1e-4
+)
+
+// HMMRegimeStream classifies the regime of the source by a hidden Markov model with the gaussian emissions.
+// The model is fitted by the Baum-Welch algorithm on the values of the window, and it's refitted every RefitInterval
+// updates, starting from the last fitted model. Between the fits, the regime probabilities are updated by the forward
+// algorithm.
+//
+// The source is usually the returns (see LogReturns). The regimes are sorted by the variance, so the regime 0 is the
+// calmest one. The value is the index of the most probable current regime, and it's 0 until the window is filled.
+type HMMRegimeStream struct {
+	*types.Float64Series
+
+	// Probability is the probability of the current regime
+	Probability *types.Float64Series
+
+	// RefitInterval is the number of updates between the fits, defaults to DefaultHMMRefitInterval
+	RefitInterval int
+
+	// Means, Variances and Transition are the parameters of the fitted model, Transition[i][j] is the probability
+	// of the transition from the regime i to the regime j
+	Means, Variances []float64
+	Transition       [][]float64
+
+	states, window int
+	values         floats.Slice
+
+	initial []float64
+
+	// probabilities is the filtered probabilities of the regimes of the last value
+	probabilities []float64
+
+	fitted  bool
+	updates int
+}
+
+func HMMRegime(source types.Float64Source, window, states int) *HMMRegimeStream {
+	if states < 2 {
+		panic("the number of the hmm regimes can not be less than 2")
+	}
+
+	if window < 10*states {
+		panic("the window of hmm regime is too small for the number of regimes")
+	}
+
+	s := &HMMRegimeStream{
+		Float64Series: types.NewFloat64Series(),
+		Probability:   types.NewFloat64Series(),
+		RefitInterval: DefaultHMMRefitInterval,
+		states:        states,
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+// Probabilities returns the probabilities of the regimes of the last value
+func (s *HMMRegimeStream) Probabilities() []float64 {
+	return append([]float64(nil), s.probabilities...)
+}
+
+func (s *HMMRegimeStream) Calculate(v float64) float64 {
+	s.values.Push(v)
+	s.values = s.values.Truncate(s.window)
+
+	if s.values.Length() < s.window {
+		s.Probability.PushAndEmit(0)
+		return 0
+	}
+
+	if !s.fitted {
+		s.initialize()
+		s.fit(hmmInitialIterations)
+		s.fitted = true
+	} else if s.updates%max(s.RefitInterval, 1) == 0 {
+		s.fit(hmmRefitIterations)
+	} else {
+		s.forward(v)
+	}
+	s.updates++
+
+	regime := 0
+	for i, p := range s.probabilities {
+		if p > s.probabilities[regime] {
+			regime = i
+		}
+	}
+
+	s.Probability.PushAndEmit(s.probabilities[regime])
+	return float64(regime)
+}
+
+func (s *HMMRegimeStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	s.Probability.Slice = s.Probability.Slice.Truncate(MaxNumOfSMA)
+}
+
+// initialize splits the values sorted by the distance from the mean into the regimes
+func (s *HMMRegimeStream) initialize() {
+	mean := s.values.Mean()
+	sorted := make(floats.Slice, len(s.values))
+	copy(sorted, s.values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return math.Abs(sorted[i]-mean) < math.Abs(sorted[j]-mean)
+	})
+
+	s.Means = make([]float64, s.states)
+	s.Variances = make([]float64, s.states)
+	s.initial = make([]float64, s.states)
+	s.Transition = make([][]float64, s.states)
+
+	size := len(sorted) / s.states
+	for i := 0; i < s.states; i++ {
+		group := sorted[i*size : (i+1)*size]
+		s.Means[i] = group.Mean()
+		s.Variances[i] = math.Max(sampleVariance(group), hmmMinVariance)
+		s.initial[i] = 1.0 / float64(s.states)
+
+		s.Transition[i] = make([]float64, s.states)
+		for j := range s.Transition[i] {
+			if i == j {
+				s.Transition[i][j] = 0.9
+			} else {
+				s.Transition[i][j] = 0.1 / float64(s.states-1)
+			}
+		}
+	}
+}
+
+func logGaussian(x, mean, variance float64) float64 {
+	d := x - mean
+	return -d*d/(2*variance) - 0.5*math.Log(2*math.Pi*variance)
+}
+
+// emissions returns the emission probabilities of the value relative to the most probable regime,
+// so that they don't underflow to zeros. The common factor is cancelled by the normalization.
+func (s *HMMRegimeStream) emissions(v float64) []float64 {
+	b := make([]float64, s.states)
+	maxLog := math.Inf(-1)
+	for j := range b {
+		b[j] = logGaussian(v, s.Means[j], s.Variances[j])
+		maxLog = math.Max(maxLog, b[j])
+	}
+
+	for j := range b {
+		b[j] = math.Exp(b[j] - maxLog)
+	}
+	return b
+}
+
+// forward updates the filtered probabilities by the new value
+func (s *HMMRegimeStream) forward(v float64) {
+	b := s.emissions(v)
+	next := make([]float64, s.states)
+	var sum float64
+	for j := 0; j < s.states; j++ {
+		for i := 0; i < s.states; i++ {
+			next[j] += s.probabilities[i] * s.Transition[i][j]
+		}
+		next[j] *= b[j]
+		sum += next[j]
+	}
+
+	for j := range next {
+		next[j] /= sum
+	}
+	s.probabilities = next
+}
+
+// fit runs the Baum-Welch iterations with the scaled forward and backward probabilities
+func (s *HMMRegimeStream) fit(iterations int) {
+	n, k := len(s.values), s.states
+
+	alpha := make([][]float64, n)
+	beta := make([][]float64, n)
+	emissions := make([][]float64, n)
+	scales := make([]float64, n)
+	for t := range alpha {
+		alpha[t] = make([]float64, k)
+		beta[t] = make([]float64, k)
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		for t, v := range s.values {
+			emissions[t] = s.emissions(v)
+		}
+
+		// forward
+		for t := 0; t < n; t++ {
+			scales[t] = 0
+			for j := 0; j < k; j++ {
+				if t == 0 {
+					alpha[t][j] = s.initial[j] * emissions[t][j]
+				} else {
+					var sum float64
+					for i := 0; i < k; i++ {
+						sum += alpha[t-1][i] * s.Transition[i][j]
+					}
+					alpha[t][j] = sum * emissions[t][j]
+				}
+				scales[t] += alpha[t][j]
+			}
+
+			if scales[t] == 0 {
+				scales[t] = math.SmallestNonzeroFloat64
+			}
+
+			for j := 0; j < k; j++ {
+				alpha[t][j] /= scales[t]
+			}
+		}
+
+		// backward
+		for j := 0; j < k; j++ {
+			beta[n-1][j] = 1
+		}
+
+		for t := n - 2; t >= 0; t-- {
+			for i := 0; i < k; i++ {
+				var sum float64
+				for j := 0; j < k; j++ {
+					sum += s.Transition[i][j] * emissions[t+1][j] * beta[t+1][j]
+				}
+				beta[t][i] = sum / scales[t+1]
+			}
+		}
+
+		// re-estimation
+		gammaSums := make([]float64, k)
+		transitions := make([][]float64, k)
+		means := make([]float64, k)
+		for i := range transitions {
+			transitions[i] = make([]float64, k)
+		}
+
+		gammas := make([][]float64, n)
+		for t := 0; t < n; t++ {
+			gammas[t] = make([]float64, k)
+			var sum float64
+			for i := 0; i < k; i++ {
+				gammas[t][i] = alpha[t][i] * beta[t][i]
+				sum += gammas[t][i]
+			}
+
+			for i := 0; i < k; i++ {
+				if sum > 0 {
+					gammas[t][i] /= sum
+				}
+				gammaSums[i] += gammas[t][i]
+				means[i] += gammas[t][i] * s.values[t]
+			}
+
+			if t == n-1 {
+				continue
+			}
+
+			for i := 0; i < k; i++ {
+				for j := 0; j < k; j++ {
+					transitions[i][j] += alpha[t][i] * s.Transition[i][j] * emissions[t+1][j] * beta[t+1][j] / scales[t+1]
+				}
+			}
+		}
+
+		for i := 0; i < k; i++ {
+			// the regime is not observed in the window, keep its parameters
+			if gammaSums[i] < 1e-9 {
+				continue
+			}
+
+			s.Means[i] = means[i] / gammaSums[i]
+
+			var variance float64
+			for t := 0; t < n; t++ {
+				d := s.values[t] - s.Means[i]
+				variance += gammas[t][i] * d * d
+			}
+			s.Variances[i] = math.Max(variance/gammaSums[i], hmmMinVariance)
+
+			var rowSum float64
+			for j := 0; j < k; j++ {
+				s.Transition[i][j] = math.Max(transitions[i][j], hmmMinTransition)
+				rowSum += s.Transition[i][j]
+			}
+
+			for j := 0; j < k; j++ {
+				s.Transition[i][j] /= rowSum
+			}
+
+			s.initial[i] = gammas[0][i]
+		}
+
+		// the filtered probabilities of the last value
+		s.probabilities = append(s.probabilities[:0], alpha[n-1]...)
+	}
+
+	s.sortRegimes()
+}
+
+// sortRegimes sorts the regimes by the variance
+func (s *HMMRegimeStream) sortRegimes() {
+	order := make([]int, s.states)
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return s.Variances[order[a]] < s.Variances[order[b]]
+	})
+
+	means := make([]float64, s.states)
+	variances := make([]float64, s.states)
+	initial := make([]float64, s.states)
+	probabilities := make([]float64, s.states)
+	transition := make([][]float64, s.states)
+	for a, i := range order {
+		means[a] = s.Means[i]
+		variances[a] = s.Variances[i]
+		initial[a] = s.initial[i]
+		probabilities[a] = s.probabilities[i]
+		transition[a] = make([]float64, s.states)
+		for b, j := range order {
+			transition[a][b] = s.Transition[i][j]
+		}
+	}
+
+	s.Means, s.Variances, s.initial, s.probabilities, s.Transition = means, variances, initial, probabilities, transition
+}
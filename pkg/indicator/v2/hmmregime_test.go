@@ -0,0 +1,47 @@
+package indicatorv2
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestHMMRegime(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	source := types.NewFloat64Series()
+	regime := HMMRegime(source, 200, 2)
+
+	for i := 0; i < 199; i++ {
+		source.PushAndEmit(0.01 * r.NormFloat64())
+	}
+	assert.Equal(t, 0.0, regime.Last(0), "the value before the window is filled")
+
+	// the calm regime
+	for i := 0; i < 200; i++ {
+		source.PushAndEmit(0.001 * r.NormFloat64())
+		if i > 20 && i < 150 {
+			assert.Equal(t, 0.0, regime.Last(0), "calm #%d", i)
+		}
+	}
+
+	// the volatile regime, a small return of it may still look calm
+	volatile := 0
+	for i := 0; i < 50; i++ {
+		source.PushAndEmit(0.02 * r.NormFloat64())
+		if regime.Last(0) == 1.0 {
+			volatile++
+		}
+	}
+	assert.Greater(t, volatile, 40)
+	assert.Less(t, regime.Variances[0], regime.Variances[1])
+	assert.GreaterOrEqual(t, regime.Probability.Last(0), 0.5)
+
+	var sum float64
+	for _, p := range regime.Probabilities() {
+		sum += p
+	}
+	assert.InDelta(t, 1.0, sum, 1e-9)
+}
@@ -0,0 +1,33 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// HULLStream is the Hull Moving Average: EMA(2 * EMA(price, window/2) - EMA(price, window), sqrt(window))
+type HULLStream struct {
+	*EWMAStream
+
+	ma1, ma2 *EWMAStream
+}
+
+func HULL(source types.Float64Source, window int) *HULLStream {
+	var (
+		ma1  = EWMA2(source, window/2)
+		ma2  = EWMA2(source, window)
+		diff = types.NewFloat64Series()
+		s    = &HULLStream{
+			EWMAStream: EWMA2(diff, int(math.Sqrt(float64(window)))),
+			ma1:        ma1,
+			ma2:        ma2,
+		}
+	)
+
+	diff.Subscribe(source, func(v float64) {
+		diff.PushAndEmit(2.*ma1.Last(0) - ma2.Last(0))
+		diff.Slice = diff.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
@@ -0,0 +1,90 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const DefaultHurstWindow = 100
+
+// HurstStream is the rolling Hurst exponent of the source, estimated by the scaling of the standard deviation of
+// the lagged differences, std(x[t] - x[t-lag]) ~ lag^H, over the lags from 2 to window / 2.
+//
+// H is about 0.5 for a random walk, greater than 0.5 for a trending series and less than 0.5 for a mean reverting series.
+// The source is usually the log prices, the value is 0.5 until the window is filled.
+type HurstStream struct {
+	*types.Float64Series
+
+	window int
+	values floats.Slice
+}
+
+func Hurst(source types.Float64Source, window int) *HurstStream {
+	if window == 0 {
+		window = DefaultHurstWindow
+	}
+
+	if window < 8 {
+		panic("the window of hurst exponent can not be less than 8")
+	}
+
+	s := &HurstStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *HurstStream) Calculate(v float64) float64 {
+	s.values.Push(v)
+	s.values = s.values.Truncate(s.window)
+	if s.values.Length() < s.window {
+		return 0.5
+	}
+
+	var logLags, logStds floats.Slice
+	for lag := 2; lag <= s.window/2; lag++ {
+		var diffs floats.Slice
+		for i := lag; i < len(s.values); i++ {
+			diffs.Push(s.values[i] - s.values[i-lag])
+		}
+
+		std := types.Stdev(diffs)
+		if std <= 0 {
+			continue
+		}
+
+		logLags.Push(math.Log(float64(lag)))
+		logStds.Push(math.Log(std))
+	}
+
+	if logLags.Length() < 2 {
+		return 0.5
+	}
+
+	return slope(logLags, logStds)
+}
+
+func (s *HurstStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
+
+// slope returns the slope of the least squares regression of y on x
+func slope(x, y floats.Slice) float64 {
+	meanX, meanY := x.Mean(), y.Mean()
+
+	var sxy, sxx float64
+	for i := range x {
+		sxy += (x[i] - meanX) * (y[i] - meanY)
+		sxx += (x[i] - meanX) * (x[i] - meanX)
+	}
+
+	if sxx == 0 {
+		return 0
+	}
+
+	return sxy / sxx
+}
@@ -0,0 +1,45 @@
+package indicatorv2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestHurst(t *testing.T) {
+	hurst := func(values []float64) float64 {
+		source := types.NewFloat64Series()
+		h := Hurst(source, 200)
+		for _, v := range values {
+			source.PushAndEmit(v)
+		}
+		return h.Last(0)
+	}
+
+	r := rand.New(rand.NewSource(42))
+
+	var randomWalk, meanReverting, trending []float64
+	var x, y, z, drift float64
+	for i := 0; i < 200; i++ {
+		x += r.NormFloat64()
+		randomWalk = append(randomWalk, x)
+
+		y = 0.2*y + r.NormFloat64()
+		meanReverting = append(meanReverting, y)
+
+		drift = 0.98*drift + r.NormFloat64()
+		z += drift
+		trending = append(trending, z)
+	}
+
+	assert.InDelta(t, 0.5, hurst(randomWalk), 0.15)
+	assert.Less(t, hurst(meanReverting), 0.2)
+	assert.Greater(t, hurst(trending), 0.7)
+
+	assert.Equal(t, 0.5, hurst(randomWalk[:100]), "the value before the window is filled")
+	assert.False(t, math.IsNaN(hurst(make([]float64, 200))))
+}
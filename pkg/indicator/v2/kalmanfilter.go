@@ -0,0 +1,59 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// KalmanFilterStream is a one-dimensional kalman filter, the process noise is estimated from the mean of
+// the squared moves of the values in the window, and it can be scaled up by the additional smooth window.
+type KalmanFilterStream struct {
+	*types.Float64Series
+
+	additionalSmoothWindow int
+	amp2                   *types.Queue // measurement uncertainty
+	k                      float64      // Kalman gain
+	lastMeasurement        float64
+}
+
+func KalmanFilter(source types.Float64Source, window, additionalSmoothWindow int) *KalmanFilterStream {
+	checkWindow(window)
+
+	s := &KalmanFilterStream{
+		Float64Series:          types.NewFloat64Series(),
+		additionalSmoothWindow: additionalSmoothWindow,
+		amp2:                   types.NewQueue(window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *KalmanFilterStream) Calculate(v float64) float64 {
+	if s.Length() == 0 {
+		s.amp2.Update(v * v)
+		s.lastMeasurement = v
+		return v
+	}
+
+	amp := math.Abs(v - s.lastMeasurement)
+	s.lastMeasurement = v
+
+	// measurement
+	s.amp2.Update(amp * amp)
+	q := math.Sqrt(types.Mean(s.amp2)) * float64(1+s.additionalSmoothWindow)
+
+	// update
+	lastPredict := s.Slice.Last(0)
+	curState := v + (v - lastPredict)
+	estimated := lastPredict + s.k*(curState-lastPredict)
+
+	// predict
+	p := math.Abs(curState - estimated)
+	s.k = p / (p + q)
+	return estimated
+}
+
+func (s *KalmanFilterStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
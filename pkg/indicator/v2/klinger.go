@@ -0,0 +1,81 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+const (
+	DefaultKlingerFastWindow = 34
+	DefaultKlingerSlowWindow = 55
+)
+
+// KlingerOscillatorStream is the Klinger Volume Oscillator, the difference between the fast EMA and the slow EMA
+// of the volume force.
+type KlingerOscillatorStream struct {
+	*types.Float64Series
+
+	// VolumeForce is the volume force series, the trend-adjusted volume
+	VolumeForce *types.Float64Series
+
+	fast, slow *EWMAStream
+
+	dm, cm, trend, lastSum float64
+}
+
+// KlingerOscillator creates the klinger oscillator stream, the default windows (34, 55) are used if the windows are zero
+func KlingerOscillator(source KLineSubscription, fastWindow, slowWindow int) *KlingerOscillatorStream {
+	if fastWindow == 0 {
+		fastWindow = DefaultKlingerFastWindow
+	}
+	if slowWindow == 0 {
+		slowWindow = DefaultKlingerSlowWindow
+	}
+
+	vf := types.NewFloat64Series()
+	s := &KlingerOscillatorStream{
+		Float64Series: types.NewFloat64Series(),
+		VolumeForce:   vf,
+		fast:          EWMA2(vf, fastWindow),
+		slow:          EWMA2(vf, slowWindow),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low, closePrice, volume := k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64()
+		if s.lastSum == 0 {
+			// the first volume force can not be calculated
+			s.dm = high - low
+			s.cm = s.dm
+			s.trend = 1.
+			s.lastSum = high + low + closePrice
+			return
+		}
+
+		vf.PushAndEmit(s.calculateVolumeForce(high, low, closePrice, volume))
+		vf.Slice = vf.Slice.Truncate(MaxNumOfEWMA)
+
+		s.PushAndEmit(s.fast.Last(0) - s.slow.Last(0))
+		s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
+
+func (s *KlingerOscillatorStream) calculateVolumeForce(high, low, closePrice, volume float64) float64 {
+	trend := 1.
+	if high+low+closePrice <= s.lastSum {
+		trend = -1.
+	}
+
+	dm := high - low
+	if s.trend == trend {
+		s.cm = s.cm + dm
+	} else {
+		s.cm = s.dm + dm
+	}
+
+	s.trend = trend
+	s.lastSum = high + low + closePrice
+	s.dm = dm
+	if s.cm == 0 {
+		return 0
+	}
+
+	return volume * (2.*(s.dm/s.cm) - 1.) * trend
+}
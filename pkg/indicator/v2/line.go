@@ -0,0 +1,58 @@
+package indicatorv2
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LineStream is a straight line through two points, which simulates a trend, support or resistance line.
+// The points are indexed by the number of the klines before the latest kline, the indexes are shifted by the
+// closed klines, and the value of the line at each closed kline is pushed.
+type LineStream struct {
+	*types.Float64Series
+
+	start, end           float64
+	startIndex, endIndex int
+
+	lastTime time.Time
+}
+
+func Line(source KLineSubscription, startIndex int, startValue float64, endIndex int, endValue float64) *LineStream {
+	s := &LineStream{
+		Float64Series: types.NewFloat64Series(),
+		start:         startValue,
+		end:           endValue,
+		startIndex:    startIndex,
+		endIndex:      endIndex,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		delta := 1
+		if k.Interval != "" && !s.lastTime.IsZero() {
+			delta = int(k.EndTime.Time().Sub(s.lastTime) / k.Interval.Duration())
+		}
+		s.lastTime = k.EndTime.Time()
+
+		s.startIndex += delta
+		s.endIndex += delta
+		s.PushAndEmit(s.At(0))
+		s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
+
+// At returns the value of the line at the i-th kline before the latest kline
+func (s *LineStream) At(i int) float64 {
+	return (s.end-s.start)/float64(s.startIndex-s.endIndex)*float64(s.endIndex-i) + s.end
+}
+
+func (s *LineStream) SetXY1(index int, value float64) {
+	s.startIndex = index
+	s.start = value
+}
+
+func (s *LineStream) SetXY2(index int, value float64) {
+	s.endIndex = index
+	s.end = value
+}
@@ -0,0 +1,60 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// LinRegStream calculates the slope of the linear regression line of the close prices in the window.
+// The slope is 0 before the window is filled.
+type LinRegStream struct {
+	*types.Float64Series
+
+	// ValueRatios are the slopes divided by the close prices
+	ValueRatios *types.Float64Series
+
+	closes *types.Queue
+	window int
+}
+
+func LinReg(source KLineSubscription, window int) *LinRegStream {
+	checkWindow(window)
+
+	s := &LinRegStream{
+		Float64Series: types.NewFloat64Series(),
+		ValueRatios:   types.NewFloat64Series(),
+		closes:        types.NewQueue(window),
+		window:        window,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		closePrice := k.Close.Float64()
+		s.closes.Update(closePrice)
+		if s.closes.Length() < s.window || s.window < 2 {
+			s.PushAndEmit(0)
+			s.ValueRatios.PushAndEmit(0)
+			return
+		}
+
+		slope := s.calculate()
+		s.PushAndEmit(slope)
+		s.ValueRatios.PushAndEmit(slope / closePrice)
+	})
+	return s
+}
+
+func (s *LinRegStream) calculate() float64 {
+	var sumX, sumY, sumXSqr, sumXY float64
+	for i := 0; i < s.window; i++ {
+		val := s.closes.Last(i)
+		per := float64(i + 1)
+		sumX += per
+		sumY += val
+		sumXSqr += per * per
+		sumXY += val * per
+	}
+
+	length := float64(s.window)
+	slope := (length*sumXY - sumX*sumY) / (length*sumXSqr - sumX*sumX)
+	average := sumY / length
+	endPrice := average - slope*sumX/length + slope
+	startPrice := endPrice + slope*(length-1)
+	return (endPrice - startPrice) / (length - 1)
+}
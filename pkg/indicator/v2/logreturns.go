@@ -0,0 +1,30 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// LogReturnsStream is the log return of the source values, ln(v[t] / v[t-1]).
+// The first value of the source has no return, and it's not emitted.
+type LogReturnsStream struct {
+	*types.Float64Series
+
+	prev float64
+}
+
+func LogReturns(source types.Float64Source) *LogReturnsStream {
+	s := &LogReturnsStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	s.Subscribe(source, func(v float64) {
+		if s.prev > 0 && v > 0 {
+			s.PushAndEmit(math.Log(v / s.prev))
+			s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+		}
+		s.prev = v
+	})
+	return s
+}
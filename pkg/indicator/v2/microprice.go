@@ -0,0 +1,44 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// MicroPriceStream calculates the volume-weighted mid price of the best bid and ask,
+// (bidPrice * askVolume + askPrice * bidVolume) / (bidVolume + askVolume).
+// The micro price leans toward the side with the thinner queue.
+type MicroPriceStream struct {
+	*types.Float64Series
+}
+
+func MicroPrice(source BookSubscription) *MicroPriceStream {
+	s := &MicroPriceStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	source.AddSubscriber(func(book types.OrderBook) {
+		if v, ok := s.Calculate(book); ok {
+			s.PushAndEmit(v)
+			s.Truncate()
+		}
+	})
+	return s
+}
+
+func (s *MicroPriceStream) Calculate(book types.OrderBook) (float64, bool) {
+	bid, ask, ok := topOfBook(book)
+	if !ok {
+		return 0.0, false
+	}
+
+	total := bid.Volume.Add(ask.Volume)
+	if total.IsZero() {
+		return midPrice(bid, ask).Float64(), true
+	}
+
+	return bid.Price.Mul(ask.Volume).Add(ask.Price.Mul(bid.Volume)).Div(total).Float64(), true
+}
+
+func (s *MicroPriceStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfBooks)
+}
@@ -0,0 +1,41 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// OBVStream is the On-Balance Volume, the cumulative volume that is added when the close price rises
+// and subtracted when the close price falls
+//
+// It deviates from the v1 OBV, which compares the volume with the first close price instead of comparing the close
+// price with the previous one, so the v1 OBV adds the volumes that are larger than the first close price.
+type OBVStream struct {
+	*types.Float64Series
+
+	prevClose float64
+}
+
+func OBV(source KLineSubscription) *OBVStream {
+	s := &OBVStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		closePrice, volume := k.Close.Float64(), k.Volume.Float64()
+		if s.Length() == 0 {
+			s.prevClose = closePrice
+			s.PushAndEmit(volume)
+			return
+		}
+
+		obv := s.Slice.Last(0)
+		if closePrice > s.prevClose {
+			obv += volume
+		} else if closePrice < s.prevClose {
+			obv -= volume
+		}
+
+		s.prevClose = closePrice
+		s.PushAndEmit(obv)
+		s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
@@ -0,0 +1,28 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_OBV(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := KLines(stream, "", "")
+	obv := OBV(kLines)
+
+	closePrices := []float64{10, 11, 11, 9, 12}
+	volumes := []float64{100, 200, 300, 400, 500}
+	for i, c := range closePrices {
+		stream.EmitKLineClosed(types.KLine{
+			Close:  fixedpoint.NewFromFloat(c),
+			Volume: fixedpoint.NewFromFloat(volumes[i]),
+		})
+	}
+
+	// 100, +200, unchanged, -400, +500
+	assert.Equal(t, []float64{100, 300, 300, -100, 400}, []float64(obv.Slice))
+}
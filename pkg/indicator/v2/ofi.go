@@ -0,0 +1,65 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OFIStream calculates the order flow imbalance of the successive books (Cont, Kukanov and Stoikov),
+// the bid contribution is positive when the bid queue grows or the bid price moves up,
+// and the ask contribution is negative when the ask queue grows or the ask price moves down.
+type OFIStream struct {
+	*types.Float64Series
+
+	lastBid, lastAsk types.PriceVolume
+	hasLast          bool
+}
+
+func OFI(source BookSubscription) *OFIStream {
+	s := &OFIStream{
+		Float64Series: types.NewFloat64Series(),
+	}
+
+	source.AddSubscriber(func(book types.OrderBook) {
+		if v, ok := s.Calculate(book); ok {
+			s.PushAndEmit(v)
+			s.Truncate()
+		}
+	})
+	return s
+}
+
+func (s *OFIStream) Calculate(book types.OrderBook) (float64, bool) {
+	bid, ask, ok := topOfBook(book)
+	if !ok {
+		return 0.0, false
+	}
+
+	if !s.hasLast {
+		s.lastBid, s.lastAsk, s.hasLast = bid, ask, true
+		return 0.0, false
+	}
+
+	e := fixedpoint.Zero
+
+	if bid.Price.Compare(s.lastBid.Price) >= 0 {
+		e = e.Add(bid.Volume)
+	}
+	if bid.Price.Compare(s.lastBid.Price) <= 0 {
+		e = e.Sub(s.lastBid.Volume)
+	}
+
+	if ask.Price.Compare(s.lastAsk.Price) <= 0 {
+		e = e.Sub(ask.Volume)
+	}
+	if ask.Price.Compare(s.lastAsk.Price) >= 0 {
+		e = e.Add(s.lastAsk.Volume)
+	}
+
+	s.lastBid, s.lastAsk = bid, ask
+	return e.Float64(), true
+}
+
+func (s *OFIStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfBooks)
+}
@@ -0,0 +1,41 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ParkinsonStream is the Parkinson volatility estimator, which estimates the volatility from the high and low prices:
+//
+//	sigma^2 = 1 / (4 * ln(2)) * mean(ln(high / low)^2)
+//
+// The value is the volatility per kline, it's not annualized.
+type ParkinsonStream struct {
+	*types.Float64Series
+
+	window int
+	terms  *types.Queue
+}
+
+func Parkinson(source KLineSubscription, window int) *ParkinsonStream {
+	checkWindow(window)
+	s := &ParkinsonStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		terms:         types.NewQueue(window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low := k.High.Float64(), k.Low.Float64()
+		if low <= 0 {
+			return
+		}
+
+		hl := math.Log(high / low)
+		s.terms.Update(hl * hl)
+		s.PushAndEmit(math.Sqrt(s.terms.Mean(s.window) / (4.0 * math.Ln2)))
+		s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
@@ -0,0 +1,45 @@
+package indicatorv2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func ohlc(o, h, l, c float64) types.KLine {
+	return types.KLine{
+		Open:  fixedpoint.NewFromFloat(o),
+		High:  fixedpoint.NewFromFloat(h),
+		Low:   fixedpoint.NewFromFloat(l),
+		Close: fixedpoint.NewFromFloat(c),
+	}
+}
+
+func TestParkinsonAndGarmanKlass(t *testing.T) {
+	source := &KLineStream{}
+	parkinson := Parkinson(source, 2)
+	garmanKlass := GarmanKlass(source, 2)
+
+	kLines := []types.KLine{
+		ohlc(100, 110, 90, 105),
+		ohlc(105, 108, 102, 103),
+		ohlc(103, 104, 100, 100),
+	}
+	source.BackFill(kLines)
+
+	var pTerms, gkTerms []float64
+	for _, k := range kLines[1:] {
+		hl := math.Log(k.High.Float64() / k.Low.Float64())
+		co := math.Log(k.Close.Float64() / k.Open.Float64())
+		pTerms = append(pTerms, hl*hl)
+		gkTerms = append(gkTerms, 0.5*hl*hl-(2*math.Ln2-1)*co*co)
+	}
+
+	assert.Equal(t, 3, parkinson.Length())
+	assert.InDelta(t, math.Sqrt((pTerms[0]+pTerms[1])/2/(4*math.Ln2)), parkinson.Last(0), 1e-12)
+	assert.InDelta(t, math.Sqrt((gkTerms[0]+gkTerms[1])/2), garmanKlass.Last(0), 1e-12)
+}
@@ -0,0 +1,47 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PivotStream finds the pivot lows and the pivot highs of the klines in the window, a pivot is the lowest low
+// or the highest high of the window, which is located at the middle of the window.
+type PivotStream struct {
+	// Lows are the pivot lows, Highs are the pivot highs
+	Lows, Highs *types.Float64Series
+
+	lows, highs *types.Queue
+	window      int
+}
+
+func Pivot(source KLineSubscription, window int) *PivotStream {
+	checkWindow(window)
+
+	s := &PivotStream{
+		Lows:   types.NewFloat64Series(),
+		Highs:  types.NewFloat64Series(),
+		lows:   types.NewQueue(window),
+		highs:  types.NewQueue(window),
+		window: window,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.lows.Update(k.Low.Float64())
+		s.highs.Update(k.High.Float64())
+		if s.lows.Length() < s.window {
+			return
+		}
+
+		middle := s.window/2 - 1
+		if low := types.Lowest(s.lows, s.window); low > 0 && low == s.lows.Last(middle) {
+			s.Lows.PushAndEmit(low)
+			s.Lows.Slice = s.Lows.Slice.Truncate(MaxNumOfSMA)
+		}
+
+		if high := types.Highest(s.highs, s.window); high > 0 && high == s.highs.Last(middle) {
+			s.Highs.PushAndEmit(high)
+			s.Highs.Slice = s.Highs.Slice.Truncate(MaxNumOfSMA)
+		}
+	})
+	return s
+}
@@ -0,0 +1,143 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PivotSupertrendStream is the supertrend whose center line is the weighted average of the pivot points instead of
+// the middle price, the embedded series is the trend line. Nothing is pushed before the first pivot point is found.
+//
+// based on "Pivot Point Supertrend by LonesomeTheBlue" from tradingview
+type PivotSupertrendStream struct {
+	*types.Float64Series
+
+	// Support is the support line in an uptrend, Resistance is the resistance line in a downtrend
+	Support, Resistance *types.Float64Series
+
+	atrMultiplier float64
+	atr           *ATRStream
+
+	pivotLow  *PivotLowStream
+	pivotHigh *PivotHighStream
+
+	previousPivotLow, previousPivotHigh float64
+
+	// lastPivot is the latest pivot point, center is the weighted center line of the pivot points
+	lastPivot, center float64
+
+	closePrice, previousClosePrice float64
+	uptrendPrice, downtrendPrice   float64
+
+	trend       types.Direction
+	tradeSignal types.Direction
+}
+
+func PivotSupertrend(source KLineSubscription, window, pivotWindow int, atrMultiplier float64) *PivotSupertrendStream {
+	s := &PivotSupertrendStream{
+		Float64Series: types.NewFloat64Series(),
+		Support:       types.NewFloat64Series(),
+		Resistance:    types.NewFloat64Series(),
+		atrMultiplier: atrMultiplier,
+		atr:           ATR2(source, window),
+		pivotLow:      PivotLow(LowPrices(source), pivotWindow),
+		pivotHigh:     PivotHigh(HighPrices(source), pivotWindow),
+		trend:         types.DirectionUp,
+	}
+
+	// the pivots and the atr are subscribed before, so they are updated with the kline already
+	source.AddSubscriber(func(k types.KLine) {
+		s.update(k.Close.Float64())
+	})
+	return s
+}
+
+func (s *PivotSupertrendStream) update(closePrice float64) {
+	var (
+		atr                    = s.atr.Last(0)
+		pivotLow               = s.pivotLow.Last(0)
+		pivotHigh              = s.pivotHigh.Last(0)
+		previousTrend          = s.trend
+		previousUptrendPrice   = s.uptrendPrice
+		previousDowntrendPrice = s.downtrendPrice
+		newPivotLow            = pivotLow != s.previousPivotLow
+		newPivotHigh           = pivotHigh != s.previousPivotHigh
+	)
+
+	s.previousPivotLow, s.previousPivotHigh = pivotLow, pivotHigh
+	s.previousClosePrice = s.closePrice
+	s.closePrice = closePrice
+
+	if s.lastPivot == 0 {
+		if s.pivotHigh.Length() > 0 {
+			s.lastPivot = pivotHigh
+		} else if s.pivotLow.Length() > 0 {
+			s.lastPivot = pivotLow
+		} else {
+			return
+		}
+	}
+
+	// the last pivot is only changed when a new pivot point is found
+	if newPivotHigh {
+		s.lastPivot = pivotHigh
+	} else if newPivotLow {
+		s.lastPivot = pivotLow
+	}
+
+	if s.center == 0 {
+		s.center = s.lastPivot
+	} else {
+		s.center = (s.center*2 + s.lastPivot) / 3
+	}
+
+	s.uptrendPrice = s.center - atr*s.atrMultiplier
+	if s.previousClosePrice > previousUptrendPrice {
+		s.uptrendPrice = math.Max(s.uptrendPrice, previousUptrendPrice)
+	}
+
+	s.downtrendPrice = s.center + atr*s.atrMultiplier
+	if s.previousClosePrice < previousDowntrendPrice {
+		s.downtrendPrice = math.Min(s.downtrendPrice, previousDowntrendPrice)
+	}
+
+	if previousTrend == types.DirectionUp && closePrice < previousUptrendPrice {
+		s.trend = types.DirectionDown
+	} else if previousTrend == types.DirectionDown && closePrice > previousDowntrendPrice {
+		s.trend = types.DirectionUp
+	}
+
+	switch {
+	case atr <= 0:
+		s.tradeSignal = types.DirectionNone
+	case s.trend == types.DirectionUp && previousTrend == types.DirectionDown:
+		s.tradeSignal = types.DirectionUp
+	case s.trend == types.DirectionDown && previousTrend == types.DirectionUp:
+		s.tradeSignal = types.DirectionDown
+	default:
+		s.tradeSignal = types.DirectionNone
+	}
+
+	s.Support.PushAndEmit(s.uptrendPrice)
+	s.Resistance.PushAndEmit(s.downtrendPrice)
+	s.Support.Slice = s.Support.Slice.Truncate(MaxNumOfEWMA)
+	s.Resistance.Slice = s.Resistance.Slice.Truncate(MaxNumOfEWMA)
+
+	if s.trend == types.DirectionDown {
+		s.PushAndEmit(s.downtrendPrice)
+	} else {
+		s.PushAndEmit(s.uptrendPrice)
+	}
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
+
+// Direction returns the current trend
+func (s *PivotSupertrendStream) Direction() types.Direction {
+	return s.trend
+}
+
+// Signal returns the trade signal of the last kline, it's DirectionNone if the trend is not reversed
+func (s *PivotSupertrendStream) Signal() types.Direction {
+	return s.tradeSignal
+}
@@ -0,0 +1,114 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const psarAccelerationFactorStep = 0.02
+
+// PSARStream is the Parabolic Stop and Reverse, the embedded series is the stop and reverse price.
+// The acceleration factor starts from 0.02 and it's increased by 0.02 whenever a new extreme point is made, up to 0.2.
+// There is no value for the first kline.
+type PSARStream struct {
+	*types.Float64Series
+
+	// Falling is true if the PSAR is above the price
+	Falling bool
+
+	window     int
+	highs      *types.Queue
+	lows       *types.Queue
+	af         float64 // acceleration factor
+	ep         float64 // extreme point
+	numOfKLine int
+}
+
+func PSAR(source KLineSubscription, window int) *PSARStream {
+	checkWindow(window)
+
+	// the SAR is bounded by the highs or the lows of the last 2 klines, so at least 3 of them are kept
+	s := &PSARStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		highs:         types.NewQueue(max(window, 3)),
+		lows:          types.NewQueue(max(window, 3)),
+		af:            psarAccelerationFactorStep,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		high, low := k.High.Float64(), k.Low.Float64()
+		s.numOfKLine++
+		isWarmingUp := s.numOfKLine <= s.window
+
+		s.highs.Update(high)
+		s.lows.Update(low)
+		if s.numOfKLine == 1 {
+			return
+		}
+
+		if isWarmingUp {
+			s.Falling = s.falling()
+			if s.Falling {
+				s.ep = s.lows.Last(1)
+				s.PushAndEmit(s.highs.Last(1))
+			} else {
+				s.ep = s.highs.Last(1)
+				s.PushAndEmit(s.lows.Last(1))
+			}
+			return
+		}
+
+		s.PushAndEmit(s.calculate(high, low))
+		s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
+
+func (s *PSARStream) falling() bool {
+	up := s.highs.Last(0) - s.highs.Last(1)
+	dn := s.lows.Last(1) - s.lows.Last(0)
+	return (dn > up) && (dn > 0)
+}
+
+func (s *PSARStream) calculate(high, low float64) float64 {
+	prevPSAR := s.Slice.Last(0)
+	if s.Falling {
+		psar := prevPSAR - s.af*(prevPSAR-s.ep)
+		result := math.Max(psar, math.Max(s.highs.Last(1), s.highs.Last(2)))
+		if low < s.ep {
+			s.ep = low
+			s.increaseAccelerationFactor()
+		}
+
+		if high > psar { // reverse
+			result = s.ep
+			s.af = psarAccelerationFactorStep
+			s.ep = high
+			s.Falling = false
+		}
+		return result
+	}
+
+	psar := prevPSAR + s.af*(s.ep-prevPSAR)
+	result := math.Min(psar, math.Min(s.lows.Last(1), s.lows.Last(2)))
+	if high > s.ep {
+		s.ep = high
+		s.increaseAccelerationFactor()
+	}
+
+	if low < psar { // reverse
+		result = s.ep
+		s.af = psarAccelerationFactorStep
+		s.ep = low
+		s.Falling = true
+	}
+	return result
+}
+
+func (s *PSARStream) increaseAccelerationFactor() {
+	if s.af <= 0.18 {
+		s.af += psarAccelerationFactorStep
+	}
+}
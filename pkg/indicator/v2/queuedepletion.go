@@ -0,0 +1,73 @@
+package indicatorv2
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// QueueDepletionStream calculates the depletion rate of the best price queue of the side, in base volume per second.
+// The rate is the decreased volume when the best price is unchanged, the whole previous queue when the best price
+// moves away from the spread, and zero when the queue grows or the best price improves.
+type QueueDepletionStream struct {
+	*types.Float64Series
+
+	side     types.SideType
+	last     types.PriceVolume
+	lastTime time.Time
+}
+
+func QueueDepletion(source BookSubscription, side types.SideType) *QueueDepletionStream {
+	s := &QueueDepletionStream{
+		Float64Series: types.NewFloat64Series(),
+		side:          side,
+	}
+
+	source.AddSubscriber(func(book types.OrderBook) {
+		if v, ok := s.Calculate(book); ok {
+			s.PushAndEmit(v)
+			s.Truncate()
+		}
+	})
+	return s
+}
+
+func (s *QueueDepletionStream) Calculate(book types.OrderBook) (float64, bool) {
+	pv, ok := book.SideBook(s.side).First()
+	if !ok {
+		return 0.0, false
+	}
+
+	now := book.LastUpdateTime()
+	last, lastTime := s.last, s.lastTime
+	s.last, s.lastTime = pv, now
+
+	if lastTime.IsZero() {
+		return 0.0, false
+	}
+
+	seconds := now.Sub(lastTime).Seconds()
+	if seconds <= 0 {
+		return 0.0, false
+	}
+
+	// compare the prices in the direction of the side, positive means the best price moves away from the spread
+	cmp := last.Price.Compare(pv.Price)
+	if s.side == types.SideTypeSell {
+		cmp = -cmp
+	}
+
+	switch {
+	case cmp > 0:
+		return last.Volume.Float64() / seconds, true
+
+	case cmp == 0 && pv.Volume.Compare(last.Volume) < 0:
+		return last.Volume.Sub(pv.Volume).Float64() / seconds, true
+	}
+
+	return 0.0, true
+}
+
+func (s *QueueDepletionStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfBooks)
+}
@@ -0,0 +1,42 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Resample creates a KLine stream of the higher interval by aggregating the closed klines of the source.
+//
+// The higher-timeframe kline is emitted in the same update of the source kline that closes its window,
+// so the subscribers of the source added after Resample always see the higher-timeframe kline closed at the same time,
+// no matter the order of the kline events of the different intervals in the live stream or in the backtest.
+// The first window is dropped if the source starts in the middle of it.
+func Resample(source KLineSubscription, interval types.Interval) *KLineStream {
+	s := &KLineStream{}
+
+	var aggregator *types.KLineAggregator
+	var skipFirst bool
+	source.AddSubscriber(func(k types.KLine) {
+		if aggregator == nil {
+			aggregator = types.NewKLineAggregator(k.Symbol, interval, k.Interval)
+			aggregator.OnKLineClosed(func(k types.KLine) {
+				if skipFirst {
+					skipFirst = false
+					return
+				}
+
+				s.kLines = append(s.kLines, k)
+				s.EmitUpdate(k)
+
+				if len(s.kLines) > MaxNumOfKLines {
+					s.kLines = s.kLines[len(s.kLines)-1-MaxNumOfKLines:]
+				}
+			})
+
+			skipFirst = !types.KLineStartTime(k.StartTime.Time(), interval).Equal(k.StartTime.Time())
+		}
+
+		aggregator.AddKLine(k)
+	})
+
+	return s
+}
@@ -0,0 +1,61 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestResample(t *testing.T) {
+	kLines := buildTestKLines(100)
+	for i := range kLines {
+		kLines[i].Symbol = "BTCUSDT"
+		kLines[i].Interval = types.Interval1m
+	}
+
+	// start in the middle of the first 15m window
+	kLines = kLines[5:]
+
+	source := &KLineStream{}
+	resampled := Resample(source, types.Interval15m)
+	aligned := Align(resampled, ClosePrices(resampled))
+
+	// the 1m handler subscribed after the resampled stream sees the 15m kline closed at the same time
+	var numOfChecks int
+	source.OnUpdate(func(k types.KLine) {
+		minute := int(k.StartTime.Time().Sub(kLines[0].StartTime.Time()).Minutes()) + 5
+		value, ok := aligned.AsOf(k.EndTime.Time(), 0)
+		if minute < 29 {
+			assert.False(t, ok, "minute %d", minute)
+			return
+		}
+
+		// the close price of the last closed 15m window
+		last := (minute+1)/15*15 - 1
+		require.True(t, ok, "minute %d", minute)
+		assert.Equal(t, kLines[last-5].Close.Float64(), value, "minute %d", minute)
+		numOfChecks++
+	})
+	source.BackFill(kLines)
+	assert.Equal(t, 99-29+1, numOfChecks)
+
+	expected := types.AggregateKLines(kLines, types.Interval15m, types.Interval1m)
+	require.Len(t, expected, 5)
+	assert.Equal(t, expected, resampled.kLines)
+
+	// the aligned series created after the history is filled
+	late := Align(resampled, ClosePrices(resampled))
+	assert.Equal(t, aligned.Length(), late.Length())
+	for _, k := range kLines {
+		v1, ok1 := aligned.AsOf(k.EndTime.Time(), 1)
+		v2, ok2 := late.AsOf(k.EndTime.Time(), 1)
+		assert.Equal(t, ok1, ok2)
+		assert.Equal(t, v1, v2)
+	}
+
+	_, ok := aligned.AsOf(kLines[len(kLines)-1].EndTime.Time(), 5)
+	assert.False(t, ok)
+}
@@ -0,0 +1,315 @@
+package indicatorv2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// StreamState is the serializable state of a stream
+type StreamState struct {
+	// Values are the values of the series
+	Values []float64 `json:"values,omitempty"`
+
+	// Vars are the private variables of the calculation
+	Vars map[string]float64 `json:"vars,omitempty"`
+
+	// Streams are the states of the inner streams
+	Streams map[string]*StreamState `json:"streams,omitempty"`
+}
+
+func (s *StreamState) stream(name string) (*StreamState, error) {
+	if state, ok := s.Streams[name]; ok && state != nil {
+		return state, nil
+	}
+
+	return nil, fmt.Errorf("the state of the inner stream %s is missing", name)
+}
+
+func (s *StreamState) restoreStreams(streams map[string]StateSnapshotter) error {
+	for name, stream := range streams {
+		state, err := s.stream(name)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.RestoreState(state); err != nil {
+			return fmt.Errorf("unable to restore the inner stream %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// StateSnapshotter is implemented by the recursive streams, e.g., EWMA and RMA. Their values depend on the whole
+// kline history, so they can't be rebuilt from a limited kline window on restart without the snapshot of their state.
+type StateSnapshotter interface {
+	SnapshotState() *StreamState
+	RestoreState(state *StreamState) error
+}
+
+// RestoreStream creates the stream and restores its state saved after the kline closed at the given time.
+//
+// The stream is created on a kline stream which pushes the klines of the source closed after the given time only.
+// The earlier klines are kept in the history of the kline stream without being pushed, so that the restored state
+// is not updated with the same klines twice. An error is returned if the kline closed at the given time is not in
+// the source, since the klines between the snapshot and the source would be missing.
+func RestoreStream[T StateSnapshotter](
+	source *KLineStream, state *StreamState, endTime time.Time, create func(source *KLineStream) T,
+) (T, error) {
+	found := false
+	for _, k := range source.kLines {
+		if k.EndTime.Time().Equal(endTime) {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		var zero T
+		return zero, fmt.Errorf("the kline closed at %s is not found, the klines after the snapshot are missing", endTime)
+	}
+
+	kLines := &KLineStream{}
+	s := create(kLines)
+	if err := s.RestoreState(state); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		kLines.kLines = append(kLines.kLines, k)
+		if k.EndTime.Time().After(endTime) {
+			kLines.EmitUpdate(k)
+		}
+
+		if len(kLines.kLines) > MaxNumOfKLines {
+			kLines.kLines = kLines.kLines[len(kLines.kLines)-1-MaxNumOfKLines:]
+		}
+	})
+
+	return s, nil
+}
+
+func snapshotValues(values floats.Slice) []float64 {
+	return append([]float64(nil), values...)
+}
+
+func restoreValues(values []float64) floats.Slice {
+	return append(floats.Slice(nil), values...)
+}
+
+func (s *EWMAStream) SnapshotState() *StreamState {
+	return &StreamState{Values: snapshotValues(s.Slice)}
+}
+
+func (s *EWMAStream) RestoreState(state *StreamState) error {
+	s.Slice = restoreValues(state.Values)
+	return nil
+}
+
+func (s *RMAStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Values: snapshotValues(s.Slice),
+		Vars: map[string]float64{
+			"counter":  float64(s.counter),
+			"sum":      s.sum,
+			"previous": s.previous,
+		},
+	}
+}
+
+func (s *RMAStream) RestoreState(state *StreamState) error {
+	s.Slice = restoreValues(state.Values)
+	s.counter = int(state.Vars["counter"])
+	s.sum = state.Vars["sum"]
+	s.previous = state.Vars["previous"]
+	return nil
+}
+
+func (s *TRStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Values: snapshotValues(s.Slice),
+		Vars:   map[string]float64{"previousClose": s.previousClose},
+	}
+}
+
+func (s *TRStream) RestoreState(state *StreamState) error {
+	s.Slice = restoreValues(state.Values)
+	s.previousClose = state.Vars["previousClose"]
+	return nil
+}
+
+func (s *SubtractStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Values: snapshotValues(s.Slice),
+		Streams: map[string]*StreamState{
+			"a": {Values: snapshotValues(s.a)},
+			"b": {Values: snapshotValues(s.b)},
+		},
+	}
+}
+
+func (s *SubtractStream) RestoreState(state *StreamState) error {
+	a, err := state.stream("a")
+	if err != nil {
+		return err
+	}
+
+	b, err := state.stream("b")
+	if err != nil {
+		return err
+	}
+
+	s.Slice = restoreValues(state.Values)
+	s.a = restoreValues(a.Values)
+	s.b = restoreValues(b.Values)
+	return nil
+}
+
+func (s *ATRStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Streams: map[string]*StreamState{
+			"rma": s.RMAStream.SnapshotState(),
+			"tr":  s.tr.SnapshotState(),
+		},
+	}
+}
+
+func (s *ATRStream) RestoreState(state *StreamState) error {
+	return state.restoreStreams(map[string]StateSnapshotter{
+		"rma": s.RMAStream,
+		"tr":  s.tr,
+	})
+}
+
+func (s *ATRPStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Values: snapshotValues(s.Slice),
+		Streams: map[string]*StreamState{
+			"atr": s.atr.SnapshotState(),
+		},
+	}
+}
+
+func (s *ATRPStream) RestoreState(state *StreamState) error {
+	if err := state.restoreStreams(map[string]StateSnapshotter{"atr": s.atr}); err != nil {
+		return err
+	}
+
+	s.Slice = restoreValues(state.Values)
+	return nil
+}
+
+func (s *MACDStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Streams: map[string]*StreamState{
+			"macd":      s.SubtractStream.SnapshotState(),
+			"fast":      s.FastEWMA.SnapshotState(),
+			"slow":      s.SlowEWMA.SnapshotState(),
+			"signal":    s.Signal.SnapshotState(),
+			"histogram": s.Histogram.SnapshotState(),
+		},
+	}
+}
+
+func (s *MACDStream) RestoreState(state *StreamState) error {
+	return state.restoreStreams(map[string]StateSnapshotter{
+		"macd":      s.SubtractStream,
+		"fast":      s.FastEWMA,
+		"slow":      s.SlowEWMA,
+		"signal":    s.Signal,
+		"histogram": s.Histogram,
+	})
+}
+
+func (s *ADXStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Vars: map[string]float64{
+			"prevHigh": s.prevHigh.Float64(),
+			"prevLow":  s.prevLow.Float64(),
+		},
+		Streams: map[string]*StreamState{
+			"adx":   s.RMAStream.SnapshotState(),
+			"atr":   s.atr.SnapshotState(),
+			"dmp":   s.dmp.SnapshotState(),
+			"dmn":   s.dmn.SnapshotState(),
+			"plus":  {Values: snapshotValues(s.Plus.Slice)},
+			"minus": {Values: snapshotValues(s.Minus.Slice)},
+		},
+	}
+}
+
+func (s *ADXStream) RestoreState(state *StreamState) error {
+	if err := state.restoreStreams(map[string]StateSnapshotter{
+		"adx": s.RMAStream,
+		"atr": s.atr,
+		"dmp": s.dmp,
+		"dmn": s.dmn,
+	}); err != nil {
+		return err
+	}
+
+	plus, err := state.stream("plus")
+	if err != nil {
+		return err
+	}
+
+	minus, err := state.stream("minus")
+	if err != nil {
+		return err
+	}
+
+	s.Plus.Slice = restoreValues(plus.Values)
+	s.Minus.Slice = restoreValues(minus.Values)
+	s.prevHigh = fixedpoint.NewFromFloat(state.Vars["prevHigh"])
+	s.prevLow = fixedpoint.NewFromFloat(state.Vars["prevLow"])
+	return nil
+}
+
+func (s *KeltnerStream) SnapshotState() *StreamState {
+	return &StreamState{
+		Streams: map[string]*StreamState{
+			"ewma":            s.EWMA.SnapshotState(),
+			"atr":             s.ATR.SnapshotState(),
+			"mid":             {Values: snapshotValues(s.Mid.Slice)},
+			"firstUpperBand":  {Values: snapshotValues(s.FirstUpperBand.Slice)},
+			"firstLowerBand":  {Values: snapshotValues(s.FirstLowerBand.Slice)},
+			"secondUpperBand": {Values: snapshotValues(s.SecondUpperBand.Slice)},
+			"secondLowerBand": {Values: snapshotValues(s.SecondLowerBand.Slice)},
+			"thirdUpperBand":  {Values: snapshotValues(s.ThirdUpperBand.Slice)},
+			"thirdLowerBand":  {Values: snapshotValues(s.ThirdLowerBand.Slice)},
+		},
+	}
+}
+
+func (s *KeltnerStream) RestoreState(state *StreamState) error {
+	if err := state.restoreStreams(map[string]StateSnapshotter{
+		"ewma": s.EWMA,
+		"atr":  s.ATR,
+	}); err != nil {
+		return err
+	}
+
+	for name, series := range map[string]*types.Float64Series{
+		"mid":             s.Mid,
+		"firstUpperBand":  s.FirstUpperBand,
+		"firstLowerBand":  s.FirstLowerBand,
+		"secondUpperBand": s.SecondUpperBand,
+		"secondLowerBand": s.SecondLowerBand,
+		"thirdUpperBand":  s.ThirdUpperBand,
+		"thirdLowerBand":  s.ThirdLowerBand,
+	} {
+		values, err := state.stream(name)
+		if err != nil {
+			return err
+		}
+
+		series.Slice = restoreValues(values.Values)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package indicatorv2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// pushKLines pushes the klines to the subscribers of the stream one by one, like the live klines
+func pushKLines(s *KLineStream, kLines []types.KLine) {
+	for _, k := range kLines {
+		s.kLines = append(s.kLines, k)
+		s.EmitUpdate(k)
+	}
+}
+
+func TestRestoreStream(t *testing.T) {
+	kLines := buildTestKLines(300)
+
+	type snapshotTest struct {
+		name   string
+		create func(source *KLineStream) StateSnapshotter
+		values func(s StateSnapshotter) types.Series
+	}
+
+	tests := []snapshotTest{
+		{
+			name:   "EWMA",
+			create: func(source *KLineStream) StateSnapshotter { return EWMA2(ClosePrices(source), 50) },
+			values: func(s StateSnapshotter) types.Series { return s.(*EWMAStream) },
+		},
+		{
+			name:   "MACD",
+			create: func(source *KLineStream) StateSnapshotter { return MACD2(ClosePrices(source), 12, 26, 9) },
+			values: func(s StateSnapshotter) types.Series { return s.(*MACDStream).Histogram },
+		},
+		{
+			name:   "ATR",
+			create: func(source *KLineStream) StateSnapshotter { return ATR2(source, 14) },
+			values: func(s StateSnapshotter) types.Series { return s.(*ATRStream) },
+		},
+		{
+			name:   "ATRP",
+			create: func(source *KLineStream) StateSnapshotter { return ATRP2(source, 14) },
+			values: func(s StateSnapshotter) types.Series { return s.(*ATRPStream) },
+		},
+		{
+			name:   "ADX",
+			create: func(source *KLineStream) StateSnapshotter { return ADX(source, 14) },
+			values: func(s StateSnapshotter) types.Series { return s.(*ADXStream) },
+		},
+		{
+			name:   "Keltner",
+			create: func(source *KLineStream) StateSnapshotter { return Keltner(source, 20, 14) },
+			values: func(s StateSnapshotter) types.Series { return s.(*KeltnerStream).ThirdUpperBand },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectedSource := &KLineStream{}
+			expected := tt.create(expectedSource)
+			pushKLines(expectedSource, kLines)
+
+			// the previous run stopped after the first 200 klines
+			savedSource := &KLineStream{}
+			saved := tt.create(savedSource)
+			pushKLines(savedSource, kLines[:200])
+			data, err := json.Marshal(saved.SnapshotState())
+			require.NoError(t, err)
+
+			var state StreamState
+			require.NoError(t, json.Unmarshal(data, &state))
+
+			// only the last 50 klines of the previous run are kept by the market data store
+			source := &KLineStream{kLines: append([]types.KLine(nil), kLines[150:200]...)}
+			restored, err := RestoreStream(source, &state, kLines[199].EndTime.Time(), tt.create)
+			require.NoError(t, err)
+
+			pushKLines(source, kLines[200:])
+
+			expectedValues, restoredValues := tt.values(expected), tt.values(restored)
+			require.Equal(t, expectedValues.Length(), restoredValues.Length())
+			for i := 0; i < expectedValues.Length(); i++ {
+				if !assert.InDelta(t, expectedValues.Last(i), restoredValues.Last(i), 1e-9, "the %d-th last value", i) {
+					return
+				}
+			}
+		})
+	}
+
+	t.Run("the klines after the snapshot are missing", func(t *testing.T) {
+		state := EWMA2(ClosePrices(&KLineStream{kLines: kLines[:200]}), 50).SnapshotState()
+		_, err := RestoreStream(&KLineStream{kLines: kLines[250:]}, state, kLines[199].EndTime.Time(),
+			func(source *KLineStream) *EWMAStream {
+				return EWMA2(ClosePrices(source), 50)
+			})
+		assert.Error(t, err)
+	})
+}
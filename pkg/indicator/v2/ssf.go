@@ -0,0 +1,59 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SSFStream is the Ehlers Super Smoother Filter, a 2-pole or 3-pole butterworth filter
+type SSFStream struct {
+	*types.Float64Series
+
+	poles          int
+	c1, c2, c3, c4 float64
+}
+
+// SSF creates the super smoother filter, the poles can be 2 or 3
+func SSF(source types.Float64Source, window, poles int) *SSFStream {
+	checkWindow(window)
+
+	s := &SSFStream{
+		Float64Series: types.NewFloat64Series(),
+		poles:         poles,
+	}
+
+	if poles == 3 {
+		x := math.Pi / float64(window)
+		a0 := math.Exp(-x)
+		b0 := 2. * a0 * math.Cos(math.Sqrt(3.)*x)
+		c0 := a0 * a0
+
+		s.c4 = c0 * c0
+		s.c3 = -c0 * (1. + b0)
+		s.c2 = c0 + b0
+		s.c1 = 1. - s.c2 - s.c3 - s.c4
+	} else {
+		s.poles = 2
+		x := math.Pi * math.Sqrt(2.) / float64(window)
+		a0 := math.Exp(-x)
+		s.c3 = -a0 * a0
+		s.c2 = 2. * a0 * math.Cos(x)
+		s.c1 = 1. - s.c2 - s.c3
+	}
+
+	s.Bind(source, s)
+	return s
+}
+
+func (s *SSFStream) Calculate(v float64) float64 {
+	result := s.c1*v + s.c2*s.Slice.Last(0) + s.c3*s.Slice.Last(1)
+	if s.poles == 3 {
+		result += s.c4 * s.Slice.Last(2)
+	}
+	return result
+}
+
+func (s *SSFStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
@@ -0,0 +1,102 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// SupertrendStream is the supertrend indicator, the embedded series is the trend line,
+// which is the support line in an uptrend and the resistance line in a downtrend.
+type SupertrendStream struct {
+	*types.Float64Series
+
+	// Support is the support line in an uptrend, Resistance is the resistance line in a downtrend
+	Support, Resistance *types.Float64Series
+
+	atrMultiplier float64
+	atr           *ATRStream
+
+	closePrice, previousClosePrice float64
+
+	trend       types.Direction
+	tradeSignal types.Direction
+}
+
+func Supertrend(source KLineSubscription, window int, atrMultiplier float64) *SupertrendStream {
+	s := &SupertrendStream{
+		Float64Series: types.NewFloat64Series(),
+		Support:       types.NewFloat64Series(),
+		Resistance:    types.NewFloat64Series(),
+		atrMultiplier: atrMultiplier,
+		atr:           ATR2(source, window),
+		trend:         types.DirectionUp,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.update(k.High.Float64(), k.Low.Float64(), k.Close.Float64())
+	})
+	return s
+}
+
+func (s *SupertrendStream) update(high, low, closePrice float64) {
+	var (
+		atr                    = s.atr.Last(0)
+		previousTrend          = s.trend
+		previousUptrendPrice   = s.Support.Last(0)
+		previousDowntrendPrice = s.Resistance.Last(0)
+		src                    = (high + low) / 2
+	)
+
+	s.previousClosePrice = s.closePrice
+	s.closePrice = closePrice
+
+	uptrendPrice := src - atr*s.atrMultiplier
+	if s.previousClosePrice > previousUptrendPrice {
+		uptrendPrice = math.Max(uptrendPrice, previousUptrendPrice)
+	}
+
+	downtrendPrice := src + atr*s.atrMultiplier
+	if s.previousClosePrice < previousDowntrendPrice {
+		downtrendPrice = math.Min(downtrendPrice, previousDowntrendPrice)
+	}
+
+	if previousTrend == types.DirectionUp && closePrice < previousUptrendPrice {
+		s.trend = types.DirectionDown
+	} else if previousTrend == types.DirectionDown && closePrice > previousDowntrendPrice {
+		s.trend = types.DirectionUp
+	}
+
+	switch {
+	case atr <= 0:
+		s.tradeSignal = types.DirectionNone
+	case s.trend == types.DirectionUp && previousTrend == types.DirectionDown:
+		s.tradeSignal = types.DirectionUp
+	case s.trend == types.DirectionDown && previousTrend == types.DirectionUp:
+		s.tradeSignal = types.DirectionDown
+	default:
+		s.tradeSignal = types.DirectionNone
+	}
+
+	s.Support.PushAndEmit(uptrendPrice)
+	s.Resistance.PushAndEmit(downtrendPrice)
+	s.Support.Slice = s.Support.Slice.Truncate(MaxNumOfEWMA)
+	s.Resistance.Slice = s.Resistance.Slice.Truncate(MaxNumOfEWMA)
+
+	if s.trend == types.DirectionDown {
+		s.PushAndEmit(downtrendPrice)
+	} else {
+		s.PushAndEmit(uptrendPrice)
+	}
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
+
+// Direction returns the current trend
+func (s *SupertrendStream) Direction() types.Direction {
+	return s.trend
+}
+
+// Signal returns the trade signal of the last kline, it's DirectionNone if the trend is not reversed
+func (s *SupertrendStream) Signal() types.Direction {
+	return s.tradeSignal
+}
@@ -0,0 +1,32 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// TEMAStream is the Triple Exponential Moving Average: 3 * EMA - 3 * EMA(EMA) + EMA(EMA(EMA))
+type TEMAStream struct {
+	*types.Float64Series
+
+	ema1, ema2, ema3 *EWMAStream
+}
+
+func TEMA(source types.Float64Source, window int) *TEMAStream {
+	ema1 := EWMA2(source, window)
+	ema2 := EWMA2(ema1, window)
+	s := &TEMAStream{
+		Float64Series: types.NewFloat64Series(),
+		ema1:          ema1,
+		ema2:          ema2,
+		ema3:          EWMA2(ema2, window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *TEMAStream) Calculate(_ float64) float64 {
+	a1, a2, a3 := s.ema1.Last(0), s.ema2.Last(0), s.ema3.Last(0)
+	return 3.0*a1 - 3.0*a2 + a3
+}
+
+func (s *TEMAStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
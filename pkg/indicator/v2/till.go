@@ -0,0 +1,51 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+const DefaultTillVolumeFactor = 0.7
+
+// TILLStream is the Tillson T3 Moving Average, a combination of 6 chained EMAs weighted by the volume factor
+type TILLStream struct {
+	*types.Float64Series
+
+	e1, e2, e3, e4, e5, e6 *EWMAStream
+	c1, c2, c3, c4         float64
+}
+
+func TILL(source types.Float64Source, window int, volumeFactor float64) *TILLStream {
+	if volumeFactor == 0 {
+		volumeFactor = DefaultTillVolumeFactor
+	}
+
+	square := volumeFactor * volumeFactor
+	cube := volumeFactor * square
+
+	e1 := EWMA2(source, window)
+	e2 := EWMA2(e1, window)
+	e3 := EWMA2(e2, window)
+	e4 := EWMA2(e3, window)
+	e5 := EWMA2(e4, window)
+	s := &TILLStream{
+		Float64Series: types.NewFloat64Series(),
+		e1:            e1,
+		e2:            e2,
+		e3:            e3,
+		e4:            e4,
+		e5:            e5,
+		e6:            EWMA2(e5, window),
+		c1:            -cube,
+		c2:            3.*square + 3.*cube,
+		c3:            -6.*square - 3*volumeFactor - 3*cube,
+		c4:            1. + 3.*volumeFactor + cube + 3.*square,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *TILLStream) Calculate(_ float64) float64 {
+	return s.c1*s.e6.Last(0) + s.c2*s.e5.Last(0) + s.c3*s.e4.Last(0) + s.c4*s.e3.Last(0)
+}
+
+func (s *TILLStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
@@ -0,0 +1,13 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// TMAStream is the Triangular Moving Average, the SMA of the SMA with the half window
+type TMAStream struct {
+	*SMAStream
+}
+
+func TMA(source types.Float64Source, window int) *TMAStream {
+	w := (window + 1) / 2
+	return &TMAStream{SMAStream: SMA(SMA(source, w), w)}
+}
@@ -0,0 +1,68 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	DefaultTSIFastWindow = 13
+	DefaultTSISlowWindow = 25
+)
+
+// TSIStream is the True Strength Index, the double smoothed price change divided by
+// the double smoothed absolute price change, in percentage
+type TSIStream struct {
+	*types.Float64Series
+
+	pcs, pcds, apcs, apcds *EWMAStream
+
+	prev  float64
+	count int
+}
+
+// TSI creates the true strength index stream, the default windows (13, 25) are used if the windows are zero
+func TSI(source types.Float64Source, fastWindow, slowWindow int) *TSIStream {
+	if fastWindow == 0 {
+		fastWindow = DefaultTSIFastWindow
+	}
+	if slowWindow == 0 {
+		slowWindow = DefaultTSISlowWindow
+	}
+
+	s := &TSIStream{
+		Float64Series: types.NewFloat64Series(),
+		pcs:           EWMA2(nil, slowWindow),
+		apcs:          EWMA2(nil, slowWindow),
+	}
+	s.pcds = EWMA2(s.pcs, fastWindow)
+	s.apcds = EWMA2(s.apcs, fastWindow)
+	s.Bind(source, s)
+	return s
+}
+
+func (s *TSIStream) Calculate(v float64) float64 {
+	s.count++
+	if s.count == 1 {
+		s.prev = v
+		return 0
+	}
+
+	pc := v - s.prev
+	s.prev = v
+	s.pcs.PushAndEmit(s.pcs.Calculate(pc))
+	s.apcs.PushAndEmit(s.apcs.Calculate(math.Abs(pc)))
+
+	if s.apcds.Last(0) == 0 {
+		return 0
+	}
+	return s.pcds.Last(0) / s.apcds.Last(0) * 100.
+}
+
+func (s *TSIStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	for _, ema := range []*EWMAStream{s.pcs, s.pcds, s.apcs, s.apcds} {
+		ema.Slice = ema.Slice.Truncate(MaxNumOfEWMA)
+	}
+}
@@ -0,0 +1,86 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// UTBotAlertStream is the UT Bot Alerts indicator, the embedded series is the ATR trailing stop,
+// and the signal is emitted when the close price crosses the trailing stop.
+//
+// It deviates from the v1 UtBotAlert, which trails the stop of two klines ago instead of the previous one,
+// so the v1 signals are missed or delayed when the stop moves on the last kline.
+//
+//go:generate callbackgen -type UTBotAlertStream
+type UTBotAlertStream struct {
+	*types.Float64Series
+
+	keyValue float64
+	atr      *ATRStream
+
+	previousClosePrice float64
+	signals            []types.Direction
+	signalCallbacks    []func(signal types.Direction)
+}
+
+// UTBotAlert creates the UT Bot Alerts stream, the key value is the multiplier of the ATR
+func UTBotAlert(source KLineSubscription, window int, keyValue float64) *UTBotAlertStream {
+	s := &UTBotAlertStream{
+		Float64Series: types.NewFloat64Series(),
+		keyValue:      keyValue,
+		atr:           ATR2(source, window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.update(k.Close.Float64())
+	})
+	return s
+}
+
+func (s *UTBotAlertStream) update(closePrice float64) {
+	var (
+		nLoss        = s.atr.Last(0) * s.keyValue
+		prevStop     = s.Slice.Last(0)
+		prevClose    = s.previousClosePrice
+		trailingStop float64
+	)
+
+	switch {
+	case s.Length() == 0:
+		trailingStop = 0
+	case closePrice > prevStop && prevClose > prevStop:
+		trailingStop = math.Max(prevStop, closePrice-nLoss)
+	case closePrice < prevStop && prevClose < prevStop:
+		trailingStop = math.Min(prevStop, closePrice+nLoss)
+	case closePrice > prevStop:
+		trailingStop = closePrice - nLoss
+	default:
+		trailingStop = closePrice + nLoss
+	}
+
+	var signal types.Direction = types.DirectionNone
+	if closePrice > trailingStop && prevClose < prevStop {
+		signal = types.DirectionUp
+	} else if closePrice < trailingStop && prevClose > prevStop {
+		signal = types.DirectionDown
+	}
+
+	s.previousClosePrice = closePrice
+	s.signals = append(s.signals, signal)
+	if len(s.signals) > MaxNumOfEWMA {
+		s.signals = s.signals[len(s.signals)-MaxNumOfEWMA:]
+	}
+
+	s.PushAndEmit(trailingStop)
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	s.EmitSignal(signal)
+}
+
+// Signal returns the signal of the last kline, DirectionUp for buy, DirectionDown for sell
+func (s *UTBotAlertStream) Signal() types.Direction {
+	if len(s.signals) == 0 {
+		return types.DirectionNone
+	}
+	return s.signals[len(s.signals)-1]
+}
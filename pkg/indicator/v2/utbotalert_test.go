@@ -0,0 +1,39 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_UTBotAlert(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := KLines(stream, "", "")
+	utBot := UTBotAlert(kLines, 3, 1)
+
+	var signals []types.Direction
+	utBot.OnSignal(func(signal types.Direction) {
+		signals = append(signals, signal)
+	})
+
+	// the true range of each kline is 2 after the first kline
+	closePrices := []float64{100, 101, 102, 103, 104, 100, 97, 96, 95, 99, 102, 103}
+	for _, c := range closePrices {
+		stream.EmitKLineClosed(types.KLine{
+			High:  fixedpoint.NewFromFloat(c + 1),
+			Low:   fixedpoint.NewFromFloat(c - 1),
+			Close: fixedpoint.NewFromFloat(c),
+		})
+	}
+
+	assert.Equal(t, []types.Direction{
+		types.DirectionNone, types.DirectionNone, types.DirectionNone, types.DirectionNone, types.DirectionNone,
+		types.DirectionDown, types.DirectionNone, types.DirectionNone, types.DirectionNone,
+		types.DirectionUp, types.DirectionNone, types.DirectionNone,
+	}, signals)
+	assert.Equal(t, types.Direction(types.DirectionNone), utBot.Signal())
+	assert.Greater(t, closePrices[len(closePrices)-1], utBot.Last(0))
+}
@@ -0,0 +1,378 @@
+package indicatorv2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// buildTestKLines builds deterministic klines oscillating around an uptrend
+func buildTestKLines(n int) (kLines []types.KLine) {
+	rnd := rand.New(rand.NewSource(42))
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := 100.0
+	for i := 0; i < n; i++ {
+		openPrice := price
+		price = 100.0 + 10.0*math.Sin(float64(i)/7.0) + 0.05*float64(i) + rnd.Float64()*2.0 - 1.0
+		high := math.Max(openPrice, price) + rnd.Float64()
+		low := math.Min(openPrice, price) - rnd.Float64()
+		kLines = append(kLines, types.KLine{
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Minute)),
+			EndTime:   types.Time(startTime.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromFloat(openPrice),
+			High:      fixedpoint.NewFromFloat(high),
+			Low:       fixedpoint.NewFromFloat(low),
+			Close:     fixedpoint.NewFromFloat(price),
+			Volume:    fixedpoint.NewFromFloat(100.0 + rnd.Float64()*1000.0),
+			Closed:    true,
+		})
+	}
+	return kLines
+}
+
+type lastGetter interface {
+	Last(i int) float64
+	Length() int
+}
+
+// assertSeriesInDelta compares the values of the series from the end of the warm-up period onward,
+// the warm-up period is the number of the klines before the values of the indicators are converged.
+func assertSeriesInDelta(t *testing.T, expected, actual lastGetter, numOfKLines, warmUp int, delta float64) {
+	n := numOfKLines - warmUp
+	if !assert.GreaterOrEqual(t, expected.Length(), n) || !assert.GreaterOrEqual(t, actual.Length(), n) {
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		if !assert.InDelta(t, expected.Last(i), actual.Last(i), delta, "the %d-th last value", i) {
+			return
+		}
+	}
+}
+
+// Test_V1Compatibility compares the values of the v2 streams with the v1 indicators after the warm-up period
+func Test_V1Compatibility(t *testing.T) {
+	const numOfKLines = 300
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 14}
+
+	type compatTest struct {
+		name   string
+		v1     func(k types.KLine)
+		v1Val  lastGetter
+		v2     func(kLines *KLineStream) lastGetter
+		warmUp int
+		delta  float64
+	}
+
+	var tests []compatTest
+
+	addValueTest := func(name string, v1 interface {
+		lastGetter
+		Update(v float64)
+	}, v2 func(source types.Float64Source) lastGetter, warmUp int, delta float64) {
+		tests = append(tests, compatTest{
+			name:  name,
+			v1:    func(k types.KLine) { v1.Update(k.Close.Float64()) },
+			v1Val: v1,
+			v2: func(kLines *KLineStream) lastGetter {
+				return v2(ClosePrices(kLines))
+			},
+			warmUp: warmUp,
+			delta:  delta,
+		})
+	}
+
+	addKLineTest := func(name string, v1 interface {
+		lastGetter
+		PushK(k types.KLine)
+	}, v2 func(kLines *KLineStream) lastGetter, warmUp int, delta float64) {
+		tests = append(tests, compatTest{name: name, v1: v1.PushK, v1Val: v1, v2: v2, warmUp: warmUp, delta: delta})
+	}
+
+	addValueTest("DEMA", &indicator.DEMA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return DEMA(s, iw.Window)
+	}, 0, 1e-9)
+	addValueTest("TEMA", &indicator.TEMA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return TEMA(s, iw.Window)
+	}, 0, 1e-9)
+	addValueTest("ZLEMA", &indicator.ZLEMA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return ZLEMA(s, iw.Window)
+	}, (iw.Window+1)/2, 1e-9)
+	addValueTest("HULL", &indicator.HULL{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return HULL(s, iw.Window)
+	}, 0, 1e-9)
+	// v1 applies the first weight to the latest value, so the offset is mirrored: the peak of the v2 weights
+	// is close to the latest value when the offset is close to 1 (TradingView), which is offset 1 - o in v1
+	addValueTest("ALMA", &indicator.ALMA{IntervalWindow: iw, Offset: 0.5, Sigma: 6}, func(s types.Float64Source) lastGetter {
+		return ALMA(s, iw.Window, 0.5, 6)
+	}, iw.Window-1, 1e-9)
+	addValueTest("ALMA/mirrored", &indicator.ALMA{IntervalWindow: iw, Offset: 0.15, Sigma: 6}, func(s types.Float64Source) lastGetter {
+		return ALMA(s, iw.Window, 0.85, 6)
+	}, iw.Window-1, 1e-9)
+	addValueTest("TMA", &indicator.TMA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return TMA(s, iw.Window)
+	}, iw.Window-1, 1e-9)
+	addValueTest("TILL", &indicator.TILL{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return TILL(s, iw.Window, 0)
+	}, 0, 1e-9)
+	addValueTest("WWMA", &indicator.WWMA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return WWMA(s, iw.Window)
+	}, 0, 1e-9)
+	addValueTest("VIDYA", &indicator.VIDYA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return VIDYA(s, iw.Window)
+	}, 0, 1e-9)
+	addValueTest("SSF2", &indicator.SSF{IntervalWindow: iw, Poles: 2}, func(s types.Float64Source) lastGetter {
+		return SSF(s, iw.Window, 2)
+	}, 0, 1e-9)
+	addValueTest("SSF3", &indicator.SSF{IntervalWindow: iw, Poles: 3}, func(s types.Float64Source) lastGetter {
+		return SSF(s, iw.Window, 3)
+	}, 0, 1e-9)
+	addValueTest("GMA", &indicator.GMA{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return GMA(s, iw.Window)
+	}, iw.Window-1, 1e-9)
+	addValueTest("Drift", &indicator.Drift{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return Drift(s, iw.Window)
+	}, iw.Window, 1e-9)
+	addValueTest("FisherTransform", &indicator.FisherTransform{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return FisherTransform(s, iw.Window)
+	}, 0, 1e-9)
+	addValueTest("GHFilter", &indicator.GHFilter{IntervalWindow: iw}, func(s types.Float64Source) lastGetter {
+		return GHFilter(s, iw.Window)
+	}, 0, 1e-9)
+	addValueTest("KalmanFilter", &indicator.KalmanFilter{IntervalWindow: iw, AdditionalSmoothWindow: 2}, func(s types.Float64Source) lastGetter {
+		return KalmanFilter(s, iw.Window, 2)
+	}, 0, 1e-9)
+	addValueTest("TSI", &indicator.TSI{Interval: iw.Interval}, func(s types.Float64Source) lastGetter {
+		return TSI(s, 0, 0)
+	}, 1, 1e-6)
+
+	addKLineTest("VWAP", &indicator.VWAP{IntervalWindow: iw}, func(kLines *KLineStream) lastGetter {
+		return VWAP(kLines, iw.Window)
+	}, 0, 1e-9)
+	addKLineTest("VWMA", &indicator.VWMA{IntervalWindow: iw}, func(kLines *KLineStream) lastGetter {
+		return VWMA(kLines, iw.Window)
+	}, iw.Window-1, 1e-9)
+	addKLineTest("EMV", &indicator.EMV{IntervalWindow: iw}, func(kLines *KLineStream) lastGetter {
+		return EMV(kLines, iw.Window, 0)
+	}, iw.Window, 1e-6)
+	addKLineTest("KlingerOscillator", &indicator.KlingerOscillator{IntervalWindow: iw}, func(kLines *KLineStream) lastGetter {
+		return KlingerOscillator(kLines, 0, 0)
+	}, 1, 1e-6)
+	addKLineTest("PSAR", &indicator.PSAR{IntervalWindow: iw}, func(kLines *KLineStream) lastGetter {
+		return PSAR(kLines, iw.Window)
+	}, 1, 1e-9)
+	addKLineTest("Supertrend", &indicator.Supertrend{
+		IntervalWindow:   iw,
+		ATRMultiplier:    3,
+		AverageTrueRange: &indicator.ATR{IntervalWindow: iw},
+	}, func(kLines *KLineStream) lastGetter {
+		return Supertrend(kLines, iw.Window, 3)
+	}, 0, 1e-6)
+	addKLineTest("PivotSupertrend", &indicator.PivotSupertrend{
+		IntervalWindow:   iw,
+		ATRMultiplier:    3,
+		AverageTrueRange: &indicator.ATR{IntervalWindow: iw},
+		PivotLow:         &indicator.PivotLow{IntervalWindow: types.IntervalWindow{Interval: iw.Interval, Window: 5}},
+		PivotHigh:        &indicator.PivotHigh{IntervalWindow: types.IntervalWindow{Interval: iw.Interval, Window: 5}},
+	}, func(kLines *KLineStream) lastGetter {
+		return PivotSupertrend(kLines, iw.Window, 5, 3)
+	}, 18, 1e-6)
+	addKLineTest("WeightedDrift", &indicator.WeightedDrift{IntervalWindow: iw}, func(kLines *KLineStream) lastGetter {
+		return WeightedDrift(kLines, iw.Window)
+	}, 8, 1e-9)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &types.StandardStream{}
+			kLines := KLines(stream, "", "")
+			v2 := tt.v2(kLines)
+
+			for _, k := range buildTestKLines(numOfKLines) {
+				tt.v1(k)
+				stream.EmitKLineClosed(k)
+			}
+
+			assertSeriesInDelta(t, tt.v1Val, v2, numOfKLines, tt.warmUp, tt.delta)
+		})
+	}
+}
+
+func Test_V1Compatibility_KLines(t *testing.T) {
+	const numOfKLines = 300
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 14}
+
+	stream := &types.StandardStream{}
+	kLines := KLines(stream, "", "")
+
+	ad := AD(kLines)
+	v1AD := &indicator.AD{IntervalWindow: iw}
+
+	linReg := LinReg(kLines, iw.Window)
+	v1LinReg := &indicator.LinReg{IntervalWindow: iw}
+
+	dmi := DMI(kLines, iw.Window, iw.Window)
+	v1DMI := &indicator.DMI{IntervalWindow: iw, ADXSmoothing: iw.Window}
+
+	volatility := Volatility(ClosePrices(kLines), iw.Window)
+	v1Volatility := &indicator.Volatility{IntervalWindow: iw}
+
+	pivot := Pivot(kLines, iw.Window)
+	v1Pivot := &indicator.Pivot{IntervalWindow: iw}
+
+	line := Line(kLines, 20, 90.0, 5, 110.0)
+
+	// the v1 volume profile steps the price levels by the delta, it's only correct with the delta 1
+	volumeProfile := VolumeProfile(kLines, iw.Window, 1)
+	v1VolumeProfile := &indicator.VolumeProfile{IntervalWindow: iw, Delta: 1}
+
+	var pocAbove, pocBelow, v1POCAbove, v1POCBelow floats.Slice
+
+	var allKLines []types.KLine
+	for _, k := range buildTestKLines(numOfKLines) {
+		allKLines = append(allKLines, k)
+		v1AD.Update(k.High.Float64(), k.Low.Float64(), k.Close.Float64(), k.Volume.Float64())
+		v1LinReg.Update(k)
+		v1DMI.PushK(k)
+		v1Volatility.CalculateAndUpdate(allKLines)
+		v1Pivot.CalculateAndUpdate(allKLines)
+		v1VolumeProfile.Update(types.KLineTypicalPriceMapper(k), k.Volume.Float64(), k.EndTime)
+		stream.EmitKLineClosed(k)
+
+		closePrice := k.Close.Float64()
+		price, _ := volumeProfile.PointOfControlAboveEqual(closePrice)
+		pocAbove.Push(price)
+		price, _ = volumeProfile.PointOfControlBelowEqual(closePrice)
+		pocBelow.Push(price)
+		price, _ = v1VolumeProfile.PointOfControlAboveEqual(closePrice)
+		v1POCAbove.Push(price)
+		price, _ = v1VolumeProfile.PointOfControlBelowEqual(closePrice)
+		v1POCBelow.Push(price)
+	}
+
+	t.Run("AD", func(t *testing.T) {
+		assertSeriesInDelta(t, v1AD, ad, numOfKLines, 0, 1e-6)
+	})
+	t.Run("LinReg", func(t *testing.T) {
+		assertSeriesInDelta(t, v1LinReg, linReg, numOfKLines, iw.Window-1, 1e-9)
+	})
+	t.Run("LinReg.ValueRatios", func(t *testing.T) {
+		assertSeriesInDelta(t, v1LinReg.ValueRatios, linReg.ValueRatios, numOfKLines, iw.Window-1, 1e-9)
+	})
+	t.Run("DMI.ADX", func(t *testing.T) {
+		assertSeriesInDelta(t, v1DMI.GetADX(), dmi, numOfKLines, iw.Window, 1e-6)
+	})
+	t.Run("DMI.DIPlus", func(t *testing.T) {
+		assertSeriesInDelta(t, v1DMI.GetDIPlus(), dmi.DIPlus, numOfKLines, iw.Window, 1e-6)
+	})
+	t.Run("DMI.DIMinus", func(t *testing.T) {
+		assertSeriesInDelta(t, v1DMI.GetDIMinus(), dmi.DIMinus, numOfKLines, iw.Window, 1e-6)
+	})
+	t.Run("Volatility", func(t *testing.T) {
+		assertSeriesInDelta(t, v1Volatility, volatility, numOfKLines, iw.Window-1, 1e-9)
+	})
+
+	t.Run("VolumeProfile.PointOfControlAboveEqual", func(t *testing.T) {
+		assertSeriesInDelta(t, v1POCAbove, pocAbove, numOfKLines, 0, 1e-9)
+	})
+	t.Run("VolumeProfile.PointOfControlBelowEqual", func(t *testing.T) {
+		assertSeriesInDelta(t, v1POCBelow, pocBelow, numOfKLines, 0, 1e-9)
+	})
+
+	// the v1 line is not shifted by the first kline, so the indexes are shifted in advance
+	v1Line := indicator.NewLine(20+numOfKLines, 90.0, 5+numOfKLines, 110.0, iw.Interval)
+	t.Run("Line", func(t *testing.T) {
+		for i := 0; i < numOfKLines; i++ {
+			if !assert.InDelta(t, v1Line.Last(i), line.At(i), 1e-9, "the %d-th last value", i) ||
+				!assert.InDelta(t, v1Line.Last(i), line.Last(i), 1e-9, "the %d-th last value", i) {
+				return
+			}
+		}
+	})
+
+	// the pivots are only pushed when they are found, so all of them are compared
+	t.Run("Pivot.Lows", func(t *testing.T) {
+		assertSeriesInDelta(t, v1Pivot.Lows, pivot.Lows, v1Pivot.Lows.Length(), 0, 1e-9)
+	})
+	t.Run("Pivot.Highs", func(t *testing.T) {
+		assertSeriesInDelta(t, v1Pivot.Highs, pivot.Highs, v1Pivot.Highs.Length(), 0, 1e-9)
+	})
+}
+
+// Test_V1Deviations pins the v2 streams that deviate from the v1 indicators, the reasons are in the doc comments
+func Test_V1Deviations(t *testing.T) {
+	t.Run("OBV", func(t *testing.T) {
+		stream := &types.StandardStream{}
+		obv := OBV(KLines(stream, "", ""))
+		v1OBV := &indicator.OBV{}
+
+		closePrices := []float64{10, 11, 11, 9, 12}
+		volumes := []float64{100, 200, 300, 400, 500}
+		for i, c := range closePrices {
+			k := types.KLine{Close: fixedpoint.NewFromFloat(c), Volume: fixedpoint.NewFromFloat(volumes[i])}
+			v1OBV.PushK(k)
+			stream.EmitKLineClosed(k)
+		}
+
+		// v1 compares the volume with the first close price, so the volumes larger than it are always added
+		assert.Equal(t, floats.Slice{100, 300, 600, 1000, 1500}, v1OBV.Values)
+		assert.Equal(t, floats.Slice{100, 300, 300, -100, 400}, obv.Slice)
+	})
+
+	t.Run("UTBotAlert", func(t *testing.T) {
+		stream := &types.StandardStream{}
+		utBot := UTBotAlert(KLines(stream, "", ""), 3, 1)
+		v1UTBot := indicator.NewUtBotAlert(types.IntervalWindow{Window: 3}, 1)
+
+		// the true range of each kline is 2 after the first kline
+		for _, c := range []float64{100, 101, 102, 103, 104, 103, 102.6, 101.9} {
+			k := types.KLine{
+				High:  fixedpoint.NewFromFloat(c + 1),
+				Low:   fixedpoint.NewFromFloat(c - 1),
+				Close: fixedpoint.NewFromFloat(c),
+			}
+			v1UTBot.PushK(k)
+			stream.EmitKLineClosed(k)
+		}
+
+		// v1 trails the stop 101 of two klines ago, so the close price 101.9 doesn't cross it
+		assert.Equal(t, types.Direction(types.DirectionNone), v1UTBot.GetSignal())
+		assert.Equal(t, types.Direction(types.DirectionDown), utBot.Signal())
+		assert.InDelta(t, 103.9, utBot.Last(0), 1e-9)
+	})
+
+	t.Run("VolumeProfile", func(t *testing.T) {
+		iw := types.IntervalWindow{Interval: types.Interval1m, Window: 3}
+		stream := &types.StandardStream{}
+		vp := VolumeProfile(KLines(stream, "", ""), iw.Window, 0.5)
+		v1VP := &indicator.VolumeProfile{IntervalWindow: iw, Delta: 0.5}
+
+		k := types.KLine{
+			High:    fixedpoint.NewFromFloat(100),
+			Low:     fixedpoint.NewFromFloat(100),
+			Close:   fixedpoint.NewFromFloat(100),
+			Volume:  fixedpoint.NewFromFloat(10),
+			EndTime: types.Time(time.Date(2023, 1, 1, 0, 1, 0, 0, time.UTC)),
+		}
+		v1VP.Update(types.KLineTypicalPriceMapper(k), k.Volume.Float64(), k.EndTime)
+		stream.EmitKLineClosed(k)
+
+		// v1 returns the index of the price level instead of the price if the delta is not 1
+		price, volume := v1VP.PointOfControlAboveEqual(100)
+		assert.Equal(t, 200.0, price)
+		assert.Equal(t, 10.0, volume)
+
+		price, volume = vp.PointOfControlAboveEqual(100)
+		assert.Equal(t, 100.0, price)
+		assert.Equal(t, 10.0, volume)
+	})
+}
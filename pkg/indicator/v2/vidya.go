@@ -0,0 +1,60 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VIDYAStream is the Variable Index Dynamic Average, an EMA whose smoothing factor is scaled by
+// the absolute Chande Momentum Oscillator of the window
+type VIDYAStream struct {
+	*types.Float64Series
+
+	changes *types.Queue
+	window  int
+	last    float64
+	count   int
+}
+
+func VIDYA(source types.Float64Source, window int) *VIDYAStream {
+	checkWindow(window)
+
+	s := &VIDYAStream{
+		Float64Series: types.NewFloat64Series(),
+		changes:       types.NewQueue(window),
+		window:        window,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *VIDYAStream) Calculate(v float64) float64 {
+	s.count++
+	if s.count == 1 {
+		s.last = v
+		return v
+	}
+
+	s.changes.Update(v - s.last)
+	s.last = v
+
+	var sum, absSum float64
+	for i := 0; i < s.changes.Length(); i++ {
+		c := s.changes.Last(i)
+		sum += c
+		absSum += math.Abs(c)
+	}
+
+	cmo := 0.0
+	if absSum != 0 {
+		cmo = math.Abs(sum / absSum)
+	}
+
+	alpha := 2. / float64(s.window+1)
+	return v*alpha*cmo + s.Slice.Last(0)*(1.-alpha*cmo)
+}
+
+func (s *VIDYAStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+}
@@ -0,0 +1,49 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VolatilityStream is the population standard deviation of the source values in the window,
+// nothing is pushed before the window is filled.
+type VolatilityStream struct {
+	*types.Float64Series
+
+	rawValues *types.Queue
+	window    int
+}
+
+func Volatility(source types.Float64Source, window int) *VolatilityStream {
+	checkWindow(window)
+
+	s := &VolatilityStream{
+		Float64Series: types.NewFloat64Series(),
+		rawValues:     types.NewQueue(window),
+		window:        window,
+	}
+
+	s.Subscribe(source, func(v float64) {
+		s.rawValues.Update(v)
+		if s.rawValues.Length() < s.window {
+			return
+		}
+
+		s.PushAndEmit(s.volatility())
+		s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
+
+func (s *VolatilityStream) volatility() float64 {
+	mean := types.Mean(s.rawValues, s.window)
+
+	var sv float64
+	for i := 0; i < s.window; i++ {
+		d := s.rawValues.Last(i) - mean
+		sv += d * d
+	}
+
+	return math.Sqrt(sv / float64(s.window))
+}
@@ -0,0 +1,119 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// VolumeProfileStream accumulates the volumes of the klines in the window at the price levels, the price level of a kline
+// is its typical price rounded to the delta. The embedded series is the point of control, the price level with the most volume.
+//
+// It's the same as the v1 VolumeProfile updated with the typical prices of the klines when the delta is 1. With other
+// deltas, the v1 VolumeProfile steps the level indexes by the delta and returns the level index instead of the price.
+type VolumeProfileStream struct {
+	*types.Float64Series
+
+	window  int
+	delta   float64
+	profile map[int64]float64
+	counts  map[int64]int
+	levels  []int64
+	volumes []float64
+}
+
+func VolumeProfile(source KLineSubscription, window int, delta float64) *VolumeProfileStream {
+	checkWindow(window)
+	if delta <= 0 {
+		panic("delta of the volume profile must be greater than 0")
+	}
+
+	s := &VolumeProfileStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		delta:         delta,
+		profile:       make(map[int64]float64),
+		counts:        make(map[int64]int),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.update(types.KLineTypicalPriceMapper(k), k.Volume.Float64())
+
+		poc, _ := s.PointOfControl()
+		s.PushAndEmit(poc)
+		s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
+
+func (s *VolumeProfileStream) update(price, volume float64) {
+	level := s.level(price)
+	s.profile[level] += volume
+	s.counts[level]++
+	s.levels = append(s.levels, level)
+	s.volumes = append(s.volumes, volume)
+
+	if len(s.levels) > s.window {
+		oldLevel := s.levels[0]
+		s.profile[oldLevel] -= s.volumes[0]
+		s.counts[oldLevel]--
+
+		// the level is removed by the count, the subtracted volume may leave a float residue
+		if s.counts[oldLevel] <= 0 {
+			delete(s.profile, oldLevel)
+			delete(s.counts, oldLevel)
+		}
+
+		s.levels = s.levels[1:]
+		s.volumes = s.volumes[1:]
+	}
+}
+
+func (s *VolumeProfileStream) level(price float64) int64 {
+	return int64(math.Round(price / s.delta))
+}
+
+// Volume returns the volume at the price level of the given price
+func (s *VolumeProfileStream) Volume(price float64) float64 {
+	return s.profile[s.level(price)]
+}
+
+// PointOfControl returns the price level with the most volume in the window
+func (s *VolumeProfileStream) PointOfControl() (price float64, volume float64) {
+	return s.pointOfControl(func(level int64) bool { return true })
+}
+
+// PointOfControlAboveEqual returns the price level with the most volume at or above the given price, it can be used as a resistance level
+func (s *VolumeProfileStream) PointOfControlAboveEqual(price float64) (float64, float64) {
+	start := s.level(price)
+	return s.pointOfControl(func(level int64) bool { return level >= start })
+}
+
+// PointOfControlBelowEqual returns the price level with the most volume at or below the given price, it can be used as a support level
+func (s *VolumeProfileStream) PointOfControlBelowEqual(price float64) (float64, float64) {
+	start := s.level(price)
+	return s.pointOfControl(func(level int64) bool { return level <= start })
+}
+
+func (s *VolumeProfileStream) pointOfControl(filter func(level int64) bool) (price float64, volume float64) {
+	found := false
+	var pocLevel int64
+	for level, vol := range s.profile {
+		if !filter(level) {
+			continue
+		}
+
+		// prefer the lower price level if the volumes are the same, so that the result is deterministic
+		if !found || vol > volume || (vol == volume && level < pocLevel) {
+			found = true
+			pocLevel = level
+			volume = vol
+		}
+	}
+
+	if !found {
+		return 0, 0
+	}
+
+	return float64(pocLevel) * s.delta, volume
+}
@@ -0,0 +1,67 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_VolumeProfile(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := KLines(stream, "", "")
+	vp := VolumeProfile(kLines, 3, 1.0)
+
+	push := func(price, volume float64) {
+		stream.EmitKLineClosed(types.KLine{
+			High:   fixedpoint.NewFromFloat(price),
+			Low:    fixedpoint.NewFromFloat(price),
+			Close:  fixedpoint.NewFromFloat(price),
+			Volume: fixedpoint.NewFromFloat(volume),
+		})
+	}
+
+	push(100, 10)
+	push(101, 30)
+	push(102, 20)
+	assert.Equal(t, 101.0, vp.Last(0))
+
+	price, volume := vp.PointOfControlAboveEqual(101.6)
+	assert.Equal(t, 102.0, price)
+	assert.Equal(t, 20.0, volume)
+
+	price, volume = vp.PointOfControlBelowEqual(100.2)
+	assert.Equal(t, 100.0, price)
+	assert.Equal(t, 10.0, volume)
+
+	// the volume of 100 is removed from the window
+	push(102, 20)
+	assert.Equal(t, 102.0, vp.Last(0))
+	assert.Equal(t, 0.0, vp.Volume(100))
+
+	price, volume = vp.PointOfControlBelowEqual(99)
+	assert.Equal(t, 0.0, price)
+	assert.Equal(t, 0.0, volume)
+}
+
+func Test_VolumeProfile_removedLevel(t *testing.T) {
+	stream := &types.StandardStream{}
+	kLines := KLines(stream, "", "")
+	vp := VolumeProfile(kLines, 2, 1.0)
+
+	for _, pv := range [][2]float64{{100, 0.1}, {100, 0.2}, {101, 1}, {101, 1}} {
+		stream.EmitKLineClosed(types.KLine{
+			High:   fixedpoint.NewFromFloat(pv[0]),
+			Low:    fixedpoint.NewFromFloat(pv[0]),
+			Close:  fixedpoint.NewFromFloat(pv[0]),
+			Volume: fixedpoint.NewFromFloat(pv[1]),
+		})
+	}
+
+	// 0.1 + 0.2 - 0.1 - 0.2 leaves a float residue, the level is removed anyway
+	price, volume := vp.PointOfControlBelowEqual(100.2)
+	assert.Equal(t, 0.0, price)
+	assert.Equal(t, 0.0, volume)
+}
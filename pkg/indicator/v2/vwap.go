@@ -0,0 +1,52 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// VWAPStream is the Volume Weighted Average Price of the typical prices,
+// the VWAP is accumulated from the start if the window is zero.
+type VWAPStream struct {
+	*types.Float64Series
+
+	window              int
+	prices, volumes     *types.Queue
+	weightedSum, volSum float64
+}
+
+func VWAP(source KLineSubscription, window int) *VWAPStream {
+	s := &VWAPStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+
+	if window > 0 {
+		s.prices = types.NewQueue(window)
+		s.volumes = types.NewQueue(window)
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		price, volume := types.KLineTypicalPriceMapper(k), k.Volume.Float64()
+		s.PushAndEmit(s.update(price, volume))
+		s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	})
+	return s
+}
+
+func (s *VWAPStream) update(price, volume float64) float64 {
+	if s.window > 0 {
+		if s.prices.Length() == s.window {
+			s.weightedSum -= s.prices.Last(s.window-1) * s.volumes.Last(s.window-1)
+			s.volSum -= s.volumes.Last(s.window - 1)
+		}
+
+		s.prices.Update(price)
+		s.volumes.Update(volume)
+	}
+
+	s.weightedSum += price * volume
+	s.volSum += volume
+	if s.volSum == 0 {
+		return price
+	}
+
+	return s.weightedSum / s.volSum
+}
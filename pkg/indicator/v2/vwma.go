@@ -0,0 +1,35 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// VWMAStream is the Volume Weighted Moving Average of the close prices: SMA(close * volume) / SMA(volume)
+type VWMAStream struct {
+	*types.Float64Series
+
+	priceVolumeSMA, volumeSMA *SMAStream
+}
+
+func VWMA(source KLineSubscription, window int) *VWMAStream {
+	s := &VWMAStream{
+		Float64Series:  types.NewFloat64Series(),
+		priceVolumeSMA: SMA(nil, window),
+		volumeSMA:      SMA(nil, window),
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		closePrice, volume := k.Close.Float64(), k.Volume.Float64()
+		s.priceVolumeSMA.PushAndEmit(s.priceVolumeSMA.Calculate(closePrice * volume))
+		s.volumeSMA.PushAndEmit(s.volumeSMA.Calculate(volume))
+		s.priceVolumeSMA.Truncate()
+		s.volumeSMA.Truncate()
+
+		v := closePrice
+		if vol := s.volumeSMA.Last(0); vol != 0 {
+			v = s.priceVolumeSMA.Last(0) / vol
+		}
+
+		s.PushAndEmit(v)
+		s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
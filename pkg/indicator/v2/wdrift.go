@@ -0,0 +1,71 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// WeightedDriftStream is the drift of the log returns weighted by the kline volumes. The log return of a kline is
+// normalized by its volume and counted as many times as the volume is multiple of the lowest volume in the window.
+// The klines without volume are skipped.
+//
+// Refer: https://tradingview.com/script/aDymGrFx-Drift-Study-Inspired-by-Monte-Carlo-Simulations-with-BM-KL/
+type WeightedDriftStream struct {
+	*types.Float64Series
+
+	changes *types.Queue
+	weights *types.Queue
+	window  int
+	last    float64
+}
+
+func WeightedDrift(source KLineSubscription, window int) *WeightedDriftStream {
+	checkWindow(window)
+
+	s := &WeightedDriftStream{
+		Float64Series: types.NewFloat64Series(),
+		changes:       types.NewQueue(window),
+		weights:       types.NewQueue(window),
+		window:        window,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		s.update(k.Close.Float64(), k.Volume.Abs().Float64())
+	})
+	return s
+}
+
+func (s *WeightedDriftStream) update(value, weight float64) {
+	if weight == 0 {
+		s.last = value
+		return
+	}
+
+	if s.weights.Length() == 0 {
+		s.last = value
+		s.weights.Update(weight)
+		return
+	}
+
+	s.weights.Update(weight)
+	base := types.Lowest(s.weights, s.window)
+
+	var change float64
+	if value != 0 {
+		change = math.Log(value/s.last) / weight * base
+		s.last = value
+	}
+
+	for i := 0; i < int(weight/base); i++ {
+		s.changes.Update(change)
+	}
+
+	if s.changes.Length() < s.window {
+		return
+	}
+
+	stdev := types.Stdev(s.changes, s.window)
+	s.PushAndEmit(types.Mean(s.changes, s.window) - stdev*stdev*0.5)
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
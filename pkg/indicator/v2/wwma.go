@@ -0,0 +1,12 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// WWMAStream is the Welles Wilder's Moving Average, which is the same as the RMA without the adjustment
+type WWMAStream struct {
+	*RMAStream
+}
+
+func WWMA(source types.Float64Source, window int) *WWMAStream {
+	return &WWMAStream{RMAStream: RMA2(source, window, false)}
+}
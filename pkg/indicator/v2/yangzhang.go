@@ -0,0 +1,85 @@
+package indicatorv2
+
+import (
+	"math"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// YangZhangStream is the Yang-Zhang volatility estimator, which combines the overnight (close to open) volatility,
+// the open to close volatility and the Rogers-Satchell volatility:
+//
+//	sigma^2 = var(ln(open / prevClose)) + k * var(ln(close / open)) + (1 - k) * mean(rs)
+//	rs = ln(high / close) * ln(high / open) + ln(low / close) * ln(low / open)
+//	k = 0.34 / (1.34 + (n + 1) / (n - 1))
+//
+// The variances are the sample variances. The first kline has no previous close, and it's skipped.
+// The value is the volatility per kline, it's not annualized.
+type YangZhangStream struct {
+	*types.Float64Series
+
+	window int
+
+	overnight, openToClose, rogersSatchell floats.Slice
+
+	prevClose float64
+}
+
+func YangZhang(source KLineSubscription, window int) *YangZhangStream {
+	if window < 2 {
+		panic("the window of yang-zhang volatility can not be less than 2")
+	}
+
+	s := &YangZhangStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+	}
+
+	source.AddSubscriber(func(k types.KLine) {
+		openPrice, high, low, closePrice := k.Open.Float64(), k.High.Float64(), k.Low.Float64(), k.Close.Float64()
+		if openPrice <= 0 || low <= 0 || closePrice <= 0 {
+			return
+		}
+
+		prevClose := s.prevClose
+		s.prevClose = closePrice
+		if prevClose <= 0 {
+			return
+		}
+
+		s.overnight.Push(math.Log(openPrice / prevClose))
+		s.openToClose.Push(math.Log(closePrice / openPrice))
+		s.rogersSatchell.Push(math.Log(high/closePrice)*math.Log(high/openPrice) + math.Log(low/closePrice)*math.Log(low/openPrice))
+
+		s.overnight = s.overnight.Truncate(s.window)
+		s.openToClose = s.openToClose.Truncate(s.window)
+		s.rogersSatchell = s.rogersSatchell.Truncate(s.window)
+
+		n := float64(s.overnight.Length())
+		if n < 2 {
+			s.PushAndEmit(math.Sqrt(math.Max(s.rogersSatchell.Mean(), 0)))
+			return
+		}
+
+		kk := 0.34 / (1.34 + (n+1)/(n-1))
+		variance := sampleVariance(s.overnight) + kk*sampleVariance(s.openToClose) + (1-kk)*s.rogersSatchell.Mean()
+		s.PushAndEmit(math.Sqrt(math.Max(variance, 0)))
+		s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+	})
+	return s
+}
+
+func sampleVariance(values floats.Slice) float64 {
+	n := float64(values.Length())
+	if n < 2 {
+		return 0
+	}
+
+	mean := values.Mean()
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / (n - 1)
+}
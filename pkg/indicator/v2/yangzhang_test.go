@@ -0,0 +1,47 @@
+package indicatorv2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestYangZhang(t *testing.T) {
+	source := &KLineStream{}
+	yz := YangZhang(source, 3)
+
+	kLines := []types.KLine{
+		ohlc(100, 102, 99, 101),
+		ohlc(102, 106, 101, 105),
+		ohlc(104, 105, 100, 101),
+		ohlc(100, 103, 98, 102),
+	}
+	source.BackFill(kLines)
+	assert.Equal(t, 3, yz.Length(), "the first kline is skipped")
+
+	var overnight, openToClose, rs []float64
+	for i, k := range kLines[1:] {
+		o, h, l, c := k.Open.Float64(), k.High.Float64(), k.Low.Float64(), k.Close.Float64()
+		overnight = append(overnight, math.Log(o/kLines[i].Close.Float64()))
+		openToClose = append(openToClose, math.Log(c/o))
+		rs = append(rs, math.Log(h/c)*math.Log(h/o)+math.Log(l/c)*math.Log(l/o))
+	}
+
+	variance := func(values []float64) float64 {
+		var mean, sum float64
+		for _, v := range values {
+			mean += v / float64(len(values))
+		}
+		for _, v := range values {
+			sum += (v - mean) * (v - mean)
+		}
+		return sum / float64(len(values)-1)
+	}
+
+	k := 0.34 / (1.34 + 4.0/2.0)
+	expected := variance(overnight) + k*variance(openToClose) + (1-k)*(rs[0]+rs[1]+rs[2])/3
+	assert.InDelta(t, math.Sqrt(expected), yz.Last(0), 1e-12)
+}
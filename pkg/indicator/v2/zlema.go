@@ -0,0 +1,41 @@
+package indicatorv2
+
+import "github.com/c9s/bbgo/pkg/types"
+
+// ZLEMAStream is the Zero Lag Exponential Moving Average,
+// it removes the lag of EMA by applying EMA on (2 * price - lagged price)
+type ZLEMAStream struct {
+	*types.Float64Series
+
+	ema       *EWMAStream
+	rawValues *types.Queue
+	lag       int
+}
+
+func ZLEMA(source types.Float64Source, window int) *ZLEMAStream {
+	lag := int((float64(window)-1)/2. + 0.5)
+	s := &ZLEMAStream{
+		Float64Series: types.NewFloat64Series(),
+		ema:           EWMA2(nil, window),
+		rawValues:     types.NewQueue(lag + 1),
+		lag:           lag,
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *ZLEMAStream) Calculate(v float64) float64 {
+	s.rawValues.Update(v)
+	if s.rawValues.Length() <= s.lag {
+		return v
+	}
+
+	emaData := 2.*v - s.rawValues.Last(s.lag)
+	s.ema.PushAndEmit(s.ema.Calculate(emaData))
+	return s.ema.Last(0)
+}
+
+func (s *ZLEMAStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfEWMA)
+	s.ema.Slice = s.ema.Slice.Truncate(MaxNumOfEWMA)
+}
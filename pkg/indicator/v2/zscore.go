@@ -0,0 +1,40 @@
+package indicatorv2
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ZScoreStream is the rolling z-score of the source, the distance from the rolling mean
+// in units of the rolling (population) standard deviation.
+type ZScoreStream struct {
+	*types.Float64Series
+
+	window    int
+	rawValues *types.Queue
+}
+
+func ZScore(source types.Float64Source, window int) *ZScoreStream {
+	checkWindow(window)
+	s := &ZScoreStream{
+		Float64Series: types.NewFloat64Series(),
+		window:        window,
+		rawValues:     types.NewQueue(window),
+	}
+	s.Bind(source, s)
+	return s
+}
+
+func (s *ZScoreStream) Calculate(v float64) float64 {
+	s.rawValues.Update(v)
+
+	std := s.rawValues.Stdev()
+	if std == 0 {
+		return 0
+	}
+
+	return (v - s.rawValues.Mean(s.window)) / std
+}
+
+func (s *ZScoreStream) Truncate() {
+	s.Slice = s.Slice.Truncate(MaxNumOfSMA)
+}
@@ -0,0 +1,29 @@
+package indicatorv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestZScore(t *testing.T) {
+	source := types.NewFloat64Series()
+	zscore := ZScore(source, 4)
+
+	for _, v := range []float64{1, 1, 1} {
+		source.PushAndEmit(v)
+	}
+	assert.Equal(t, 0.0, zscore.Last(0), "the z-score of a constant series")
+
+	// mean = 2.5, population std = sqrt(1.25)
+	for _, v := range []float64{1, 2, 3, 4} {
+		source.PushAndEmit(v)
+	}
+	assert.InDelta(t, 1.3416, zscore.Last(0), 0.0001)
+
+	// mean = 2.25, std = sqrt(2.1875)
+	source.PushAndEmit(0)
+	assert.InDelta(t, -1.5213, zscore.Last(0), 0.0001)
+}
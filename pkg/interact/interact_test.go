@@ -22,6 +22,15 @@ func Test_parseFuncArgsAndCall_NoErrorFunction(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_parseFuncArgsAndCall_MissingArgs(t *testing.T) {
+	f := func(a string, b float64) error {
+		return nil
+	}
+
+	_, err := ParseFuncArgsAndCall(f, []string{"BTCUSDT"})
+	assert.EqualError(t, err, "missing argument #2")
+}
+
 func Test_parseFuncArgsAndCall_ErrorFunction(t *testing.T) {
 	errorFunc := func(a string, b float64) error {
 		return errors.New("error")
@@ -1,6 +1,7 @@
 package interact
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -19,6 +20,10 @@ func ParseFuncArgsAndCall(f interface{}, args []string, objects ...interface{})
 	for i := 0; i < ft.NumIn(); i++ {
 		at := ft.In(i)
 
+		if at.Kind() != reflect.Interface && argIndex >= len(args) {
+			return "", fmt.Errorf("missing argument #%d", argIndex+1)
+		}
+
 		// get the kind of argument
 		switch k := at.Kind(); k {
 
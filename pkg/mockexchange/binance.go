@@ -0,0 +1,825 @@
+package mockexchange
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const paramsContextKey = "params"
+
+const defaultMaxQuantity = "9000000"
+
+// binanceError is the error response format of the Binance API
+type binanceError struct {
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+}
+
+// binanceServer serves the Binance compatible spot REST API on top of the engine
+type binanceServer struct {
+	engine *Engine
+	hub    *binanceStreamHub
+
+	apiKey, apiSecret string
+
+	listenKeysMu sync.Mutex
+	listenKeys   map[string]struct{}
+}
+
+func newBinanceServer(engine *Engine, hub *binanceStreamHub, config AccountConfig) *binanceServer {
+	return &binanceServer{
+		engine:     engine,
+		hub:        hub,
+		apiKey:     config.APIKey,
+		apiSecret:  config.APISecret,
+		listenKeys: make(map[string]struct{}),
+	}
+}
+
+func (s *binanceServer) registerRoutes(r gin.IRouter) {
+	r.GET("/api/v3/ping", s.ping)
+	r.GET("/api/v3/time", s.serverTime)
+	r.GET("/fapi/v1/time", s.serverTime)
+	r.GET("/api/v3/exchangeInfo", s.exchangeInfo)
+	r.GET("/api/v3/ticker/24hr", s.ticker24hr)
+	r.GET("/api/v3/avgPrice", s.avgPrice)
+	r.GET("/api/v3/klines", s.parseParams, s.klines)
+	r.GET("/api/v3/depth", s.parseParams, s.depth)
+
+	signed := r.Group("", s.parseParams, s.authenticate(true))
+	signed.GET("/api/v3/account", s.account)
+	signed.POST("/api/v3/order", s.createOrder)
+	signed.GET("/api/v3/order", s.queryOrder)
+	signed.DELETE("/api/v3/order", s.cancelOrder)
+	signed.GET("/api/v3/openOrders", s.openOrders)
+	signed.GET("/api/v3/allOrders", s.allOrders)
+	signed.GET("/api/v3/myTrades", s.myTrades)
+
+	userStream := r.Group("", s.parseParams, s.authenticate(false))
+	userStream.POST("/api/v3/userDataStream", s.createListenKey)
+	userStream.PUT("/api/v3/userDataStream", s.keepaliveListenKey)
+	userStream.DELETE("/api/v3/userDataStream", s.closeListenKey)
+
+	r.GET("/ws", s.hub.serveMarketStream)
+	r.GET("/ws/:listenKey", s.serveUserDataStream)
+}
+
+// parseParams merges the query string and the form body into the request params.
+// The body is read manually since the Binance clients also send the form body with the DELETE requests.
+func (s *binanceServer) parseParams(c *gin.Context) {
+	params := c.Request.URL.Query()
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		s.abort(c, http.StatusBadRequest, -1000, err.Error())
+		return
+	}
+
+	if len(body) > 0 {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			s.abort(c, http.StatusBadRequest, -1100, "Illegal characters found in parameter.")
+			return
+		}
+
+		for k, vs := range form {
+			for _, v := range vs {
+				params.Add(k, v)
+			}
+		}
+	}
+
+	c.Set(paramsContextKey, params)
+	c.Set("body", string(body))
+}
+
+// authenticate checks the api key and the signature when the api key and secret are configured
+func (s *binanceServer) authenticate(signed bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.apiKey == "" {
+			return
+		}
+
+		if c.GetHeader("X-MBX-APIKEY") != s.apiKey {
+			s.abort(c, http.StatusUnauthorized, -2015, "Invalid API-key, IP, or permissions for action.")
+			return
+		}
+
+		if !signed || s.apiSecret == "" {
+			return
+		}
+
+		rawQuery := c.Request.URL.RawQuery
+		idx := strings.LastIndex(rawQuery, "signature=")
+		if idx < 0 {
+			s.abort(c, http.StatusBadRequest, -1102, "Mandatory parameter 'signature' was not sent, was empty/null, or malformed.")
+			return
+		}
+
+		payload := strings.TrimSuffix(rawQuery[:idx], "&") + c.GetString("body")
+		mac := hmac.New(sha256.New, []byte(s.apiSecret))
+		mac.Write([]byte(payload))
+
+		if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(rawQuery[idx+len("signature="):])) {
+			s.abort(c, http.StatusBadRequest, -1022, "Signature for this request is not valid.")
+			return
+		}
+	}
+}
+
+func (s *binanceServer) ping(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (s *binanceServer) serverTime(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"serverTime": time.Now().UnixMilli()})
+}
+
+func (s *binanceServer) exchangeInfo(c *gin.Context) {
+	markets := s.engine.Markets()
+
+	info := binance.ExchangeInfo{
+		Timezone:        "UTC",
+		ServerTime:      time.Now().UnixMilli(),
+		RateLimits:      []binance.RateLimit{},
+		ExchangeFilters: []interface{}{},
+	}
+
+	for _, symbol := range sortedSymbols(markets) {
+		market := markets[symbol]
+
+		maxQuantity := defaultMaxQuantity
+		if market.MaxQuantity.Sign() > 0 {
+			maxQuantity = market.MaxQuantity.String()
+		}
+
+		info.Symbols = append(info.Symbols, binance.Symbol{
+			Symbol:                     symbol,
+			Status:                     "TRADING",
+			BaseAsset:                  market.BaseCurrency,
+			BaseAssetPrecision:         market.VolumePrecision,
+			QuoteAsset:                 market.QuoteCurrency,
+			QuotePrecision:             market.PricePrecision,
+			QuoteAssetPrecision:        market.PricePrecision,
+			OrderTypes:                 []string{"LIMIT", "LIMIT_MAKER", "MARKET", "STOP_LOSS", "STOP_LOSS_LIMIT"},
+			QuoteOrderQtyMarketAllowed: false,
+			IsSpotTradingAllowed:       true,
+			Filters: []map[string]interface{}{
+				{
+					"filterType": "PRICE_FILTER",
+					"minPrice":   market.TickSize.String(),
+					"maxPrice":   "0",
+					"tickSize":   market.TickSize.String(),
+				},
+				{
+					"filterType": "LOT_SIZE",
+					"minQty":     market.MinQuantity.String(),
+					"maxQty":     maxQuantity,
+					"stepSize":   market.StepSize.String(),
+				},
+				{
+					"filterType":       "NOTIONAL",
+					"minNotional":      market.MinNotional.String(),
+					"applyMinToMarket": true,
+					"maxNotional":      defaultMaxQuantity,
+					"avgPriceMins":     5,
+				},
+			},
+			Permissions: []string{"SPOT"},
+		})
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+func (s *binanceServer) ticker24hr(c *gin.Context) {
+	symbol := c.Query("symbol")
+	if symbol != "" {
+		stats, err := s.priceChangeStats(symbol)
+		if err != nil {
+			s.error(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, stats)
+		return
+	}
+
+	var symbols []string
+	if v := c.Query("symbols"); v != "" {
+		if err := json.Unmarshal([]byte(v), &symbols); err != nil {
+			s.abort(c, http.StatusBadRequest, -1100, "Illegal characters found in parameter 'symbols'.")
+			return
+		}
+	} else {
+		symbols = sortedSymbols(s.engine.Markets())
+	}
+
+	var allStats []*binance.PriceChangeStats
+	for _, symbol := range symbols {
+		stats, err := s.priceChangeStats(symbol)
+		if err != nil {
+			s.error(c, err)
+			return
+		}
+
+		allStats = append(allStats, stats)
+	}
+
+	c.JSON(http.StatusOK, allStats)
+}
+
+func (s *binanceServer) priceChangeStats(symbol string) (*binance.PriceChangeStats, error) {
+	ticker, err := s.engine.QueryTicker(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	priceChange := ticker.Last.Sub(ticker.Open)
+	priceChangePercent := fixedpoint.Zero
+	if !ticker.Open.IsZero() {
+		priceChangePercent = priceChange.Div(ticker.Open).Mul(fixedpoint.NewFromInt(100))
+	}
+
+	return &binance.PriceChangeStats{
+		Symbol:             symbol,
+		PriceChange:        priceChange.String(),
+		PriceChangePercent: priceChangePercent.String(),
+		WeightedAvgPrice:   ticker.Last.String(),
+		PrevClosePrice:     ticker.Open.String(),
+		LastPrice:          ticker.Last.String(),
+		LastQty:            "0",
+		BidPrice:           ticker.Buy.String(),
+		BidQty:             "0",
+		AskPrice:           ticker.Sell.String(),
+		AskQty:             "0",
+		OpenPrice:          ticker.Open.String(),
+		HighPrice:          ticker.High.String(),
+		LowPrice:           ticker.Low.String(),
+		Volume:             ticker.Volume.String(),
+		QuoteVolume:        ticker.Volume.Mul(ticker.Last).String(),
+		OpenTime:           ticker.Time.Add(-24 * time.Hour).UnixMilli(),
+		CloseTime:          ticker.Time.UnixMilli(),
+	}, nil
+}
+
+func (s *binanceServer) avgPrice(c *gin.Context) {
+	symbol := c.Query("symbol")
+	if _, ok := s.engine.Markets()[symbol]; !ok {
+		s.error(c, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol))
+		return
+	}
+
+	price, _ := s.engine.LastPrice(symbol)
+	c.JSON(http.StatusOK, binance.AvgPrice{Mins: 5, Price: price.String()})
+}
+
+func (s *binanceServer) klines(c *gin.Context) {
+	params := getParams(c)
+	startTime, endTime, err := parseTimeRange(params)
+	if err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	limit := parseLimit(params, 500, 1000)
+	klines, err := s.engine.QueryKLines(params.Get("symbol"), types.Interval(params.Get("interval")), startTime, endTime, limit)
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	rows := make([][]interface{}, 0, len(klines))
+	for _, k := range klines {
+		rows = append(rows, []interface{}{
+			k.StartTime.Time().UnixMilli(),
+			k.Open.String(),
+			k.High.String(),
+			k.Low.String(),
+			k.Close.String(),
+			k.Volume.String(),
+			k.EndTime.Time().UnixMilli(),
+			k.QuoteVolume.String(),
+			k.NumberOfTrades,
+			"0",
+			"0",
+			"0",
+		})
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+func (s *binanceServer) depth(c *gin.Context) {
+	params := getParams(c)
+	book, err := s.engine.QueryDepth(params.Get("symbol"), parseLimit(params, 100, 5000))
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lastUpdateId": book.LastUpdateId,
+		"bids":         toBinanceDepthEntries(book.Bids),
+		"asks":         toBinanceDepthEntries(book.Asks),
+	})
+}
+
+func (s *binanceServer) account(c *gin.Context) {
+	account := s.engine.Account()
+	balances := account.Balances()
+
+	var binanceBalances []binance.Balance
+	for _, currency := range sortedCurrencies(balances) {
+		b := balances[currency]
+		binanceBalances = append(binanceBalances, binance.Balance{
+			Asset:  currency,
+			Free:   b.Available.String(),
+			Locked: b.Locked.String(),
+		})
+	}
+
+	c.JSON(http.StatusOK, binance.Account{
+		MakerCommission: account.MakerFeeRate.Mul(fixedpoint.NewFromInt(10000)).Int64(),
+		TakerCommission: account.TakerFeeRate.Mul(fixedpoint.NewFromInt(10000)).Int64(),
+		CommissionRates: binance.CommissionRates{
+			Maker:  account.MakerFeeRate.String(),
+			Taker:  account.TakerFeeRate.String(),
+			Buyer:  "0",
+			Seller: "0",
+		},
+		CanTrade:    account.CanTrade,
+		CanWithdraw: account.CanWithdraw,
+		CanDeposit:  account.CanDeposit,
+		UpdateTime:  uint64(time.Now().UnixMilli()),
+		AccountType: "SPOT",
+		Balances:    binanceBalances,
+		Permissions: []string{"SPOT"},
+	})
+}
+
+func (s *binanceServer) createOrder(c *gin.Context) {
+	params := getParams(c)
+
+	orderType, err := toGlobalOrderType(params.Get("type"))
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	submitOrder := types.SubmitOrder{
+		ClientOrderID: params.Get("newClientOrderId"),
+		Symbol:        params.Get("symbol"),
+		Side:          types.SideType(params.Get("side")),
+		Type:          orderType,
+		TimeInForce:   types.TimeInForce(params.Get("timeInForce")),
+	}
+
+	for key, field := range map[string]*fixedpoint.Value{
+		"quantity":  &submitOrder.Quantity,
+		"price":     &submitOrder.Price,
+		"stopPrice": &submitOrder.StopPrice,
+	} {
+		if v := params.Get(key); v != "" {
+			if *field, err = fixedpoint.NewFromString(v); err != nil {
+				s.abort(c, http.StatusBadRequest, -1100, fmt.Sprintf("Illegal characters found in parameter '%s'.", key))
+				return
+			}
+		}
+	}
+
+	if submitOrder.ClientOrderID == "" {
+		submitOrder.ClientOrderID = strings.ReplaceAll(uuid.New().String(), "-", "")[:22]
+	}
+
+	createdOrder, err := s.engine.SubmitOrder(submitOrder)
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	// the market orders are filled immediately, query the latest status
+	if latest, err := s.engine.QueryOrder(createdOrder.Symbol, createdOrder.OrderID, ""); err == nil {
+		createdOrder = latest
+	}
+
+	o := toBinanceOrder(*createdOrder)
+	response := binance.CreateOrderResponse{
+		Symbol:                   o.Symbol,
+		OrderID:                  o.OrderID,
+		ClientOrderID:            o.ClientOrderID,
+		TransactTime:             o.Time,
+		Price:                    o.Price,
+		OrigQuantity:             o.OrigQuantity,
+		ExecutedQuantity:         o.ExecutedQuantity,
+		CummulativeQuoteQuantity: o.CummulativeQuoteQuantity,
+		Status:                   o.Status,
+		TimeInForce:              o.TimeInForce,
+		Type:                     o.Type,
+		Side:                     o.Side,
+		Fills:                    []*binance.Fill{},
+	}
+
+	if params.Get("newOrderRespType") == "FULL" {
+		trades, _ := s.engine.QueryTrades(createdOrder.Symbol, TradeQuery{OrderID: createdOrder.OrderID})
+		for _, t := range trades {
+			response.Fills = append(response.Fills, &binance.Fill{
+				TradeID:         int64(t.ID),
+				Price:           t.Price.String(),
+				Quantity:        t.Quantity.String(),
+				Commission:      t.Fee.String(),
+				CommissionAsset: t.FeeCurrency,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *binanceServer) queryOrder(c *gin.Context) {
+	params := getParams(c)
+	orderID, err := parseUint(params, "orderId")
+	if err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	o, err := s.engine.QueryOrder(params.Get("symbol"), orderID, params.Get("origClientOrderId"))
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toBinanceOrder(*o))
+}
+
+func (s *binanceServer) cancelOrder(c *gin.Context) {
+	params := getParams(c)
+	orderID, err := parseUint(params, "orderId")
+	if err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	canceledOrder, err := s.engine.CancelOrder(params.Get("symbol"), orderID, params.Get("origClientOrderId"))
+	if err != nil {
+		// binance responds the unknown order error for both the missing orders and the closed orders
+		if errors.Is(err, ErrOrderNotFound) || errors.Is(err, ErrOrderCanNotBeCanceled) {
+			s.abort(c, http.StatusBadRequest, -2011, "Unknown order sent.")
+			return
+		}
+
+		s.error(c, err)
+		return
+	}
+
+	o := toBinanceOrder(*canceledOrder)
+	c.JSON(http.StatusOK, binance.CancelOrderResponse{
+		Symbol:                   o.Symbol,
+		OrigClientOrderID:        o.ClientOrderID,
+		OrderID:                  o.OrderID,
+		OrderListID:              -1,
+		ClientOrderID:            o.ClientOrderID,
+		TransactTime:             time.Now().UnixMilli(),
+		Price:                    o.Price,
+		OrigQuantity:             o.OrigQuantity,
+		ExecutedQuantity:         o.ExecutedQuantity,
+		CummulativeQuoteQuantity: o.CummulativeQuoteQuantity,
+		Status:                   o.Status,
+		TimeInForce:              o.TimeInForce,
+		Type:                     o.Type,
+		Side:                     o.Side,
+	})
+}
+
+func (s *binanceServer) openOrders(c *gin.Context) {
+	orders, err := s.engine.QueryOpenOrders(getParams(c).Get("symbol"))
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toBinanceOrders(orders))
+}
+
+func (s *binanceServer) allOrders(c *gin.Context) {
+	params := getParams(c)
+	orderID, err := parseUint(params, "orderId")
+	if err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	startTime, endTime, err := parseTimeRange(params)
+	if err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	orders, err := s.engine.QueryOrders(params.Get("symbol"), orderID, startTime, endTime, parseLimit(params, 500, 1000))
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toBinanceOrders(orders))
+}
+
+func (s *binanceServer) myTrades(c *gin.Context) {
+	params := getParams(c)
+
+	var q TradeQuery
+	var err error
+	if q.OrderID, err = parseUint(params, "orderId"); err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	if q.FromID, err = parseUint(params, "fromId"); err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	if q.StartTime, q.EndTime, err = parseTimeRange(params); err != nil {
+		s.abort(c, http.StatusBadRequest, -1100, err.Error())
+		return
+	}
+
+	q.Limit = parseLimit(params, 500, 1000)
+
+	trades, err := s.engine.QueryTrades(params.Get("symbol"), q)
+	if err != nil {
+		s.error(c, err)
+		return
+	}
+
+	binanceTrades := make([]binance.TradeV3, 0, len(trades))
+	for _, t := range trades {
+		binanceTrades = append(binanceTrades, binance.TradeV3{
+			ID:              int64(t.ID),
+			Symbol:          t.Symbol,
+			OrderID:         int64(t.OrderID),
+			OrderListId:     -1,
+			Price:           t.Price.String(),
+			Quantity:        t.Quantity.String(),
+			QuoteQuantity:   t.QuoteQuantity.String(),
+			Commission:      t.Fee.String(),
+			CommissionAsset: t.FeeCurrency,
+			Time:            t.Time.Time().UnixMilli(),
+			IsBuyer:         t.IsBuyer,
+			IsMaker:         t.IsMaker,
+			IsBestMatch:     true,
+		})
+	}
+
+	c.JSON(http.StatusOK, binanceTrades)
+}
+
+func (s *binanceServer) createListenKey(c *gin.Context) {
+	listenKey := strings.ReplaceAll(uuid.New().String()+uuid.New().String(), "-", "")
+
+	s.listenKeysMu.Lock()
+	s.listenKeys[listenKey] = struct{}{}
+	s.listenKeysMu.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{"listenKey": listenKey})
+}
+
+func (s *binanceServer) keepaliveListenKey(c *gin.Context) {
+	if !s.hasListenKey(getParams(c).Get("listenKey")) {
+		s.abort(c, http.StatusBadRequest, -1125, "This listenKey does not exist.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (s *binanceServer) closeListenKey(c *gin.Context) {
+	listenKey := getParams(c).Get("listenKey")
+	if !s.hasListenKey(listenKey) {
+		s.abort(c, http.StatusBadRequest, -1125, "This listenKey does not exist.")
+		return
+	}
+
+	s.listenKeysMu.Lock()
+	delete(s.listenKeys, listenKey)
+	s.listenKeysMu.Unlock()
+
+	s.hub.closeUserDataStreams(listenKey)
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (s *binanceServer) hasListenKey(listenKey string) bool {
+	s.listenKeysMu.Lock()
+	defer s.listenKeysMu.Unlock()
+
+	_, ok := s.listenKeys[listenKey]
+	return ok
+}
+
+func (s *binanceServer) serveUserDataStream(c *gin.Context) {
+	listenKey := c.Param("listenKey")
+	if !s.hasListenKey(listenKey) {
+		s.abort(c, http.StatusBadRequest, -1125, "This listenKey does not exist.")
+		return
+	}
+
+	s.hub.serveUserDataStream(c, listenKey)
+}
+
+func (s *binanceServer) abort(c *gin.Context, status, code int, message string) {
+	c.AbortWithStatusJSON(status, binanceError{Code: code, Message: message})
+}
+
+// error converts the engine errors to the Binance error codes
+func (s *binanceServer) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownSymbol):
+		s.abort(c, http.StatusBadRequest, -1121, "Invalid symbol.")
+	case errors.Is(err, ErrOrderNotFound):
+		s.abort(c, http.StatusBadRequest, -2013, "Order does not exist.")
+	case errors.Is(err, ErrInsufficientBalance):
+		s.abort(c, http.StatusBadRequest, -2010, "Account has insufficient balance for requested action.")
+	case errors.Is(err, ErrDuplicateClientOrderID):
+		s.abort(c, http.StatusBadRequest, -2010, "Duplicate order sent.")
+	case errors.Is(err, ErrWouldTakeLiquidity):
+		s.abort(c, http.StatusBadRequest, -2010, "Order would immediately match and take.")
+	case errors.Is(err, ErrNoLastPrice):
+		s.abort(c, http.StatusBadRequest, -1013, "Market is closed.")
+	case errors.Is(err, ErrFilterFailure):
+		s.abort(c, http.StatusBadRequest, -1013, "Filter failure: "+filterName(err))
+	case errors.Is(err, ErrUnsupportedOrderType):
+		s.abort(c, http.StatusBadRequest, -1116, "Invalid orderType.")
+	case errors.Is(err, ErrUnsupportedInterval):
+		s.abort(c, http.StatusBadRequest, -1120, "Invalid interval.")
+	case errors.Is(err, ErrInvalidOrderParameter):
+		s.abort(c, http.StatusBadRequest, -1102, err.Error())
+	default:
+		s.abort(c, http.StatusInternalServerError, -1000, err.Error())
+	}
+}
+
+// filterName extracts the filter name from the filter failure error, e.g., "filter failure: LOT_SIZE, quantity 0.1"
+func filterName(err error) string {
+	msg := strings.TrimPrefix(err.Error(), ErrFilterFailure.Error()+": ")
+	if idx := strings.Index(msg, ","); idx > 0 {
+		return msg[:idx]
+	}
+	return msg
+}
+
+func getParams(c *gin.Context) url.Values {
+	if v, ok := c.Get(paramsContextKey); ok {
+		return v.(url.Values)
+	}
+	return c.Request.URL.Query()
+}
+
+func parseUint(params url.Values, key string) (uint64, error) {
+	v := params.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("illegal characters found in parameter '%s'", key)
+	}
+	return n, nil
+}
+
+func parseTimeRange(params url.Values) (startTime, endTime *time.Time, err error) {
+	for key, field := range map[string]**time.Time{"startTime": &startTime, "endTime": &endTime} {
+		ms, err := parseUint(params, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if ms > 0 {
+			t := time.UnixMilli(int64(ms))
+			*field = &t
+		}
+	}
+
+	return startTime, endTime, nil
+}
+
+func parseLimit(params url.Values, defaultLimit, maxLimit int) int {
+	limit, err := strconv.Atoi(params.Get("limit"))
+	if err != nil || limit <= 0 {
+		return defaultLimit
+	}
+
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
+
+func sortedSymbols(markets types.MarketMap) []string {
+	var symbols []string
+	for symbol := range markets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func toGlobalOrderType(orderType string) (types.OrderType, error) {
+	switch binance.OrderType(orderType) {
+	case binance.OrderTypeLimit:
+		return types.OrderTypeLimit, nil
+	case binance.OrderTypeLimitMaker:
+		return types.OrderTypeLimitMaker, nil
+	case binance.OrderTypeMarket:
+		return types.OrderTypeMarket, nil
+	case binance.OrderTypeStopLoss:
+		return types.OrderTypeStopMarket, nil
+	case binance.OrderTypeStopLossLimit:
+		return types.OrderTypeStopLimit, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedOrderType, orderType)
+}
+
+func toLocalOrderType(orderType types.OrderType) binance.OrderType {
+	switch orderType {
+	case types.OrderTypeStopMarket:
+		return binance.OrderTypeStopLoss
+	case types.OrderTypeStopLimit:
+		return binance.OrderTypeStopLossLimit
+	}
+
+	return binance.OrderType(orderType)
+}
+
+func toLocalOrderStatus(o types.Order) binance.OrderStatusType {
+	if o.OriginalStatus != "" {
+		return binance.OrderStatusType(o.OriginalStatus)
+	}
+
+	return binance.OrderStatusType(o.Status)
+}
+
+func toBinanceOrder(o types.Order) *binance.Order {
+	timeInForce := binance.TimeInForceType(o.TimeInForce)
+	if timeInForce == "" {
+		timeInForce = binance.TimeInForceTypeGTC
+	}
+
+	return &binance.Order{
+		Symbol:                   o.Symbol,
+		OrderID:                  int64(o.OrderID),
+		OrderListId:              -1,
+		ClientOrderID:            o.ClientOrderID,
+		Price:                    o.Price.String(),
+		OrigQuantity:             o.Quantity.String(),
+		ExecutedQuantity:         o.ExecutedQuantity.String(),
+		CummulativeQuoteQuantity: o.ExecutedQuantity.Mul(o.AveragePrice).String(),
+		Status:                   toLocalOrderStatus(o),
+		TimeInForce:              timeInForce,
+		Type:                     toLocalOrderType(o.Type),
+		Side:                     binance.SideType(o.Side),
+		StopPrice:                o.StopPrice.String(),
+		IcebergQuantity:          "0",
+		Time:                     o.CreationTime.Time().UnixMilli(),
+		UpdateTime:               o.UpdateTime.Time().UnixMilli(),
+		IsWorking:                o.IsWorking,
+		OrigQuoteOrderQuantity:   "0",
+	}
+}
+
+func toBinanceOrders(orders []types.Order) []*binance.Order {
+	binanceOrders := make([]*binance.Order, 0, len(orders))
+	for _, o := range orders {
+		binanceOrders = append(binanceOrders, toBinanceOrder(o))
+	}
+	return binanceOrders
+}
+
+func toBinanceDepthEntries(pvs types.PriceVolumeSlice) [][]string {
+	entries := make([][]string, 0, len(pvs))
+	for _, pv := range pvs {
+		entries = append(entries, []string{pv.Price.String(), pv.Volume.String()})
+	}
+	return entries
+}
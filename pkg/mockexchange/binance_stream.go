@@ -0,0 +1,500 @@
+package mockexchange
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	streamSendBufferSize = 1024
+	streamWriteTimeout   = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// subscribeRequest is the websocket subscription request of the Binance stream
+type subscribeRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int      `json:"id"`
+}
+
+type streamConn struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	// listenKey is set when the connection is a user data stream
+	listenKey string
+
+	mu            sync.Mutex
+	subscriptions map[string]struct{}
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (c *streamConn) subscribed(streamName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.subscriptions[streamName]
+	return ok
+}
+
+// push queues the message without blocking the engine, the slow connections are dropped
+func (c *streamConn) push(message []byte) {
+	select {
+	case c.send <- message:
+	case <-c.done:
+	default:
+		log.Warnf("websocket connection %s is too slow, dropping it", c.conn.RemoteAddr())
+		c.close()
+	}
+}
+
+func (c *streamConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.Close()
+	})
+}
+
+// binanceStreamHub manages the websocket connections and publishes the engine events in the Binance stream format
+type binanceStreamHub struct {
+	mu    sync.Mutex
+	conns map[*streamConn]struct{}
+
+	// books are the last published order books, used for computing the depth diffs
+	books map[string]types.SliceOrderBook
+}
+
+func newBinanceStreamHub(engine *Engine) *binanceStreamHub {
+	hub := &binanceStreamHub{
+		conns: make(map[*streamConn]struct{}),
+		books: make(map[string]types.SliceOrderBook),
+	}
+
+	engine.OnMarketUpdate(hub.handleMarketUpdate)
+	engine.OnOrderUpdate(hub.handleOrderUpdate)
+	engine.OnTradeUpdate(hub.handleTradeUpdate)
+	engine.OnBalanceUpdate(hub.handleBalanceUpdate)
+	return hub
+}
+
+func (h *binanceStreamHub) serveMarketStream(c *gin.Context) {
+	h.serve(c, "")
+}
+
+func (h *binanceStreamHub) serveUserDataStream(c *gin.Context, listenKey string) {
+	h.serve(c, listenKey)
+}
+
+func (h *binanceStreamHub) serve(c *gin.Context, listenKey string) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.WithError(err).Error("websocket upgrade error")
+		return
+	}
+
+	sc := &streamConn{
+		conn:          conn,
+		send:          make(chan []byte, streamSendBufferSize),
+		listenKey:     listenKey,
+		subscriptions: make(map[string]struct{}),
+		done:          make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.conns[sc] = struct{}{}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.conns, sc)
+		h.mu.Unlock()
+		sc.close()
+	}()
+
+	go h.writeLoop(sc)
+	h.readLoop(sc)
+}
+
+func (h *binanceStreamHub) writeLoop(sc *streamConn) {
+	for {
+		select {
+		case <-sc.done:
+			return
+
+		case message := <-sc.send:
+			_ = sc.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := sc.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				sc.close()
+				return
+			}
+		}
+	}
+}
+
+func (h *binanceStreamHub) readLoop(sc *streamConn) {
+	for {
+		_, message, err := sc.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req subscribeRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			log.WithError(err).Warnf("invalid websocket request: %s", message)
+			continue
+		}
+
+		var result interface{}
+		sc.mu.Lock()
+		switch req.Method {
+		case "SUBSCRIBE":
+			for _, param := range req.Params {
+				sc.subscriptions[param] = struct{}{}
+			}
+
+		case "UNSUBSCRIBE":
+			for _, param := range req.Params {
+				delete(sc.subscriptions, param)
+			}
+
+		case "LIST_SUBSCRIPTIONS":
+			list := []string{}
+			for param := range sc.subscriptions {
+				list = append(list, param)
+			}
+			result = list
+		}
+		sc.mu.Unlock()
+
+		h.push(sc, map[string]interface{}{"result": result, "id": req.ID})
+	}
+}
+
+// DropAll closes all the websocket connections, the clients are expected to reconnect
+func (h *binanceStreamHub) DropAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sc := range h.conns {
+		sc.close()
+	}
+}
+
+func (h *binanceStreamHub) closeUserDataStreams(listenKey string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sc := range h.conns {
+		if sc.listenKey == listenKey {
+			sc.close()
+		}
+	}
+}
+
+func (h *binanceStreamHub) push(sc *streamConn, payload interface{}) {
+	message, err := json.Marshal(payload)
+	if err != nil {
+		log.WithError(err).Error("websocket message marshal error")
+		return
+	}
+
+	sc.push(message)
+}
+
+// broadcast sends the payload to the connections matching the filter
+func (h *binanceStreamHub) broadcast(filter func(sc *streamConn) bool, payload interface{}) {
+	message, err := json.Marshal(payload)
+	if err != nil {
+		log.WithError(err).Error("websocket message marshal error")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sc := range h.conns {
+		if filter(sc) {
+			sc.push(message)
+		}
+	}
+}
+
+func (h *binanceStreamHub) publish(streamName string, payload interface{}) {
+	h.broadcast(func(sc *streamConn) bool {
+		return sc.subscribed(streamName)
+	}, payload)
+}
+
+func (h *binanceStreamHub) publishUserData(payload interface{}) {
+	h.broadcast(func(sc *streamConn) bool {
+		return sc.listenKey != ""
+	}, payload)
+}
+
+func (h *binanceStreamHub) handleMarketUpdate(update MarketUpdate) {
+	symbol := update.Symbol
+	prefix := strings.ToLower(symbol) + "@"
+	eventTime := update.Time.UnixMilli()
+
+	h.publish(prefix+"trade", map[string]interface{}{
+		"e": "trade",
+		"E": eventTime,
+		"s": symbol,
+		"t": update.TradeID,
+		"p": update.Price.String(),
+		"q": update.Volume.String(),
+		"b": 0,
+		"a": 0,
+		"T": eventTime,
+		"m": update.IsBuyerMaker,
+		"M": true,
+	})
+
+	h.publish(prefix+"aggTrade", map[string]interface{}{
+		"e": "aggTrade",
+		"E": eventTime,
+		"s": symbol,
+		"a": update.TradeID,
+		"p": update.Price.String(),
+		"q": update.Volume.String(),
+		"f": update.TradeID,
+		"l": update.TradeID,
+		"T": eventTime,
+		"m": update.IsBuyerMaker,
+		"M": true,
+	})
+
+	book := update.Book
+	if len(book.Bids) > 0 && len(book.Asks) > 0 {
+		h.publish(prefix+"bookTicker", map[string]interface{}{
+			"u": book.LastUpdateId,
+			"s": symbol,
+			"b": book.Bids[0].Price.String(),
+			"B": book.Bids[0].Volume.String(),
+			"a": book.Asks[0].Price.String(),
+			"A": book.Asks[0].Volume.String(),
+		})
+	}
+
+	h.mu.Lock()
+	prevBook := h.books[symbol]
+	h.books[symbol] = book
+	h.mu.Unlock()
+
+	depthUpdate := map[string]interface{}{
+		"e": "depthUpdate",
+		"E": eventTime,
+		"s": symbol,
+		"U": book.LastUpdateId,
+		"u": book.LastUpdateId,
+		"b": toBinanceDepthEntries(diffPriceVolumes(prevBook.Bids, book.Bids)),
+		"a": toBinanceDepthEntries(diffPriceVolumes(prevBook.Asks, book.Asks)),
+	}
+	for _, suffix := range []string{"depth", "depth@100ms", "depth@1000ms"} {
+		h.publish(prefix+suffix, depthUpdate)
+	}
+
+	for _, levels := range []int{5, 10, 20} {
+		partialDepth := map[string]interface{}{
+			"lastUpdateId": book.LastUpdateId,
+			"bids":         toBinanceDepthEntries(headPriceVolumes(book.Bids, levels)),
+			"asks":         toBinanceDepthEntries(headPriceVolumes(book.Asks, levels)),
+		}
+
+		name := prefix + "depth" + strconv.Itoa(levels)
+		for _, suffix := range []string{"", "@100ms", "@1000ms"} {
+			h.publish(name+suffix, partialDepth)
+		}
+	}
+
+	for _, k := range update.ClosedKLines {
+		h.publish(prefix+"kline_"+string(k.Interval), toBinanceKLineEvent(eventTime, k, true))
+	}
+
+	for interval, k := range update.KLines {
+		h.publish(prefix+"kline_"+string(interval), toBinanceKLineEvent(eventTime, k, false))
+	}
+}
+
+func (h *binanceStreamHub) handleOrderUpdate(o types.Order) {
+	executionType := "NEW"
+	switch o.Status {
+	case types.OrderStatusCanceled:
+		executionType = "CANCELED"
+		if o.OriginalStatus == "EXPIRED" {
+			executionType = "EXPIRED"
+		}
+
+	case types.OrderStatusRejected:
+		executionType = "REJECTED"
+
+	case types.OrderStatusFilled, types.OrderStatusPartiallyFilled:
+		// the fills are published by the trade updates
+		return
+	}
+
+	h.publishUserData(toExecutionReport(o, executionType, nil))
+}
+
+func (h *binanceStreamHub) handleTradeUpdate(trade types.Trade, o types.Order) {
+	h.publishUserData(toExecutionReport(o, "TRADE", &trade))
+}
+
+func (h *binanceStreamHub) handleBalanceUpdate(balances types.BalanceMap) {
+	now := time.Now().UnixMilli()
+
+	var entries []map[string]string
+	for _, currency := range sortedCurrencies(balances) {
+		b := balances[currency]
+		entries = append(entries, map[string]string{
+			"a": currency,
+			"f": b.Available.String(),
+			"l": b.Locked.String(),
+		})
+	}
+
+	h.publishUserData(map[string]interface{}{
+		"e": "outboundAccountPosition",
+		"E": now,
+		"u": now,
+		"B": entries,
+	})
+}
+
+func toExecutionReport(o types.Order, executionType string, trade *types.Trade) map[string]interface{} {
+	binanceOrder := toBinanceOrder(o)
+	eventTime := time.Now().UnixMilli()
+
+	report := map[string]interface{}{
+		"e": "executionReport",
+		"E": eventTime,
+		"s": o.Symbol,
+		"c": o.ClientOrderID,
+		"S": string(o.Side),
+		"o": string(binanceOrder.Type),
+		"f": string(binanceOrder.TimeInForce),
+		"q": o.Quantity.String(),
+		"p": o.Price.String(),
+		"P": o.StopPrice.String(),
+		"F": "0",
+		"g": -1,
+		"C": "",
+		"x": executionType,
+		"X": string(binanceOrder.Status),
+		"r": "NONE",
+		"i": o.OrderID,
+		"l": "0",
+		"z": o.ExecutedQuantity.String(),
+		"L": "0",
+		"n": "0",
+		"N": nil,
+		"T": o.UpdateTime.Time().UnixMilli(),
+		"t": -1,
+		"I": 0,
+		"w": o.IsWorking,
+		"m": false,
+		"M": false,
+		"O": o.CreationTime.Time().UnixMilli(),
+		"Z": binanceOrder.CummulativeQuoteQuantity,
+		"Y": "0",
+		"Q": "0",
+	}
+
+	if executionType == "CANCELED" || executionType == "EXPIRED" {
+		report["C"] = o.ClientOrderID
+	}
+
+	if trade != nil {
+		report["l"] = trade.Quantity.String()
+		report["L"] = trade.Price.String()
+		report["n"] = trade.Fee.String()
+		report["N"] = trade.FeeCurrency
+		report["T"] = trade.Time.Time().UnixMilli()
+		report["t"] = trade.ID
+		report["m"] = trade.IsMaker
+		report["Y"] = trade.QuoteQuantity.String()
+	}
+
+	return report
+}
+
+func toBinanceKLineEvent(eventTime int64, k types.KLine, closed bool) map[string]interface{} {
+	return map[string]interface{}{
+		"e": "kline",
+		"E": eventTime,
+		"s": k.Symbol,
+		"k": map[string]interface{}{
+			"t": k.StartTime.Time().UnixMilli(),
+			"T": k.EndTime.Time().UnixMilli(),
+			"s": k.Symbol,
+			"i": string(k.Interval),
+			"f": 0,
+			"L": 0,
+			"o": k.Open.String(),
+			"c": k.Close.String(),
+			"h": k.High.String(),
+			"l": k.Low.String(),
+			"v": k.Volume.String(),
+			"n": k.NumberOfTrades,
+			"x": closed || k.Closed,
+			"q": k.QuoteVolume.String(),
+			"V": "0",
+			"Q": "0",
+			"B": "0",
+		},
+	}
+}
+
+// diffPriceVolumes returns the levels of the current book and the removed levels of the previous book with zero volume
+func diffPriceVolumes(prev, current types.PriceVolumeSlice) types.PriceVolumeSlice {
+	diff := append(types.PriceVolumeSlice{}, current...)
+
+	for _, pv := range prev {
+		found := false
+		for _, c := range current {
+			if c.Price.Eq(pv.Price) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			diff = append(diff, types.PriceVolume{Price: pv.Price, Volume: fixedpoint.Zero})
+		}
+	}
+
+	return diff
+}
+
+func headPriceVolumes(pvs types.PriceVolumeSlice, n int) types.PriceVolumeSlice {
+	if len(pvs) > n {
+		return pvs[:n]
+	}
+	return pvs
+}
+
+func sortedCurrencies(balances types.BalanceMap) []string {
+	var currencies []string
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
@@ -0,0 +1,200 @@
+package mockexchange
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const DefaultBindAddress = "localhost:9443"
+
+const defaultBookLevels = 20
+
+var defaultFeeRate = fixedpoint.MustNewFromString("0.075%")
+
+type MarketConfig struct {
+	BaseCurrency  string `json:"baseCurrency" yaml:"baseCurrency"`
+	QuoteCurrency string `json:"quoteCurrency" yaml:"quoteCurrency"`
+
+	TickSize    fixedpoint.Value `json:"tickSize" yaml:"tickSize"`
+	StepSize    fixedpoint.Value `json:"stepSize" yaml:"stepSize"`
+	MinQuantity fixedpoint.Value `json:"minQuantity,omitempty" yaml:"minQuantity,omitempty"`
+	MaxQuantity fixedpoint.Value `json:"maxQuantity,omitempty" yaml:"maxQuantity,omitempty"`
+	MinNotional fixedpoint.Value `json:"minNotional,omitempty" yaml:"minNotional,omitempty"`
+
+	// BookLevels is the number of the price levels on each side of the synthesized order book
+	BookLevels int `json:"bookLevels,omitempty" yaml:"bookLevels,omitempty"`
+
+	// BookVolume is the volume of each price level of the synthesized order book
+	BookVolume fixedpoint.Value `json:"bookVolume,omitempty" yaml:"bookVolume,omitempty"`
+}
+
+func (c MarketConfig) Market(symbol string) types.Market {
+	minQuantity := c.MinQuantity
+	if minQuantity.IsZero() {
+		minQuantity = c.StepSize
+	}
+
+	return types.Market{
+		Symbol:          symbol,
+		LocalSymbol:     symbol,
+		PricePrecision:  precisionOf(c.TickSize),
+		VolumePrecision: precisionOf(c.StepSize),
+		QuoteCurrency:   c.QuoteCurrency,
+		BaseCurrency:    c.BaseCurrency,
+		MinNotional:     c.MinNotional,
+		MinAmount:       c.MinNotional,
+		MinQuantity:     minQuantity,
+		MaxQuantity:     c.MaxQuantity,
+		StepSize:        c.StepSize,
+		TickSize:        c.TickSize,
+	}
+}
+
+// precisionOf converts the step size to the number of decimals, e.g., 0.001 = 3
+func precisionOf(step fixedpoint.Value) int {
+	if step.Sign() <= 0 {
+		return 8
+	}
+
+	p := -int(math.Round(math.Log10(step.Float64())))
+	if p < 0 {
+		return 0
+	}
+	return p
+}
+
+type AccountConfig struct {
+	// APIKey and APISecret are used for verifying the request signature, the verification is skipped if they are empty
+	APIKey    string `json:"apiKey,omitempty" yaml:"apiKey,omitempty"`
+	APISecret string `json:"apiSecret,omitempty" yaml:"apiSecret,omitempty"`
+
+	MakerFeeRate fixedpoint.Value `json:"makerFeeRate,omitempty" yaml:"makerFeeRate,omitempty"`
+	TakerFeeRate fixedpoint.Value `json:"takerFeeRate,omitempty" yaml:"takerFeeRate,omitempty"`
+
+	Balances map[string]fixedpoint.Value `json:"balances" yaml:"balances"`
+}
+
+// PricePoint is a waypoint of a scripted price path
+type PricePoint struct {
+	Price fixedpoint.Value `json:"price" yaml:"price"`
+
+	// Steps is the number of ticks used for moving from the previous point to this point
+	Steps int `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+type PricePathConfig struct {
+	// Script is the scripted price path, the price moves linearly between the points
+	Script []PricePoint `json:"script,omitempty" yaml:"script,omitempty"`
+
+	// Replay is the kline file (csv or tsv, e.g., the kline dump of the backtest report) to replay
+	Replay string `json:"replay,omitempty" yaml:"replay,omitempty"`
+
+	// Loop restarts the path from the beginning when it's exhausted
+	Loop bool `json:"loop,omitempty" yaml:"loop,omitempty"`
+
+	// Volume is the trade volume of each tick
+	Volume fixedpoint.Value `json:"volume,omitempty" yaml:"volume,omitempty"`
+}
+
+type FeedConfig struct {
+	// Interval is the wall clock time between two ticks
+	Interval time.Duration `json:"interval" yaml:"interval"`
+
+	Symbols map[string]PricePathConfig `json:"symbols" yaml:"symbols"`
+}
+
+type FaultConfig struct {
+	// ErrorRate is the probability [0, 1] of responding a REST request with ErrorStatus
+	ErrorRate float64 `json:"errorRate,omitempty" yaml:"errorRate,omitempty"`
+
+	// ErrorStatus is the http status code of the injected errors, defaults to 503
+	ErrorStatus int `json:"errorStatus,omitempty" yaml:"errorStatus,omitempty"`
+
+	// ErrorPaths limits the injected errors to the given url paths
+	ErrorPaths []string `json:"errorPaths,omitempty" yaml:"errorPaths,omitempty"`
+
+	// Latency is the delay added to every REST response
+	Latency time.Duration `json:"latency,omitempty" yaml:"latency,omitempty"`
+
+	// DropInterval drops all the websocket connections periodically
+	DropInterval time.Duration `json:"dropInterval,omitempty" yaml:"dropInterval,omitempty"`
+}
+
+type Config struct {
+	Markets map[string]MarketConfig `json:"markets" yaml:"markets"`
+	Account AccountConfig           `json:"account" yaml:"account"`
+	Feed    FeedConfig              `json:"feed" yaml:"feed"`
+	Faults  FaultConfig             `json:"faults,omitempty" yaml:"faults,omitempty"`
+}
+
+func (c *Config) Validate() error {
+	if len(c.Markets) == 0 {
+		return fmt.Errorf("mock exchange config: at least one market is required")
+	}
+
+	for symbol, market := range c.Markets {
+		if market.BaseCurrency == "" || market.QuoteCurrency == "" {
+			return fmt.Errorf("mock exchange config: market %s requires baseCurrency and quoteCurrency", symbol)
+		}
+
+		if market.TickSize.Sign() <= 0 || market.StepSize.Sign() <= 0 {
+			return fmt.Errorf("mock exchange config: market %s requires tickSize and stepSize", symbol)
+		}
+	}
+
+	for symbol, path := range c.Feed.Symbols {
+		if _, ok := c.Markets[symbol]; !ok {
+			return fmt.Errorf("mock exchange config: feed symbol %s is not defined in markets", symbol)
+		}
+
+		if len(path.Script) == 0 && path.Replay == "" {
+			return fmt.Errorf("mock exchange config: feed symbol %s requires a script or a replay file", symbol)
+		}
+	}
+
+	if c.Faults.ErrorRate < 0 || c.Faults.ErrorRate > 1 {
+		return fmt.Errorf("mock exchange config: errorRate %f is out of range [0, 1]", c.Faults.ErrorRate)
+	}
+
+	return nil
+}
+
+func (c *Config) setDefaults() {
+	if c.Account.MakerFeeRate.IsZero() {
+		c.Account.MakerFeeRate = defaultFeeRate
+	}
+
+	if c.Account.TakerFeeRate.IsZero() {
+		c.Account.TakerFeeRate = defaultFeeRate
+	}
+
+	if c.Feed.Interval == 0 {
+		c.Feed.Interval = time.Second
+	}
+}
+
+func LoadConfig(configFile string) (*Config, error) {
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	config.setDefaults()
+	return &config, nil
+}
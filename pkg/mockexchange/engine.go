@@ -0,0 +1,949 @@
+package mockexchange
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var (
+	ErrUnknownSymbol          = errors.New("unknown symbol")
+	ErrOrderNotFound          = errors.New("order does not exist")
+	ErrInsufficientBalance    = errors.New("account has insufficient balance for requested action")
+	ErrDuplicateClientOrderID = errors.New("duplicate client order id")
+	ErrWouldTakeLiquidity     = errors.New("order would immediately match and take")
+	ErrNoLastPrice            = errors.New("market has no price yet")
+	ErrUnsupportedOrderType   = errors.New("unsupported order type")
+	ErrFilterFailure          = errors.New("filter failure")
+	ErrInvalidOrderParameter  = errors.New("invalid order parameter")
+	ErrOrderCanNotBeCanceled  = errors.New("order can not be canceled")
+	ErrUnsupportedInterval    = errors.New("unsupported kline interval")
+)
+
+// maxKLines is the number of klines kept for each symbol and interval
+const maxKLines = 1000
+
+// KLineIntervals are the intervals that the engine aggregates the ticks into
+var KLineIntervals = []types.Interval{
+	types.Interval1s,
+	types.Interval1m,
+	types.Interval3m,
+	types.Interval5m,
+	types.Interval15m,
+	types.Interval30m,
+	types.Interval1h,
+	types.Interval2h,
+	types.Interval4h,
+	types.Interval6h,
+	types.Interval12h,
+	types.Interval1d,
+	types.Interval3d,
+	types.Interval1w,
+}
+
+// Tick is a price movement of a symbol, ticks are sent by the feed to drive the matching engine
+type Tick struct {
+	Symbol string
+	Price  fixedpoint.Value
+	Volume fixedpoint.Value
+	Time   time.Time
+}
+
+// MarketUpdate is the market data snapshot after a tick is processed
+type MarketUpdate struct {
+	Tick
+
+	// TradeID is the market trade ID of the tick
+	TradeID uint64
+
+	// IsBuyerMaker is true when the price moves down
+	IsBuyerMaker bool
+
+	// Book is the synthesized order book around the tick price
+	Book types.SliceOrderBook
+
+	// KLines are the current klines of all the intervals
+	KLines map[types.Interval]types.KLine
+
+	// ClosedKLines are the klines closed by this tick
+	ClosedKLines []types.KLine
+}
+
+// TradeQuery is the query options of Engine.QueryTrades
+type TradeQuery struct {
+	OrderID   uint64
+	FromID    uint64
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int
+}
+
+type order struct {
+	types.Order
+
+	// lockedCurrency and locked are the balance locked by the order
+	lockedCurrency string
+	locked         fixedpoint.Value
+
+	// triggered is set when the stop price of the stop order is reached
+	triggered bool
+}
+
+type symbolState struct {
+	market types.Market
+
+	bookLevels int
+	bookVolume fixedpoint.Value
+
+	lastPrice      fixedpoint.Value
+	lastTime       time.Time
+	marketTradeID  uint64
+	bookUpdateID   int64
+	klines         map[types.Interval][]types.KLine
+	openOrders     []*order
+	trades         []types.Trade
+	orders         []*order
+	ordersByClient map[string]*order
+}
+
+// Engine is a price driven matching engine of a single account.
+// Resting orders are filled entirely at their limit price once the tick price reaches them,
+// and the orders that cross the last price are filled immediately at the last price.
+//
+//go:generate callbackgen -type Engine
+type Engine struct {
+	mu sync.Mutex
+
+	account      *types.Account
+	makerFeeRate fixedpoint.Value
+	takerFeeRate fixedpoint.Value
+
+	symbols map[string]*symbolState
+	orders  map[uint64]*order
+
+	orderID uint64
+	tradeID uint64
+
+	now func() time.Time
+
+	orderUpdateCallbacks   []func(order types.Order)
+	tradeUpdateCallbacks   []func(trade types.Trade, order types.Order)
+	balanceUpdateCallbacks []func(balances types.BalanceMap)
+	marketUpdateCallbacks  []func(update MarketUpdate)
+}
+
+func NewEngine(config *Config) *Engine {
+	account := types.NewAccount()
+	account.MakerFeeRate = config.Account.MakerFeeRate
+	account.TakerFeeRate = config.Account.TakerFeeRate
+	account.CanTrade = true
+	account.CanDeposit = true
+	account.CanWithdraw = true
+
+	balances := types.BalanceMap{}
+	for currency, amount := range config.Account.Balances {
+		balances[currency] = types.Balance{
+			Currency:  currency,
+			Available: amount,
+			Locked:    fixedpoint.Zero,
+		}
+	}
+	account.UpdateBalances(balances)
+
+	e := &Engine{
+		account:      account,
+		makerFeeRate: config.Account.MakerFeeRate,
+		takerFeeRate: config.Account.TakerFeeRate,
+		symbols:      make(map[string]*symbolState),
+		orders:       make(map[uint64]*order),
+		now:          time.Now,
+	}
+
+	for symbol, marketConfig := range config.Markets {
+		bookLevels := marketConfig.BookLevels
+		if bookLevels <= 0 {
+			bookLevels = defaultBookLevels
+		}
+
+		bookVolume := marketConfig.BookVolume
+		if bookVolume.IsZero() {
+			bookVolume = fixedpoint.One
+		}
+
+		e.symbols[symbol] = &symbolState{
+			market:         marketConfig.Market(symbol),
+			bookLevels:     bookLevels,
+			bookVolume:     bookVolume,
+			klines:         make(map[types.Interval][]types.KLine),
+			ordersByClient: make(map[string]*order),
+		}
+	}
+
+	return e
+}
+
+func (e *Engine) Markets() types.MarketMap {
+	markets := types.MarketMap{}
+	for symbol, s := range e.symbols {
+		markets[symbol] = s.market
+	}
+	return markets
+}
+
+func (e *Engine) Account() *types.Account {
+	return e.account
+}
+
+func (e *Engine) LastPrice(symbol string) (fixedpoint.Value, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok || s.lastPrice.IsZero() {
+		return fixedpoint.Zero, false
+	}
+
+	return s.lastPrice, true
+}
+
+// ProcessTick moves the price of the symbol, fills the orders reached by the price and publishes the market update
+func (e *Engine) ProcessTick(tick Tick) error {
+	e.mu.Lock()
+	s, ok := e.symbols[tick.Symbol]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownSymbol, tick.Symbol)
+	}
+
+	if tick.Time.IsZero() {
+		tick.Time = e.now()
+	}
+
+	tick.Price = s.market.TruncatePrice(tick.Price)
+	if tick.Price.Sign() <= 0 {
+		e.mu.Unlock()
+		return fmt.Errorf("%w: tick price %s of %s is not positive", ErrInvalidOrderParameter, tick.Price.String(), tick.Symbol)
+	}
+
+	isBuyerMaker := tick.Price.Compare(s.lastPrice) < 0
+	s.lastPrice = tick.Price
+	s.lastTime = tick.Time
+	s.marketTradeID++
+	s.bookUpdateID++
+
+	closedKLines := s.updateKLines(tick)
+
+	var events []interface{}
+	events = append(events, e.matchOrders(s)...)
+
+	update := MarketUpdate{
+		Tick:         tick,
+		TradeID:      s.marketTradeID,
+		IsBuyerMaker: isBuyerMaker,
+		Book:         s.book(0),
+		KLines:       s.currentKLines(),
+		ClosedKLines: closedKLines,
+	}
+	e.mu.Unlock()
+
+	e.emitEvents(events)
+	e.EmitMarketUpdate(update)
+	return nil
+}
+
+func (e *Engine) SubmitOrder(submitOrder types.SubmitOrder) (*types.Order, error) {
+	e.mu.Lock()
+	createdOrder, events, err := e.submitOrder(submitOrder)
+	e.mu.Unlock()
+
+	e.emitEvents(events)
+	return createdOrder, err
+}
+
+func (e *Engine) submitOrder(submitOrder types.SubmitOrder) (*types.Order, []interface{}, error) {
+	s, ok := e.symbols[submitOrder.Symbol]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, submitOrder.Symbol)
+	}
+
+	if err := s.validate(submitOrder); err != nil {
+		return nil, nil, err
+	}
+
+	if len(submitOrder.ClientOrderID) > 0 {
+		if o, ok := s.ordersByClient[submitOrder.ClientOrderID]; ok && !o.Status.Closed() {
+			return nil, nil, fmt.Errorf("%w: %s", ErrDuplicateClientOrderID, submitOrder.ClientOrderID)
+		}
+	}
+
+	switch submitOrder.Type {
+	case types.OrderTypeMarket:
+		if s.lastPrice.IsZero() {
+			return nil, nil, fmt.Errorf("%w: %s", ErrNoLastPrice, submitOrder.Symbol)
+		}
+
+	case types.OrderTypeLimitMaker:
+		if s.crosses(submitOrder.Side, submitOrder.Price) {
+			return nil, nil, ErrWouldTakeLiquidity
+		}
+	}
+
+	// lock the balance with the worst price we know
+	lockPrice := submitOrder.Price
+	switch submitOrder.Type {
+	case types.OrderTypeMarket:
+		lockPrice = s.lastPrice
+	case types.OrderTypeStopMarket:
+		lockPrice = submitOrder.StopPrice
+	}
+
+	o := &order{}
+	switch submitOrder.Side {
+	case types.SideTypeBuy:
+		o.lockedCurrency = s.market.QuoteCurrency
+		o.locked = submitOrder.Quantity.Mul(lockPrice)
+	case types.SideTypeSell:
+		o.lockedCurrency = s.market.BaseCurrency
+		o.locked = submitOrder.Quantity
+	}
+
+	if err := e.account.LockBalance(o.lockedCurrency, o.locked); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInsufficientBalance, err.Error())
+	}
+
+	e.orderID++
+	now := e.now()
+	o.Order = types.Order{
+		SubmitOrder:      submitOrder,
+		OrderID:          e.orderID,
+		Status:           types.OrderStatusNew,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     types.Time(now),
+		UpdateTime:       types.Time(now),
+	}
+
+	e.orders[o.OrderID] = o
+	s.orders = append(s.orders, o)
+	if len(o.ClientOrderID) > 0 {
+		s.ordersByClient[o.ClientOrderID] = o
+	}
+
+	events := []interface{}{o.Order, e.account.Balances()}
+
+	switch {
+	case o.Type == types.OrderTypeMarket:
+		events = append(events, e.fillOrder(s, o, s.lastPrice, false)...)
+
+	case (o.Type == types.OrderTypeLimit) && s.crosses(o.Side, o.Price):
+		events = append(events, e.fillOrder(s, o, s.lastPrice, false)...)
+
+	case o.Type == types.OrderTypeLimit && (o.TimeInForce == types.TimeInForceIOC || o.TimeInForce == types.TimeInForceFOK):
+		// the IOC and FOK orders that can not be filled immediately are expired
+		o.OriginalStatus = "EXPIRED"
+		events = append(events, e.closeOrder(s, o, types.OrderStatusCanceled)...)
+
+	default:
+		s.openOrders = append(s.openOrders, o)
+	}
+
+	createdOrder := o.Order
+	return &createdOrder, events, nil
+}
+
+func (e *Engine) CancelOrder(symbol string, orderID uint64, clientOrderID string) (*types.Order, error) {
+	e.mu.Lock()
+	canceledOrder, events, err := e.cancelOrder(symbol, orderID, clientOrderID)
+	e.mu.Unlock()
+
+	e.emitEvents(events)
+	return canceledOrder, err
+}
+
+func (e *Engine) cancelOrder(symbol string, orderID uint64, clientOrderID string) (*types.Order, []interface{}, error) {
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	o, err := e.findOrder(s, orderID, clientOrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.Status.Closed() {
+		return nil, nil, fmt.Errorf("%w: order %d is %s", ErrOrderCanNotBeCanceled, o.OrderID, o.Status)
+	}
+
+	events := e.closeOrder(s, o, types.OrderStatusCanceled)
+	canceledOrder := o.Order
+	return &canceledOrder, events, nil
+}
+
+func (e *Engine) QueryOrder(symbol string, orderID uint64, clientOrderID string) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	o, err := e.findOrder(s, orderID, clientOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := o.Order
+	return &found, nil
+}
+
+func (e *Engine) QueryOpenOrders(symbol string) ([]types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var orders []types.Order
+	for sym, s := range e.symbols {
+		if symbol != "" && symbol != sym {
+			continue
+		}
+
+		for _, o := range s.openOrders {
+			orders = append(orders, o.Order)
+		}
+	}
+
+	return orders, nil
+}
+
+// QueryOrders returns the orders of the symbol in ascending order.
+// When fromOrderID is given, the orders with ID >= fromOrderID are returned, otherwise the orders are filtered by the creation time.
+func (e *Engine) QueryOrders(
+	symbol string, fromOrderID uint64, startTime, endTime *time.Time, limit int,
+) ([]types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	var orders []types.Order
+	for _, o := range s.orders {
+		if fromOrderID > 0 {
+			if o.OrderID < fromOrderID {
+				continue
+			}
+		} else if !inTimeRange(o.CreationTime.Time(), startTime, endTime) {
+			continue
+		}
+
+		orders = append(orders, o.Order)
+		if limit > 0 && len(orders) >= limit {
+			break
+		}
+	}
+
+	return orders, nil
+}
+
+// QueryTrades returns the trades of the symbol in ascending order
+func (e *Engine) QueryTrades(symbol string, q TradeQuery) ([]types.Trade, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	var trades []types.Trade
+	for _, t := range s.trades {
+		if q.OrderID > 0 && t.OrderID != q.OrderID {
+			continue
+		}
+
+		if q.FromID > 0 {
+			if t.ID < q.FromID {
+				continue
+			}
+		} else if !inTimeRange(t.Time.Time(), q.StartTime, q.EndTime) {
+			continue
+		}
+
+		trades = append(trades, t)
+		if q.Limit > 0 && len(trades) >= q.Limit {
+			break
+		}
+	}
+
+	return trades, nil
+}
+
+// QueryKLines returns the klines of the symbol in ascending order, the last kline might not be closed yet
+func (e *Engine) QueryKLines(
+	symbol string, interval types.Interval, startTime, endTime *time.Time, limit int,
+) ([]types.KLine, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	if !isKLineInterval(interval) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedInterval, interval)
+	}
+
+	var klines []types.KLine
+	for _, k := range s.klines[interval] {
+		if startTime != nil && k.StartTime.Time().Before(*startTime) {
+			continue
+		}
+
+		if endTime != nil && k.StartTime.Time().After(*endTime) {
+			continue
+		}
+
+		klines = append(klines, k)
+	}
+
+	// keep the latest klines
+	if limit > 0 && len(klines) > limit {
+		if startTime != nil {
+			klines = klines[:limit]
+		} else {
+			klines = klines[len(klines)-limit:]
+		}
+	}
+
+	return klines, nil
+}
+
+// QueryDepth returns the synthesized order book, levels = 0 returns all the levels
+func (e *Engine) QueryDepth(symbol string, levels int) (types.SliceOrderBook, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return types.SliceOrderBook{}, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	return s.book(levels), nil
+}
+
+// QueryTicker returns the 24 hours ticker of the symbol
+func (e *Engine) QueryTicker(symbol string) (*types.Ticker, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+
+	ticker := &types.Ticker{
+		Time: s.lastTime,
+		Last: s.lastPrice,
+		Open: s.lastPrice,
+		High: s.lastPrice,
+		Low:  s.lastPrice,
+	}
+
+	since := s.lastTime.Add(-24 * time.Hour)
+	first := true
+	for _, k := range s.klines[types.Interval1m] {
+		if k.StartTime.Time().Before(since) {
+			continue
+		}
+
+		if first {
+			ticker.Open = k.Open
+			first = false
+		}
+
+		ticker.High = fixedpoint.Max(ticker.High, k.High)
+		ticker.Low = fixedpoint.Min(ticker.Low, k.Low)
+		ticker.Volume = ticker.Volume.Add(k.Volume)
+	}
+
+	if !s.lastPrice.IsZero() {
+		ticker.Buy = s.lastPrice.Sub(s.market.TickSize)
+		ticker.Sell = s.lastPrice.Add(s.market.TickSize)
+	}
+
+	return ticker, nil
+}
+
+func (e *Engine) findOrder(s *symbolState, orderID uint64, clientOrderID string) (*order, error) {
+	if orderID > 0 {
+		o, ok := e.orders[orderID]
+		if !ok || o.Symbol != s.market.Symbol {
+			return nil, fmt.Errorf("%w: order id %d", ErrOrderNotFound, orderID)
+		}
+		return o, nil
+	}
+
+	if len(clientOrderID) > 0 {
+		o, ok := s.ordersByClient[clientOrderID]
+		if !ok {
+			return nil, fmt.Errorf("%w: client order id %s", ErrOrderNotFound, clientOrderID)
+		}
+		return o, nil
+	}
+
+	return nil, fmt.Errorf("%w: either order id or client order id is required", ErrInvalidOrderParameter)
+}
+
+// matchOrders fills the open orders that are reached by the last price
+func (e *Engine) matchOrders(s *symbolState) (events []interface{}) {
+	price := s.lastPrice
+
+	var remaining, makers, takers []*order
+	for _, o := range s.openOrders {
+		switch o.Type {
+		case types.OrderTypeStopMarket, types.OrderTypeStopLimit:
+			if !o.triggered {
+				if !stopTriggered(o.Side, o.StopPrice, price) {
+					remaining = append(remaining, o)
+					continue
+				}
+
+				o.triggered = true
+				if o.Type == types.OrderTypeStopMarket || s.crosses(o.Side, o.Price) {
+					takers = append(takers, o)
+				} else {
+					remaining = append(remaining, o)
+				}
+				continue
+			}
+
+			// the triggered stop limit order works as a limit order
+			if limitReached(o.Side, o.Price, price) {
+				makers = append(makers, o)
+				continue
+			}
+
+		case types.OrderTypeLimit, types.OrderTypeLimitMaker:
+			if limitReached(o.Side, o.Price, price) {
+				makers = append(makers, o)
+				continue
+			}
+		}
+
+		remaining = append(remaining, o)
+	}
+
+	s.openOrders = remaining
+
+	for _, o := range takers {
+		events = append(events, e.fillOrder(s, o, price, false)...)
+	}
+
+	for _, o := range makers {
+		events = append(events, e.fillOrder(s, o, o.Price, true)...)
+	}
+
+	return events
+}
+
+// fillOrder fills the whole quantity of the order at the given price
+func (e *Engine) fillOrder(s *symbolState, o *order, price fixedpoint.Value, isMaker bool) []interface{} {
+	feeRate := e.takerFeeRate
+	if isMaker {
+		feeRate = e.makerFeeRate
+	}
+
+	quantity := o.Quantity
+	quoteQuantity := quantity.Mul(price)
+
+	var fee fixedpoint.Value
+	var feeCurrency string
+
+	switch o.Side {
+	case types.SideTypeBuy:
+		// the buyer pays the fee in the base currency
+		fee = quantity.Mul(feeRate)
+		feeCurrency = s.market.BaseCurrency
+		_ = e.account.UseLockedBalance(o.lockedCurrency, o.locked)
+		e.account.AddBalance(s.market.QuoteCurrency, o.locked.Sub(quoteQuantity))
+		e.account.AddBalance(s.market.BaseCurrency, quantity.Sub(fee))
+
+	case types.SideTypeSell:
+		// the seller pays the fee in the quote currency
+		fee = quoteQuantity.Mul(feeRate)
+		feeCurrency = s.market.QuoteCurrency
+		_ = e.account.UseLockedBalance(o.lockedCurrency, o.locked)
+		e.account.AddBalance(s.market.QuoteCurrency, quoteQuantity.Sub(fee))
+	}
+
+	o.locked = fixedpoint.Zero
+
+	now := e.now()
+	e.tradeID++
+	trade := types.Trade{
+		ID:            e.tradeID,
+		OrderID:       o.OrderID,
+		Price:         price,
+		Quantity:      quantity,
+		QuoteQuantity: quoteQuantity,
+		Symbol:        o.Symbol,
+		Side:          o.Side,
+		IsBuyer:       o.Side == types.SideTypeBuy,
+		IsMaker:       isMaker,
+		Time:          types.Time(now),
+		Fee:           fee,
+		FeeCurrency:   feeCurrency,
+	}
+	s.trades = append(s.trades, trade)
+
+	o.ExecutedQuantity = quantity
+	o.AveragePrice = price
+	o.Status = types.OrderStatusFilled
+	o.IsWorking = false
+	o.UpdateTime = types.Time(now)
+
+	return []interface{}{trade, e.account.Balances()}
+}
+
+func (e *Engine) closeOrder(s *symbolState, o *order, status types.OrderStatus) []interface{} {
+	if o.locked.Sign() > 0 {
+		_ = e.account.UnlockBalance(o.lockedCurrency, o.locked)
+		o.locked = fixedpoint.Zero
+	}
+
+	o.Status = status
+	o.IsWorking = false
+	o.UpdateTime = types.Time(e.now())
+
+	var remaining []*order
+	for _, openOrder := range s.openOrders {
+		if openOrder != o {
+			remaining = append(remaining, openOrder)
+		}
+	}
+	s.openOrders = remaining
+
+	return []interface{}{o.Order, e.account.Balances()}
+}
+
+// emitEvents emits the collected events outside the lock, so that the callbacks can query the engine
+func (e *Engine) emitEvents(events []interface{}) {
+	for _, event := range events {
+		switch ev := event.(type) {
+		case types.Order:
+			e.EmitOrderUpdate(ev)
+
+		case types.Trade:
+			o, err := e.QueryOrder(ev.Symbol, ev.OrderID, "")
+			if err == nil {
+				e.EmitTradeUpdate(ev, *o)
+			}
+
+		case types.BalanceMap:
+			e.EmitBalanceUpdate(ev)
+		}
+	}
+}
+
+func (s *symbolState) validate(o types.SubmitOrder) error {
+	market := s.market
+
+	switch o.Side {
+	case types.SideTypeBuy, types.SideTypeSell:
+	default:
+		return fmt.Errorf("%w: side %q", ErrInvalidOrderParameter, o.Side)
+	}
+
+	switch o.Type {
+	case types.OrderTypeLimit, types.OrderTypeLimitMaker, types.OrderTypeMarket:
+	case types.OrderTypeStopLimit, types.OrderTypeStopMarket:
+		if o.StopPrice.Sign() <= 0 {
+			return fmt.Errorf("%w: stop price is required for %s order", ErrInvalidOrderParameter, o.Type)
+		}
+
+		if market.TruncatePrice(o.StopPrice).Compare(o.StopPrice) != 0 {
+			return fmt.Errorf("%w: PRICE_FILTER, stop price %s", ErrFilterFailure, o.StopPrice.String())
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedOrderType, o.Type)
+	}
+
+	if o.Quantity.Sign() <= 0 {
+		return fmt.Errorf("%w: quantity %s", ErrInvalidOrderParameter, o.Quantity.String())
+	}
+
+	if o.Quantity.Compare(market.MinQuantity) < 0 ||
+		(market.MaxQuantity.Sign() > 0 && o.Quantity.Compare(market.MaxQuantity) > 0) ||
+		market.TruncateQuantity(o.Quantity).Compare(o.Quantity) != 0 {
+		return fmt.Errorf("%w: LOT_SIZE, quantity %s", ErrFilterFailure, o.Quantity.String())
+	}
+
+	price := o.Price
+	switch o.Type {
+	case types.OrderTypeMarket:
+		price = s.lastPrice
+	case types.OrderTypeStopMarket:
+		price = o.StopPrice
+	default:
+		if o.Price.Sign() <= 0 {
+			return fmt.Errorf("%w: price is required for %s order", ErrInvalidOrderParameter, o.Type)
+		}
+
+		if market.TruncatePrice(o.Price).Compare(o.Price) != 0 {
+			return fmt.Errorf("%w: PRICE_FILTER, price %s", ErrFilterFailure, o.Price.String())
+		}
+	}
+
+	if price.Sign() > 0 && o.Quantity.Mul(price).Compare(market.MinNotional) < 0 {
+		return fmt.Errorf("%w: NOTIONAL, notional %s", ErrFilterFailure, o.Quantity.Mul(price).String())
+	}
+
+	return nil
+}
+
+// crosses returns true if the order price crosses the last price, which means the order takes the liquidity
+func (s *symbolState) crosses(side types.SideType, price fixedpoint.Value) bool {
+	if s.lastPrice.IsZero() {
+		return false
+	}
+
+	return limitReached(side, price, s.lastPrice)
+}
+
+// book synthesizes the order book around the last price
+func (s *symbolState) book(levels int) types.SliceOrderBook {
+	book := types.SliceOrderBook{
+		Symbol:       s.market.Symbol,
+		Time:         s.lastTime,
+		LastUpdateId: s.bookUpdateID,
+	}
+
+	if s.lastPrice.IsZero() {
+		return book
+	}
+
+	if levels <= 0 || levels > s.bookLevels {
+		levels = s.bookLevels
+	}
+
+	for i := 1; i <= levels; i++ {
+		offset := s.market.TickSize.Mul(fixedpoint.NewFromInt(int64(i)))
+
+		if bid := s.lastPrice.Sub(offset); bid.Sign() > 0 {
+			book.Bids = append(book.Bids, types.PriceVolume{Price: bid, Volume: s.bookVolume})
+		}
+
+		book.Asks = append(book.Asks, types.PriceVolume{Price: s.lastPrice.Add(offset), Volume: s.bookVolume})
+	}
+
+	return book
+}
+
+// updateKLines aggregates the tick into the klines and returns the klines that are closed by the tick
+func (s *symbolState) updateKLines(tick Tick) (closed []types.KLine) {
+	for _, interval := range KLineIntervals {
+		d := interval.Duration()
+		startTime := tick.Time.Truncate(d)
+
+		klines := s.klines[interval]
+		n := len(klines)
+		if n > 0 && !klines[n-1].StartTime.Time().Before(startTime) {
+			k := &klines[n-1]
+			k.High = fixedpoint.Max(k.High, tick.Price)
+			k.Low = fixedpoint.Min(k.Low, tick.Price)
+			k.Close = tick.Price
+			k.Volume = k.Volume.Add(tick.Volume)
+			k.QuoteVolume = k.QuoteVolume.Add(tick.Volume.Mul(tick.Price))
+			k.LastTradeID = s.marketTradeID
+			k.NumberOfTrades++
+			continue
+		}
+
+		if n > 0 {
+			klines[n-1].Closed = true
+			closed = append(closed, klines[n-1])
+		}
+
+		klines = append(klines, types.KLine{
+			Symbol:         tick.Symbol,
+			Interval:       interval,
+			StartTime:      types.Time(startTime),
+			EndTime:        types.Time(startTime.Add(d - time.Millisecond)),
+			Open:           tick.Price,
+			Close:          tick.Price,
+			High:           tick.Price,
+			Low:            tick.Price,
+			Volume:         tick.Volume,
+			QuoteVolume:    tick.Volume.Mul(tick.Price),
+			LastTradeID:    s.marketTradeID,
+			NumberOfTrades: 1,
+		})
+
+		if len(klines) > maxKLines {
+			klines = klines[len(klines)-maxKLines:]
+		}
+
+		s.klines[interval] = klines
+	}
+
+	return closed
+}
+
+func (s *symbolState) currentKLines() map[types.Interval]types.KLine {
+	klines := make(map[types.Interval]types.KLine, len(s.klines))
+	for interval, all := range s.klines {
+		if len(all) > 0 {
+			klines[interval] = all[len(all)-1]
+		}
+	}
+	return klines
+}
+
+func limitReached(side types.SideType, orderPrice, price fixedpoint.Value) bool {
+	switch side {
+	case types.SideTypeBuy:
+		return price.Compare(orderPrice) <= 0
+	case types.SideTypeSell:
+		return price.Compare(orderPrice) >= 0
+	}
+	return false
+}
+
+func stopTriggered(side types.SideType, stopPrice, price fixedpoint.Value) bool {
+	switch side {
+	case types.SideTypeBuy:
+		return price.Compare(stopPrice) >= 0
+	case types.SideTypeSell:
+		return price.Compare(stopPrice) <= 0
+	}
+	return false
+}
+
+func inTimeRange(t time.Time, startTime, endTime *time.Time) bool {
+	if startTime != nil && t.Before(*startTime) {
+		return false
+	}
+
+	if endTime != nil && t.After(*endTime) {
+		return false
+	}
+
+	return true
+}
+
+func isKLineInterval(interval types.Interval) bool {
+	for _, i := range KLineIntervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
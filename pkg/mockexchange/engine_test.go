@@ -0,0 +1,137 @@
+package mockexchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestConfig() *Config {
+	config := &Config{
+		Markets: map[string]MarketConfig{
+			"BTCUSDT": {
+				BaseCurrency:  "BTC",
+				QuoteCurrency: "USDT",
+				TickSize:      fixedpoint.NewFromFloat(0.01),
+				StepSize:      fixedpoint.NewFromFloat(0.00001),
+				MinNotional:   fixedpoint.NewFromFloat(5.0),
+			},
+		},
+		Account: AccountConfig{
+			Balances: map[string]fixedpoint.Value{
+				"BTC":  fixedpoint.NewFromFloat(1.0),
+				"USDT": fixedpoint.NewFromFloat(100000.0),
+			},
+		},
+	}
+	config.setDefaults()
+	return config
+}
+
+func TestEngine_LimitOrder(t *testing.T) {
+	engine := NewEngine(newTestConfig())
+	require.NoError(t, engine.ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(30000.0)}))
+
+	var trades []types.Trade
+	engine.OnTradeUpdate(func(trade types.Trade, order types.Order) {
+		trades = append(trades, trade)
+	})
+
+	order, err := engine.SubmitOrder(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(29900.0),
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusNew, order.Status)
+
+	usdt, _ := engine.Account().Balance("USDT")
+	assert.Equal(t, "2990", usdt.Locked.String())
+
+	require.NoError(t, engine.ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(29950.0)}))
+	assert.Empty(t, trades)
+
+	require.NoError(t, engine.ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(29850.0)}))
+	require.Len(t, trades, 1)
+	assert.Equal(t, "29900", trades[0].Price.String())
+	assert.True(t, trades[0].IsMaker)
+
+	order, err = engine.QueryOrder("BTCUSDT", order.OrderID, "")
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+
+	usdt, _ = engine.Account().Balance("USDT")
+	assert.Equal(t, "0", usdt.Locked.String())
+	assert.Equal(t, "97010", usdt.Available.String())
+}
+
+func TestEngine_MarketOrder(t *testing.T) {
+	engine := NewEngine(newTestConfig())
+
+	_, err := engine.SubmitOrder(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	assert.ErrorIs(t, err, ErrNoLastPrice)
+
+	require.NoError(t, engine.ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(30000.0)}))
+
+	order, err := engine.SubmitOrder(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	require.NoError(t, err)
+
+	order, err = engine.QueryOrder("BTCUSDT", order.OrderID, "")
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+	assert.Equal(t, "30000", order.AveragePrice.String())
+
+	trades, err := engine.QueryTrades("BTCUSDT", TradeQuery{OrderID: order.OrderID})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	assert.False(t, trades[0].IsMaker)
+	assert.Equal(t, "USDT", trades[0].FeeCurrency)
+}
+
+func TestEngine_LimitMakerRejected(t *testing.T) {
+	engine := NewEngine(newTestConfig())
+	require.NoError(t, engine.ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(30000.0)}))
+
+	_, err := engine.SubmitOrder(types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimitMaker,
+		Price:    fixedpoint.NewFromFloat(30100.0),
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	assert.ErrorIs(t, err, ErrWouldTakeLiquidity)
+}
+
+func TestScriptedPath(t *testing.T) {
+	path := NewScriptedPath([]PricePoint{
+		{Price: fixedpoint.NewFromFloat(100.0)},
+		{Price: fixedpoint.NewFromFloat(110.0), Steps: 2},
+	})
+
+	var prices []string
+	for {
+		price, ok := path.Next()
+		if !ok {
+			break
+		}
+		prices = append(prices, price.String())
+	}
+
+	assert.Equal(t, []string{"100", "105", "110"}, prices)
+}
@@ -0,0 +1,116 @@
+package mockexchange
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// faultInjector injects the REST errors, the response latency and the websocket disconnections
+type faultInjector struct {
+	mu     sync.Mutex
+	config FaultConfig
+
+	// reload is notified when the config is replaced, so that the drop loop can reset its ticker
+	reload chan struct{}
+}
+
+func newFaultInjector(config FaultConfig) *faultInjector {
+	return &faultInjector{
+		config: config,
+		reload: make(chan struct{}, 1),
+	}
+}
+
+func (f *faultInjector) Config() FaultConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.config
+}
+
+func (f *faultInjector) SetConfig(config FaultConfig) {
+	f.mu.Lock()
+	f.config = config
+	f.mu.Unlock()
+
+	select {
+	case f.reload <- struct{}{}:
+	default:
+	}
+}
+
+// middleware is the gin middleware of the REST fault injection, the websocket endpoints are excluded
+func (f *faultInjector) middleware(c *gin.Context) {
+	path := c.Request.URL.Path
+	if strings.HasPrefix(path, "/ws") || strings.HasPrefix(path, "/mock/") {
+		return
+	}
+
+	config := f.Config()
+	if config.Latency > 0 {
+		time.Sleep(config.Latency)
+	}
+
+	if config.ErrorRate <= 0 || !matchPaths(config.ErrorPaths, path) {
+		return
+	}
+
+	if rand.Float64() < config.ErrorRate {
+		status := config.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+
+		log.Infof("injecting http %d error to %s %s", status, c.Request.Method, path)
+		c.AbortWithStatusJSON(status, binanceError{Code: -1001, Message: "Internal error; unable to process your request. Please try again."})
+	}
+}
+
+// dropLoop drops all the websocket connections every DropInterval
+func (f *faultInjector) dropLoop(ctx context.Context, hub *binanceStreamHub) {
+	for {
+		interval := f.Config().DropInterval
+
+		if interval <= 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-f.reload:
+				continue
+			}
+		}
+
+		ticker := time.NewTicker(interval)
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+
+		case <-f.reload:
+
+		case <-ticker.C:
+			log.Infof("dropping all the websocket connections")
+			hub.DropAll()
+		}
+		ticker.Stop()
+	}
+}
+
+func matchPaths(paths []string, path string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+
+	return false
+}
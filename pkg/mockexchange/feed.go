@@ -0,0 +1,255 @@
+package mockexchange
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// PricePath generates the price sequence of a symbol
+type PricePath interface {
+	// Next returns the next price, false is returned when the path is exhausted
+	Next() (fixedpoint.Value, bool)
+
+	// Reset rewinds the path to the beginning
+	Reset()
+}
+
+// ScriptedPath moves the price linearly between the scripted points
+type ScriptedPath struct {
+	points []PricePoint
+
+	index, step int
+}
+
+func NewScriptedPath(points []PricePoint) *ScriptedPath {
+	return &ScriptedPath{points: points}
+}
+
+func (p *ScriptedPath) Next() (fixedpoint.Value, bool) {
+	for p.index < len(p.points) {
+		point := p.points[p.index]
+		if p.index == 0 || point.Steps <= 1 {
+			p.index++
+			return point.Price, true
+		}
+
+		p.step++
+		prev := p.points[p.index-1].Price
+		steps := fixedpoint.NewFromInt(int64(point.Steps))
+		price := prev.Add(point.Price.Sub(prev).Mul(fixedpoint.NewFromInt(int64(p.step))).Div(steps))
+		if p.step >= point.Steps {
+			p.index++
+			p.step = 0
+		}
+
+		return price, true
+	}
+
+	return fixedpoint.Zero, false
+}
+
+func (p *ScriptedPath) Reset() {
+	p.index = 0
+	p.step = 0
+}
+
+// ReplayPath replays the prices of a kline file. Each kline produces the open, high, low and close prices,
+// the high and low prices are ordered by the kline direction.
+type ReplayPath struct {
+	prices []fixedpoint.Value
+	index  int
+}
+
+// LoadReplayPath loads a csv or tsv kline file with the open, high, low and close columns,
+// e.g., the kline files dumped by the backtest report
+func LoadReplayPath(filename string) (*ReplayPath, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	if ext := strings.ToLower(filepath.Ext(filename)); ext == ".tsv" {
+		reader.Comma = '\t'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the header of %s: %w", filename, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var indexes [4]int
+	for i, name := range []string{"open", "high", "low", "close"} {
+		idx, ok := columns[name]
+		if !ok {
+			return nil, fmt.Errorf("column %s is not found in %s", name, filename)
+		}
+		indexes[i] = idx
+	}
+
+	path := &ReplayPath{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		var ohlc [4]fixedpoint.Value
+		for i, idx := range indexes {
+			if idx >= len(record) {
+				return nil, fmt.Errorf("invalid record %v in %s", record, filename)
+			}
+
+			ohlc[i], err = fixedpoint.NewFromString(record[idx])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		open, high, low, closePrice := ohlc[0], ohlc[1], ohlc[2], ohlc[3]
+		if closePrice.Compare(open) >= 0 {
+			path.prices = append(path.prices, open, low, high, closePrice)
+		} else {
+			path.prices = append(path.prices, open, high, low, closePrice)
+		}
+	}
+
+	if len(path.prices) == 0 {
+		return nil, fmt.Errorf("no kline is found in %s", filename)
+	}
+
+	return path, nil
+}
+
+func (p *ReplayPath) Next() (fixedpoint.Value, bool) {
+	if p.index >= len(p.prices) {
+		return fixedpoint.Zero, false
+	}
+
+	price := p.prices[p.index]
+	p.index++
+	return price, true
+}
+
+func (p *ReplayPath) Reset() {
+	p.index = 0
+}
+
+type feedSymbol struct {
+	path   PricePath
+	loop   bool
+	volume fixedpoint.Value
+}
+
+// Feed drives the engine by sending the ticks of the price paths periodically
+type Feed struct {
+	engine   *Engine
+	interval time.Duration
+	symbols  map[string]*feedSymbol
+}
+
+func NewFeed(engine *Engine, config FeedConfig) (*Feed, error) {
+	feed := &Feed{
+		engine:   engine,
+		interval: config.Interval,
+		symbols:  make(map[string]*feedSymbol),
+	}
+
+	markets := engine.Markets()
+	for symbol, pathConfig := range config.Symbols {
+		var path PricePath
+		if pathConfig.Replay != "" {
+			replayPath, err := LoadReplayPath(pathConfig.Replay)
+			if err != nil {
+				return nil, err
+			}
+			path = replayPath
+		} else {
+			path = NewScriptedPath(pathConfig.Script)
+		}
+
+		volume := pathConfig.Volume
+		if volume.IsZero() {
+			volume = markets[symbol].MinQuantity
+		}
+
+		feed.symbols[symbol] = &feedSymbol{
+			path:   path,
+			loop:   pathConfig.Loop,
+			volume: volume,
+		}
+	}
+
+	return feed, nil
+}
+
+// Step sends the next tick of each symbol, false is returned when all the paths are exhausted
+func (f *Feed) Step() bool {
+	active := false
+	for symbol, s := range f.symbols {
+		price, ok := s.path.Next()
+		if !ok && s.loop {
+			s.path.Reset()
+			price, ok = s.path.Next()
+		}
+
+		if !ok {
+			continue
+		}
+
+		active = true
+		if err := f.engine.ProcessTick(Tick{
+			Symbol: symbol,
+			Price:  price,
+			Volume: s.volume,
+		}); err != nil {
+			log.WithError(err).Errorf("unable to process the %s tick", symbol)
+		}
+	}
+
+	return active
+}
+
+func (f *Feed) Run(ctx context.Context) {
+	if len(f.symbols) == 0 {
+		return
+	}
+
+	// send the first ticks immediately, so that the markets have their prices
+	if !f.Step() {
+		return
+	}
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if !f.Step() {
+				log.Infof("all the price paths are exhausted, stopping the feed")
+				return
+			}
+		}
+	}
+}
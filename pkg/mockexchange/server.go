@@ -0,0 +1,160 @@
+package mockexchange
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+var log = logrus.WithField("component", "mockexchange")
+
+// Server is a local exchange server speaking the Binance spot protocol,
+// an unchanged binance session can connect to it by setting BINANCE_API_BASE_URL and BINANCE_API_WS_URL.
+type Server struct {
+	config *Config
+
+	engine *Engine
+	feed   *Feed
+	hub    *binanceStreamHub
+	faults *faultInjector
+
+	handler http.Handler
+}
+
+func NewServer(config *Config) (*Server, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	config.setDefaults()
+
+	engine := NewEngine(config)
+	feed, err := NewFeed(engine, config.Feed)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		config: config,
+		engine: engine,
+		feed:   feed,
+		hub:    newBinanceStreamHub(engine),
+		faults: newFaultInjector(config.Faults),
+	}
+
+	r := gin.New()
+	r.Use(gin.Recovery(), s.faults.middleware)
+
+	newBinanceServer(engine, s.hub, config.Account).registerRoutes(r)
+
+	admin := r.Group("/mock")
+	admin.POST("/price", s.setPrice)
+	admin.GET("/faults", s.getFaults)
+	admin.POST("/faults", s.setFaults)
+	admin.POST("/sockets/drop", s.dropSockets)
+
+	s.handler = r
+	return s, nil
+}
+
+func (s *Server) Engine() *Engine {
+	return s.engine
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// DropConnections closes all the websocket connections
+func (s *Server) DropConnections() {
+	s.hub.DropAll()
+}
+
+// SetFaults replaces the fault injection config
+func (s *Server) SetFaults(config FaultConfig) {
+	s.faults.SetConfig(config)
+}
+
+// Start starts the price feed and the fault injection loop in the background
+func (s *Server) Start(ctx context.Context) {
+	go s.feed.Run(ctx)
+	go s.faults.dropLoop(ctx, s.hub)
+}
+
+// Run starts the server on the bind address and blocks until the context is canceled
+func (s *Server) Run(ctx context.Context, bind string) error {
+	listener, err := net.Listen("tcp", bind)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: s.handler}
+	s.Start(ctx)
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		s.hub.DropAll()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).Error("mock exchange server shutdown error")
+		}
+	}()
+
+	log.Infof("mock exchange server is listening on %s", listener.Addr())
+	if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+type setPriceRequest struct {
+	Symbol string           `json:"symbol"`
+	Price  fixedpoint.Value `json:"price"`
+	Volume fixedpoint.Value `json:"volume,omitempty"`
+}
+
+// setPrice sends a tick to the engine, it can be used for driving the price manually
+func (s *Server) setPrice(c *gin.Context) {
+	var req setPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.engine.ProcessTick(Tick{Symbol: req.Symbol, Price: req.Price, Volume: req.Volume}); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (s *Server) getFaults(c *gin.Context) {
+	c.JSON(http.StatusOK, s.faults.Config())
+}
+
+func (s *Server) setFaults(c *gin.Context) {
+	var config FaultConfig
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.SetFaults(config)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (s *Server) dropSockets(c *gin.Context) {
+	s.DropConnections()
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,114 @@
+package mockexchange
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/exchange/binance"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestServer(t *testing.T) (*Server, *binance.Exchange) {
+	server, err := NewServer(newTestConfig())
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	t.Setenv("BINANCE_API_BASE_URL", ts.URL)
+	t.Setenv("BINANCE_API_WS_URL", "ws"+strings.TrimPrefix(ts.URL, "http"))
+
+	require.NoError(t, server.Engine().ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(30000.0)}))
+	return server, binance.New("key", "secret")
+}
+
+func TestServer_BinanceSession(t *testing.T) {
+	server, ex := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	markets, err := ex.QueryMarkets(ctx)
+	require.NoError(t, err)
+	require.Contains(t, markets, "BTCUSDT")
+	assert.Equal(t, "0.01", markets["BTCUSDT"].TickSize.String())
+	assert.Equal(t, "5", markets["BTCUSDT"].MinNotional.String())
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "100000", balances["USDT"].Available.String())
+
+	ticker, err := ex.QueryTicker(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "30000", ticker.Last.String())
+
+	stream := ex.NewStream()
+	tradeC := make(chan types.Trade, 1)
+	stream.OnTradeUpdate(func(trade types.Trade) {
+		tradeC <- trade
+	})
+	authC := make(chan struct{}, 1)
+	stream.OnAuth(func() {
+		authC <- struct{}{}
+	})
+	require.NoError(t, stream.Connect(ctx))
+	defer stream.Close()
+
+	select {
+	case <-authC:
+	case <-ctx.Done():
+		t.Fatal("user data stream is not connected")
+	}
+
+	createdOrder, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromFloat(29900.0),
+		Quantity: fixedpoint.NewFromFloat(0.01),
+		Market:   markets["BTCUSDT"],
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusNew, createdOrder.Status)
+
+	openOrders, err := ex.QueryOpenOrders(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Len(t, openOrders, 1)
+
+	require.NoError(t, server.Engine().ProcessTick(Tick{Symbol: "BTCUSDT", Price: fixedpoint.NewFromFloat(29800.0)}))
+
+	select {
+	case trade := <-tradeC:
+		assert.Equal(t, createdOrder.OrderID, trade.OrderID)
+		assert.Equal(t, "29900", trade.Price.String())
+	case <-ctx.Done():
+		t.Fatal("trade update is not received")
+	}
+
+	trades, err := ex.QueryTrades(ctx, "BTCUSDT", &types.TradeQueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	assert.Equal(t, "0.01", trades[0].Quantity.String())
+}
+
+func TestServer_FaultInjection(t *testing.T) {
+	server, ex := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server.SetFaults(FaultConfig{ErrorRate: 1.0, ErrorPaths: []string{"/api/v3/account"}})
+
+	_, err := ex.QueryAccountBalances(ctx)
+	assert.Error(t, err)
+
+	_, err = ex.QueryMarkets(ctx)
+	assert.NoError(t, err)
+}
@@ -248,6 +248,7 @@ func (s *Server) newEngine(ctx context.Context) *gin.Engine {
 	})
 
 	r.GET("/api/strategies/single", s.listStrategies)
+	r.GET("/api/streams", s.listStreams)
 	r.NoRoute(s.assetsHandler)
 	return r
 }
@@ -346,6 +347,16 @@ func (s *Server) listSessions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"session": session})
 }
 
+func (s *Server) listStreams(c *gin.Context) {
+	watchdog := s.Environ.StreamWatchdog()
+	if watchdog == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "stream watchdog is not enabled"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"streams": watchdog.Statuses()})
+}
+
 func (s *Server) listSessionSymbols(c *gin.Context) {
 	sessionName := c.Param("session")
 	session, ok := s.Environ.Session(sessionName)
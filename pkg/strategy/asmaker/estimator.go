@@ -0,0 +1,91 @@
+package asmaker
+
+import (
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+)
+
+// minDepth is the floor of the mean trade depth, so that the intensity decay is finite
+// when the market trades are all at the mid price
+const minDepth = 1e-6
+
+// marketTradeEstimator estimates the parameters of the Avellaneda-Stoikov model from the market trades of the window.
+// The prices are relative, the returns are the log returns of the mid price and the depths are the distances of the
+// trade prices from the mid price relative to the mid price.
+type marketTradeEstimator struct {
+	window int
+
+	// returns and durations are the log returns of the mid price between the trades and their durations in seconds
+	returns, durations floats.Slice
+
+	// depths are the relative distances of the trade prices from the mid price
+	depths floats.Slice
+
+	times []time.Time
+
+	lastMid float64
+}
+
+func newMarketTradeEstimator(window int) *marketTradeEstimator {
+	return &marketTradeEstimator{window: window}
+}
+
+func (e *marketTradeEstimator) Update(t time.Time, price, mid float64) {
+	if price <= 0 || mid <= 0 {
+		return
+	}
+
+	if e.lastMid > 0 && len(e.times) > 0 {
+		e.returns.Push(math.Log(mid / e.lastMid))
+		e.durations.Push(t.Sub(e.times[len(e.times)-1]).Seconds())
+		e.returns = e.returns.Truncate(e.window)
+		e.durations = e.durations.Truncate(e.window)
+	}
+	e.lastMid = mid
+
+	e.depths.Push(math.Abs(price/mid - 1))
+	e.depths = e.depths.Truncate(e.window)
+
+	e.times = append(e.times, t)
+	if len(e.times) > e.window {
+		e.times = e.times[len(e.times)-e.window:]
+	}
+}
+
+// Ready returns true when the window is filled and the trades span a positive duration
+func (e *marketTradeEstimator) Ready() bool {
+	return e.returns.Length() >= e.window && e.durations.Sum() > 0
+}
+
+// Variance returns the variance of the log return of the mid price per second
+func (e *marketTradeEstimator) Variance() float64 {
+	duration := e.durations.Sum()
+	if duration <= 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, r := range e.returns {
+		sum += r * r
+	}
+	return sum / duration
+}
+
+// Intensity returns the parameters of the order arrival intensity lambda(delta) = a * exp(-k * delta),
+// where delta is the relative distance from the mid price. The rate of the trades reaching the depth delta decays
+// exponentially, so the depths are exponentially distributed, and k is estimated by the maximum likelihood
+// 1 / mean(depth). a is the number of the trades per second.
+func (e *marketTradeEstimator) Intensity() (a, k float64) {
+	if len(e.times) < 2 {
+		return 0, 0
+	}
+
+	duration := e.times[len(e.times)-1].Sub(e.times[0]).Seconds()
+	if duration > 0 {
+		a = float64(len(e.times)-1) / duration
+	}
+
+	return a, 1.0 / math.Max(e.depths.Mean(), minDepth)
+}
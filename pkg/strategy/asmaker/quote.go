@@ -0,0 +1,17 @@
+package asmaker
+
+import "math"
+
+// quote returns the reservation price and the optimal spread of the Avellaneda-Stoikov model:
+//
+//	reservation = mid * (1 - inventory * gamma * variance * tau)
+//	spread = mid * (gamma * variance * tau + 2 / gamma * ln(1 + gamma / k))
+//
+// inventory is the number of the order quantities held, variance is the variance of the log return per second,
+// tau is the remaining horizon in seconds, and k is the decay of the order arrival intensity in the relative depth.
+func quote(mid, inventory, gamma, variance, tau, k float64) (reservation, spread float64) {
+	risk := gamma * variance * tau
+	reservation = mid * (1 - inventory*risk)
+	spread = mid * (risk + 2/gamma*math.Log1p(gamma/k))
+	return reservation, spread
+}
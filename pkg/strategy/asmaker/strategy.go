@@ -0,0 +1,298 @@
+package asmaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/strategy/common"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+const ID = "asmaker"
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+// Strategy asmaker is the Avellaneda-Stoikov market maker.
+// It quotes around the reservation price, which is skewed against the inventory of the position, with the optimal
+// spread from the volatility and the order arrival intensity estimated from the market trades.
+//
+// See "High-frequency trading in a limit order book", Avellaneda and Stoikov (2008).
+type Strategy struct {
+	*common.Strategy
+
+	Environment *bbgo.Environment
+	Market      types.Market
+
+	Symbol string `json:"symbol"`
+
+	// Interval is the interval of updating the quotes
+	Interval types.Interval `json:"interval"`
+
+	Quantity  fixedpoint.Value `json:"quantity"`
+	OrderType types.OrderType  `json:"orderType"`
+
+	// RiskAversion is the gamma of the model, a larger risk aversion skews the quotes more against the inventory
+	RiskAversion float64 `json:"riskAversion"`
+
+	// Horizon is the remaining time of the model, the quotes are computed with a rolling horizon
+	Horizon types.Duration `json:"horizon"`
+
+	// Window is the number of the market trades for estimating the volatility and the order arrival intensity
+	Window int `json:"window"`
+
+	// MinSpread and MaxSpread bound the optimal spread, relative to the mid price
+	MinSpread fixedpoint.Value `json:"minSpread"`
+	MaxSpread fixedpoint.Value `json:"maxSpread"`
+
+	// MaxInventory stops quoting the side which increases the position when the position quantity reaches it
+	MaxInventory fixedpoint.Value `json:"maxInventory"`
+
+	InventorySkew common.InventorySkew `json:"inventorySkew"`
+
+	activeOrderBook *bbgo.ActiveOrderBook
+	book            *types.StreamOrderBook
+	estimator       *marketTradeEstimator
+	mu              sync.Mutex
+}
+
+func (s *Strategy) Defaults() error {
+	if s.Interval == "" {
+		s.Interval = types.Interval1m
+	}
+
+	if s.OrderType == "" {
+		s.OrderType = types.OrderTypeLimitMaker
+	}
+
+	if s.Horizon == 0 {
+		s.Horizon = types.Duration(time.Hour)
+	}
+
+	if s.Window == 0 {
+		s.Window = 500
+	}
+	return nil
+}
+
+func (s *Strategy) Initialize() error {
+	if s.Strategy == nil {
+		s.Strategy = &common.Strategy{}
+	}
+	return nil
+}
+
+func (s *Strategy) ID() string {
+	return ID
+}
+
+func (s *Strategy) InstanceID() string {
+	return fmt.Sprintf("%s:%s", ID, s.Symbol)
+}
+
+func (s *Strategy) Validate() error {
+	if s.Quantity.Sign() <= 0 {
+		return fmt.Errorf("quantity should be positive")
+	}
+
+	if s.RiskAversion <= 0 {
+		return fmt.Errorf("riskAversion should be positive")
+	}
+
+	if s.Horizon.Duration() <= 0 {
+		return fmt.Errorf("horizon should be positive")
+	}
+
+	if s.Window < 2 {
+		return fmt.Errorf("window should be greater than 1")
+	}
+
+	if s.MinSpread.Sign() < 0 || s.MaxSpread.Sign() < 0 {
+		return fmt.Errorf("minSpread and maxSpread should not be negative")
+	}
+
+	if s.MaxSpread.Sign() > 0 && s.MaxSpread.Compare(s.MinSpread) < 0 {
+		return fmt.Errorf("maxSpread should not be less than minSpread")
+	}
+
+	if s.MaxInventory.Sign() < 0 {
+		return fmt.Errorf("maxInventory should not be negative")
+	}
+
+	return s.InventorySkew.Validate()
+}
+
+func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
+	session.Subscribe(types.BookChannel, s.Symbol, types.SubscribeOptions{})
+	session.Subscribe(types.MarketTradeChannel, s.Symbol, types.SubscribeOptions{})
+	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.Interval})
+
+	if !s.CircuitBreakLossThreshold.IsZero() {
+		session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.CircuitBreakEMA.Interval})
+	}
+}
+
+func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
+	s.Strategy.Initialize(ctx, s.Environment, session, s.Market, ID, s.InstanceID())
+
+	s.estimator = newMarketTradeEstimator(s.Window)
+
+	s.book = types.NewStreamBook(s.Symbol)
+	s.book.BindStream(session.MarketDataStream)
+
+	s.activeOrderBook = bbgo.NewActiveOrderBook(s.Symbol)
+	s.activeOrderBook.BindStream(session.UserDataStream)
+
+	session.MarketDataStream.OnMarketTrade(types.TradeWith(s.Symbol, func(trade types.Trade) {
+		bid, ask, ok := s.book.BestBidAndAsk()
+		if !ok {
+			return
+		}
+
+		mid := bid.Price.Add(ask.Price).Div(fixedpoint.Two)
+
+		s.mu.Lock()
+		s.estimator.Update(trade.Time.Time(), trade.Price.Float64(), mid.Float64())
+		s.mu.Unlock()
+	}))
+
+	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, s.Interval, func(kline types.KLine) {
+		if s.IsHalted(kline.EndTime.Time()) {
+			log.Infof("circuit break halted")
+			return
+		}
+
+		s.updateQuotes(ctx)
+	}))
+
+	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		err := s.activeOrderBook.GracefulCancel(ctx, s.Session.Exchange)
+		util.LogErr(err, "unable to cancel orders")
+
+		bbgo.Sync(ctx, s)
+	})
+
+	return nil
+}
+
+func (s *Strategy) updateQuotes(ctx context.Context) {
+	if err := s.activeOrderBook.GracefulCancel(ctx, s.Session.Exchange); util.LogErr(err, "unable to cancel orders") {
+		return
+	}
+
+	s.mu.Lock()
+	ready := s.estimator.Ready()
+	variance := s.estimator.Variance()
+	a, k := s.estimator.Intensity()
+	s.mu.Unlock()
+
+	if !ready {
+		log.Infof("waiting for %d market trades to estimate the model", s.Window)
+		return
+	}
+
+	bid, ask, ok := s.book.BestBidAndAsk()
+	if !ok {
+		log.Warnf("%s order book is not ready", s.Symbol)
+		return
+	}
+
+	if _, err := s.Session.UpdateAccount(ctx); util.LogErr(err, "unable to update account") {
+		return
+	}
+
+	orders := s.generateOrders(bid.Price, ask.Price, variance, k)
+	log.Infof("variance: %g/s, intensity: %f * exp(-%f * depth), orders: %+v", variance, a, k, orders)
+
+	createdOrders, err := s.OrderExecutor.SubmitOrders(ctx, orders...)
+	if util.LogErr(err, "unable to submit orders") {
+		return
+	}
+
+	s.activeOrderBook.Add(createdOrders...)
+}
+
+func (s *Strategy) generateOrders(bestBid, bestAsk fixedpoint.Value, variance, k float64) []types.SubmitOrder {
+	mid := bestBid.Add(bestAsk).Div(fixedpoint.Two)
+	inventory := s.Position.GetBase().Div(s.Quantity).Float64()
+
+	reservation, spread := quote(mid.Float64(), inventory, s.RiskAversion, variance, s.Horizon.Duration().Seconds(), k)
+
+	halfSpread := fixedpoint.NewFromFloat(spread / 2)
+	if s.MinSpread.Sign() > 0 {
+		halfSpread = fixedpoint.Max(halfSpread, mid.Mul(s.MinSpread).Div(fixedpoint.Two))
+	}
+
+	if s.MaxSpread.Sign() > 0 {
+		halfSpread = fixedpoint.Min(halfSpread, mid.Mul(s.MaxSpread).Div(fixedpoint.Two))
+	}
+
+	r := fixedpoint.NewFromFloat(reservation)
+	bidPrice := r.Sub(halfSpread).Round(s.Market.PricePrecision, fixedpoint.Down)
+	askPrice := r.Add(halfSpread).Round(s.Market.PricePrecision, fixedpoint.Up)
+
+	// the maker orders can't cross the book
+	bidPrice = fixedpoint.Min(bidPrice, bestAsk.Sub(s.Market.TickSize))
+	askPrice = fixedpoint.Max(askPrice, bestBid.Add(s.Market.TickSize))
+
+	log.Infof("mid price: %s, inventory: %f, reservation price: %f, spread: %f, bid: %s, ask: %s",
+		mid.String(), inventory, reservation, spread, bidPrice.String(), askPrice.String())
+
+	baseBalance, _ := s.Session.GetAccount().Balance(s.Market.BaseCurrency)
+	quoteBalance, _ := s.Session.GetAccount().Balance(s.Market.QuoteCurrency)
+
+	buyQuantity := s.Quantity
+	sellQuantity := s.Quantity
+	if !s.InventorySkew.InventoryRangeMultiplier.IsZero() {
+		ratios := s.InventorySkew.CalculateBidAskRatios(s.Quantity, mid, baseBalance.Total(), quoteBalance.Total())
+		buyQuantity = s.Market.TruncateQuantity(s.Quantity.Mul(ratios.BidRatio))
+		sellQuantity = s.Market.TruncateQuantity(s.Quantity.Mul(ratios.AskRatio))
+	}
+
+	if s.MaxInventory.Sign() > 0 {
+		if s.Position.GetBase().Compare(s.MaxInventory) >= 0 {
+			buyQuantity = fixedpoint.Zero
+		} else if s.Position.GetBase().Neg().Compare(s.MaxInventory) >= 0 {
+			sellQuantity = fixedpoint.Zero
+		}
+	}
+
+	var orders []types.SubmitOrder
+	if !s.Market.IsDustQuantity(buyQuantity, bidPrice) && quoteBalance.Available.Compare(buyQuantity.Mul(bidPrice)) >= 0 {
+		orders = append(orders, types.SubmitOrder{
+			Symbol:      s.Symbol,
+			Side:        types.SideTypeBuy,
+			Type:        s.OrderType,
+			Price:       bidPrice,
+			Quantity:    buyQuantity,
+			Market:      s.Market,
+			TimeInForce: types.TimeInForceGTC,
+		})
+	}
+
+	if !s.Market.IsDustQuantity(sellQuantity, askPrice) && baseBalance.Available.Compare(sellQuantity) >= 0 {
+		orders = append(orders, types.SubmitOrder{
+			Symbol:      s.Symbol,
+			Side:        types.SideTypeSell,
+			Type:        s.OrderType,
+			Price:       askPrice,
+			Quantity:    sellQuantity,
+			Market:      s.Market,
+			TimeInForce: types.TimeInForceGTC,
+		})
+	}
+
+	return orders
+}
@@ -0,0 +1,117 @@
+package asmaker
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/strategy/common"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestQuote(t *testing.T) {
+	// without the inventory the reservation price is the mid price
+	reservation, spread := quote(100, 0, 1, 1e-8, 3600, 5000)
+	assert.Equal(t, 100.0, reservation)
+	assert.InDelta(t, 100*(3.6e-5+2*math.Log(1+1.0/5000)), spread, 1e-12)
+
+	// the reservation price is skewed against the inventory
+	long, _ := quote(100, 2, 10, 1e-8, 3600, 5000)
+	short, _ := quote(100, -2, 10, 1e-8, 3600, 5000)
+	assert.InDelta(t, 100*(1-2*3.6e-4), long, 1e-12)
+	assert.InDelta(t, 100*(1+2*3.6e-4), short, 1e-12)
+
+	// the spread approaches 2 / k when the risk aversion approaches zero
+	_, spread = quote(100, 0, 1e-9, 1e-8, 3600, 5000)
+	assert.InDelta(t, 100*2.0/5000, spread, 1e-9)
+}
+
+func TestMarketTradeEstimator(t *testing.T) {
+	e := newMarketTradeEstimator(4)
+	now := time.Now()
+
+	mids := []float64{100, 101, 100, 101, 100}
+	depths := []float64{0.001, 0.003, 0.001, 0.003, 0.001}
+	for i, mid := range mids {
+		assert.False(t, e.Ready())
+		e.Update(now.Add(time.Duration(i)*2*time.Second), mid*(1+depths[i]), mid)
+	}
+
+	assert.True(t, e.Ready())
+
+	r := math.Log(101.0 / 100.0)
+	assert.InDelta(t, 4*r*r/8, e.Variance(), 1e-12)
+
+	a, k := e.Intensity()
+	assert.InDelta(t, 3.0/6.0, a, 1e-12)
+	assert.InDelta(t, 1/0.002, k, 1e-6)
+}
+
+func TestStrategy_generateOrders(t *testing.T) {
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		BaseCurrency:    "BTC",
+		QuoteCurrency:   "USDT",
+		PricePrecision:  2,
+		VolumePrecision: 4,
+		TickSize:        fixedpoint.NewFromFloat(0.01),
+		StepSize:        fixedpoint.NewFromFloat(0.0001),
+		MinQuantity:     fixedpoint.NewFromFloat(0.0001),
+		MinNotional:     fixedpoint.NewFromFloat(1),
+	}
+
+	account := types.NewAccount()
+	account.UpdateBalances(types.BalanceMap{
+		"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(1)},
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(100_000)},
+	})
+
+	session := &bbgo.ExchangeSession{Account: account}
+
+	s := &Strategy{
+		Strategy: &common.Strategy{
+			Position: types.NewPositionFromMarket(market),
+			Session:  session,
+		},
+		Market:       market,
+		Symbol:       "BTCUSDT",
+		Quantity:     fixedpoint.NewFromFloat(0.1),
+		RiskAversion: 10,
+		MaxInventory: fixedpoint.NewFromFloat(0.2),
+	}
+	assert.NoError(t, s.Defaults())
+	assert.NoError(t, s.Validate())
+
+	// 1e-8 * 3600 * 10 = 3.6e-4 per the order quantity
+	s.Position.Base = fixedpoint.NewFromFloat(0.1)
+	orders := s.generateOrders(fixedpoint.NewFromFloat(29999), fixedpoint.NewFromFloat(30001), 1e-8, 5000)
+	if assert.Len(t, orders, 2) {
+		halfSpread := 30000 * (3.6e-4 + 2/10.0*math.Log(1+10.0/5000)) / 2
+		reservation := 30000 * (1 - 3.6e-4)
+		assert.Equal(t, types.SideTypeBuy, orders[0].Side)
+		assert.InDelta(t, reservation-halfSpread, orders[0].Price.Float64(), 0.01)
+		assert.Equal(t, types.SideTypeSell, orders[1].Side)
+		assert.InDelta(t, reservation+halfSpread, orders[1].Price.Float64(), 0.01)
+		assert.Equal(t, types.OrderTypeLimitMaker, orders[1].Type)
+	}
+
+	// the bid can't cross the best ask when the reservation price is skewed far above the mid price
+	s.Position.Base = fixedpoint.NewFromFloat(-0.1)
+	s.RiskAversion = 1000
+	s.MaxSpread = fixedpoint.NewFromFloat(0.001)
+	orders = s.generateOrders(fixedpoint.NewFromFloat(29999), fixedpoint.NewFromFloat(30001), 1e-8, 5000)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, "30000.99", orders[0].Price.String())
+	}
+
+	// the side which increases the position is not quoted at the max inventory
+	s.Position.Base = fixedpoint.NewFromFloat(0.2)
+	orders = s.generateOrders(fixedpoint.NewFromFloat(29999), fixedpoint.NewFromFloat(30001), 1e-8, 5000)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, types.SideTypeSell, orders[0].Side)
+	}
+}
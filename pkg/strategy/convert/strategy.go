@@ -19,8 +19,6 @@ const ID = "convert"
 
 var log = logrus.WithField("strategy", ID)
 
-var stableCoins = []string{"USDT", "USDC"}
-
 func init() {
 	bbgo.RegisterStrategy(ID, &Strategy{})
 }
@@ -403,7 +401,7 @@ func findIndirectMarket(markets map[string]types.Market, from, to string) ([]typ
 	}
 
 	// prefer stable coins for better liquidity
-	for _, stableCoin := range stableCoins {
+	for _, stableCoin := range types.DefaultSymbolRegistry.EquivalentQuotes("USDT") {
 		m1, ok1 := sourceMarkets[stableCoin]
 		m2, ok2 := targetMarkets[stableCoin]
 		if ok1 && ok2 {
@@ -0,0 +1,188 @@
+package pairtrade
+
+import (
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+)
+
+type HedgeRatioMethod string
+
+const (
+	HedgeRatioMethodOLS    HedgeRatioMethod = "ols"
+	HedgeRatioMethodKalman HedgeRatioMethod = "kalman"
+)
+
+// HedgeRatioConfig configures the estimator of the regression between the log prices of the legs:
+//
+//	log(primary) = alpha + beta * log(hedge) + spread
+type HedgeRatioConfig struct {
+	// Method is "ols" (the rolling ordinary least squares) or "kalman" (the kalman filter regression),
+	// defaults to "ols"
+	Method HedgeRatioMethod `json:"method"`
+
+	// Window is the rolling window of the ols method, and the warm-up length of the kalman method
+	Window int `json:"window"`
+
+	// Delta is the process noise of the kalman method, the hedge ratio adapts faster with a larger delta
+	Delta float64 `json:"delta,omitempty"`
+
+	// ObservationVariance is the measurement noise of the kalman method
+	ObservationVariance float64 `json:"observationVariance,omitempty"`
+}
+
+func (c *HedgeRatioConfig) Defaults() {
+	if c.Method == "" {
+		c.Method = HedgeRatioMethodOLS
+	}
+
+	if c.Window == 0 {
+		c.Window = 200
+	}
+
+	if c.Delta == 0 {
+		c.Delta = 1e-4
+	}
+
+	if c.ObservationVariance == 0 {
+		c.ObservationVariance = 1e-3
+	}
+}
+
+func (c *HedgeRatioConfig) Validate() error {
+	switch c.Method {
+	case HedgeRatioMethodOLS, HedgeRatioMethodKalman:
+	default:
+		return fmt.Errorf("unsupported hedge ratio method: %q", c.Method)
+	}
+
+	if c.Window < 2 {
+		return fmt.Errorf("hedge ratio window should be greater than 1")
+	}
+
+	if c.Delta <= 0 || c.Delta >= 1 {
+		return fmt.Errorf("hedge ratio delta should be between 0 and 1")
+	}
+
+	if c.ObservationVariance <= 0 {
+		return fmt.Errorf("hedge ratio observationVariance should be positive")
+	}
+
+	return nil
+}
+
+// hedgeRatioEstimator estimates y = alpha + beta * x, y is the log price of the primary leg,
+// and x is the log price of the hedge leg
+type hedgeRatioEstimator interface {
+	Update(x, y float64)
+
+	// Ready returns true when the estimator has seen enough values
+	Ready() bool
+
+	Estimate() (alpha, beta float64)
+}
+
+func newHedgeRatioEstimator(c HedgeRatioConfig) hedgeRatioEstimator {
+	if c.Method == HedgeRatioMethodKalman {
+		return newKalmanRegression(c.Window, c.Delta, c.ObservationVariance)
+	}
+
+	return &rollingOLS{window: c.Window}
+}
+
+type rollingOLS struct {
+	window int
+	x, y   floats.Slice
+}
+
+func (r *rollingOLS) Update(x, y float64) {
+	r.x.Push(x)
+	r.y.Push(y)
+	r.x = r.x.Truncate(r.window)
+	r.y = r.y.Truncate(r.window)
+}
+
+func (r *rollingOLS) Ready() bool {
+	return r.x.Length() >= r.window
+}
+
+func (r *rollingOLS) Estimate() (alpha, beta float64) {
+	meanX, meanY := r.x.Mean(), r.y.Mean()
+
+	var sxy, sxx float64
+	for i := range r.x {
+		dx := r.x[i] - meanX
+		sxy += dx * (r.y[i] - meanY)
+		sxx += dx * dx
+	}
+
+	if sxx == 0 {
+		return meanY, 0
+	}
+
+	beta = sxy / sxx
+	return meanY - beta*meanX, beta
+}
+
+// kalmanRegression is the kalman filter of the regression coefficients, the coefficients (beta, alpha) are
+// the hidden state with a random walk, and y is observed as beta * x + alpha with the observation noise.
+type kalmanRegression struct {
+	warmUp int
+
+	// vw is the variance of the random walk of the state, ve is the variance of the observation noise
+	vw, ve float64
+
+	// theta is the state (beta, alpha), p is the covariance of the state
+	theta [2]float64
+	p     [2][2]float64
+
+	updates int
+}
+
+func newKalmanRegression(warmUp int, delta, observationVariance float64) *kalmanRegression {
+	return &kalmanRegression{
+		warmUp: warmUp,
+		vw:     delta / (1 - delta),
+		ve:     observationVariance,
+	}
+}
+
+func (k *kalmanRegression) Update(x, y float64) {
+	f := [2]float64{x, 1}
+
+	// predict, the state covariance grows by the random walk
+	r := k.p
+	r[0][0] += k.vw
+	r[1][1] += k.vw
+
+	// rf = R * F'
+	rf := [2]float64{
+		r[0][0]*f[0] + r[0][1]*f[1],
+		r[1][0]*f[0] + r[1][1]*f[1],
+	}
+
+	q := f[0]*rf[0] + f[1]*rf[1] + k.ve
+	e := y - (f[0]*k.theta[0] + f[1]*k.theta[1])
+
+	// update
+	gain := [2]float64{rf[0] / q, rf[1] / q}
+	k.theta[0] += gain[0] * e
+	k.theta[1] += gain[1] * e
+
+	// P = R - K * F * R, F * R = (R * F')' since R is symmetric
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			k.p[i][j] = r[i][j] - gain[i]*rf[j]
+		}
+	}
+
+	k.updates++
+}
+
+func (k *kalmanRegression) Ready() bool {
+	return k.updates >= k.warmUp
+}
+
+func (k *kalmanRegression) Estimate() (alpha, beta float64) {
+	return k.theta[1], k.theta[0]
+}
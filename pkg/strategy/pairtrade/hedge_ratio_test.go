@@ -0,0 +1,50 @@
+package pairtrade
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedgeRatioEstimators(t *testing.T) {
+	const alpha, beta = 0.5, 1.5
+
+	for _, method := range []HedgeRatioMethod{HedgeRatioMethodOLS, HedgeRatioMethodKalman} {
+		t.Run(string(method), func(t *testing.T) {
+			c := HedgeRatioConfig{Method: method, Window: 100, Delta: 1e-4, ObservationVariance: 1e-4}
+			assert.NoError(t, c.Validate())
+
+			r := rand.New(rand.NewSource(1))
+			estimator := newHedgeRatioEstimator(c)
+
+			x := math.Log(100.0)
+			for i := 0; i < 500; i++ {
+				x += 0.01 * r.NormFloat64()
+				y := alpha + beta*x + 0.001*r.NormFloat64()
+				estimator.Update(x, y)
+
+				if i == 98 {
+					assert.False(t, estimator.Ready())
+				}
+			}
+
+			assert.True(t, estimator.Ready())
+
+			a, b := estimator.Estimate()
+			assert.InDelta(t, beta, b, 0.05)
+			assert.InDelta(t, alpha, a, 0.25)
+		})
+	}
+}
+
+func TestHedgeRatioConfig(t *testing.T) {
+	c := HedgeRatioConfig{}
+	c.Defaults()
+	assert.Equal(t, HedgeRatioMethodOLS, c.Method)
+	assert.NoError(t, c.Validate())
+
+	c.Method = "median"
+	assert.Error(t, c.Validate())
+}
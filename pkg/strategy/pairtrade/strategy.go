@@ -0,0 +1,602 @@
+package pairtrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const ID = "pairtrade"
+
+var log = logrus.WithField("strategy", ID)
+
+func init() {
+	bbgo.RegisterStrategy(ID, &Strategy{})
+}
+
+// Leg is one symbol of the pair, the legs can be on the same session or on different sessions.
+// A short leg requires a margin or futures session.
+type Leg struct {
+	Session string `json:"session"`
+	Symbol  string `json:"symbol"`
+
+	session       *bbgo.ExchangeSession
+	market        types.Market
+	position      *types.Position
+	orderExecutor *bbgo.GeneralOrderExecutor
+}
+
+func (l *Leg) String() string {
+	return l.Session + ":" + l.Symbol
+}
+
+// isDust returns true if the quantity can not be submitted as an order
+func (l *Leg) isDust(quantity, price fixedpoint.Value) bool {
+	return quantity.Compare(l.market.MinQuantity) < 0 || quantity.Mul(price).Compare(l.market.MinNotional) < 0
+}
+
+// adjust submits a market order to move the position of the leg to the target base quantity
+func (l *Leg) adjust(ctx context.Context, target, price fixedpoint.Value, tag string) error {
+	diff := target.Sub(l.position.GetBase())
+	quantity := l.market.TruncateQuantity(diff.Abs())
+	if l.isDust(quantity, price) {
+		return nil
+	}
+
+	side := types.SideTypeBuy
+	if diff.Sign() < 0 {
+		side = types.SideTypeSell
+	}
+
+	_, err := l.orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   l.Symbol,
+		Market:   l.market,
+		Side:     side,
+		Type:     types.OrderTypeMarket,
+		Quantity: quantity,
+		Tag:      tag,
+	})
+	return err
+}
+
+// The side of the spread position
+const (
+	SpreadFlat = 0
+
+	// SpreadLong is long the primary leg and short the hedge leg, it's opened when the spread is low
+	SpreadLong = 1
+
+	// SpreadShort is short the primary leg and long the hedge leg, it's opened when the spread is high
+	SpreadShort = -1
+)
+
+type State struct {
+	// Side is the side of the spread position
+	Side int `json:"side"`
+
+	// Quantity is the base quantity of the primary leg when the spread position is opened
+	Quantity fixedpoint.Value `json:"quantity"`
+
+	// HedgeRatio is the base quantity of the hedge leg per base quantity of the primary leg,
+	// it's fixed when the spread position is opened
+	HedgeRatio fixedpoint.Value `json:"hedgeRatio"`
+
+	// Stopped is set when the spread position is stopped out, the strategy doesn't open a new spread position
+	// until the z-score reverts to the exit z-score
+	Stopped bool `json:"stopped"`
+
+	OpenTime time.Time `json:"openTime"`
+}
+
+// Strategy is the pair trading (statistical arbitrage) strategy.
+//
+// It estimates the hedge ratio by regressing the log price of the primary leg on the log price of the hedge leg,
+// and trades the z-score of the residual spread:
+//
+//	spread = log(primary) - alpha - beta * log(hedge)
+//
+// When the z-score is higher than entryZScore, it sells the primary leg and buys beta times of the notional
+// of the hedge leg, and the reverse when the z-score is lower than -entryZScore. The spread position is closed when
+// the z-score reverts to exitZScore, or stopped out when the z-score diverges beyond stopZScore.
+//
+// The hedge leg is kept balanced with the filled quantity of the primary leg, so the partially filled orders
+// don't leave the pair unhedged.
+type Strategy struct {
+	Environment *bbgo.Environment
+
+	Primary Leg `json:"primary"`
+	Hedge   Leg `json:"hedge"`
+
+	Interval types.Interval `json:"interval"`
+
+	HedgeRatio HedgeRatioConfig `json:"hedgeRatio"`
+
+	// Window is the window of the z-score of the spread
+	Window int `json:"window"`
+
+	EntryZScore float64 `json:"entryZScore"`
+	ExitZScore  float64 `json:"exitZScore"`
+
+	// StopZScore is the z-score to stop out the spread position, disabled if zero
+	StopZScore float64 `json:"stopZScore,omitempty"`
+
+	// Quantity is the base quantity of the primary leg, QuoteQuantity is used if Quantity is not set
+	Quantity      fixedpoint.Value `json:"quantity,omitempty"`
+	QuoteQuantity fixedpoint.Value `json:"quoteQuantity,omitempty"`
+
+	// MaxImbalance is the max deviation ratio of the hedge leg quantity from the balanced quantity,
+	// the hedge leg is adjusted when the deviation is larger than this ratio. Defaults to 1%.
+	MaxImbalance fixedpoint.Value `json:"maxImbalance,omitempty"`
+
+	PrimaryPosition    *types.Position    `persistence:"primary_position"`
+	HedgePosition      *types.Position    `persistence:"hedge_position"`
+	PrimaryProfitStats *types.ProfitStats `persistence:"primary_profit_stats"`
+	HedgeProfitStats   *types.ProfitStats `persistence:"hedge_profit_stats"`
+
+	State *State `persistence:"state"`
+
+	// mu protects the state, the estimator and the positions from the kline handlers of the 2 sessions
+	mu sync.Mutex
+
+	estimator hedgeRatioEstimator
+	spread    *types.Float64Series
+	zScore    *indicatorv2.ZScoreStream
+	aligner   *pairAligner
+
+	// primaryPrice and hedgePrice are the last aligned close prices
+	primaryPrice, hedgePrice fixedpoint.Value
+
+	// running is set after the kline history is loaded, the strategy doesn't trade the history
+	running bool
+
+	balanceC chan struct{}
+}
+
+func (s *Strategy) ID() string {
+	return ID
+}
+
+func (s *Strategy) InstanceID() string {
+	return fmt.Sprintf("%s:%s:%s-%s:%s", ID, s.Primary.Session, s.Primary.Symbol, s.Hedge.Session, s.Hedge.Symbol)
+}
+
+func (s *Strategy) Defaults() error {
+	if s.Interval == "" {
+		s.Interval = types.Interval1h
+	}
+
+	if s.Window == 0 {
+		s.Window = 50
+	}
+
+	if s.EntryZScore == 0 {
+		s.EntryZScore = 2.0
+	}
+
+	if s.MaxImbalance.IsZero() {
+		s.MaxImbalance = fixedpoint.NewFromFloat(0.01)
+	}
+
+	s.HedgeRatio.Defaults()
+	return nil
+}
+
+func (s *Strategy) Validate() error {
+	if s.Primary.Session == "" || s.Primary.Symbol == "" {
+		return errors.New("primary session and symbol are required")
+	}
+
+	if s.Hedge.Session == "" || s.Hedge.Symbol == "" {
+		return errors.New("hedge session and symbol are required")
+	}
+
+	if s.Primary.Session == s.Hedge.Session && s.Primary.Symbol == s.Hedge.Symbol {
+		return errors.New("primary and hedge legs can not be the same market")
+	}
+
+	if s.Quantity.IsZero() && s.QuoteQuantity.IsZero() {
+		return errors.New("either quantity or quoteQuantity is required")
+	}
+
+	if s.Window < 2 {
+		return errors.New("window should be greater than 1")
+	}
+
+	if s.EntryZScore <= 0 {
+		return errors.New("entryZScore should be positive")
+	}
+
+	if s.ExitZScore < 0 || s.ExitZScore >= s.EntryZScore {
+		return errors.New("exitZScore should be between 0 and entryZScore")
+	}
+
+	if s.StopZScore != 0 && s.StopZScore <= s.EntryZScore {
+		return errors.New("stopZScore should be greater than entryZScore")
+	}
+
+	return s.HedgeRatio.Validate()
+}
+
+func (s *Strategy) CrossSubscribe(sessions map[string]*bbgo.ExchangeSession) {
+	for _, leg := range []*Leg{&s.Primary, &s.Hedge} {
+		session, ok := sessions[leg.Session]
+		if !ok {
+			log.Errorf("session %s is not defined", leg.Session)
+			continue
+		}
+
+		session.Subscribe(types.KLineChannel, leg.Symbol, types.SubscribeOptions{Interval: s.Interval})
+	}
+}
+
+func (s *Strategy) CrossRun(ctx context.Context, _ bbgo.OrderExecutionRouter, sessions map[string]*bbgo.ExchangeSession) error {
+	instanceID := s.InstanceID()
+
+	for _, leg := range []*Leg{&s.Primary, &s.Hedge} {
+		session, ok := sessions[leg.Session]
+		if !ok {
+			return fmt.Errorf("session %s is not defined", leg.Session)
+		}
+
+		market, ok := session.Market(leg.Symbol)
+		if !ok {
+			return fmt.Errorf("market %s not found in session %s", leg.Symbol, leg.Session)
+		}
+
+		leg.session = session
+		leg.market = market
+	}
+
+	if s.PrimaryPosition == nil {
+		s.PrimaryPosition = types.NewPositionFromMarket(s.Primary.market)
+	}
+
+	if s.HedgePosition == nil {
+		s.HedgePosition = types.NewPositionFromMarket(s.Hedge.market)
+	}
+
+	if s.PrimaryProfitStats == nil {
+		s.PrimaryProfitStats = types.NewProfitStats(s.Primary.market)
+	}
+
+	if s.HedgeProfitStats == nil {
+		s.HedgeProfitStats = types.NewProfitStats(s.Hedge.market)
+	}
+
+	if s.State == nil {
+		s.State = &State{}
+	}
+
+	s.Primary.position = s.PrimaryPosition
+	s.Hedge.position = s.HedgePosition
+	s.balanceC = make(chan struct{}, 1)
+
+	for _, leg := range []*Leg{&s.Primary, &s.Hedge} {
+		profitStats := s.PrimaryProfitStats
+		if leg == &s.Hedge {
+			profitStats = s.HedgeProfitStats
+		}
+
+		leg.orderExecutor = bbgo.NewGeneralOrderExecutor(leg.session, leg.Symbol, ID, instanceID, leg.position)
+		leg.orderExecutor.BindEnvironment(s.Environment)
+		leg.orderExecutor.BindProfitStats(profitStats)
+		leg.orderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
+			bbgo.Sync(ctx, s)
+			s.requestBalance()
+		})
+		leg.orderExecutor.Bind()
+	}
+
+	log.Infof("loaded primary position: %s", s.PrimaryPosition.String())
+	log.Infof("loaded hedge position: %s", s.HedgePosition.String())
+
+	s.estimator = newHedgeRatioEstimator(s.HedgeRatio)
+	s.spread = types.NewFloat64Series()
+	s.zScore = indicatorv2.ZScore(s.spread, s.Window)
+	s.aligner = newPairAligner(func(primary, hedge float64) {
+		s.update(ctx, primary, hedge)
+	})
+
+	// the kline history is pushed to the subscribers right away, it warms up the estimator and the z-score
+	for i, leg := range []*Leg{&s.Primary, &s.Hedge} {
+		i := i
+		leg.session.Indicators(leg.Symbol).KLines(s.Interval).AddSubscriber(func(k types.KLine) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.aligner.push(i, k)
+		})
+	}
+
+	s.mu.Lock()
+	s.running = true
+	s.mu.Unlock()
+
+	// in the backtest, the legs are balanced by the kline handler, since the backtest doesn't run in the real time
+	if !bbgo.IsBackTesting {
+		go s.balanceWorker(ctx)
+	}
+
+	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		for _, leg := range []*Leg{&s.Primary, &s.Hedge} {
+			if err := leg.orderExecutor.GracefulCancel(ctx); err != nil {
+				log.WithError(err).Errorf("unable to cancel %s orders", leg.String())
+			}
+		}
+
+		bbgo.Sync(ctx, s)
+	})
+
+	return nil
+}
+
+// update updates the hedge ratio and the spread z-score by the aligned close prices, and trades the spread
+func (s *Strategy) update(ctx context.Context, primaryPrice, hedgePrice float64) {
+	if primaryPrice <= 0 || hedgePrice <= 0 {
+		return
+	}
+
+	x, y := math.Log(hedgePrice), math.Log(primaryPrice)
+	s.estimator.Update(x, y)
+	if !s.estimator.Ready() {
+		return
+	}
+
+	alpha, beta := s.estimator.Estimate()
+	s.spread.PushAndEmit(y - alpha - beta*x)
+
+	s.primaryPrice = fixedpoint.NewFromFloat(primaryPrice)
+	s.hedgePrice = fixedpoint.NewFromFloat(hedgePrice)
+
+	if !s.running || s.spread.Length() < s.Window {
+		return
+	}
+
+	z := s.zScore.Last(0)
+	side, stopped := s.nextSide(s.State.Side, s.State.Stopped, z)
+	s.State.Stopped = stopped
+
+	switch {
+	case side == s.State.Side:
+		s.balance(ctx)
+
+	case side == SpreadFlat:
+		log.Infof("closing %s spread position, z-score: %f, stopped: %v", s.InstanceID(), z, stopped)
+		s.closeSpread(ctx)
+
+	default:
+		log.Infof("opening %s spread position, side: %d, z-score: %f, beta: %f", s.InstanceID(), side, z, beta)
+		s.openSpread(ctx, side, beta)
+	}
+}
+
+// nextSide returns the side of the spread position by the z-score, and whether the spread position is stopped out
+func (s *Strategy) nextSide(side int, stopped bool, z float64) (int, bool) {
+	if stopped {
+		// wait for the spread to revert before opening a new spread position
+		return SpreadFlat, math.Abs(z) > s.ExitZScore
+	}
+
+	if side != SpreadFlat && s.StopZScore > 0 && math.Abs(z) >= s.StopZScore {
+		return SpreadFlat, true
+	}
+
+	switch side {
+	case SpreadLong:
+		if z >= -s.ExitZScore {
+			return SpreadFlat, false
+		}
+
+	case SpreadShort:
+		if z <= s.ExitZScore {
+			return SpreadFlat, false
+		}
+
+	default:
+		if z >= s.EntryZScore {
+			return SpreadShort, false
+		} else if z <= -s.EntryZScore {
+			return SpreadLong, false
+		}
+	}
+
+	return side, false
+}
+
+func (s *Strategy) openSpread(ctx context.Context, side int, beta float64) {
+	if beta <= 0 {
+		log.Warnf("hedge ratio %f is not positive, the legs are not hedging each other, skip opening", beta)
+		return
+	}
+
+	quantity := s.Quantity
+	if quantity.IsZero() {
+		quantity = s.QuoteQuantity.Div(s.primaryPrice)
+	}
+	quantity = s.Primary.market.TruncateQuantity(quantity)
+
+	// the notional of the hedge leg is beta times of the notional of the primary leg
+	hedgeRatio := fixedpoint.NewFromFloat(beta).Mul(s.primaryPrice).Div(s.hedgePrice)
+
+	s.cancelOrders(ctx)
+
+	s.State.Side = side
+	s.State.Quantity = quantity
+	s.State.HedgeRatio = hedgeRatio
+	s.State.OpenTime = time.Now()
+
+	// both legs are submitted together, the hedge leg is balanced later with the filled quantity of the primary leg
+	primaryTarget := quantity.Mul(fixedpoint.NewFromInt(int64(side)))
+	if err := s.Primary.adjust(ctx, primaryTarget, s.primaryPrice, "pairOpen"); err != nil {
+		log.WithError(err).Errorf("unable to open the primary leg %s", s.Primary.String())
+	}
+
+	if err := s.Hedge.adjust(ctx, hedgeTarget(primaryTarget, hedgeRatio), s.hedgePrice, "pairOpen"); err != nil {
+		log.WithError(err).Errorf("unable to open the hedge leg %s", s.Hedge.String())
+	}
+
+	bbgo.Sync(ctx, s)
+}
+
+func (s *Strategy) closeSpread(ctx context.Context) {
+	s.cancelOrders(ctx)
+
+	s.State.Side = SpreadFlat
+	s.State.Quantity = fixedpoint.Zero
+
+	if err := s.Primary.adjust(ctx, fixedpoint.Zero, s.primaryPrice, "pairClose"); err != nil {
+		log.WithError(err).Errorf("unable to close the primary leg %s", s.Primary.String())
+	}
+
+	if err := s.Hedge.adjust(ctx, fixedpoint.Zero, s.hedgePrice, "pairClose"); err != nil {
+		log.WithError(err).Errorf("unable to close the hedge leg %s", s.Hedge.String())
+	}
+
+	bbgo.Sync(ctx, s)
+}
+
+func (s *Strategy) cancelOrders(ctx context.Context) {
+	for _, leg := range []*Leg{&s.Primary, &s.Hedge} {
+		if err := leg.orderExecutor.GracefulCancel(ctx); err != nil {
+			log.WithError(err).Errorf("unable to cancel %s orders", leg.String())
+		}
+	}
+}
+
+// hedgeTarget returns the base quantity of the hedge leg that balances the base quantity of the primary leg
+func hedgeTarget(primary, hedgeRatio fixedpoint.Value) fixedpoint.Value {
+	return primary.Mul(hedgeRatio).Neg()
+}
+
+// isImbalanced returns true if the hedge quantity deviates from the target by more than the max imbalance ratio
+func isImbalanced(hedge, target, maxImbalance fixedpoint.Value) bool {
+	return hedge.Sub(target).Abs().Compare(target.Abs().Mul(maxImbalance)) > 0
+}
+
+// balance moves the primary leg to the quantity of the spread position, and then the hedge leg to the filled
+// quantity of the primary leg. It waits for the active orders, which will update the positions.
+func (s *Strategy) balance(ctx context.Context) {
+	if s.primaryPrice.IsZero() || s.hedgePrice.IsZero() {
+		return
+	}
+
+	if s.Primary.orderExecutor.ActiveMakerOrders().NumOfOrders() > 0 || s.Hedge.orderExecutor.ActiveMakerOrders().NumOfOrders() > 0 {
+		return
+	}
+
+	primaryTarget := s.State.Quantity.Mul(fixedpoint.NewFromInt(int64(s.State.Side)))
+	primaryDiff := primaryTarget.Sub(s.PrimaryPosition.GetBase()).Abs()
+	if !s.Primary.isDust(s.Primary.market.TruncateQuantity(primaryDiff), s.primaryPrice) {
+		log.Infof("primary leg %s is off the target %s, adjusting", s.Primary.String(), primaryTarget.String())
+		if err := s.Primary.adjust(ctx, primaryTarget, s.primaryPrice, "pairBalance"); err != nil {
+			log.WithError(err).Errorf("unable to adjust the primary leg %s", s.Primary.String())
+		}
+
+		// the hedge leg is balanced after the primary order is filled
+		return
+	}
+
+	target := hedgeTarget(s.PrimaryPosition.GetBase(), s.State.HedgeRatio)
+	if !isImbalanced(s.HedgePosition.GetBase(), target, s.MaxImbalance) {
+		return
+	}
+
+	log.Infof("hedge leg %s is imbalanced, base: %s, target: %s", s.Hedge.String(), s.HedgePosition.GetBase().String(), target.String())
+	if err := s.Hedge.adjust(ctx, target, s.hedgePrice, "pairBalance"); err != nil {
+		log.WithError(err).Errorf("unable to adjust the hedge leg %s", s.Hedge.String())
+	}
+}
+
+func (s *Strategy) requestBalance() {
+	select {
+	case s.balanceC <- struct{}{}:
+	default:
+	}
+}
+
+// balanceWorker balances the legs when the positions are updated, for example, by the partially filled orders
+func (s *Strategy) balanceWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-s.balanceC:
+			s.mu.Lock()
+			if s.running {
+				s.balance(ctx)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// maxPendingKLines is the max number of the klines waiting for the other leg, the oldest one is dropped
+const maxPendingKLines = 100
+
+// pairAligner pairs the close prices of the klines of the legs by the start time
+type pairAligner struct {
+	pending [2]map[int64]float64
+
+	// last is the start time of the last paired klines
+	last int64
+
+	handler func(primary, hedge float64)
+}
+
+func newPairAligner(handler func(primary, hedge float64)) *pairAligner {
+	return &pairAligner{
+		pending: [2]map[int64]float64{{}, {}},
+		handler: handler,
+	}
+}
+
+// push pushes the kline of the leg, 0 is the primary leg and 1 is the hedge leg.
+// The klines older than the last paired klines are dropped.
+func (a *pairAligner) push(leg int, k types.KLine) {
+	t := k.StartTime.Time().UnixMilli()
+	if t <= a.last {
+		return
+	}
+
+	other, ok := a.pending[1-leg][t]
+	if !ok {
+		a.pending[leg][t] = k.Close.Float64()
+		if len(a.pending[leg]) > maxPendingKLines {
+			oldest := t
+			for pt := range a.pending[leg] {
+				if pt < oldest {
+					oldest = pt
+				}
+			}
+			delete(a.pending[leg], oldest)
+		}
+		return
+	}
+
+	a.last = t
+	for _, pending := range a.pending {
+		for pt := range pending {
+			if pt <= t {
+				delete(pending, pt)
+			}
+		}
+	}
+
+	if leg == 0 {
+		a.handler(k.Close.Float64(), other)
+	} else {
+		a.handler(other, k.Close.Float64())
+	}
+}
@@ -0,0 +1,88 @@
+package pairtrade
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_nextSide(t *testing.T) {
+	s := &Strategy{EntryZScore: 2.0, ExitZScore: 0.5, StopZScore: 4.0}
+
+	tests := []struct {
+		name        string
+		side        int
+		stopped     bool
+		z           float64
+		wantSide    int
+		wantStopped bool
+	}{
+		{"flat", SpreadFlat, false, 1.0, SpreadFlat, false},
+		{"open short", SpreadFlat, false, 2.1, SpreadShort, false},
+		{"open long", SpreadFlat, false, -2.1, SpreadLong, false},
+		{"hold short", SpreadShort, false, 1.0, SpreadShort, false},
+		{"exit short", SpreadShort, false, 0.4, SpreadFlat, false},
+		{"exit long", SpreadLong, false, -0.4, SpreadFlat, false},
+		{"stop long", SpreadLong, false, -4.5, SpreadFlat, true},
+		{"stopped", SpreadFlat, true, 3.0, SpreadFlat, true},
+		{"stop released", SpreadFlat, true, 0.3, SpreadFlat, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			side, stopped := s.nextSide(tt.side, tt.stopped, tt.z)
+			assert.Equal(t, tt.wantSide, side)
+			assert.Equal(t, tt.wantStopped, stopped)
+		})
+	}
+}
+
+func TestHedgeBalance(t *testing.T) {
+	ratio := fixedpoint.NewFromFloat(0.05)
+	maxImbalance := fixedpoint.NewFromFloat(0.01)
+
+	// the primary leg is partially filled, 0.6 of 1.0
+	target := hedgeTarget(fixedpoint.NewFromFloat(0.6), ratio)
+	assert.Equal(t, "-0.03", target.String())
+
+	assert.True(t, isImbalanced(fixedpoint.NewFromFloat(-0.05), target, maxImbalance))
+	assert.False(t, isImbalanced(fixedpoint.NewFromFloat(-0.0300001), target, maxImbalance))
+	assert.False(t, isImbalanced(fixedpoint.Zero, fixedpoint.Zero, maxImbalance))
+}
+
+func TestPairAligner(t *testing.T) {
+	var pairs [][2]float64
+	aligner := newPairAligner(func(primary, hedge float64) {
+		pairs = append(pairs, [2]float64{primary, hedge})
+	})
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kLine := func(minute int, price float64) types.KLine {
+		return types.KLine{
+			StartTime: types.Time(base.Add(time.Duration(minute) * time.Minute)),
+			Close:     fixedpoint.NewFromFloat(price),
+		}
+	}
+
+	// the history of the primary leg is pushed before the hedge leg
+	aligner.push(0, kLine(0, 10))
+	aligner.push(0, kLine(1, 11))
+	aligner.push(1, kLine(0, 100))
+	aligner.push(1, kLine(1, 101))
+
+	// the hedge kline of minute 2 is missing
+	aligner.push(0, kLine(2, 12))
+	aligner.push(1, kLine(3, 103))
+	aligner.push(0, kLine(3, 13))
+
+	// the primary kline of minute 2 is dropped after minute 3 is paired
+	aligner.push(1, kLine(2, 102))
+
+	assert.Equal(t, [][2]float64{{10, 100}, {11, 101}, {13, 103}}, pairs)
+	assert.Empty(t, aligner.pending[0])
+	assert.Empty(t, aligner.pending[1])
+}
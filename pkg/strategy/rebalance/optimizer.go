@@ -0,0 +1,387 @@
+package rebalance
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type OptimizerMethod string
+
+const (
+	OptimizerMethodMeanVariance OptimizerMethod = "meanVariance"
+	OptimizerMethodMinVariance  OptimizerMethod = "minVariance"
+	OptimizerMethodRiskParity   OptimizerMethod = "riskParity"
+)
+
+const (
+	optimizerMaxIterations = 10_000
+	optimizerTolerance     = 1e-10
+)
+
+// OptimizerConfig computes the target weights of the base currencies from the returns of the historical klines.
+// The weights are long-only, and the weight of the quote currency in targetWeights is kept as the cash reserve.
+type OptimizerConfig struct {
+	// Method is "meanVariance", "minVariance" or "riskParity"
+	Method OptimizerMethod `json:"method"`
+
+	// Interval and Window are the interval and the number of the returns of the historical klines
+	Interval types.Interval `json:"interval"`
+	Window   int            `json:"window"`
+
+	// Shrinkage is the intensity of shrinking the sample covariance toward the scaled identity matrix, from 0 to 1.
+	// The intensity is estimated by the Ledoit-Wolf method if it's not set.
+	Shrinkage *float64 `json:"shrinkage,omitempty"`
+
+	// RiskAversion is the risk aversion of the mean-variance method, it maximizes w'mu - riskAversion / 2 * w'Sw
+	RiskAversion float64 `json:"riskAversion,omitempty"`
+
+	// MaxWeight is the max weight of a base currency in the optimized portfolio, before the cash reserve is applied
+	MaxWeight fixedpoint.Value `json:"maxWeight,omitempty"`
+
+	// TargetVolatility is the annualized volatility target of the portfolio, the base currency weights are scaled down
+	// and the rest is moved into the quote currency when the forecast volatility is higher than the target.
+	TargetVolatility fixedpoint.Value `json:"targetVolatility,omitempty"`
+
+	// MaxTurnover is the max one-way turnover of a scheduled rebalance, the sum of the weight increases.
+	// The target weights move toward the optimized weights by this turnover.
+	MaxTurnover fixedpoint.Value `json:"maxTurnover,omitempty"`
+}
+
+func (c *OptimizerConfig) Defaults() {
+	if c.Interval == "" {
+		c.Interval = types.Interval1d
+	}
+
+	if c.Window == 0 {
+		c.Window = 90
+	}
+
+	if c.RiskAversion == 0 {
+		c.RiskAversion = 3.0
+	}
+
+	if c.MaxWeight.IsZero() {
+		c.MaxWeight = fixedpoint.One
+	}
+}
+
+func (c *OptimizerConfig) Validate() error {
+	switch c.Method {
+	case OptimizerMethodMeanVariance, OptimizerMethodMinVariance, OptimizerMethodRiskParity:
+	default:
+		return fmt.Errorf("unsupported optimizer method: %q", c.Method)
+	}
+
+	if c.Window < 2 {
+		return fmt.Errorf("optimizer window should be greater than 1")
+	}
+
+	if c.Shrinkage != nil && (*c.Shrinkage < 0 || *c.Shrinkage > 1) {
+		return fmt.Errorf("optimizer shrinkage should be between 0 and 1")
+	}
+
+	if c.RiskAversion <= 0 {
+		return fmt.Errorf("optimizer riskAversion should be positive")
+	}
+
+	if c.MaxWeight.Sign() <= 0 || c.MaxWeight.Compare(fixedpoint.One) > 0 {
+		return fmt.Errorf("optimizer maxWeight should be between 0 and 1")
+	}
+
+	if c.TargetVolatility.Sign() < 0 || c.MaxTurnover.Sign() < 0 {
+		return fmt.Errorf("optimizer targetVolatility and maxTurnover should not be negative")
+	}
+
+	return nil
+}
+
+// Optimize returns the weights of the assets from the returns, returns[t][i] is the return of the asset i at time t.
+// The weights sum to 1 unless the volatility target scales them down.
+func (c *OptimizerConfig) Optimize(returns [][]float64) []float64 {
+	n := len(returns[0])
+	mean := meanReturns(returns)
+	covariance := shrinkCovariance(returns, c.Shrinkage)
+
+	// the max weight can't be less than the equal weight, otherwise the weights can't sum to 1
+	maxWeight := math.Max(c.MaxWeight.Float64(), 1.0/float64(n))
+
+	var weights []float64
+	switch c.Method {
+	case OptimizerMethodMeanVariance:
+		weights = meanVarianceWeights(mean, covariance, c.RiskAversion, maxWeight)
+	case OptimizerMethodMinVariance:
+		weights = meanVarianceWeights(make([]float64, n), covariance, 1.0, maxWeight)
+	case OptimizerMethodRiskParity:
+		weights = riskParityWeights(covariance, maxWeight)
+	}
+
+	if c.TargetVolatility.Sign() > 0 {
+		volatility := math.Sqrt(quadratic(weights, covariance) * periodsPerYear(c.Interval))
+		if target := c.TargetVolatility.Float64(); volatility > target {
+			for i := range weights {
+				weights[i] *= target / volatility
+			}
+		}
+	}
+
+	return weights
+}
+
+func periodsPerYear(interval types.Interval) float64 {
+	return float64(365*24*time.Hour) / float64(interval.Duration())
+}
+
+func meanReturns(returns [][]float64) []float64 {
+	n := len(returns[0])
+	mean := make([]float64, n)
+	for _, r := range returns {
+		for i := range r {
+			mean[i] += r[i] / float64(len(returns))
+		}
+	}
+	return mean
+}
+
+// shrinkCovariance returns the covariance shrunk toward mu * I, where mu is the average variance.
+// If the shrinkage is nil, the shrinkage intensity is estimated by the Ledoit-Wolf (2004) method.
+func shrinkCovariance(returns [][]float64, shrinkage *float64) [][]float64 {
+	t, n := float64(len(returns)), len(returns[0])
+	mean := meanReturns(returns)
+
+	deviations := make([][]float64, len(returns))
+	for k, r := range returns {
+		deviations[k] = make([]float64, n)
+		for i := range r {
+			deviations[k][i] = r[i] - mean[i]
+		}
+	}
+
+	sample := make([][]float64, n)
+	for i := range sample {
+		sample[i] = make([]float64, n)
+		for j := range sample[i] {
+			for _, d := range deviations {
+				sample[i][j] += d[i] * d[j] / t
+			}
+		}
+	}
+
+	var mu float64
+	for i := 0; i < n; i++ {
+		mu += sample[i][i] / float64(n)
+	}
+
+	var intensity float64
+	if shrinkage != nil {
+		intensity = *shrinkage
+	} else {
+		// d2 is the distance of the sample covariance from the target,
+		// b2 is the estimated error of the sample covariance, bounded by d2
+		var d2, b2 float64
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				target := 0.0
+				if i == j {
+					target = mu
+				}
+				d2 += (sample[i][j] - target) * (sample[i][j] - target)
+			}
+		}
+
+		for _, d := range deviations {
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					e := d[i]*d[j] - sample[i][j]
+					b2 += e * e / (t * t)
+				}
+			}
+		}
+
+		if d2 > 0 {
+			intensity = math.Min(b2, d2) / d2
+		}
+	}
+
+	covariance := make([][]float64, n)
+	for i := range covariance {
+		covariance[i] = make([]float64, n)
+		for j := range covariance[i] {
+			covariance[i][j] = (1 - intensity) * sample[i][j]
+			if i == j {
+				covariance[i][j] += intensity * mu
+			}
+		}
+	}
+	return covariance
+}
+
+func multiply(m [][]float64, v []float64) []float64 {
+	out := make([]float64, len(m))
+	for i := range m {
+		for j := range v {
+			out[i] += m[i][j] * v[j]
+		}
+	}
+	return out
+}
+
+func quadratic(w []float64, m [][]float64) float64 {
+	var sum float64
+	for i, x := range multiply(m, w) {
+		sum += w[i] * x
+	}
+	return sum
+}
+
+// projectCappedSimplex projects v onto {w: 0 <= w[i] <= maxWeight, sum(w) = 1} by the bisection of the shift
+func projectCappedSimplex(v []float64, maxWeight float64) []float64 {
+	w := make([]float64, len(v))
+	shift := func(tau float64) float64 {
+		var sum float64
+		for i := range v {
+			w[i] = math.Min(math.Max(v[i]-tau, 0), maxWeight)
+			sum += w[i]
+		}
+		return sum
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, x := range v {
+		lo = math.Min(lo, x-maxWeight)
+		hi = math.Max(hi, x)
+	}
+
+	for i := 0; i < 100; i++ {
+		tau := (lo + hi) / 2
+		if shift(tau) > 1 {
+			lo = tau
+		} else {
+			hi = tau
+		}
+	}
+
+	shift((lo + hi) / 2)
+	return w
+}
+
+func equalWeights(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1.0 / float64(n)
+	}
+	return w
+}
+
+// meanVarianceWeights maximizes w'mu - riskAversion / 2 * w'Sw by the projected gradient ascent,
+// it's the minimum variance portfolio when mu is zero
+func meanVarianceWeights(mu []float64, covariance [][]float64, riskAversion, maxWeight float64) []float64 {
+	w := equalWeights(len(mu))
+
+	// the step size is bounded by the largest eigenvalue, which is not greater than the trace
+	var trace float64
+	for i := range covariance {
+		trace += covariance[i][i]
+	}
+
+	if trace == 0 {
+		return w
+	}
+
+	step := 1.0 / (riskAversion * trace)
+	for iteration := 0; iteration < optimizerMaxIterations; iteration++ {
+		sw := multiply(covariance, w)
+		next := make([]float64, len(w))
+		for i := range w {
+			next[i] = w[i] + step*(mu[i]-riskAversion*sw[i])
+		}
+		next = projectCappedSimplex(next, maxWeight)
+
+		var change float64
+		for i := range w {
+			change += math.Abs(next[i] - w[i])
+		}
+
+		w = next
+		if change < optimizerTolerance {
+			break
+		}
+	}
+
+	return w
+}
+
+// riskParityWeights returns the weights of which the risk contributions w[i] * (Sw)[i] are equal
+func riskParityWeights(covariance [][]float64, maxWeight float64) []float64 {
+	n := len(covariance)
+	w := equalWeights(n)
+
+	for iteration := 0; iteration < optimizerMaxIterations; iteration++ {
+		sw := multiply(covariance, w)
+		variance := quadratic(w, covariance)
+		if variance <= 0 {
+			return w
+		}
+
+		// scale each weight by the square root of the ratio of the target contribution to the current contribution
+		next := make([]float64, n)
+		for i := range w {
+			contribution := w[i] * sw[i] / variance
+			if contribution <= 0 {
+				next[i] = w[i]
+				continue
+			}
+			next[i] = w[i] * math.Sqrt(1.0/float64(n)/contribution)
+		}
+
+		var sum float64
+		for _, x := range next {
+			sum += x
+		}
+		for i := range next {
+			next[i] /= sum
+		}
+		next = projectCappedSimplex(next, maxWeight)
+
+		var change float64
+		for i := range w {
+			change += math.Abs(next[i] - w[i])
+		}
+
+		w = next
+		if change < optimizerTolerance {
+			break
+		}
+	}
+
+	return w
+}
+
+// limitTurnover moves the current weights toward the target weights by the max one-way turnover
+func limitTurnover(current, target types.ValueMap, maxTurnover fixedpoint.Value) types.ValueMap {
+	if maxTurnover.IsZero() {
+		return target
+	}
+
+	turnover := fixedpoint.Zero
+	for currency, weight := range target {
+		if diff := weight.Sub(current[currency]); diff.Sign() > 0 {
+			turnover = turnover.Add(diff)
+		}
+	}
+
+	if turnover.Compare(maxTurnover) <= 0 {
+		return target
+	}
+
+	ratio := maxTurnover.Div(turnover)
+	limited := make(types.ValueMap)
+	for currency, weight := range target {
+		c := current[currency]
+		limited[currency] = c.Add(weight.Sub(c).Mul(ratio))
+	}
+	return limited
+}
@@ -0,0 +1,127 @@
+package rebalance
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// uncorrelatedReturns generates the returns of the assets with the volatilities and the means
+func uncorrelatedReturns(n int, volatilities, means []float64) [][]float64 {
+	r := rand.New(rand.NewSource(1))
+	returns := make([][]float64, n)
+	for t := range returns {
+		returns[t] = make([]float64, len(volatilities))
+		for i, v := range volatilities {
+			returns[t][i] = means[i] + v*r.NormFloat64()
+		}
+	}
+	return returns
+}
+
+func TestProjectCappedSimplex(t *testing.T) {
+	w := projectCappedSimplex([]float64{0.9, 0.5, -0.2}, 0.6)
+	assert.InDelta(t, 0.6, w[0], 1e-9)
+	assert.InDelta(t, 0.4, w[1], 1e-9)
+	assert.InDelta(t, 0.0, w[2], 1e-9)
+}
+
+func TestOptimizerConfig_Optimize(t *testing.T) {
+	zero := 0.0
+	returns := uncorrelatedReturns(2000, []float64{0.01, 0.02}, []float64{0, 0})
+
+	t.Run("minVariance", func(t *testing.T) {
+		c := &OptimizerConfig{Method: OptimizerMethodMinVariance, Window: 2000, Shrinkage: &zero}
+		c.Defaults()
+		assert.NoError(t, c.Validate())
+
+		// the weights of the uncorrelated assets are inversely proportional to the variances
+		w := c.Optimize(returns)
+		assert.InDelta(t, 0.8, w[0], 0.03)
+		assert.InDelta(t, 0.2, w[1], 0.03)
+	})
+
+	t.Run("riskParity", func(t *testing.T) {
+		c := &OptimizerConfig{Method: OptimizerMethodRiskParity, Window: 2000, Shrinkage: &zero}
+		c.Defaults()
+
+		// the weights of the uncorrelated assets are inversely proportional to the volatilities
+		w := c.Optimize(returns)
+		assert.InDelta(t, 2.0/3.0, w[0], 0.03)
+		assert.InDelta(t, 1.0/3.0, w[1], 0.03)
+	})
+
+	t.Run("meanVariance", func(t *testing.T) {
+		c := &OptimizerConfig{Method: OptimizerMethodMeanVariance, Window: 2000, Shrinkage: &zero, MaxWeight: fixedpoint.NewFromFloat(0.7)}
+		c.Defaults()
+
+		w := c.Optimize(uncorrelatedReturns(2000, []float64{0.01, 0.01}, []float64{0.0, 0.002}))
+		assert.InDelta(t, 0.3, w[0], 1e-6, "the higher return asset is capped by the max weight")
+		assert.InDelta(t, 0.7, w[1], 1e-6)
+	})
+
+	t.Run("targetVolatility", func(t *testing.T) {
+		c := &OptimizerConfig{Method: OptimizerMethodMinVariance, Window: 2000, Interval: types.Interval1d, Shrinkage: &zero,
+			TargetVolatility: fixedpoint.NewFromFloat(0.1)}
+		c.Defaults()
+
+		w := c.Optimize(returns)
+		covariance := shrinkCovariance(returns, &zero)
+		assert.InDelta(t, 0.1, math.Sqrt(quadratic(w, covariance)*365), 1e-9)
+		assert.Less(t, w[0]+w[1], 1.0)
+	})
+}
+
+func TestShrinkCovariance(t *testing.T) {
+	returns := uncorrelatedReturns(30, []float64{0.01, 0.02, 0.03}, []float64{0, 0, 0})
+
+	one := 1.0
+	covariance := shrinkCovariance(returns, &one)
+	assert.Equal(t, 0.0, covariance[0][1])
+	assert.InDelta(t, covariance[0][0], covariance[2][2], 1e-15)
+
+	// the estimated intensity is between the sample covariance and the target
+	sample := shrinkCovariance(returns, new(float64))
+	shrunk := shrinkCovariance(returns, nil)
+	assert.Less(t, math.Abs(shrunk[0][1]), math.Abs(sample[0][1]))
+	assert.Greater(t, shrunk[0][0], sample[0][0])
+	assert.Less(t, shrunk[2][2], sample[2][2])
+}
+
+func TestLimitTurnover(t *testing.T) {
+	current := types.ValueMap{"BTC": fixedpoint.NewFromFloat(0.2), "ETH": fixedpoint.NewFromFloat(0.3), "USDT": fixedpoint.NewFromFloat(0.5)}
+	target := types.ValueMap{"BTC": fixedpoint.NewFromFloat(0.6), "ETH": fixedpoint.NewFromFloat(0.3), "USDT": fixedpoint.NewFromFloat(0.1)}
+
+	limited := limitTurnover(current, target, fixedpoint.NewFromFloat(0.1))
+	assert.Equal(t, "0.3", limited["BTC"].String())
+	assert.Equal(t, "0.3", limited["ETH"].String())
+	assert.Equal(t, "0.4", limited["USDT"].String())
+
+	assert.Equal(t, target, limitTurnover(current, target, fixedpoint.Zero))
+	assert.Equal(t, target, limitTurnover(current, target, fixedpoint.One))
+}
+
+func TestProposalReport(t *testing.T) {
+	report := proposalReport([]proposedOrder{
+		{
+			SubmitOrder: types.SubmitOrder{
+				Symbol:   "BTCUSDT",
+				Side:     types.SideTypeSell,
+				Quantity: fixedpoint.NewFromFloat(0.01),
+				Price:    fixedpoint.NewFromFloat(40000),
+			},
+			Weight:       fixedpoint.NewFromFloat(0.52),
+			TargetWeight: fixedpoint.NewFromFloat(0.5),
+		},
+	}, "USDT")
+
+	lines := strings.Split(report, "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "BTCUSDT SELL 0.01 @ 40000 (400 USDT), weight 52.00% -> 50.00%", lines[1])
+}
@@ -3,6 +3,9 @@ package rebalance
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/robfig/cron/v3"
@@ -10,6 +13,7 @@ import (
 
 	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
+	indicatorv2 "github.com/c9s/bbgo/pkg/indicator/v2"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -38,10 +42,18 @@ type Strategy struct {
 	DryRun        bool              `json:"dryRun"`
 	OnStart       bool              `json:"onStart"` // rebalance on start
 
+	// Optimizer computes the target weights of the base currencies on every scheduled rebalance,
+	// targetWeights is used before there are enough historical klines
+	Optimizer *OptimizerConfig `json:"optimizer,omitempty"`
+
 	symbols         []string
 	markets         map[string]types.Market
 	activeOrderBook *bbgo.ActiveOrderBook
 	cron            *cron.Cron
+
+	// targetWeights is the target weights of the current rebalance, it's TargetWeights or the optimized weights
+	targetWeights types.ValueMap
+	closePrices   map[string]*indicatorv2.PriceStream
 }
 
 func (s *Strategy) Defaults() error {
@@ -56,6 +68,10 @@ func (s *Strategy) Defaults() error {
 	if s.BalanceType == "" {
 		s.BalanceType = types.BalanceTypeAvailable
 	}
+
+	if s.Optimizer != nil {
+		s.Optimizer.Defaults()
+	}
 	return nil
 }
 
@@ -104,10 +120,28 @@ func (s *Strategy) Validate() error {
 	if s.MaxAmount.Sign() < 0 {
 		return fmt.Errorf("maxAmount shoud not less than 0")
 	}
+
+	if s.Optimizer != nil {
+		if err := s.Optimizer.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := s.TargetWeights[s.QuoteCurrency]; !ok && s.Optimizer.TargetVolatility.Sign() > 0 {
+			return fmt.Errorf("the quote currency %s should be in targetWeights for the volatility target", s.QuoteCurrency)
+		}
+	}
 	return nil
 }
 
-func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {}
+func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
+	if s.Optimizer == nil {
+		return
+	}
+
+	for _, symbol := range s.symbols {
+		session.Subscribe(types.KLineChannel, symbol, types.SubscribeOptions{Interval: s.Optimizer.Interval})
+	}
+}
 
 func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
 	s.markets = make(map[string]types.Market)
@@ -121,6 +155,14 @@ func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.
 
 	s.MultiMarketStrategy.Initialize(ctx, s.Environment, session, s.markets, ID, s.InstanceID())
 
+	s.targetWeights = s.TargetWeights
+	if s.Optimizer != nil {
+		s.closePrices = make(map[string]*indicatorv2.PriceStream)
+		for _, symbol := range s.symbols {
+			s.closePrices[symbol] = session.Indicators(symbol).CLOSE(s.Optimizer.Interval)
+		}
+	}
+
 	s.activeOrderBook = bbgo.NewActiveOrderBook("")
 	s.activeOrderBook.BindStream(session.UserDataStream)
 	s.activeOrderBook.OnFilled(func(order types.Order) {
@@ -129,6 +171,7 @@ func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.
 
 	session.UserDataStream.OnStart(func() {
 		if s.OnStart {
+			s.optimize(ctx)
 			s.rebalance(ctx)
 		}
 	})
@@ -142,6 +185,7 @@ func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.
 
 	s.cron = cron.New()
 	s.cron.AddFunc(s.Schedule, func() {
+		s.optimize(ctx)
 		s.rebalance(ctx)
 	})
 	s.cron.Start()
@@ -155,24 +199,29 @@ func (s *Strategy) rebalance(ctx context.Context) {
 		log.WithError(err).Errorf("failed to cancel orders")
 	}
 
-	order, err := s.generateOrder(ctx)
+	orders, err := s.generateOrders(ctx)
 	if err != nil {
 		log.WithError(err).Error("failed to generate order")
 		return
 	}
 
-	if order == nil {
+	if len(orders) == 0 {
 		log.Info("no order generated")
 		return
 	}
-	log.Infof("generated order: %s", order.String())
 
 	if s.DryRun {
-		log.Infof("dry run, not submitting orders")
+		report := proposalReport(orders, s.QuoteCurrency)
+		log.Infof("dry run, not submitting orders\n%s", report)
+		bbgo.Notify(report)
 		return
 	}
 
-	createdOrders, err := s.OrderExecutorMap.SubmitOrders(ctx, *order)
+	// the orders are submitted one by one, the next order is generated when the order is filled
+	order := orders[0].SubmitOrder
+	log.Infof("generated order: %s", order.String())
+
+	createdOrders, err := s.OrderExecutorMap.SubmitOrders(ctx, order)
 	if err != nil {
 		log.WithError(err).Error("failed to submit orders")
 		return
@@ -211,22 +260,136 @@ func (s *Strategy) selectBalances() (types.BalanceMap, error) {
 	return m, nil
 }
 
-func (s *Strategy) generateOrder(ctx context.Context) (*types.SubmitOrder, error) {
-	prices, err := s.queryMidPrices(ctx)
+// proposedOrder is a rebalance order with the current weight and the target weight of the base currency
+type proposedOrder struct {
+	types.SubmitOrder
+
+	Weight, TargetWeight fixedpoint.Value
+}
+
+// proposalReport reports the proposed orders of the dry run
+func proposalReport(orders []proposedOrder, quoteCurrency string) string {
+	var sb strings.Builder
+	sb.WriteString("rebalance proposed orders:")
+	for _, order := range orders {
+		fmt.Fprintf(&sb, "\n%s %s %s @ %s (%s %s), weight %.2f%% -> %.2f%%",
+			order.Symbol,
+			order.Side,
+			order.Quantity.String(),
+			order.Price.String(),
+			order.Quantity.Mul(order.Price).String(),
+			quoteCurrency,
+			order.Weight.Float64()*100,
+			order.TargetWeight.Float64()*100)
+	}
+	return sb.String()
+}
+
+// currentWeights returns the weights of the currencies in targetWeights
+func (s *Strategy) currentWeights(ctx context.Context) (prices, values, weights types.ValueMap, balances types.BalanceMap, err error) {
+	prices, err = s.queryMidPrices(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, nil, err
+	}
+
+	balances, err = s.selectBalances()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	values = prices.Mul(s.toValueMap(balances))
+	return prices, values, values.Normalize(), balances, nil
+}
+
+// optimize updates the target weights by the optimizer, it keeps the current target weights on errors
+func (s *Strategy) optimize(ctx context.Context) {
+	if s.Optimizer == nil {
+		return
+	}
+
+	var currencies []string
+	for _, market := range s.markets {
+		currencies = append(currencies, market.BaseCurrency)
+	}
+	sort.Strings(currencies)
+
+	returns, err := s.historicalReturns(currencies)
+	if err != nil {
+		log.WithError(err).Warnf("unable to optimize the target weights, keep the target weights: %v", s.targetWeights)
+		return
+	}
+
+	_, _, current, _, err := s.currentWeights(ctx)
+	if err != nil {
+		log.WithError(err).Warnf("unable to query the current weights, keep the target weights: %v", s.targetWeights)
+		return
+	}
+
+	// the weight of the quote currency in targetWeights is the cash reserve
+	investment := fixedpoint.One.Sub(s.TargetWeights[s.QuoteCurrency])
+	invested := fixedpoint.Zero
+
+	target := make(types.ValueMap)
+	for i, weight := range s.Optimizer.Optimize(returns) {
+		target[currencies[i]] = fixedpoint.NewFromFloat(weight).Mul(investment)
+		invested = invested.Add(target[currencies[i]])
+	}
+
+	if _, ok := s.TargetWeights[s.QuoteCurrency]; ok {
+		target[s.QuoteCurrency] = fixedpoint.One.Sub(invested)
+	}
+
+	s.targetWeights = limitTurnover(current, target, s.Optimizer.MaxTurnover)
+	log.Infof("optimized target weights: %v, the turnover limited target weights: %v", target, s.targetWeights)
+}
+
+// historicalReturns returns the log returns of the close prices of the window, returns[t][i] is the return of
+// the currency i at time t
+func (s *Strategy) historicalReturns(currencies []string) ([][]float64, error) {
+	n := s.Optimizer.Window + 1
+
+	prices := make([][]float64, len(currencies))
+	for i, currency := range currencies {
+		symbol := currency + s.QuoteCurrency
+		closePrices, ok := s.closePrices[symbol]
+		if !ok || closePrices.Length() < n {
+			return nil, fmt.Errorf("not enough %s %s klines for the optimizer window %d", symbol, s.Optimizer.Interval, s.Optimizer.Window)
+		}
+
+		prices[i] = closePrices.Slice.Tail(n)
+	}
+
+	returns := make([][]float64, s.Optimizer.Window)
+	for t := range returns {
+		returns[t] = make([]float64, len(currencies))
+		for i := range currencies {
+			if prices[i][t] <= 0 || prices[i][t+1] <= 0 {
+				return nil, fmt.Errorf("invalid %s%s close price", currencies[i], s.QuoteCurrency)
+			}
+
+			returns[t][i] = math.Log(prices[i][t+1] / prices[i][t])
+		}
 	}
 
-	balances, err := s.selectBalances()
+	return returns, nil
+}
+
+func (s *Strategy) generateOrders(ctx context.Context) ([]proposedOrder, error) {
+	prices, values, weights, balances, err := s.currentWeights(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	values := prices.Mul(s.toValueMap(balances))
-	weights := values.Normalize()
+	var symbols []string
+	for symbol := range s.markets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
 
-	for symbol, market := range s.markets {
-		target := s.TargetWeights[market.BaseCurrency]
+	var orders []proposedOrder
+	for _, symbol := range symbols {
+		market := s.markets[symbol]
+		target := s.targetWeights[market.BaseCurrency]
 		weight := weights[market.BaseCurrency]
 		midPrice := prices[market.BaseCurrency]
 
@@ -281,15 +444,19 @@ func (s *Strategy) generateOrder(ctx context.Context) (*types.SubmitOrder, error
 			continue
 		}
 
-		return &types.SubmitOrder{
-			Symbol:   symbol,
-			Side:     side,
-			Type:     s.OrderType,
-			Quantity: quantity,
-			Price:    price,
-		}, nil
+		orders = append(orders, proposedOrder{
+			SubmitOrder: types.SubmitOrder{
+				Symbol:   symbol,
+				Side:     side,
+				Type:     s.OrderType,
+				Quantity: quantity,
+				Price:    price,
+			},
+			Weight:       weight,
+			TargetWeight: target,
+		})
 	}
-	return nil, nil
+	return orders, nil
 }
 
 func (s *Strategy) toValueMap(balances types.BalanceMap) types.ValueMap {
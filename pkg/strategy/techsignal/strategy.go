@@ -12,6 +12,7 @@ import (
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 
 	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/indicator/v2/expr"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -57,6 +58,18 @@ type Strategy struct {
 
 		MinQuoteVolume fixedpoint.Value `json:"minQuoteVolume"`
 	} `json:"supportDetection"`
+
+	// Signals notify when the indicator expressions become true on the kline close, for example:
+	//
+	//   signals:
+	//   - interval: 1h
+	//     condition: "cross(ema(close, 9), ema(close, 21))"
+	//     message: "EMA cross"
+	Signals []struct {
+		Interval  types.Interval `json:"interval"`
+		Condition string         `json:"condition"`
+		Message   string         `json:"message"`
+	} `json:"signals"`
 }
 
 func (s *Strategy) ID() string {
@@ -74,6 +87,12 @@ func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
 			Interval: detection.MovingAverageInterval,
 		})
 	}
+
+	for _, signal := range s.Signals {
+		session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{
+			Interval: signal.Interval,
+		})
+	}
 }
 
 func (s *Strategy) Validate() error {
@@ -81,6 +100,37 @@ func (s *Strategy) Validate() error {
 		return errors.New("symbol is required")
 	}
 
+	for _, signal := range s.Signals {
+		if err := expr.Check(signal.Condition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Strategy) bindSignals(session *bbgo.ExchangeSession) error {
+	indicators := session.Indicators(s.Symbol)
+	for _, signal := range s.Signals {
+		interval := signal.Interval
+		message := signal.Message
+		if message == "" {
+			message = signal.Condition
+		}
+
+		condition, err := expr.Compile(indicators.KLines(interval), signal.Condition)
+		if err != nil {
+			return err
+		}
+
+		// notify only when the condition changes from false to true
+		condition.OnUpdate(func(v float64) {
+			if condition.Truthy() && (condition.Length() < 2 || condition.Last(1) == 0) {
+				bbgo.Notify("Detected %s %s signal: %s", s.Symbol, interval.String(), message)
+			}
+		})
+	}
+
 	return nil
 }
 
@@ -154,6 +204,10 @@ func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, se
 		}
 	}
 
+	if err := s.bindSignals(session); err != nil {
+		return err
+	}
+
 	session.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
 		// skip k-lines from other symbols
 		if kline.Symbol != s.Symbol {
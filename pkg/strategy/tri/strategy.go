@@ -143,6 +143,8 @@ func (s *Strategy) InstanceID() string {
 }
 
 func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
+	s.normalizeSymbols(session.ExchangeName)
+
 	if !s.SeparateStream {
 		for _, symbol := range s.Symbols {
 			session.Subscribe(types.BookChannel, symbol, types.SubscribeOptions{
@@ -189,7 +191,7 @@ func (s *Strategy) Initialize() error {
 
 func (s *Strategy) Run(ctx context.Context, orderExecutor bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
 
-	s.Symbols = compileSymbols(s.Symbols)
+	s.normalizeSymbols(session.ExchangeName)
 
 	if s.MarketOrderProtectiveRatio.IsZero() {
 		s.MarketOrderProtectiveRatio = marketOrderProtectiveRatio
@@ -943,6 +945,19 @@ func logMarketPath(path *Path) {
 	displayBook("C", path.marketC)
 }
 
+// normalizeSymbols converts the local symbols of the paths, e.g., BTC-USDT, into the canonical symbols
+func (s *Strategy) normalizeSymbols(exchange types.ExchangeName) {
+	for _, path := range s.Paths {
+		for i, symbol := range path {
+			path[i], _ = types.DefaultSymbolRegistry.CanonicalSymbol(exchange, symbol)
+		}
+	}
+
+	for i, symbol := range s.Symbols {
+		s.Symbols[i], _ = types.DefaultSymbolRegistry.CanonicalSymbol(exchange, symbol)
+	}
+}
+
 func collectSymbols(paths [][]string) (symbols []string) {
 	symbolMap := make(map[string]struct{})
 	for _, path := range paths {
@@ -0,0 +1,293 @@
+package streamrecorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// syntheticOrderIDBase is the base of the order ids of the submitted orders not found in the journal
+const syntheticOrderIDBase = 1_000_000_000_000
+
+// Exchange is the stub exchange of the replay, the queries are served by the recorded data.
+// The submitted orders are matched with the recorded orders of the same symbol, side, type, price and quantity,
+// so that the order updates of the journal are associated with the orders submitted by the replayed strategy.
+type Exchange struct {
+	session SessionInfo
+
+	mu          sync.Mutex
+	now         time.Time
+	markets     types.MarketMap
+	accountData json.RawMessage
+	kLines      map[string][]types.KLine
+	lastPrices  map[string]fixedpoint.Value
+
+	// recordedOrders is the recorded orders in the order of the first update
+	recordedOrders []types.Order
+	matchedOrders  map[uint64]struct{}
+	lastOrderID    uint64
+}
+
+var _ types.Exchange = &Exchange{}
+
+func newExchange(session SessionInfo) *Exchange {
+	return &Exchange{
+		session:       session,
+		now:           session.StartTime,
+		markets:       types.MarketMap{},
+		kLines:        make(map[string][]types.KLine),
+		lastPrices:    make(map[string]fixedpoint.Value),
+		matchedOrders: make(map[uint64]struct{}),
+		lastOrderID:   syntheticOrderIDBase,
+	}
+}
+
+func kLineKey(symbol string, interval types.Interval) string {
+	return symbol + ":" + interval.String()
+}
+
+// load loads the header records and the recorded orders
+func (e *Exchange) load(record Record) error {
+	switch record.Event {
+	case EventMarkets:
+		return json.Unmarshal(record.Data, &e.markets)
+
+	case EventAccount:
+		e.accountData = record.Data
+
+	case EventKLineHistory:
+		var history kLineHistoryRecord
+		if err := json.Unmarshal(record.Data, &history); err != nil {
+			return err
+		}
+
+		for _, k := range history.KLines {
+			e.addKLine(k)
+		}
+
+	case EventOrderUpdate:
+		var order types.Order
+		if err := json.Unmarshal(record.Data, &order); err != nil {
+			return err
+		}
+
+		for _, o := range e.recordedOrders {
+			if o.OrderID == order.OrderID {
+				return nil
+			}
+		}
+		e.recordedOrders = append(e.recordedOrders, order)
+	}
+
+	return nil
+}
+
+func (e *Exchange) setTime(t time.Time) {
+	e.mu.Lock()
+	e.now = t
+	e.mu.Unlock()
+}
+
+func (e *Exchange) setLastPrice(symbol string, price fixedpoint.Value) {
+	e.mu.Lock()
+	e.lastPrices[symbol] = price
+	e.mu.Unlock()
+}
+
+func (e *Exchange) addKLine(k types.KLine) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := kLineKey(k.Symbol, k.Interval)
+	kLines := e.kLines[key]
+
+	// the history klines are preloaded backward in batches and they may overlap with each other,
+	// so that the klines are inserted by the start time and the duplicated klines are skipped
+	idx := sort.Search(len(kLines), func(i int) bool {
+		return !kLines[i].StartTime.Before(k.StartTime.Time())
+	})
+
+	if idx < len(kLines) && kLines[idx].StartTime.Equal(k.StartTime.Time()) {
+		return
+	}
+
+	kLines = append(kLines, types.KLine{})
+	copy(kLines[idx+1:], kLines[idx:])
+	kLines[idx] = k
+	e.kLines[key] = kLines
+
+	if idx == len(kLines)-1 {
+		e.lastPrices[k.Symbol] = k.Close
+	}
+}
+
+func (e *Exchange) Name() types.ExchangeName {
+	return e.session.Exchange
+}
+
+func (e *Exchange) PlatformFeeCurrency() string {
+	return ""
+}
+
+// NewStream returns a new replay stream, the replayed sessions use the streams of the replayer instead
+func (e *Exchange) NewStream() types.Stream {
+	return NewReplayStream()
+}
+
+func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
+	if len(e.markets) == 0 {
+		return nil, fmt.Errorf("the journal has no markets record")
+	}
+
+	return e.markets, nil
+}
+
+func (e *Exchange) QueryTicker(ctx context.Context, symbol string) (*types.Ticker, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	price, ok := e.lastPrices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no recorded price of %s", symbol)
+	}
+
+	return &types.Ticker{Time: e.now, Last: price, Buy: price, Sell: price, Open: price, High: price, Low: price}, nil
+}
+
+func (e *Exchange) QueryTickers(ctx context.Context, symbols ...string) (map[string]types.Ticker, error) {
+	if len(symbols) == 0 {
+		e.mu.Lock()
+		for symbol := range e.lastPrices {
+			symbols = append(symbols, symbol)
+		}
+		e.mu.Unlock()
+	}
+
+	tickers := make(map[string]types.Ticker)
+	for _, symbol := range symbols {
+		ticker, err := e.QueryTicker(ctx, symbol)
+		if err != nil {
+			continue
+		}
+		tickers[symbol] = *ticker
+	}
+
+	return tickers, nil
+}
+
+// QueryKLines returns the recorded klines, including the preloaded history klines and the replayed closed klines
+func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var kLines []types.KLine
+	for _, k := range e.kLines[kLineKey(symbol, interval)] {
+		if options.StartTime != nil && k.StartTime.Before(*options.StartTime) {
+			continue
+		}
+		if options.EndTime != nil && k.EndTime.After(*options.EndTime) {
+			continue
+		}
+		kLines = append(kLines, k)
+	}
+
+	if options.Limit > 0 && len(kLines) > options.Limit {
+		if options.StartTime != nil && options.EndTime == nil {
+			kLines = kLines[:options.Limit]
+		} else {
+			kLines = kLines[len(kLines)-options.Limit:]
+		}
+	}
+
+	return kLines, nil
+}
+
+func (e *Exchange) QueryAccount(ctx context.Context) (*types.Account, error) {
+	account := types.NewAccount()
+	if len(e.accountData) == 0 {
+		return account, nil
+	}
+
+	// decode a new copy for every query, so that the recorded account is not modified by the session
+	var record accountRecord
+	record.Account = account
+	if err := json.Unmarshal(e.accountData, &record); err != nil {
+		return nil, err
+	}
+
+	account.UpdateBalances(record.Balances)
+	return account, nil
+}
+
+func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap, error) {
+	account, err := e.QueryAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.Balances(), nil
+}
+
+func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if recorded, ok := e.matchRecordedOrder(order); ok {
+		return &recorded, nil
+	}
+
+	e.lastOrderID++
+	return &types.Order{
+		SubmitOrder:      order,
+		Exchange:         e.session.Exchange,
+		OrderID:          e.lastOrderID,
+		Status:           types.OrderStatusNew,
+		ExecutedQuantity: fixedpoint.Zero,
+		IsWorking:        true,
+		CreationTime:     types.Time(e.now),
+		UpdateTime:       types.Time(e.now),
+	}, nil
+}
+
+func (e *Exchange) matchRecordedOrder(order types.SubmitOrder) (types.Order, bool) {
+	for _, recorded := range e.recordedOrders {
+		if _, ok := e.matchedOrders[recorded.OrderID]; ok {
+			continue
+		}
+
+		if recorded.Symbol != order.Symbol || recorded.Side != order.Side || recorded.Type != order.Type {
+			continue
+		}
+
+		if !recorded.Quantity.Eq(order.Quantity) || !recorded.Price.Eq(order.Price) {
+			continue
+		}
+
+		e.matchedOrders[recorded.OrderID] = struct{}{}
+
+		// the submitted order is returned as a new order, its status is updated by the recorded order updates
+		recorded.Status = types.OrderStatusNew
+		recorded.ExecutedQuantity = fixedpoint.Zero
+		recorded.IsWorking = true
+		recorded.ClientOrderID = order.ClientOrderID
+		recorded.Tag = order.Tag
+		return recorded, true
+	}
+
+	return types.Order{}, false
+}
+
+// QueryOpenOrders returns no open order, the open orders of the recorded run are restored by the order updates
+func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return nil, nil
+}
+
+func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) error {
+	return nil
+}
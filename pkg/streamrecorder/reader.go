@@ -0,0 +1,89 @@
+package streamrecorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// Reader reads the records of the journal
+type Reader struct {
+	closers []io.Closer
+	decoder *json.Decoder
+}
+
+// Open opens the journal file, the file ends with .gz is decompressed
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(path, ".gz") {
+		return &Reader{closers: []io.Closer{f}, decoder: json.NewDecoder(bufio.NewReader(f))}, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &Reader{closers: []io.Closer{gz, f}, decoder: json.NewDecoder(bufio.NewReader(gz))}, nil
+}
+
+// NewReader creates the reader of the uncompressed journal
+func NewReader(r io.Reader) *Reader {
+	return &Reader{decoder: json.NewDecoder(r)}
+}
+
+// Next returns the next record, io.EOF is returned at the end of the journal.
+// The journal truncated by a crashed process ends at the last complete record.
+func (r *Reader) Next() (*Record, error) {
+	var record Record
+	if err := r.decoder.Decode(&record); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *Reader) ReadAll() (records []Record, err error) {
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return records, err
+		}
+
+		records = append(records, *record)
+	}
+}
+
+func (r *Reader) Close() error {
+	for _, closer := range r.closers {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile reads all the records of the journal file
+func ReadFile(path string) ([]Record, error) {
+	reader, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+	return reader.ReadAll()
+}
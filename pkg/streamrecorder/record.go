@@ -0,0 +1,124 @@
+package streamrecorder
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// StreamType is the stream of the recorded event, the header records have no stream
+type StreamType string
+
+const (
+	StreamMarket StreamType = "market"
+	StreamUser   StreamType = "user"
+)
+
+type EventType string
+
+const (
+	// the header events are replayed by the stub exchange
+	EventSession      EventType = "session"
+	EventMarkets      EventType = "markets"
+	EventAccount      EventType = "account"
+	EventKLineHistory EventType = "klineHistory"
+
+	EventStart           EventType = "start"
+	EventConnect         EventType = "connect"
+	EventDisconnect      EventType = "disconnect"
+	EventAuth            EventType = "auth"
+	EventKLine           EventType = "kline"
+	EventKLineClosed     EventType = "klineClosed"
+	EventBookSnapshot    EventType = "bookSnapshot"
+	EventBookUpdate      EventType = "bookUpdate"
+	EventBookTicker      EventType = "bookTicker"
+	EventMarketTrade     EventType = "marketTrade"
+	EventAggTrade        EventType = "aggTrade"
+	EventTradeUpdate     EventType = "tradeUpdate"
+	EventOrderUpdate     EventType = "orderUpdate"
+	EventBalanceSnapshot EventType = "balanceSnapshot"
+	EventBalanceUpdate   EventType = "balanceUpdate"
+)
+
+// Record is a journal line, the short json keys keep the journal compact
+type Record struct {
+	Time   time.Time       `json:"t"`
+	Stream StreamType      `json:"s,omitempty"`
+	Event  EventType       `json:"e"`
+	Data   json.RawMessage `json:"d,omitempty"`
+}
+
+// SessionInfo is the first record of the journal
+type SessionInfo struct {
+	Name     string             `json:"name"`
+	Exchange types.ExchangeName `json:"exchange"`
+
+	Margin          bool `json:"margin,omitempty"`
+	IsolatedMargin  bool `json:"isolatedMargin,omitempty"`
+	Futures         bool `json:"futures,omitempty"`
+	IsolatedFutures bool `json:"isolatedFutures,omitempty"`
+
+	StartTime time.Time `json:"startTime"`
+}
+
+type accountRecord struct {
+	Account  *types.Account   `json:"account"`
+	Balances types.BalanceMap `json:"balances"`
+}
+
+type kLineHistoryRecord struct {
+	Symbol   string         `json:"symbol"`
+	Interval types.Interval `json:"interval"`
+	KLines   []types.KLine  `json:"klines"`
+}
+
+// bookRecord encodes the price levels as the [price, volume] pairs,
+// since types.PriceVolumeSlice only unmarshals the pair format.
+type bookRecord struct {
+	Symbol       string                `json:"symbol"`
+	Time         time.Time             `json:"time"`
+	LastUpdateId int64                 `json:"lastUpdateId,omitempty"`
+	Checksum     int64                 `json:"checksum,omitempty"`
+	Bids         [][2]fixedpoint.Value `json:"bids"`
+	Asks         [][2]fixedpoint.Value `json:"asks"`
+}
+
+func newBookRecord(book types.SliceOrderBook) bookRecord {
+	return bookRecord{
+		Symbol:       book.Symbol,
+		Time:         book.Time,
+		LastUpdateId: book.LastUpdateId,
+		Checksum:     book.Checksum,
+		Bids:         encodePriceVolumes(book.Bids),
+		Asks:         encodePriceVolumes(book.Asks),
+	}
+}
+
+func (r bookRecord) SliceOrderBook() types.SliceOrderBook {
+	return types.SliceOrderBook{
+		Symbol:       r.Symbol,
+		Time:         r.Time,
+		LastUpdateId: r.LastUpdateId,
+		Checksum:     r.Checksum,
+		Bids:         decodePriceVolumes(r.Bids),
+		Asks:         decodePriceVolumes(r.Asks),
+	}
+}
+
+func encodePriceVolumes(pvs types.PriceVolumeSlice) [][2]fixedpoint.Value {
+	levels := make([][2]fixedpoint.Value, 0, len(pvs))
+	for _, pv := range pvs {
+		levels = append(levels, [2]fixedpoint.Value{pv.Price, pv.Volume})
+	}
+	return levels
+}
+
+func decodePriceVolumes(levels [][2]fixedpoint.Value) types.PriceVolumeSlice {
+	pvs := make(types.PriceVolumeSlice, 0, len(levels))
+	for _, level := range levels {
+		pvs = append(pvs, types.PriceVolume{Price: level[0], Volume: level[1]})
+	}
+	return pvs
+}
@@ -0,0 +1,229 @@
+package streamrecorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// flushInterval is the interval of flushing the buffered records to the file by the flush worker,
+// so that the journal of a crashed process loses at most the records of the last interval
+const flushInterval = time.Second
+
+// Recorder journals the stream events into a json-lines file, the file is gzip compressed when its name ends with .gz
+type Recorder struct {
+	mu sync.Mutex
+
+	file   io.Closer
+	gz     *gzip.Writer
+	buf    *bufio.Writer
+	writer *json.Encoder
+
+	loggedError bool
+
+	// closeC stops the flush worker, and the worker closes stoppedC when it returns
+	closeC    chan struct{}
+	stoppedC  chan struct{}
+	closeOnce sync.Once
+
+	now func() time.Time
+}
+
+// Create creates the journal file of the path
+func Create(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{file: f}
+	if strings.HasSuffix(path, ".gz") {
+		r.gz = gzip.NewWriter(f)
+		r.start(bufio.NewWriter(r.gz))
+	} else {
+		r.start(bufio.NewWriter(f))
+	}
+
+	return r, nil
+}
+
+// NewRecorder creates the recorder writing the uncompressed journal to the writer
+func NewRecorder(w io.Writer) *Recorder {
+	r := &Recorder{}
+	r.start(bufio.NewWriter(w))
+	return r
+}
+
+func (r *Recorder) start(buf *bufio.Writer) {
+	r.buf = buf
+	r.writer = json.NewEncoder(buf)
+	r.now = time.Now
+	r.closeC = make(chan struct{})
+	r.stoppedC = make(chan struct{})
+	go r.flushWorker()
+}
+
+// flushWorker flushes the buffered records every flushInterval until the recorder is closed
+func (r *Recorder) flushWorker() {
+	defer close(r.stoppedC)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closeC:
+			return
+
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				r.logError(err)
+			}
+		}
+	}
+}
+
+// Record journals the event with the current time
+func (r *Recorder) Record(stream StreamType, event EventType, data interface{}) error {
+	record := Record{
+		Stream: stream,
+		Event:  event,
+	}
+
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		record.Data = raw
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record.Time = r.now()
+	return r.writer.Encode(record)
+}
+
+func (r *Recorder) RecordSession(info SessionInfo) error {
+	return r.Record("", EventSession, info)
+}
+
+func (r *Recorder) RecordMarkets(markets types.MarketMap) error {
+	return r.Record("", EventMarkets, markets)
+}
+
+func (r *Recorder) RecordAccount(account *types.Account) error {
+	return r.Record("", EventAccount, accountRecord{Account: account, Balances: account.Balances()})
+}
+
+// RecordKLineHistory journals the klines queried for preloading the indicators
+func (r *Recorder) RecordKLineHistory(symbol string, interval types.Interval, kLines []types.KLine) error {
+	return r.Record("", EventKLineHistory, kLineHistoryRecord{Symbol: symbol, Interval: interval, KLines: kLines})
+}
+
+// BindStream journals the events of the stream
+func (r *Recorder) BindStream(streamType StreamType, stream types.Stream) {
+	record := func(event EventType, data interface{}) {
+		if err := r.Record(streamType, event, data); err != nil {
+			r.logError(err)
+		}
+	}
+
+	stream.OnStart(func() { record(EventStart, nil) })
+	stream.OnConnect(func() { record(EventConnect, nil) })
+	stream.OnDisconnect(func() { record(EventDisconnect, nil) })
+	stream.OnAuth(func() { record(EventAuth, nil) })
+
+	if streamType == StreamMarket {
+		// the bars are not recorded since they are rebuilt from the replayed market trades
+		stream.OnKLine(func(k types.KLine) {
+			if !k.Interval.IsBar() {
+				record(EventKLine, k)
+			}
+		})
+		stream.OnKLineClosed(func(k types.KLine) {
+			if !k.Interval.IsBar() {
+				record(EventKLineClosed, k)
+			}
+		})
+		stream.OnBookSnapshot(func(book types.SliceOrderBook) { record(EventBookSnapshot, newBookRecord(book)) })
+		stream.OnBookUpdate(func(book types.SliceOrderBook) { record(EventBookUpdate, newBookRecord(book)) })
+		stream.OnBookTickerUpdate(func(bookTicker types.BookTicker) { record(EventBookTicker, bookTicker) })
+		stream.OnMarketTrade(func(trade types.Trade) { record(EventMarketTrade, trade) })
+		stream.OnAggTrade(func(trade types.Trade) { record(EventAggTrade, trade) })
+		return
+	}
+
+	stream.OnTradeUpdate(func(trade types.Trade) { record(EventTradeUpdate, trade) })
+	stream.OnOrderUpdate(func(order types.Order) { record(EventOrderUpdate, order) })
+	stream.OnBalanceSnapshot(func(balances types.BalanceMap) { record(EventBalanceSnapshot, balances) })
+	stream.OnBalanceUpdate(func(balances types.BalanceMap) { record(EventBalanceUpdate, balances) })
+}
+
+// logError logs the first write error only, since the stream callbacks keep failing with the same error
+func (r *Recorder) logError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loggedError {
+		return
+	}
+
+	r.loggedError = true
+	log.WithError(err).Errorf("[streamrecorder] unable to write the journal")
+}
+
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flush()
+}
+
+func (r *Recorder) flush() error {
+	if err := r.buf.Flush(); err != nil {
+		return err
+	}
+
+	if r.gz != nil {
+		return r.gz.Flush()
+	}
+
+	return nil
+}
+
+// Close stops the flush worker, flushes the buffered records and closes the journal file
+func (r *Recorder) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closeC)
+	})
+	<-r.stoppedC
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.buf.Flush(); err != nil {
+		return err
+	}
+
+	if r.gz != nil {
+		if err := r.gz.Close(); err != nil {
+			return err
+		}
+	}
+
+	if r.file != nil {
+		return r.file.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package streamrecorder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func recordTestSession(t *testing.T, recorder *Recorder, startTime time.Time) {
+	now := startTime
+	recorder.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	require.NoError(t, recorder.RecordSession(SessionInfo{Name: "binance", Exchange: types.ExchangeBinance, StartTime: startTime}))
+	require.NoError(t, recorder.RecordMarkets(types.MarketMap{
+		"BTCUSDT": {Exchange: types.ExchangeBinance, Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+	}))
+
+	account := types.NewAccount()
+	account.UpdateBalances(types.BalanceMap{"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(1000)}})
+	require.NoError(t, recorder.RecordAccount(account))
+
+	require.NoError(t, recorder.RecordKLineHistory("BTCUSDT", types.Interval1m, []types.KLine{
+		{Exchange: types.ExchangeBinance, Symbol: "BTCUSDT", Interval: types.Interval1m, StartTime: types.Time(startTime.Add(-2 * time.Minute)), EndTime: types.Time(startTime.Add(-time.Minute - time.Millisecond)), Close: fixedpoint.NewFromInt(29000)},
+		{Exchange: types.ExchangeBinance, Symbol: "BTCUSDT", Interval: types.Interval1m, StartTime: types.Time(startTime.Add(-time.Minute)), EndTime: types.Time(startTime.Add(-time.Millisecond)), Close: fixedpoint.NewFromInt(29500)},
+	}))
+
+	marketStream := types.NewStandardStream()
+	userStream := types.NewStandardStream()
+	recorder.BindStream(StreamMarket, &marketStream)
+	recorder.BindStream(StreamUser, &userStream)
+
+	marketStream.EmitConnect()
+	marketStream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Time:   startTime,
+		Bids:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromInt(30000), Volume: fixedpoint.One}},
+		Asks:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromInt(30010), Volume: fixedpoint.One}},
+	})
+	marketStream.EmitKLineClosed(types.KLine{
+		Exchange:  types.ExchangeBinance,
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1m,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+		Close:     fixedpoint.NewFromInt(30005),
+	})
+
+	userStream.EmitAuth()
+	userStream.EmitOrderUpdate(types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.NewFromInt(30000),
+			Quantity: fixedpoint.One,
+		},
+		Exchange: types.ExchangeBinance,
+		OrderID:  123,
+		Status:   types.OrderStatusNew,
+	})
+	userStream.EmitBalanceUpdate(types.BalanceMap{"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(500)}})
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "binance.jsonl.gz")
+
+	recorder, err := Create(path)
+	require.NoError(t, err)
+	recordTestSession(t, recorder, startTime)
+	require.NoError(t, recorder.Close())
+
+	replayer, err := LoadReplayer(path)
+	require.NoError(t, err)
+	assert.Equal(t, "binance", replayer.Session.Name)
+	assert.Equal(t, startTime, replayer.StartTime().UTC())
+
+	ctx := context.Background()
+	ex := replayer.Exchange
+
+	markets, err := ex.QueryMarkets(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, markets, "BTCUSDT")
+
+	balances, err := ex.QueryAccountBalances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", balances["USDT"].Available.String())
+
+	kLines, err := ex.QueryKLines(ctx, "BTCUSDT", types.Interval1m, types.KLineQueryOptions{EndTime: &startTime, Limit: 1000})
+	require.NoError(t, err)
+	require.Len(t, kLines, 2)
+
+	// the submitted order matching the recorded order gets the recorded order id
+	order, err := ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromInt(30000),
+		Quantity: fixedpoint.One,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(123), order.OrderID)
+
+	order, err = ex.SubmitOrder(ctx, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    fixedpoint.NewFromInt(30000),
+		Quantity: fixedpoint.One,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, uint64(123), order.OrderID)
+
+	var events []string
+	var book types.SliceOrderBook
+	replayer.MarketDataStream.OnConnect(func() { events = append(events, "connect") })
+	replayer.MarketDataStream.OnBookSnapshot(func(b types.SliceOrderBook) {
+		events = append(events, "bookSnapshot")
+		book = b
+	})
+	replayer.MarketDataStream.OnKLineClosed(func(k types.KLine) { events = append(events, "klineClosed") })
+	replayer.UserDataStream.OnAuth(func() { events = append(events, "auth") })
+	replayer.UserDataStream.OnOrderUpdate(func(o types.Order) { events = append(events, "orderUpdate") })
+	replayer.UserDataStream.OnBalanceUpdate(func(b types.BalanceMap) { events = append(events, "balanceUpdate") })
+
+	require.NoError(t, replayer.Replay(ctx))
+	assert.Equal(t, []string{"connect", "bookSnapshot", "klineClosed", "auth", "orderUpdate", "balanceUpdate"}, events)
+
+	require.Len(t, book.Bids, 1)
+	assert.Equal(t, "30000", book.Bids[0].Price.String())
+	assert.Equal(t, "30010", book.Asks[0].Price.String())
+
+	// the replayed klines are served by the exchange
+	ticker, err := ex.QueryTicker(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "30005", ticker.Last.String())
+
+	kLines, err = ex.QueryKLines(ctx, "BTCUSDT", types.Interval1m, types.KLineQueryOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, kLines, 2)
+	assert.Equal(t, "30005", kLines[1].Close.String())
+}
+
+func TestReplayAll(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newReplayer := func(name string, offset time.Duration) *Replayer {
+		path := filepath.Join(t.TempDir(), name+".jsonl")
+		recorder, err := Create(path)
+		require.NoError(t, err)
+
+		now := startTime.Add(offset)
+		recorder.now = func() time.Time {
+			now = now.Add(2 * time.Second)
+			return now
+		}
+
+		require.NoError(t, recorder.RecordSession(SessionInfo{Name: name, Exchange: types.ExchangeBinance, StartTime: startTime}))
+
+		stream := types.NewStandardStream()
+		recorder.BindStream(StreamMarket, &stream)
+		stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT", Exchange: types.ExchangeBinance, Side: types.SideTypeBuy})
+		stream.EmitMarketTrade(types.Trade{Symbol: "BTCUSDT", Exchange: types.ExchangeBinance, Side: types.SideTypeBuy})
+		require.NoError(t, recorder.Close())
+
+		replayer, err := LoadReplayer(path)
+		require.NoError(t, err)
+		return replayer
+	}
+
+	a := newReplayer("a", 0)
+	b := newReplayer("b", time.Second)
+
+	var sessions []string
+	a.MarketDataStream.OnMarketTrade(func(trade types.Trade) { sessions = append(sessions, "a") })
+	b.MarketDataStream.OnMarketTrade(func(trade types.Trade) { sessions = append(sessions, "b") })
+
+	require.NoError(t, ReplayAll(context.Background(), 0, a, b))
+	assert.Equal(t, []string{"a", "b", "a", "b"}, sessions)
+}
+
+func TestRecorder_FlushWorker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	recorder, err := Create(path)
+	require.NoError(t, err)
+
+	// a lone record on a quiet stream is flushed without a following record
+	require.NoError(t, recorder.RecordSession(SessionInfo{Name: "binance", Exchange: types.ExchangeBinance}))
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && strings.Contains(string(data), `"binance"`)
+	}, 3*flushInterval, 10*time.Millisecond)
+
+	require.NoError(t, recorder.Close())
+	select {
+	case <-recorder.stoppedC:
+	default:
+		t.Fatal("the flush worker is not stopped by Close")
+	}
+}
@@ -0,0 +1,251 @@
+package streamrecorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// ReplayStream is a fake stream fed by the replayer, connecting the stream does nothing
+type ReplayStream struct {
+	*types.StandardStream
+}
+
+func NewReplayStream() *ReplayStream {
+	stream := types.NewStandardStream()
+	return &ReplayStream{StandardStream: &stream}
+}
+
+func (s *ReplayStream) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (s *ReplayStream) Reconnect() {}
+
+func (s *ReplayStream) Close() error {
+	return nil
+}
+
+// Replayer feeds the journal of a session back through the replay streams and the stub exchange
+type Replayer struct {
+	Session SessionInfo
+
+	Exchange         *Exchange
+	MarketDataStream *ReplayStream
+	UserDataStream   *ReplayStream
+
+	// Speed is the replay speed relative to the recorded time, e.g., 1.0 for the real time,
+	// zero replays the events as fast as possible. It's used by Replay, ReplayAll takes the speed argument.
+	Speed float64
+
+	records []Record
+}
+
+// NewReplayer loads the header records into the stub exchange and keeps the stream events for replaying
+func NewReplayer(records []Record) (*Replayer, error) {
+	r := &Replayer{
+		MarketDataStream: NewReplayStream(),
+		UserDataStream:   NewReplayStream(),
+	}
+
+	var hasSession bool
+	var events []Record
+	for _, record := range records {
+		switch record.Event {
+		case EventSession:
+			if err := json.Unmarshal(record.Data, &r.Session); err != nil {
+				return nil, err
+			}
+			hasSession = true
+
+		case EventMarkets, EventAccount, EventKLineHistory:
+			// handled by the exchange below
+
+		default:
+			events = append(events, record)
+		}
+	}
+
+	if !hasSession {
+		return nil, fmt.Errorf("the journal has no session record")
+	}
+
+	r.MarketDataStream.SetPublicOnly()
+	r.Exchange = newExchange(r.Session)
+	for _, record := range records {
+		if err := r.Exchange.load(record); err != nil {
+			return nil, err
+		}
+	}
+
+	r.records = events
+	return r, nil
+}
+
+// LoadReplayer creates the replayer of the journal file
+func LoadReplayer(path string) (*Replayer, error) {
+	records, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayer(records)
+}
+
+// StartTime returns the session start time of the recorded run
+func (r *Replayer) StartTime() time.Time {
+	return r.Session.StartTime
+}
+
+// Replay emits the recorded events in order until the end of the journal or the context is canceled
+func (r *Replayer) Replay(ctx context.Context) error {
+	return ReplayAll(ctx, r.Speed, r)
+}
+
+// ReplayAll replays the journals of multiple sessions, the events of the sessions are interleaved by the recorded time
+func ReplayAll(ctx context.Context, speed float64, replayers ...*Replayer) error {
+	cursors := make([]int, len(replayers))
+
+	var lastTime time.Time
+	for {
+		// pick the earliest event of the sessions
+		next := -1
+		for i, r := range replayers {
+			if cursors[i] >= len(r.records) {
+				continue
+			}
+
+			if next < 0 || r.records[cursors[i]].Time.Before(replayers[next].records[cursors[next]].Time) {
+				next = i
+			}
+		}
+
+		if next < 0 {
+			return nil
+		}
+
+		r := replayers[next]
+		record := r.records[cursors[next]]
+		cursors[next]++
+
+		if speed > 0 && !lastTime.IsZero() {
+			if delay := time.Duration(float64(record.Time.Sub(lastTime)) / speed); delay > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(delay):
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		lastTime = record.Time
+		if err := r.dispatch(record); err != nil {
+			return fmt.Errorf("unable to replay the %s event of session %s at %s: %w", record.Event, r.Session.Name, record.Time, err)
+		}
+	}
+}
+
+func (r *Replayer) dispatch(record Record) error {
+	stream := r.MarketDataStream
+	if record.Stream == StreamUser {
+		stream = r.UserDataStream
+	}
+
+	r.Exchange.setTime(record.Time)
+
+	switch record.Event {
+	case EventStart:
+		stream.EmitStart()
+
+	case EventConnect:
+		stream.EmitConnect()
+
+	case EventDisconnect:
+		stream.EmitDisconnect()
+
+	case EventAuth:
+		stream.EmitAuth()
+
+	case EventKLine, EventKLineClosed:
+		var k types.KLine
+		if err := json.Unmarshal(record.Data, &k); err != nil {
+			return err
+		}
+
+		if record.Event == EventKLine {
+			stream.EmitKLine(k)
+		} else {
+			r.Exchange.addKLine(k)
+			stream.EmitKLineClosed(k)
+		}
+
+	case EventBookSnapshot, EventBookUpdate:
+		var book bookRecord
+		if err := json.Unmarshal(record.Data, &book); err != nil {
+			return err
+		}
+
+		if record.Event == EventBookSnapshot {
+			stream.EmitBookSnapshot(book.SliceOrderBook())
+		} else {
+			stream.EmitBookUpdate(book.SliceOrderBook())
+		}
+
+	case EventBookTicker:
+		var bookTicker types.BookTicker
+		if err := json.Unmarshal(record.Data, &bookTicker); err != nil {
+			return err
+		}
+		stream.EmitBookTickerUpdate(bookTicker)
+
+	case EventMarketTrade, EventAggTrade, EventTradeUpdate:
+		var trade types.Trade
+		if err := json.Unmarshal(record.Data, &trade); err != nil {
+			return err
+		}
+
+		switch record.Event {
+		case EventMarketTrade:
+			r.Exchange.setLastPrice(trade.Symbol, trade.Price)
+			stream.EmitMarketTrade(trade)
+		case EventAggTrade:
+			r.Exchange.setLastPrice(trade.Symbol, trade.Price)
+			stream.EmitAggTrade(trade)
+		default:
+			stream.EmitTradeUpdate(trade)
+		}
+
+	case EventOrderUpdate:
+		var order types.Order
+		if err := json.Unmarshal(record.Data, &order); err != nil {
+			return err
+		}
+		stream.EmitOrderUpdate(order)
+
+	case EventBalanceSnapshot, EventBalanceUpdate:
+		var balances types.BalanceMap
+		if err := json.Unmarshal(record.Data, &balances); err != nil {
+			return err
+		}
+
+		if record.Event == EventBalanceSnapshot {
+			stream.EmitBalanceSnapshot(balances)
+		} else {
+			stream.EmitBalanceUpdate(balances)
+		}
+
+	default:
+		return fmt.Errorf("unknown event type %s", record.Event)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// Package conformance checks the exchange adapters against the contracts which the strategies and the sync services
+// rely on. The scenarios only query the exchange, they never submit or cancel orders, so the suite can run against the
+// recorded fixtures in the tests as well as the real exchanges.
+package conformance
+
+import (
+	"context"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const (
+	defaultLimit         = 100
+	defaultStreamTimeout = 5 * time.Second
+)
+
+type StreamConfig struct {
+	// Messages are pushed to the stream once the first connection subscribes the channels
+	Messages []string
+
+	// Timeout is the max waiting time of each stream event, default 5s
+	Timeout time.Duration
+}
+
+type Config struct {
+	// Symbol is the global symbol used by the market data and the history scenarios
+	Symbol string
+
+	// Interval is the k line interval, default 1m
+	Interval types.Interval
+
+	// StartTime and EndTime is the time range of the history queries, default the last 7 days
+	StartTime, EndTime time.Time
+
+	// Limit is the limit of the k line and the trade queries, default 100
+	Limit int
+
+	// Contracts are the contracts to check, all the contracts are checked if it's empty
+	Contracts []Contract
+
+	// Stream enables the stream scenario, the stream is connected to a local websocket server instead of the exchange
+	Stream *StreamConfig
+}
+
+func (c *Config) defaults() {
+	if c.Interval == "" {
+		c.Interval = types.Interval1m
+	}
+
+	if c.EndTime.IsZero() {
+		c.EndTime = time.Now()
+	}
+
+	if c.StartTime.IsZero() {
+		c.StartTime = c.EndTime.Add(-7 * 24 * time.Hour)
+	}
+
+	if c.Limit <= 0 {
+		c.Limit = defaultLimit
+	}
+
+	if c.Stream != nil && c.Stream.Timeout <= 0 {
+		c.Stream.Timeout = defaultStreamTimeout
+	}
+}
+
+// env is shared by the scenarios, the later scenarios use the results of the previous ones.
+type env struct {
+	exchange types.Exchange
+	config   Config
+	reporter *reporter
+
+	markets      types.MarketMap
+	closedOrders []types.Order
+}
+
+type scenario struct {
+	contracts []Contract
+	run       func(ctx context.Context, e *env)
+}
+
+var scenarios = []scenario{
+	{[]Contract{ContractMarkets}, checkMarkets},
+	{[]Contract{ContractTicker}, checkTicker},
+	{[]Contract{ContractKLineOrder, ContractKLineRange}, checkKLines},
+	{[]Contract{ContractBalances}, checkBalances},
+	{[]Contract{ContractClosedOrderOrder, ContractClosedOrderFields, ContractClosedOrderPagination, ContractOrderTrades}, checkClosedOrders},
+	{[]Contract{ContractTradeOrder, ContractTradeIDOrder, ContractTradeFields, ContractTradeFee, ContractTradePagination}, checkTrades},
+	{[]Contract{ContractOrderTrades}, checkOrderTrades},
+	{[]Contract{ContractStreamConnect, ContractStreamReconnect, ContractStreamEvents}, checkStream},
+}
+
+// Run runs the scenarios against the exchange and reports the result of each contract.
+func Run(ctx context.Context, exchange types.Exchange, config Config) *Report {
+	config.defaults()
+
+	e := &env{
+		exchange: exchange,
+		config:   config,
+		reporter: newReporter(config.Contracts),
+	}
+
+	for _, s := range scenarios {
+		if !e.reporter.anyEnabled(s.contracts...) {
+			continue
+		}
+		s.run(ctx, e)
+	}
+
+	return e.reporter.report(exchange.Name())
+}
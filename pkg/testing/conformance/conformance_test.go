@@ -0,0 +1,75 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestRun(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ex := mocks.NewMockExchange(mockCtrl)
+	ex.EXPECT().Name().Return(types.ExchangeBinance).AnyTimes()
+	ex.EXPECT().QueryMarkets(gomock.Any()).Return(types.MarketMap{
+		"BTCUSDT": {
+			Exchange:      types.ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+			TickSize:      fixedpoint.NewFromFloat(0.01),
+			StepSize:      fixedpoint.NewFromFloat(0.0001),
+		},
+		// the market is keyed by the local symbol
+		"ETH-USDT": {
+			Exchange:      types.ExchangeBinance,
+			Symbol:        "ETHUSDT",
+			BaseCurrency:  "ETH",
+			QuoteCurrency: "USDT",
+			TickSize:      fixedpoint.NewFromFloat(0.01),
+			StepSize:      fixedpoint.NewFromFloat(0.0001),
+		},
+	}, nil)
+	ex.EXPECT().QueryTicker(gomock.Any(), "BTCUSDT").Return(&types.Ticker{
+		Last: fixedpoint.NewFromFloat(60000),
+		Buy:  fixedpoint.NewFromFloat(59999),
+		Sell: fixedpoint.NewFromFloat(60001),
+	}, nil)
+	ex.EXPECT().QueryTickers(gomock.Any(), "BTCUSDT").Return(map[string]types.Ticker{
+		"BTCUSDT": {Last: fixedpoint.NewFromFloat(60000)},
+	}, nil)
+
+	report := Run(context.Background(), ex, Config{
+		Symbol:    "BTCUSDT",
+		Contracts: []Contract{ContractMarkets, ContractTicker, ContractClosedOrderOrder, ContractStreamConnect},
+	})
+
+	assert.Equal(t, types.ExchangeBinance, report.Exchange)
+	assert.Len(t, report.Results, 4)
+	assert.Equal(t, []Contract{ContractMarkets}, report.Violated())
+
+	result, ok := report.Result(ContractMarkets)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"market ETHUSDT is keyed by ETH-USDT"}, result.Violations)
+
+	result, ok = report.Result(ContractTicker)
+	assert.True(t, ok)
+	assert.Equal(t, StatusPass, result.Status)
+
+	// the mock exchange doesn't implement the trade history service
+	result, ok = report.Result(ContractClosedOrderOrder)
+	assert.True(t, ok)
+	assert.Equal(t, StatusSkip, result.Status)
+
+	result, ok = report.Result(ContractStreamConnect)
+	assert.True(t, ok)
+	assert.Equal(t, StatusSkip, result.Status)
+	assert.Equal(t, "the stream scenario is not enabled", result.Reason)
+}
@@ -0,0 +1,66 @@
+package conformance
+
+// Contract is a guarantee of the exchange adapters which the strategies, the batch queries and the sync services rely on.
+type Contract string
+
+const (
+	// ContractMarkets requires the markets are keyed by the global symbol with the valid precisions and filters.
+	ContractMarkets Contract = "markets"
+	// ContractTicker requires the ticker has a positive last price and the best bid is not greater than the best ask.
+	ContractTicker Contract = "ticker"
+	// ContractKLineOrder requires the k lines are in ascending order without duplicates.
+	ContractKLineOrder Contract = "klines.order"
+	// ContractKLineRange requires the k lines are in the requested time range and don't exceed the limit.
+	ContractKLineRange Contract = "klines.range"
+	// ContractBalances requires the balances are keyed by the currency without negative amounts.
+	ContractBalances Contract = "balances"
+	// ContractClosedOrderOrder requires the closed orders are in ascending order by the creation time, the batch
+	// query moves the time window forward by the creation time of the last order.
+	ContractClosedOrderOrder Contract = "closed_orders.order"
+	// ContractClosedOrderFields requires the closed orders are finished and belong to the queried symbol and time range.
+	ContractClosedOrderFields Contract = "closed_orders.fields"
+	// ContractClosedOrderPagination requires the orders before the lastOrderID are not returned again.
+	ContractClosedOrderPagination Contract = "closed_orders.pagination"
+	// ContractTradeOrder requires the trades are in ascending order by the trade time without duplicated ids.
+	ContractTradeOrder Contract = "trades.order"
+	// ContractTradeIDOrder requires the trade ids increase with the trade time, the LastTradeID pagination relies on it.
+	ContractTradeIDOrder Contract = "trades.id_order"
+	// ContractTradeFields requires the trades belong to the queried symbol with the consistent price and quantities.
+	ContractTradeFields Contract = "trades.fields"
+	// ContractTradeFee requires the fee currency is set when the fee is charged, and it's one of the base currency, the
+	// quote currency and the platform fee currency.
+	ContractTradeFee Contract = "trades.fee"
+	// ContractTradePagination requires the trades before the LastTradeID are not returned again.
+	ContractTradePagination Contract = "trades.pagination"
+	// ContractOrderTrades requires the trades of an order can be queried by the decimal order id, which is how the
+	// strategies query the order trades.
+	ContractOrderTrades Contract = "order_trades"
+	// ContractStreamConnect requires the stream subscribes the channels once it's connected.
+	ContractStreamConnect Contract = "stream.connect"
+	// ContractStreamReconnect requires the stream reconnects and subscribes the channels again after the connection is
+	// dropped.
+	ContractStreamReconnect Contract = "stream.reconnect"
+	// ContractStreamEvents requires the stream emits the market data events of the subscribed symbol.
+	ContractStreamEvents Contract = "stream.events"
+)
+
+// AllContracts is the list of the contracts in the checking order.
+var AllContracts = []Contract{
+	ContractMarkets,
+	ContractTicker,
+	ContractKLineOrder,
+	ContractKLineRange,
+	ContractBalances,
+	ContractClosedOrderOrder,
+	ContractClosedOrderFields,
+	ContractClosedOrderPagination,
+	ContractTradeOrder,
+	ContractTradeIDOrder,
+	ContractTradeFields,
+	ContractTradeFee,
+	ContractTradePagination,
+	ContractOrderTrades,
+	ContractStreamConnect,
+	ContractStreamReconnect,
+	ContractStreamEvents,
+}
@@ -0,0 +1,165 @@
+package conformance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+type Result struct {
+	Contract Contract
+	Status   Status
+
+	// Violations are the details of the broken guarantees
+	Violations []string
+
+	// Reason is the reason why the contract is skipped
+	Reason string
+}
+
+// Report is the conformance result of an exchange, the results are in the order of AllContracts.
+type Report struct {
+	Exchange types.ExchangeName
+	Results  []Result
+}
+
+func (r *Report) Result(contract Contract) (Result, bool) {
+	for _, result := range r.Results {
+		if result.Contract == contract {
+			return result, true
+		}
+	}
+	return Result{}, false
+}
+
+// Violated returns the contracts which are broken by the exchange.
+func (r *Report) Violated() (contracts []Contract) {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			contracts = append(contracts, result.Contract)
+		}
+	}
+	return contracts
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("conformance report of %s:\n", r.Exchange))
+	for _, result := range r.Results {
+		switch result.Status {
+		case StatusPass:
+			sb.WriteString(fmt.Sprintf("✅ %s\n", result.Contract))
+
+		case StatusSkip:
+			sb.WriteString(fmt.Sprintf("⏭️ %s: %s\n", result.Contract, result.Reason))
+
+		case StatusFail:
+			sb.WriteString(fmt.Sprintf("❌ %s\n", result.Contract))
+			for _, v := range result.Violations {
+				sb.WriteString(fmt.Sprintf("    - %s\n", v))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// maxViolations is the max number of the recorded violations of a contract, the rest are dropped to keep the report
+// readable.
+const maxViolations = 10
+
+// reporter collects the violations of the contracts during the scenarios.
+type reporter struct {
+	enabled map[Contract]bool
+	results map[Contract]*Result
+}
+
+func newReporter(contracts []Contract) *reporter {
+	if len(contracts) == 0 {
+		contracts = AllContracts
+	}
+
+	enabled := make(map[Contract]bool, len(contracts))
+	for _, c := range contracts {
+		enabled[c] = true
+	}
+
+	return &reporter{
+		enabled: enabled,
+		results: map[Contract]*Result{},
+	}
+}
+
+// anyEnabled returns true if one of the contracts is enabled.
+func (r *reporter) anyEnabled(contracts ...Contract) bool {
+	for _, c := range contracts {
+		if r.enabled[c] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reporter) result(contract Contract) *Result {
+	result, ok := r.results[contract]
+	if !ok {
+		result = &Result{Contract: contract, Status: StatusPass}
+		r.results[contract] = result
+	}
+	return result
+}
+
+// checked marks the contract as checked, it passes unless any violation is reported.
+func (r *reporter) checked(contract Contract) {
+	r.result(contract)
+}
+
+func (r *reporter) violate(contract Contract, format string, args ...interface{}) {
+	result := r.result(contract)
+	result.Status = StatusFail
+	if len(result.Violations) < maxViolations {
+		result.Violations = append(result.Violations, fmt.Sprintf(format, args...))
+	}
+}
+
+// check reports the violation if the condition is false.
+func (r *reporter) check(contract Contract, cond bool, format string, args ...interface{}) {
+	if cond {
+		r.checked(contract)
+		return
+	}
+	r.violate(contract, format, args...)
+}
+
+func (r *reporter) skip(reason string, contracts ...Contract) {
+	for _, c := range contracts {
+		if _, ok := r.results[c]; ok {
+			continue
+		}
+		r.results[c] = &Result{Contract: c, Status: StatusSkip, Reason: reason}
+	}
+}
+
+func (r *reporter) report(exchange types.ExchangeName) *Report {
+	report := &Report{Exchange: exchange}
+	for _, c := range AllContracts {
+		if !r.enabled[c] {
+			continue
+		}
+
+		if result, ok := r.results[c]; ok {
+			report.Results = append(report.Results, *result)
+		} else {
+			report.Results = append(report.Results, Result{Contract: c, Status: StatusSkip, Reason: "not checked"})
+		}
+	}
+	return report
+}
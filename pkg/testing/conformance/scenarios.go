@@ -0,0 +1,305 @@
+package conformance
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// quoteQuantityTolerance is the tolerance of the difference between the quote quantity and price * quantity
+var quoteQuantityTolerance = fixedpoint.NewFromFloat(0.001)
+
+func checkMarkets(ctx context.Context, e *env) {
+	r := e.reporter
+	markets, err := e.exchange.QueryMarkets(ctx)
+	if err != nil {
+		r.violate(ContractMarkets, "failed to query markets: %v", err)
+		return
+	}
+
+	e.markets = markets
+	_, ok := markets[e.config.Symbol]
+	r.check(ContractMarkets, ok, "market %s is not found", e.config.Symbol)
+
+	for symbol, m := range markets {
+		r.check(ContractMarkets, symbol == m.Symbol, "market %s is keyed by %s", m.Symbol, symbol)
+		r.check(ContractMarkets, m.Exchange == e.exchange.Name(), "unexpected exchange %s of market %s", m.Exchange, symbol)
+		r.check(ContractMarkets, m.BaseCurrency != "" && m.QuoteCurrency != "", "empty base or quote currency of market %s", symbol)
+		r.check(ContractMarkets, m.TickSize.Sign() > 0, "non-positive tick size %s of market %s", m.TickSize, symbol)
+		r.check(ContractMarkets, m.StepSize.Sign() > 0, "non-positive step size %s of market %s", m.StepSize, symbol)
+	}
+}
+
+func checkTicker(ctx context.Context, e *env) {
+	r := e.reporter
+	ticker, err := e.exchange.QueryTicker(ctx, e.config.Symbol)
+	if err != nil {
+		r.violate(ContractTicker, "failed to query ticker: %v", err)
+		return
+	}
+
+	r.check(ContractTicker, ticker.Last.Sign() > 0, "non-positive last price %s", ticker.Last)
+	if !ticker.Buy.IsZero() && !ticker.Sell.IsZero() {
+		r.check(ContractTicker, ticker.Buy.Compare(ticker.Sell) <= 0, "crossed ticker, buy %s > sell %s", ticker.Buy, ticker.Sell)
+	}
+
+	tickers, err := e.exchange.QueryTickers(ctx, e.config.Symbol)
+	if err != nil {
+		r.violate(ContractTicker, "failed to query tickers: %v", err)
+		return
+	}
+
+	_, ok := tickers[e.config.Symbol]
+	r.check(ContractTicker, ok, "tickers are not keyed by the global symbol %s", e.config.Symbol)
+}
+
+func checkKLines(ctx context.Context, e *env) {
+	r := e.reporter
+	c := e.config
+	startTime, endTime := c.StartTime, c.EndTime
+	kLines, err := e.exchange.QueryKLines(ctx, c.Symbol, c.Interval, types.KLineQueryOptions{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+		Limit:     c.Limit,
+	})
+	if err != nil {
+		r.violate(ContractKLineOrder, "failed to query klines: %v", err)
+		r.skip("failed to query klines", ContractKLineRange)
+		return
+	}
+
+	if len(kLines) == 0 {
+		r.skip("no klines in the time range", ContractKLineOrder, ContractKLineRange)
+		return
+	}
+
+	r.check(ContractKLineRange, len(kLines) <= c.Limit, "%d klines exceed the limit %d", len(kLines), c.Limit)
+	rangeStart := c.StartTime.Truncate(c.Interval.Duration())
+	for i, k := range kLines {
+		if i > 0 {
+			prev := kLines[i-1]
+			r.check(ContractKLineOrder, prev.StartTime.Before(k.StartTime.Time()),
+				"kline #%d starts at %s, which is not after the previous one %s", i, k.StartTime, prev.StartTime)
+		}
+
+		r.check(ContractKLineRange, k.Symbol == c.Symbol && k.Interval == c.Interval,
+			"unexpected symbol %s or interval %s of kline #%d", k.Symbol, k.Interval, i)
+		r.check(ContractKLineRange, !k.StartTime.Before(rangeStart) && !k.StartTime.After(c.EndTime),
+			"kline #%d starts at %s, which is out of the range [%s, %s]", i, k.StartTime, rangeStart, c.EndTime)
+	}
+}
+
+func checkBalances(ctx context.Context, e *env) {
+	r := e.reporter
+	balances, err := e.exchange.QueryAccountBalances(ctx)
+	if err != nil {
+		r.violate(ContractBalances, "failed to query balances: %v", err)
+		return
+	}
+
+	r.checked(ContractBalances)
+	for currency, b := range balances {
+		r.check(ContractBalances, currency == b.Currency, "balance %s is keyed by %s", b.Currency, currency)
+		r.check(ContractBalances, b.Available.Sign() >= 0 && b.Locked.Sign() >= 0,
+			"negative balance of %s, available: %s, locked: %s", currency, b.Available, b.Locked)
+	}
+}
+
+func checkClosedOrders(ctx context.Context, e *env) {
+	r := e.reporter
+	c := e.config
+	service, ok := e.exchange.(types.ExchangeTradeHistoryService)
+	if !ok {
+		r.skip("types.ExchangeTradeHistoryService is not implemented",
+			ContractClosedOrderOrder, ContractClosedOrderFields, ContractClosedOrderPagination)
+		return
+	}
+
+	orders, err := service.QueryClosedOrders(ctx, c.Symbol, c.StartTime, c.EndTime, 0)
+	if err != nil {
+		r.violate(ContractClosedOrderOrder, "failed to query closed orders: %v", err)
+		r.skip("failed to query closed orders", ContractClosedOrderFields, ContractClosedOrderPagination)
+		return
+	}
+
+	if len(orders) == 0 {
+		r.skip("no closed orders in the time range",
+			ContractClosedOrderOrder, ContractClosedOrderFields, ContractClosedOrderPagination)
+		return
+	}
+
+	e.closedOrders = orders
+
+	var lastOrderID uint64
+	for i, o := range orders {
+		if i > 0 {
+			prev := orders[i-1]
+			r.check(ContractClosedOrderOrder, !o.CreationTime.Before(prev.CreationTime.Time()),
+				"order #%d is created at %s, which is before the previous one %s", i, o.CreationTime, prev.CreationTime)
+		}
+
+		r.check(ContractClosedOrderFields, o.Symbol == c.Symbol && o.Exchange == e.exchange.Name(),
+			"unexpected symbol %s or exchange %s of order %d", o.Symbol, o.Exchange, o.OrderID)
+		r.check(ContractClosedOrderFields, !o.IsWorking && o.Status != types.OrderStatusNew && o.Status != types.OrderStatusPartiallyFilled,
+			"order %d is still working, status: %s", o.OrderID, o.Status)
+		r.check(ContractClosedOrderFields, !o.CreationTime.Before(c.StartTime) && !o.CreationTime.After(c.EndTime),
+			"order %d is created at %s, which is out of the range [%s, %s]", o.OrderID, o.CreationTime, c.StartTime, c.EndTime)
+
+		if o.OrderID > lastOrderID {
+			lastOrderID = o.OrderID
+		}
+	}
+
+	next, err := service.QueryClosedOrders(ctx, c.Symbol, c.StartTime, c.EndTime, lastOrderID)
+	if err != nil {
+		r.violate(ContractClosedOrderPagination, "failed to query closed orders with the last order id %d: %v", lastOrderID, err)
+		return
+	}
+
+	r.checked(ContractClosedOrderPagination)
+	for _, o := range next {
+		r.check(ContractClosedOrderPagination, o.OrderID > lastOrderID,
+			"order %d is returned again with the last order id %d", o.OrderID, lastOrderID)
+	}
+}
+
+func checkTrades(ctx context.Context, e *env) {
+	r := e.reporter
+	c := e.config
+	contracts := []Contract{ContractTradeOrder, ContractTradeIDOrder, ContractTradeFields, ContractTradeFee, ContractTradePagination}
+	service, ok := e.exchange.(types.ExchangeTradeHistoryService)
+	if !ok {
+		r.skip("types.ExchangeTradeHistoryService is not implemented", contracts...)
+		return
+	}
+
+	startTime, endTime := c.StartTime, c.EndTime
+	trades, err := service.QueryTrades(ctx, c.Symbol, &types.TradeQueryOptions{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+		Limit:     int64(c.Limit),
+	})
+	if err != nil {
+		r.violate(ContractTradeOrder, "failed to query trades: %v", err)
+		r.skip("failed to query trades", contracts...)
+		return
+	}
+
+	if len(trades) == 0 {
+		r.skip("no trades in the time range", contracts...)
+		return
+	}
+
+	// the fee can be charged in the platform token besides the currencies of the market
+	feeCurrencies := map[string]bool{}
+	if platformCurrency := e.exchange.PlatformFeeCurrency(); platformCurrency != "" {
+		feeCurrencies[platformCurrency] = true
+	}
+	market, hasMarket := e.markets[c.Symbol]
+	if hasMarket {
+		feeCurrencies[market.BaseCurrency] = true
+		feeCurrencies[market.QuoteCurrency] = true
+	}
+
+	ids := map[uint64]struct{}{}
+	var lastTradeID uint64
+	for i, t := range trades {
+		if i > 0 {
+			prev := trades[i-1]
+			r.check(ContractTradeOrder, !t.Time.Before(prev.Time.Time()),
+				"trade #%d is at %s, which is before the previous one %s", i, t.Time, prev.Time)
+			if prev.Time.Before(t.Time.Time()) {
+				r.check(ContractTradeIDOrder, prev.ID < t.ID,
+					"trade %d is after trade %d, but the id is not greater", t.ID, prev.ID)
+			} else {
+				r.checked(ContractTradeIDOrder)
+			}
+		}
+
+		_, duplicated := ids[t.ID]
+		r.check(ContractTradeOrder, !duplicated, "trade %d is duplicated", t.ID)
+		ids[t.ID] = struct{}{}
+
+		r.check(ContractTradeFields, t.Symbol == c.Symbol && t.Exchange == e.exchange.Name(),
+			"unexpected symbol %s or exchange %s of trade %d", t.Symbol, t.Exchange, t.ID)
+		r.check(ContractTradeFields, t.Price.Sign() > 0 && t.Quantity.Sign() > 0,
+			"non-positive price %s or quantity %s of trade %d", t.Price, t.Quantity, t.ID)
+		r.check(ContractTradeFields, t.IsBuyer == (t.Side == types.SideTypeBuy),
+			"inconsistent side %s and buyer flag %v of trade %d", t.Side, t.IsBuyer, t.ID)
+
+		quote := t.Price.Mul(t.Quantity)
+		r.check(ContractTradeFields, t.QuoteQuantity.Sub(quote).Abs().Compare(quote.Mul(quoteQuantityTolerance)) <= 0,
+			"quote quantity %s of trade %d doesn't match price * quantity %s", t.QuoteQuantity, t.ID, quote)
+
+		if t.Fee.IsZero() {
+			r.checked(ContractTradeFee)
+		} else {
+			r.check(ContractTradeFee, t.FeeCurrency != "", "empty fee currency of trade %d with fee %s", t.ID, t.Fee)
+			if hasMarket && t.FeeCurrency != "" {
+				r.check(ContractTradeFee, feeCurrencies[t.FeeCurrency],
+					"unexpected fee currency %s of trade %d", t.FeeCurrency, t.ID)
+			}
+		}
+
+		if t.ID > lastTradeID {
+			lastTradeID = t.ID
+		}
+	}
+
+	next, err := service.QueryTrades(ctx, c.Symbol, &types.TradeQueryOptions{
+		StartTime:   &startTime,
+		EndTime:     &endTime,
+		Limit:       int64(c.Limit),
+		LastTradeID: lastTradeID,
+	})
+	if err != nil {
+		r.violate(ContractTradePagination, "failed to query trades with the last trade id %d: %v", lastTradeID, err)
+		return
+	}
+
+	// some exchanges include the trade of the last trade id, it's deduplicated by the sync service
+	r.checked(ContractTradePagination)
+	for _, t := range next {
+		r.check(ContractTradePagination, t.ID >= lastTradeID,
+			"trade %d is returned again with the last trade id %d", t.ID, lastTradeID)
+	}
+}
+
+func checkOrderTrades(ctx context.Context, e *env) {
+	r := e.reporter
+	service, ok := e.exchange.(types.ExchangeOrderQueryService)
+	if !ok {
+		r.skip("types.ExchangeOrderQueryService is not implemented", ContractOrderTrades)
+		return
+	}
+
+	var order *types.Order
+	for i := range e.closedOrders {
+		if e.closedOrders[i].ExecutedQuantity.Sign() > 0 {
+			order = &e.closedOrders[i]
+			break
+		}
+	}
+
+	if order == nil {
+		r.skip("no filled orders in the time range", ContractOrderTrades)
+		return
+	}
+
+	trades, err := service.QueryOrderTrades(ctx, types.OrderQuery{
+		Symbol:  order.Symbol,
+		OrderID: strconv.FormatUint(order.OrderID, 10),
+	})
+	if err != nil {
+		r.violate(ContractOrderTrades, "failed to query the trades of order %d: %v", order.OrderID, err)
+		return
+	}
+
+	r.check(ContractOrderTrades, len(trades) > 0, "no trades of the filled order %d", order.OrderID)
+	for _, t := range trades {
+		r.check(ContractOrderTrades, t.OrderID == order.OrderID,
+			"trade %d belongs to order %d instead of order %d", t.ID, t.OrderID, order.OrderID)
+	}
+}
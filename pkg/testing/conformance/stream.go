@@ -0,0 +1,219 @@
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// streamReconnectCoolDown shortens the reconnect waiting period of the stream
+const streamReconnectCoolDown = 100 * time.Millisecond
+
+type endpointSetter interface {
+	SetEndpointCreator(creator types.EndpointCreator)
+}
+
+type reconnectCoolDownSetter interface {
+	SetReconnectCoolDownPeriod(period time.Duration)
+}
+
+// streamServer is a local websocket server, it records the messages of each connection and pushes the configured
+// messages to the first connection once it receives the first message.
+type streamServer struct {
+	*httptest.Server
+
+	messages []string
+
+	mu    sync.Mutex
+	conns []*serverConn
+	connC chan *serverConn
+}
+
+type serverConn struct {
+	conn *websocket.Conn
+
+	mu       sync.Mutex
+	received []string
+	// receivedC is notified once a message is received
+	receivedC chan struct{}
+}
+
+func (c *serverConn) numReceived() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.received)
+}
+
+func newStreamServer(messages []string) *streamServer {
+	s := &streamServer{
+		messages: messages,
+		connC:    make(chan *serverConn, 10),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *streamServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.Server.URL, "http")
+}
+
+func (s *streamServer) handle(w http.ResponseWriter, req *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	c := &serverConn{conn: conn, receivedC: make(chan struct{}, 100)}
+	s.conns = append(s.conns, c)
+	first := len(s.conns) == 1
+	s.mu.Unlock()
+	s.connC <- c
+
+	for {
+		mt, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if mt != websocket.TextMessage {
+			continue
+		}
+
+		c.mu.Lock()
+		c.received = append(c.received, string(message))
+		n := len(c.received)
+		c.mu.Unlock()
+
+		if first && n == 1 {
+			for _, m := range s.messages {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(m)); err != nil {
+					return
+				}
+			}
+		}
+
+		select {
+		case c.receivedC <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// waitConn waits for the next connection.
+func (s *streamServer) waitConn(timeout time.Duration) *serverConn {
+	select {
+	case c := <-s.connC:
+		return c
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+// waitReceived waits until the connection receives any message, and then waits a while for the rest messages.
+func (c *serverConn) waitReceived(timeout time.Duration) int {
+	select {
+	case <-c.receivedC:
+	case <-time.After(timeout):
+		return 0
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	return c.numReceived()
+}
+
+func checkStream(ctx context.Context, e *env) {
+	r := e.reporter
+	c := e.config
+	contracts := []Contract{ContractStreamConnect, ContractStreamReconnect, ContractStreamEvents}
+	if c.Stream == nil {
+		r.skip("the stream scenario is not enabled", contracts...)
+		return
+	}
+
+	stream := e.exchange.NewStream()
+	setter, ok := stream.(endpointSetter)
+	if !ok {
+		r.skip("the endpoint of the stream can not be replaced", contracts...)
+		return
+	}
+
+	server := newStreamServer(c.Stream.Messages)
+	defer server.Close()
+
+	setter.SetEndpointCreator(func(ctx context.Context) (string, error) {
+		return server.URL(), nil
+	})
+	if s, ok := stream.(reconnectCoolDownSetter); ok {
+		s.SetReconnectCoolDownPeriod(streamReconnectCoolDown)
+	}
+
+	var mu sync.Mutex
+	var symbols []string
+	onEvent := func(symbol string) {
+		mu.Lock()
+		symbols = append(symbols, symbol)
+		mu.Unlock()
+	}
+	stream.OnBookSnapshot(func(book types.SliceOrderBook) { onEvent(book.Symbol) })
+	stream.OnBookUpdate(func(book types.SliceOrderBook) { onEvent(book.Symbol) })
+	stream.OnMarketTrade(func(trade types.Trade) { onEvent(trade.Symbol) })
+
+	stream.SetPublicOnly()
+	stream.Subscribe(types.BookChannel, c.Symbol, types.SubscribeOptions{Depth: types.DepthLevel5})
+	stream.Subscribe(types.MarketTradeChannel, c.Symbol, types.SubscribeOptions{})
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := stream.Connect(streamCtx); err != nil {
+		r.violate(ContractStreamConnect, "failed to connect: %v", err)
+		r.skip("failed to connect", ContractStreamReconnect, ContractStreamEvents)
+		return
+	}
+	defer func() { _ = stream.Close() }()
+
+	first := server.waitConn(c.Stream.Timeout)
+	if first == nil {
+		r.violate(ContractStreamConnect, "the stream is not connected to the server")
+		r.skip("the stream is not connected", ContractStreamReconnect, ContractStreamEvents)
+		return
+	}
+
+	subscriptions := first.waitReceived(c.Stream.Timeout)
+	r.check(ContractStreamConnect, subscriptions > 0, "no subscription is sent once the stream is connected")
+
+	if len(c.Stream.Messages) == 0 {
+		r.skip("no stream messages are configured", ContractStreamEvents)
+	} else {
+		mu.Lock()
+		received := append([]string(nil), symbols...)
+		mu.Unlock()
+
+		r.check(ContractStreamEvents, len(received) > 0, "no market data event is emitted")
+		for _, symbol := range received {
+			r.check(ContractStreamEvents, symbol == c.Symbol, "unexpected symbol %s of the event", symbol)
+		}
+	}
+
+	// drop the connection without the close frame
+	_ = first.conn.UnderlyingConn().Close()
+
+	second := server.waitConn(c.Stream.Timeout)
+	if second == nil {
+		r.violate(ContractStreamReconnect, "the stream doesn't reconnect in %s after the connection is dropped", c.Stream.Timeout)
+		return
+	}
+
+	resubscriptions := second.waitReceived(c.Stream.Timeout)
+	r.check(ContractStreamReconnect, resubscriptions >= subscriptions,
+		"%d messages are sent after reconnecting, expected at least %d subscriptions", resubscriptions, subscriptions)
+}
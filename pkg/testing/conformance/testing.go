@@ -0,0 +1,32 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// RunTest runs the conformance suite in the test. The known violations are the contracts which the exchange is
+// expected to break, the test fails if any other contract is broken or a known violation passes.
+func RunTest(t *testing.T, exchange types.Exchange, config Config, knownViolations ...Contract) *Report {
+	report := Run(context.Background(), exchange, config)
+	t.Log(report.String())
+
+	known := map[Contract]bool{}
+	for _, c := range knownViolations {
+		known[c] = true
+	}
+
+	for _, result := range report.Results {
+		switch {
+		case result.Status == StatusFail && !known[result.Contract]:
+			t.Errorf("contract %s is violated: %v", result.Contract, result.Violations)
+
+		case result.Status == StatusPass && known[result.Contract]:
+			t.Errorf("contract %s is expected to be violated but passes, remove it from the known violations", result.Contract)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,245 @@
+// Package golden checks the indicators against the reference outputs of TA-Lib and pandas-ta.
+//
+// The kline fixture and the reference outputs are generated once by testdata/generate.py and committed,
+// the indicator tests of each package define the cases and run them with RunTest.
+//
+// The committed reference outputs were calculated by the python fallback of generate.py, which follows the
+// algorithms of TA-Lib and pandas-ta, since the libraries were not available when they were generated.
+// Regenerate them with TA-Lib and pandas-ta installed, and review the known deviations of the cases.
+package golden
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Symbol is the symbol of the fixture klines
+const Symbol = "BTCUSDT"
+
+// Interval is the interval of the fixture klines
+var Interval = types.Interval1m
+
+// Case is an indicator checked against a reference output
+type Case struct {
+	Name string
+
+	// Reference is the column name of the reference output
+	Reference string
+
+	// Skip is the number of the reference values not compared after the reference warm-up, e.g., the placeholder
+	// value of the reference at the end of its warm-up. The seeding differences must not be skipped, they are
+	// known deviations, or the indicator is compared with the reference of the same seeding.
+	Skip int
+
+	// Tolerance is the max relative error, defaults to DefaultTolerance
+	Tolerance float64
+
+	// KnownDeviation explains why the indicator is expected to deviate from the reference.
+	// The case fails if the indicator matches the reference, so that the known deviation can be removed.
+	KnownDeviation string
+
+	// Calculate returns the indicator values aligned to the klines, NaN if there is no value for the kline
+	Calculate func(kLines []types.KLine) []float64
+}
+
+const DefaultTolerance = 1e-9
+
+// Mismatch is a value different from the reference
+type Mismatch struct {
+	Index     int
+	Value     float64
+	Reference float64
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("#%d: %v, expected %v", m.Index, m.Value, m.Reference)
+}
+
+// Collect pushes the klines one by one and collects the last value of the series after each push,
+// NaN is collected if the series is not updated by the kline.
+func Collect(kLines []types.KLine, push func(k types.KLine), series interface {
+	Last(i int) float64
+	Length() int
+}) []float64 {
+	values := make([]float64, len(kLines))
+	for i, k := range kLines {
+		length := series.Length()
+		push(k)
+
+		if series.Length() > length {
+			values[i] = series.Last(0)
+		} else {
+			values[i] = math.NaN()
+		}
+	}
+	return values
+}
+
+// Compare compares the values with the reference after the reference warm-up and the skipped values.
+// A missing value where the reference has one is a mismatch, so a longer warm-up is caught as well.
+func Compare(values, reference []float64, skip int, tolerance float64) (mismatches []Mismatch) {
+	start := 0
+	for start < len(reference) && math.IsNaN(reference[start]) {
+		start++
+	}
+
+	for i := start + skip; i < len(reference); i++ {
+		expected := reference[i]
+		if math.IsNaN(expected) {
+			continue
+		}
+
+		var value = math.NaN()
+		if i < len(values) {
+			value = values[i]
+		}
+
+		if math.IsNaN(value) || math.Abs(value-expected) > tolerance*math.Max(1.0, math.Abs(expected)) {
+			mismatches = append(mismatches, Mismatch{Index: i, Value: value, Reference: expected})
+		}
+	}
+
+	return mismatches
+}
+
+// RunTest runs the cases against the reference outputs
+func RunTest(t *testing.T, cases []Case) {
+	kLines, err := LoadKLines()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reference, err := LoadReference()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			expected, ok := reference[c.Reference]
+			if !ok {
+				t.Fatalf("reference %s not found", c.Reference)
+			}
+
+			tolerance := c.Tolerance
+			if tolerance == 0 {
+				tolerance = DefaultTolerance
+			}
+
+			mismatches := Compare(c.Calculate(kLines), expected, c.Skip, tolerance)
+			switch {
+			case len(mismatches) > 0 && c.KnownDeviation == "":
+				t.Errorf("%d values deviate from %s, the first one is %s", len(mismatches), c.Reference, mismatches[0])
+
+			case len(mismatches) == 0 && c.KnownDeviation != "":
+				t.Errorf("the known deviation from %s is gone, remove it: %s", c.Reference, c.KnownDeviation)
+
+			case len(mismatches) > 0:
+				t.Logf("known deviation from %s: %s", c.Reference, c.KnownDeviation)
+			}
+		})
+	}
+}
+
+func testdataDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}
+
+func readCSV(name string) ([][]string, error) {
+	f, err := os.Open(filepath.Join(testdataDir(), name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", name, err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+
+	return records, nil
+}
+
+// LoadKLines loads the closed klines of the fixture
+func LoadKLines() ([]types.KLine, error) {
+	records, err := readCSV("klines.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	var kLines []types.KLine
+	for _, record := range records[1:] {
+		if len(record) != 6 {
+			return nil, fmt.Errorf("unexpected kline record: %v", record)
+		}
+
+		startTime, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		var values [5]fixedpoint.Value
+		for i := range values {
+			if values[i], err = fixedpoint.NewFromString(record[i+1]); err != nil {
+				return nil, err
+			}
+		}
+
+		t := time.UnixMilli(startTime).UTC()
+		kLines = append(kLines, types.KLine{
+			Symbol:    Symbol,
+			Interval:  Interval,
+			StartTime: types.Time(t),
+			EndTime:   types.Time(t.Add(Interval.Duration() - time.Millisecond)),
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
+			Closed:    true,
+		})
+	}
+
+	return kLines, nil
+}
+
+// LoadReference loads the reference outputs by the column names, NaN is loaded for the missing values
+func LoadReference() (map[string][]float64, error) {
+	records, err := readCSV("reference.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	header := records[0]
+	reference := make(map[string][]float64, len(header))
+	for _, record := range records[1:] {
+		for i, name := range header[1:] {
+			value := math.NaN()
+			if cell := record[i+1]; cell != "" {
+				if value, err = strconv.ParseFloat(cell, 64); err != nil {
+					return nil, err
+				}
+			}
+
+			reference[name] = append(reference[name], value)
+		}
+	}
+
+	return reference, nil
+}
@@ -0,0 +1,40 @@
+package golden
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	nan := math.NaN()
+	reference := []float64{nan, nan, 1.0, 2.0, 3.0, 4.0}
+
+	assert.Empty(t, Compare([]float64{0, 0, 1.0, 2.0, 3.0, 4.0}, reference, 0, DefaultTolerance))
+
+	// the longer warm-up is a mismatch
+	mismatches := Compare([]float64{nan, nan, nan, 2.0, 3.0, 4.0}, reference, 0, DefaultTolerance)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, 2, mismatches[0].Index)
+
+	// the skipped values are not compared
+	assert.Empty(t, Compare([]float64{nan, nan, 0.5, 1.5, 3.0, 4.0}, reference, 2, DefaultTolerance))
+
+	// the tolerance is relative to the reference
+	assert.Empty(t, Compare([]float64{nan, nan, 1.0, 2.0, 3.0, 4.0001}, reference, 0, 1e-4))
+	assert.Len(t, Compare([]float64{nan, nan, 1.0, 2.0, 3.0, 4.001}, reference, 0, 1e-4), 1)
+}
+
+func TestLoad(t *testing.T) {
+	kLines, err := LoadKLines()
+	require.NoError(t, err)
+
+	reference, err := LoadReference()
+	require.NoError(t, err)
+
+	for name, values := range reference {
+		assert.Len(t, values, len(kLines), name)
+	}
+}
@@ -0,0 +1,790 @@
+#!/usr/bin/env python3
+"""
+Generates the kline fixture and the reference indicator outputs of the golden tests.
+
+    pip install TA-Lib pandas-ta
+    python3 pkg/testing/golden/testdata/generate.py
+
+The reference outputs come from 3 sources:
+
+  * TA-Lib (talib module), the columns without prefix, e.g., ema_20 is seeded with the SMA of the first window.
+  * pandas-ta (pandas_ta module) with the talib backend disabled, the columns with the pta_ prefix. The EMAs are
+    calculated with presma=False, so they are seeded with the first value like the bbgo indicators.
+  * the formulas of the indicators which are not implemented by TA-Lib or pandas-ta, the columns with the fml_
+    prefix, e.g., the Parkinson volatility.
+
+Without TA-Lib or pandas-ta, the script falls back to the pure python implementations below, which follow the
+algorithms of the libraries (lookback, seeding, smoothing and the pandas ewm adjustment), and prints a warning.
+The fallback is not a substitute for the libraries: the committed reference.csv must be regenerated with both
+of them installed, and the known deviations of the golden cases must be reviewed after regenerating.
+
+The outputs are written with full precision, an empty cell means the reference has no value (warm-up).
+"""
+
+import csv
+import math
+import os
+import random
+import sys
+
+try:
+    import talib
+    import numpy as np
+except ImportError:
+    talib = None
+
+try:
+    import pandas as pd
+    import pandas_ta
+
+    # use the pandas-ta algorithms instead of delegating to TA-Lib
+    pandas_ta.Imports["talib"] = False
+except ImportError:
+    pandas_ta = None
+
+NUM_OF_KLINES = 300
+START_TIME = 1672531200000  # 2023-01-01T00:00:00Z
+INTERVAL = 60 * 1000
+
+NaN = float("nan")
+
+
+def generate_klines():
+    rnd = random.Random(42)
+    klines = []
+    price = 100.0
+    for i in range(NUM_OF_KLINES):
+        open_price = price
+        price = max(1.0, price * (1.0 + rnd.gauss(0.0, 0.01)) + 5.0 * math.sin(i / 20.0) * 0.1)
+        high = max(open_price, price) * (1.0 + rnd.random() * 0.005)
+        low = min(open_price, price) * (1.0 - rnd.random() * 0.005)
+        volume = 100.0 + rnd.random() * 1000.0
+        klines.append({
+            "start_time": START_TIME + i * INTERVAL,
+            "open": round(open_price, 8),
+            "high": round(high, 8),
+            "low": round(low, 8),
+            "close": round(price, 8),
+            "volume": round(volume, 8),
+        })
+    return klines
+
+
+def sma(xs, n):
+    out = [NaN] * len(xs)
+    for i in range(len(xs)):
+        window = xs[i - n + 1:i + 1] if i >= n - 1 else []
+        if len(window) == n and not any(math.isnan(x) for x in window):
+            out[i] = sum(window) / n
+    return out
+
+
+def ema(xs, n):
+    """TA-Lib EMA, seeded with the SMA of the first n valid values"""
+    out = [NaN] * len(xs)
+    k = 2.0 / (n + 1)
+    start = next(i for i, x in enumerate(xs) if not math.isnan(x))
+    seed = start + n - 1
+    if seed >= len(xs):
+        return out
+    out[seed] = sum(xs[start:seed + 1]) / n
+    for i in range(seed + 1, len(xs)):
+        out[i] = out[i - 1] + k * (xs[i] - out[i - 1])
+    return out
+
+
+def dema(xs, n):
+    e1 = ema(xs, n)
+    e2 = ema(e1, n)
+    return [2 * a - b for a, b in zip(e1, e2)]
+
+
+def tema(xs, n):
+    e1 = ema(xs, n)
+    e2 = ema(e1, n)
+    e3 = ema(e2, n)
+    return [3 * a - 3 * b + c for a, b, c in zip(e1, e2, e3)]
+
+
+def trima(xs, n):
+    if n % 2 == 1:
+        a = b = (n + 1) // 2
+    else:
+        a, b = n // 2, n // 2 + 1
+    return sma(sma(xs, a), b)
+
+
+def stddev(xs, n):
+    """TA-Lib STDDEV, the population standard deviation"""
+    out = [NaN] * len(xs)
+    for i in range(n - 1, len(xs)):
+        window = xs[i - n + 1:i + 1]
+        mean = sum(window) / n
+        out[i] = math.sqrt(sum((x - mean) ** 2 for x in window) / n)
+    return out
+
+
+def rsi(xs, n):
+    """TA-Lib RSI with the Wilder smoothing"""
+    out = [NaN] * len(xs)
+    gains = [max(xs[i] - xs[i - 1], 0.0) for i in range(1, n + 1)]
+    losses = [max(xs[i - 1] - xs[i], 0.0) for i in range(1, n + 1)]
+    avg_gain, avg_loss = sum(gains) / n, sum(losses) / n
+    out[n] = 100.0 * avg_gain / (avg_gain + avg_loss)
+    for i in range(n + 1, len(xs)):
+        change = xs[i] - xs[i - 1]
+        avg_gain = (avg_gain * (n - 1) + max(change, 0.0)) / n
+        avg_loss = (avg_loss * (n - 1) + max(-change, 0.0)) / n
+        out[i] = 100.0 * avg_gain / (avg_gain + avg_loss)
+    return out
+
+
+def true_range(highs, lows, closes):
+    tr = [NaN]
+    for i in range(1, len(closes)):
+        tr.append(max(highs[i] - lows[i], abs(highs[i] - closes[i - 1]), abs(lows[i] - closes[i - 1])))
+    return tr
+
+
+def atr(highs, lows, closes, n):
+    """TA-Lib ATR, seeded with the SMA of the first n true ranges"""
+    tr = true_range(highs, lows, closes)
+    out = [NaN] * len(closes)
+    out[n] = sum(tr[1:n + 1]) / n
+    for i in range(n + 1, len(closes)):
+        out[i] = (out[i - 1] * (n - 1) + tr[i]) / n
+    return out
+
+
+def stochf(highs, lows, closes, k_period, d_period):
+    """TA-Lib STOCHF with the SMA fast D, both outputs start at the lookback of fast D"""
+    k = [NaN] * len(closes)
+    for i in range(k_period - 1, len(closes)):
+        highest = max(highs[i - k_period + 1:i + 1])
+        lowest = min(lows[i - k_period + 1:i + 1])
+        k[i] = 0.0 if highest == lowest else 100.0 * (closes[i] - lowest) / (highest - lowest)
+    d = sma(k, d_period)
+    lookback = k_period + d_period - 2
+    k = [NaN if i < lookback else v for i, v in enumerate(k)]
+    return k, d
+
+
+def cci(highs, lows, closes, n):
+    tp = [(h + l + c) / 3.0 for h, l, c in zip(highs, lows, closes)]
+    out = [NaN] * len(tp)
+    for i in range(n - 1, len(tp)):
+        window = tp[i - n + 1:i + 1]
+        mean = sum(window) / n
+        dev = sum(abs(x - mean) for x in window) / n
+        out[i] = 0.0 if dev == 0 else (tp[i] - mean) / (0.015 * dev)
+    return out
+
+
+def obv(closes, volumes):
+    out = [volumes[0]]
+    for i in range(1, len(closes)):
+        if closes[i] > closes[i - 1]:
+            out.append(out[-1] + volumes[i])
+        elif closes[i] < closes[i - 1]:
+            out.append(out[-1] - volumes[i])
+        else:
+            out.append(out[-1])
+    return out
+
+
+def ad(highs, lows, closes, volumes):
+    out = []
+    total = 0.0
+    for h, l, c, v in zip(highs, lows, closes, volumes):
+        if h > l:
+            total += ((c - l) - (h - c)) / (h - l) * v
+        out.append(total)
+    return out
+
+
+def dmi(highs, lows, closes, n):
+    """TA-Lib PLUS_DI, MINUS_DI and ADX"""
+    size = len(closes)
+    plus_di, minus_di, dx, adx = [NaN] * size, [NaN] * size, [NaN] * size, [NaN] * size
+
+    def directional_movements(i):
+        diff_p = highs[i] - highs[i - 1]
+        diff_m = lows[i - 1] - lows[i]
+        plus_dm = diff_p if diff_p > 0 and diff_p > diff_m else 0.0
+        minus_dm = diff_m if diff_m > 0 and diff_m > diff_p else 0.0
+        tr = max(highs[i] - lows[i], abs(highs[i] - closes[i - 1]), abs(lows[i] - closes[i - 1]))
+        return plus_dm, minus_dm, tr
+
+    sum_p = sum_m = sum_tr = 0.0
+    for i in range(1, n):
+        p, m, tr = directional_movements(i)
+        sum_p, sum_m, sum_tr = sum_p + p, sum_m + m, sum_tr + tr
+
+    for i in range(n, size):
+        p, m, tr = directional_movements(i)
+        sum_p = sum_p - sum_p / n + p
+        sum_m = sum_m - sum_m / n + m
+        sum_tr = sum_tr - sum_tr / n + tr
+        plus_di[i] = 100.0 * sum_p / sum_tr
+        minus_di[i] = 100.0 * sum_m / sum_tr
+        total = plus_di[i] + minus_di[i]
+        dx[i] = 0.0 if total == 0 else 100.0 * abs(plus_di[i] - minus_di[i]) / total
+
+    first = 2 * n - 1
+    adx[first] = sum(dx[n:first + 1]) / n
+    for i in range(first + 1, size):
+        adx[i] = (adx[i - 1] * (n - 1) + dx[i]) / n
+
+    return plus_di, minus_di, adx
+
+
+def linearreg_slope(xs, n):
+    """TA-Lib LINEARREG_SLOPE, x is the distance from the latest value"""
+    out = [NaN] * len(xs)
+    sum_x = n * (n - 1) * 0.5
+    sum_x_sqr = n * (n - 1) * (2 * n - 1) / 6.0
+    divisor = sum_x * sum_x - n * sum_x_sqr
+    for i in range(n - 1, len(xs)):
+        sum_xy = sum_y = 0.0
+        for j in range(n - 1, -1, -1):
+            sum_y += xs[i - j]
+            sum_xy += j * xs[i - j]
+        out[i] = (n * sum_xy - sum_x * sum_y) / divisor
+    return out
+
+
+def correl(xs, ys, n):
+    """TA-Lib CORREL, the Pearson correlation coefficient"""
+    out = [NaN] * len(xs)
+    for i in range(n - 1, len(xs)):
+        wx, wy = xs[i - n + 1:i + 1], ys[i - n + 1:i + 1]
+        sum_x, sum_y = sum(wx), sum(wy)
+        sum_xy = sum(x * y for x, y in zip(wx, wy))
+        sum_x2, sum_y2 = sum(x * x for x in wx), sum(y * y for y in wy)
+        temp = (sum_x2 - sum_x * sum_x / n) * (sum_y2 - sum_y * sum_y / n)
+        out[i] = (sum_xy - sum_x * sum_y / n) / math.sqrt(temp) if temp > 1e-14 else 0.0
+    return out
+
+
+def beta(xs, ys, n):
+    """TA-Lib BETA, the beta of the returns of ys against the returns of xs"""
+    out = [NaN] * len(xs)
+    rx = [NaN] + [(xs[i] - xs[i - 1]) / xs[i - 1] if xs[i - 1] != 0 else 0.0 for i in range(1, len(xs))]
+    ry = [NaN] + [(ys[i] - ys[i - 1]) / ys[i - 1] if ys[i - 1] != 0 else 0.0 for i in range(1, len(ys))]
+    for i in range(n, len(xs)):
+        wx, wy = rx[i - n + 1:i + 1], ry[i - n + 1:i + 1]
+        sum_x, sum_y = sum(wx), sum(wy)
+        sum_xx = sum(x * x for x in wx)
+        sum_xy = sum(x * y for x, y in zip(wx, wy))
+        temp = n * sum_xx - sum_x * sum_x
+        out[i] = (n * sum_xy - sum_x * sum_y) / temp if abs(temp) > 1e-14 else 0.0
+    return out
+
+
+def sar(highs, lows, acceleration, maximum):
+    """TA-Lib SAR, the initial direction is decided by the minus DM of the first 2 klines"""
+    out = [NaN] * len(highs)
+    diff_p = highs[1] - highs[0]
+    diff_m = lows[0] - lows[1]
+    is_long = not (diff_m > 0 and diff_p < diff_m)
+
+    af = acceleration
+    if is_long:
+        ep, value = highs[1], lows[0]
+    else:
+        ep, value = lows[1], highs[0]
+
+    new_high, new_low = highs[1], lows[1]
+    for i in range(1, len(highs)):
+        prev_high, prev_low = new_high, new_low
+        new_high, new_low = highs[i], lows[i]
+        if is_long:
+            if new_low <= value:
+                is_long = False
+                value = max(ep, prev_high, new_high)
+                out[i] = value
+                af, ep = acceleration, new_low
+                value = max(value + af * (ep - value), prev_high, new_high)
+            else:
+                out[i] = value
+                if new_high > ep:
+                    ep = new_high
+                    af = min(af + acceleration, maximum)
+                value = min(value + af * (ep - value), prev_low, new_low)
+        else:
+            if new_high >= value:
+                is_long = True
+                value = min(ep, prev_low, new_low)
+                out[i] = value
+                af, ep = acceleration, new_high
+                value = min(value + af * (ep - value), prev_low, new_low)
+            else:
+                out[i] = value
+                if new_low < ep:
+                    ep = new_low
+                    af = min(af + acceleration, maximum)
+                value = max(value + af * (ep - value), prev_high, new_high)
+    return out
+
+
+def ewm(xs, alpha, adjust, min_periods=0):
+    """pandas Series.ewm(alpha=alpha, adjust=adjust, min_periods=min_periods).mean(), the leading NaNs are skipped"""
+    out = [NaN] * len(xs)
+    mean, weight, count = NaN, 0.0, 0
+    for i, x in enumerate(xs):
+        if math.isnan(x):
+            if count > 0:
+                raise ValueError("NaN after the first valid value is not supported")
+            continue
+
+        if count == 0:
+            mean, weight = x, 1.0
+        elif adjust:
+            weight = weight * (1.0 - alpha) + 1.0
+            mean = mean + (x - mean) / weight
+        else:
+            mean = (1.0 - alpha) * mean + alpha * x
+
+        count += 1
+        if count >= min_periods:
+            out[i] = mean
+    return out
+
+
+def pta_ema(xs, n):
+    """pandas-ta ema with presma=False, seeded with the first valid value"""
+    return ewm(xs, 2.0 / (n + 1), False)
+
+
+def pta_rma(xs, n):
+    """pandas-ta rma, the adjusted ewm with min_periods"""
+    return ewm(xs, 1.0 / n, True, n)
+
+
+def pta_dema(xs, n):
+    e1 = pta_ema(xs, n)
+    e2 = pta_ema(e1, n)
+    return [2 * a - b for a, b in zip(e1, e2)]
+
+
+def pta_tema(xs, n):
+    e1 = pta_ema(xs, n)
+    e2 = pta_ema(e1, n)
+    e3 = pta_ema(e2, n)
+    return [3 * a - 3 * b + c for a, b, c in zip(e1, e2, e3)]
+
+
+def pta_t3(xs, n, a):
+    e1 = pta_ema(xs, n)
+    e2 = pta_ema(e1, n)
+    e3 = pta_ema(e2, n)
+    e4 = pta_ema(e3, n)
+    e5 = pta_ema(e4, n)
+    e6 = pta_ema(e5, n)
+    c1 = -a * a * a
+    c2 = 3 * a * a + 3 * a * a * a
+    c3 = -6 * a * a - 3 * a - 3 * a * a * a
+    c4 = a * a * a + 3 * a * a + 3 * a + 1
+    return [c1 * v6 + c2 * v5 + c3 * v4 + c4 * v3 for v3, v4, v5, v6 in zip(e3, e4, e5, e6)]
+
+
+def pta_macd(xs, fast, slow, signal):
+    """pandas-ta macd with presma=False, the signal line is the EMA of the valid MACD values"""
+    line = [a - b for a, b in zip(pta_ema(xs, fast), pta_ema(xs, slow))]
+    sig = pta_ema(line, signal)
+    hist = [a - b for a, b in zip(line, sig)]
+    return line, sig, hist
+
+
+def pta_atr(highs, lows, closes, n):
+    return pta_rma(true_range(highs, lows, closes), n)
+
+
+def pta_adx(highs, lows, closes, n):
+    """pandas-ta adx, the DMs and the DX are smoothed by rma"""
+    size = len(closes)
+    pos, neg = [NaN] * size, [NaN] * size
+    for i in range(1, size):
+        up = highs[i] - highs[i - 1]
+        dn = lows[i - 1] - lows[i]
+        pos[i] = up if up > dn and up > 0 else 0.0
+        neg[i] = dn if dn > up and dn > 0 else 0.0
+
+    atr_ = pta_atr(highs, lows, closes, n)
+    dmp = [100.0 / a * p for a, p in zip(atr_, pta_rma(pos, n))]
+    dmn = [100.0 / a * m for a, m in zip(atr_, pta_rma(neg, n))]
+    dx = [100.0 * abs(p - m) / (p + m) for p, m in zip(dmp, dmn)]
+    return pta_rma(dx, n), dmp, dmn
+
+
+def pta_zlma(xs, n):
+    lag = int(0.5 * (n - 1))
+    return pta_ema([2 * xs[i] - xs[i - lag] if i >= lag else NaN for i in range(len(xs))], n)
+
+
+def pta_tsi(xs, fast, slow):
+    diff = [NaN] + [xs[i] - xs[i - 1] for i in range(1, len(xs))]
+    fast_slow = pta_ema(pta_ema(diff, slow), fast)
+    abs_fast_slow = pta_ema(pta_ema([abs(d) for d in diff], slow), fast)
+    return [100.0 * a / b for a, b in zip(fast_slow, abs_fast_slow)]
+
+
+def pta_eom(highs, lows, volumes, n, divisor):
+    size = len(highs)
+    values = [NaN] * size
+    for i in range(1, size):
+        distance = (highs[i] + lows[i]) / 2.0 - (highs[i - 1] + lows[i - 1]) / 2.0
+        box_ratio = volumes[i] / divisor / (highs[i] - lows[i])
+        values[i] = distance / box_ratio
+    return sma(values, n)
+
+
+def pta_vwap(highs, lows, closes, volumes):
+    """pandas-ta vwap anchored by day, the fixture klines are in the same day"""
+    out = []
+    pv = vs = 0.0
+    for h, l, c, v in zip(highs, lows, closes, volumes):
+        pv += (h + l + c) / 3.0 * v
+        vs += v
+        out.append(pv / vs)
+    return out
+
+
+def pta_vwma(closes, volumes, n):
+    pv = sma([c * v for c, v in zip(closes, volumes)], n)
+    vs = sma(volumes, n)
+    return [a / b for a, b in zip(pv, vs)]
+
+
+def pta_alma(xs, n, sigma, offset):
+    """pandas-ta alma, the first weight is applied to the latest value"""
+    m = offset * (n - 1)
+    s = n / sigma
+    weights = [math.exp(-((i - m) ** 2) / (2 * s * s)) for i in range(n)]
+    total = sum(weights)
+    out = [NaN] * (n - 1) + [0.0]
+    for i in range(n, len(xs)):
+        out.append(sum(weights[j] * xs[i - j] for j in range(n)) / total)
+    return out
+
+
+def pta_wma(xs, n):
+    """pandas-ta wma, the latest value has the largest weight"""
+    out = [NaN] * len(xs)
+    total = n * (n + 1) / 2.0
+    for i in range(len(xs)):
+        window = xs[i - n + 1:i + 1] if i >= n - 1 else []
+        if len(window) == n and not any(math.isnan(x) for x in window):
+            out[i] = sum((j + 1) * x for j, x in enumerate(window)) / total
+    return out
+
+
+def pta_hma(xs, n):
+    half, sqrt_n = int(n / 2), int(math.sqrt(n))
+    diff = [2 * a - b for a, b in zip(pta_wma(xs, half), pta_wma(xs, n))]
+    return pta_wma(diff, sqrt_n)
+
+
+def pta_vidya(xs, n):
+    """pandas-ta vidya, seeded with 0 at the end of the first window and the zeros are replaced by NaN"""
+    size = len(xs)
+    alpha = 2.0 / (n + 1)
+    diff = [NaN] + [xs[i] - xs[i - 1] for i in range(1, size)]
+    out = [0.0] * size
+    for i in range(n, size):
+        window = diff[i - n + 1:i + 1]
+        positive = sum(d for d in window if d > 0)
+        negative = sum(-d for d in window if d < 0)
+        cmo = abs((positive - negative) / (positive + negative))
+        out[i] = alpha * cmo * xs[i] + out[i - 1] * (1 - alpha * cmo)
+    return [NaN if i < n - 1 or v == 0 else v for i, v in enumerate(out)]
+
+
+def pta_ssf(xs, n, poles):
+    """pandas-ta ssf, the first values are the source values"""
+    out = list(xs)
+    if poles == 3:
+        x = math.pi / n
+        a0 = math.exp(-x)
+        b0 = 2 * a0 * math.cos(math.sqrt(3) * x)
+        c0 = a0 * a0
+        c4 = c0 * c0
+        c3 = -c0 * (1 + b0)
+        c2 = c0 + b0
+        c1 = 1 - c2 - c3 - c4
+        for i in range(3, len(xs)):
+            out[i] = c1 * xs[i] + c2 * out[i - 1] + c3 * out[i - 2] + c4 * out[i - 3]
+    else:
+        x = math.pi * math.sqrt(2) / n
+        a0 = math.exp(-x)
+        a1 = -a0 * a0
+        b1 = 2 * a0 * math.cos(x)
+        c1 = 1 - a1 - b1
+        for i in range(2, len(xs)):
+            out[i] = c1 * xs[i] + b1 * out[i - 1] + a1 * out[i - 2]
+    return out
+
+
+def pta_kvo(highs, lows, closes, volumes, fast, slow):
+    """pandas-ta kvo, the volume signed by the change of the typical price, smoothed by the SMA seeded EMA"""
+    tp = [(h + l + c) / 3.0 for h, l, c in zip(highs, lows, closes)]
+    signed = [NaN]
+    for i in range(1, len(tp)):
+        change = tp[i] - tp[i - 1]
+        signed.append(volumes[i] * (1.0 if change > 0 else -1.0 if change < 0 else 0.0))
+    return [a - b for a, b in zip(ema(signed, fast), ema(signed, slow))]
+
+
+def pta_supertrend(highs, lows, closes, n, multiplier):
+    size = len(closes)
+    matr = [multiplier * a for a in pta_atr(highs, lows, closes, n)]
+    upper = [(h + l) / 2.0 + m for h, l, m in zip(highs, lows, matr)]
+    lower = [(h + l) / 2.0 - m for h, l, m in zip(highs, lows, matr)]
+    direction, trend = [1] * size, [NaN] * size
+    for i in range(1, size):
+        if closes[i] > upper[i - 1]:
+            direction[i] = 1
+        elif closes[i] < lower[i - 1]:
+            direction[i] = -1
+        else:
+            direction[i] = direction[i - 1]
+            if direction[i] > 0 and lower[i] < lower[i - 1]:
+                lower[i] = lower[i - 1]
+            if direction[i] < 0 and upper[i] > upper[i - 1]:
+                upper[i] = upper[i - 1]
+        trend[i] = lower[i] if direction[i] > 0 else upper[i]
+    return trend
+
+
+def pta_zscore(xs, n):
+    """pandas-ta zscore with ddof=0"""
+    mean, std = sma(xs, n), stddev(xs, n)
+    return [(x - m) / s for x, m, s in zip(xs, mean, std)]
+
+
+def pta_log_return(xs):
+    return [NaN] + [math.log(xs[i] / xs[i - 1]) for i in range(1, len(xs))]
+
+
+def fml_smma(xs, n):
+    """the Wilder smoothed moving average seeded with the SMA of the first window"""
+    out = [NaN] * len(xs)
+    out[n - 1] = sum(xs[:n]) / n
+    for i in range(n, len(xs)):
+        out[i] = (out[i - 1] * (n - 1) + xs[i]) / n
+    return out
+
+
+def fml_cma(xs):
+    out = []
+    total = 0.0
+    for i, x in enumerate(xs):
+        total += x
+        out.append(total / (i + 1))
+    return out
+
+
+def fml_gma(xs, n):
+    return [math.exp(v) for v in sma([math.log(x) for x in xs], n)]
+
+
+def fml_parkinson(highs, lows, n):
+    terms = [math.log(h / l) ** 2 for h, l in zip(highs, lows)]
+    return [math.sqrt(v / (4.0 * math.log(2.0))) for v in sma(terms, n)]
+
+
+def fml_garman_klass(opens, highs, lows, closes, n):
+    terms = [0.5 * math.log(h / l) ** 2 - (2.0 * math.log(2.0) - 1.0) * math.log(c / o) ** 2
+             for o, h, l, c in zip(opens, highs, lows, closes)]
+    return [math.sqrt(max(v, 0.0)) for v in sma(terms, n)]
+
+
+def fml_yang_zhang(opens, highs, lows, closes, n):
+    """the Yang-Zhang volatility with the sample variances, the first kline has no previous close"""
+    out = [NaN] * len(closes)
+    k = 0.34 / (1.34 + (n + 1) / (n - 1))
+
+    def variance(values):
+        mean = sum(values) / len(values)
+        return sum((v - mean) ** 2 for v in values) / (len(values) - 1)
+
+    for i in range(n, len(closes)):
+        window = range(i - n + 1, i + 1)
+        overnight = [math.log(opens[j] / closes[j - 1]) for j in window]
+        open_to_close = [math.log(closes[j] / opens[j]) for j in window]
+        rs = [math.log(highs[j] / closes[j]) * math.log(highs[j] / opens[j]) +
+              math.log(lows[j] / closes[j]) * math.log(lows[j] / opens[j]) for j in window]
+        out[i] = math.sqrt(max(variance(overnight) + k * variance(open_to_close) + (1 - k) * sum(rs) / n, 0.0))
+    return out
+
+
+def talib_references(h, l, c, v):
+    refs = {}
+    if talib is not None:
+        ha, la, ca, va = (np.array(x, dtype=float) for x in (h, l, c, v))
+        refs["sma_20"] = talib.SMA(ca, 20)
+        refs["ema_20"] = talib.EMA(ca, 20)
+        refs["dema_20"] = talib.DEMA(ca, 20)
+        refs["tema_20"] = talib.TEMA(ca, 20)
+        refs["trima_21"] = talib.TRIMA(ca, 21)
+        refs["stddev_20"] = talib.STDDEV(ca, 20, 1)
+        refs["rsi_14"] = talib.RSI(ca, 14)
+        refs["atr_14"] = talib.ATR(ha, la, ca, 14)
+        refs["natr_14"] = talib.NATR(ha, la, ca, 14)
+        up, mid, down = talib.BBANDS(ca, 20, 2, 2)
+        refs["bbands_20_upper"], refs["bbands_20_middle"], refs["bbands_20_lower"] = up, mid, down
+        refs["stochf_14_3_k"], refs["stochf_14_3_d"] = talib.STOCHF(ha, la, ca, 14, 3, 0)
+        refs["cci_20"] = talib.CCI(ha, la, ca, 20)
+        refs["obv"] = talib.OBV(ca, va)
+        refs["ad"] = talib.AD(ha, la, ca, va)
+        refs["plus_di_14"] = talib.PLUS_DI(ha, la, ca, 14)
+        refs["minus_di_14"] = talib.MINUS_DI(ha, la, ca, 14)
+        refs["adx_14"] = talib.ADX(ha, la, ca, 14)
+        refs["linearreg_slope_14"] = talib.LINEARREG_SLOPE(ca, 14)
+        refs["correl_20"] = talib.CORREL(ha, la, 20)
+        refs["beta_20"] = talib.BETA(ca, ha, 20)
+        refs["sar"] = talib.SAR(ha, la, 0.02, 0.2)
+        return {name: list(values) for name, values in refs.items()}
+
+    refs["sma_20"] = sma(c, 20)
+    refs["ema_20"] = ema(c, 20)
+    refs["dema_20"] = dema(c, 20)
+    refs["tema_20"] = tema(c, 20)
+    refs["trima_21"] = trima(c, 21)
+    refs["stddev_20"] = stddev(c, 20)
+    refs["rsi_14"] = rsi(c, 14)
+    refs["atr_14"] = atr(h, l, c, 14)
+    refs["natr_14"] = [a / x * 100.0 for a, x in zip(refs["atr_14"], c)]
+    mid, std = sma(c, 20), stddev(c, 20)
+    refs["bbands_20_upper"] = [m + 2 * s for m, s in zip(mid, std)]
+    refs["bbands_20_middle"] = mid
+    refs["bbands_20_lower"] = [m - 2 * s for m, s in zip(mid, std)]
+    refs["stochf_14_3_k"], refs["stochf_14_3_d"] = stochf(h, l, c, 14, 3)
+    refs["cci_20"] = cci(h, l, c, 20)
+    refs["obv"] = obv(c, v)
+    refs["ad"] = ad(h, l, c, v)
+    refs["plus_di_14"], refs["minus_di_14"], refs["adx_14"] = dmi(h, l, c, 14)
+    refs["linearreg_slope_14"] = linearreg_slope(c, 14)
+    refs["correl_20"] = correl(h, l, 20)
+    refs["beta_20"] = beta(c, h, 20)
+    refs["sar"] = sar(h, l, 0.02, 0.2)
+    return refs
+
+
+def pandas_ta_references(o, h, l, c, v):
+    refs = {}
+    if pandas_ta is not None:
+        index = pd.date_range(start=pd.Timestamp(START_TIME, unit="ms"), periods=len(c), freq="1min")
+        hs, ls, cs, vs = (pd.Series(x, index=index, dtype=float) for x in (h, l, c, v))
+        refs["pta_ema_20"] = pandas_ta.ema(cs, 20, presma=False)
+        refs["pta_dema_20"] = pandas_ta.dema(cs, 20, presma=False)
+        refs["pta_tema_20"] = pandas_ta.tema(cs, 20, presma=False)
+        refs["pta_t3_10"] = pandas_ta.t3(cs, 10, 0.7, presma=False)
+        macd_ = pandas_ta.macd(cs, 12, 26, 9, presma=False)
+        refs["pta_macd_12_26_9"] = macd_["MACD_12_26_9"]
+        refs["pta_macd_12_26_9_signal"] = macd_["MACDs_12_26_9"]
+        refs["pta_macd_12_26_9_hist"] = macd_["MACDh_12_26_9"]
+        refs["pta_rma_14"] = pandas_ta.rma(cs, 14)
+        refs["pta_atr_14"] = pandas_ta.atr(hs, ls, cs, 14, mamode="rma")
+        refs["pta_natr_14"] = pandas_ta.natr(hs, ls, cs, 14, mamode="rma")
+        adx_ = pandas_ta.adx(hs, ls, cs, 14)
+        refs["pta_adx_14"], refs["pta_dmp_14"], refs["pta_dmn_14"] = adx_["ADX_14"], adx_["DMP_14"], adx_["DMN_14"]
+        refs["pta_zlma_21"] = pandas_ta.zlma(cs, 21, presma=False)
+        refs["pta_tsi_13_25"] = pandas_ta.tsi(cs, 13, 25, presma=False).iloc[:, 0]
+        refs["pta_eom_14"] = pandas_ta.eom(hs, ls, cs, vs, 14, divisor=100000000)
+        refs["pta_vwap"] = pandas_ta.vwap(hs, ls, cs, vs, anchor="D")
+        refs["pta_vwma_20"] = pandas_ta.vwma(cs, vs, 20)
+        refs["pta_alma_10"] = pandas_ta.alma(cs, 10, 6.0, 0.85)
+        refs["pta_hma_16"] = pandas_ta.hma(cs, 16)
+        refs["pta_vidya_14"] = pandas_ta.vidya(cs, 14)
+        refs["pta_ssf_10_2"] = pandas_ta.ssf(cs, 10, 2)
+        refs["pta_ssf_10_3"] = pandas_ta.ssf(cs, 10, 3)
+        refs["pta_kvo_34_55"] = pandas_ta.kvo(hs, ls, cs, vs, 34, 55).iloc[:, 0]
+        refs["pta_supertrend_10_3"] = pandas_ta.supertrend(hs, ls, cs, 10, 3.0).iloc[:, 0]
+        refs["pta_zscore_20"] = pandas_ta.zscore(cs, 20, ddof=0)
+        refs["pta_log_return"] = pandas_ta.log_return(cs)
+        refs["pta_wwma_14"] = cs.ewm(alpha=1.0 / 14, adjust=False).mean()
+        return {name: list(values.to_numpy(dtype=float)) for name, values in refs.items()}
+
+    refs["pta_ema_20"] = pta_ema(c, 20)
+    refs["pta_dema_20"] = pta_dema(c, 20)
+    refs["pta_tema_20"] = pta_tema(c, 20)
+    refs["pta_t3_10"] = pta_t3(c, 10, 0.7)
+    refs["pta_macd_12_26_9"], refs["pta_macd_12_26_9_signal"], refs["pta_macd_12_26_9_hist"] = pta_macd(c, 12, 26, 9)
+    refs["pta_rma_14"] = pta_rma(c, 14)
+    refs["pta_atr_14"] = pta_atr(h, l, c, 14)
+    refs["pta_natr_14"] = [100.0 / x * a for a, x in zip(refs["pta_atr_14"], c)]
+    refs["pta_adx_14"], refs["pta_dmp_14"], refs["pta_dmn_14"] = pta_adx(h, l, c, 14)
+    refs["pta_zlma_21"] = pta_zlma(c, 21)
+    refs["pta_tsi_13_25"] = pta_tsi(c, 13, 25)
+    refs["pta_eom_14"] = pta_eom(h, l, v, 14, 100000000)
+    refs["pta_vwap"] = pta_vwap(h, l, c, v)
+    refs["pta_vwma_20"] = pta_vwma(c, v, 20)
+    refs["pta_alma_10"] = pta_alma(c, 10, 6.0, 0.85)
+    refs["pta_hma_16"] = pta_hma(c, 16)
+    refs["pta_vidya_14"] = pta_vidya(c, 14)
+    refs["pta_ssf_10_2"] = pta_ssf(c, 10, 2)
+    refs["pta_ssf_10_3"] = pta_ssf(c, 10, 3)
+    refs["pta_kvo_34_55"] = pta_kvo(h, l, c, v, 34, 55)
+    refs["pta_supertrend_10_3"] = pta_supertrend(h, l, c, 10, 3.0)
+    refs["pta_zscore_20"] = pta_zscore(c, 20)
+    refs["pta_log_return"] = pta_log_return(c)
+    refs["pta_wwma_14"] = ewm(c, 1.0 / 14, False)
+    return refs
+
+
+def formula_references(o, h, l, c):
+    return {
+        "fml_smma_14": fml_smma(c, 14),
+        "fml_cma": fml_cma(c),
+        "fml_gma_20": fml_gma(c, 20),
+        "fml_parkinson_20": fml_parkinson(h, l, 20),
+        "fml_garman_klass_20": fml_garman_klass(o, h, l, c, 20),
+        "fml_yang_zhang_20": fml_yang_zhang(o, h, l, c, 20),
+    }
+
+
+def references(klines):
+    o = [k["open"] for k in klines]
+    h = [k["high"] for k in klines]
+    l = [k["low"] for k in klines]
+    c = [k["close"] for k in klines]
+    v = [k["volume"] for k in klines]
+
+    for name, module in (("TA-Lib", talib), ("pandas-ta", pandas_ta)):
+        if module is None:
+            print(f"warning: {name} is not installed, the {name} references are calculated by the python fallback, "
+                  f"regenerate them with {name} installed before committing", file=sys.stderr)
+
+    refs = talib_references(h, l, c, v)
+    refs.update(pandas_ta_references(o, h, l, c, v))
+    refs.update(formula_references(o, h, l, c))
+    return refs
+
+
+def main():
+    directory = os.path.dirname(os.path.abspath(__file__))
+    klines = generate_klines()
+
+    with open(os.path.join(directory, "klines.csv"), "w", newline="") as f:
+        writer = csv.writer(f, lineterminator="\n")
+        writer.writerow(["start_time", "open", "high", "low", "close", "volume"])
+        for k in klines:
+            writer.writerow([k["start_time"], repr(k["open"]), repr(k["high"]), repr(k["low"]), repr(k["close"]),
+                             repr(k["volume"])])
+
+    refs = references(klines)
+    names = sorted(refs)
+    with open(os.path.join(directory, "reference.csv"), "w", newline="") as f:
+        writer = csv.writer(f, lineterminator="\n")
+        writer.writerow(["index"] + names)
+        for i in range(len(klines)):
+            row = [i]
+            for name in names:
+                value = float(refs[name][i])
+                row.append("" if math.isnan(value) else repr(value))
+            writer.writerow(row)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,132 @@
+package httptesting
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Recording is a recorded http response, the query string and the headers are not recorded since they usually contain
+// the credentials and the signatures.
+type Recording struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+// Recorder records the responses of the underlying transport, it's used to record the fixtures from the real server.
+type Recorder struct {
+	Transport http.RoundTripper
+
+	mu         sync.Mutex
+	recordings []Recording
+}
+
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{Transport: transport}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	r.recordings = append(r.recordings, Recording{
+		Method:     strings.ToUpper(req.Method),
+		Path:       req.URL.Path,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) Recordings() []Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Recording(nil), r.recordings...)
+}
+
+// Save writes the recordings into the file in JSON format.
+func (r *Recorder) Save(filename string) error {
+	out, err := json.MarshalIndent(r.Recordings(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, out, 0644)
+}
+
+func LoadRecordings(filename string) ([]Recording, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var recordings []Recording
+	if err := json.Unmarshal(content, &recordings); err != nil {
+		return nil, err
+	}
+
+	return recordings, nil
+}
+
+// Replay registers the recordings as the handlers. The recordings of the same method and path are replied in the
+// recorded order, and the last one is replied repeatedly once all of them are replied.
+func (transport *MockTransport) Replay(recordings []Recording) {
+	type key struct{ method, path string }
+
+	var keys []key
+	grouped := map[key][]Recording{}
+	for _, r := range recordings {
+		k := key{strings.ToUpper(r.Method), r.Path}
+		if _, ok := grouped[k]; !ok {
+			keys = append(keys, k)
+		}
+		grouped[k] = append(grouped[k], r)
+	}
+
+	for _, k := range keys {
+		var mu sync.Mutex
+		queue := grouped[k]
+		f := func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			r := queue[0]
+			if len(queue) > 1 {
+				queue = queue[1:]
+			}
+			mu.Unlock()
+
+			return BuildResponseString(r.StatusCode, r.Body), nil
+		}
+
+		switch k.method {
+		case "GET":
+			transport.GET(k.path, f)
+		case "POST":
+			transport.POST(k.path, f)
+		case "DELETE":
+			transport.DELETE(k.path, f)
+		case "PUT":
+			transport.PUT(k.path, f)
+		}
+	}
+}
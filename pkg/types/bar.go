@@ -0,0 +1,239 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// BarType is the sampling method of the information-driven bars, the bars are sampled by the trading activity
+// instead of the time.
+type BarType string
+
+const (
+	// BarTypeVolume closes a bar when the traded base volume reaches the threshold
+	BarTypeVolume BarType = "volume"
+
+	// BarTypeDollar closes a bar when the traded quote volume reaches the threshold
+	BarTypeDollar BarType = "dollar"
+
+	// BarTypeTick closes a bar when the number of trades reaches the threshold
+	BarTypeTick BarType = "tick"
+
+	// BarTypeImbalance closes a bar when the absolute taker buy volume minus the taker sell volume reaches the threshold
+	BarTypeImbalance BarType = "imbalance"
+)
+
+var barTypes = []BarType{BarTypeVolume, BarTypeDollar, BarTypeTick, BarTypeImbalance}
+
+// BarInterval returns the pseudo kline interval of the bars, e.g. "volume:100" for 100 base volume bars.
+// The bar intervals can be subscribed like the time intervals.
+func BarInterval(barType BarType, threshold fixedpoint.Value) Interval {
+	return Interval(string(barType) + ":" + threshold.String())
+}
+
+// ParseBarInterval parses the bar type and the threshold of the bar interval
+func ParseBarInterval(i Interval) (barType BarType, threshold fixedpoint.Value, err error) {
+	prefix, value, found := strings.Cut(string(i), ":")
+	if !found {
+		return "", fixedpoint.Zero, fmt.Errorf("%s is not a bar interval", i)
+	}
+
+	barType = BarType(prefix)
+	if !barType.IsValid() {
+		return "", fixedpoint.Zero, fmt.Errorf("%s has an invalid bar type %s", i, prefix)
+	}
+
+	threshold, err = fixedpoint.NewFromString(value)
+	if err != nil {
+		return "", fixedpoint.Zero, fmt.Errorf("%s has an invalid threshold: %w", i, err)
+	}
+
+	if threshold.Sign() <= 0 {
+		return "", fixedpoint.Zero, fmt.Errorf("%s threshold must be positive", i)
+	}
+
+	return barType, threshold, nil
+}
+
+func (t BarType) IsValid() bool {
+	for _, barType := range barTypes {
+		if t == barType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBar returns true if the interval is a bar interval, the bar intervals have no fixed duration.
+func (i Interval) IsBar() bool {
+	prefix, _, found := strings.Cut(string(i), ":")
+	return found && BarType(prefix).IsValid()
+}
+
+// BarBuilder builds the bars of a symbol from the market trades. The bars are emitted as the klines of the bar
+// interval, so the kline consumers like the indicators can use them like the time-based klines.
+// A trade is never split, so a bar closed by a large trade may exceed the threshold.
+//
+//go:generate callbackgen -type BarBuilder
+type BarBuilder struct {
+	Symbol   string
+	Interval Interval
+	Exchange ExchangeName
+
+	barType   BarType
+	threshold fixedpoint.Value
+
+	bar *KLine
+
+	// accumulated is the volume, the quote volume, the number of trades or the signed volume of the current bar
+	accumulated fixedpoint.Value
+
+	barUpdateCallbacks []func(k KLine)
+	barClosedCallbacks []func(k KLine)
+}
+
+func NewBarBuilder(symbol string, interval Interval) (*BarBuilder, error) {
+	barType, threshold, err := ParseBarInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BarBuilder{
+		Symbol:    symbol,
+		Interval:  interval,
+		barType:   barType,
+		threshold: threshold,
+	}, nil
+}
+
+// barDelta is the trading activity added to the current bar
+type barDelta struct {
+	startTime, endTime     time.Time
+	open, high, low, close fixedpoint.Value
+	volume, quoteVolume    fixedpoint.Value
+	takerBuyVolume         fixedpoint.Value
+	takerBuyQuoteVolume    fixedpoint.Value
+	numberOfTrades         uint64
+	lastTradeID            uint64
+}
+
+// AddTrade adds a market trade, the side of the market trade is the taker side
+func (b *BarBuilder) AddTrade(trade Trade) {
+	if trade.Symbol != b.Symbol {
+		return
+	}
+
+	quoteQuantity := trade.QuoteQuantity
+	if quoteQuantity.IsZero() {
+		quoteQuantity = trade.Price.Mul(trade.Quantity)
+	}
+
+	delta := barDelta{
+		startTime:      trade.Time.Time(),
+		endTime:        trade.Time.Time(),
+		open:           trade.Price,
+		high:           trade.Price,
+		low:            trade.Price,
+		close:          trade.Price,
+		volume:         trade.Quantity,
+		quoteVolume:    quoteQuantity,
+		numberOfTrades: 1,
+		lastTradeID:    trade.ID,
+	}
+
+	if trade.Side == SideTypeBuy {
+		delta.takerBuyVolume = trade.Quantity
+		delta.takerBuyQuoteVolume = quoteQuantity
+	}
+
+	b.add(delta)
+}
+
+// AddKLine adds a time-based kline as a batch of trades. It's used for building the bars in the back-test,
+// where the market trades are not available, so the bars are only as precise as the kline interval.
+func (b *BarBuilder) AddKLine(k KLine) {
+	if k.Symbol != b.Symbol {
+		return
+	}
+
+	numberOfTrades := k.NumberOfTrades
+	if numberOfTrades == 0 {
+		numberOfTrades = 1
+	}
+
+	b.add(barDelta{
+		startTime:           k.StartTime.Time(),
+		endTime:             k.EndTime.Time(),
+		open:                k.Open,
+		high:                k.High,
+		low:                 k.Low,
+		close:               k.Close,
+		volume:              k.Volume,
+		quoteVolume:         k.QuoteVolume,
+		takerBuyVolume:      k.TakerBuyBaseAssetVolume,
+		takerBuyQuoteVolume: k.TakerBuyQuoteAssetVolume,
+		numberOfTrades:      numberOfTrades,
+		lastTradeID:         k.LastTradeID,
+	})
+}
+
+func (b *BarBuilder) add(delta barDelta) {
+	if b.bar == nil {
+		b.bar = &KLine{
+			Exchange:  b.Exchange,
+			Symbol:    b.Symbol,
+			Interval:  b.Interval,
+			StartTime: Time(delta.startTime),
+			Open:      delta.open,
+			High:      delta.high,
+			Low:       delta.low,
+		}
+		b.accumulated = fixedpoint.Zero
+	}
+
+	bar := b.bar
+	bar.EndTime = Time(delta.endTime)
+	bar.High = fixedpoint.Max(bar.High, delta.high)
+	bar.Low = fixedpoint.Min(bar.Low, delta.low)
+	bar.Close = delta.close
+	bar.Volume = bar.Volume.Add(delta.volume)
+	bar.QuoteVolume = bar.QuoteVolume.Add(delta.quoteVolume)
+	bar.TakerBuyBaseAssetVolume = bar.TakerBuyBaseAssetVolume.Add(delta.takerBuyVolume)
+	bar.TakerBuyQuoteAssetVolume = bar.TakerBuyQuoteAssetVolume.Add(delta.takerBuyQuoteVolume)
+	bar.NumberOfTrades += delta.numberOfTrades
+	if delta.lastTradeID > 0 {
+		bar.LastTradeID = delta.lastTradeID
+	}
+
+	switch b.barType {
+	case BarTypeVolume:
+		b.accumulated = b.accumulated.Add(delta.volume)
+	case BarTypeDollar:
+		b.accumulated = b.accumulated.Add(delta.quoteVolume)
+	case BarTypeTick:
+		b.accumulated = b.accumulated.Add(fixedpoint.NewFromInt(int64(delta.numberOfTrades)))
+	case BarTypeImbalance:
+		// taker buy volume - taker sell volume
+		b.accumulated = b.accumulated.Add(delta.takerBuyVolume.Mul(Two).Sub(delta.volume))
+	}
+
+	if b.accumulated.Abs().Compare(b.threshold) < 0 {
+		b.EmitBarUpdate(*bar)
+		return
+	}
+
+	bar.Closed = true
+	b.bar = nil
+	b.EmitBarClosed(*bar)
+}
+
+// Current returns the current unclosed bar
+func (b *BarBuilder) Current() (KLine, bool) {
+	if b.bar == nil {
+		return KLine{}, false
+	}
+	return *b.bar, true
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestParseBarInterval(t *testing.T) {
+	barType, threshold, err := ParseBarInterval("dollar:1000000")
+	require.NoError(t, err)
+	assert.Equal(t, BarTypeDollar, barType)
+	assert.Equal(t, "1000000", threshold.String())
+	assert.Equal(t, Interval("dollar:1000000"), BarInterval(barType, threshold))
+
+	for _, interval := range []Interval{"1m", "candle:100", "volume:abc", "tick:0", "tick:-1"} {
+		_, _, err := ParseBarInterval(interval)
+		assert.Error(t, err, interval)
+	}
+
+	assert.True(t, Interval("volume:10").IsBar())
+	assert.False(t, Interval1m.IsBar())
+
+	// the bar intervals have no fixed duration
+	assert.Equal(t, time.Duration(0), Interval("tick:100").Duration())
+	assert.Equal(t, 0, Interval("tick:100").Minutes())
+}
+
+func newBarTestTrade(t time.Time, side SideType, price, quantity float64) Trade {
+	return Trade{
+		Symbol:   "BTCUSDT",
+		Side:     side,
+		Price:    fixedpoint.NewFromFloat(price),
+		Quantity: fixedpoint.NewFromFloat(quantity),
+		Time:     Time(t),
+	}
+}
+
+func TestBarBuilder(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []Trade{
+		newBarTestTrade(now, SideTypeBuy, 100, 1),
+		newBarTestTrade(now.Add(time.Second), SideTypeBuy, 102, 2),
+		newBarTestTrade(now.Add(2*time.Second), SideTypeSell, 99, 1),
+		newBarTestTrade(now.Add(3*time.Second), SideTypeSell, 101, 3),
+		newBarTestTrade(now.Add(4*time.Second), SideTypeBuy, 103, 1),
+	}
+
+	build := func(interval Interval) (closed []KLine, updates int) {
+		builder, err := NewBarBuilder("BTCUSDT", interval)
+		require.NoError(t, err)
+
+		builder.OnBarClosed(func(k KLine) { closed = append(closed, k) })
+		builder.OnBarUpdate(func(k KLine) { updates++ })
+		for _, trade := range trades {
+			builder.AddTrade(trade)
+		}
+
+		// the trades of the other symbols are ignored
+		builder.AddTrade(Trade{Symbol: "ETHUSDT", Quantity: fixedpoint.NewFromInt(100)})
+		return closed, updates
+	}
+
+	t.Run("volume", func(t *testing.T) {
+		bars, updates := build("volume:3")
+		require.Len(t, bars, 2)
+		assert.Equal(t, 3, updates)
+
+		bar := bars[0]
+		assert.True(t, bar.Closed)
+		assert.Equal(t, Interval("volume:3"), bar.Interval)
+		assert.Equal(t, now, bar.StartTime.Time())
+		assert.Equal(t, now.Add(time.Second), bar.EndTime.Time())
+		assert.Equal(t, "100", bar.Open.String())
+		assert.Equal(t, "102", bar.High.String())
+		assert.Equal(t, "100", bar.Low.String())
+		assert.Equal(t, "102", bar.Close.String())
+		assert.Equal(t, "3", bar.Volume.String())
+		assert.Equal(t, "304", bar.QuoteVolume.String())
+		assert.Equal(t, uint64(2), bar.NumberOfTrades)
+
+		// the large trade is not split
+		assert.Equal(t, "4", bars[1].Volume.String())
+		assert.Equal(t, "99", bars[1].Low.String())
+	})
+
+	t.Run("dollar", func(t *testing.T) {
+		bars, _ := build("dollar:300")
+		require.Len(t, bars, 2)
+		assert.Equal(t, "304", bars[0].QuoteVolume.String())
+		assert.Equal(t, "402", bars[1].QuoteVolume.String())
+	})
+
+	t.Run("tick", func(t *testing.T) {
+		bars, _ := build("tick:2")
+		require.Len(t, bars, 2)
+		assert.Equal(t, uint64(2), bars[1].NumberOfTrades)
+		assert.Equal(t, "101", bars[1].Close.String())
+	})
+
+	t.Run("imbalance", func(t *testing.T) {
+		// signed volumes: +1, +2 (closed at 3), -1, -3 (closed at -4), +1
+		bars, _ := build("imbalance:3")
+		require.Len(t, bars, 2)
+		assert.Equal(t, "3", bars[0].TakerBuyBaseAssetVolume.String())
+		assert.Equal(t, "0", bars[1].TakerBuyBaseAssetVolume.String())
+		assert.Equal(t, "4", bars[1].Volume.String())
+	})
+}
+
+func TestBarBuilder_AddKLine(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	builder, err := NewBarBuilder("BTCUSDT", "volume:10")
+	require.NoError(t, err)
+
+	var bars []KLine
+	builder.OnBarClosed(func(k KLine) { bars = append(bars, k) })
+
+	for i := 0; i < 3; i++ {
+		builder.AddKLine(KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  Interval1m,
+			StartTime: Time(now.Add(time.Duration(i) * time.Minute)),
+			EndTime:   Time(now.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromInt(100),
+			High:      fixedpoint.NewFromInt(110 + int64(i)),
+			Low:       fixedpoint.NewFromInt(90),
+			Close:     fixedpoint.NewFromInt(105),
+			Volume:    fixedpoint.NewFromInt(6),
+		})
+	}
+
+	require.Len(t, bars, 1)
+	assert.Equal(t, "12", bars[0].Volume.String())
+	assert.Equal(t, "111", bars[0].High.String())
+	assert.Equal(t, now.Add(2*time.Minute-time.Millisecond), bars[0].EndTime.Time())
+
+	current, ok := builder.Current()
+	require.True(t, ok)
+	assert.Equal(t, "6", current.Volume.String())
+}
@@ -23,6 +23,8 @@ const (
 	ExchangeBitget   ExchangeName = "bitget"
 	ExchangeBacktest ExchangeName = "backtest"
 	ExchangeBybit    ExchangeName = "bybit"
+	ExchangeGateIO   ExchangeName = "gateio"
+	ExchangeCoinbase ExchangeName = "coinbase"
 )
 
 var SupportedExchanges = []ExchangeName{
@@ -32,6 +34,8 @@ var SupportedExchanges = []ExchangeName{
 	ExchangeKucoin,
 	ExchangeBitget,
 	ExchangeBybit,
+	ExchangeGateIO,
+	ExchangeCoinbase,
 	// note: we are not using "backtest"
 }
 
@@ -55,7 +59,7 @@ func (n *ExchangeName) UnmarshalJSON(data []byte) error {
 
 func (n ExchangeName) IsValid() bool {
 	switch n {
-	case ExchangeBinance, ExchangeBitget, ExchangeBybit, ExchangeMax, ExchangeOKEx, ExchangeKucoin:
+	case ExchangeBinance, ExchangeBitget, ExchangeBybit, ExchangeCoinbase, ExchangeGateIO, ExchangeMax, ExchangeOKEx, ExchangeKucoin:
 		return true
 	}
 	return false
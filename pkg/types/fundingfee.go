@@ -0,0 +1,23 @@
+package types
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// FundingFee is the funding fee settled on a perpetual futures position.
+// A positive amount means the fee is received, a negative amount means the fee is paid.
+type FundingFee struct {
+	Exchange ExchangeName     `json:"exchange"`
+	Symbol   string           `json:"symbol"`
+	Asset    string           `json:"asset"`
+	Amount   fixedpoint.Value `json:"amount"`
+
+	// FundingRate is the funding rate applied in this settlement, it's optional since not every exchange provides it.
+	FundingRate fixedpoint.Value `json:"fundingRate,omitempty"`
+
+	// TxnID is the exchange transaction id (bill id, exec id...) of the settlement
+	TxnID string    `json:"txnID"`
+	Time  time.Time `json:"time"`
+}
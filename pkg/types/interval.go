@@ -29,6 +29,11 @@ func (i Interval) Seconds() int {
 // Milliseconds is specially handled, for better precision
 // for ms level interval, calling Seconds and Minutes directly might trigger panic error
 func (i Interval) Milliseconds() int {
+	// the bar intervals have no fixed duration
+	if i.IsBar() {
+		return 0
+	}
+
 	t := 0
 	index := 0
 	for i, rn := range string(i) {
@@ -112,6 +117,10 @@ var Interval2w = Interval("2w")
 var Interval1mo = Interval("1mo")
 
 func ParseInterval(input Interval) int {
+	if input.IsBar() {
+		return 0
+	}
+
 	t := 0
 	index := 0
 	for i, rn := range string(input) {
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// weekOffset is the offset from the unix epoch (Thursday) to the first Monday, the weekly klines start on Monday
+const weekOffset = 4 * 24 * time.Hour
+
+// KLineStartTime returns the start time of the kline window of the interval containing the given time.
+// The windows are aligned to the unix epoch, except that the weekly windows start on Monday.
+func KLineStartTime(t time.Time, interval Interval) time.Time {
+	d := interval.Duration()
+	if d <= 0 {
+		return t
+	}
+
+	offset := time.Duration(0)
+	if d%(7*24*time.Hour) == 0 {
+		offset = weekOffset
+	}
+
+	elapsed := time.Duration(t.UnixMilli())*time.Millisecond - offset
+	r := elapsed % d
+	elapsed -= r
+	if r < 0 {
+		elapsed -= d
+	}
+	return time.UnixMilli(int64((elapsed + offset) / time.Millisecond)).In(t.Location())
+}
+
+// FindBaseInterval finds the largest supported interval that the interval can be aggregated from.
+// The monthly klines can't be aggregated since the months have different lengths.
+func FindBaseInterval(interval Interval, supportedIntervals map[Interval]int) (base Interval, ok bool) {
+	if interval.IsBar() || interval == Interval1mo {
+		return "", false
+	}
+
+	target := interval.Duration()
+	if target <= 0 {
+		return "", false
+	}
+
+	week := 7 * 24 * time.Hour
+	for supported := range supportedIntervals {
+		d := supported.Duration()
+		if d <= 0 || d >= target || target%d != 0 {
+			continue
+		}
+
+		// the weekly windows are aligned to Monday, so a window longer than a day can't be the base of them
+		if target%week == 0 && d > 24*time.Hour {
+			continue
+		}
+
+		if d%week == 0 && target%week != 0 {
+			continue
+		}
+
+		if base == "" || d > base.Duration() {
+			base = supported
+		}
+	}
+
+	return base, base != ""
+}
+
+// KLineAggregator aggregates the closed klines of the base interval into the klines of a longer interval.
+// It's used for synthesizing the intervals that the exchange doesn't support.
+//
+//go:generate callbackgen -type KLineAggregator
+type KLineAggregator struct {
+	Symbol       string
+	Interval     Interval
+	BaseInterval Interval
+
+	kLine *KLine
+
+	// complete is false if the first base kline of the window is missing
+	complete bool
+
+	// lastBaseTime is the start time of the last added base kline
+	lastBaseTime time.Time
+
+	kLineClosedCallbacks []func(k KLine)
+}
+
+func NewKLineAggregator(symbol string, interval, baseInterval Interval) *KLineAggregator {
+	return &KLineAggregator{
+		Symbol:       symbol,
+		Interval:     interval,
+		BaseInterval: baseInterval,
+	}
+}
+
+// AddKLine adds a closed base kline, the aggregated kline is emitted when the last base kline of the window is added.
+// A window missing the last base kline is emitted when the kline of the next window arrives.
+func (a *KLineAggregator) AddKLine(k KLine) {
+	if k.Symbol != a.Symbol || k.Interval != a.BaseInterval {
+		return
+	}
+
+	// ignore the duplicated and the out-of-order base klines
+	if !a.lastBaseTime.IsZero() && !k.StartTime.Time().After(a.lastBaseTime) {
+		return
+	}
+	a.lastBaseTime = k.StartTime.Time()
+
+	// the previous window is not closed by its last base kline
+	startTime := KLineStartTime(k.StartTime.Time(), a.Interval)
+	if a.kLine != nil && a.kLine.StartTime.Time().Before(startTime) {
+		a.close()
+	}
+
+	if a.kLine == nil {
+		a.kLine = &KLine{
+			Exchange:  k.Exchange,
+			Symbol:    k.Symbol,
+			Interval:  a.Interval,
+			StartTime: Time(startTime),
+			EndTime:   Time(startTime.Add(a.Interval.Duration() - time.Millisecond)),
+			Open:      k.Open,
+			High:      k.High,
+			Low:       k.Low,
+		}
+		a.complete = k.StartTime.Time().Equal(startTime)
+	}
+
+	kLine := a.kLine
+	kLine.High = fixedpoint.Max(kLine.High, k.High)
+	kLine.Low = fixedpoint.Min(kLine.Low, k.Low)
+	kLine.Close = k.Close
+	kLine.Volume = kLine.Volume.Add(k.Volume)
+	kLine.QuoteVolume = kLine.QuoteVolume.Add(k.QuoteVolume)
+	kLine.TakerBuyBaseAssetVolume = kLine.TakerBuyBaseAssetVolume.Add(k.TakerBuyBaseAssetVolume)
+	kLine.TakerBuyQuoteAssetVolume = kLine.TakerBuyQuoteAssetVolume.Add(k.TakerBuyQuoteAssetVolume)
+	kLine.NumberOfTrades += k.NumberOfTrades
+	if k.LastTradeID > 0 {
+		kLine.LastTradeID = k.LastTradeID
+	}
+
+	// the base kline is the last one of the window
+	if !k.StartTime.Time().Add(a.BaseInterval.Duration()).Before(startTime.Add(a.Interval.Duration())) {
+		a.close()
+	}
+}
+
+func (a *KLineAggregator) close() {
+	kLine := *a.kLine
+	kLine.Closed = true
+	a.kLine = nil
+	a.EmitKLineClosed(kLine)
+}
+
+// AggregateKLines aggregates the base klines sorted by the start time into the klines of the interval.
+// The windows missing the first base kline and the unclosed last window are dropped.
+func AggregateKLines(kLines []KLine, interval, baseInterval Interval) (aggregated []KLine) {
+	if len(kLines) == 0 {
+		return nil
+	}
+
+	aggregator := NewKLineAggregator(kLines[0].Symbol, interval, baseInterval)
+	aggregator.OnKLineClosed(func(k KLine) {
+		if aggregator.complete {
+			aggregated = append(aggregated, k)
+		}
+	})
+
+	for _, k := range kLines {
+		aggregator.AddKLine(k)
+	}
+
+	return aggregated
+}
+
+// Current returns the unclosed aggregated kline
+func (a *KLineAggregator) Current() (KLine, bool) {
+	if a.kLine == nil {
+		return KLine{}, false
+	}
+	return *a.kLine, true
+}
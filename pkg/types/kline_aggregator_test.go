@@ -0,0 +1,119 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestKLineStartTime(t *testing.T) {
+	tm := time.Date(2023, 3, 15, 13, 47, 12, 0, time.UTC) // Wednesday
+
+	assert.Equal(t, time.Date(2023, 3, 15, 13, 45, 0, 0, time.UTC), KLineStartTime(tm, Interval3m).UTC())
+	assert.Equal(t, time.Date(2023, 3, 15, 12, 0, 0, 0, time.UTC), KLineStartTime(tm, Interval2h).UTC())
+	assert.Equal(t, time.Date(2023, 3, 13, 0, 0, 0, 0, time.UTC), KLineStartTime(tm, Interval1w).UTC())
+}
+
+func TestFindBaseInterval(t *testing.T) {
+	supported := map[Interval]int{
+		Interval1m:  60,
+		Interval5m:  300,
+		Interval15m: 900,
+		Interval1h:  3600,
+		Interval4h:  4 * 3600,
+		Interval1d:  86400,
+		Interval3d:  3 * 86400,
+	}
+
+	for interval, expected := range map[Interval]Interval{
+		Interval3m:  Interval1m,
+		Interval30m: Interval15m,
+		Interval2h:  Interval1h,
+		Interval12h: Interval4h,
+		Interval1w:  Interval1d,
+	} {
+		base, ok := FindBaseInterval(interval, supported)
+		assert.True(t, ok, interval)
+		assert.Equal(t, expected, base, interval)
+	}
+
+	_, ok := FindBaseInterval(Interval1mo, supported)
+	assert.False(t, ok)
+
+	_, ok = FindBaseInterval(Interval1s, supported)
+	assert.False(t, ok)
+}
+
+func newAggregatorTestKLine(startTime time.Time, open, high, low, close float64) KLine {
+	return KLine{
+		Symbol:    "BTCUSDT",
+		Interval:  Interval1m,
+		StartTime: Time(startTime),
+		EndTime:   Time(startTime.Add(time.Minute - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(open),
+		High:      fixedpoint.NewFromFloat(high),
+		Low:       fixedpoint.NewFromFloat(low),
+		Close:     fixedpoint.NewFromFloat(close),
+		Volume:    fixedpoint.One,
+	}
+}
+
+func TestKLineAggregator(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	aggregator := NewKLineAggregator("BTCUSDT", Interval3m, Interval1m)
+
+	var kLines []KLine
+	aggregator.OnKLineClosed(func(k KLine) { kLines = append(kLines, k) })
+
+	aggregator.AddKLine(newAggregatorTestKLine(startTime, 100, 105, 99, 101))
+	aggregator.AddKLine(newAggregatorTestKLine(startTime.Add(time.Minute), 101, 108, 100, 107))
+
+	// the duplicated base kline is ignored
+	aggregator.AddKLine(newAggregatorTestKLine(startTime.Add(time.Minute), 101, 108, 100, 107))
+	assert.Len(t, kLines, 0)
+
+	aggregator.AddKLine(newAggregatorTestKLine(startTime.Add(2*time.Minute), 107, 110, 95, 96))
+	require.Len(t, kLines, 1)
+
+	k := kLines[0]
+	assert.True(t, k.Closed)
+	assert.Equal(t, Interval3m, k.Interval)
+	assert.Equal(t, startTime, k.StartTime.Time())
+	assert.Equal(t, startTime.Add(3*time.Minute-time.Millisecond), k.EndTime.Time())
+	assert.Equal(t, "100", k.Open.String())
+	assert.Equal(t, "110", k.High.String())
+	assert.Equal(t, "95", k.Low.String())
+	assert.Equal(t, "96", k.Close.String())
+	assert.Equal(t, "3", k.Volume.String())
+
+	// the window missing the last base kline is closed by the next window
+	aggregator.AddKLine(newAggregatorTestKLine(startTime.Add(3*time.Minute), 96, 97, 95, 96))
+	aggregator.AddKLine(newAggregatorTestKLine(startTime.Add(6*time.Minute), 96, 97, 95, 96))
+	require.Len(t, kLines, 2)
+	assert.Equal(t, "1", kLines[1].Volume.String())
+
+	// the base klines of the other intervals are ignored
+	aggregator.AddKLine(KLine{Symbol: "BTCUSDT", Interval: Interval5m, StartTime: Time(startTime.Add(10 * time.Minute))})
+	current, ok := aggregator.Current()
+	require.True(t, ok)
+	assert.Equal(t, startTime.Add(6*time.Minute), current.StartTime.Time())
+}
+
+func TestAggregateKLines(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var base []KLine
+	for i := 1; i < 8; i++ {
+		base = append(base, newAggregatorTestKLine(startTime.Add(time.Duration(i)*time.Minute), 100, 101, 99, 100))
+	}
+
+	// [1m, 2m] misses the first base kline, [6m, 7m] is not closed
+	kLines := AggregateKLines(base, Interval3m, Interval1m)
+	require.Len(t, kLines, 1)
+	assert.Equal(t, startTime.Add(3*time.Minute), kLines[0].StartTime.Time())
+}
@@ -13,6 +13,11 @@ type FuturesExchange interface {
 	GetFuturesSettings() FuturesSettings
 }
 
+// FuturesPositionService queries the positions of all the futures contracts of an exchange
+type FuturesPositionService interface {
+	QueryFuturesPositions(ctx context.Context) (FuturesPositionMap, error)
+}
+
 type FuturesSettings struct {
 	IsFutures             bool
 	IsIsolatedFutures     bool
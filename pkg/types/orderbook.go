@@ -127,11 +127,26 @@ type BookSignalType int
 const (
 	BookSignalSnapshot BookSignalType = 1
 	BookSignalUpdate   BookSignalType = 2
+
+	// BookSignalUnhealthy is sent when the book is detected as crossed, stale or mismatching the checksum
+	BookSignalUnhealthy BookSignalType = 3
+
+	// BookSignalHealthy is sent when the unhealthy book is recovered
+	BookSignalHealthy BookSignalType = 4
 )
 
 type BookSignal struct {
 	Type BookSignalType
 	Time time.Time
+
+	// Reason is the reason of the unhealthy signal
+	Reason string
+}
+
+// BookChecksumCalculator is implemented by the market data streams that receive the order book checksum with
+// the depth updates, the checksum of the local book is compared with SliceOrderBook.Checksum of the update.
+type BookChecksumCalculator interface {
+	CalculateBookChecksum(symbol string, bids, asks PriceVolumeSlice) int64
 }
 
 // StreamOrderBook receives streaming data from websocket connection and
@@ -177,8 +192,13 @@ func (sb *StreamOrderBook) BindStream(stream Stream) {
 }
 
 func (sb *StreamOrderBook) emitChange(signalType BookSignalType, bookTime time.Time) {
+	sb.SendSignal(&BookSignal{Type: signalType, Time: defaultTime(bookTime, time.Now)})
+}
+
+// SendSignal sends the signal to the signal channel without blocking, the signal is dropped if the channel is full
+func (sb *StreamOrderBook) SendSignal(signal *BookSignal) {
 	select {
-	case sb.C <- &BookSignal{Type: signalType, Time: defaultTime(bookTime, time.Now)}:
+	case sb.C <- signal:
 	default:
 	}
 }
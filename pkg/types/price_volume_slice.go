@@ -250,6 +250,7 @@ func (slice PriceVolumeSlice) AverageDepthPrice(requiredQuantity fixedpoint.Valu
 		pv := slice[i]
 		if pv.Volume.Compare(rq) >= 0 {
 			totalAmount = totalAmount.Add(rq.Mul(pv.Price))
+			rq = fixedpoint.Zero
 			break
 		}
 
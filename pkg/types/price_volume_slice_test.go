@@ -28,3 +28,21 @@ func TestPriceVolumeSlice_Remove(t *testing.T) {
 		assert.Equal(t, 2, len(slice), "with descending %v", descending)
 	}
 }
+
+func TestPriceVolumeSlice_AverageDepthPrice(t *testing.T) {
+	slice := PriceVolumeSlice{
+		{Price: fixedpoint.NewFromInt(100), Volume: fixedpoint.One},
+		{Price: fixedpoint.NewFromInt(98), Volume: fixedpoint.NewFromInt(2)},
+	}
+
+	assert.Equal(t, "100", slice.AverageDepthPrice(fixedpoint.One).String())
+
+	// the rest quantity is filled by the second level, (100 * 1 + 98 * 1) / 2
+	assert.Equal(t, "99", slice.AverageDepthPrice(fixedpoint.NewFromInt(2)).String())
+
+	// (100 * 1 + 98 * 2) / 3
+	assert.Equal(t, "98.666666", slice.AverageDepthPrice(fixedpoint.NewFromInt(3)).String()[:9])
+
+	// the required quantity exceeds the depth
+	assert.Equal(t, "98.666666", slice.AverageDepthPrice(fixedpoint.NewFromInt(4)).String()[:9])
+}
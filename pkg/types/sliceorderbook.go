@@ -26,6 +26,10 @@ type SliceOrderBook struct {
 	// this is for binance right now.
 	LastUpdateId int64
 
+	// Checksum is the checksum of the top levels after applying this update,
+	// this field is optional, see BookChecksumCalculator
+	Checksum int64
+
 	lastUpdateTime time.Time
 
 	loadCallbacks   []func(book *SliceOrderBook)
@@ -194,6 +194,16 @@ func (s *StandardStream) EmitFuturesPositionSnapshot(futuresPositions FuturesPos
 	}
 }
 
+func (s *StandardStream) OnFundingFee(cb func(fee FundingFee)) {
+	s.fundingFeeCallbacks = append(s.fundingFeeCallbacks, cb)
+}
+
+func (s *StandardStream) EmitFundingFee(fee FundingFee) {
+	for _, cb := range s.fundingFeeCallbacks {
+		cb(fee)
+	}
+}
+
 type StandardStreamEventHub interface {
 	OnStart(cb func())
 
@@ -232,4 +242,6 @@ type StandardStreamEventHub interface {
 	OnFuturesPositionUpdate(cb func(futuresPositions FuturesPositionMap))
 
 	OnFuturesPositionSnapshot(cb func(futuresPositions FuturesPositionMap))
+
+	OnFundingFee(cb func(fee FundingFee))
 }
@@ -74,6 +74,9 @@ type StandardStream struct {
 	dispatcher   Dispatcher
 	pingInterval time.Duration
 
+	// reconnectCoolDown is the waiting period before re-connecting
+	reconnectCoolDown time.Duration
+
 	endpointCreator EndpointCreator
 
 	// Conn is the websocket connection
@@ -150,6 +153,8 @@ type StandardStream struct {
 
 	FuturesPositionSnapshotCallbacks []func(futuresPositions FuturesPositionMap)
 
+	fundingFeeCallbacks []func(fee FundingFee)
+
 	heartBeat HeartBeat
 
 	beforeConnect BeforeConnect
@@ -175,14 +180,16 @@ type StandardStreamEmitter interface {
 	EmitForceOrder(LiquidationInfo)
 	EmitFuturesPositionUpdate(FuturesPositionMap)
 	EmitFuturesPositionSnapshot(FuturesPositionMap)
+	EmitFundingFee(FundingFee)
 }
 
 func NewStandardStream() StandardStream {
 	return StandardStream{
-		ReconnectC:   make(chan struct{}, 1),
-		CloseC:       make(chan struct{}),
-		sg:           NewSyncGroup(),
-		pingInterval: pingInterval,
+		ReconnectC:        make(chan struct{}, 1),
+		CloseC:            make(chan struct{}),
+		sg:                NewSyncGroup(),
+		pingInterval:      pingInterval,
+		reconnectCoolDown: reconnectCoolDownPeriod,
 	}
 }
 
@@ -321,6 +328,11 @@ func (s *StandardStream) SetPingInterval(interval time.Duration) {
 	s.pingInterval = interval
 }
 
+// SetReconnectCoolDownPeriod sets the waiting period before re-connecting, it's usually shortened in the tests.
+func (s *StandardStream) SetReconnectCoolDownPeriod(period time.Duration) {
+	s.reconnectCoolDown = period
+}
+
 func (s *StandardStream) ping(
 	ctx context.Context, conn *websocket.Conn, cancel context.CancelFunc,
 ) {
@@ -433,8 +445,8 @@ func (s *StandardStream) reconnector(ctx context.Context) {
 			return
 
 		case <-s.ReconnectC:
-			log.Warnf("received reconnect signal, cooling for %s...", reconnectCoolDownPeriod)
-			time.Sleep(reconnectCoolDownPeriod)
+			log.Warnf("received reconnect signal, cooling for %s...", s.reconnectCoolDown)
+			time.Sleep(s.reconnectCoolDown)
 
 			log.Warnf("re-connecting...")
 			if err := s.DialAndConnect(ctx); err != nil {
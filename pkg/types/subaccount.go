@@ -0,0 +1,35 @@
+package types
+
+import (
+	"context"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// SubAccount is a sub-account of the master account of the API key.
+// Name is the identifier used by the exchange api, e.g., the email of binance, the subAcct of okex and the member id of bybit.
+type SubAccount struct {
+	Name         string `json:"name"`
+	Label        string `json:"label,omitempty"`
+	Frozen       bool   `json:"frozen"`
+	CreationTime Time   `json:"creationTime,omitempty"`
+}
+
+// SubAccountService manages the sub-accounts of the master account
+type SubAccountService interface {
+	QuerySubAccounts(ctx context.Context) ([]SubAccount, error)
+
+	// QuerySubAccountBalances queries the balances of the spot (trading) account of the sub-account
+	QuerySubAccountBalances(ctx context.Context, subAccount string) (BalanceMap, error)
+
+	// TransferSubAccountAsset transfers the asset between the spot (trading) accounts of the master and the sub-account.
+	//
+	// types.TransferIn => master to sub-account
+	// types.TransferOut => sub-account to master
+	TransferSubAccountAsset(ctx context.Context, subAccount, asset string, amount fixedpoint.Value, io TransferDirection) error
+}
+
+// InternalTransferService transfers the asset between the accounts of the same user, e.g., spot to futures
+type InternalTransferService interface {
+	InternalTransfer(ctx context.Context, asset string, amount fixedpoint.Value, from, to AccountType) error
+}
@@ -0,0 +1,271 @@
+package types
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// QuoteGroupUSD is the quote equivalence group of the USD stable coins
+const QuoteGroupUSD = "USD"
+
+// DefaultSymbolRegistry is the registry shared by the sessions and the strategies,
+// the markets of the sessions are registered when they are loaded.
+var DefaultSymbolRegistry = NewSymbolRegistry()
+
+type symbolAssets struct {
+	Base, Quote string
+}
+
+// SymbolRegistry normalizes the symbols and the assets across the exchanges.
+//
+// The canonical symbol is the global symbol used by bbgo, e.g., BTCUSDT, and the canonical asset is the upper-case
+// currency, e.g., BTC. Each exchange can register its local aliases of the symbols and the assets, the contract
+// multipliers of its derivatives and the quote currencies that are treated as equivalent, e.g., USDT and USDC.
+type SymbolRegistry struct {
+	mu sync.RWMutex
+
+	// localSymbols maps the canonical symbols to the local symbols, and globalSymbols maps them back
+	localSymbols  map[ExchangeName]map[string]string
+	globalSymbols map[ExchangeName]map[string]string
+
+	// localAssets maps the canonical assets to the local assets, and globalAssets maps them back
+	localAssets  map[ExchangeName]map[string]string
+	globalAssets map[ExchangeName]map[string]string
+
+	// multipliers stores the contract multipliers, the quantity of one contract in the base asset
+	multipliers map[ExchangeName]map[string]fixedpoint.Value
+
+	// symbolAssets stores the base and quote assets of the canonical symbols
+	symbolAssets map[string]symbolAssets
+
+	// quoteGroups maps the quote assets to their equivalence groups,
+	// and groupQuotes stores the quote assets of the groups in the order of the preference
+	quoteGroups map[string]string
+	groupQuotes map[string][]string
+}
+
+func NewSymbolRegistry() *SymbolRegistry {
+	r := &SymbolRegistry{
+		localSymbols:  make(map[ExchangeName]map[string]string),
+		globalSymbols: make(map[ExchangeName]map[string]string),
+		localAssets:   make(map[ExchangeName]map[string]string),
+		globalAssets:  make(map[ExchangeName]map[string]string),
+		multipliers:   make(map[ExchangeName]map[string]fixedpoint.Value),
+		symbolAssets:  make(map[string]symbolAssets),
+		quoteGroups:   make(map[string]string),
+		groupQuotes:   make(map[string][]string),
+	}
+
+	r.RegisterQuoteGroup(QuoteGroupUSD, "USDT", "USDC", "BUSD", "FDUSD", "TUSD", "DAI", "USD")
+	return r
+}
+
+// RegisterSymbol registers the local symbol of the canonical symbol on the exchange
+func (r *SymbolRegistry) RegisterSymbol(exchange ExchangeName, symbol, localSymbol string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	setAlias(r.localSymbols, exchange, symbol, localSymbol)
+	setAlias(r.globalSymbols, exchange, localSymbol, symbol)
+}
+
+// RegisterSymbolAssets registers the base and the quote assets of the canonical symbol
+func (r *SymbolRegistry) RegisterSymbolAssets(symbol, base, quote string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.symbolAssets[symbol] = symbolAssets{Base: base, Quote: quote}
+}
+
+// RegisterMarkets registers the local symbols and the assets of the markets of the exchange
+func (r *SymbolRegistry) RegisterMarkets(exchange ExchangeName, markets MarketMap) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, market := range markets {
+		if market.LocalSymbol != "" && market.LocalSymbol != market.Symbol {
+			setAlias(r.localSymbols, exchange, market.Symbol, market.LocalSymbol)
+			setAlias(r.globalSymbols, exchange, market.LocalSymbol, market.Symbol)
+		}
+
+		if market.BaseCurrency != "" && market.QuoteCurrency != "" {
+			r.symbolAssets[market.Symbol] = symbolAssets{Base: market.BaseCurrency, Quote: market.QuoteCurrency}
+		}
+	}
+}
+
+// LocalSymbol returns the local symbol of the canonical symbol on the exchange,
+// the canonical symbol is returned if there is no registered alias.
+func (r *SymbolRegistry) LocalSymbol(exchange ExchangeName, symbol string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.localSymbols[exchange][symbol]; ok {
+		return s, true
+	}
+	return symbol, false
+}
+
+// CanonicalSymbol returns the canonical symbol of the local symbol on the exchange,
+// the upper-case local symbol is returned if there is no registered alias.
+func (r *SymbolRegistry) CanonicalSymbol(exchange ExchangeName, localSymbol string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.globalSymbols[exchange][localSymbol]; ok {
+		return s, true
+	}
+	return strings.ToUpper(localSymbol), false
+}
+
+// RegisterAsset registers the local asset of the canonical asset on the exchange, e.g., XBT for BTC
+func (r *SymbolRegistry) RegisterAsset(exchange ExchangeName, asset, localAsset string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	setAlias(r.localAssets, exchange, asset, localAsset)
+	setAlias(r.globalAssets, exchange, localAsset, asset)
+}
+
+// LocalAsset returns the local asset of the canonical asset on the exchange,
+// the canonical asset is returned if there is no registered alias.
+func (r *SymbolRegistry) LocalAsset(exchange ExchangeName, asset string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.localAssets[exchange][asset]; ok {
+		return s, true
+	}
+	return asset, false
+}
+
+// CanonicalAsset returns the canonical asset of the local asset on the exchange,
+// the upper-case local asset is returned if there is no registered alias.
+func (r *SymbolRegistry) CanonicalAsset(exchange ExchangeName, localAsset string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.globalAssets[exchange][localAsset]; ok {
+		return s, true
+	}
+	return strings.ToUpper(localAsset), false
+}
+
+// SetContractMultiplier sets the quantity of one contract of the symbol in the base asset
+func (r *SymbolRegistry) SetContractMultiplier(exchange ExchangeName, symbol string, multiplier fixedpoint.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.multipliers[exchange]
+	if !ok {
+		m = make(map[string]fixedpoint.Value)
+		r.multipliers[exchange] = m
+	}
+	m[symbol] = multiplier
+}
+
+// ContractMultiplier returns the contract multiplier of the symbol, it's one if the multiplier is not set
+func (r *SymbolRegistry) ContractMultiplier(exchange ExchangeName, symbol string) fixedpoint.Value {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if m, ok := r.multipliers[exchange][symbol]; ok && m.Sign() > 0 {
+		return m
+	}
+	return fixedpoint.One
+}
+
+// RegisterQuoteGroup registers the quote assets that are treated as equivalent, e.g., the USD stable coins.
+// The order of the assets is the order of the preference.
+func (r *SymbolRegistry) RegisterQuoteGroup(group string, assets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, asset := range assets {
+		if _, ok := r.quoteGroups[asset]; !ok {
+			r.groupQuotes[group] = append(r.groupQuotes[group], asset)
+		}
+		r.quoteGroups[asset] = group
+	}
+}
+
+// QuoteGroup returns the equivalence group of the quote asset
+func (r *SymbolRegistry) QuoteGroup(asset string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	group, ok := r.quoteGroups[asset]
+	return group, ok
+}
+
+// IsQuoteEquivalent returns true if the two quote assets are the same or in the same equivalence group
+func (r *SymbolRegistry) IsQuoteEquivalent(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groupA, okA := r.quoteGroups[a]
+	groupB, okB := r.quoteGroups[b]
+	return okA && okB && groupA == groupB
+}
+
+// EquivalentQuotes returns the quote assets of the equivalence group of the asset in the order of the preference,
+// the asset itself is returned if it's not in any group.
+func (r *SymbolRegistry) EquivalentQuotes(asset string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	group, ok := r.quoteGroups[asset]
+	if !ok {
+		return []string{asset}
+	}
+
+	return append([]string(nil), r.groupQuotes[group]...)
+}
+
+// SymbolAssets returns the base and the quote assets of the canonical symbol
+func (r *SymbolRegistry) SymbolAssets(symbol string) (base, quote string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assets, ok := r.symbolAssets[symbol]
+	return assets.Base, assets.Quote, ok
+}
+
+// EquivalentSymbols returns the symbols of the markets that trade the same base asset against the equivalent
+// quote assets of the symbol, in the order of the quote preference. The symbol itself is included if it's in the markets.
+func (r *SymbolRegistry) EquivalentSymbols(symbol string, markets MarketMap) []string {
+	base, quote, ok := r.SymbolAssets(symbol)
+	if !ok {
+		if m, found := markets[symbol]; found {
+			base, quote = m.BaseCurrency, m.QuoteCurrency
+		} else {
+			return nil
+		}
+	}
+
+	var symbols []string
+	for _, q := range r.EquivalentQuotes(quote) {
+		for _, m := range markets {
+			if m.BaseCurrency == base && m.QuoteCurrency == q {
+				symbols = append(symbols, m.Symbol)
+			}
+		}
+	}
+
+	return symbols
+}
+
+func setAlias(aliases map[ExchangeName]map[string]string, exchange ExchangeName, key, value string) {
+	m, ok := aliases[exchange]
+	if !ok {
+		m = make(map[string]string)
+		aliases[exchange] = m
+	}
+	m[key] = value
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestSymbolRegistry_Symbols(t *testing.T) {
+	r := NewSymbolRegistry()
+	r.RegisterMarkets(ExchangeOKEx, MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", LocalSymbol: "BTC-USDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"ETHBTC":  {Symbol: "ETHBTC", LocalSymbol: "ETH-BTC", BaseCurrency: "ETH", QuoteCurrency: "BTC"},
+	})
+
+	local, ok := r.LocalSymbol(ExchangeOKEx, "BTCUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, "BTC-USDT", local)
+
+	symbol, ok := r.CanonicalSymbol(ExchangeOKEx, "ETH-BTC")
+	assert.True(t, ok)
+	assert.Equal(t, "ETHBTC", symbol)
+
+	// the aliases are per exchange, the unregistered symbols fall back to the upper-case symbol
+	local, ok = r.LocalSymbol(ExchangeBinance, "BTCUSDT")
+	assert.False(t, ok)
+	assert.Equal(t, "BTCUSDT", local)
+
+	symbol, ok = r.CanonicalSymbol(ExchangeMax, "btctwd")
+	assert.False(t, ok)
+	assert.Equal(t, "BTCTWD", symbol)
+
+	base, quote, ok := r.SymbolAssets("ETHBTC")
+	assert.True(t, ok)
+	assert.Equal(t, "ETH", base)
+	assert.Equal(t, "BTC", quote)
+}
+
+func TestSymbolRegistry_Assets(t *testing.T) {
+	r := NewSymbolRegistry()
+	r.RegisterAsset(ExchangeKucoin, "BTC", "XBT")
+
+	local, ok := r.LocalAsset(ExchangeKucoin, "BTC")
+	assert.True(t, ok)
+	assert.Equal(t, "XBT", local)
+
+	asset, ok := r.CanonicalAsset(ExchangeKucoin, "XBT")
+	assert.True(t, ok)
+	assert.Equal(t, "BTC", asset)
+
+	asset, _ = r.CanonicalAsset(ExchangeMax, "usdt")
+	assert.Equal(t, "USDT", asset)
+}
+
+func TestSymbolRegistry_ContractMultiplier(t *testing.T) {
+	r := NewSymbolRegistry()
+	r.SetContractMultiplier(ExchangeOKEx, "BTCUSDT", fixedpoint.NewFromFloat(0.01))
+
+	assert.Equal(t, "0.01", r.ContractMultiplier(ExchangeOKEx, "BTCUSDT").String())
+	assert.Equal(t, "1", r.ContractMultiplier(ExchangeOKEx, "ETHUSDT").String())
+	assert.Equal(t, "1", r.ContractMultiplier(ExchangeBinance, "BTCUSDT").String())
+}
+
+func TestSymbolRegistry_QuoteEquivalence(t *testing.T) {
+	r := NewSymbolRegistry()
+
+	assert.True(t, r.IsQuoteEquivalent("USDT", "USDC"))
+	assert.True(t, r.IsQuoteEquivalent("BUSD", "USDT"))
+	assert.True(t, r.IsQuoteEquivalent("BTC", "BTC"))
+	assert.False(t, r.IsQuoteEquivalent("USDT", "BTC"))
+	assert.False(t, r.IsQuoteEquivalent("TWD", "USDT"))
+
+	quotes := r.EquivalentQuotes("USDC")
+	assert.Equal(t, []string{"USDT", "USDC", "BUSD", "FDUSD", "TUSD", "DAI", "USD"}, quotes)
+	assert.Equal(t, []string{"TWD"}, r.EquivalentQuotes("TWD"))
+
+	r.RegisterQuoteGroup("EUR", "EUR", "EURC")
+	assert.True(t, r.IsQuoteEquivalent("EURC", "EUR"))
+
+	markets := MarketMap{
+		"BTCUSDT": {Symbol: "BTCUSDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+		"BTCUSDC": {Symbol: "BTCUSDC", BaseCurrency: "BTC", QuoteCurrency: "USDC"},
+		"BTCTWD":  {Symbol: "BTCTWD", BaseCurrency: "BTC", QuoteCurrency: "TWD"},
+		"ETHUSDT": {Symbol: "ETHUSDT", BaseCurrency: "ETH", QuoteCurrency: "USDT"},
+	}
+	assert.Equal(t, []string{"BTCUSDT", "BTCUSDC"}, r.EquivalentSymbols("BTCUSDC", markets))
+	assert.Nil(t, r.EquivalentSymbols("XRPUSDT", markets))
+}